@@ -7,8 +7,17 @@ import (
 	"os"
 
 	"sprayer/src/api"
+	"sprayer/src/api/application"
+	"sprayer/src/api/apply"
+	"sprayer/src/api/health"
 	"sprayer/src/api/job"
+	"sprayer/src/api/llm"
 	"sprayer/src/api/profile"
+	"sprayer/src/api/redact"
+	"sprayer/src/api/rules"
+	"sprayer/src/api/scraperun"
+	"sprayer/src/api/sourcestats"
+	"sprayer/src/api/tracking"
 	"github.com/joho/godotenv"
 )
 
@@ -16,6 +25,8 @@ func main() {
 	godotenv.Load()
 
 	port := flag.String("port", "8080", "Port to listen on")
+	readyExternal := flag.Bool("ready-external", false, "Also check LLM and SMTP reachability in /ready")
+	track := flag.Bool("tracking", false, "Serve email open/click tracking endpoints (needs "+tracking.EnvSecret+")")
 	flag.Parse()
 
 	if envPort := os.Getenv("PORT"); envPort != "" {
@@ -31,17 +42,66 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize profile store: %v", err)
 	}
+	profiles, _ := profileStore.All()
+	redact.Install(os.Stderr, redact.FromProfiles(profiles))
 
 	h := api.NewHandler(jobStore, profileStore)
+	appStore, err := application.NewStore(jobStore.DB)
+	if err != nil {
+		log.Fatalf("Failed to initialize application store: %v", err)
+	}
+	h.EnableApplications(appStore)
+	runStore, err := scraperun.NewStore(jobStore.DB)
+	if err != nil {
+		log.Fatalf("Failed to initialize scrape run store: %v", err)
+	}
+	h.EnableScrapeHistory(runStore)
+	statsStore, err := sourcestats.NewStore(jobStore.DB)
+	if err != nil {
+		log.Fatalf("Failed to initialize source stats store: %v", err)
+	}
+	h.EnableSourceStats(statsStore)
+	ruleStore, err := rules.NewStore(jobStore.DB)
+	if err != nil {
+		log.Fatalf("Failed to initialize rule store: %v", err)
+	}
+	h.EnableIngestRules(ruleStore)
+	if *readyExternal {
+		h.CheckExternal(
+			health.LLMReachable(llm.NewClient().BaseURL(), health.DefaultTimeout),
+			health.SMTPReachable(apply.SMTPAddr(), health.DefaultTimeout),
+		)
+	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", h.HealthCheck)
+	mux.HandleFunc("/ready", h.Ready)
 	mux.HandleFunc("/jobs", h.ListJobs)
+	mux.HandleFunc("/jobs/", h.Job)
 	mux.HandleFunc("/jobs/scrape", h.ScrapeJobs)
+	mux.HandleFunc("/jobs/scrape/status", h.GetScrapeStatus)
+	mux.HandleFunc("/jobs/scrape/stream", h.StreamScrape)
+	mux.HandleFunc("/scrapes", h.ListScrapes)
 	mux.HandleFunc("/profiles", h.ListProfiles)
 
+	if *track {
+		signer := tracking.SignerFromEnv()
+		if signer == nil {
+			log.Fatalf("-tracking needs %s set", tracking.EnvSecret)
+		}
+		h.EnableTracking(appStore, signer)
+		mux.HandleFunc("/t/open/", h.TrackOpen)
+		mux.HandleFunc("/t/click/", h.TrackClick)
+	}
+
+	// Every route is also served under /api/v1, the versioned path
+	// clients should use.
+	root := http.NewServeMux()
+	root.Handle("/api/v1/", http.StripPrefix("/api/v1", mux))
+	root.Handle("/", mux)
+
 	log.Printf("Starting API server on :%s", *port)
-	if err := http.ListenAndServe(":"+*port, mux); err != nil {
+	if err := http.ListenAndServe(":"+*port, root); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -1,15 +1,21 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/joho/godotenv"
 
+	"sprayer/src/api/offline"
+	"sprayer/src/api/power"
+	"sprayer/src/api/settings"
 	"sprayer/src/ui"
 	"sprayer/src/ui/tui"
+	"sprayer/src/ui/tui/theme"
 	"sprayer/src/version"
 )
 
@@ -19,6 +25,10 @@ func main() {
 	versionFlag := flag.Bool("version", false, "Print version information")
 	shortVersionFlag := flag.Bool("v", false, "Print short version")
 	tuiFlag := flag.Bool("tui", false, "Run in TUI mode")
+	plainFlag := flag.Bool("plain", false, "Leave the terminal title alone")
+	statusFile := flag.String("status-file", "", "Keep a one-line TUI summary in this file, e.g. for tmux status-right")
+	profileFlag := flag.String("profile", "", "Profile the TUI writes and sends applications as (default the default_profile setting)")
+	dryRunFlag := flag.Bool("dry-run", false, "Bulk apply in the TUI only saves drafts, sending nothing")
 	flag.Parse()
 
 	if *versionFlag {
@@ -32,8 +42,34 @@ func main() {
 	}
 
 	if *tuiFlag {
-		p := tea.NewProgram(tui.NewModel())
-		if _, err := p.Run(); err != nil {
+		// Cancelled once the TUI quits, before the deferred Close: work
+		// still running, a draft being written or a scrape, would
+		// otherwise hold the database open.
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var opts []tui.Option
+		if cli, err := ui.NewCLI(); err == nil {
+			defer cli.Close()
+			opts = append(opts, cli.TUIOptions(ctx, *profileFlag, *dryRunFlag)...)
+		} else {
+			log.Printf("job store unavailable: %v", err)
+		}
+		// After NewCLI, which puts the stored settings in the environment.
+		theme.Apply(os.Getenv(settings.EnvTheme))
+		// The connectivity probe and power detection can take a second;
+		// the TUI runs them after its first frame.
+		opts = append(opts, tui.WithOfflineProbe(offline.Offline))
+		opts = append(opts, tui.WithPowerDetect(func() power.Decision {
+			return power.Decide(power.System().Detect(), power.ConfigFromEnv())
+		}))
+		opts = append(opts, tui.WithTerminalTitle(!*plainFlag && isTerminal(os.Stdout)))
+		if *statusFile != "" {
+			opts = append(opts, tui.WithStatusFile(*statusFile))
+		}
+		p := tea.NewProgram(tui.NewModel(opts...))
+		_, err := p.Run()
+		cancel()
+		if err != nil {
 			log.Fatal(err)
 		}
 		return
@@ -43,6 +79,11 @@ func main() {
 	runCLI()
 }
 
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
 func runCLI() {
 	cli, err := ui.NewCLI()
 	if err != nil {
@@ -0,0 +1,56 @@
+package application
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// Sent is exactly what went out with an emailed application: the
+// addresses, the final subject and body as sent, and the files attached
+// or used to write it.
+type Sent struct {
+	From            string    `json:"from"` // the sending address used
+	To              string    `json:"to"`
+	Cc              []string  `json:"cc,omitempty"`
+	Subject         string    `json:"subject"`
+	Body            string    `json:"body"`
+	CVPath          string    `json:"cv_path,omitempty"`
+	CoverLetterPath string    `json:"cover_letter_path,omitempty"`
+	SentAt          time.Time `json:"sent_at"`
+}
+
+// sentColumns were added after the applications table first shipped.
+var sentColumns = []job.Column{
+	{Name: "sent_from", Decl: "TEXT DEFAULT ''"},
+	{Name: "sent_to", Decl: "TEXT DEFAULT ''"},
+	{Name: "sent_cc", Decl: "TEXT DEFAULT ''"},
+	{Name: "sent_subject", Decl: "TEXT DEFAULT ''"},
+	{Name: "sent_body", Decl: "TEXT DEFAULT ''"},
+	{Name: "sent_cv_path", Decl: "TEXT DEFAULT ''"},
+	{Name: "sent_cover_letter", Decl: "TEXT DEFAULT ''"},
+	{Name: "sent_at", Decl: "DATETIME DEFAULT NULL"},
+}
+
+// sentArgs flattens s for INSERT; nil stores no artifacts.
+func sentArgs(s *Sent) []any {
+	if s == nil {
+		return []any{"", "", "", "", "", "", "", nil}
+	}
+	return []any{s.From, s.To, strings.Join(s.Cc, ","), s.Subject, s.Body, s.CVPath, s.CoverLetterPath, s.SentAt.UTC()}
+}
+
+// ForJob returns the latest application to a job, or sql.ErrNoRows when
+// there is none.
+func (s *Store) ForJob(jobID string) (*Application, error) {
+	apps, err := s.query(`SELECT `+columns+` FROM applications WHERE job_id = ? ORDER BY applied_at DESC, id DESC LIMIT 1`, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if len(apps) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &apps[0], nil
+}
@@ -0,0 +1,128 @@
+package application
+
+import (
+	"database/sql"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"sprayer/src/api/shingle"
+)
+
+// Letter is the body of a sent application email, kept with its shingle
+// set so new letters can be compared against it cheaply.
+type Letter struct {
+	ApplicationID int64
+	Company       string
+	Body          string
+	SentAt        time.Time
+	Shingles      shingle.Set
+}
+
+// EchoWindow is how many of the most recent letters a new one is
+// compared against.
+const EchoWindow = 200
+
+// DefaultEchoThreshold is the shingle similarity above which a new letter
+// echoes an old one. Rewrites of one letter score well above it; letters
+// by the same person about the same skills, written separately, below.
+const DefaultEchoThreshold = 0.2
+
+// EnvEchoThreshold overrides DefaultEchoThreshold, as a number in (0, 1].
+const EnvEchoThreshold = "SPRAYER_ECHO_THRESHOLD"
+
+// EchoThreshold returns the configured echo threshold.
+func EchoThreshold() float64 {
+	if v, err := strconv.ParseFloat(os.Getenv(EnvEchoThreshold), 64); err == nil && v > 0 && v <= 1 {
+		return v
+	}
+	return DefaultEchoThreshold
+}
+
+func migrateLetters(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS application_letters (
+			application_id INTEGER PRIMARY KEY,
+			company        TEXT,
+			body           TEXT,
+			shingles       BLOB,
+			sent_at        DATETIME
+		);
+		CREATE INDEX IF NOT EXISTS idx_application_letters_sent_at ON application_letters(sent_at);`)
+	return err
+}
+
+// AddLetter stores the letter sent for an application, computing its
+// shingles now so later comparisons need not.
+func (s *Store) AddLetter(l *Letter) error {
+	if l.SentAt.IsZero() {
+		l.SentAt = time.Now()
+	}
+	l.Shingles = shingle.Of(l.Body)
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO application_letters (application_id, company, body, shingles, sent_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		l.ApplicationID, l.Company, l.Body, l.Shingles.Encode(), l.SentAt.UTC())
+	return err
+}
+
+// RecentLetters returns up to n letters, newest first.
+func (s *Store) RecentLetters(n int) ([]Letter, error) {
+	rows, err := s.db.Query(`
+		SELECT application_id, company, body, shingles, sent_at
+		FROM application_letters ORDER BY sent_at DESC, application_id DESC LIMIT ?`, n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Letter
+	for rows.Next() {
+		var l Letter
+		var packed []byte
+		if err := rows.Scan(&l.ApplicationID, &l.Company, &l.Body, &packed, &l.SentAt); err != nil {
+			return nil, err
+		}
+		if l.Shingles, err = shingle.Decode(packed); err != nil {
+			return nil, err
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+// Echo is a previous letter a new one resembles.
+type Echo struct {
+	Letter     Letter
+	Similarity float64
+	// Passages are the longest stretches of the new letter that also
+	// appear in the old one.
+	Passages []string
+}
+
+// Echoes compares body with the last EchoWindow letters and returns those
+// at or above threshold, most similar first.
+func (s *Store) Echoes(body string, threshold float64) ([]Echo, error) {
+	letters, err := s.RecentLetters(EchoWindow)
+	if err != nil {
+		return nil, err
+	}
+	return FindEchoes(body, letters, threshold), nil
+}
+
+// FindEchoes returns the letters body resembles at or above threshold,
+// most similar first.
+func FindEchoes(body string, letters []Letter, threshold float64) []Echo {
+	set := shingle.Of(body)
+	var echoes []Echo
+	for _, l := range letters {
+		sim := shingle.Jaccard(set, l.Shingles)
+		if sim < threshold || sim == 0 {
+			continue
+		}
+		echoes = append(echoes, Echo{Letter: l, Similarity: sim, Passages: shingle.Passages(body, l.Shingles, 3)})
+	}
+	sort.SliceStable(echoes, func(i, j int) bool { return echoes[i].Similarity > echoes[j].Similarity })
+	return echoes
+}
@@ -0,0 +1,156 @@
+package application
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+const acmeLetter = `Dear Acme hiring team,
+
+I am writing to apply for the Backend Engineer role. Over the past five
+years I have built payment services in Go and Postgres, most recently
+leading the migration of a monolith to event-driven services on Kafka,
+which cut settlement latency from hours to under a minute.
+
+What draws me to Acme is your work on real-time fraud scoring. I have
+shipped a rules engine that evaluates ten thousand transactions per second
+with a p99 under five milliseconds, and I would love to bring that
+experience to a team that cares about reliability as much as speed.
+
+I enjoy mentoring, writing design documents that people actually read, and
+being on call for the systems I build.
+
+I look forward to hearing from you.
+
+Best regards,
+Sam`
+
+// globexLetter is acmeLetter rewritten for another company: new name, one
+// new paragraph, the rest copied.
+const globexLetter = `Hi Globex team,
+
+I am writing to apply for the Backend Engineer role. Over the past five
+years I have built payment services in Go and Postgres, most recently
+leading the migration of a monolith to event-driven services on Kafka,
+which cut settlement latency from hours to under a minute.
+
+Globex's open-source ledger caught my eye; I have read the consensus code
+and have a few ideas about batching writes.
+
+I have shipped a rules engine that evaluates ten thousand transactions per
+second with a p99 under five milliseconds, and I would love to bring that
+experience to a team that cares about reliability as much as speed.
+
+I enjoy mentoring, writing design documents that people actually read, and
+being on call for the systems I build.
+
+I look forward to hearing from you.
+
+Best regards,
+Sam`
+
+// initechLetter is by the same person about the same skills, written on
+// its own: it shares stock phrases and facts, not paragraphs.
+const initechLetter = `Dear Initech hiring team,
+
+I am writing to apply for the Platform Engineer position. For five years
+I have worked on payment services in Go and Postgres, and I led our move
+from a monolith to event-driven services on Kafka.
+
+Your post mentions on-call fatigue. At my current company I rewrote our
+alerting so that pages dropped by two thirds, and I run a weekly review of
+every incident with the engineers who were paged.
+
+I like mentoring junior engineers and I write design documents before
+writing code.
+
+I look forward to hearing from you.
+
+Best regards,
+Sam`
+
+func TestFindEchoes_NearDuplicateVsSimilar(t *testing.T) {
+	s := openTestStore(t)
+	sent := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	if err := s.AddLetter(&Letter{ApplicationID: 1, Company: "Acme", Body: acmeLetter, SentAt: sent}); err != nil {
+		t.Fatal(err)
+	}
+
+	echoes, err := s.Echoes(globexLetter, DefaultEchoThreshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(echoes) != 1 || echoes[0].Letter.Company != "Acme" || !echoes[0].Letter.SentAt.Equal(sent) {
+		t.Fatalf("near-duplicate not flagged against Acme: %+v", echoes)
+	}
+	e := echoes[0]
+	if len(e.Passages) != 2 || !strings.HasPrefix(e.Passages[0], "I have shipped a rules engine") ||
+		!strings.HasSuffix(e.Passages[1], "from hours to under a minute") {
+		t.Errorf("want the copied closing and opening, longest first; got %q", e.Passages)
+	}
+	for _, p := range e.Passages {
+		if strings.Contains(p, "Globex") {
+			t.Errorf("passage %q includes text only the new letter has", p)
+		}
+	}
+
+	echoes, err = s.Echoes(initechLetter, DefaultEchoThreshold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(echoes) != 0 {
+		t.Errorf("separately written letter flagged at %.2f: %q", echoes[0].Similarity, echoes[0].Passages)
+	}
+}
+
+func TestEchoThreshold(t *testing.T) {
+	for v, want := range map[string]float64{"": DefaultEchoThreshold, "0.5": 0.5, "2": DefaultEchoThreshold, "x": DefaultEchoThreshold} {
+		t.Setenv(EnvEchoThreshold, v)
+		if got := EchoThreshold(); got != want {
+			t.Errorf("%s=%q: got %v, want %v", EnvEchoThreshold, v, got, want)
+		}
+	}
+}
+
+func TestRecentLetters_Window(t *testing.T) {
+	s := openTestStore(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 1; i <= 3; i++ {
+		l := &Letter{ApplicationID: int64(i), Company: fmt.Sprint("c", i), Body: acmeLetter, SentAt: start.AddDate(0, 0, i)}
+		if err := s.AddLetter(l); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := s.RecentLetters(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Company != "c3" || got[1].Company != "c2" || len(got[0].Shingles) == 0 {
+		t.Errorf("RecentLetters(2) = %+v", got)
+	}
+}
+
+// BenchmarkEchoes checks a new letter against a full window of stored
+// letters, including loading their shingles.
+func BenchmarkEchoes(b *testing.B) {
+	s := openTestStore(b)
+	rng := rand.New(rand.NewSource(1))
+	words := strings.Fields(acmeLetter + " " + initechLetter)
+	for i := 0; i < EchoWindow; i++ {
+		body := make([]string, 300)
+		for w := range body {
+			body[w] = words[rng.Intn(len(words))]
+		}
+		if err := s.AddLetter(&Letter{ApplicationID: int64(i), Body: strings.Join(body, " ")}); err != nil {
+			b.Fatal(err)
+		}
+	}
+	for b.Loop() {
+		if _, err := s.Echoes(globexLetter, DefaultEchoThreshold); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package application
+
+import "time"
+
+// Status tracks where an application stands.
+type Status string
+
+const (
+	StatusApplied   Status = "applied"
+	StatusReplied   Status = "replied"
+	StatusInterview Status = "interview"
+	StatusOffer     Status = "offer"
+	StatusRejected  Status = "rejected"
+	StatusWithdrawn Status = "withdrawn"
+)
+
+// Method is how an application was submitted.
+type Method string
+
+const (
+	MethodEmail  Method = "email"
+	MethodPortal Method = "portal"
+	MethodOther  Method = "other"
+)
+
+// Application is a record of having applied to a job.
+type Application struct {
+	ID        int64      `json:"id"`
+	JobID     string     `json:"job_id"`
+	ProfileID string     `json:"profile_id"`
+	Company   string     `json:"company"`
+	Title     string     `json:"title"`
+	Method    Method     `json:"method"`
+	Status    Status     `json:"status"`
+	AppliedAt time.Time  `json:"applied_at"`
+	RepliedAt *time.Time `json:"replied_at,omitempty"`
+	Notes     string     `json:"notes,omitempty"`
+
+	// OpenedAt and ClickedAt are the first tracked open and click of the
+	// application email, when the profile has tracking enabled.
+	OpenedAt  *time.Time `json:"opened_at,omitempty"`
+	ClickedAt *time.Time `json:"clicked_at,omitempty"`
+
+	// Posting is the ad as captured at application time, if any.
+	Posting *Posting `json:"posting,omitempty"`
+
+	// Sent is what an emailed application sent, when it was recorded.
+	Sent *Sent `json:"sent,omitempty"`
+
+	// WithdrawnAt and WithdrawReason are set when the applicant pulled out.
+	WithdrawnAt    *time.Time `json:"withdrawn_at,omitempty"`
+	WithdrawReason string     `json:"withdraw_reason,omitempty"`
+}
+
+// Responded reports whether the employer got back in any form.
+func (a Application) Responded() bool {
+	return a.RepliedAt != nil || (a.Status != StatusApplied && a.Status != StatusWithdrawn && a.Status != "")
+}
+
+// Interviewed reports whether the application reached the interview stage.
+func (a Application) Interviewed() bool {
+	return a.Status == StatusInterview || a.Status == StatusOffer
+}
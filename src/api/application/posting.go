@@ -0,0 +1,48 @@
+package application
+
+import (
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// Posting is the job ad as it read when the application was made. Ads are
+// edited or pulled after the fact, so this copy is never updated.
+type Posting struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Salary      string    `json:"salary"`
+	Location    string    `json:"location"`
+	URL         string    `json:"url"`
+	CapturedAt  time.Time `json:"captured_at"`
+}
+
+// SnapshotPosting copies the fields of j worth keeping.
+func SnapshotPosting(j job.Job, at time.Time) *Posting {
+	return &Posting{
+		Title:       j.Title,
+		Description: j.Description,
+		Salary:      j.Salary,
+		Location:    j.Location,
+		URL:         j.URL,
+		CapturedAt:  at,
+	}
+}
+
+// postingColumns were added after the applications table first shipped.
+var postingColumns = []job.Column{
+	{Name: "posting_title", Decl: "TEXT DEFAULT ''"},
+	{Name: "posting_description", Decl: "TEXT DEFAULT ''"},
+	{Name: "posting_salary", Decl: "TEXT DEFAULT ''"},
+	{Name: "posting_location", Decl: "TEXT DEFAULT ''"},
+	{Name: "posting_url", Decl: "TEXT DEFAULT ''"},
+	{Name: "posting_at", Decl: "DATETIME DEFAULT NULL"},
+}
+
+// postingArgs flattens p for INSERT; a nil posting stores no snapshot.
+func postingArgs(p *Posting) []any {
+	if p == nil {
+		return []any{"", "", "", "", "", nil}
+	}
+	return []any{p.Title, p.Description, p.Salary, p.Location, p.URL, p.CapturedAt.UTC()}
+}
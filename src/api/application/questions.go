@@ -0,0 +1,256 @@
+package application
+
+import (
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// QuestionSource tells how a question got onto a job.
+type QuestionSource string
+
+const (
+	SourceDetected QuestionSource = "detected" // read from the posting
+	SourceManual   QuestionSource = "manual"
+)
+
+// Question is something an employer asks applicants to answer, with the
+// answer given. Questions belong to a job and profile so they can be
+// answered before the application is recorded.
+type Question struct {
+	ID        int64          `json:"id"`
+	JobID     string         `json:"job_id"`
+	ProfileID string         `json:"profile_id"`
+	Text      string         `json:"question"`
+	Answer    string         `json:"answer,omitempty"`
+	Source    QuestionSource `json:"source"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// Answered reports whether q has a non-blank answer.
+func (q Question) Answered() bool { return strings.TrimSpace(q.Answer) != "" }
+
+// migrateQuestions creates the questions table and an FTS4 index over
+// question and answer text, kept in sync by triggers.
+func migrateQuestions(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS application_questions (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			job_id     TEXT NOT NULL,
+			profile_id TEXT NOT NULL,
+			question   TEXT NOT NULL,
+			answer     TEXT DEFAULT '',
+			source     TEXT,
+			created_at DATETIME,
+			updated_at DATETIME,
+			UNIQUE (job_id, profile_id, question)
+		);
+		CREATE VIRTUAL TABLE IF NOT EXISTS question_search USING fts4(question, answer, tokenize=porter);
+		CREATE TRIGGER IF NOT EXISTS question_search_insert AFTER INSERT ON application_questions BEGIN
+			INSERT INTO question_search (docid, question, answer) VALUES (new.id, new.question, new.answer);
+		END;
+		CREATE TRIGGER IF NOT EXISTS question_search_update AFTER UPDATE ON application_questions BEGIN
+			UPDATE question_search SET question = new.question, answer = new.answer WHERE docid = new.id;
+		END;
+		CREATE TRIGGER IF NOT EXISTS question_search_delete AFTER DELETE ON application_questions BEGIN
+			DELETE FROM question_search WHERE docid = old.id;
+		END;`)
+	return err
+}
+
+const questionColumns = `id, job_id, profile_id, question, answer, source, created_at, updated_at`
+
+// AddQuestion stores q and sets its ID. Asking the same question twice for
+// a job keeps the first, with its answer.
+func (s *Store) AddQuestion(q *Question) error {
+	q.Text = strings.TrimSpace(q.Text)
+	if q.Source == "" {
+		q.Source = SourceManual
+	}
+	now := time.Now().UTC()
+	if _, err := s.db.Exec(`
+		INSERT OR IGNORE INTO application_questions (job_id, profile_id, question, answer, source, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		q.JobID, q.ProfileID, q.Text, q.Answer, q.Source, now, now); err != nil {
+		return err
+	}
+	qs, err := s.queryQuestions(`SELECT `+questionColumns+` FROM application_questions
+		WHERE job_id = ? AND profile_id = ? AND question = ?`, q.JobID, q.ProfileID, q.Text)
+	if err != nil {
+		return err
+	}
+	if len(qs) == 0 {
+		return sql.ErrNoRows
+	}
+	*q = qs[0]
+	return nil
+}
+
+// AnswerQuestion records the answer to question id.
+func (s *Store) AnswerQuestion(id int64, answer string) error {
+	res, err := s.db.Exec(`UPDATE application_questions SET answer = ?, updated_at = ? WHERE id = ?`,
+		strings.TrimSpace(answer), time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteQuestion removes question id.
+func (s *Store) DeleteQuestion(id int64) error {
+	_, err := s.db.Exec(`DELETE FROM application_questions WHERE id = ?`, id)
+	return err
+}
+
+// QuestionByID loads one question.
+func (s *Store) QuestionByID(id int64) (*Question, error) {
+	qs, err := s.queryQuestions(`SELECT `+questionColumns+` FROM application_questions WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(qs) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &qs[0], nil
+}
+
+// Questions lists a job's questions for a profile in the order added.
+func (s *Store) Questions(jobID, profileID string) ([]Question, error) {
+	return s.queryQuestions(`SELECT `+questionColumns+` FROM application_questions
+		WHERE job_id = ? AND profile_id = ? ORDER BY id`, jobID, profileID)
+}
+
+// Answers lists the answered questions of a job for a profile.
+func (s *Store) Answers(jobID, profileID string) ([]Question, error) {
+	qs, err := s.Questions(jobID, profileID)
+	if err != nil {
+		return nil, err
+	}
+	var out []Question
+	for _, q := range qs {
+		if q.Answered() {
+			out = append(out, q)
+		}
+	}
+	return out, nil
+}
+
+// AnswerMatch is a previous answer found by SimilarAnswers. Score is the
+// share of the searched question's terms it has in common, 0 to 1.
+type AnswerMatch struct {
+	Question
+	Score float64 `json:"score"`
+}
+
+// SimilarAnswers searches answered questions, across all jobs, for ones
+// worded like text, best match first. The full-text index stems words,
+// so "led an incident" finds "leading incidents".
+func (s *Store) SimilarAnswers(text string, limit int) ([]AnswerMatch, error) {
+	terms := searchTerms(text)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	qs, err := s.queryQuestions(`
+		SELECT `+prefixedQuestionColumns+`
+		FROM question_search f JOIN application_questions q ON q.id = f.docid
+		WHERE question_search MATCH ? AND q.answer != ''`, strings.Join(terms, " OR "))
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]AnswerMatch, 0, len(qs))
+	for _, q := range qs {
+		matches = append(matches, AnswerMatch{Question: q, Score: overlap(terms, searchTerms(q.Text))})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].UpdatedAt.After(matches[j].UpdatedAt)
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+var prefixedQuestionColumns = func() string {
+	cols := strings.Split(questionColumns, ",")
+	for i, c := range cols {
+		cols[i] = "q." + strings.TrimSpace(c)
+	}
+	return strings.Join(cols, ", ")
+}()
+
+// stopWords are left out of searches; nearly every question has them.
+var stopWords = map[string]bool{
+	"the": true, "and": true, "you": true, "your": true, "for": true, "with": true,
+	"that": true, "this": true, "what": true, "how": true, "why": true, "when": true,
+	"tell": true, "about": true, "describe": true, "time": true, "have": true,
+	"are": true, "was": true, "were": true, "did": true, "does": true, "our": true,
+	"please": true, "would": true, "can": true, "could": true, "give": true, "example": true,
+}
+
+// searchTerms lowercases text into distinct words of three or more
+// letters, without stop words. The result is safe to use in MATCH.
+func searchTerms(text string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(w) < 3 || stopWords[w] || seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+// overlap is the share of want found in got, comparing word stems
+// roughly by their first five letters.
+func overlap(want, got []string) float64 {
+	stem := func(w string) string {
+		if len(w) > 5 {
+			return w[:5]
+		}
+		return w
+	}
+	have := make(map[string]bool, len(got))
+	for _, w := range got {
+		have[stem(w)] = true
+	}
+	n := 0
+	for _, w := range want {
+		if have[stem(w)] {
+			n++
+		}
+	}
+	return float64(n) / float64(len(want))
+}
+
+func (s *Store) queryQuestions(q string, args ...any) ([]Question, error) {
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Question
+	for rows.Next() {
+		var qu Question
+		if err := rows.Scan(&qu.ID, &qu.JobID, &qu.ProfileID, &qu.Text, &qu.Answer, &qu.Source,
+			&qu.CreatedAt, &qu.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, qu)
+	}
+	return out, rows.Err()
+}
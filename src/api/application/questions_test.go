@@ -0,0 +1,91 @@
+package application
+
+import (
+	"testing"
+)
+
+func TestQuestions_AddAnswerDedupe(t *testing.T) {
+	s := openTestStore(t)
+	q := &Question{JobID: "j1", ProfileID: "p", Text: "  Describe a time you led an incident.  ", Source: SourceDetected}
+	if err := s.AddQuestion(q); err != nil {
+		t.Fatal(err)
+	}
+	if q.ID == 0 || q.Text != "Describe a time you led an incident." || q.CreatedAt.IsZero() {
+		t.Fatalf("added %+v", q)
+	}
+	if err := s.AnswerQuestion(q.ID, "During the 2023 outage I ran the bridge call.\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Detecting the same question again keeps the answer.
+	again := &Question{JobID: "j1", ProfileID: "p", Text: q.Text, Source: SourceDetected}
+	if err := s.AddQuestion(again); err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != q.ID || again.Answer != "During the 2023 outage I ran the bridge call." {
+		t.Errorf("re-added %+v", again)
+	}
+
+	s.AddQuestion(&Question{JobID: "j1", ProfileID: "p", Text: "Why Go?"})
+	s.AddQuestion(&Question{JobID: "j1", ProfileID: "other", Text: "Why Rust?"})
+	qs, err := s.Questions("j1", "p")
+	if err != nil || len(qs) != 2 {
+		t.Fatalf("Questions = %+v, %v", qs, err)
+	}
+	if qs[1].Source != SourceManual {
+		t.Errorf("source = %q, want manual by default", qs[1].Source)
+	}
+	answers, _ := s.Answers("j1", "p")
+	if len(answers) != 1 || answers[0].ID != q.ID {
+		t.Errorf("Answers = %+v", answers)
+	}
+
+	if err := s.AnswerQuestion(999, "x"); err == nil {
+		t.Error("answering a missing question succeeded")
+	}
+}
+
+func TestQuestions_SimilarAnswers(t *testing.T) {
+	s := openTestStore(t)
+	add := func(job, text, answer string) int64 {
+		q := &Question{JobID: job, ProfileID: "p", Text: text}
+		if err := s.AddQuestion(q); err != nil {
+			t.Fatal(err)
+		}
+		if answer != "" {
+			s.AnswerQuestion(q.ID, answer)
+		}
+		return q.ID
+	}
+	incident := add("acme", "Tell us about leading incidents in production.", "I led the payments outage response.")
+	review := add("acme", "How do you approach code review?", "Small PRs, fast feedback.")
+	add("globex", "Describe a production incident you led.", "") // unanswered: not a result
+	kafka := add("initech", "What is your experience with Kafka?", "Three years running Kafka at scale.")
+
+	got, err := s.SimilarAnswers("Describe a time you led an incident response in production", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != incident {
+		t.Fatalf("matches = %+v, want the incident answer only", got)
+	}
+	if got[0].Score <= 0 || got[0].Score > 1 {
+		t.Errorf("score = %v", got[0].Score)
+	}
+
+	// Answers are searchable too, and results are ranked.
+	got, _ = s.SimilarAnswers("kafka code review", 5)
+	if len(got) != 2 || got[0].ID != review || got[1].ID != kafka {
+		t.Errorf("matches = %+v", got)
+	}
+
+	if err := s.DeleteQuestion(kafka); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.SimilarAnswers("kafka", 5); len(got) != 0 {
+		t.Errorf("deleted question still indexed: %+v", got)
+	}
+	if got, _ := s.SimilarAnswers("the and you", 5); got != nil {
+		t.Errorf("stop words matched %+v", got)
+	}
+}
@@ -0,0 +1,210 @@
+package application
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// Store persists applications alongside jobs and profiles.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore wraps a database connection for application storage.
+func NewStore(db *sql.DB) (*Store, error) {
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS applications (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			job_id     TEXT,
+			profile_id TEXT,
+			company    TEXT,
+			title      TEXT,
+			method     TEXT,
+			status     TEXT,
+			applied_at DATETIME,
+			replied_at DATETIME,
+			notes      TEXT DEFAULT ''
+		);
+		CREATE INDEX IF NOT EXISTS idx_applications_applied_at ON applications(applied_at);`)
+	if err != nil {
+		return err
+	}
+	if err := job.EnsureColumns(db, "applications", postingColumns); err != nil {
+		return err
+	}
+	if err := migrateTracking(db); err != nil {
+		return err
+	}
+	if err := migrateThreads(db); err != nil {
+		return err
+	}
+	if err := migrateLetters(db); err != nil {
+		return err
+	}
+	if err := job.EnsureColumns(db, "applications", withdrawalColumns); err != nil {
+		return err
+	}
+	if err := job.EnsureColumns(db, "applications", sentColumns); err != nil {
+		return err
+	}
+	return migrateQuestions(db)
+}
+
+const columns = `id, job_id, profile_id, company, title, method, status, applied_at, replied_at, notes,
+	posting_title, posting_description, posting_salary, posting_location, posting_url, posting_at,
+	withdrawn_at, withdraw_reason,
+	sent_from, sent_to, sent_cc, sent_subject, sent_body, sent_cv_path, sent_cover_letter, sent_at` + engagementColumns
+
+// Add records a new application and sets its ID.
+func (s *Store) Add(a *Application) error {
+	if a.Status == "" {
+		a.Status = StatusApplied
+	}
+	if a.AppliedAt.IsZero() {
+		a.AppliedAt = time.Now()
+	}
+	args := append([]any{a.JobID, a.ProfileID, a.Company, a.Title, a.Method, a.Status,
+		a.AppliedAt.UTC(), utc(a.RepliedAt), a.Notes}, postingArgs(a.Posting)...)
+	args = append(args, sentArgs(a.Sent)...)
+	res, err := s.db.Exec(`
+		INSERT INTO applications (job_id, profile_id, company, title, method, status, applied_at, replied_at, notes,
+			posting_title, posting_description, posting_salary, posting_location, posting_url, posting_at,
+			sent_from, sent_to, sent_cc, sent_subject, sent_body, sent_cv_path, sent_cover_letter, sent_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, args...)
+	if err != nil {
+		return err
+	}
+	a.ID, err = res.LastInsertId()
+	return err
+}
+
+// SetStatus updates an application's status, stamping the reply date the
+// first time the employer responds.
+func (s *Store) SetStatus(id int64, status Status, at time.Time) error {
+	_, err := s.db.Exec(`
+		UPDATE applications
+		SET status = ?,
+		    replied_at = CASE WHEN replied_at IS NULL AND ? NOT IN ('applied', 'withdrawn') THEN ? ELSE replied_at END
+		WHERE id = ?`, status, status, at.UTC(), id)
+	return err
+}
+
+// MoveJob points everything recorded against job from at job to, for
+// when from turns out to duplicate it. A question to already has keeps
+// its own answer.
+func (s *Store) MoveJob(from, to string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, q := range []string{
+		`UPDATE applications SET job_id = ? WHERE job_id = ?`,
+		`UPDATE tracking_links SET job_id = ? WHERE job_id = ?`,
+		`UPDATE OR IGNORE application_questions SET job_id = ? WHERE job_id = ?`,
+	} {
+		if _, err := tx.Exec(q, to, from); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM application_questions WHERE job_id = ?`, from); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ByID returns a single application.
+func (s *Store) ByID(id int64) (*Application, error) {
+	apps, err := s.query(`SELECT `+columns+` FROM applications WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(apps) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &apps[0], nil
+}
+
+// All returns every application, oldest first.
+func (s *Store) All() ([]Application, error) {
+	return s.query(`SELECT ` + columns + ` FROM applications ORDER BY applied_at, id`)
+}
+
+// Between returns applications submitted in [from, to]. A zero bound is open.
+func (s *Store) Between(from, to time.Time) ([]Application, error) {
+	if from.IsZero() {
+		from = time.Unix(0, 0)
+	}
+	if to.IsZero() {
+		to = time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return s.query(`SELECT `+columns+` FROM applications
+		WHERE applied_at >= ? AND applied_at <= ? ORDER BY applied_at, id`, from.UTC(), to.UTC())
+}
+
+// utc normalizes optional timestamps so stored values compare as text.
+func utc(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+func (s *Store) query(q string, args ...any) ([]Application, error) {
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var apps []Application
+	for rows.Next() {
+		var a Application
+		var p Posting
+		var sent Sent
+		var sentCc string
+		var replied, captured, withdrawn, sentAt sql.NullTime
+		var opened, clicked sql.NullString
+		err := rows.Scan(&a.ID, &a.JobID, &a.ProfileID, &a.Company, &a.Title,
+			&a.Method, &a.Status, &a.AppliedAt, &replied, &a.Notes,
+			&p.Title, &p.Description, &p.Salary, &p.Location, &p.URL, &captured,
+			&withdrawn, &a.WithdrawReason,
+			&sent.From, &sent.To, &sentCc, &sent.Subject, &sent.Body, &sent.CVPath, &sent.CoverLetterPath, &sentAt,
+			&opened, &clicked)
+		if err != nil {
+			return nil, err
+		}
+		a.OpenedAt, a.ClickedAt = scanTime(opened), scanTime(clicked)
+		if replied.Valid {
+			t := replied.Time
+			a.RepliedAt = &t
+		}
+		if captured.Valid {
+			p.CapturedAt = captured.Time
+			a.Posting = &p
+		}
+		if withdrawn.Valid {
+			t := withdrawn.Time
+			a.WithdrawnAt = &t
+		}
+		if sentAt.Valid {
+			sent.SentAt = sentAt.Time
+			if sentCc != "" {
+				sent.Cc = strings.Split(sentCc, ",")
+			}
+			a.Sent = &sent
+		}
+		apps = append(apps, a)
+	}
+	return apps, rows.Err()
+}
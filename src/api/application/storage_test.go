@@ -0,0 +1,125 @@
+package application
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openTestStore(t testing.TB) *Store {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	s, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestStore_BetweenAndStatus(t *testing.T) {
+	s := openTestStore(t)
+	jan := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+	jul := time.Date(2024, 7, 2, 9, 0, 0, 0, time.UTC)
+
+	a := &Application{JobID: "j1", Company: "Acme", Title: "Go Dev", Method: MethodEmail, AppliedAt: jan}
+	if err := s.Add(a); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(&Application{JobID: "j2", Company: "Later", AppliedAt: jul}); err != nil {
+		t.Fatal(err)
+	}
+
+	apps, err := s.Between(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 1 || apps[0].Company != "Acme" || apps[0].Status != StatusApplied {
+		t.Fatalf("unexpected range result: %+v", apps)
+	}
+
+	replied := jan.Add(72 * time.Hour)
+	if err := s.SetStatus(a.ID, StatusInterview, replied); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetStatus(a.ID, StatusOffer, replied.Add(240*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	all, err := s.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := all[0]
+	if got.Status != StatusOffer || got.RepliedAt == nil || !got.RepliedAt.Equal(replied) {
+		t.Errorf("expected offer with first reply date kept, got %+v", got)
+	}
+}
+
+func TestStore_ThreadAppendsOnce(t *testing.T) {
+	s := openTestStore(t)
+	a := &Application{Company: "Acme"}
+	if err := s.Add(a); err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+	in := &ThreadMessage{ApplicationID: a.ID, Direction: Incoming, MessageID: "<r@acme.dev>", Date: at}
+	out := &ThreadMessage{ApplicationID: a.ID, Direction: Outgoing, MessageID: "<o@me>", Date: at.Add(time.Hour)}
+	again := &ThreadMessage{ApplicationID: a.ID, Direction: Incoming, MessageID: "<r@acme.dev>", Date: at}
+	for _, m := range []*ThreadMessage{in, out, again} {
+		if err := s.AppendMessage(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if again.ID != in.ID {
+		t.Errorf("repeated message got ID %d, want the stored %d", again.ID, in.ID)
+	}
+
+	thread, err := s.Thread(a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(thread) != 2 || thread[0].Direction != Incoming || thread[1].MessageID != "<o@me>" {
+		t.Errorf("unexpected thread: %+v", thread)
+	}
+}
+
+func TestStore_MoveJob(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Add(&Application{JobID: "dup", ProfileID: "alice", Company: "Acme"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []*Question{
+		{JobID: "dup", ProfileID: "alice", Text: "Why Acme?", Answer: "old"},
+		{JobID: "dup", ProfileID: "alice", Text: "Notice period?"},
+		{JobID: "kept", ProfileID: "alice", Text: "Why Acme?", Answer: "kept"},
+	} {
+		if err := s.AddQuestion(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.MoveJob("dup", "kept"); err != nil {
+		t.Fatal(err)
+	}
+	apps, _ := s.All()
+	if len(apps) != 1 || apps[0].JobID != "kept" {
+		t.Errorf("applications = %+v", apps)
+	}
+	qs, err := s.Questions("kept", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(qs) != 2 || qs[1].Text != "Why Acme?" || qs[1].Answer != "kept" {
+		t.Errorf("questions = %+v", qs)
+	}
+	if left, _ := s.Questions("dup", "alice"); len(left) != 0 {
+		t.Errorf("questions left on the duplicate: %+v", left)
+	}
+}
@@ -0,0 +1,88 @@
+package application
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Direction tells whether a thread message was sent or received.
+type Direction string
+
+const (
+	Outgoing Direction = "out"
+	Incoming Direction = "in"
+)
+
+// ThreadMessage is one email in an application's conversation.
+type ThreadMessage struct {
+	ID            int64     `json:"id"`
+	ApplicationID int64     `json:"application_id"`
+	Direction     Direction `json:"direction"`
+	MessageID     string    `json:"message_id"`
+	From          string    `json:"from"`
+	To            string    `json:"to"`
+	Subject       string    `json:"subject"`
+	Date          time.Time `json:"date"`
+	Body          string    `json:"body"`
+}
+
+func migrateThreads(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS application_messages (
+			id             INTEGER PRIMARY KEY AUTOINCREMENT,
+			application_id INTEGER NOT NULL,
+			direction      TEXT,
+			message_id     TEXT,
+			from_addr      TEXT,
+			to_addr        TEXT,
+			subject        TEXT,
+			date           DATETIME,
+			body           TEXT,
+			UNIQUE (application_id, message_id)
+		)`)
+	return err
+}
+
+// AppendMessage adds m to its application's thread and sets m.ID. A
+// message already in the thread (same Message-ID) is left alone, and m.ID
+// is set to the stored one.
+func (s *Store) AppendMessage(m *ThreadMessage) error {
+	res, err := s.db.Exec(`
+		INSERT OR IGNORE INTO application_messages
+		(application_id, direction, message_id, from_addr, to_addr, subject, date, body)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.ApplicationID, m.Direction, m.MessageID, m.From, m.To, m.Subject, m.Date.UTC(), m.Body)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return s.db.QueryRow(`SELECT id FROM application_messages WHERE application_id = ? AND message_id = ?`,
+			m.ApplicationID, m.MessageID).Scan(&m.ID)
+	}
+	m.ID, err = res.LastInsertId()
+	return err
+}
+
+// Thread returns an application's messages in date order.
+func (s *Store) Thread(applicationID int64) ([]ThreadMessage, error) {
+	rows, err := s.db.Query(`
+		SELECT id, application_id, direction, message_id, from_addr, to_addr, subject, date, body
+		FROM application_messages WHERE application_id = ? ORDER BY date, id`, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var msgs []ThreadMessage
+	for rows.Next() {
+		var m ThreadMessage
+		if err := rows.Scan(&m.ID, &m.ApplicationID, &m.Direction, &m.MessageID,
+			&m.From, &m.To, &m.Subject, &m.Date, &m.Body); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, m)
+	}
+	return msgs, rows.Err()
+}
@@ -0,0 +1,171 @@
+package application
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// TrackKind is what a tracking link records.
+type TrackKind string
+
+const (
+	TrackOpen  TrackKind = "open"  // image loaded by the mail client
+	TrackClick TrackKind = "click" // link followed by the reader
+)
+
+// TrackingLink is a tracked URL placed in an application email. Like
+// questions, links belong to a job and profile, since they are made before
+// the send that records the application.
+type TrackingLink struct {
+	ID        int64     `json:"id"`
+	JobID     string    `json:"job_id"`
+	ProfileID string    `json:"profile_id"`
+	Kind      TrackKind `json:"kind"`
+	URL       string    `json:"url,omitempty"` // click target; empty for opens
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TrackingHit is one recorded open or click.
+type TrackingHit struct {
+	LinkID   int64     `json:"link_id"`
+	Kind     TrackKind `json:"kind"`
+	URL      string    `json:"url,omitempty"`
+	At       time.Time `json:"at"`
+	UAFamily string    `json:"ua_family"`
+}
+
+func migrateTracking(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS tracking_links (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			job_id     TEXT NOT NULL,
+			profile_id TEXT NOT NULL,
+			kind       TEXT NOT NULL,
+			url        TEXT DEFAULT '',
+			created_at DATETIME
+		);
+		CREATE INDEX IF NOT EXISTS idx_tracking_links_job ON tracking_links(job_id, profile_id);
+		CREATE TABLE IF NOT EXISTS tracking_hits (
+			id        INTEGER PRIMARY KEY AUTOINCREMENT,
+			link_id   INTEGER NOT NULL,
+			at        DATETIME,
+			ua_family TEXT DEFAULT ''
+		);
+		CREATE INDEX IF NOT EXISTS idx_tracking_hits_link ON tracking_hits(link_id);`)
+	return err
+}
+
+// engagementColumns add the first open and first click of each
+// application's emails to the columns read by query.
+const engagementColumns = `,
+	(SELECT MIN(h.at) FROM tracking_hits h JOIN tracking_links l ON l.id = h.link_id
+	 WHERE l.job_id = applications.job_id AND l.profile_id = applications.profile_id AND l.kind = 'open'),
+	(SELECT MIN(h.at) FROM tracking_hits h JOIN tracking_links l ON l.id = h.link_id
+	 WHERE l.job_id = applications.job_id AND l.profile_id = applications.profile_id AND l.kind = 'click')`
+
+// AddTrackingLink stores l and sets its ID and creation time.
+func (s *Store) AddTrackingLink(l *TrackingLink) error {
+	if l.CreatedAt.IsZero() {
+		l.CreatedAt = time.Now()
+	}
+	res, err := s.db.Exec(`INSERT INTO tracking_links (job_id, profile_id, kind, url, created_at) VALUES (?, ?, ?, ?, ?)`,
+		l.JobID, l.ProfileID, l.Kind, l.URL, l.CreatedAt.UTC())
+	if err != nil {
+		return err
+	}
+	l.ID, err = res.LastInsertId()
+	return err
+}
+
+// TrackingLinkByID loads one link.
+func (s *Store) TrackingLinkByID(id int64) (*TrackingLink, error) {
+	var l TrackingLink
+	err := s.db.QueryRow(`SELECT id, job_id, profile_id, kind, url, created_at FROM tracking_links WHERE id = ?`, id).
+		Scan(&l.ID, &l.JobID, &l.ProfileID, &l.Kind, &l.URL, &l.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// RecordHit stores a hit on link at the given time.
+func (s *Store) RecordHit(linkID int64, at time.Time, uaFamily string) error {
+	_, err := s.db.Exec(`INSERT INTO tracking_hits (link_id, at, ua_family) VALUES (?, ?, ?)`,
+		linkID, at.UTC(), uaFamily)
+	return err
+}
+
+// Hits lists the recorded opens and clicks on a job's emails for a
+// profile, oldest first.
+func (s *Store) Hits(jobID, profileID string) ([]TrackingHit, error) {
+	return s.hits(`WHERE l.job_id = ? AND l.profile_id = ?`, jobID, profileID)
+}
+
+// JobHits lists the recorded opens and clicks on a job's emails from
+// every profile, oldest first.
+func (s *Store) JobHits(jobID string) ([]TrackingHit, error) {
+	return s.hits(`WHERE l.job_id = ?`, jobID)
+}
+
+func (s *Store) hits(where string, args ...any) ([]TrackingHit, error) {
+	rows, err := s.db.Query(`
+		SELECT h.link_id, l.kind, l.url, h.at, h.ua_family
+		FROM tracking_hits h JOIN tracking_links l ON l.id = h.link_id
+		`+where+`
+		ORDER BY h.at, h.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []TrackingHit
+	for rows.Next() {
+		var h TrackingHit
+		if err := rows.Scan(&h.LinkID, &h.Kind, &h.URL, &h.At, &h.UAFamily); err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+// OpenCounts counts the recorded opens of each job's emails, by job ID.
+// Jobs never opened are absent.
+func (s *Store) OpenCounts() (map[string]int, error) {
+	rows, err := s.db.Query(`
+		SELECT l.job_id, COUNT(*)
+		FROM tracking_hits h JOIN tracking_links l ON l.id = h.link_id
+		WHERE l.kind = 'open'
+		GROUP BY l.job_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var id string
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+		counts[id] = n
+	}
+	return counts, rows.Err()
+}
+
+// scanTime reads a timestamp computed by an expression, which SQLite
+// returns as text without a declared type.
+func scanTime(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	for _, layout := range sqlite3.SQLiteTimestampFormats {
+		if t, err := time.Parse(layout, s.String); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// ErrNotWithdrawable is returned for applications that are already over.
+var ErrNotWithdrawable = errors.New("application cannot be withdrawn")
+
+var withdrawalColumns = []job.Column{
+	{Name: "withdrawn_at", Decl: "DATETIME DEFAULT NULL"},
+	{Name: "withdraw_reason", Decl: "TEXT DEFAULT ''"},
+}
+
+// Withdrawable reports whether an application in status s is still open
+// for the applicant to pull out of. A rejection or an earlier withdrawal
+// has already closed it.
+func (s Status) Withdrawable() bool {
+	return s != StatusRejected && s != StatusWithdrawn
+}
+
+// Withdraw marks an application withdrawn at at, keeping reason. It fails
+// with ErrNotWithdrawable when the application's status does not allow it.
+func (s *Store) Withdraw(id int64, reason string, at time.Time) error {
+	res, err := s.db.Exec(`
+		UPDATE applications SET status = ?, withdrawn_at = ?, withdraw_reason = ?
+		WHERE id = ? AND status NOT IN (?, ?)`,
+		StatusWithdrawn, at.UTC(), strings.TrimSpace(reason), id, StatusRejected, StatusWithdrawn)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return err
+	}
+	a, err := s.ByID(id)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("application %d is %s: %w", id, a.Status, ErrNotWithdrawable)
+}
+
+// ParseStatuses reads a comma-separated status list such as
+// "interview,applied". "sent" is accepted for applied.
+func ParseStatuses(list string) ([]Status, error) {
+	var out []Status
+	for _, f := range strings.Split(list, ",") {
+		switch st := Status(strings.ToLower(strings.TrimSpace(f))); st {
+		case "":
+		case "sent":
+			out = append(out, StatusApplied)
+		case StatusApplied, StatusReplied, StatusInterview, StatusOffer, StatusRejected, StatusWithdrawn:
+			out = append(out, st)
+		default:
+			return nil, fmt.Errorf("unknown status %q", f)
+		}
+	}
+	return out, nil
+}
+
+// Selection picks the applications a bulk withdrawal covers.
+type Selection struct {
+	// Statuses limits the selection; empty means every withdrawable one.
+	Statuses []Status
+	// Except names companies to keep, such as the one whose offer was
+	// accepted. Names are compared with job.NormalizeCompany.
+	Except []string
+}
+
+// Apply returns the withdrawable applications in apps that the selection
+// covers, in their original order.
+func (sel Selection) Apply(apps []Application) []Application {
+	except := make(map[string]bool, len(sel.Except))
+	for _, c := range sel.Except {
+		if key := job.NormalizeCompany(c); key != "" {
+			except[key] = true
+		}
+	}
+	var out []Application
+	for _, a := range apps {
+		if !a.Status.Withdrawable() || except[job.NormalizeCompany(a.Company)] {
+			continue
+		}
+		if len(sel.Statuses) > 0 && !slices.Contains(sel.Statuses, a.Status) {
+			continue
+		}
+		out = append(out, a)
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+package application
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStore_Withdraw(t *testing.T) {
+	s := openTestStore(t)
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	open := &Application{JobID: "j1", Company: "Acme", Status: StatusInterview, AppliedAt: at}
+	closed := &Application{JobID: "j2", Company: "Globex", Status: StatusRejected, AppliedAt: at}
+	for _, a := range []*Application{open, closed} {
+		if err := s.Add(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := s.Withdraw(open.ID, " accepted another offer ", at); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.ByID(open.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != StatusWithdrawn || got.WithdrawnAt == nil || !got.WithdrawnAt.Equal(at) || got.WithdrawReason != "accepted another offer" {
+		t.Errorf("withdrawn = %s at %v (%q)", got.Status, got.WithdrawnAt, got.WithdrawReason)
+	}
+
+	for _, id := range []int64{open.ID, closed.ID} {
+		if err := s.Withdraw(id, "", at); !errors.Is(err, ErrNotWithdrawable) {
+			t.Errorf("Withdraw(%d) = %v, want ErrNotWithdrawable", id, err)
+		}
+	}
+	if got, _ := s.ByID(closed.ID); got.Status != StatusRejected || got.WithdrawnAt != nil {
+		t.Errorf("rejected application changed: %s at %v", got.Status, got.WithdrawnAt)
+	}
+}
+
+func TestParseStatuses(t *testing.T) {
+	got, err := ParseStatuses("interview, Sent,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Status{StatusInterview, StatusApplied}; !slices.Equal(got, want) {
+		t.Errorf("ParseStatuses = %v, want %v", got, want)
+	}
+	if _, err := ParseStatuses("interview,ghosted"); err == nil {
+		t.Error("unknown status accepted")
+	}
+}
+
+func TestSelection_Apply(t *testing.T) {
+	apps := []Application{
+		{ID: 1, Company: "Acme Inc.", Status: StatusInterview},
+		{ID: 2, Company: "Globex", Status: StatusApplied},
+		{ID: 3, Company: "Initech", Status: StatusOffer},
+		{ID: 4, Company: "Umbrella", Status: StatusRejected},
+		{ID: 5, Company: "Hooli", Status: StatusWithdrawn},
+	}
+	ids := func(as []Application) []int64 {
+		var out []int64
+		for _, a := range as {
+			out = append(out, a.ID)
+		}
+		return out
+	}
+
+	if got := ids(Selection{}.Apply(apps)); !slices.Equal(got, []int64{1, 2, 3}) {
+		t.Errorf("empty selection = %v, want the open applications", got)
+	}
+	sel := Selection{Statuses: []Status{StatusInterview, StatusApplied, StatusRejected}, Except: []string{"acme"}}
+	if got := ids(sel.Apply(apps)); !slices.Equal(got, []int64{2}) {
+		t.Errorf("selection = %v, want [2]", got)
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"sprayer/src/api/application"
+)
+
+// EnableApplications serves each job's application from apps. Without it,
+// or EnableTracking, /jobs/{id}/application answers 404.
+func (h *Handler) EnableApplications(apps *application.Store) {
+	h.appStore = apps
+}
+
+// JobApplication serves /jobs/{id}/application: the latest application
+// to the job with what was sent, the subject, body and files attached.
+// A job never applied to is a 404.
+func (h *Handler) JobApplication(w http.ResponseWriter, r *http.Request, jobID string) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if h.appStore == nil || jobID == "" || strings.Contains(jobID, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	a, err := h.appStore.ForJob(jobID)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no application to job %q", jobID))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(a)
+}
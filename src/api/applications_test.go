@@ -0,0 +1,56 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sprayer/src/api/application"
+)
+
+func TestJobApplication_ServesWhatWasSent(t *testing.T) {
+	h := newReadyHandler(t, filepath.Join(t.TempDir(), "jobs.db"))
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.Job(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+	if rec := get("/jobs/job-1/application"); rec.Code != http.StatusNotFound {
+		t.Errorf("without applications: got %d, want 404", rec.Code)
+	}
+
+	apps, err := application.NewStore(h.store.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.EnableApplications(apps)
+	sentAt := time.Date(2024, 5, 6, 9, 30, 0, 0, time.UTC)
+	for _, a := range []*application.Application{
+		{JobID: "job-1", Company: "Acme", Method: application.MethodPortal, AppliedAt: sentAt.Add(-time.Hour)},
+		{JobID: "job-1", Company: "Acme", Method: application.MethodEmail, AppliedAt: sentAt, Sent: &application.Sent{
+			From: "jane+acme@example.com", To: "jobs@acme.com", Cc: []string{"cto@acme.com"},
+			Subject: "Go Engineer", Body: "Dear Acme,", CVPath: "/cv/acme.pdf", CoverLetterPath: "/cover.md", SentAt: sentAt,
+		}},
+	} {
+		if err := apps.Add(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := get("/jobs/job-1/application")
+	var got application.Application
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("got %d, %v", rec.Code, err)
+	}
+	s := got.Sent
+	if got.Method != application.MethodEmail || s == nil || s.From != "jane+acme@example.com" || s.Cc[0] != "cto@acme.com" ||
+		s.Body != "Dear Acme," || s.CVPath != "/cv/acme.pdf" || s.CoverLetterPath != "/cover.md" || !s.SentAt.Equal(sentAt) {
+		t.Errorf("application = %+v, sent %+v", got, s)
+	}
+	if rec := get("/jobs/job-2/application"); rec.Code != http.StatusNotFound {
+		t.Errorf("job never applied to: got %d, want 404", rec.Code)
+	}
+}
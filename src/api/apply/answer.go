@@ -0,0 +1,61 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/llm"
+	"sprayer/src/api/profile"
+)
+
+// DraftAnswer asks the LLM to draft an answer to an employer's question
+// from the profile's CV. The draft is meant to be edited before use.
+func DraftAnswer(j job.Job, p profile.Profile, client *llm.Client, question string) (string, error) {
+	return DraftAnswerContext(context.Background(), j, p, client, question)
+}
+
+// DraftAnswerContext is DraftAnswer, abandoning the LLM request if ctx is
+// cancelled.
+func DraftAnswerContext(ctx context.Context, j job.Job, p profile.Profile, client *llm.Client, question string) (string, error) {
+	if client == nil {
+		return "", fmt.Errorf("LLM not configured: set %s", llm.EnvLLMKey)
+	}
+	if err := client.Check(ctx); err != nil {
+		return "", err
+	}
+	cv := p.CVData
+	if cv == nil {
+		cv = &profile.CVData{Name: p.Name, Technologies: p.Keywords}
+	}
+
+	vars := map[string]string{
+		"job_title":      j.Title,
+		"company":        j.Company,
+		"question":       question,
+		"applicant_name": firstNonBlank(cv.Name, p.Name),
+		"summary":        cv.Summary,
+		"skills":         strings.Join(append(append([]string{}, cv.Skills...), cv.Technologies...), ", "),
+		"experience":     formatExperience(cv.Experience),
+	}
+	prompt, err := llm.LoadPrompt("answer_question", vars)
+	if err != nil {
+		return "", fmt.Errorf("load prompt: %w", err)
+	}
+
+	answer, err := client.CompleteContext(llm.For(ctx, llm.PurposeAnswer, j.ID), "You are a concise, honest writing assistant for job applications.", prompt)
+	if err != nil {
+		return "", fmt.Errorf("LLM generation: %w", err)
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+func firstNonBlank(vals ...string) string {
+	for _, v := range vals {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package apply
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/llm"
+	"sprayer/src/api/profile"
+)
+
+func TestRenderTemplate_IncludesAnswers(t *testing.T) {
+	p := profile.NewDefaultProfile()
+	p.Name = "Ada Lovelace"
+	answers := []application.Question{
+		{Text: "Describe a time you led an incident.", Answer: "I ran the response to our 2023 outage."},
+		{Text: "Unanswered question here?"},
+	}
+	for _, name := range []string{"email", "cover_letter"} {
+		_, body, err := RenderTemplate(name, testJob(), p, answers...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(body, "Describe a time you led an incident.\nI ran the response to our 2023 outage.") {
+			t.Errorf("%s: answer missing:\n%s", name, body)
+		}
+		if strings.Contains(body, "Unanswered") {
+			t.Errorf("%s: unanswered question rendered:\n%s", name, body)
+		}
+	}
+
+	// Without answers the output is unchanged.
+	_, plain, _ := RenderTemplate("email", testJob(), p)
+	if strings.Contains(plain, "\n\n\n") {
+		t.Errorf("blank lines left by the empty answers block:\n%s", plain)
+	}
+}
+
+func TestDraftAnswer(t *testing.T) {
+	var prompt string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/models" {
+			w.Write([]byte(`{"data":[]}`))
+			return
+		}
+		var req struct {
+			Messages []struct{ Content string } `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		prompt = req.Messages[len(req.Messages)-1].Content
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"  I led the payments outage response.  "}}]}`))
+	}))
+	defer srv.Close()
+	t.Setenv(llm.EnvLLMURL, srv.URL)
+	t.Setenv(llm.EnvLLMKey, "test")
+
+	p := profile.NewDefaultProfile()
+	p.CVData = &profile.CVData{
+		Name:       "Grace Hopper",
+		Experience: []profile.Experience{{Title: "SRE", Company: "Navy", Duration: "2019-2024", Description: "Ran incident response"}},
+	}
+	got, err := DraftAnswer(testJob(), p, llm.NewClient(), "Describe a time you led an incident.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "I led the payments outage response." {
+		t.Errorf("answer = %q", got)
+	}
+	for _, want := range []string{"Question: Describe a time you led an incident.", "Grace Hopper", "SRE at Navy", "Senior Go Engineer at Acme"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+
+	t.Setenv(llm.EnvLLMKey, "")
+	if _, err := DraftAnswer(testJob(), p, llm.NewClient(), "Why us?"); err == nil {
+		t.Error("DraftAnswer without an LLM succeeded")
+	}
+}
@@ -0,0 +1,165 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+// CVState is the outcome of resolving which CV, if any, goes out with an
+// application.
+type CVState int
+
+const (
+	CVNone        CVState = iota // profile has no CV path
+	CVTailored                   // job-specific PDF built from the profile CV
+	CVOriginal                   // the profile's own PDF
+	CVStale                      // PDF exists but its .tex was edited since
+	CVMissing                    // configured path does not exist
+	CVBuildFailed                // last pdflatex run on the .tex failed
+	CVNoCompiler                 // only a .tex, and pdflatex is not installed
+	CVUnbuilt                    // only a .tex, never compiled
+)
+
+// Severity grades a CVState for display.
+type Severity int
+
+const (
+	SeverityOK Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+// CVStatus describes the attachment an application would send.
+type CVStatus struct {
+	State CVState
+	Path  string    // PDF to attach; empty when nothing will be attached
+	Built time.Time // modification time of Path
+}
+
+// lookPath finds pdflatex; replaced in tests.
+var lookPath = exec.LookPath
+
+// TailoredCVPath is where a job-specific CV for jobID is kept, next to the
+// profile's CV.
+func TailoredCVPath(p profile.Profile, jobID string) string {
+	return filepath.Join(filepath.Dir(p.CVPath), "tailored", sanitize(jobID)+".pdf")
+}
+
+// ResolveJobCV is ResolveCV for j, preferring the tailored CV linked
+// from the job record when its PDF exists.
+func ResolveJobCV(p profile.Profile, j job.Job) CVStatus {
+	if j.CVPath != "" {
+		if info, err := os.Stat(j.CVPath); err == nil {
+			return CVStatus{State: CVTailored, Path: j.CVPath, Built: info.ModTime()}
+		}
+	}
+	return ResolveCV(p, j.ID)
+}
+
+// ResolveCV works out which CV an application to jobID would attach. The
+// profile's CV path may name either the .tex source or the PDF.
+func ResolveCV(p profile.Profile, jobID string) CVStatus {
+	if p.CVPath == "" {
+		return CVStatus{State: CVNone}
+	}
+	if jobID != "" {
+		if info, err := os.Stat(TailoredCVPath(p, jobID)); err == nil {
+			return CVStatus{State: CVTailored, Path: TailoredCVPath(p, jobID), Built: info.ModTime()}
+		}
+	}
+
+	base := strings.TrimSuffix(p.CVPath, filepath.Ext(p.CVPath))
+	pdf, tex := base+".pdf", base+".tex"
+	pdfInfo, pdfErr := os.Stat(pdf)
+	texInfo, texErr := os.Stat(tex)
+
+	switch {
+	case texErr == nil && buildFailed(base+".log", pdfInfo):
+		return CVStatus{State: CVBuildFailed}
+	case pdfErr == nil && texErr == nil && texInfo.ModTime().After(pdfInfo.ModTime()):
+		return CVStatus{State: CVStale, Path: pdf, Built: pdfInfo.ModTime()}
+	case pdfErr == nil:
+		return CVStatus{State: CVOriginal, Path: pdf, Built: pdfInfo.ModTime()}
+	case texErr != nil:
+		return CVStatus{State: CVMissing}
+	}
+	if _, err := lookPath("pdflatex"); err != nil {
+		return CVStatus{State: CVNoCompiler}
+	}
+	return CVStatus{State: CVUnbuilt}
+}
+
+// buildFailed reports whether the pdflatex log records a fatal error from
+// a run newer than the current PDF (if any).
+func buildFailed(logPath string, pdf os.FileInfo) bool {
+	info, err := os.Stat(logPath)
+	if err != nil || (pdf != nil && !info.ModTime().After(pdf.ModTime())) {
+		return false
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(data), "Fatal error occurred") ||
+		strings.Contains(string(data), "no output PDF file produced")
+}
+
+// Severity grades the status. Errors mean the application would go out
+// without the CV the user expects.
+func (s CVStatus) Severity() Severity {
+	switch s.State {
+	case CVTailored, CVOriginal:
+		return SeverityOK
+	case CVNone, CVStale:
+		return SeverityWarning
+	default:
+		return SeverityError
+	}
+}
+
+// Blocking reports whether sending directly should require an override.
+func (s CVStatus) Blocking() bool { return s.Severity() == SeverityError }
+
+// Label renders the status for the apply confirmation, relative to now.
+func (s CVStatus) Label(now time.Time) string {
+	switch s.State {
+	case CVNone:
+		return "CV: none configured"
+	case CVTailored:
+		return fmt.Sprintf("CV: tailored PDF (built %s)", ago(now.Sub(s.Built)))
+	case CVOriginal:
+		return fmt.Sprintf("CV: original PDF (%s version)", s.Built.Format("2006-01"))
+	case CVStale:
+		return fmt.Sprintf("CV: original PDF (%s version), older than its .tex", s.Built.Format("2006-01"))
+	case CVMissing:
+		return "CV: configured file not found — will send without attachment"
+	case CVBuildFailed:
+		return "CV: .tex failed to compile last time — will send without attachment"
+	case CVNoCompiler:
+		return "CV: .tex present, pdflatex missing — will send without attachment"
+	case CVUnbuilt:
+		return "CV: .tex present, PDF not built — will send without attachment"
+	}
+	return "CV: unknown"
+}
+
+// ago formats d coarsely, e.g. "3m ago".
+func ago(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,130 @@
+package apply
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+// cvFixture lays out CV files in a temp dir. Files are written in the
+// order given, each a minute after the previous, so staleness is explicit.
+func cvFixture(t *testing.T, files ...string) profile.Profile {
+	t.Helper()
+	dir := t.TempDir()
+	at := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	for _, name := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		content := "%PDF"
+		if strings.HasSuffix(name, ".log") {
+			content = "! Emergency stop.\n!  ==> Fatal error occurred, no output PDF file produced!\n"
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, at, at); err != nil {
+			t.Fatal(err)
+		}
+		at = at.Add(time.Minute)
+	}
+	return profile.Profile{CVPath: filepath.Join(dir, "cv.tex")}
+}
+
+func withPdflatex(t *testing.T, installed bool) {
+	t.Helper()
+	old := lookPath
+	lookPath = func(string) (string, error) {
+		if installed {
+			return "/usr/bin/pdflatex", nil
+		}
+		return "", errors.New("not found")
+	}
+	t.Cleanup(func() { lookPath = old })
+}
+
+func TestResolveCV_States(t *testing.T) {
+	withPdflatex(t, true)
+
+	tests := []struct {
+		name     string
+		p        profile.Profile
+		state    CVState
+		severity Severity
+		attach   bool
+	}{
+		{"none", profile.Profile{}, CVNone, SeverityWarning, false},
+		{"tailored", cvFixture(t, "cv.tex", "cv.pdf", "tailored/job-1.pdf"), CVTailored, SeverityOK, true},
+		{"original", cvFixture(t, "cv.tex", "cv.pdf"), CVOriginal, SeverityOK, true},
+		{"pdf only", cvFixture(t, "cv.pdf"), CVOriginal, SeverityOK, true},
+		{"stale", cvFixture(t, "cv.pdf", "cv.tex"), CVStale, SeverityWarning, true},
+		{"missing", cvFixture(t), CVMissing, SeverityError, false},
+		{"build failed", cvFixture(t, "cv.tex", "cv.pdf", "cv.log"), CVBuildFailed, SeverityError, false},
+		{"old failure", cvFixture(t, "cv.tex", "cv.log", "cv.pdf"), CVOriginal, SeverityOK, true},
+		{"unbuilt", cvFixture(t, "cv.tex"), CVUnbuilt, SeverityError, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := ResolveCV(tt.p, "job-1")
+			if s.State != tt.state {
+				t.Fatalf("state = %v, want %v", s.State, tt.state)
+			}
+			if s.Severity() != tt.severity || s.Blocking() != (tt.severity == SeverityError) {
+				t.Errorf("severity = %v, blocking = %v", s.Severity(), s.Blocking())
+			}
+			if (s.Path != "") != tt.attach {
+				t.Errorf("attachment path = %q", s.Path)
+			}
+		})
+	}
+}
+
+func TestResolveCV_NoCompiler(t *testing.T) {
+	withPdflatex(t, false)
+	s := ResolveCV(cvFixture(t, "cv.tex"), "job-1")
+	if s.State != CVNoCompiler || !s.Blocking() {
+		t.Fatalf("got %+v", s)
+	}
+	if got := s.Label(time.Now()); got != "CV: .tex present, pdflatex missing — will send without attachment" {
+		t.Errorf("Label = %q", got)
+	}
+}
+
+func TestCVStatus_Label(t *testing.T) {
+	built := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		s    CVStatus
+		want string
+	}{
+		{CVStatus{State: CVTailored, Built: built}, "CV: tailored PDF (built 3m ago)"},
+		{CVStatus{State: CVOriginal, Built: built}, "CV: original PDF (2024-01 version)"},
+		{CVStatus{State: CVNone}, "CV: none configured"},
+	}
+	for _, tt := range tests {
+		if got := tt.s.Label(built.Add(3 * time.Minute)); got != tt.want {
+			t.Errorf("Label = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestResolveJobCV(t *testing.T) {
+	withPdflatex(t, true)
+	p := cvFixture(t, "cv.pdf")
+	linked := filepath.Join(t.TempDir(), "cv.pdf")
+	if err := os.WriteFile(linked, []byte("%PDF"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s := ResolveJobCV(p, job.Job{ID: "a", CVPath: linked}); s.State != CVTailored || s.Path != linked {
+		t.Errorf("linked CV: %+v", s)
+	}
+	// A link to a PDF since removed falls back to the profile's CV.
+	gone := job.Job{ID: "a", CVPath: filepath.Join(t.TempDir(), "gone.pdf")}
+	if s := ResolveJobCV(p, gone); s.State != CVOriginal {
+		t.Errorf("missing link: %+v", s)
+	}
+}
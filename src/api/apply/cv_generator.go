@@ -1,6 +1,7 @@
 package apply
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -10,8 +11,16 @@ import (
 	"sprayer/src/api/job"
 	"sprayer/src/api/llm"
 	"sprayer/src/api/profile"
+	"sprayer/src/api/redact"
 )
 
+// contactPlaceholders stand in for contact details in prompts when
+// redaction is on; the model only needs to know where they go.
+var contactPlaceholders = map[string]string{
+	"applicant_email": "[EMAIL]",
+	"applicant_phone": "[PHONE]",
+}
+
 type CVGenerator struct {
 	client *llm.Client
 	cache  map[string]*CachedCV
@@ -31,11 +40,16 @@ func NewCVGenerator(client *llm.Client) *CVGenerator {
 	}
 }
 
+// GenerateCustomCV has the LLM write a CV tailored to j. Without one
+// the profile's LaTeX CV template is filled in instead, and not cached.
 func (g *CVGenerator) GenerateCustomCV(j *job.Job, p *profile.Profile) (string, error) {
+	return g.GenerateCustomCVContext(context.Background(), j, p)
+}
+
+// GenerateCustomCVContext is GenerateCustomCV, abandoning the LLM request
+// if ctx is cancelled.
+func (g *CVGenerator) GenerateCustomCVContext(ctx context.Context, j *job.Job, p *profile.Profile) (string, error) {
 	cacheKey := j.ID
-	if g.client == nil {
-		return "", fmt.Errorf("LLM client not available")
-	}
 
 	g.mu.RLock()
 	if cached, ok := g.cache[cacheKey]; ok {
@@ -59,6 +73,9 @@ func (g *CVGenerator) GenerateCustomCV(j *job.Job, p *profile.Profile) (string,
 	if cvData == nil {
 		return "", fmt.Errorf("no CV data available for profile")
 	}
+	if !g.Available() {
+		return GenerateLatexCV(*j, *cvData, p.CVTemplate)
+	}
 
 	vars := map[string]string{
 		"job_title":       j.Title,
@@ -76,18 +93,24 @@ func (g *CVGenerator) GenerateCustomCV(j *job.Job, p *profile.Profile) (string,
 		"education":       formatEducation(cvData.Education),
 	}
 
+	restore := func(s string) string { return s }
+	if redact.PromptsEnabled() {
+		restore = redact.MaskVars(vars, contactPlaceholders)
+	}
+
 	prompt, err := llm.LoadPrompt("cv_custom", vars)
 	if err != nil {
 		return "", fmt.Errorf("load prompt: %w", err)
 	}
 
-	cvContent, err := g.client.Complete(
+	cvContent, err := g.client.CompleteContext(llm.For(ctx, llm.PurposeCVLatex, j.ID),
 		"You are an expert CV/resume writer. Generate a tailored, professional CV that highlights relevant experience for the specific job. Be concise and impactful.",
 		prompt,
 	)
 	if err != nil {
 		return "", fmt.Errorf("LLM generation: %w", err)
 	}
+	cvContent = restore(cvContent)
 
 	g.mu.Lock()
 	g.cache[cacheKey] = &CachedCV{
@@ -0,0 +1,158 @@
+package apply
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"sprayer/src/api/export"
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+//go:embed templates/cv/*.tex
+var cvTemplateFS embed.FS
+
+// DefaultCVTemplate is the CV layout used when a profile names none.
+const DefaultCVTemplate = "compact"
+
+// LatexCV is the data a CV template renders. Every string is already
+// escaped for LaTeX, so templates insert them as they are.
+type LatexCV struct {
+	Name, Title, Summary string
+	Email, Phone         string
+	Location             string
+	Contact              []string // email, phone and location, those set
+	Skills               []string // technologies and skills, the posting's first
+	Experience           []LatexExperience
+	Education            []LatexEducation
+	Languages            []string
+	Job                  LatexJob // the posting the CV is for
+}
+
+// LatexExperience is a role in a LatexCV.
+type LatexExperience struct {
+	Title, Company, Duration, Description string
+	Technologies                          []string // the posting's first
+}
+
+// LatexEducation is a degree in a LatexCV; Degree includes the field.
+type LatexEducation struct {
+	Degree, Institution, Year string
+}
+
+// LatexJob is the posting a LatexCV is tailored to.
+type LatexJob struct {
+	Title, Company string
+}
+
+// NewLatexCV escapes cv for a template, ordering technologies and skills
+// the posting j mentions first.
+func NewLatexCV(j job.Job, cv profile.CVData) LatexCV {
+	text := strings.ToLower(j.Title + " " + j.Description)
+	esc := export.EscapeLaTeX
+	l := LatexCV{
+		Name: esc(cv.Name), Title: esc(cv.Title), Summary: esc(cv.Summary),
+		Email: esc(cv.Email), Phone: esc(cv.Phone), Location: esc(cv.Location),
+		Skills:    escapeAll(relevantFirst(text, append(append([]string{}, cv.Technologies...), cv.Skills...))),
+		Languages: escapeAll(cv.Languages),
+		Job:       LatexJob{Title: esc(j.Title), Company: esc(j.Company)},
+	}
+	for _, c := range []string{l.Email, l.Phone, l.Location} {
+		if c != "" {
+			l.Contact = append(l.Contact, c)
+		}
+	}
+	for _, e := range cv.Experience {
+		l.Experience = append(l.Experience, LatexExperience{
+			Title: esc(e.Title), Company: esc(e.Company), Duration: esc(e.Duration),
+			Description:  esc(e.Description),
+			Technologies: escapeAll(relevantFirst(text, e.Technologies)),
+		})
+	}
+	for _, e := range cv.Education {
+		l.Education = append(l.Education, LatexEducation{
+			Degree:      esc(strings.TrimSpace(e.Degree + " " + e.Field)),
+			Institution: esc(e.Institution), Year: esc(e.Year),
+		})
+	}
+	return l
+}
+
+func escapeAll(ss []string) []string {
+	out := make([]string, len(ss))
+	for i, s := range ss {
+		out[i] = export.EscapeLaTeX(s)
+	}
+	return out
+}
+
+// CVTemplateDir holds the user's own CV templates, name.tex each, in
+// TemplateDir.
+func CVTemplateDir() string {
+	return TemplateDir()
+}
+
+// CVTemplates lists the CV template names: the built-ins and those in
+// CVTemplateDir.
+func CVTemplates() []string {
+	var names []string
+	builtins, _ := cvTemplateFS.ReadDir("templates/cv")
+	user, _ := os.ReadDir(CVTemplateDir())
+	for _, e := range append(builtins, user...) {
+		if name, ok := strings.CutSuffix(e.Name(), ".tex"); ok && !e.IsDir() && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+var cvTemplateFuncs = template.FuncMap{"join": strings.Join}
+
+// loadCVTemplate parses the CV template name: a path to a template file,
+// a name.tex in CVTemplateDir, which may replace a built-in, or a
+// built-in. Empty is DefaultCVTemplate.
+func loadCVTemplate(name string) (*template.Template, error) {
+	if name == "" {
+		name = DefaultCVTemplate
+	}
+	base := strings.TrimSuffix(filepath.Base(name), ".tex")
+	var src []byte
+	var err error
+	if strings.ContainsRune(name, filepath.Separator) {
+		src, err = os.ReadFile(name)
+	} else if src, err = os.ReadFile(filepath.Join(CVTemplateDir(), base+".tex")); os.IsNotExist(err) {
+		src, err = cvTemplateFS.ReadFile("templates/cv/" + base + ".tex")
+		if err != nil {
+			return nil, fmt.Errorf("unknown CV template %q (have %s)", name, strings.Join(CVTemplates(), ", "))
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read CV template: %w", err)
+	}
+	t, err := template.New(base).Funcs(cvTemplateFuncs).Parse(string(src))
+	if err != nil {
+		return nil, fmt.Errorf("CV template %s: %w", base, err)
+	}
+	return t, nil
+}
+
+// GenerateLatexCV renders cv as a LaTeX CV for j with the named
+// template; see loadCVTemplate.
+func GenerateLatexCV(j job.Job, cv profile.CVData, tmpl string) (string, error) {
+	t, err := loadCVTemplate(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, NewLatexCV(j, cv)); err != nil {
+		return "", fmt.Errorf("render CV template %s: %w", t.Name(), err)
+	}
+	return buf.String(), nil
+}
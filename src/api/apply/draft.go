@@ -14,9 +14,9 @@ import (
 
 // Draft generates a Maildir-format email draft file for mu4e.
 func Draft(j job.Job, p profile.Profile, subject, body string) (string, error) {
-	maildirPath := filepath.Join(os.Getenv("HOME"), "Maildir", "drafts", "new")
-	if err := os.MkdirAll(maildirPath, 0755); err != nil {
-		return "", fmt.Errorf("create drafts dir: %w", err)
+	maildirPath, err := draftsDir()
+	if err != nil {
+		return "", err
 	}
 
 	// Determine recipient
@@ -30,8 +30,7 @@ func Draft(j job.Job, p profile.Profile, subject, body string) (string, error) {
 
 	// Try to attach CV PDF
 	var attachmentPart string
-	cvPDF := findPDF(p.CVPath)
-	if cvPDF != "" {
+	if cvPDF := ResolveJobCV(p, j).Path; cvPDF != "" {
 		pdfData, err := os.ReadFile(cvPDF)
 		if err == nil {
 			encoded := base64.StdEncoding.EncodeToString(pdfData)
@@ -49,6 +48,9 @@ Content-Transfer-Encoding: base64
 	var msg strings.Builder
 	msg.WriteString(fmt.Sprintf("From: %s\n", p.ContactEmail))
 	msg.WriteString(fmt.Sprintf("To: %s\n", to))
+	if len(j.Cc) > 0 {
+		msg.WriteString(fmt.Sprintf("Cc: %s\n", strings.Join(j.Cc, ", ")))
+	}
 	msg.WriteString(fmt.Sprintf("Subject: %s\n", subject))
 	msg.WriteString(fmt.Sprintf("Date: %s\n", time.Now().Format(time.RFC1123Z)))
 	msg.WriteString("MIME-Version: 1.0\n")
@@ -76,17 +78,14 @@ Content-Transfer-Encoding: base64
 
 const boundary = "sprayer-boundary"
 
-// findPDF looks for a .pdf file alongside or derived from the given tex path.
-func findPDF(texPath string) string {
-	if texPath == "" {
-		return ""
-	}
-	// Try the same name with .pdf extension
-	pdf := strings.TrimSuffix(texPath, filepath.Ext(texPath)) + ".pdf"
-	if _, err := os.Stat(pdf); err == nil {
-		return pdf
+// draftsDir returns (creating it if needed) the Maildir drafts folder
+// that mu4e picks up.
+func draftsDir() (string, error) {
+	dir := filepath.Join(os.Getenv("HOME"), "Maildir", "drafts", "new")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create drafts dir: %w", err)
 	}
-	return ""
+	return dir, nil
 }
 
 func sanitize(s string) string {
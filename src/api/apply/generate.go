@@ -1,9 +1,11 @@
 package apply
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
+	"sprayer/src/api/application"
 	"sprayer/src/api/job"
 	"sprayer/src/api/llm"
 	"sprayer/src/api/parse"
@@ -11,14 +13,107 @@ import (
 )
 
 // GenerateEmail uses syntactic parsing + LLM to produce a personalized application email.
-// Returns subject and body.
-func GenerateEmail(j job.Job, p profile.Profile, client *llm.Client, promptName string) (string, string, error) {
-	// 1. Extract context via syntactic parsing
-	email := j.Email
-	if email == "" {
-		email = parse.ExtractFirstEmail(j.Description)
+// Returns subject and body. Answers to the job's questions are worked in.
+// Without a configured LLM the matching built-in template is rendered
+// instead.
+func GenerateEmail(j job.Job, p profile.Profile, client *llm.Client, promptName string, answers ...application.Question) (string, string, error) {
+	return GenerateEmailContext(context.Background(), j, p, client, promptName, answers...)
+}
+
+// GenerateEmailContext is GenerateEmail, abandoning the LLM request if
+// ctx is cancelled.
+func GenerateEmailContext(ctx context.Context, j job.Job, p profile.Profile, client *llm.Client, promptName string, answers ...application.Question) (string, string, error) {
+	ok, err := useLLM(ctx, client)
+	if err != nil {
+		return "", "", err
+	}
+	if !ok {
+		return RenderTemplate(TemplateFor(promptName), j, p, answers...)
+	}
+	prompt, err := emailPrompt(j, p, promptName, answers)
+	if err != nil {
+		return "", "", err
 	}
 
+	// 4. Generate via LLM
+	body, err := client.CompleteContext(llm.For(ctx, emailPurpose(promptName), j.ID), emailSystem, prompt)
+	if err != nil {
+		return "", "", fmt.Errorf("LLM generation: %w", err)
+	}
+	return emailSubject(j, p), body, nil
+}
+
+// StreamEmail is GenerateEmail sending the body on chunks as the LLM
+// writes it, for providers that stream; others send it whole once done.
+// The subject and body returned are what GenerateEmail would return.
+// Cancelling ctx abandons the request. chunks is not closed.
+func StreamEmail(ctx context.Context, j job.Job, p profile.Profile, client *llm.Client, promptName string, chunks chan<- string, answers ...application.Question) (string, string, error) {
+	ok, err := useLLM(ctx, client)
+	if err != nil {
+		return "", "", err
+	}
+	if !ok {
+		subject, body, err := RenderTemplate(TemplateFor(promptName), j, p, answers...)
+		if err == nil {
+			select {
+			case chunks <- body:
+			case <-ctx.Done():
+				return "", "", ctx.Err()
+			}
+		}
+		return subject, body, err
+	}
+	prompt, err := emailPrompt(j, p, promptName, answers)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := client.CompleteStream(llm.For(ctx, emailPurpose(promptName), j.ID), emailSystem, prompt, chunks)
+	if err != nil {
+		return "", "", fmt.Errorf("LLM generation: %w", err)
+	}
+	return emailSubject(j, p), body, nil
+}
+
+// useLLM reports whether client is configured and healthy enough to
+// write with; if ctx was cancelled while asking, that is the error.
+func useLLM(ctx context.Context, client *llm.Client) (bool, error) {
+	if client == nil {
+		return false, nil
+	}
+	if client.Check(ctx) == nil {
+		return true, nil
+	}
+	return false, ctx.Err()
+}
+
+// emailSystem is the system prompt application emails are written under.
+const emailSystem = "You are a professional job application assistant. Be concise and natural."
+
+// emailPurpose is what the usage log files a completion from promptName
+// under.
+func emailPurpose(promptName string) string {
+	if promptName == "cover_letter" {
+		return llm.PurposeCoverLetter
+	}
+	return llm.PurposeEmail
+}
+
+func emailSubject(j job.Job, p profile.Profile) string {
+	return fmt.Sprintf("Application for %s — %s", j.Title, p.Name)
+}
+
+// emailPrompt fills in the named prompt for j and p.
+func emailPrompt(j job.Job, p profile.Profile, promptName string, answers []application.Question) (string, error) {
+	prompt, err := llm.LoadPrompt(promptName, PromptVars(j, p, answers))
+	if err != nil {
+		return "", fmt.Errorf("load prompt %q: %w", promptName, err)
+	}
+	return prompt, nil
+}
+
+// PromptVars are the variables an email prompt is filled in with, which
+// templates also see as .Vars.
+func PromptVars(j job.Job, p profile.Profile, answers []application.Question) map[string]string {
 	location := j.Location
 	if location == "" {
 		locs := parse.ExtractLocations(j.Description)
@@ -27,35 +122,33 @@ func GenerateEmail(j job.Job, p profile.Profile, client *llm.Client, promptName
 		}
 	}
 
-	// 2. Build prompt variables
 	vars := map[string]string{
 		"job_title":       j.Title,
 		"company":         j.Company,
 		"location":        location,
 		"applicant_name":  p.Name,
 		"skills":          strings.Join(p.Keywords, ", "),
+		"matched_tech":    strings.Join(matchedTech(j, p), ", "),
+		"experience":      "",
 		"job_description": truncate(parse.Sanitize(j.Description), 2000),
 		"applied_date":    j.AppliedDate.Format("2006-01-02"),
+		"answers":         formatAnswers(answers),
 	}
-
-	// 3. Load and interpolate prompt
-	prompt, err := llm.LoadPrompt(promptName, vars)
-	if err != nil {
-		return "", "", fmt.Errorf("load prompt %q: %w", promptName, err)
+	if cv := p.CVData; cv != nil {
+		vars["experience"] = formatExperience(cv.Experience)
 	}
+	return vars
+}
 
-	// 4. Generate via LLM
-	body, err := client.Complete(
-		"You are a professional job application assistant. Be concise and natural.",
-		prompt,
-	)
-	if err != nil {
-		return "", "", fmt.Errorf("LLM generation: %w", err)
+// matchedTech is the profile's keywords, technologies and skills the
+// posting mentions.
+func matchedTech(j job.Job, p profile.Profile) []string {
+	terms := append([]string{}, p.Keywords...)
+	if cv := p.CVData; cv != nil {
+		terms = append(append(terms, cv.Technologies...), cv.Skills...)
 	}
-
-	subject := fmt.Sprintf("Application for %s — %s", j.Title, p.Name)
-
-	return subject, body, nil
+	hit, _ := mentioned(strings.ToLower(j.Title+" "+j.Description), terms)
+	return hit
 }
 
 func truncate(s string, max int) string {
@@ -0,0 +1,238 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/job"
+	"sprayer/src/api/llm"
+	"sprayer/src/api/profile"
+)
+
+func testJob() job.Job {
+	return job.Job{
+		ID:       "ashby-acme-1",
+		Title:    "Senior Go Engineer",
+		Company:  "Acme",
+		Location: "Remote",
+		Email:    "jobs@acme.dev",
+	}
+}
+
+func TestApplyFlow_WithoutLLM(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(llm.EnvLLMKey, "")
+
+	p := profile.NewDefaultProfile()
+	p.Name = "Ada Lovelace"
+	p.ContactEmail = "ada@example.com"
+
+	client := llm.NewClient()
+	subject, body, err := GenerateEmail(testJob(), p, client, "email_cold")
+	if err != nil {
+		t.Fatalf("GenerateEmail without LLM: %v", err)
+	}
+	if subject != "Application for Senior Go Engineer — Ada Lovelace" {
+		t.Errorf("subject = %q", subject)
+	}
+	for _, want := range []string{"Hi Acme team", "(Remote)", "golang, rust and remote", "Ada Lovelace\nada@example.com"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+
+	path, err := Draft(testJob(), p, subject, body)
+	if err != nil {
+		t.Fatalf("Draft: %v", err)
+	}
+	if dir := filepath.Join(home, "Maildir", "drafts", "new"); filepath.Dir(path) != dir {
+		t.Errorf("draft written to %s, want %s", path, dir)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "To: jobs@acme.dev") || !strings.Contains(string(data), "Subject: "+subject) {
+		t.Errorf("unexpected draft:\n%s", data)
+	}
+	if strings.Contains(string(data), "Cc:") {
+		t.Errorf("draft has a Cc line without cc addresses:\n%s", data)
+	}
+
+	j := testJob()
+	j.Cc = []string{"maria@acme.dev", "tom@acme.dev"}
+	path, err = Draft(j, p, subject, body)
+	if err != nil {
+		t.Fatalf("Draft: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), "To: jobs@acme.dev\nCc: maria@acme.dev, tom@acme.dev\n") {
+		t.Errorf("draft lacks the Cc line:\n%s", data)
+	}
+}
+
+func TestGenerateEmailContext_Cancel(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/tags" {
+			w.Write([]byte(`{"models":[{"name":"llama3.1:latest"}]}`))
+			return
+		}
+		select { // a generation that never finishes
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+	client := llm.NewClientFor(&llm.Ollama{URL: srv.URL, ModelName: "llama3.1"})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, _, err := GenerateEmailContext(ctx, testJob(), profile.NewDefaultProfile(), client, "email_cold")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GenerateEmailContext = %v, want the deadline", err)
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("cancelled generation took %v", took)
+	}
+}
+
+func TestRenderTemplate_UsesCVData(t *testing.T) {
+	p := profile.NewDefaultProfile()
+	p.CVData = &profile.CVData{
+		Name:    "Grace Hopper",
+		Summary: "Compiler engineer.",
+		Skills:  []string{"COBOL", "Go"},
+		Experience: []profile.Experience{
+			{Title: "Staff Engineer", Company: "Navy", Duration: "1943-1986"},
+		},
+	}
+
+	subject, body, err := RenderTemplate("cover_letter", testJob(), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "Cover letter — Senior Go Engineer at Acme" {
+		t.Errorf("subject = %q", subject)
+	}
+	for _, want := range []string{"as Staff Engineer at Navy", "COBOL and Go", "Compiler engineer.", "- Staff Engineer, Navy (1943-1986)", "Grace Hopper"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestRenderTemplate_Unknown(t *testing.T) {
+	if _, _, err := RenderTemplate("nope", testJob(), profile.NewDefaultProfile()); err == nil {
+		t.Error("expected error for unknown template")
+	}
+	if got := BuiltinTemplates(); len(got) != 3 {
+		t.Errorf("BuiltinTemplates = %v", got)
+	}
+}
+
+func TestRenderWithdrawal(t *testing.T) {
+	p := profile.NewDefaultProfile()
+	p.Name = "Jane Doe"
+	a := application.Application{
+		JobID: "gh-1", Company: "Acme", Title: "Backend Engineer",
+		AppliedAt: time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC),
+	}
+
+	subject, body, err := RenderWithdrawal(a, p, "I have accepted another offer.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "Withdrawing my application: Backend Engineer — Jane Doe" {
+		t.Errorf("subject = %q", subject)
+	}
+	for _, want := range []string{"Hi Acme team", "Backend Engineer position, which I applied for on 2024-03-04", "I have accepted another offer, so I would like to withdraw", "Jane Doe"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+
+	_, body, err = RenderWithdrawal(a, p, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, "2024-03-04. I would like to withdraw") {
+		t.Errorf("body without a reason:\n%s", body)
+	}
+}
+
+func TestRenderTemplate_UserTemplate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(llm.EnvLLMKey, "")
+	dir := TemplateDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tmpl := "Subject: {{.Vars.job_title}} at {{.Vars.company}}\n\nI work with {{.Vars.matched_tech}}.\n{{.Vars.experience}}\n"
+	if err := os.WriteFile(filepath.Join(dir, "email.tmpl"), []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "thanks.tmpl"), []byte("Subject: Thanks\n\nThanks, {{.Name}}"), 0o644)
+
+	p := profile.NewDefaultProfile()
+	p.Keywords = []string{"golang", "haskell"}
+	p.CVData = &profile.CVData{
+		Technologies: []string{"Rust", "COBOL"},
+		Experience:   []profile.Experience{{Title: "SRE", Company: "Navy", Duration: "2019-2024"}},
+	}
+	j := testJob()
+	j.Description = "We write Golang and Rust services."
+
+	subject, body, err := GenerateEmail(j, p, llm.NewClient(), "email_cold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject != "Senior Go Engineer at Acme" {
+		t.Errorf("subject = %q", subject)
+	}
+	for _, want := range []string{"I work with golang, Rust.", "SRE at Navy"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+
+	if _, _, err := RenderTemplate("cover_letter", j, p); err != nil {
+		t.Errorf("built-in cover letter without an override: %v", err)
+	}
+	if got := strings.Join(BuiltinTemplates(), ","); got != "cover_letter,email,email_followup,thanks" {
+		t.Errorf("BuiltinTemplates = %s", got)
+	}
+
+	os.WriteFile(filepath.Join(dir, "email.tmpl"), []byte("Subject: {{.Broken"), 0o644)
+	if _, _, err := RenderTemplate("email", j, p); err == nil {
+		t.Error("a broken user template rendered")
+	}
+}
+
+func TestCVGenerator_FillsTemplateWithoutLLM(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(llm.EnvLLMKey, "")
+	p := profile.NewDefaultProfile()
+	p.CVData = &profile.CVData{Name: "Grace Hopper", Technologies: []string{"Go"}}
+
+	src, err := NewCVGenerator(llm.NewClient()).GenerateCustomCV(&job.Job{ID: "1", Title: "Go Engineer", Company: "Acme"}, &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(src, `\documentclass`) || !strings.Contains(src, "Grace Hopper") {
+		t.Errorf("filled CV template:\n%s", src)
+	}
+}
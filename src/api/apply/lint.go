@@ -0,0 +1,170 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sprayer/src/api/profile"
+)
+
+// AddressRole names where an address in an outgoing application came from.
+type AddressRole string
+
+const (
+	RoleScratch   AddressRole = "scratch address"
+	RoleFrom      AddressRole = "From"
+	RoleCV        AddressRole = "CV contact"
+	RoleSignature AddressRole = "signature"
+)
+
+// Addresses are the sender addresses visible in one application. Empty
+// fields are unknown and never reported.
+type Addresses struct {
+	Scratch   string // alias assigned to the job for reply tracking
+	From      string
+	CV        string
+	Signature string
+}
+
+// AddressWarning reports an address that disagrees with the one the
+// application is expected to use.
+type AddressWarning struct {
+	Role     AddressRole
+	Got      string
+	Expected AddressRole
+	Want     string
+}
+
+func (w AddressWarning) String() string {
+	return fmt.Sprintf("%s is %s but %s is %s", w.Role, w.Got, w.Expected, w.Want)
+}
+
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+
+// CheckAddresses compares every known address against the one replies
+// should go to: the job's scratch alias if it has one, else From.
+func CheckAddresses(a Addresses) []AddressWarning {
+	roles := []struct {
+		role AddressRole
+		addr string
+	}{
+		{RoleScratch, a.Scratch}, {RoleFrom, a.From}, {RoleCV, a.CV}, {RoleSignature, a.Signature},
+	}
+
+	ref := -1
+	for i, r := range roles {
+		if r.addr != "" {
+			ref = i
+			break
+		}
+	}
+	if ref < 0 {
+		return nil
+	}
+
+	var warnings []AddressWarning
+	for _, r := range roles[ref+1:] {
+		if r.addr != "" && !strings.EqualFold(r.addr, roles[ref].addr) {
+			warnings = append(warnings, AddressWarning{
+				Role: r.role, Got: r.addr, Expected: roles[ref].role, Want: roles[ref].addr,
+			})
+		}
+	}
+	return warnings
+}
+
+// ComposedAddresses gathers the addresses of an application about to be
+// sent from from with the given body.
+func ComposedAddresses(p profile.Profile, from, body, scratch string) Addresses {
+	return Addresses{
+		Scratch:   scratch,
+		From:      from,
+		CV:        CVEmail(p),
+		Signature: SignatureEmail(body),
+	}
+}
+
+// CVEmail returns the contact address in the profile's CV: the first
+// address in the .tex source when there is one, else the parsed CV data.
+func CVEmail(p profile.Profile) string {
+	if tex := cvTexPath(p); tex != "" {
+		if data, err := os.ReadFile(tex); err == nil {
+			return emailPattern.FindString(string(data))
+		}
+	}
+	if p.CVData != nil {
+		return p.CVData.Email
+	}
+	return ""
+}
+
+// SignatureEmail returns the first address in body's signature block, or
+// "" when the signature has none. Addresses elsewhere in the body, such as
+// a recruiter's, are not the sender's and are ignored.
+func SignatureEmail(body string) string {
+	return emailPattern.FindString(signatureBlock(body))
+}
+
+// signOff matches a closing line such as "Best regards," that a signature
+// follows.
+var signOff = regexp.MustCompile(`(?i)^(best|best regards|kind regards|warm regards|regards|many thanks|thanks|thank you|cheers|sincerely|yours|yours sincerely|yours truly)[,.!]?$`)
+
+// signatureBlock returns the signature at the end of body: what follows a
+// "-- " delimiter, else what follows the last sign-off line, else the last
+// paragraph.
+func signatureBlock(body string) string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	lines := strings.Split(strings.TrimRight(body, "\n \t"), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if lines[i] == "-- " || lines[i] == "--" {
+			return strings.Join(lines[i+1:], "\n")
+		}
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		if signOff.MatchString(strings.TrimSpace(lines[i])) {
+			return strings.Join(lines[i+1:], "\n")
+		}
+	}
+	start := 0
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) == "" {
+			start = i + 1
+			break
+		}
+	}
+	return strings.Join(lines[start:], "\n")
+}
+
+// FixCVContact replaces the contact address in the CV's .tex source with
+// addr, so the next build carries the right contact block. Other addresses
+// in the CV, such as referees', are left alone.
+func FixCVContact(p profile.Profile, addr string) error {
+	tex := cvTexPath(p)
+	if tex == "" {
+		return fmt.Errorf("no .tex source for CV %q", p.CVPath)
+	}
+	data, err := os.ReadFile(tex)
+	if err != nil {
+		return err
+	}
+	old := emailPattern.Find(data)
+	if old == nil {
+		return fmt.Errorf("no contact address in %s", tex)
+	}
+	fixed := strings.ReplaceAll(string(data), string(old), addr)
+	return os.WriteFile(tex, []byte(fixed), 0644)
+}
+
+func cvTexPath(p profile.Profile) string {
+	if p.CVPath == "" {
+		return ""
+	}
+	tex := strings.TrimSuffix(p.CVPath, filepath.Ext(p.CVPath)) + ".tex"
+	if _, err := os.Stat(tex); err != nil {
+		return ""
+	}
+	return tex
+}
@@ -0,0 +1,100 @@
+package apply
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/profile"
+)
+
+func TestCheckAddresses(t *testing.T) {
+	const real, alias, other = "me@real.dev", "acme-42@scratch.io", "old@real.dev"
+
+	tests := []struct {
+		name string
+		a    Addresses
+		want []string // rendered warnings
+	}{
+		{"all agree", Addresses{Scratch: alias, From: alias, CV: alias, Signature: alias}, nil},
+		{"case differs only", Addresses{From: real, CV: "Me@Real.dev"}, nil},
+		{"nothing known", Addresses{}, nil},
+		{"cv real, sending from scratch", Addresses{Scratch: alias, From: alias, CV: real},
+			[]string{"CV contact is me@real.dev but scratch address is acme-42@scratch.io"}},
+		{"from real, scratch assigned", Addresses{Scratch: alias, From: real, CV: alias},
+			[]string{"From is me@real.dev but scratch address is acme-42@scratch.io"}},
+		{"signature scratch, from real", Addresses{From: real, CV: real, Signature: alias},
+			[]string{"signature is acme-42@scratch.io but From is me@real.dev"}},
+		{"cv scratch, from real", Addresses{From: real, CV: alias},
+			[]string{"CV contact is acme-42@scratch.io but From is me@real.dev"}},
+		{"no from, cv vs signature", Addresses{CV: real, Signature: other},
+			[]string{"signature is old@real.dev but CV contact is me@real.dev"}},
+		{"everything wrong", Addresses{Scratch: alias, From: real, CV: other, Signature: real},
+			[]string{
+				"From is me@real.dev but scratch address is acme-42@scratch.io",
+				"CV contact is old@real.dev but scratch address is acme-42@scratch.io",
+				"signature is me@real.dev but scratch address is acme-42@scratch.io",
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, w := range CheckAddresses(tt.a) {
+				got = append(got, w.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestComposedAddresses_ExtractsFromCVAndBody(t *testing.T) {
+	dir := t.TempDir()
+	tex := filepath.Join(dir, "cv.tex")
+	src := `\name{Jo}` + "\n" + `\email{\href{mailto:me@real.dev}{me@real.dev}}` + "\n" +
+		`\referee{Sam}{sam@uni.edu}` + "\n"
+	if err := os.WriteFile(tex, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p := profile.Profile{CVPath: filepath.Join(dir, "cv.pdf")}
+	body := "Hi,\n\nI saw your post via hr@acme.com.\n\nBest,\nJo\nacme-42@scratch.io\n"
+
+	a := ComposedAddresses(p, "acme-42@scratch.io", body, "")
+	if a.CV != "me@real.dev" || a.Signature != "acme-42@scratch.io" {
+		t.Fatalf("extracted %+v", a)
+	}
+
+	if err := FixCVContact(p, "acme-42@scratch.io"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(tex)
+	if strings.Contains(string(data), "me@real.dev") || !strings.Contains(string(data), "sam@uni.edu") {
+		t.Errorf("fix should replace only the contact address:\n%s", data)
+	}
+	if w := CheckAddresses(ComposedAddresses(p, "acme-42@scratch.io", body, "")); len(w) != 0 {
+		t.Errorf("still warns after fix: %v", w)
+	}
+}
+
+func TestCVEmail_FallsBackToParsedData(t *testing.T) {
+	p := profile.Profile{CVPath: "/nonexistent/cv.pdf", CVData: &profile.CVData{Email: "me@real.dev"}}
+	if got := CVEmail(p); got != "me@real.dev" {
+		t.Errorf("CVEmail = %q", got)
+	}
+}
+
+func TestSignatureEmail_ReadsOnlyTheSignature(t *testing.T) {
+	cases := []struct{ name, body, want string }{
+		{"after sign-off", "Hi,\n\nI saw your post via hr@acme.com.\n\nBest regards,\nJo\nme@real.dev\n", "me@real.dev"},
+		{"sign-off without address", "Hi,\n\nPlease reply to hr@acme.com.\n\nBest,\nJo\n", ""},
+		{"delimiter", "Hi,\n\nThanks!\n-- \nJo · jo@real.dev\nreferee: sam@uni.edu\n", "jo@real.dev"},
+		{"last paragraph", "Hi,\n\nWrite to hr@acme.com.\n\nJo\njo@real.dev\n", "jo@real.dev"},
+	}
+	for _, c := range cases {
+		if got := SignatureEmail(c.body); got != c.want {
+			t.Errorf("%s: SignatureEmail = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
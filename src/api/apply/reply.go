@@ -0,0 +1,197 @@
+package apply
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Message is a plain-text email with the headers needed for threading.
+type Message struct {
+	MessageID  string
+	InReplyTo  string
+	References []string
+	From       string
+	ReplyTo    string
+	To         string
+	Subject    string
+	Date       time.Time
+	Body       string
+}
+
+// ParseMessage reads an RFC 5322 message such as a recruiter reply saved
+// from a mail client. Only the text body of single-part messages is kept,
+// decoded from quoted-printable or base64 when its
+// Content-Transfer-Encoding says so.
+func ParseMessage(r io.Reader) (Message, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return Message{}, fmt.Errorf("parse message: %w", err)
+	}
+	body, err := io.ReadAll(transferDecoder(msg.Header.Get("Content-Transfer-Encoding"), msg.Body))
+	if err != nil {
+		return Message{}, fmt.Errorf("read body: %w", err)
+	}
+
+	h := msg.Header
+	m := Message{
+		MessageID:  strings.TrimSpace(h.Get("Message-ID")),
+		InReplyTo:  strings.TrimSpace(h.Get("In-Reply-To")),
+		References: strings.Fields(h.Get("References")),
+		From:       h.Get("From"),
+		ReplyTo:    h.Get("Reply-To"),
+		To:         h.Get("To"),
+		Subject:    decodeHeader(h.Get("Subject")),
+		Body:       strings.ReplaceAll(string(body), "\r\n", "\n"),
+	}
+	if d, err := h.Date(); err == nil {
+		m.Date = d
+	}
+	return m, nil
+}
+
+// transferDecoder undoes the Content-Transfer-Encoding cte on body; 7bit,
+// 8bit, binary and unknown encodings are read as they are.
+func transferDecoder(cte string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(cte)) {
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	}
+	return body
+}
+
+func decodeHeader(s string) string {
+	dec := new(mime.WordDecoder)
+	if out, err := dec.DecodeHeader(s); err == nil {
+		return out
+	}
+	return s
+}
+
+// NewReply prepares a reply to orig from the given address: addressed to
+// the sender (or Reply-To), subject prefixed "Re:", threading headers set
+// and the original quoted below an empty writing area.
+func NewReply(orig Message, from string) Message {
+	to := orig.ReplyTo
+	if to == "" {
+		to = orig.From
+	}
+
+	refs := append([]string(nil), orig.References...)
+	if len(refs) == 0 && orig.InReplyTo != "" {
+		refs = append(refs, orig.InReplyTo)
+	}
+	if orig.MessageID != "" {
+		refs = append(refs, orig.MessageID)
+	}
+
+	return Message{
+		MessageID:  NewMessageID(from),
+		InReplyTo:  orig.MessageID,
+		References: refs,
+		From:       from,
+		To:         to,
+		Subject:    replySubject(orig.Subject),
+		Date:       time.Now(),
+		Body:       "\n\n" + Quote(orig),
+	}
+}
+
+func replySubject(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 3 && strings.EqualFold(s[:3], "re:") {
+		return s
+	}
+	return "Re: " + s
+}
+
+// Quote renders orig as an attribution line followed by "> " quoted lines.
+func Quote(orig Message) string {
+	var b strings.Builder
+	name := orig.From
+	if addr, err := mail.ParseAddress(orig.From); err == nil && addr.Name != "" {
+		name = addr.Name
+	}
+	if orig.Date.IsZero() {
+		fmt.Fprintf(&b, "%s wrote:\n", name)
+	} else {
+		fmt.Fprintf(&b, "On %s, %s wrote:\n", orig.Date.Format("Mon, Jan 2, 2006 at 15:04"), name)
+	}
+
+	// Split rather than scan: a bufio.Scanner stops at a line over its
+	// buffer size and would drop the rest of the message.
+	for _, line := range strings.Split(strings.TrimRight(orig.Body, "\n"), "\n") {
+		if strings.HasPrefix(line, ">") {
+			b.WriteString(">" + line + "\n")
+		} else if line == "" {
+			b.WriteString(">\n")
+		} else {
+			b.WriteString("> " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// NewMessageID returns a unique Message-ID in the sender's domain.
+func NewMessageID(from string) string {
+	domain := "sprayer.local"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		from = addr.Address
+	}
+	if i := strings.LastIndex(from, "@"); i >= 0 && i < len(from)-1 {
+		domain = from[i+1:]
+	}
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(buf), domain)
+}
+
+// Format renders m as an RFC 5322 message.
+func (m Message) Format() []byte {
+	var b strings.Builder
+	header := func(k, v string) {
+		if v != "" {
+			fmt.Fprintf(&b, "%s: %s\r\n", k, v)
+		}
+	}
+	date := m.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	header("From", m.From)
+	header("To", m.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("Message-ID", m.MessageID)
+	header("In-Reply-To", m.InReplyTo)
+	header("References", strings.Join(m.References, " "))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(m.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// DraftMessage saves m into the Maildir drafts folder used by Draft.
+func DraftMessage(m Message) (string, error) {
+	dir, err := draftsDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%d.sprayer.reply", time.Now().UnixNano()))
+	if err := os.WriteFile(path, m.Format(), 0644); err != nil {
+		return "", fmt.Errorf("write draft: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,118 @@
+package apply
+
+import (
+	"bytes"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadReply(t *testing.T) Message {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", "recruiter_reply.eml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	m, err := ParseMessage(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestNewReply_ThreadingHeaders(t *testing.T) {
+	orig := loadReply(t)
+	reply := NewReply(orig, "Ada Lovelace <ada@example.com>")
+	reply.Body = "Thursday works, 10am?" + reply.Body
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(reply.Format()))
+	if err != nil {
+		t.Fatalf("reply is not a valid message: %v", err)
+	}
+	h := parsed.Header
+	if got := h.Get("To"); got != "talent@acme.dev" {
+		t.Errorf("To = %q, want Reply-To address", got)
+	}
+	if got := decodeHeader(h.Get("Subject")); got != "Re: Your application — Senior Go Engineer" {
+		t.Errorf("Subject = %q", got)
+	}
+	if got := h.Get("In-Reply-To"); got != "<reply-2@acme.dev>" {
+		t.Errorf("In-Reply-To = %q", got)
+	}
+	if got := h.Get("References"); got != "<app-1@example.com> <reply-2@acme.dev>" {
+		t.Errorf("References = %q, want full chain", got)
+	}
+	if id := h.Get("Message-ID"); !strings.HasPrefix(id, "<") || !strings.HasSuffix(id, "@example.com>") {
+		t.Errorf("Message-ID = %q", id)
+	}
+}
+
+func TestNewReply_QuotesBelowCursorArea(t *testing.T) {
+	reply := NewReply(loadReply(t), "ada@example.com")
+
+	want := "\n\nOn Tue, Mar 5, 2024 at 14:30, Jane Recruiter wrote:\n" +
+		"> Hi Ada,\n" +
+		">\n" +
+		"> Thanks for applying. Are you free Thursday?\n" +
+		">\n" +
+		">> Original application text\n" +
+		">\n" +
+		"> Jane\n"
+	if reply.Body != want {
+		t.Errorf("body:\n%q\nwant:\n%q", reply.Body, want)
+	}
+}
+
+func TestReplySubject_NoDoublePrefix(t *testing.T) {
+	if got := replySubject("RE: Interview"); got != "RE: Interview" {
+		t.Errorf("got %q", got)
+	}
+	if got := replySubject("Interview"); got != "Re: Interview" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestDraftMessage_WritesThreadedDraft(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	reply := NewReply(loadReply(t), "ada@example.com")
+
+	path, err := DraftMessage(reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "In-Reply-To: <reply-2@acme.dev>\r\n") {
+		t.Errorf("draft missing threading header:\n%s", data)
+	}
+}
+
+func TestParseMessage_DecodesTransferEncoding(t *testing.T) {
+	for cte, body := range map[string]string{
+		"quoted-printable": "Caf=C3=A9 on Tuesday? Let=E2=80=99s ta=\r\nlk.\r\n",
+		"base64":           "Q2Fmw6kgb24gVHVlc2RheT8gTGV04oCZcyB0\r\nYWxrLgo=\r\n",
+		"7bit":             "Café on Tuesday? Let’s talk.\r\n",
+	} {
+		raw := "From: jane@acme.com\r\nSubject: Re: Go Engineer\r\nContent-Transfer-Encoding: " + cte + "\r\n\r\n" + body
+		m, err := ParseMessage(strings.NewReader(raw))
+		if err != nil {
+			t.Fatalf("%s: %v", cte, err)
+		}
+		if strings.TrimSpace(m.Body) != "Café on Tuesday? Let’s talk." {
+			t.Errorf("%s: body = %q", cte, m.Body)
+		}
+	}
+}
+
+func TestQuote_KeepsLongLines(t *testing.T) {
+	long := strings.Repeat("x", 100_000)
+	q := Quote(Message{From: "jane@acme.com", Body: "first\n" + long + "\nlast\n"})
+	if !strings.Contains(q, "> "+long+"\n") || !strings.HasSuffix(q, "> last\n") {
+		t.Errorf("quote lost lines after a long one: %d bytes", len(q))
+	}
+}
@@ -3,48 +3,210 @@ package apply
 import (
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/smtp"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/jordan-wright/email"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/offline"
 )
 
-// SendDirect sends an email immediately using SMTP configuration.
-// It mimics the behavior of tools like 'pop'.
-func SendDirect(to, subject, body, attachmentPath string) error {
-	host := os.Getenv("SPRAYER_SMTP_HOST")
-	port := os.Getenv("SPRAYER_SMTP_PORT")
-	username := os.Getenv("SPRAYER_SMTP_USER")
-	password := os.Getenv("SPRAYER_SMTP_PASS")
-	from := os.Getenv("SPRAYER_SMTP_FROM")
+// SendDirect sends an email immediately using SMTP configuration, copying
+// any cc addresses. It mimics the behavior of tools like 'pop'.
+func SendDirect(to, subject, body, attachmentPath string, cc ...string) error {
+	return SendTracked(to, subject, body, attachmentPath, "", cc...)
+}
 
-	if host == "" || username == "" || password == "" {
-		return fmt.Errorf("SMTP configuration missing (SPRAYER_SMTP_HOST, USER, PASS)")
+// SendTracked is SendDirect with an open-tracking image at pixelURL in the
+// HTML part; an empty pixelURL adds none.
+func SendTracked(to, subject, body, attachmentPath, pixelURL string, cc ...string) error {
+	e, err := trackedEmail(to, subject, body, attachmentPath, pixelURL, cc...)
+	if err != nil {
+		return err
 	}
-	if from == "" {
-		from = username
+	return send(e)
+}
+
+// PreviewTracked builds the message SendTracked would send and writes it
+// to EmailOutputDir as an .eml file without sending, returning its path.
+func PreviewTracked(to, subject, body, attachmentPath, pixelURL string, cc ...string) (string, error) {
+	e, err := trackedEmail(to, subject, body, attachmentPath, pixelURL, cc...)
+	if err != nil {
+		return "", err
 	}
-	if port == "" {
-		port = "587"
+	if e.From == "" {
+		e.From = SMTPFrom()
 	}
+	return writeEML(e)
+}
 
+func trackedEmail(to, subject, body, attachmentPath, pixelURL string, cc ...string) (*email.Email, error) {
 	e := email.NewEmail()
-	e.From = from
 	e.To = []string{to}
+	e.Cc = cc
 	e.Subject = subject
 	e.Text = []byte(body)
-	
+
 	// Basic HTML conversion (wrapping body in pre/div)
 	// In a real 'pop' like tool we would render markdown.
-	htmlBody := fmt.Sprintf("<html><body><pre style='font-family: sans-serif'>%s</pre></body></html>", body)
+	pixel := ""
+	if pixelURL != "" {
+		pixel = fmt.Sprintf("<img src=\"%s\" width=\"1\" height=\"1\" alt=\"\">", pixelURL)
+	}
+	htmlBody := fmt.Sprintf("<html><body><pre style='font-family: sans-serif'>%s</pre>%s</body></html>", body, pixel)
 	e.HTML = []byte(htmlBody)
 
 	if attachmentPath != "" {
 		if _, err := e.AttachFile(attachmentPath); err != nil {
-			return fmt.Errorf("attach file: %w", err)
+			return nil, fmt.Errorf("attach file: %w", err)
 		}
 	}
+	return e, nil
+}
+
+// SendMessage sends m as plain text, keeping its threading headers so the
+// recipient's client files it under the original conversation.
+func SendMessage(m Message) error {
+	e := email.NewEmail()
+	e.From = m.From
+	e.To = []string{m.To}
+	e.Subject = m.Subject
+	e.Text = []byte(m.Body)
+	if m.MessageID != "" {
+		e.Headers.Set("Message-ID", m.MessageID)
+	}
+	if m.InReplyTo != "" {
+		e.Headers.Set("In-Reply-To", m.InReplyTo)
+	}
+	if len(m.References) > 0 {
+		e.Headers.Set("References", strings.Join(m.References, " "))
+	}
+	return send(e)
+}
+
+// EnvSMTPDryRun, set to 1, has every send write the message to
+// EmailOutputDir instead of handing it to the SMTP server.
+const EnvSMTPDryRun = "SPRAYER_SMTP_DRY_RUN"
+
+// SMTPDryRun reports whether EnvSMTPDryRun is on.
+func SMTPDryRun() bool {
+	v := os.Getenv(EnvSMTPDryRun)
+	return v == "1" || strings.EqualFold(v, "true")
+}
+
+// EmailOutputDir is where previewed and dry-run messages are written.
+func EmailOutputDir() string {
+	return filepath.Join(job.DataDir(), "outputs", "emails")
+}
+
+// CoverLetterDir is where generated cover letters are written.
+func CoverLetterDir() string {
+	return filepath.Join(job.DataDir(), "outputs", "cover-letters")
+}
+
+// WriteCoverLetter writes the cover letter for jobID to CoverLetterDir,
+// replacing one written before, and returns its path.
+func WriteCoverLetter(jobID, letter string) (string, error) {
+	dir := CoverLetterDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create cover letters dir: %w", err)
+	}
+	path := filepath.Join(dir, sanitize(jobID)+".txt")
+	if err := os.WriteFile(path, []byte(strings.TrimRight(letter, "\n")+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("write cover letter: %w", err)
+	}
+	return path, nil
+}
+
+// DryRunError is returned by sends in a dry run: the message was written
+// to Path and not sent.
+type DryRunError struct {
+	Path string
+}
+
+func (e *DryRunError) Error() string {
+	return "dry run: message written to " + e.Path + ", not sent"
+}
+
+// writeEML writes the full MIME message, attachments included, to
+// EmailOutputDir.
+func writeEML(e *email.Email) (string, error) {
+	raw, err := e.Bytes()
+	if err != nil {
+		return "", fmt.Errorf("build message: %w", err)
+	}
+	dir := EmailOutputDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("create emails dir: %w", err)
+	}
+	to := ""
+	if len(e.To) > 0 {
+		to = "." + sanitize(e.To[0])
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%d%s.eml", time.Now().UnixNano(), to))
+	if err := os.WriteFile(path, raw, 0644); err != nil {
+		return "", fmt.Errorf("write message: %w", err)
+	}
+	return path, nil
+}
+
+// SMTPFrom is the sender address configured for outgoing mail.
+func SMTPFrom() string {
+	if from := os.Getenv("SPRAYER_SMTP_FROM"); from != "" {
+		return from
+	}
+	return os.Getenv("SPRAYER_SMTP_USER")
+}
+
+// SMTPAddr is the configured host:port of the mail server, or "" when no
+// host is set.
+func SMTPAddr() string {
+	host := os.Getenv("SPRAYER_SMTP_HOST")
+	if host == "" {
+		return ""
+	}
+	port := os.Getenv("SPRAYER_SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+func send(e *email.Email) error {
+	host := os.Getenv("SPRAYER_SMTP_HOST")
+	port := os.Getenv("SPRAYER_SMTP_PORT")
+	username := os.Getenv("SPRAYER_SMTP_USER")
+	password := os.Getenv("SPRAYER_SMTP_PASS")
+
+	if SMTPDryRun() {
+		if e.From == "" {
+			e.From = SMTPFrom()
+		}
+		path, err := writeEML(e)
+		if err != nil {
+			return err
+		}
+		return &DryRunError{Path: path}
+	}
+	if host == "" || username == "" || password == "" {
+		return fmt.Errorf("SMTP configuration missing (SPRAYER_SMTP_HOST, USER, PASS)")
+	}
+	if port == "" {
+		port = "587"
+	}
+	if e.From == "" {
+		e.From = SMTPFrom()
+	}
 
 	addr := fmt.Sprintf("%s:%s", host, port)
+	if err := offline.CheckHost("sending", addr); err != nil {
+		return err
+	}
 	auth := smtp.PlainAuth("", username, password, host)
 
 	// Start TLS if port is 587 or 465
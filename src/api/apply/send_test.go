@@ -0,0 +1,98 @@
+package apply
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// attachments parses the .eml at path and returns its attachments'
+// decoded contents by filename.
+func attachments(t *testing.T, path string) (*mail.Message, map[string][]byte) {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("not a valid message: %v", err)
+	}
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := map[string][]byte{}
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if part.FileName() == "" {
+			continue
+		}
+		enc, _ := io.ReadAll(part)
+		if part.Header.Get("Content-Transfer-Encoding") != "base64" {
+			t.Fatalf("%s not base64", part.FileName())
+		}
+		data, err := base64.StdEncoding.DecodeString(strings.NewReplacer("\r", "", "\n", "").Replace(string(enc)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[part.FileName()] = data
+	}
+	return msg, out
+}
+
+func TestPreviewTracked(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("SPRAYER_SMTP_FROM", "ada@example.com")
+	cv := filepath.Join(t.TempDir(), "cv.pdf")
+	pdf := append([]byte("%PDF-1.5\n"), bytes.Repeat([]byte{0, 0xff, 0x7f}, 200)...)
+	if err := os.WriteFile(cv, pdf, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := PreviewTracked("jobs@acme.com", "Go Engineer", "Hello Acme,\n", cv, "https://t.example.com/t/open/x.gif", "cto@acme.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(path) != EmailOutputDir() || filepath.Ext(path) != ".eml" {
+		t.Errorf("path = %s", path)
+	}
+	msg, att := attachments(t, path)
+	if msg.Header.Get("To") != "<jobs@acme.com>" || msg.Header.Get("Cc") != "<cto@acme.com>" ||
+		msg.Header.Get("Subject") != "Go Engineer" || !strings.Contains(msg.Header.Get("From"), "ada@example.com") {
+		t.Errorf("headers = %v", msg.Header)
+	}
+	if !bytes.Equal(att["cv.pdf"], pdf) {
+		t.Errorf("attachment decodes to %d bytes, want the %d of the original", len(att["cv.pdf"]), len(pdf))
+	}
+}
+
+func TestSendTracked_DryRun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(EnvSMTPDryRun, "1")
+	t.Setenv("SPRAYER_SMTP_HOST", "") // would fail were it to send
+
+	err := SendTracked("jobs@acme.com", "Go Engineer", "Hello\n", "", "")
+	var dry *DryRunError
+	if !errors.As(err, &dry) {
+		t.Fatalf("err = %v, want a DryRunError", err)
+	}
+	if _, err := os.Stat(dry.Path); err != nil {
+		t.Errorf("no message written: %v", err)
+	}
+}
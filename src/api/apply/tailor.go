@@ -0,0 +1,95 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sprayer/src/api/export"
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+// CVOutputDir is where the tailored CV for jobID is built: the .tex
+// source and the PDF, or the log of a failed build.
+func CVOutputDir(jobID string) string {
+	return filepath.Join(job.DataDir(), "outputs", "cv", sanitize(jobID))
+}
+
+// TailorCV builds a CV PDF tailored to j from the profile's CV, in the
+// profile's CV template, and returns its path. A PDF already linked from the job is reused unless
+// regenerate is set, in which case it is overwritten. Without a LaTeX
+// engine the .tex is still written and the error wraps export.ErrNoLaTeX.
+func TailorCV(ctx context.Context, j job.Job, p profile.Profile, regenerate bool) (pdf string, reused bool, err error) {
+	if !regenerate && j.CVPath != "" {
+		if _, err := os.Stat(j.CVPath); err == nil {
+			return j.CVPath, true, nil
+		}
+	}
+
+	cv, err := profileCV(p)
+	if err != nil {
+		return "", false, err
+	}
+	src, err := GenerateLatexCV(j, *cv, p.CVTemplate)
+	if err != nil {
+		return "", false, err
+	}
+	dir := CVOutputDir(j.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", false, fmt.Errorf("create cv dir: %w", err)
+	}
+	tex := filepath.Join(dir, "cv.tex")
+	if err := os.WriteFile(tex, []byte(src), 0644); err != nil {
+		return "", false, fmt.Errorf("write cv: %w", err)
+	}
+	pdf, err = export.CompileLaTeX(ctx, tex)
+	if errors.Is(err, export.ErrNoLaTeX) {
+		return "", false, fmt.Errorf("wrote %s but cannot build the PDF: %w", tex, err)
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return pdf, false, nil
+}
+
+// profileCV is the structured CV behind p: the imported CV data, or the
+// CV file parsed as text.
+func profileCV(p profile.Profile) (*profile.CVData, error) {
+	if p.CVData != nil {
+		return p.CVData, nil
+	}
+	if p.CVPath == "" {
+		return nil, errors.New("profile has no CV to tailor; import one with 'sprayer cv'")
+	}
+	return profile.NewCVParser().ParseCVFromFile(p.CVPath)
+}
+
+// relevantFirst returns terms without duplicates, those text mentions
+// first, each group in its original order.
+func relevantFirst(text string, terms []string) []string {
+	hit, rest := mentioned(text, terms)
+	return append(hit, rest...)
+}
+
+// mentioned splits terms, without duplicates, into those text mentions
+// and the rest.
+func mentioned(text string, terms []string) (hit, rest []string) {
+	seen := map[string]bool{}
+	for _, t := range terms {
+		k := strings.ToLower(strings.TrimSpace(t))
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		if strings.Contains(text, k) {
+			hit = append(hit, t)
+		} else {
+			rest = append(rest, t)
+		}
+	}
+	return hit, rest
+}
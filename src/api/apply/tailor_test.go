@@ -0,0 +1,110 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/export"
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+func TestGenerateLatexCV(t *testing.T) {
+	j := job.Job{Title: "Backend Engineer", Description: "We run Go and PostgreSQL on Kubernetes."}
+	cv := profile.CVData{
+		Name:         "Ada Lovelace",
+		Email:        "ada@example.com",
+		Summary:      "Ships 100% of the time & on budget",
+		Technologies: []string{"Python", "Go", "Kubernetes"},
+		Skills:       []string{"go", "PostgreSQL"},
+		Experience: []profile.Experience{
+			{Company: "R&D Co", Title: "Engineer", Duration: "2020-2024", Technologies: []string{"C#", "Go"}},
+		},
+	}
+	tex, err := GenerateLatexCV(j, cv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`{\LARGE\bfseries Ada Lovelace}`,
+		`Ships 100\% of the time \& on budget`,
+		"\\section*{Skills}\nGo, Kubernetes, PostgreSQL, Python\n",
+		`\textbf{Engineer}, R\&D Co \hfill 2020-2024`,
+		`\emph{Go, C\#}`,
+		`\end{document}`,
+	} {
+		if !strings.Contains(tex, want) {
+			t.Errorf("CV lacks %q:\n%s", want, tex)
+		}
+	}
+
+	classic, err := GenerateLatexCV(j, cv, "classic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(classic, `\begin{minipage}`) || !strings.Contains(classic, "Go\\\\\nKubernetes\\\\") {
+		t.Errorf("classic layout:\n%s", classic)
+	}
+}
+
+func TestGenerateLatexCV_UserTemplates(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	os.MkdirAll(CVTemplateDir(), 0755)
+	mine := `{{.Name}} for {{.Job.Company}}: {{join .Skills "; "}}`
+	if err := os.WriteFile(filepath.Join(CVTemplateDir(), "mine.tex"), []byte(mine), 0644); err != nil {
+		t.Fatal(err)
+	}
+	j := job.Job{Company: "R&D", Description: "C# shop"}
+	cv := profile.CVData{Name: "Ada_L", Skills: []string{"Go", "C#"}}
+	for _, name := range []string{"mine", "mine.tex", filepath.Join(CVTemplateDir(), "mine.tex")} {
+		got, err := GenerateLatexCV(j, cv, name)
+		if want := `Ada\_L for R\&D: C\#; Go`; err != nil || got != want {
+			t.Errorf("GenerateLatexCV(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+
+	if got := CVTemplates(); strings.Join(got, ",") != "classic,compact,mine" {
+		t.Errorf("CVTemplates = %v", got)
+	}
+	_, err := GenerateLatexCV(j, cv, "fancy")
+	if err == nil || !strings.Contains(err.Error(), "have classic, compact, mine") {
+		t.Errorf("unknown template: %v", err)
+	}
+}
+
+func TestTailorCV(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	p := profile.Profile{CVData: &profile.CVData{Name: "Ada", Skills: []string{"Go"}}}
+	j := job.Job{ID: "remoteok/42", Title: "Go Engineer"}
+
+	t.Setenv("PATH", t.TempDir()) // no LaTeX engine
+	t.Setenv(export.EngineEnv, "")
+
+	_, _, err := TailorCV(context.Background(), j, p, false)
+	tex := filepath.Join(CVOutputDir(j.ID), "cv.tex")
+	if _, statErr := os.Stat(tex); statErr != nil {
+		t.Fatalf("no .tex written: %v", statErr)
+	}
+	if !errors.Is(err, export.ErrNoLaTeX) || !strings.Contains(err.Error(), tex) {
+		t.Errorf("without a LaTeX engine: %v", err)
+	}
+
+	// A PDF already linked is reused unless regenerating.
+	linked := filepath.Join(t.TempDir(), "cv.pdf")
+	os.WriteFile(linked, []byte("%PDF"), 0644)
+	j.CVPath = linked
+	if pdf, reused, err := TailorCV(context.Background(), j, p, false); err != nil || !reused || pdf != linked {
+		t.Errorf("reuse = %q, %v, %v", pdf, reused, err)
+	}
+	if pdf, reused, _ := TailorCV(context.Background(), j, p, true); reused || pdf == linked {
+		t.Errorf("regenerate reused %q", pdf)
+	}
+
+	if _, _, err := TailorCV(context.Background(), job.Job{ID: "x"}, profile.Profile{}, false); err == nil {
+		t.Error("tailored a CV without one in the profile")
+	}
+}
@@ -0,0 +1,231 @@
+package apply
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/job"
+	"sprayer/src/api/parse"
+	"sprayer/src/api/profile"
+)
+
+//go:embed templates/*.tmpl templates/withdraw/*.tmpl
+var templateFS embed.FS
+
+var builtinTemplates = template.Must(template.ParseFS(templateFS, "templates/*.tmpl"))
+
+// withdrawalTemplate is kept apart from the builtins, which are all ways
+// to apply.
+var withdrawalTemplate = template.Must(template.ParseFS(templateFS, "templates/withdraw/withdrawal.tmpl"))
+
+// promptTemplates maps LLM prompt names to the built-in template used when
+// no LLM is available.
+var promptTemplates = map[string]string{
+	"email_cold":     "email",
+	"email_followup": "email_followup",
+	"cover_letter":   "cover_letter",
+}
+
+// TemplateData is what built-in templates can reference.
+type TemplateData struct {
+	Job         job.Job
+	Profile     profile.Profile
+	Name        string
+	Email       string
+	Company     string
+	Location    string
+	Skills      string
+	Summary     string
+	AppliedDate string
+	Current     *profile.Experience
+	Experience  []profile.Experience
+	// Answers are the job's answered application questions.
+	Answers []application.Question
+	// Reason is why the applicant withdraws, for the withdrawal email.
+	Reason string
+	// Vars are the variables the LLM prompt would be filled in with
+	// (see PromptVars), e.g. {{.Vars.matched_tech}}.
+	Vars map[string]string
+}
+
+// TemplateDir holds the user's own templates: name.tmpl for emails and
+// letters, which may replace a built-in, and name.tex for CVs.
+func TemplateDir() string {
+	return filepath.Join(job.DataDir(), "templates")
+}
+
+// BuiltinTemplates lists the names accepted by RenderTemplate: the
+// built-ins and the .tmpl files in TemplateDir.
+func BuiltinTemplates() []string {
+	var names []string
+	for _, t := range builtinTemplates.Templates() {
+		names = append(names, strings.TrimSuffix(t.Name(), ".tmpl"))
+	}
+	user, _ := os.ReadDir(TemplateDir())
+	for _, e := range user {
+		if name, ok := strings.CutSuffix(e.Name(), ".tmpl"); ok && !e.IsDir() && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TemplateFor returns the built-in template standing in for an LLM prompt.
+func TemplateFor(promptName string) string {
+	if name, ok := promptTemplates[promptName]; ok {
+		return name
+	}
+	return "email"
+}
+
+// RenderTemplate fills a template from job, profile and CV data, and any
+// answers to the job's questions: name.tmpl in TemplateDir, or the
+// built-in of that name. Templates start with a "Subject:" line followed
+// by a blank line.
+func RenderTemplate(name string, j job.Job, p profile.Profile, answers ...application.Question) (string, string, error) {
+	t, err := loadTemplate(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	data := newTemplateData(j, p)
+	data.Answers = answered(answers)
+	data.Vars = PromptVars(j, p, answers)
+	return execute(t, name, data)
+}
+
+// loadTemplate parses the user's template name, falling back to the
+// built-in.
+func loadTemplate(name string) (*template.Template, error) {
+	base := path.Base(name)
+	src, err := os.ReadFile(filepath.Join(TemplateDir(), base+".tmpl"))
+	if err == nil {
+		t, err := template.New(base).Parse(string(src))
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", base, err)
+		}
+		return t, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read template: %w", err)
+	}
+	if t := builtinTemplates.Lookup(base + ".tmpl"); t != nil {
+		return t, nil
+	}
+	return nil, fmt.Errorf("unknown template %q (have %s)", name, strings.Join(BuiltinTemplates(), ", "))
+}
+
+// RenderWithdrawal writes the email withdrawing application a, which
+// references the role and the date applied. reason, if any, is a clause
+// such as "I have accepted another offer".
+func RenderWithdrawal(a application.Application, p profile.Profile, reason string) (string, string, error) {
+	j := job.Job{ID: a.JobID, Title: a.Title, Company: a.Company, AppliedDate: a.AppliedAt}
+	data := newTemplateData(j, p)
+	data.Reason = strings.TrimSuffix(strings.TrimSpace(reason), ".")
+	return execute(withdrawalTemplate, "withdrawal", data)
+}
+
+// execute runs t and splits its "Subject:" line from the body.
+func execute(t *template.Template, name string, data TemplateData) (string, string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", "", fmt.Errorf("render template %q: %w", name, err)
+	}
+
+	head, body, _ := strings.Cut(buf.String(), "\n\n")
+	subject, ok := strings.CutPrefix(head, "Subject: ")
+	if !ok {
+		return "", "", fmt.Errorf("template %q has no subject line", name)
+	}
+	return strings.TrimSpace(subject), strings.TrimSpace(body) + "\n", nil
+}
+
+func newTemplateData(j job.Job, p profile.Profile) TemplateData {
+	d := TemplateData{
+		Job:      j,
+		Profile:  p,
+		Name:     p.Name,
+		Email:    p.ContactEmail,
+		Company:  j.Company,
+		Location: j.Location,
+	}
+	if d.Company == "" {
+		d.Company = "hiring"
+	}
+	if d.Location == "" {
+		if locs := parse.ExtractLocations(j.Description); len(locs) > 0 {
+			d.Location = locs[0]
+		}
+	}
+	if !j.AppliedDate.IsZero() {
+		d.AppliedDate = j.AppliedDate.Format("2006-01-02")
+	}
+
+	skills := p.Keywords
+	if cv := p.CVData; cv != nil {
+		if cv.Name != "" {
+			d.Name = cv.Name
+		}
+		if d.Email == "" {
+			d.Email = cv.Email
+		}
+		d.Summary = cv.Summary
+		d.Experience = cv.Experience
+		if len(cv.Experience) > 0 {
+			d.Current = &cv.Experience[0]
+		}
+		if len(cv.Skills) > 0 {
+			skills = cv.Skills
+		} else if len(cv.Technologies) > 0 {
+			skills = cv.Technologies
+		}
+	}
+	d.Skills = joinList(skills, 5)
+	return d
+}
+
+func answered(qs []application.Question) []application.Question {
+	var out []application.Question
+	for _, q := range qs {
+		if q.Answered() {
+			out = append(out, q)
+		}
+	}
+	return out
+}
+
+// formatAnswers renders answers for a prompt variable, or "none".
+func formatAnswers(qs []application.Question) string {
+	var parts []string
+	for _, q := range answered(qs) {
+		parts = append(parts, fmt.Sprintf("Q: %s\nA: %s", q.Text, q.Answer))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// joinList renders up to max items as "a, b and c".
+func joinList(items []string, max int) string {
+	if len(items) > max {
+		items = items[:max]
+	}
+	switch len(items) {
+	case 0:
+		return "modern backend technologies"
+	case 1:
+		return items[0]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
@@ -0,0 +1,285 @@
+package batch
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"sprayer/src/api/offline"
+)
+
+func openStore(t *testing.T, path string) *Store {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	s, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func newBatch(t *testing.T, s *Store, n int) *Batch {
+	t.Helper()
+	var items []Item
+	for i := 1; i <= n; i++ {
+		items = append(items, Item{JobID: fmt.Sprintf("job-%d", i), To: fmt.Sprintf("hr%d@example.com", i), Subject: "Hello"})
+	}
+	b, err := s.Create("default", items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func states(b *Batch) string {
+	var out string
+	for _, it := range b.Items {
+		out += string(it.State[0])
+	}
+	return out
+}
+
+func TestItemTransitions(t *testing.T) {
+	all := []ItemState{ItemPending, ItemApproved, ItemSending, ItemSent, ItemFailed, ItemSkipped}
+	allowed := map[[2]ItemState]bool{
+		{ItemPending, ItemApproved}: true, {ItemPending, ItemSkipped}: true,
+		{ItemApproved, ItemSending}: true, {ItemApproved, ItemPending}: true, {ItemApproved, ItemSkipped}: true,
+		{ItemSending, ItemSent}: true, {ItemSending, ItemFailed}: true,
+		{ItemSending, ItemApproved}: true, {ItemSending, ItemSkipped}: true,
+		{ItemFailed, ItemApproved}: true, {ItemFailed, ItemSkipped}: true,
+		{ItemSkipped, ItemPending}: true,
+	}
+	for _, from := range all {
+		for _, to := range all {
+			if got := from.CanTransition(to); got != allowed[[2]ItemState{from, to}] {
+				t.Errorf("%s -> %s allowed = %v", from, to, got)
+			}
+		}
+	}
+}
+
+func TestStore_RejectsInvalidTransitions(t *testing.T) {
+	s := openStore(t, filepath.Join(t.TempDir(), "b.db"))
+	b := newBatch(t, s, 2)
+
+	if err := s.SetItemState(b.ID, 1, ItemSent, ""); !errors.Is(err, ErrTransition) {
+		t.Errorf("pending -> sent should be rejected, got %v", err)
+	}
+	if err := s.SetItemState(b.ID, 3, ItemApproved, ""); err == nil {
+		t.Error("expected an error for a missing item")
+	}
+	if _, err := s.Complete(b.ID); !errors.Is(err, ErrTransition) {
+		t.Errorf("completing with pending items should be rejected, got %v", err)
+	}
+
+	if _, err := s.Abandon(b.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetItemState(b.ID, 1, ItemApproved, ""); !errors.Is(err, ErrTransition) {
+		t.Errorf("abandoned batch should be frozen, got %v", err)
+	}
+	if _, err := s.Abandon(b.ID); !errors.Is(err, ErrTransition) {
+		t.Errorf("closing twice should be rejected, got %v", err)
+	}
+}
+
+func TestStore_CrashAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "b.db")
+	s := openStore(t, path)
+	b := newBatch(t, s, 5)
+	for pos := 1; pos <= 4; pos++ {
+		if err := s.SetItemState(b.ID, pos, ItemApproved, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.SetItemState(b.ID, 5, ItemSkipped, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// SMTP dies on the third message.
+	var sent []string
+	_, err := s.Send(b.ID, func(it Item) error {
+		if it.Position == 3 {
+			return errors.New("421 service not available")
+		}
+		sent = append(sent, it.JobID)
+		return nil
+	})
+	if err == nil || len(sent) != 2 {
+		t.Fatalf("expected failure after two sends, got %v (sent %v)", err, sent)
+	}
+
+	// Restart: a fresh store on the same file sees exactly where it stopped.
+	s = openStore(t, path)
+	open, err := s.Open()
+	if err != nil || len(open) != 1 {
+		t.Fatalf("expected the batch to be reopenable, got %v (%v)", open, err)
+	}
+	b = &open[0]
+	if got := states(b); got != "ssfas" {
+		t.Fatalf("states after reopen = %s, want ssfas", got)
+	}
+	if it, _ := b.Item(3); it.Error != "421 service not available" {
+		t.Errorf("failure not kept: %q", it.Error)
+	}
+
+	if err := s.Retry(b.ID); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := s.Send(b.ID, func(it Item) error {
+		sent = append(sent, it.JobID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(sent) != "[job-1 job-2 job-3 job-4]" || sum.Sent != 4 || sum.Skipped != 1 {
+		t.Errorf("sent %v, summary %+v", sent, sum)
+	}
+
+	if _, err := s.Complete(b.ID); err != nil {
+		t.Fatal(err)
+	}
+	if open, _ := s.Open(); len(open) != 0 {
+		t.Errorf("completed batch still open")
+	}
+	hist, err := s.History()
+	if err != nil || len(hist) != 1 {
+		t.Fatalf("history = %v (%v)", hist, err)
+	}
+	if h := hist[0]; h.State != StateCompleted || h.Sent != 4 || h.Skipped != 1 || h.Failed != 0 {
+		t.Errorf("history entry = %+v", h)
+	}
+}
+
+func TestStore_RetrySingleItem(t *testing.T) {
+	s := openStore(t, filepath.Join(t.TempDir(), "b.db"))
+	b := newBatch(t, s, 3)
+	for pos := 1; pos <= 3; pos++ {
+		s.SetItemState(b.ID, pos, ItemApproved, "")
+	}
+	// Every send fails, so only the first is attempted.
+	s.Send(b.ID, func(Item) error { return errors.New("down") })
+	if err := s.Retry(b.ID, 2); !errors.Is(err, ErrTransition) {
+		t.Errorf("retrying an item that never failed should be rejected, got %v", err)
+	}
+	if err := s.Retry(b.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+	b, _ = s.ByID(b.ID)
+	if got := states(b); got != "aaa" {
+		t.Errorf("states = %s, want aaa", got)
+	}
+}
+
+func TestStore_SendOfflineKeepsItemsQueued(t *testing.T) {
+	s := openStore(t, filepath.Join(t.TempDir(), "b.db"))
+	b := newBatch(t, s, 2)
+	for pos := 1; pos <= 2; pos++ {
+		s.SetItemState(b.ID, pos, ItemApproved, "")
+	}
+	_, err := s.Send(b.ID, func(Item) error { return &offline.Error{Feature: "sending"} })
+	if !errors.Is(err, offline.ErrOffline) {
+		t.Fatalf("Send = %v, want the offline error", err)
+	}
+	b, _ = s.ByID(b.ID)
+	if got := states(b); got != "aa" {
+		t.Errorf("states = %s, want both still approved", got)
+	}
+}
+
+func TestStore_SendLeavesInterruptedItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "b.db")
+	s := openStore(t, path)
+	b := newBatch(t, s, 3)
+	for pos := 1; pos <= 3; pos++ {
+		s.SetItemState(b.ID, pos, ItemApproved, "")
+	}
+
+	// The process dies while handing the second item to the mailer.
+	func() {
+		defer func() { recover() }()
+		s.Send(b.ID, func(it Item) error {
+			if it.Position == 2 {
+				panic("killed")
+			}
+			return nil
+		})
+	}()
+
+	s = openStore(t, path)
+	var sent []string
+	sum, err := s.Send(b.ID, func(it Item) error {
+		sent = append(sent, it.JobID)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(sent) != "[job-3]" {
+		t.Errorf("resumed run sent %v, want only job-3", sent)
+	}
+	if sum.Sending != 1 || sum.Sent != 2 || !strings.Contains(sum.String(), "1 interrupted while sending") {
+		t.Errorf("summary = %+v (%s)", sum, sum)
+	}
+
+	if err := s.Retry(b.ID); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ = s.ByID(b.ID); b.Items[1].State != ItemSending {
+		t.Errorf("retrying all failed items resent the interrupted one: %s", b.Items[1].State)
+	}
+	if err := s.Retry(b.ID, 2); err != nil {
+		t.Fatalf("retrying the interrupted item: %v", err)
+	}
+	if b, _ = s.ByID(b.ID); b.Items[1].State != ItemApproved {
+		t.Errorf("item 2 = %s after retry, want approved", b.Items[1].State)
+	}
+}
+
+func TestStore_SetItemStateErrors(t *testing.T) {
+	s := openStore(t, filepath.Join(t.TempDir(), "b.db"))
+	b := newBatch(t, s, 1)
+	if err := s.SetItemState(b.ID, 2, ItemApproved, ""); err == nil || !strings.Contains(err.Error(), "no item 2") {
+		t.Errorf("missing item: %v", err)
+	}
+	if err := s.SetItemState(b.ID+1, 1, ItemApproved, ""); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("missing batch: %v", err)
+	}
+	if _, err := s.Abandon(b.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetItemState(b.ID, 1, ItemApproved, ""); !errors.Is(err, ErrTransition) {
+		t.Errorf("closed batch: %v", err)
+	}
+}
+
+func TestStore_KeepsCc(t *testing.T) {
+	s := openStore(t, filepath.Join(t.TempDir(), "batch.db"))
+	b, err := s.Create("default", []Item{
+		{JobID: "a", To: "jobs@acme.com", Cc: []string{"maria@acme.com", "tom@acme.com"}},
+		{JobID: "b", To: "hr@globex.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.ByID(b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cc := got.Items[0].Cc; len(cc) != 2 || cc[0] != "maria@acme.com" || cc[1] != "tom@acme.com" {
+		t.Errorf("first item Cc = %q", cc)
+	}
+	if cc := got.Items[1].Cc; cc != nil {
+		t.Errorf("second item Cc = %q, want none", cc)
+	}
+}
@@ -0,0 +1,152 @@
+// Package batch persists bulk sends so a batch interrupted part-way (SMTP
+// outage, crash, Ctrl-C) can be reopened and resumed where it stopped.
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ItemState tracks one application within a batch.
+type ItemState string
+
+const (
+	ItemPending  ItemState = "pending"  // drafted, awaiting review
+	ItemApproved ItemState = "approved" // queued to send
+	// ItemSending is saved just before an item is handed to the mailer.
+	// Found on a later run, the send was interrupted and the email may or
+	// may not be out, so it is left for the user to retry or skip.
+	ItemSending ItemState = "sending"
+	ItemSent    ItemState = "sent"
+	ItemFailed  ItemState = "failed" // send attempted; Error says why
+	ItemSkipped ItemState = "skipped"
+)
+
+// State tracks the batch as a whole.
+type State string
+
+const (
+	StateOpen      State = "open"
+	StateCompleted State = "completed" // every item sent or skipped
+	StateAbandoned State = "abandoned"
+)
+
+// ErrTransition is returned for a state change the machine does not allow.
+var ErrTransition = errors.New("invalid state transition")
+
+var itemTransitions = map[ItemState][]ItemState{
+	ItemPending:  {ItemApproved, ItemSkipped},
+	ItemApproved: {ItemSending, ItemPending, ItemSkipped},
+	ItemSending:  {ItemSent, ItemFailed, ItemApproved, ItemSkipped},
+	ItemFailed:   {ItemApproved, ItemSkipped},
+	ItemSkipped:  {ItemPending},
+	ItemSent:     nil, // final: the email is out
+}
+
+// CanTransition reports whether an item may move from s to to.
+func (s ItemState) CanTransition(to ItemState) bool {
+	for _, next := range itemTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Item is one drafted application in a batch.
+type Item struct {
+	ID        int64     `json:"id"`
+	BatchID   int64     `json:"batch_id"`
+	Position  int       `json:"position"` // 1-based send order
+	JobID     string    `json:"job_id"`
+	To        string    `json:"to"`
+	Cc        []string  `json:"cc,omitempty"`
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+	DraftPath string    `json:"draft_path"` // the draft in the outbox
+	State     ItemState `json:"state"`
+	Error     string    `json:"error,omitempty"` // last send failure
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Batch is an ordered set of applications sent together.
+type Batch struct {
+	ID        int64      `json:"id"`
+	ProfileID string     `json:"profile_id"`
+	State     State      `json:"state"`
+	CreatedAt time.Time  `json:"created_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+	Items     []Item     `json:"items"`
+}
+
+// Summary counts items by state.
+type Summary struct {
+	Total    int `json:"total"`
+	Pending  int `json:"pending"`
+	Approved int `json:"approved"`
+	Sending  int `json:"sending,omitempty"`
+	Sent     int `json:"sent"`
+	Failed   int `json:"failed"`
+	Skipped  int `json:"skipped"`
+}
+
+func (s Summary) String() string {
+	out := fmt.Sprintf("%d sent, %d failed, %d skipped, %d approved, %d pending of %d",
+		s.Sent, s.Failed, s.Skipped, s.Approved, s.Pending, s.Total)
+	if s.Sending > 0 {
+		out += fmt.Sprintf(" (%d interrupted while sending)", s.Sending)
+	}
+	return out
+}
+
+// Summary counts b's items by state.
+func (b *Batch) Summary() Summary {
+	s := Summary{Total: len(b.Items)}
+	for _, it := range b.Items {
+		switch it.State {
+		case ItemPending:
+			s.Pending++
+		case ItemApproved:
+			s.Approved++
+		case ItemSending:
+			s.Sending++
+		case ItemSent:
+			s.Sent++
+		case ItemFailed:
+			s.Failed++
+		case ItemSkipped:
+			s.Skipped++
+		}
+	}
+	return s
+}
+
+// Finished reports whether nothing is left to send: every item was sent or
+// skipped.
+func (b *Batch) Finished() bool {
+	s := b.Summary()
+	return s.Sent+s.Skipped == s.Total
+}
+
+// Item returns the item at a 1-based position.
+func (b *Batch) Item(pos int) (*Item, bool) {
+	for i := range b.Items {
+		if b.Items[i].Position == pos {
+			return &b.Items[i], true
+		}
+	}
+	return nil, false
+}
+
+// canClose checks a batch transition. Closed batches are final, and only
+// a finished batch counts as completed.
+func (b *Batch) canClose(to State) error {
+	if b.State != StateOpen {
+		return fmt.Errorf("batch %d is %s: %w", b.ID, b.State, ErrTransition)
+	}
+	if to == StateCompleted && !b.Finished() {
+		return fmt.Errorf("batch %d has unsent items (%s): %w", b.ID, b.Summary(), ErrTransition)
+	}
+	return nil
+}
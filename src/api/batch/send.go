@@ -0,0 +1,85 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+
+	"sprayer/src/api/offline"
+)
+
+// SendFunc delivers one item.
+type SendFunc func(Item) error
+
+// Send delivers the batch's approved items in order, persisting each result
+// before moving on. It stops at the first failure so the batch shows where
+// it stopped; later items stay approved for the next run. Offline, the
+// item is not marked failed, since nothing was tried.
+//
+// Each item is saved as sending before send is called, so one a crash
+// interrupted stays sending and is never sent again unasked; Retry or a
+// skip settles it.
+func (s *Store) Send(id int64, send SendFunc) (Summary, error) {
+	b, err := s.ByID(id)
+	if err != nil {
+		return Summary{}, err
+	}
+	if b.State != StateOpen {
+		return b.Summary(), fmt.Errorf("batch %d is %s: %w", id, b.State, ErrTransition)
+	}
+
+	for _, it := range b.Items {
+		if it.State != ItemApproved {
+			continue
+		}
+		if err := s.SetItemState(id, it.Position, ItemSending, ""); err != nil {
+			return Summary{}, err
+		}
+		if sendErr := send(it); sendErr != nil {
+			if errors.Is(sendErr, offline.ErrOffline) {
+				// Nothing was attempted; the item goes back in the queue.
+				if err := s.SetItemState(id, it.Position, ItemApproved, ""); err != nil {
+					return Summary{}, err
+				}
+				return s.summary(id), sendErr
+			}
+			if err := s.SetItemState(id, it.Position, ItemFailed, sendErr.Error()); err != nil {
+				return Summary{}, err
+			}
+			return s.summary(id), fmt.Errorf("item %d (%s): %w", it.Position, it.To, sendErr)
+		}
+		if err := s.SetItemState(id, it.Position, ItemSent, ""); err != nil {
+			return Summary{}, err
+		}
+	}
+	return s.summary(id), nil
+}
+
+// Retry queues failed items for sending again: those at positions, or all
+// failed items when none are given.
+func (s *Store) Retry(id int64, positions ...int) error {
+	b, err := s.ByID(id)
+	if err != nil {
+		return err
+	}
+	if len(positions) == 0 {
+		for _, it := range b.Items {
+			if it.State == ItemFailed {
+				positions = append(positions, it.Position)
+			}
+		}
+	}
+	for _, pos := range positions {
+		if err := s.SetItemState(id, pos, ItemApproved, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) summary(id int64) Summary {
+	b, err := s.ByID(id)
+	if err != nil {
+		return Summary{}
+	}
+	return b.Summary()
+}
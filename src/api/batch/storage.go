@@ -0,0 +1,249 @@
+package batch
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// Store persists batches and their items.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore wraps a database connection for batch storage.
+func NewStore(db *sql.DB) (*Store, error) {
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS batches (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			profile_id TEXT,
+			state      TEXT,
+			created_at DATETIME,
+			closed_at  DATETIME,
+			sent       INTEGER DEFAULT 0,
+			failed     INTEGER DEFAULT 0,
+			skipped    INTEGER DEFAULT 0
+		);
+		CREATE TABLE IF NOT EXISTS batch_items (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			batch_id   INTEGER NOT NULL,
+			position   INTEGER,
+			job_id     TEXT,
+			recipient  TEXT,
+			subject    TEXT,
+			body       TEXT,
+			draft_path TEXT,
+			state      TEXT,
+			error      TEXT DEFAULT '',
+			updated_at DATETIME,
+			UNIQUE (batch_id, position)
+		);`)
+	if err != nil {
+		return err
+	}
+	return job.EnsureColumns(db, "batch_items", []job.Column{
+		{Name: "cc", Decl: "TEXT DEFAULT ''"},
+	})
+}
+
+// Create stores a new open batch. Items are numbered in the order given
+// and start out pending.
+func (s *Store) Create(profileID string, items []Item) (*Batch, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	res, err := tx.Exec(`INSERT INTO batches (profile_id, state, created_at) VALUES (?, ?, ?)`,
+		profileID, StateOpen, now)
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	for i, it := range items {
+		_, err := tx.Exec(`
+			INSERT INTO batch_items (batch_id, position, job_id, recipient, cc, subject, body, draft_path, state, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, i+1, it.JobID, it.To, strings.Join(it.Cc, ","), it.Subject, it.Body, it.DraftPath, ItemPending, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.ByID(id)
+}
+
+// ByID loads a batch with its items in send order.
+func (s *Store) ByID(id int64) (*Batch, error) {
+	batches, err := s.query(`SELECT id, profile_id, state, created_at, closed_at FROM batches WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(batches) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &batches[0], nil
+}
+
+// Open returns batches that were neither completed nor abandoned, so an
+// interrupted run can be picked up after a restart.
+func (s *Store) Open() ([]Batch, error) {
+	return s.query(`SELECT id, profile_id, state, created_at, closed_at FROM batches
+		WHERE state = ? ORDER BY created_at, id`, StateOpen)
+}
+
+// SetItemState moves the item at pos through the state machine. errMsg is
+// kept for failed items and cleared otherwise. Only the item and its
+// batch's state are read, so a send loop can call it for every item.
+func (s *Store) SetItemState(batchID int64, pos int, to ItemState, errMsg string) error {
+	var state State
+	var itemID sql.NullInt64
+	var from sql.NullString
+	err := s.db.QueryRow(`
+		SELECT b.state, i.id, i.state FROM batches b
+		LEFT JOIN batch_items i ON i.batch_id = b.id AND i.position = ?
+		WHERE b.id = ?`, pos, batchID).Scan(&state, &itemID, &from)
+	if err != nil {
+		return err
+	}
+	if state != StateOpen {
+		return fmt.Errorf("batch %d is %s: %w", batchID, state, ErrTransition)
+	}
+	if !itemID.Valid {
+		return fmt.Errorf("batch %d has no item %d", batchID, pos)
+	}
+	if it := ItemState(from.String); !it.CanTransition(to) {
+		return fmt.Errorf("item %d: %s -> %s: %w", pos, it, to, ErrTransition)
+	}
+	if to != ItemFailed {
+		errMsg = ""
+	}
+	_, err = s.db.Exec(`UPDATE batch_items SET state = ?, error = ?, updated_at = ? WHERE id = ?`,
+		to, errMsg, time.Now().UTC(), itemID.Int64)
+	return err
+}
+
+// Complete closes a finished batch.
+func (s *Store) Complete(id int64) (Summary, error) { return s.close(id, StateCompleted) }
+
+// Abandon closes a batch whatever its items' states.
+func (s *Store) Abandon(id int64) (Summary, error) { return s.close(id, StateAbandoned) }
+
+// close records the final state and a summary row for the history.
+func (s *Store) close(id int64, to State) (Summary, error) {
+	b, err := s.ByID(id)
+	if err != nil {
+		return Summary{}, err
+	}
+	if err := b.canClose(to); err != nil {
+		return Summary{}, err
+	}
+	sum := b.Summary()
+	_, err = s.db.Exec(`UPDATE batches SET state = ?, closed_at = ?, sent = ?, failed = ?, skipped = ? WHERE id = ?`,
+		to, time.Now().UTC(), sum.Sent, sum.Failed, sum.Skipped, id)
+	return sum, err
+}
+
+// HistoryEntry is the summary recorded when a batch was closed.
+type HistoryEntry struct {
+	BatchID  int64     `json:"batch_id"`
+	State    State     `json:"state"`
+	ClosedAt time.Time `json:"closed_at"`
+	Sent     int       `json:"sent"`
+	Failed   int       `json:"failed"`
+	Skipped  int       `json:"skipped"`
+}
+
+// History lists closed batches, most recent first.
+func (s *Store) History() ([]HistoryEntry, error) {
+	rows, err := s.db.Query(`SELECT id, state, closed_at, sent, failed, skipped FROM batches
+		WHERE state != ? ORDER BY closed_at DESC, id DESC`, StateOpen)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []HistoryEntry
+	for rows.Next() {
+		var h HistoryEntry
+		if err := rows.Scan(&h.BatchID, &h.State, &h.ClosedAt, &h.Sent, &h.Failed, &h.Skipped); err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) query(q string, args ...any) ([]Batch, error) {
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	var batches []Batch
+	for rows.Next() {
+		var b Batch
+		var closed sql.NullTime
+		if err := rows.Scan(&b.ID, &b.ProfileID, &b.State, &b.CreatedAt, &closed); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if closed.Valid {
+			t := closed.Time
+			b.ClosedAt = &t
+		}
+		batches = append(batches, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range batches {
+		if batches[i].Items, err = s.items(batches[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return batches, nil
+}
+
+func (s *Store) items(batchID int64) ([]Item, error) {
+	rows, err := s.db.Query(`
+		SELECT id, batch_id, position, job_id, recipient, cc, subject, body, draft_path, state, error, updated_at
+		FROM batch_items WHERE batch_id = ? ORDER BY position`, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []Item
+	for rows.Next() {
+		var it Item
+		var cc string
+		if err := rows.Scan(&it.ID, &it.BatchID, &it.Position, &it.JobID, &it.To, &cc, &it.Subject,
+			&it.Body, &it.DraftPath, &it.State, &it.Error, &it.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if cc != "" {
+			it.Cc = strings.Split(cc, ",")
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,99 @@
+// Package brand holds the colors, font and name that sprayer's rendered
+// documents share, so a report or CV looks like it came from the same
+// person as the rest of their material.
+//
+// The settings live in the .env file next to the SMTP and LLM ones.
+// Unset colors default to the TUI palette.
+package brand
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	EnvPrimary = "SPRAYER_BRAND_PRIMARY"
+	EnvAccent  = "SPRAYER_BRAND_ACCENT"
+	EnvFont    = "SPRAYER_BRAND_FONT"
+	EnvName    = "SPRAYER_BRAND_NAME"
+	EnvTagline = "SPRAYER_BRAND_TAGLINE"
+)
+
+// The TUI's accent and cyan, used when no colors are configured.
+const (
+	DefaultPrimary = "#7b61ff"
+	DefaultAccent  = "#4cc9f0"
+)
+
+// Font is a type family preference. Documents map it to what their
+// format offers.
+type Font string
+
+const (
+	FontSerif Font = "serif"
+	FontSans  Font = "sans"
+	FontMono  Font = "mono"
+)
+
+// Brand is the shared look of rendered documents.
+type Brand struct {
+	Primary string // "#rrggbb" or "#rgb"
+	Accent  string
+	Font    Font
+	Name    string
+	Tagline string
+}
+
+// Default is the brand used when nothing is configured.
+func Default() Brand {
+	return Brand{Primary: DefaultPrimary, Accent: DefaultAccent, Font: FontSans}
+}
+
+// FromEnv reads the brand settings, filling unset ones from Default. An
+// invalid color or font is an error rather than a silently ugly document.
+func FromEnv() (Brand, error) {
+	b := Default()
+	if v := strings.TrimSpace(os.Getenv(EnvPrimary)); v != "" {
+		b.Primary = v
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvAccent)); v != "" {
+		b.Accent = v
+	}
+	if v := strings.TrimSpace(os.Getenv(EnvFont)); v != "" {
+		b.Font = Font(strings.ToLower(v))
+	}
+	b.Name = strings.TrimSpace(os.Getenv(EnvName))
+	b.Tagline = strings.TrimSpace(os.Getenv(EnvTagline))
+	return b, b.Validate()
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// ValidColor reports whether c is a "#rrggbb" or "#rgb" hex color.
+func ValidColor(c string) bool { return hexColor.MatchString(c) }
+
+// Validate checks the colors are hex strings and the font is known.
+func (b Brand) Validate() error {
+	for _, c := range []struct{ env, value string }{{EnvPrimary, b.Primary}, {EnvAccent, b.Accent}} {
+		if !ValidColor(c.value) {
+			return fmt.Errorf("%s: %q is not a hex color like #7b61ff", c.env, c.value)
+		}
+	}
+	switch b.Font {
+	case FontSerif, FontSans, FontMono:
+		return nil
+	}
+	return fmt.Errorf("%s: %q is not one of serif, sans, mono", EnvFont, b.Font)
+}
+
+// HTML returns a validated color as six upper-case hex digits without the
+// hash, the form LaTeX's xcolor HTML model takes. "#abc" becomes "AABBCC".
+func HTML(color string) string {
+	h := strings.ToUpper(strings.TrimPrefix(color, "#"))
+	if len(h) == 3 {
+		h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]})
+	}
+	return h
+}
@@ -0,0 +1,57 @@
+package brand
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	for _, env := range []string{EnvPrimary, EnvAccent, EnvFont, EnvName, EnvTagline} {
+		t.Setenv(env, "")
+	}
+	b, err := FromEnv()
+	if err != nil || b != Default() {
+		t.Fatalf("unset brand = %+v, %v; want the defaults", b, err)
+	}
+
+	t.Setenv(EnvPrimary, "#0A3D62")
+	t.Setenv(EnvAccent, " #e58 ")
+	t.Setenv(EnvFont, "Serif")
+	t.Setenv(EnvName, "Jane Doe")
+	t.Setenv(EnvTagline, "Backend engineer")
+	b, err = FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Brand{Primary: "#0A3D62", Accent: "#e58", Font: FontSerif, Name: "Jane Doe", Tagline: "Backend engineer"}
+	if b != want {
+		t.Errorf("brand = %+v, want %+v", b, want)
+	}
+}
+
+func TestFromEnv_RejectsInvalid(t *testing.T) {
+	for env, value := range map[string]string{
+		EnvPrimary: "teal",
+		EnvAccent:  "#12345",
+		EnvFont:    "comic",
+	} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(EnvPrimary, "")
+			t.Setenv(EnvAccent, "")
+			t.Setenv(EnvFont, "")
+			t.Setenv(env, value)
+			_, err := FromEnv()
+			if err == nil || !strings.Contains(err.Error(), env) {
+				t.Errorf("%s=%q: err = %v, want one naming the setting", env, value, err)
+			}
+		})
+	}
+}
+
+func TestHTML(t *testing.T) {
+	for in, want := range map[string]string{"#7b61ff": "7B61FF", "#abc": "AABBCC"} {
+		if got := HTML(in); got != want {
+			t.Errorf("HTML(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
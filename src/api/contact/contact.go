@@ -0,0 +1,327 @@
+// Package contact keeps the recruiters and referrers met while applying,
+// linked to the companies they work at and the applications they were
+// part of.
+package contact
+
+import (
+	"database/sql"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/job"
+)
+
+// Source tells how a contact was first recorded.
+type Source string
+
+const (
+	SourceReply    Source = "reply"    // sent a reply to an application
+	SourceReferral Source = "referral" // entered by hand as a referral
+)
+
+// Contact is a person, identified by email address.
+type Contact struct {
+	ID              int64     `json:"id"`
+	Name            string    `json:"name"`
+	Email           string    `json:"email"`
+	Company         string    `json:"company,omitempty"`
+	Source          Source    `json:"source"`
+	Notes           string    `json:"notes,omitempty"`
+	LastInteraction time.Time `json:"last_interaction"`
+}
+
+// Label is the contact's name, or the address when the name is unknown.
+func (c Contact) Label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return c.Email
+}
+
+// FromAddress builds a contact from an address header value such as
+// "Jane Doe <jane@acme.com>".
+func FromAddress(header string, source Source, at time.Time) (Contact, error) {
+	addr, err := mail.ParseAddress(header)
+	if err != nil {
+		return Contact{}, fmt.Errorf("contact address %q: %w", header, err)
+	}
+	return Contact{Name: addr.Name, Email: addr.Address, Source: source, LastInteraction: at}, nil
+}
+
+// Store persists contacts alongside applications.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore wraps a database connection for contact storage.
+func NewStore(db *sql.DB) (*Store, error) {
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS contacts (
+			id               INTEGER PRIMARY KEY AUTOINCREMENT,
+			name             TEXT DEFAULT '',
+			email            TEXT NOT NULL UNIQUE,
+			company          TEXT DEFAULT '',
+			company_key      TEXT DEFAULT '',
+			source           TEXT,
+			notes            TEXT DEFAULT '',
+			last_interaction DATETIME
+		);
+		CREATE INDEX IF NOT EXISTS idx_contacts_company_key ON contacts(company_key);
+		CREATE TABLE IF NOT EXISTS contact_applications (
+			contact_id     INTEGER NOT NULL,
+			application_id INTEGER NOT NULL,
+			PRIMARY KEY (contact_id, application_id)
+		);`)
+	return err
+}
+
+const columns = `id, name, email, company, source, notes, last_interaction`
+
+// Save records c, or updates the contact with the same address. An
+// existing contact keeps its source; blank fields are filled in, notes
+// are appended and the later interaction wins. c is reloaded from the
+// stored row.
+func (s *Store) Save(c *Contact) error {
+	c.Email = strings.ToLower(strings.TrimSpace(c.Email))
+	if c.Email == "" {
+		return fmt.Errorf("contact needs an email address")
+	}
+	if c.LastInteraction.IsZero() {
+		c.LastInteraction = time.Now()
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO contacts (name, email, company, company_key, source, notes, last_interaction)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (email) DO UPDATE SET
+			name = CASE WHEN name = '' THEN excluded.name ELSE name END,
+			company = CASE WHEN company = '' THEN excluded.company ELSE company END,
+			company_key = CASE WHEN company = '' THEN excluded.company_key ELSE company_key END,
+			notes = CASE
+				WHEN excluded.notes = '' OR instr(notes, excluded.notes) > 0 THEN notes
+				WHEN notes = '' THEN excluded.notes
+				ELSE notes || char(10) || excluded.notes END,
+			last_interaction = MAX(last_interaction, excluded.last_interaction)`,
+		strings.TrimSpace(c.Name), c.Email, strings.TrimSpace(c.Company), job.NormalizeCompany(c.Company),
+		c.Source, strings.TrimSpace(c.Notes), c.LastInteraction.UTC())
+	if err != nil {
+		return err
+	}
+	stored, err := s.ByEmail(c.Email)
+	if err != nil {
+		return err
+	}
+	*c = *stored
+	return nil
+}
+
+// Link associates a contact with an application.
+func (s *Store) Link(contactID, applicationID int64) error {
+	_, err := s.db.Exec(`INSERT OR IGNORE INTO contact_applications (contact_id, application_id) VALUES (?, ?)`,
+		contactID, applicationID)
+	return err
+}
+
+// Applications returns the IDs of the applications a contact is linked
+// to, oldest first.
+func (s *Store) Applications(contactID int64) ([]int64, error) {
+	rows, err := s.db.Query(`SELECT application_id FROM contact_applications
+		WHERE contact_id = ? ORDER BY application_id`, contactID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ByID loads one contact.
+func (s *Store) ByID(id int64) (*Contact, error) {
+	return s.one(`SELECT `+columns+` FROM contacts WHERE id = ?`, id)
+}
+
+// ByEmail loads the contact with address email, in any case.
+func (s *Store) ByEmail(email string) (*Contact, error) {
+	return s.one(`SELECT `+columns+` FROM contacts WHERE email = ?`, strings.ToLower(strings.TrimSpace(email)))
+}
+
+// All returns every contact, most recently in touch first.
+func (s *Store) All() ([]Contact, error) {
+	return s.query(`SELECT ` + columns + ` FROM contacts ORDER BY last_interaction DESC, id`)
+}
+
+// Search finds contacts whose name, address, company or notes contain
+// text, case-insensitively.
+func (s *Store) Search(text string) ([]Contact, error) {
+	like := "%" + strings.TrimSpace(text) + "%"
+	return s.query(`SELECT `+columns+` FROM contacts
+		WHERE name LIKE ? OR email LIKE ? OR company LIKE ? OR notes LIKE ?
+		ORDER BY last_interaction DESC, id`, like, like, like, like)
+}
+
+// AtCompany returns the contacts at company, matched by normalized name
+// so "Acme, Inc." finds people recorded at "ACME".
+func (s *Store) AtCompany(company string) ([]Contact, error) {
+	key := job.NormalizeCompany(company)
+	if key == "" {
+		return nil, nil
+	}
+	return s.query(`SELECT `+columns+` FROM contacts WHERE company_key = ?
+		ORDER BY last_interaction DESC, id`, key)
+}
+
+// Duplicates groups contacts that share a name under different
+// addresses, candidates for Merge.
+func (s *Store) Duplicates() ([][]Contact, error) {
+	all, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string][]Contact)
+	var names []string
+	for _, c := range all {
+		key := strings.Join(strings.Fields(strings.ToLower(c.Name)), " ")
+		if key == "" {
+			continue
+		}
+		if len(byName[key]) == 0 {
+			names = append(names, key)
+		}
+		byName[key] = append(byName[key], c)
+	}
+	sort.Strings(names)
+	var groups [][]Contact
+	for _, n := range names {
+		if len(byName[n]) > 1 {
+			groups = append(groups, byName[n])
+		}
+	}
+	return groups, nil
+}
+
+// Merge folds contact drop into keep: keep gains drop's application
+// links, blank fields and notes, and the later interaction; drop is
+// deleted.
+func (s *Store) Merge(keepID, dropID int64) error {
+	if keepID == dropID {
+		return fmt.Errorf("cannot merge contact %d into itself", keepID)
+	}
+	keep, err := s.ByID(keepID)
+	if err != nil {
+		return fmt.Errorf("contact %d: %w", keepID, err)
+	}
+	drop, err := s.ByID(dropID)
+	if err != nil {
+		return fmt.Errorf("contact %d: %w", dropID, err)
+	}
+
+	if keep.Name == "" {
+		keep.Name = drop.Name
+	}
+	if keep.Company == "" {
+		keep.Company = drop.Company
+	}
+	if drop.Notes != "" && !strings.Contains(keep.Notes, drop.Notes) {
+		keep.Notes = strings.TrimSpace(keep.Notes + "\n" + drop.Notes)
+	}
+	if drop.LastInteraction.After(keep.LastInteraction) {
+		keep.LastInteraction = drop.LastInteraction
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`
+		INSERT OR IGNORE INTO contact_applications (contact_id, application_id)
+		SELECT ?, application_id FROM contact_applications WHERE contact_id = ?`, keepID, dropID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM contact_applications WHERE contact_id = ?`, dropID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM contacts WHERE id = ?`, dropID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE contacts SET name = ?, company = ?, company_key = ?, notes = ?, last_interaction = ?
+		WHERE id = ?`, keep.Name, keep.Company, job.NormalizeCompany(keep.Company), keep.Notes,
+		keep.LastInteraction.UTC(), keepID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) one(q string, args ...any) (*Contact, error) {
+	cs, err := s.query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(cs) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &cs[0], nil
+}
+
+func (s *Store) query(q string, args ...any) ([]Contact, error) {
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Contact
+	for rows.Next() {
+		var c Contact
+		if err := rows.Scan(&c.ID, &c.Name, &c.Email, &c.Company, &c.Source, &c.Notes, &c.LastInteraction); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// Describe says how c is known, in the light of the applications they
+// are linked to: "replied to your 2023 application", "referral: met at
+// GopherCon".
+func Describe(c Contact, apps []application.Application) string {
+	if c.Source == SourceReply && len(apps) > 0 {
+		years := make([]string, 0, len(apps))
+		seen := make(map[string]bool)
+		for _, a := range apps {
+			y := a.AppliedAt.Format("2006")
+			if !seen[y] {
+				seen[y] = true
+				years = append(years, y)
+			}
+		}
+		noun := "application"
+		if len(apps) > 1 {
+			noun = "applications"
+		}
+		return fmt.Sprintf("replied to your %s %s", strings.Join(years, " and "), noun)
+	}
+	desc := string(c.Source)
+	if note, _, _ := strings.Cut(c.Notes, "\n"); note != "" {
+		desc += ": " + note
+	}
+	return desc
+}
@@ -0,0 +1,172 @@
+package contact
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"sprayer/src/api/application"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	s, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+var (
+	y2023 = time.Date(2023, 5, 2, 9, 0, 0, 0, time.UTC)
+	y2024 = time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+)
+
+func TestSave_DedupsByEmail(t *testing.T) {
+	s := openTestStore(t)
+	first := &Contact{Email: "Jane@Acme.com ", Source: SourceReply, LastInteraction: y2024}
+	if err := s.Save(first); err != nil {
+		t.Fatal(err)
+	}
+	again := &Contact{Name: "Jane Doe", Email: "jane@acme.com", Company: "Acme", Source: SourceReferral,
+		Notes: "met at GopherCon", LastInteraction: y2023}
+	if err := s.Save(again); err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != first.ID {
+		t.Fatalf("same address saved twice: %d and %d", first.ID, again.ID)
+	}
+	want := Contact{ID: first.ID, Name: "Jane Doe", Email: "jane@acme.com", Company: "Acme", Source: SourceReply,
+		Notes: "met at GopherCon", LastInteraction: y2024}
+	if !reflect.DeepEqual(*again, want) {
+		t.Errorf("merged contact\n got %+v\nwant %+v", *again, want)
+	}
+
+	// Known fields are kept; new notes are appended once.
+	for range 2 {
+		if err := s.Save(&Contact{Name: "J. Doe", Email: "jane@acme.com", Company: "Globex", Notes: "hiring for platform"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, _ := s.ByEmail("JANE@acme.com")
+	if got.Name != "Jane Doe" || got.Company != "Acme" || got.Notes != "met at GopherCon\nhiring for platform" {
+		t.Errorf("update overwrote known fields: %+v", got)
+	}
+
+	if err := s.Save(&Contact{Name: "No Address"}); err == nil {
+		t.Error("a contact without an address should be refused")
+	}
+}
+
+func TestAtCompany_Normalizes(t *testing.T) {
+	s := openTestStore(t)
+	for _, c := range []Contact{
+		{Name: "Jane", Email: "jane@acme.com", Company: "ACME", LastInteraction: y2023},
+		{Name: "Raj", Email: "raj@acme.io", Company: "The Acme Company, Inc.", LastInteraction: y2024},
+		{Name: "Bo", Email: "bo@acmelabs.com", Company: "Acme Labs"},
+		{Name: "Nobody", Email: "n@x.com"},
+	} {
+		if err := s.Save(&c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := s.AtCompany("Acme, Inc.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := labels(got); !reflect.DeepEqual(names, []string{"Raj", "Jane"}) {
+		t.Errorf("AtCompany(Acme, Inc.) = %v, want the two Acme contacts, latest first", names)
+	}
+	if got, _ := s.AtCompany(""); len(got) != 0 {
+		t.Errorf("blank company matched %v", labels(got))
+	}
+}
+
+func TestMerge(t *testing.T) {
+	s := openTestStore(t)
+	keep := &Contact{Name: "Jane Doe", Email: "jane@acme.com", LastInteraction: y2023}
+	drop := &Contact{Name: "jane doe", Email: "jane.doe@gmail.com", Company: "Acme", Notes: "prefers calls", LastInteraction: y2024}
+	other := &Contact{Name: "Raj", Email: "raj@acme.com"}
+	for _, c := range []*Contact{keep, drop, other} {
+		if err := s.Save(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Link(keep.ID, 1)
+	s.Link(drop.ID, 1)
+	s.Link(drop.ID, 2)
+
+	groups, err := s.Duplicates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 || len(groups[0]) != 2 {
+		t.Fatalf("Duplicates = %v, want the two Jane Does", groups)
+	}
+
+	if err := s.Merge(keep.ID, drop.ID); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.ByID(keep.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Company != "Acme" || got.Notes != "prefers calls" || !got.LastInteraction.Equal(y2024) || got.Email != "jane@acme.com" {
+		t.Errorf("merged contact = %+v", got)
+	}
+	if ids, _ := s.Applications(keep.ID); !reflect.DeepEqual(ids, []int64{1, 2}) {
+		t.Errorf("merged links = %v", ids)
+	}
+	if _, err := s.ByID(drop.ID); err != sql.ErrNoRows {
+		t.Errorf("dropped contact still there: %v", err)
+	}
+	if at, _ := s.AtCompany("acme"); len(at) != 1 {
+		t.Errorf("merged company not indexed: %v", labels(at))
+	}
+	if err := s.Merge(keep.ID, keep.ID); err == nil {
+		t.Error("merging a contact into itself should fail")
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	reply := Contact{Source: SourceReply}
+	apps := []application.Application{{AppliedAt: y2023}}
+	if got := Describe(reply, apps); got != "replied to your 2023 application" {
+		t.Errorf("got %q", got)
+	}
+	apps = append(apps, application.Application{AppliedAt: y2024}, application.Application{AppliedAt: y2024})
+	if got := Describe(reply, apps); got != "replied to your 2023 and 2024 applications" {
+		t.Errorf("got %q", got)
+	}
+	ref := Contact{Source: SourceReferral, Notes: "met at GopherCon\nlikes Rust"}
+	if got := Describe(ref, nil); got != "referral: met at GopherCon" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestFromAddress(t *testing.T) {
+	c, err := FromAddress(`"Doe, Jane" <jane@acme.com>`, SourceReply, y2024)
+	if err != nil || c.Name != "Doe, Jane" || c.Email != "jane@acme.com" || c.Source != SourceReply {
+		t.Errorf("FromAddress = %+v, %v", c, err)
+	}
+	if _, err := FromAddress("not an address", SourceReply, y2024); err == nil {
+		t.Error("garbage should not parse")
+	}
+}
+
+func labels(cs []Contact) []string {
+	out := make([]string, len(cs))
+	for i, c := range cs {
+		out[i] = c.Label()
+	}
+	return out
+}
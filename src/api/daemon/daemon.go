@@ -0,0 +1,208 @@
+// Package daemon scrapes on a schedule and tells a notification target
+// about the best new jobs each run found.
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/notify"
+	"sprayer/src/api/power"
+)
+
+// DefaultEvery is how often a Daemon scrapes when Every is unset.
+const DefaultEvery = 6 * time.Hour
+
+// SummaryJobs is how many jobs a Summary lists at most.
+const SummaryJobs = 10
+
+// ScrapeFunc runs one scrape to the end and returns the jobs it saved.
+// Cancelling ctx should stop the scrape early and still save what it
+// found.
+type ScrapeFunc func(ctx context.Context) ([]job.Job, error)
+
+// Daemon scrapes every Every until its context ends. A run still going
+// when the next is due is left to finish and the due run is skipped.
+type Daemon struct {
+	Scrape    ScrapeFunc
+	Every     time.Duration
+	MinScore  int // jobs scoring less are left out of summaries
+	Profile   string
+	Notifiers []Notifier
+
+	// FollowUps lists the follow-ups due at now. Each run sends those
+	// fallen due since the one before to the webhook; nil sends none.
+	FollowUps func(now time.Time) ([]job.Job, error)
+
+	// Power is read before each scheduled run. While it says to pause
+	// background work the run is skipped, and the wait until the next is
+	// stretched; nil never holds anything back.
+	Power func() power.Decision
+
+	// Logf reports each run and failure; nil discards them.
+	Logf func(format string, args ...any)
+
+	mu      sync.Mutex
+	running bool
+	checked time.Time // when follow-ups were last read
+}
+
+// Run scrapes once straight away and then every Every until ctx is
+// cancelled, skipping runs and waiting longer while Power holds
+// background work back. It returns once the run in progress, if any, has
+// saved its jobs.
+func (d *Daemon) Run(ctx context.Context) {
+	every := d.Every
+	if every <= 0 {
+		every = DefaultEvery
+	}
+	var wg sync.WaitGroup
+	start := func() {
+		if !d.begin() {
+			d.logf("Previous run still going; skipping this one.")
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer d.end()
+			d.run(ctx)
+		}()
+	}
+
+	t := time.NewTimer(0)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-t.C:
+		}
+		dec := d.power()
+		if dec.PauseBackground() {
+			d.logf("Skipping this run: %s.", dec.Reason())
+		} else {
+			start()
+		}
+		t.Reset(dec.Interval(every))
+	}
+}
+
+// power decides what to hold back now.
+func (d *Daemon) power() power.Decision {
+	if d.Power == nil {
+		return power.Decision{}
+	}
+	return d.Power()
+}
+
+// RunOnce scrapes once and notifies, unless a run is already going.
+func (d *Daemon) RunOnce(ctx context.Context) error {
+	if !d.begin() {
+		return fmt.Errorf("a run is already going")
+	}
+	defer d.end()
+	return d.run(ctx)
+}
+
+func (d *Daemon) begin() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.running {
+		return false
+	}
+	d.running = true
+	return true
+}
+
+func (d *Daemon) end() {
+	d.mu.Lock()
+	d.running = false
+	d.mu.Unlock()
+}
+
+// run scrapes and sends the summary. Notifications still go out after a
+// cancelled scrape, under a fresh deadline, since its jobs were saved.
+func (d *Daemon) run(ctx context.Context) error {
+	started := time.Now()
+	d.logf("Scraping for profile %s...", d.Profile)
+	jobs, err := d.Scrape(ctx)
+	d.notifyFollowUps(time.Now())
+	if err != nil {
+		d.logf("Scrape failed: %v", err)
+		return err
+	}
+	sum := NewSummary(d.Profile, jobs, d.MinScore)
+	sum.Started, sum.Finished = started, time.Now()
+	d.logf("Saved %d new jobs, %d at or above score %d.", sum.Found, len(sum.Jobs), d.MinScore)
+	if len(sum.Jobs) == 0 {
+		return nil
+	}
+
+	nctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+	defer cancel()
+	var firstErr error
+	for _, n := range d.Notifiers {
+		if err := n.Notify(nctx, sum); err != nil {
+			d.logf("Notifying %s: %v", n, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// notifyFollowUps reports the follow-ups fallen due since the last run,
+// or every one due on the first.
+func (d *Daemon) notifyFollowUps(now time.Time) {
+	if d.FollowUps == nil {
+		return
+	}
+	due, err := d.FollowUps(now)
+	if err != nil {
+		d.logf("Reading follow-ups: %v", err)
+		return
+	}
+	for _, j := range due {
+		if j.FollowUpAt != nil && j.FollowUpAt.After(d.checked) {
+			notify.Notify(notify.FollowUpDue(j))
+		}
+	}
+	d.checked = now
+}
+
+// notifyTimeout bounds each run's notifications.
+const notifyTimeout = 30 * time.Second
+
+func (d *Daemon) logf(format string, args ...any) {
+	if d.Logf != nil {
+		d.Logf(format, args...)
+	}
+}
+
+// Summary is what a run tells notifiers: how many new jobs it saved and
+// the best of them.
+type Summary struct {
+	Profile  string
+	Found    int
+	Jobs     []job.Job // at most SummaryJobs, best first
+	Started  time.Time
+	Finished time.Time
+}
+
+// NewSummary picks the SummaryJobs highest scoring of jobs at or above
+// minScore.
+func NewSummary(profile string, jobs []job.Job, minScore int) Summary {
+	top := job.Select(jobs, func(j job.Job) bool { return j.Score >= minScore })
+	sort.SliceStable(top, func(a, b int) bool { return top[a].Score > top[b].Score })
+	if len(top) > SummaryJobs {
+		top = top[:SummaryJobs]
+	}
+	return Summary{Profile: profile, Found: len(jobs), Jobs: top}
+}
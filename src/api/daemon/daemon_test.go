@@ -0,0 +1,207 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/notify"
+	"sprayer/src/api/power"
+)
+
+func scoredJobs(scores ...int) []job.Job {
+	jobs := make([]job.Job, len(scores))
+	for i, s := range scores {
+		jobs[i] = job.Job{ID: fmt.Sprintf("j%d", i), Title: "Go Engineer", Company: "Acme", Score: s}
+	}
+	return jobs
+}
+
+func TestNewSummary_BestAboveMinScore(t *testing.T) {
+	jobs := scoredJobs(40, 90, 10, 70, 55, 60, 61, 62, 63, 64, 65, 66, 67)
+	s := NewSummary("alice", jobs, 50)
+	if s.Found != len(jobs) {
+		t.Errorf("Found = %d, want %d", s.Found, len(jobs))
+	}
+	if len(s.Jobs) != SummaryJobs {
+		t.Fatalf("got %d jobs, want %d", len(s.Jobs), SummaryJobs)
+	}
+	if s.Jobs[0].Score != 90 || s.Jobs[1].Score != 70 || s.Jobs[9].Score != 60 {
+		t.Errorf("scores = %v..., want best first down to 60", []int{s.Jobs[0].Score, s.Jobs[1].Score, s.Jobs[9].Score})
+	}
+}
+
+type recorder struct{ got []Summary }
+
+func (r *recorder) Notify(ctx context.Context, s Summary) error {
+	r.got = append(r.got, s)
+	return nil
+}
+
+func TestRunOnce_NotifiesOnlyWithJobs(t *testing.T) {
+	rec := &recorder{}
+	jobs := scoredJobs(10)
+	d := &Daemon{
+		Scrape:    func(ctx context.Context) ([]job.Job, error) { return jobs, nil },
+		MinScore:  50,
+		Notifiers: []Notifier{rec},
+	}
+	if err := d.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.got) != 0 {
+		t.Errorf("notified with no job above the minimum score")
+	}
+	jobs = scoredJobs(80, 20)
+	if err := d.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.got) != 1 || len(rec.got[0].Jobs) != 1 || rec.got[0].Found != 2 {
+		t.Errorf("got %+v, want one summary of 1 of 2 jobs", rec.got)
+	}
+}
+
+func TestRun_SkipsOverlappingRuns(t *testing.T) {
+	var runs atomic.Int32
+	release := make(chan struct{})
+	d := &Daemon{
+		Every: 5 * time.Millisecond,
+		Scrape: func(ctx context.Context) ([]job.Job, error) {
+			runs.Add(1)
+			<-release
+			return nil, nil
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		d.Run(ctx)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	if err := d.RunOnce(ctx); err == nil {
+		t.Error("RunOnce ran alongside a run still going")
+	}
+	cancel()
+	select {
+	case <-done:
+		t.Fatal("Run returned before the run in progress finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	<-done
+	if n := runs.Load(); n != 1 {
+		t.Errorf("scraped %d times, want 1 while the first run was going", n)
+	}
+}
+
+func TestRun_PausesWhilePowerConstrained(t *testing.T) {
+	var checks, runs atomic.Int32
+	var skipped atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Daemon{
+		Every: time.Millisecond,
+		Power: func() power.Decision {
+			return power.Decision{LowBattery: checks.Add(1) <= 2}
+		},
+		Scrape: func(ctx context.Context) ([]job.Job, error) {
+			runs.Add(1)
+			cancel()
+			return nil, nil
+		},
+		Logf: func(format string, args ...any) {
+			if strings.HasPrefix(format, "Skipping") {
+				skipped.Add(1)
+			}
+		},
+	}
+	started := time.Now()
+	d.Run(ctx)
+	if runs.Load() != 1 || skipped.Load() != 2 {
+		t.Errorf("ran %d and skipped %d times, want 1 run after 2 skips", runs.Load(), skipped.Load())
+	}
+	if waited := time.Since(started); waited < 2*power.StretchFactor*time.Millisecond {
+		t.Errorf("waited %s between constrained checks, want them stretched", waited)
+	}
+}
+
+func TestRun_NotifiesAfterCancelledScrape(t *testing.T) {
+	rec := &recorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Daemon{
+		Scrape: func(ctx context.Context) ([]job.Job, error) {
+			cancel() // a SIGTERM mid-scrape: what was found is still saved
+			return scoredJobs(80), nil
+		},
+		Notifiers: []Notifier{rec},
+	}
+	d.Run(ctx)
+	if len(rec.got) != 1 {
+		t.Errorf("got %d summaries, want 1", len(rec.got))
+	}
+}
+
+func TestWebhook_PostsSummary(t *testing.T) {
+	var got WebhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	s := NewSummary("alice", scoredJobs(80, 60), 0)
+	if err := (Webhook{URL: srv.URL}).Notify(context.Background(), s); err != nil {
+		t.Fatal(err)
+	}
+	if got.Profile != "alice" || got.Found != 2 || len(got.Jobs) != 2 || got.Jobs[0].Score != 80 || got.Text == "" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestWebhook_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	if err := (Webhook{URL: srv.URL}).Notify(context.Background(), Summary{}); err == nil {
+		t.Error("want an error for a 502")
+	}
+}
+
+func TestRunOnce_PostsFollowUpsOnceDue(t *testing.T) {
+	var kinds []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e notify.Event
+		json.NewDecoder(r.Body).Decode(&e)
+		kinds = append(kinds, e.Kind+" "+e.JobID)
+	}))
+	defer srv.Close()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(notify.EnvWebhookURL, srv.URL)
+
+	due := time.Now().Add(-time.Hour)
+	followUps := []job.Job{{ID: "a", FollowUpAt: &due}}
+	d := &Daemon{
+		Scrape:    func(ctx context.Context) ([]job.Job, error) { return nil, nil },
+		FollowUps: func(time.Time) ([]job.Job, error) { return followUps, nil },
+	}
+	d.RunOnce(context.Background())
+	later := time.Now()
+	followUps = append(followUps, job.Job{ID: "b", FollowUpAt: &later})
+	time.Sleep(time.Millisecond)
+	d.RunOnce(context.Background())
+
+	want := []string{notify.EventFollowUpDue + " a", notify.EventFollowUpDue + " b"}
+	if fmt.Sprint(kinds) != fmt.Sprint(want) {
+		t.Errorf("posted %v, want %v", kinds, want)
+	}
+}
@@ -0,0 +1,154 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"sprayer/src/api/apply"
+	"sprayer/src/api/notify"
+	"sprayer/src/api/offline"
+)
+
+// Notifier sends a run's Summary somewhere.
+type Notifier interface {
+	Notify(ctx context.Context, s Summary) error
+}
+
+// Webhook POSTs each summary as JSON to URL.
+type Webhook struct {
+	URL    string
+	Client *http.Client // nil uses http.DefaultClient
+}
+
+// WebhookJob is a job as a webhook receives it.
+type WebhookJob struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Company  string `json:"company"`
+	Location string `json:"location,omitempty"`
+	Score    int    `json:"score"`
+	URL      string `json:"url"`
+}
+
+// WebhookPayload is the body of a webhook's POST.
+type WebhookPayload struct {
+	Profile  string       `json:"profile"`
+	Found    int          `json:"found"`
+	Jobs     []WebhookJob `json:"jobs"`
+	Started  time.Time    `json:"started"`
+	Finished time.Time    `json:"finished"`
+	Text     string       `json:"text"` // the summary as plain text, for chat webhooks
+}
+
+func (w Webhook) String() string { return "webhook " + w.URL }
+
+// Notify answers an error for any response but a 2xx.
+func (w Webhook) Notify(ctx context.Context, s Summary) error {
+	p := WebhookPayload{
+		Profile:  s.Profile,
+		Found:    s.Found,
+		Jobs:     make([]WebhookJob, len(s.Jobs)),
+		Started:  s.Started,
+		Finished: s.Finished,
+		Text:     s.Text(),
+	}
+	for i, j := range s.Jobs {
+		p.Jobs[i] = WebhookJob{ID: j.ID, Title: j.Title, Company: j.Company, Location: j.Location, Score: j.Score, URL: j.URL}
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if err := offline.CheckHost("notifying", req.URL.Host); err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// Email mails each summary to To, by default the configured SMTP sender.
+type Email struct {
+	To string
+}
+
+func (e Email) String() string { return "email " + e.to() }
+
+func (e Email) to() string {
+	if e.To != "" {
+		return e.To
+	}
+	return apply.SMTPFrom()
+}
+
+// Notify sends through the configured SMTP server; a dry run writes the
+// message out and counts as sent.
+func (e Email) Notify(ctx context.Context, s Summary) error {
+	to := e.to()
+	if to == "" {
+		return fmt.Errorf("no address to email (set SPRAYER_SMTP_FROM or pass one)")
+	}
+	subject := fmt.Sprintf("sprayer: %d new jobs for %s", len(s.Jobs), s.Profile)
+	err := apply.SendDirect(to, subject, s.Text(), "")
+	var dry *apply.DryRunError
+	if errors.As(err, &dry) {
+		return nil
+	}
+	return err
+}
+
+// Desktop raises a desktop notification for each summarised job scoring
+// MinScore or more.
+type Desktop struct {
+	Notifier notify.Notifier
+	MinScore int
+}
+
+func (d Desktop) String() string { return "desktop" }
+
+// Notify stops at the first notification that fails, usually for want of
+// a notifier on this system.
+func (d Desktop) Notify(ctx context.Context, s Summary) error {
+	for _, j := range s.Jobs {
+		if j.Score < d.MinScore {
+			continue
+		}
+		if err := d.Notifier.Notify(ctx, notify.ForJob(j)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Text is the summary as a short plain-text list.
+func (s Summary) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Scraped %d new jobs for profile %s; the best %d:\n\n", s.Found, s.Profile, len(s.Jobs))
+	for _, j := range s.Jobs {
+		fmt.Fprintf(&b, "[%3d] %s @ %s\n      %s\n", j.Score, j.Title, j.Company, j.URL)
+	}
+	return b.String()
+}
@@ -0,0 +1,114 @@
+// Package desktop hands things over to the user's desktop: URLs to the
+// default browser and text to the clipboard. Both work from a terminal
+// program without writing to its screen.
+package desktop
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// ErrUnavailable is returned when this system has no way to do what was
+// asked.
+var ErrUnavailable = errors.New("not available on this system")
+
+// launchWait is how long OpenURL waits to hear the launcher failed. One
+// still running by then has handed the URL over: xdg-open can stay until
+// the browser it started exits.
+const launchWait = 2 * time.Second
+
+// opener is the program and arguments that open url on this system.
+func opener(url string) (string, []string) {
+	switch runtime.GOOS {
+	case "darwin":
+		return "open", []string{url}
+	case "windows":
+		// start mangles URLs with & in them; the URL handler does not.
+		return "rundll32", []string{"url.dll,FileProtocolHandler", url}
+	}
+	return "xdg-open", []string{url}
+}
+
+// OpenURL opens url in the default browser. Its output is kept off the
+// terminal and reported with a failure instead.
+func OpenURL(url string) error {
+	if url == "" {
+		return errors.New("no URL to open")
+	}
+	name, args := opener(url)
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return fmt.Errorf("%w: %s not found", ErrUnavailable, name)
+	}
+	var out bytes.Buffer
+	cmd := exec.Command(path, args...)
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			if msg := strings.TrimSpace(out.String()); msg != "" {
+				return fmt.Errorf("%s: %w: %s", name, err, msg)
+			}
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	case <-time.After(launchWait):
+	}
+	return nil
+}
+
+// Copy puts text on the clipboard. It writes an OSC 52 sequence to term
+// when that is a terminal, which the terminal copies even over SSH, and
+// outside SSH also runs the clipboard program (pbcopy, xclip, xsel or
+// wl-copy), as not every terminal honours OSC 52. It fails only when
+// neither was possible.
+func Copy(term io.Writer, text string) error {
+	osc := false
+	if f, ok := term.(*os.File); ok && isTerminal(f) {
+		_, err := sequence(text).WriteTo(f)
+		osc = err == nil
+	}
+	if osc && overSSH() {
+		return nil // the clipboard programs would copy on the server
+	}
+	if err := clipboard.WriteAll(text); err != nil && !osc {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return nil
+}
+
+// sequence is the OSC 52 sequence copying text, wrapped for tmux and
+// screen to pass it on to the terminal.
+func sequence(text string) osc52.Sequence {
+	seq := osc52.New(text)
+	switch {
+	case os.Getenv("TMUX") != "":
+		return seq.Tmux()
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		return seq.Screen()
+	}
+	return seq
+}
+
+func overSSH() bool {
+	return os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != ""
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,65 @@
+package desktop
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeOpener puts an xdg-open running script on PATH, alone.
+func fakeOpener(t *testing.T, script string) {
+	if runtime.GOOS != "linux" {
+		t.Skip("opens through xdg-open on Linux only")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "xdg-open"), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestOpenURL(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "opened")
+	fakeOpener(t, `printf '%s' "$1" > `+out)
+	if err := OpenURL("https://example.com/jobs/1?a=1&b=2"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(out); string(got) != "https://example.com/jobs/1?a=1&b=2" {
+		t.Errorf("opened %q", got)
+	}
+}
+
+func TestOpenURL_ReportsFailure(t *testing.T) {
+	fakeOpener(t, `echo "no method available" >&2; exit 3`)
+	err := OpenURL("https://example.com")
+	if err == nil || !strings.Contains(err.Error(), "no method available") {
+		t.Errorf("got %v, want the opener's complaint", err)
+	}
+}
+
+func TestOpenURL_NoOpener(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	if err := OpenURL("https://example.com"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("got %v, want ErrUnavailable", err)
+	}
+}
+
+func TestCopy_NoTerminalNoProgram(t *testing.T) {
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Skip("the clipboard program is part of the system")
+	}
+	t.Setenv("PATH", t.TempDir())
+	if err := Copy(nil, "hello"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("got %v, want ErrUnavailable", err)
+	}
+}
+
+func TestSequence_WrapsForTmux(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	if got := sequence("hi").String(); !strings.HasPrefix(got, "\x1bPtmux;") {
+		t.Errorf("got %q, want a tmux passthrough", got)
+	}
+}
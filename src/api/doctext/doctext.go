@@ -0,0 +1,195 @@
+// Package doctext pulls the plain text out of the documents CVs are kept
+// in: PDF, Word (.docx) and plain text. It reads what a text PDF draws
+// with its fonts; scanned pages, which are only images, have no text to
+// give and are reported as such rather than read as empty.
+package doctext
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format is a kind of document Extract reads.
+type Format string
+
+const (
+	PDF  Format = "pdf"
+	DOCX Format = "docx"
+	Text Format = "text"
+)
+
+var (
+	// ErrEncrypted is a password-protected PDF, or a Word file saved with
+	// a password.
+	ErrEncrypted = errors.New("the document is encrypted; save a copy without a password")
+	// ErrNoText is a document with nothing to read, such as a scanned PDF.
+	ErrNoText = errors.New("no text found; if it is a scan, export a text PDF or .docx, or save the text as .txt")
+	// ErrUnsupported is a binary format Extract cannot read, such as a
+	// Word 97-2003 .doc.
+	ErrUnsupported = errors.New("unsupported format; save it as PDF, .docx or .txt")
+)
+
+// Detect tells the format of data, by its magic bytes first and the
+// extension of name when those say nothing.
+func Detect(name string, data []byte) Format {
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return PDF
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		return DOCX
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".pdf":
+		return PDF
+	case ".docx":
+		return DOCX
+	}
+	return Text
+}
+
+// ExtractFile reads the document at path and returns its text.
+func ExtractFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	text, err := Extract(filepath.Base(path), data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return text, nil
+}
+
+// Extract returns the text of the document data, named name. Lines are
+// kept; runs of spaces and blank lines are squeezed.
+func Extract(name string, data []byte) (string, error) {
+	var text string
+	var err error
+	switch Detect(name, data) {
+	case PDF:
+		text, err = pdfText(data)
+	case DOCX:
+		text, err = docxText(data)
+	default:
+		text, err = plainText(data)
+	}
+	if err != nil {
+		return "", err
+	}
+	if text = tidy(text); text == "" {
+		return "", ErrNoText
+	}
+	return text, nil
+}
+
+// oleMagic starts the compound files of Word 97-2003 documents, and of
+// .docx files saved with a password.
+var oleMagic = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+func plainText(data []byte) (string, error) {
+	if bytes.HasPrefix(data, oleMagic) {
+		return "", fmt.Errorf("a Word 97-2003 or password-protected file: %w", ErrUnsupported)
+	}
+	if !utf8.Valid(data) {
+		if bytes.IndexByte(data, 0) >= 0 {
+			return "", ErrUnsupported
+		}
+		// Latin-1, as older editors save text.
+		r := make([]rune, len(data))
+		for i, b := range data {
+			r[i] = rune(b)
+		}
+		return string(r), nil
+	}
+	return string(data), nil
+}
+
+// docxText reads word/document.xml: the text of each run, paragraphs and
+// breaks as new lines and tabs as tabs.
+func docxText(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("reading .docx: %w", err)
+	}
+	var doc *zip.File
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			doc = f
+		}
+	}
+	if doc == nil {
+		return "", fmt.Errorf("a zip file without word/document.xml: %w", ErrUnsupported)
+	}
+	rc, err := doc.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	var b strings.Builder
+	dec := xml.NewDecoder(rc)
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading .docx: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				b.WriteByte('\t')
+			case "br", "cr":
+				b.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				b.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				b.Write(t)
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// tidy trims each line, squeezes spaces and keeps at most one blank line
+// in a row.
+func tidy(s string) string {
+	var out []string
+	blank := false
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.Join(strings.FieldsFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == '\r' || r == '\u00a0'
+		}), " ")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if !blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		blank = false
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
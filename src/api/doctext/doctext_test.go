@@ -0,0 +1,168 @@
+package doctext
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildPDF writes a PDF with objs as objects 1, 2, ... and the trailer
+// entries given. An object that is a [2]string is a dictionary and the
+// data of its stream, Flate-compressed when the dictionary asks.
+func buildPDF(trailer string, objs ...any) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objs))
+	for i, o := range objs {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n", i+1)
+		switch o := o.(type) {
+		case string:
+			b.WriteString(o)
+		case [2]string:
+			data := []byte(o[1])
+			if strings.Contains(o[0], "/FlateDecode") {
+				var z bytes.Buffer
+				w := zlib.NewWriter(&z)
+				w.Write(data)
+				w.Close()
+				data = z.Bytes()
+			}
+			fmt.Fprintf(&b, "<< %s /Length %d >>\nstream\n", o[0], len(data))
+			b.Write(data)
+			b.WriteString("\nendstream")
+		}
+		b.WriteString("\nendobj\n")
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R %s >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, trailer, xref)
+	return b.Bytes()
+}
+
+// onePage is a PDF whose single page draws content with font as /F1.
+func onePage(trailer, font, content string, more ...any) []byte {
+	objs := []any{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 4 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 5 0 R >>",
+		font,
+		[2]string{"/Filter /FlateDecode", content},
+	}
+	return buildPDF(trailer, append(objs, more...)...)
+}
+
+func TestExtract_PDFSimpleFont(t *testing.T) {
+	data := onePage("", "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding << /Differences [1 /fi /endash] >> >>", `
+BT /F1 18 Tf 72 720 Td (Jane Doe) Tj
+/F1 10 Tf 0 -20 Td [(jane@example.com)-300(\267)-300(+44 20 7946 0958)] TJ
+0 -14 Td [(Go engineer )(\(Kubernetes\), )(speci)<01>(c work 2019)<02>(2024)] TJ
+T* (Caf\351 owner) Tj ET`)
+
+	got, err := Extract("cv.pdf", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Jane Doe\njane@example.com · +44 20 7946 0958\nGo engineer (Kubernetes), specific work 2019–2024\nCafé owner"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestExtract_PDFToUnicode(t *testing.T) {
+	cmap := `/CIDInit /ProcSet findresource begin 12 dict begin begincmap
+1 begincodespacerange <0000> <FFFF> endcodespacerange
+2 beginbfchar <0003> <0020> <0010> <0040> endbfchar
+2 beginbfrange <0020> <0039> <0041> <0040> <0041> [<00E9> <0078>] endbfrange
+endcmap CMapName currentdict /CMap defineresource pop end end`
+	// Codes 0x20-0x39 are A-Z, 0x40 é, 0x41 x, 0x03 a space, 0x10 @.
+	data := onePage("", "<< /Type /Font /Subtype /Type0 /BaseFont /Arial /Encoding /Identity-H /ToUnicode 6 0 R >>",
+		"BT /F1 12 Tf 1 0 0 1 72 700 Tm <0029002C002D00240003002E0040> Tj 1 0 0 1 72 680 Tm <0041> Tj ET",
+		[2]string{"/Filter /FlateDecode", cmap})
+
+	got, err := Extract("cv.pdf", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "JMNE Oé\nx" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestExtract_PDFErrors(t *testing.T) {
+	font := "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"
+	encrypted := onePage("/Encrypt 6 0 R", font, "BT /F1 12 Tf (x) Tj ET", "<< /Filter /Standard /V 2 >>")
+	if _, err := Extract("cv.pdf", encrypted); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("encrypted PDF: %v", err)
+	}
+	scanned := onePage("", font, "q 612 0 0 792 0 0 cm /Im1 Do Q")
+	if _, err := Extract("cv.pdf", scanned); !errors.Is(err, ErrNoText) {
+		t.Errorf("image-only PDF: %v", err)
+	}
+	if _, err := Extract("cv.pdf", []byte("%PDF-1.4\ngarbage")); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("PDF without pages: %v", err)
+	}
+	if _, err := Extract("cv.pdf", []byte("%PDF-1.7\n1 0 obj\n<< /A <00")); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("PDF cut short in a hex string: %v", err)
+	}
+}
+
+// FuzzExtract feeds mangled PDFs to the reader, without pdfText's
+// recover, so any input that panics fails.
+func FuzzExtract(f *testing.F) {
+	font := "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding << /Differences [1 /fi] >> >>"
+	f.Add(onePage("", font, "BT /F1 12 Tf 72 720 Td (Jane Doe) Tj T* [(a)-300<01>] TJ ET"))
+	f.Add(onePage("/Encrypt 6 0 R", font, "BT /F1 12 Tf (x) Tj ET", "<< /Filter /Standard /V 2 >>"))
+	f.Add(onePage("", "<< /Type /Font /Subtype /Type0 /Encoding /Identity-H /ToUnicode 6 0 R >>",
+		"BT /F1 12 Tf <00290003> Tj ET",
+		[2]string{"/Filter /FlateDecode", "1 beginbfchar <0003> <0020> endbfchar 1 beginbfrange <0020> <0039> <0041> endbfrange"}))
+	f.Add([]byte("%PDF-1.7\n1 0 obj\n<< /A <00"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		readPDF(data)
+	})
+}
+
+func TestExtract_DOCX(t *testing.T) {
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	w, _ := zw.Create("word/document.xml")
+	w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Jane</w:t></w:r><w:r><w:t xml:space="preserve"> Doe</w:t></w:r></w:p>
+<w:p><w:r><w:t>Go</w:t><w:tab/><w:t>Rust</w:t><w:br/><w:t>Berlin &amp; remote</w:t></w:r></w:p>
+<w:p/><w:p/><w:p><w:r><w:t>Experience</w:t></w:r></w:p>
+</w:body></w:document>`))
+	zw.Close()
+
+	// A .docx is found by its bytes, whatever it is called.
+	got, err := Extract("resume", b.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Jane Doe\nGo Rust\nBerlin & remote\n\nExperience"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestExtract_TextAndUnsupported(t *testing.T) {
+	if got, err := Extract("cv.txt", []byte("  Jane   Doe \r\n\n\n\nGo\n")); err != nil || got != "Jane Doe\n\nGo" {
+		t.Errorf("text: %q, %v", got, err)
+	}
+	if got, err := Extract("cv.txt", []byte("Caf\xe9")); err != nil || got != "Café" {
+		t.Errorf("Latin-1 text: %q, %v", got, err)
+	}
+	doc := append([]byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}, make([]byte, 64)...)
+	if _, err := Extract("cv.doc", doc); !errors.Is(err, ErrUnsupported) {
+		t.Errorf(".doc: %v", err)
+	}
+	if _, err := Extract("cv.txt", []byte(" \n ")); !errors.Is(err, ErrNoText) {
+		t.Errorf("blank text: %v", err)
+	}
+}
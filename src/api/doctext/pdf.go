@@ -0,0 +1,920 @@
+package doctext
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// The PDF reader below covers what CV exporters write: objects, whole or
+// packed in object streams, Flate, ASCIIHex and ASCII85 streams, pages
+// and their fonts, ToUnicode maps and simple fonts' encodings. It reads
+// text in the order it is drawn, which for CVs is the reading order.
+
+// PDF objects, as pdfLexer reads them. Strings are []byte, numbers
+// float64, booleans bool and null nil.
+type (
+	pdfName string
+	pdfRef  int
+	pdfOp   string
+	pdfDict map[string]any
+)
+
+type pdfObject struct {
+	val    any
+	stream []byte // raw, still encoded; nil when the object has none
+}
+
+// pdfDoc is a parsed PDF: its objects by number.
+type pdfDoc struct {
+	objs map[int]pdfObject
+}
+
+var (
+	pdfObjHeader = regexp.MustCompile(`(\d+)\s+\d+\s+obj\b`)
+	pdfTrailer   = regexp.MustCompile(`trailer\s*<<`)
+)
+
+// pdfText reads the text of every page of the PDF in data. A file
+// malformed in a way readPDF trips over is unsupported rather than a
+// crash.
+func pdfText(data []byte) (text string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			text, err = "", fmt.Errorf("malformed PDF: %w", ErrUnsupported)
+		}
+	}()
+	return readPDF(data)
+}
+
+// readPDF is pdfText without the recover, so fuzzing sees what panics.
+func readPDF(data []byte) (string, error) {
+	doc := parsePDF(data)
+	if doc.encrypted(data) {
+		return "", ErrEncrypted
+	}
+	pages := doc.pages()
+	if len(pages) == 0 {
+		return "", fmt.Errorf("no pages found: %w", ErrUnsupported)
+	}
+	var b strings.Builder
+	for _, p := range pages {
+		t := &pdfTextWriter{doc: doc}
+		for _, content := range p.contents {
+			t.run(content, p.resources, 0)
+		}
+		b.WriteString(t.b.String())
+		b.WriteString("\n\n")
+	}
+	return b.String(), nil
+}
+
+// parsePDF reads every object in data, in file order, so the later copy
+// of an object an incremental update rewrote wins, then the objects
+// packed in object streams.
+func parsePDF(data []byte) *pdfDoc {
+	doc := &pdfDoc{objs: make(map[int]pdfObject)}
+	for _, m := range pdfObjHeader.FindAllSubmatchIndex(data, -1) {
+		num, err := strconv.Atoi(string(data[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+		l := &pdfLexer{b: data, i: m[1]}
+		val, ok := l.next()
+		if !ok {
+			continue
+		}
+		obj := pdfObject{val: val}
+		if d, isDict := val.(pdfDict); isDict {
+			obj.stream = streamAt(data, l.i, d)
+		}
+		doc.objs[num] = obj
+	}
+	for _, obj := range doc.objs {
+		if d, ok := obj.val.(pdfDict); ok && d["Type"] == pdfName("ObjStm") {
+			doc.unpack(d, obj.stream)
+		}
+	}
+	return doc
+}
+
+// streamAt returns the raw data of the stream following a dictionary
+// that ends at i, if one does.
+func streamAt(data []byte, i int, d pdfDict) []byte {
+	if i >= len(data) {
+		return nil
+	}
+	for i < len(data) && isPDFSpace(data[i]) {
+		i++
+	}
+	if !bytes.HasPrefix(data[i:], []byte("stream")) {
+		return nil
+	}
+	i += len("stream")
+	if bytes.HasPrefix(data[i:], []byte("\r\n")) {
+		i += 2
+	} else if i < len(data) && (data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	if n, ok := d["Length"].(float64); ok && n >= 0 && i+int(n) <= len(data) {
+		end := i + int(n)
+		if rest := bytes.TrimLeft(data[end:min(end+32, len(data))], "\r\n \t"); bytes.HasPrefix(rest, []byte("endstream")) {
+			return data[i:end]
+		}
+	}
+	end := bytes.Index(data[i:], []byte("endstream"))
+	if end < 0 {
+		return nil
+	}
+	return bytes.TrimRight(data[i:i+end], "\r\n")
+}
+
+// unpack adds the objects of an object stream, unless a plain object
+// with the same number was read.
+func (doc *pdfDoc) unpack(d pdfDict, raw []byte) {
+	data, err := doc.decode(d, raw)
+	if err != nil {
+		return
+	}
+	n, _ := d["N"].(float64)
+	first, _ := d["First"].(float64)
+	l := &pdfLexer{b: data}
+	type entry struct{ num, off int }
+	var entries []entry
+	for k := 0; k < int(n); k++ {
+		num, ok1 := l.next()
+		off, ok2 := l.next()
+		fn, isNum := num.(float64)
+		fo, isOff := off.(float64)
+		if !ok1 || !ok2 || !isNum || !isOff {
+			return
+		}
+		entries = append(entries, entry{int(fn), int(fo)})
+	}
+	for _, e := range entries {
+		if _, seen := doc.objs[e.num]; seen {
+			continue
+		}
+		at := int(first) + e.off
+		if at < 0 || at >= len(data) {
+			continue
+		}
+		l := &pdfLexer{b: data, i: at}
+		if val, ok := l.next(); ok {
+			doc.objs[e.num] = pdfObject{val: val}
+		}
+	}
+}
+
+// resolve follows a reference to the object it names.
+func (doc *pdfDoc) resolve(v any) any {
+	for range 8 {
+		r, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = doc.objs[int(r)].val
+	}
+	return nil
+}
+
+func (doc *pdfDoc) dict(v any) pdfDict {
+	d, _ := doc.resolve(v).(pdfDict)
+	return d
+}
+
+// encrypted tells whether a trailer, or a cross-reference stream, names
+// an encryption dictionary.
+func (doc *pdfDoc) encrypted(data []byte) bool {
+	for _, m := range pdfTrailer.FindAllIndex(data, -1) {
+		l := &pdfLexer{b: data, i: m[1] - 2}
+		v, _ := l.next()
+		if d, ok := v.(pdfDict); ok && d["Encrypt"] != nil {
+			return true
+		}
+	}
+	for _, obj := range doc.objs {
+		if d, ok := obj.val.(pdfDict); ok && d["Type"] == pdfName("XRef") && d["Encrypt"] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// pdfPage is a page's content streams and the resources they draw with.
+type pdfPage struct {
+	contents  [][]byte
+	resources pdfDict
+}
+
+// pages walks the page tree from the catalog, passing resources down to
+// pages that inherit them. Without a catalog the page objects are taken
+// in number order.
+func (doc *pdfDoc) pages() []pdfPage {
+	var out []pdfPage
+	seen := make(map[int]bool)
+	var walk func(v any, inherited pdfDict, depth int)
+	walk = func(v any, inherited pdfDict, depth int) {
+		if r, ok := v.(pdfRef); ok {
+			if seen[int(r)] {
+				return
+			}
+			seen[int(r)] = true
+		}
+		d := doc.dict(v)
+		if d == nil || depth > 32 {
+			return
+		}
+		res := inherited
+		if r := doc.dict(d["Resources"]); r != nil {
+			res = r
+		}
+		if d["Type"] == pdfName("Page") {
+			out = append(out, pdfPage{contents: doc.contents(d["Contents"]), resources: res})
+			return
+		}
+		kids, _ := doc.resolve(d["Kids"]).([]any)
+		for _, k := range kids {
+			walk(k, res, depth+1)
+		}
+	}
+
+	nums := make([]int, 0, len(doc.objs))
+	for n := range doc.objs {
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+	for _, n := range nums {
+		if d, ok := doc.objs[n].val.(pdfDict); ok && d["Type"] == pdfName("Catalog") {
+			walk(d["Pages"], nil, 0)
+			if len(out) > 0 {
+				return out
+			}
+		}
+	}
+	for _, n := range nums {
+		if d, ok := doc.objs[n].val.(pdfDict); ok && d["Type"] == pdfName("Page") {
+			out = append(out, pdfPage{contents: doc.contents(d["Contents"]), resources: doc.dict(d["Resources"])})
+		}
+	}
+	return out
+}
+
+// contents decodes a page's content stream, or each of an array of them.
+func (doc *pdfDoc) contents(v any) [][]byte {
+	var refs []any
+	switch c := doc.resolve(v).(type) {
+	case []any:
+		refs = c
+	default:
+		refs = []any{v}
+	}
+	var out [][]byte
+	for _, r := range refs {
+		ref, ok := r.(pdfRef)
+		if !ok {
+			continue
+		}
+		obj := doc.objs[int(ref)]
+		d, _ := obj.val.(pdfDict)
+		if data, err := doc.decode(d, obj.stream); err == nil {
+			out = append(out, data)
+		}
+	}
+	return out
+}
+
+// decode undoes a stream's filters. Image filters are not undone: there
+// is no text in them.
+func (doc *pdfDoc) decode(d pdfDict, data []byte) ([]byte, error) {
+	var filters []any
+	switch f := doc.resolve(d["Filter"]).(type) {
+	case pdfName:
+		filters = []any{f}
+	case []any:
+		filters = f
+	}
+	for _, f := range filters {
+		var err error
+		switch doc.resolve(f) {
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			data, err = inflate(data)
+		case pdfName("ASCIIHexDecode"), pdfName("AHx"):
+			data, err = asciiHex(data)
+		case pdfName("ASCII85Decode"), pdfName("A85"):
+			data, err = ascii85Decode(data)
+		default:
+			err = fmt.Errorf("filter %v", f)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// inflate undoes Flate, keeping what decoded before any error, as some
+// writers end streams early.
+func inflate(data []byte) ([]byte, error) {
+	var r io.Reader
+	if zr, err := zlib.NewReader(bytes.NewReader(data)); err == nil {
+		r = zr
+	} else {
+		r = flate.NewReader(bytes.NewReader(data))
+	}
+	out, err := io.ReadAll(r)
+	if len(out) > 0 {
+		return out, nil
+	}
+	return nil, err
+}
+
+func asciiHex(data []byte) ([]byte, error) {
+	var digits []byte
+	for _, c := range data {
+		if c == '>' {
+			break
+		}
+		if !isPDFSpace(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	_, err := hex.Decode(out, digits)
+	return out, err
+}
+
+func ascii85Decode(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	out := make([]byte, 4*len(data)/5+4)
+	n, _, err := ascii85.Decode(out, data, true)
+	return out[:n], err
+}
+
+// pdfTextWriter runs content streams, writing the text they draw. Moves
+// to a new line start a new line of text, moves along the line a space.
+type pdfTextWriter struct {
+	doc   *pdfDoc
+	b     strings.Builder
+	font  *pdfFont
+	fonts map[string]*pdfFont
+	y     float64
+}
+
+func (t *pdfTextWriter) run(content []byte, res pdfDict, depth int) {
+	l := &pdfLexer{b: content}
+	var args []any
+	for {
+		v, ok := l.next()
+		if !ok {
+			return
+		}
+		op, isOp := v.(pdfOp)
+		if !isOp {
+			args = append(args, v)
+			continue
+		}
+		switch op {
+		case "Tf":
+			if len(args) >= 2 {
+				if name, ok := args[0].(pdfName); ok {
+					t.font = t.fontNamed(res, string(name))
+				}
+			}
+		case "Tj":
+			if len(args) >= 1 {
+				t.show(args[0])
+			}
+		case "'", "\"":
+			t.newline()
+			if len(args) >= 1 {
+				t.show(args[len(args)-1])
+			}
+		case "TJ":
+			if len(args) >= 1 {
+				items, _ := args[0].([]any)
+				for _, it := range items {
+					if n, ok := it.(float64); ok {
+						if n < -150 { // a gap wider than kerning: a space
+							t.space()
+						}
+						continue
+					}
+					t.show(it)
+				}
+			}
+		case "Td", "TD":
+			if len(args) >= 2 {
+				ty, _ := args[1].(float64)
+				if math.Abs(ty) > 0.5 {
+					t.newline()
+				} else {
+					t.space()
+				}
+			}
+		case "Tm":
+			if len(args) >= 6 {
+				y, _ := args[5].(float64)
+				if math.Abs(y-t.y) > 0.5 {
+					t.newline()
+				} else {
+					t.space()
+				}
+				t.y = y
+			}
+		case "T*":
+			t.newline()
+		case "ET":
+			t.space()
+		case "Do":
+			if len(args) >= 1 && depth < 8 {
+				name, _ := args[0].(pdfName)
+				t.form(res, string(name), depth)
+			}
+		case "BI":
+			l.skipInlineImage()
+		}
+		args = args[:0]
+	}
+}
+
+// form runs a form XObject, which some writers draw whole pages with.
+func (t *pdfTextWriter) form(res pdfDict, name string, depth int) {
+	ref, ok := t.doc.dict(res["XObject"])[name].(pdfRef)
+	if !ok {
+		return
+	}
+	obj := t.doc.objs[int(ref)]
+	d, _ := obj.val.(pdfDict)
+	if d["Subtype"] != pdfName("Form") {
+		return
+	}
+	data, err := t.doc.decode(d, obj.stream)
+	if err != nil {
+		return
+	}
+	if r := t.doc.dict(d["Resources"]); r != nil {
+		res = r
+	}
+	font := t.font
+	t.run(data, res, depth+1)
+	t.font = font
+}
+
+func (t *pdfTextWriter) show(v any) {
+	s, ok := v.([]byte)
+	if !ok {
+		return
+	}
+	if t.font == nil {
+		t.font = &pdfFont{}
+	}
+	t.b.WriteString(t.font.decode(s))
+}
+
+func (t *pdfTextWriter) space() {
+	if s := t.b.String(); s != "" && !strings.HasSuffix(s, " ") && !strings.HasSuffix(s, "\n") {
+		t.b.WriteByte(' ')
+	}
+}
+
+func (t *pdfTextWriter) newline() {
+	if s := t.b.String(); s != "" && !strings.HasSuffix(s, "\n") {
+		t.b.WriteByte('\n')
+	}
+}
+
+func (t *pdfTextWriter) fontNamed(res pdfDict, name string) *pdfFont {
+	ref := t.doc.dict(res["Font"])[name]
+	key := fmt.Sprint(ref)
+	if f, ok := t.fonts[key]; ok {
+		return f
+	}
+	if t.fonts == nil {
+		t.fonts = make(map[string]*pdfFont)
+	}
+	f := t.doc.font(t.doc.dict(ref))
+	t.fonts[key] = f
+	return f
+}
+
+// pdfFont turns a font's character codes into text: through its
+// ToUnicode map when it has one, else as a simple font whose codes are
+// its encoding's, with the glyph names its Differences give.
+type pdfFont struct {
+	toUnicode map[uint32]string
+	codeLen   int // bytes per code in toUnicode
+	composite bool
+	diffs     map[byte]string
+}
+
+func (doc *pdfDoc) font(d pdfDict) *pdfFont {
+	f := &pdfFont{codeLen: 1}
+	if d == nil {
+		return f
+	}
+	f.composite = d["Subtype"] == pdfName("Type0")
+	if f.composite {
+		f.codeLen = 2
+	}
+	if ref, ok := d["ToUnicode"].(pdfRef); ok {
+		obj := doc.objs[int(ref)]
+		sd, _ := obj.val.(pdfDict)
+		if data, err := doc.decode(sd, obj.stream); err == nil {
+			f.readCMap(data)
+		}
+	}
+	if enc := doc.dict(d["Encoding"]); enc != nil {
+		diffs, _ := doc.resolve(enc["Differences"]).([]any)
+		code := 0
+		for _, v := range diffs {
+			switch v := v.(type) {
+			case float64:
+				code = int(v)
+			case pdfName:
+				if code >= 0 && code < 256 {
+					if f.diffs == nil {
+						f.diffs = make(map[byte]string)
+					}
+					f.diffs[byte(code)] = glyphText(string(v))
+				}
+				code++
+			}
+		}
+	}
+	return f
+}
+
+// readCMap reads the bfchar and bfrange mappings of a ToUnicode CMap.
+func (f *pdfFont) readCMap(data []byte) {
+	f.toUnicode = make(map[uint32]string)
+	l := &pdfLexer{b: data}
+	var args []any
+	for {
+		v, ok := l.next()
+		if !ok {
+			return
+		}
+		op, isOp := v.(pdfOp)
+		if !isOp {
+			args = append(args, v)
+			continue
+		}
+		switch op {
+		case "endcodespacerange":
+			if len(args) > 0 {
+				if lo, ok := args[0].([]byte); ok && len(lo) > 0 {
+					f.codeLen = len(lo)
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(args); i += 2 {
+				src, ok1 := args[i].([]byte)
+				dst, ok2 := args[i+1].([]byte)
+				if ok1 && ok2 {
+					f.toUnicode[codeOf(src)] = utf16Text(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(args); i += 3 {
+				lo, ok1 := args[i].([]byte)
+				hi, ok2 := args[i+1].([]byte)
+				if !ok1 || !ok2 || codeOf(hi) < codeOf(lo) || codeOf(hi)-codeOf(lo) > 0xFFFF {
+					continue
+				}
+				switch dst := args[i+2].(type) {
+				case []byte:
+					base := []rune(utf16Text(dst))
+					if len(base) == 0 {
+						continue
+					}
+					for c := codeOf(lo); c <= codeOf(hi); c++ {
+						r := append([]rune(nil), base...)
+						r[len(r)-1] += rune(c - codeOf(lo))
+						f.toUnicode[c] = string(r)
+					}
+				case []any:
+					for k, d := range dst {
+						if b, ok := d.([]byte); ok {
+							f.toUnicode[codeOf(lo)+uint32(k)] = utf16Text(b)
+						}
+					}
+				}
+			}
+		}
+		args = args[:0]
+	}
+}
+
+func (f *pdfFont) decode(s []byte) string {
+	var b strings.Builder
+	if f.toUnicode != nil {
+		n := max(f.codeLen, 1)
+		for i := 0; i+n <= len(s); i += n {
+			b.WriteString(f.toUnicode[codeOf(s[i:i+n])])
+		}
+		return b.String()
+	}
+	if f.composite {
+		return "" // CIDs without a map to Unicode say nothing
+	}
+	for _, c := range s {
+		if g, ok := f.diffs[c]; ok {
+			b.WriteString(g)
+			continue
+		}
+		b.WriteRune(winAnsi(c))
+	}
+	return b.String()
+}
+
+func codeOf(b []byte) uint32 {
+	var c uint32
+	for _, x := range b {
+		c = c<<8 | uint32(x)
+	}
+	return c
+}
+
+func utf16Text(b []byte) string {
+	u := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		u = append(u, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(u))
+}
+
+// winAnsiHigh are the WinAnsiEncoding characters from 0x80 to 0x9F; the
+// rest of the encoding is Latin-1.
+var winAnsiHigh = []rune("€\u0081‚ƒ„…†‡ˆ‰Š‹Œ\u008DŽ\u008F\u0090‘’“”•–—˜™š›œ\u009DžŸ")
+
+func winAnsi(c byte) rune {
+	if c >= 0x80 && c <= 0x9F {
+		return winAnsiHigh[c-0x80]
+	}
+	return rune(c)
+}
+
+// glyphNames are the glyphs CV fonts name in their Differences beyond
+// the letters and digits, which name themselves.
+var glyphNames = map[string]string{
+	"space": " ", "comma": ",", "period": ".", "colon": ":", "semicolon": ";", "hyphen": "-",
+	"endash": "–", "emdash": "—", "quoteright": "’", "quoteleft": "‘", "quotedblleft": "“",
+	"quotedblright": "”", "quotesingle": "'", "quotedbl": "\"", "parenleft": "(", "parenright": ")",
+	"bracketleft": "[", "bracketright": "]", "slash": "/", "at": "@", "plus": "+", "ampersand": "&",
+	"numbersign": "#", "percent": "%", "bullet": "•", "periodcentered": "·", "underscore": "_",
+	"fi": "fi", "fl": "fl", "ff": "ff", "ffi": "ffi", "ffl": "ffl", "exclam": "!", "question": "?",
+	"zero": "0", "one": "1", "two": "2", "three": "3", "four": "4",
+	"five": "5", "six": "6", "seven": "7", "eight": "8", "nine": "9",
+}
+
+// glyphText is the text of a glyph name: a single letter names itself,
+// uniXXXX gives its code point.
+func glyphText(name string) string {
+	if s, ok := glyphNames[name]; ok {
+		return s
+	}
+	if len(name) == 1 {
+		return name
+	}
+	if strings.HasPrefix(name, "uni") && len(name) == 7 {
+		if n, err := strconv.ParseUint(name[3:], 16, 32); err == nil {
+			return string(rune(n))
+		}
+	}
+	return ""
+}
+
+// pdfLexer reads PDF objects and content stream operators from b.
+type pdfLexer struct {
+	b []byte
+	i int
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelim(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.i < len(l.b) {
+		switch c := l.b[l.i]; {
+		case isPDFSpace(c):
+			l.i++
+		case c == '%':
+			for l.i < len(l.b) && l.b[l.i] != '\n' && l.b[l.i] != '\r' {
+				l.i++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// next reads the next object or operator; false at the end of b.
+func (l *pdfLexer) next() (any, bool) {
+	l.skipSpace()
+	if l.i >= len(l.b) {
+		return nil, false
+	}
+	switch c := l.b[l.i]; {
+	case c == '<' && l.i+1 < len(l.b) && l.b[l.i+1] == '<':
+		l.i += 2
+		d := make(pdfDict)
+		for {
+			l.skipSpace()
+			if l.i >= len(l.b) {
+				return d, true
+			}
+			if bytes.HasPrefix(l.b[l.i:], []byte(">>")) {
+				l.i += 2
+				return d, true
+			}
+			k, ok := l.next()
+			if !ok {
+				return d, true
+			}
+			key, isName := k.(pdfName)
+			if !isName {
+				continue
+			}
+			v, _ := l.next()
+			d[string(key)] = v
+		}
+	case c == '<':
+		l.i++
+		end := bytes.IndexByte(l.b[l.i:], '>')
+		if end < 0 {
+			end = len(l.b) - l.i
+		}
+		s, _ := asciiHex(l.b[l.i : l.i+end])
+		l.i = min(l.i+end+1, len(l.b))
+		return s, true
+	case c == '(':
+		return l.literal(), true
+	case c == '[':
+		l.i++
+		var arr []any
+		for {
+			l.skipSpace()
+			if l.i >= len(l.b) {
+				return arr, true
+			}
+			if l.b[l.i] == ']' {
+				l.i++
+				return arr, true
+			}
+			v, ok := l.next()
+			if !ok {
+				return arr, true
+			}
+			arr = append(arr, v)
+		}
+	case c == '/':
+		l.i++
+		return pdfName(l.word()), true
+	case c == ']' || c == '>' || c == ')' || c == '{' || c == '}':
+		l.i++
+		return pdfOp(string(c)), true
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		w := l.word()
+		n, err := strconv.ParseFloat(w, 64)
+		if err != nil {
+			return pdfOp(w), true
+		}
+		// An integer followed by "0 R" is a reference.
+		if save := l.i; n == math.Trunc(n) && n >= 0 {
+			l.skipSpace()
+			if gen := l.word(); gen != "" && strings.Trim(gen, "0123456789") == "" {
+				l.skipSpace()
+				if l.i < len(l.b) && l.b[l.i] == 'R' && (l.i+1 == len(l.b) || isPDFSpace(l.b[l.i+1]) || isPDFDelim(l.b[l.i+1])) {
+					l.i++
+					return pdfRef(int(n)), true
+				}
+			}
+			l.i = save
+		}
+		return n, true
+	}
+	switch w := l.word(); w {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	case "null":
+		return nil, true
+	case "":
+		l.i++ // a stray delimiter
+		return pdfOp(""), true
+	default:
+		return pdfOp(w), true
+	}
+}
+
+// word reads up to the next space or delimiter.
+func (l *pdfLexer) word() string {
+	start := l.i
+	for l.i < len(l.b) && !isPDFSpace(l.b[l.i]) && !isPDFDelim(l.b[l.i]) {
+		l.i++
+	}
+	return string(l.b[start:l.i])
+}
+
+// literal reads a (string), with its escapes and balanced parentheses.
+func (l *pdfLexer) literal() []byte {
+	l.i++
+	var out []byte
+	depth := 0
+	for l.i < len(l.b) {
+		c := l.b[l.i]
+		l.i++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return out
+			}
+			depth--
+		case '\\':
+			if l.i >= len(l.b) {
+				return out
+			}
+			e := l.b[l.i]
+			l.i++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.i < len(l.b) && l.b[l.i] == '\n' {
+					l.i++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					n := int(e - '0')
+					for k := 0; k < 2 && l.i < len(l.b) && l.b[l.i] >= '0' && l.b[l.i] <= '7'; k++ {
+						n = n*8 + int(l.b[l.i]-'0')
+						l.i++
+					}
+					c = byte(n)
+				} else {
+					c = e
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// skipInlineImage skips an inline image's data, from BI to EI.
+func (l *pdfLexer) skipInlineImage() {
+	id := bytes.Index(l.b[l.i:], []byte("ID"))
+	if id < 0 {
+		l.i = len(l.b)
+		return
+	}
+	l.i += id + 3
+	for l.i < len(l.b) {
+		ei := bytes.Index(l.b[l.i:], []byte("EI"))
+		if ei < 0 {
+			l.i = len(l.b)
+			return
+		}
+		at := l.i + ei
+		l.i = at + 2
+		if at > 0 && isPDFSpace(l.b[at-1]) && (l.i == len(l.b) || isPDFSpace(l.b[l.i])) {
+			return
+		}
+	}
+}
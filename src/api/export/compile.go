@@ -0,0 +1,137 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// EngineEnv names the environment variable that picks the LaTeX engine:
+// one of Engines. Unset, the first engine installed is used.
+const EngineEnv = "SPRAYER_LATEX_ENGINE"
+
+// Engines are the LaTeX engines CompileLaTeX drives, in the order they
+// are looked for.
+var Engines = []string{"pdflatex", "latexmk", "tectonic"}
+
+// CompileTimeout bounds a compile whose caller has no deadline of its own.
+const CompileTimeout = 2 * time.Minute
+
+// ErrNoLaTeX reports that no LaTeX engine, which PDF output needs, is
+// installed.
+var ErrNoLaTeX = errors.New("no LaTeX engine found; install TeX Live (pdflatex, latexmk) or tectonic, or set " + EngineEnv)
+
+// CompileError is a failed LaTeX run, with the end of its log to show
+// the user why.
+type CompileError struct {
+	Engine string
+	Err    error
+	Log    string // the last logLines lines of the log, or of the engine's output without one
+}
+
+func (e *CompileError) Error() string {
+	return fmt.Sprintf("%s: %v\n%s", e.Engine, e.Err, e.Log)
+}
+
+func (e *CompileError) Unwrap() error { return e.Err }
+
+const logLines = 40
+
+// auxExts are the by-products of a run, removed once the PDF is built.
+var auxExts = []string{".aux", ".log", ".out", ".toc", ".fls", ".fdb_latexmk"}
+
+// lookPath finds LaTeX engines; replaced in tests.
+var lookPath = exec.LookPath
+
+// latexEngine picks the engine to run and finds its binary.
+func latexEngine() (name, bin string, err error) {
+	if name = strings.TrimSpace(os.Getenv(EngineEnv)); name != "" {
+		if !slices.Contains(Engines, name) {
+			return "", "", fmt.Errorf("%s=%q: use one of %s", EngineEnv, name, strings.Join(Engines, ", "))
+		}
+		if bin, err = lookPath(name); err != nil {
+			return "", "", fmt.Errorf("%s=%s: %w", EngineEnv, name, ErrNoLaTeX)
+		}
+		return name, bin, nil
+	}
+	for _, name := range Engines {
+		if bin, err := lookPath(name); err == nil {
+			return name, bin, nil
+		}
+	}
+	return "", "", ErrNoLaTeX
+}
+
+func engineArgs(engine, tex string) []string {
+	switch engine {
+	case "latexmk":
+		return []string{"-pdf", "-interaction=nonstopmode", "-halt-on-error", tex}
+	case "tectonic":
+		return []string{tex}
+	}
+	return []string{"-interaction=nonstopmode", "-halt-on-error", tex}
+}
+
+// CompileLaTeX builds texPath into the PDF beside it and returns the
+// PDF's path. pdflatex runs a second time when the first leaves
+// references unresolved; latexmk and tectonic rerun on their own. On
+// success the auxiliary files are removed; on failure the log is kept
+// and the error is a *CompileError. Without an engine it returns
+// ErrNoLaTeX.
+func CompileLaTeX(ctx context.Context, texPath string) (string, error) {
+	engine, bin, err := latexEngine()
+	if err != nil {
+		return "", err
+	}
+	base := strings.TrimSuffix(texPath, filepath.Ext(texPath))
+	for pass := 1; ; pass++ {
+		cmd := exec.CommandContext(ctx, bin, engineArgs(engine, filepath.Base(texPath))...)
+		cmd.Dir = filepath.Dir(texPath)
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			return "", &CompileError{Engine: engine, Err: err, Log: logTail(base+".log", out)}
+		}
+		if engine != "pdflatex" || pass == 2 || !needsRerun(base+".log") {
+			break
+		}
+	}
+	for _, ext := range auxExts {
+		os.Remove(base + ext)
+	}
+	return base + ".pdf", nil
+}
+
+// needsRerun reports whether a pdflatex log asks for another pass.
+func needsRerun(logPath string) bool {
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(data), "Rerun to get") ||
+		strings.Contains(string(data), "Label(s) may have changed")
+}
+
+// logTail is the end of the log at logPath, or of out when there is none.
+func logTail(logPath string, out []byte) string {
+	if data, err := os.ReadFile(logPath); err == nil {
+		out = data
+	}
+	return lastLines(string(out), logLines)
+}
+
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeEngines puts scripts standing in for LaTeX engines on an otherwise
+// empty PATH, so they use shell builtins only. Each records its
+// arguments in runs, next to the .tex.
+func fakeEngines(t *testing.T, scripts map[string]string) {
+	t.Helper()
+	bin := t.TempDir()
+	for name, body := range scripts {
+		script := "#!/bin/sh\nfor a; do tex=$a; done\nbase=${tex%.tex}\necho \"$*\" >> runs\n" + body
+		if err := os.WriteFile(filepath.Join(bin, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", bin)
+	t.Setenv(EngineEnv, "")
+}
+
+func texFile(t *testing.T) string {
+	t.Helper()
+	tex := filepath.Join(t.TempDir(), "cv.tex")
+	if err := os.WriteFile(tex, []byte(`\documentclass{article}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return tex
+}
+
+func runs(t *testing.T, tex string) []string {
+	t.Helper()
+	data, _ := os.ReadFile(filepath.Join(filepath.Dir(tex), "runs"))
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestCompileLaTeX_RerunsAndCleans(t *testing.T) {
+	fakeEngines(t, map[string]string{"pdflatex": `
+echo aux > "$base.aux"
+echo pdf > "$base.pdf"
+if [ ! -e pass1 ]; then
+	: > pass1
+	echo "LaTeX Warning: Label(s) may have changed. Rerun to get cross-references right." > "$base.log"
+else
+	echo "Output written on $base.pdf" > "$base.log"
+fi
+`})
+	tex := texFile(t)
+	pdf, err := CompileLaTeX(context.Background(), tex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.TrimSuffix(tex, ".tex") + ".pdf"; pdf != want {
+		t.Errorf("pdf = %q, want %q", pdf, want)
+	}
+	if r := runs(t, tex); len(r) != 2 || r[0] != "-interaction=nonstopmode -halt-on-error cv.tex" {
+		t.Errorf("runs = %q", r)
+	}
+	for _, ext := range []string{".aux", ".log"} {
+		if _, err := os.Stat(strings.TrimSuffix(tex, ".tex") + ext); err == nil {
+			t.Errorf("%s left behind", ext)
+		}
+	}
+}
+
+func TestCompileLaTeX_FailureKeepsLog(t *testing.T) {
+	fakeEngines(t, map[string]string{"pdflatex": `
+i=1; while [ $i -le 50 ]; do echo "line $i" >> "$base.log"; i=$((i+1)); done
+echo "! Undefined control sequence." >> "$base.log"
+exit 1
+`})
+	tex := texFile(t)
+	_, err := CompileLaTeX(context.Background(), tex)
+	var ce *CompileError
+	if !errors.As(err, &ce) {
+		t.Fatalf("err = %v, want a CompileError", err)
+	}
+	lines := strings.Split(ce.Log, "\n")
+	if ce.Engine != "pdflatex" || len(lines) != 40 || lines[0] != "line 12" || lines[39] != "! Undefined control sequence." {
+		t.Errorf("engine %q, log %d lines from %q", ce.Engine, len(lines), lines[0])
+	}
+	if _, err := os.Stat(strings.TrimSuffix(tex, ".tex") + ".log"); err != nil {
+		t.Error("log removed after a failure")
+	}
+	if r := runs(t, tex); len(r) != 1 {
+		t.Errorf("reran after a failure: %q", r)
+	}
+}
+
+func TestCompileLaTeX_Engines(t *testing.T) {
+	fakeEngines(t, map[string]string{
+		"latexmk":  `echo pdf > "$base.pdf"`,
+		"tectonic": `echo pdf > "$base.pdf"`,
+	})
+	tex := texFile(t)
+	if _, err := CompileLaTeX(context.Background(), tex); err != nil {
+		t.Fatal(err)
+	}
+	if r := runs(t, tex); len(r) != 1 || r[0] != "-pdf -interaction=nonstopmode -halt-on-error cv.tex" {
+		t.Errorf("auto-detected runs = %q, want latexmk once", r)
+	}
+
+	t.Setenv(EngineEnv, "tectonic")
+	tex = texFile(t)
+	if _, err := CompileLaTeX(context.Background(), tex); err != nil {
+		t.Fatal(err)
+	}
+	if r := runs(t, tex); len(r) != 1 || r[0] != "cv.tex" {
+		t.Errorf("tectonic runs = %q", r)
+	}
+
+	t.Setenv(EngineEnv, "pdflatex")
+	if _, err := CompileLaTeX(context.Background(), tex); !errors.Is(err, ErrNoLaTeX) {
+		t.Errorf("missing chosen engine: %v", err)
+	}
+	t.Setenv(EngineEnv, "xelatex")
+	if _, err := CompileLaTeX(context.Background(), tex); err == nil || errors.Is(err, ErrNoLaTeX) {
+		t.Errorf("unknown engine: %v", err)
+	}
+}
+
+func TestCompileLaTeX_Cancelled(t *testing.T) {
+	fakeEngines(t, map[string]string{"pdflatex": "while :; do :; done\n"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := CompileLaTeX(ctx, texFile(t)); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,22 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes one row per application under a header row. The summary
+// is left out so the file stays machine-readable.
+func WriteCSV(w io.Writer, r Report, opts Options) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(headers(opts)); err != nil {
+		return err
+	}
+	for _, rw := range r.rows() {
+		if err := cw.Write(rw.fields(opts)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
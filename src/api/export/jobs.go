@@ -0,0 +1,182 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// JobFormat is a file format jobs can be exported in.
+type JobFormat string
+
+const (
+	JobsCSV      JobFormat = "csv"
+	JobsMarkdown JobFormat = "markdown"
+	JobsJSON     JobFormat = "json" // whole job records; columns do not apply
+)
+
+// JobFormats lists the formats WriteJobs accepts.
+var JobFormats = []JobFormat{JobsCSV, JobsMarkdown, JobsJSON}
+
+// ParseJobFormat reads a format name; "md" is short for markdown.
+func ParseJobFormat(s string) (JobFormat, error) {
+	switch f := JobFormat(strings.ToLower(strings.TrimSpace(s))); f {
+	case JobsCSV, JobsMarkdown, JobsJSON:
+		return f, nil
+	case "md":
+		return JobsMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown format %q (want csv, markdown or json)", s)
+}
+
+// Ext is the file extension for f, without the dot.
+func (f JobFormat) Ext() string {
+	if f == JobsMarkdown {
+		return "md"
+	}
+	return string(f)
+}
+
+// JobColumn is one column of a job export.
+type JobColumn struct {
+	Name   string // as given to --columns
+	Header string
+	value  func(job.Job) string
+}
+
+var jobColumns = []JobColumn{
+	{"id", "ID", func(j job.Job) string { return j.ID }},
+	{"ref", "Ref", func(j job.Job) string { return j.Ref() }},
+	{"title", "Title", func(j job.Job) string { return j.Title }},
+	{"company", "Company", func(j job.Job) string { return j.Company }},
+	{"location", "Location", func(j job.Job) string { return j.Location }},
+	{"source", "Source", func(j job.Job) string { return j.Source }},
+	{"score", "Score", func(j job.Job) string { return strconv.Itoa(j.Score) }},
+	{"salary", "Salary", func(j job.Job) string { return j.Salary }},
+	{"posted", "Posted Date", func(j job.Job) string { return formatDay(j.PostedDate) }},
+	{"status", "Status", func(j job.Job) string { return string(j.Status) }},
+	{"applied", "Applied Date", func(j job.Job) string {
+		if !j.Applied {
+			return ""
+		}
+		return formatDay(j.AppliedDate)
+	}},
+	{"email", "Email", func(j job.Job) string { return j.Email }},
+	{"tags", "Tags", func(j job.Job) string { return strings.Join(j.Tags, ", ") }},
+	{"note", "Note", func(j job.Job) string { return j.Note }},
+	{"url", "URL", func(j job.Job) string { return j.URL }},
+	{"description", "Description", func(j job.Job) string { return j.Description }},
+}
+
+// DefaultJobColumns are exported when no columns are asked for.
+const DefaultJobColumns = "title,company,location,source,score,posted,url"
+
+// JobColumnNames lists every column an export can select.
+func JobColumnNames() []string {
+	names := make([]string, len(jobColumns))
+	for i, c := range jobColumns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// ParseJobColumns reads a comma-separated list of column names, in the
+// order given; empty means DefaultJobColumns.
+func ParseJobColumns(s string) ([]JobColumn, error) {
+	if strings.TrimSpace(s) == "" {
+		s = DefaultJobColumns
+	}
+	var cols []JobColumn
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, c := range jobColumns {
+			if c.Name == name {
+				cols = append(cols, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q (have %s)", name, strings.Join(JobColumnNames(), ", "))
+		}
+	}
+	return cols, nil
+}
+
+// WriteJobs writes jobs in format f, one row per job under a header of
+// cols. JSON ignores cols and writes the full records.
+func WriteJobs(w io.Writer, jobs []job.Job, f JobFormat, cols []JobColumn) error {
+	switch f {
+	case JobsCSV:
+		return writeJobsCSV(w, jobs, cols)
+	case JobsMarkdown:
+		return writeJobsMarkdown(w, jobs, cols)
+	case JobsJSON:
+		if jobs == nil {
+			jobs = []job.Job{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(jobs)
+	}
+	return fmt.Errorf("unknown format %q", f)
+}
+
+func writeJobsCSV(w io.Writer, jobs []job.Job, cols []JobColumn) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(jobHeaders(cols)); err != nil {
+		return err
+	}
+	for _, j := range jobs {
+		row := make([]string, len(cols))
+		for i, c := range cols {
+			row[i] = c.value(j)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeJobsMarkdown(w io.Writer, jobs []job.Job, cols []JobColumn) error {
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(jobHeaders(cols), " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(cols)) + "\n")
+	for _, j := range jobs {
+		cells := make([]string, len(cols))
+		for i, c := range cols {
+			cells[i] = escapeMarkdownCell(c.value(j))
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func jobHeaders(cols []JobColumn) []string {
+	h := make([]string, len(cols))
+	for i, c := range cols {
+		h[i] = c.Header
+	}
+	return h
+}
+
+// formatDay writes t as a date, or "" when unset.
+func formatDay(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
@@ -0,0 +1,96 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/job"
+)
+
+func exportJobs() []job.Job {
+	return []job.Job{
+		{ID: "hn-1", ShortID: 1, Title: `Senior Engineer, "Platform"`, Company: "Acme, Inc.", Location: "Remote",
+			Source: "hn", Score: 92, PostedDate: day("2024-03-01"), URL: "https://acme.com/jobs/1",
+			Description: "Go, Rust and \"some\" SQL.\nRemote | EU only."},
+		{ID: "gh-2", ShortID: 2, Title: "Backend Developer", Company: "Globex", Source: "greenhouse", Score: 64,
+			Status: job.StatusApplied, Applied: true, AppliedDate: day("2024-03-04"), Tags: []string{"go", "k8s"}},
+	}
+}
+
+func TestWriteJobs_Golden(t *testing.T) {
+	cols, err := ParseJobColumns("ref,title,company,score,status,applied,tags,description")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, f := range map[string]JobFormat{"jobs.csv": JobsCSV, "jobs.md": JobsMarkdown} {
+		var buf bytes.Buffer
+		if err := WriteJobs(&buf, exportJobs(), f, cols); err != nil {
+			t.Fatal(err)
+		}
+		checkGolden(t, name, buf.Bytes())
+	}
+}
+
+func TestWriteJobs_CSVRoundTrips(t *testing.T) {
+	cols, _ := ParseJobColumns("title,company,description")
+	var buf bytes.Buffer
+	if err := WriteJobs(&buf, exportJobs(), JobsCSV, cols); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("rows = %d, want header and 2 jobs", len(rows))
+	}
+	want := exportJobs()[0]
+	if got := rows[1]; got[0] != want.Title || got[1] != want.Company || got[2] != want.Description {
+		t.Errorf("row = %q", got)
+	}
+}
+
+func TestWriteJobs_MarkdownKeepsOneRowPerJob(t *testing.T) {
+	cols, _ := ParseJobColumns("title,description")
+	var buf bytes.Buffer
+	if err := WriteJobs(&buf, exportJobs(), JobsMarkdown, cols); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("table has %d lines:\n%s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[2], `Remote \| EU only.`) {
+		t.Errorf("pipe not escaped: %s", lines[2])
+	}
+}
+
+func TestWriteJobs_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJobs(&buf, exportJobs(), JobsJSON, nil); err != nil {
+		t.Fatal(err)
+	}
+	var got []job.Job
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil || len(got) != 2 || got[0].Description != exportJobs()[0].Description {
+		t.Errorf("json = %v, %v", got, err)
+	}
+}
+
+func TestParseJobColumns(t *testing.T) {
+	cols, err := ParseJobColumns("")
+	if err != nil || len(cols) != 7 || cols[0].Name != "title" {
+		t.Errorf("default columns = %v, %v", cols, err)
+	}
+	if cols, err := ParseJobColumns(" URL, Title "); err != nil || cols[0].Name != "url" || cols[1].Name != "title" {
+		t.Errorf("columns = %v, %v", cols, err)
+	}
+	if _, err := ParseJobColumns("title,salery"); err == nil || !strings.Contains(err.Error(), `"salery"`) {
+		t.Errorf("unknown column: %v", err)
+	}
+	if f, err := ParseJobFormat("MD"); err != nil || f != JobsMarkdown || f.Ext() != "md" {
+		t.Errorf("ParseJobFormat(MD) = %q, %v", f, err)
+	}
+}
@@ -0,0 +1,162 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sprayer/src/api/brand"
+)
+
+// WriteLaTeX renders the report as a standalone LaTeX document.
+func WriteLaTeX(w io.Writer, r Report, opts Options) error {
+	h := headers(opts)
+
+	var b strings.Builder
+	b.WriteString(`\documentclass[10pt]{article}
+\usepackage[utf8]{inputenc}
+\usepackage[T1]{fontenc}
+\usepackage[margin=2cm,landscape]{geometry}
+\usepackage{longtable}
+`)
+	if opts.Brand != nil {
+		writeBrand(&b, *opts.Brand)
+	} else {
+		b.WriteString(`\begin{document}
+\section*{Job Application Report}
+`)
+	}
+	b.WriteString(`\begin{tabular}{ll}` + "\n")
+	fmt.Fprintf(&b, "Period: & %s \\\\\n", EscapeLaTeX(r.Period()))
+	fmt.Fprintf(&b, "Total applications: & %d \\\\\n", r.Summary.Total)
+	fmt.Fprintf(&b, "Responses: & %d \\\\\n", r.Summary.Responses)
+	fmt.Fprintf(&b, "Interviews: & %d \\\\\n", r.Summary.Interviews)
+	if r.Summary.Opened+r.Summary.Clicked > 0 {
+		fmt.Fprintf(&b, "Opened / clicked: & %d / %d \\\\\n", r.Summary.Opened, r.Summary.Clicked)
+	}
+	b.WriteString(`\end{tabular}` + "\n\n")
+
+	spec := strings.Repeat("l", len(h))
+	if !opts.RedactNotes {
+		spec = strings.Repeat("l", len(h)-1) + "p{6cm}" // wrap notes
+	}
+	fmt.Fprintf(&b, "\\begin{longtable}{%s}\n", spec)
+	b.WriteString(strings.Join(h, " & ") + ` \\ \hline` + "\n")
+	b.WriteString(`\endhead` + "\n")
+	for _, rw := range r.rows() {
+		cells := rw.fields(opts)
+		for i, c := range cells {
+			cells[i] = EscapeLaTeX(c)
+		}
+		b.WriteString(strings.Join(cells, " & ") + ` \\` + "\n")
+	}
+	b.WriteString(`\end{longtable}` + "\n")
+	b.WriteString(`\end{document}` + "\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeBrand defines the brand colors and font and opens the document
+// with a colored title, followed by the name and tagline when set.
+func writeBrand(b *strings.Builder, br brand.Brand) {
+	b.WriteString(`\usepackage[HTML]{xcolor}` + "\n")
+	fmt.Fprintf(b, "\\definecolor{brandprimary}{HTML}{%s}\n", brand.HTML(br.Primary))
+	fmt.Fprintf(b, "\\definecolor{brandaccent}{HTML}{%s}\n", brand.HTML(br.Accent))
+	switch br.Font {
+	case brand.FontSans:
+		b.WriteString(`\renewcommand{\familydefault}{\sfdefault}` + "\n")
+	case brand.FontMono:
+		b.WriteString(`\renewcommand{\familydefault}{\ttdefault}` + "\n")
+	}
+	b.WriteString(`\begin{document}
+\section*{\color{brandprimary}Job Application Report}
+`)
+	if br.Name != "" {
+		fmt.Fprintf(b, "{\\large %s}", EscapeLaTeX(br.Name))
+		if br.Tagline != "" {
+			fmt.Fprintf(b, " \\textcolor{brandaccent}{%s}", EscapeLaTeX(br.Tagline))
+		}
+		b.WriteString("\n\n")
+	}
+}
+
+// latexEscapes maps each character LaTeX treats specially, and the
+// typographic punctuation pdflatex's default fonts lack, to text that
+// typesets it.
+var latexEscapes = map[rune]string{
+	'\\':     `\textbackslash{}`,
+	'&':      `\&`,
+	'%':      `\%`,
+	'$':      `\$`,
+	'#':      `\#`,
+	'_':      `\_`,
+	'{':      `\{`,
+	'}':      `\}`,
+	'~':      `\textasciitilde{}`,
+	'^':      `\textasciicircum{}`,
+	'\u201c': "``",
+	'\u201d': "''",
+	'\u2018': "`",
+	'\u2019': "'",
+	'\u2013': "--",
+	'\u2014': "---",
+	'\u2212': "-",
+	'\u2026': `\ldots{}`,
+	'\u00a0': "~",
+}
+
+// EscapeLaTeX escapes the characters LaTeX treats specially in text. It
+// is a single pass, so the braces of one expansion are never escaped by
+// another.
+func EscapeLaTeX(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if e, ok := latexEscapes[r]; ok {
+			b.WriteString(e)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// WritePDF renders the report through LaTeX and writes the PDF to path.
+// It needs a LaTeX engine; see CompileLaTeX.
+func WritePDF(path string, r Report, opts Options) error {
+	if _, _, err := latexEngine(); err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "sprayer-report-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	tex, err := os.Create(filepath.Join(dir, "report.tex"))
+	if err != nil {
+		return err
+	}
+	if err := WriteLaTeX(tex, r, opts); err != nil {
+		tex.Close()
+		return err
+	}
+	tex.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), CompileTimeout)
+	defer cancel()
+	built, err := CompileLaTeX(ctx, tex.Name())
+	if err != nil {
+		return err
+	}
+	pdf, err := os.ReadFile(built)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, pdf, 0644)
+}
@@ -0,0 +1,29 @@
+package export
+
+import "testing"
+
+func TestEscapeLaTeX(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{`plain text`, `plain text`},
+		{`C:\Projects`, `C:\textbackslash{}Projects`},
+		{`\\server`, `\textbackslash{}\textbackslash{}server`},
+		{`\textbf{x}`, `\textbackslash{}textbf\{x\}`},
+		{`{}`, `\{\}`},
+		{`}{`, `\}\{`},
+		{`\{`, `\textbackslash{}\{`},
+		{`{\}`, `\{\textbackslash{}\}`},
+		{`R&D 100% $5 #1 a_b`, `R\&D 100\% \$5 \#1 a\_b`},
+		{`~^`, `\textasciitilde{}\textasciicircum{}`},
+		{`\~\^`, `\textbackslash{}\textasciitilde{}\textbackslash{}\textasciicircum{}`},
+		{"\u201cquoted\u201d and \u2018single\u2019", "``quoted'' and `single'"},
+		{"2019\u20132024 \u2014 remote", "2019--2024 --- remote"},
+		{"\u22121 wait\u2026", `-1 wait\ldots{}`},
+		{"10\u00a0km", "10~km"},
+		{"caf\u00e9", "caf\u00e9"},
+	}
+	for _, tt := range tests {
+		if got := EscapeLaTeX(tt.in); got != tt.want {
+			t.Errorf("EscapeLaTeX(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown writes the summary header followed by a table of applications.
+func WriteMarkdown(w io.Writer, r Report, opts Options) error {
+	var b strings.Builder
+	b.WriteString("# Job Application Report\n\n")
+	fmt.Fprintf(&b, "- **Period:** %s\n", r.Period())
+	fmt.Fprintf(&b, "- **Total applications:** %d\n", r.Summary.Total)
+	fmt.Fprintf(&b, "- **Responses:** %d\n", r.Summary.Responses)
+	fmt.Fprintf(&b, "- **Interviews:** %d\n", r.Summary.Interviews)
+	if r.Summary.Opened+r.Summary.Clicked > 0 {
+		fmt.Fprintf(&b, "- **Opened / clicked:** %d / %d\n", r.Summary.Opened, r.Summary.Clicked)
+	}
+	b.WriteString("\n")
+
+	h := headers(opts)
+	b.WriteString("| " + strings.Join(h, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(h)) + "\n")
+	for _, rw := range r.rows() {
+		cells := rw.fields(opts)
+		for i, c := range cells {
+			cells[i] = escapeMarkdownCell(c)
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
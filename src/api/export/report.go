@@ -0,0 +1,129 @@
+// Package export renders application history into shareable documents
+// (CSV, Markdown, LaTeX/PDF), e.g. as proof of job search for visa or
+// tax paperwork, and lists of jobs into CSV, Markdown or JSON.
+package export
+
+import (
+	"sort"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/brand"
+	"sprayer/src/api/redact"
+)
+
+const dateLayout = "2006-01-02"
+
+// Options tweaks report output.
+type Options struct {
+	// RedactNotes drops the free-form notes column.
+	RedactNotes bool
+	// Redact scrubs personal data from every cell; nil leaves them as is.
+	Redact *redact.Redactor
+	// Brand colors the LaTeX/PDF report and puts the name and tagline
+	// under its title; nil renders it plain.
+	Brand *brand.Brand
+}
+
+// Summary is the header block of a report.
+type Summary struct {
+	Total      int
+	Responses  int
+	Interviews int
+	Opened     int // emails opened, for profiles with tracking on
+	Clicked    int
+}
+
+// Report is a chronological record of applications over a period.
+type Report struct {
+	From, To     time.Time
+	Applications []application.Application
+	Summary      Summary
+}
+
+// NewReport orders apps chronologically and computes the summary. Zero
+// bounds are narrowed to the first and last application dates.
+func NewReport(apps []application.Application, from, to time.Time) Report {
+	sorted := make([]application.Application, len(apps))
+	copy(sorted, apps)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].AppliedAt.Before(sorted[j].AppliedAt)
+	})
+
+	r := Report{From: from, To: to, Applications: sorted}
+	for _, a := range sorted {
+		r.Summary.Total++
+		if a.Responded() {
+			r.Summary.Responses++
+		}
+		if a.Interviewed() {
+			r.Summary.Interviews++
+		}
+		if a.OpenedAt != nil {
+			r.Summary.Opened++
+		}
+		if a.ClickedAt != nil {
+			r.Summary.Clicked++
+		}
+	}
+	if len(sorted) > 0 {
+		if r.From.IsZero() {
+			r.From = sorted[0].AppliedAt
+		}
+		if r.To.IsZero() {
+			r.To = sorted[len(sorted)-1].AppliedAt
+		}
+	}
+	return r
+}
+
+// Period renders the report range as "from – to".
+func (r Report) Period() string {
+	if r.From.IsZero() && r.To.IsZero() {
+		return "no applications"
+	}
+	return r.From.Format(dateLayout) + " to " + r.To.Format(dateLayout)
+}
+
+// row is the flattened, display-ready form of an application.
+type row struct {
+	Applied, Company, Title, Method, Status, Replied, Notes string
+}
+
+func (r Report) rows() []row {
+	out := make([]row, 0, len(r.Applications))
+	for _, a := range r.Applications {
+		rw := row{
+			Applied: a.AppliedAt.Format(dateLayout),
+			Company: a.Company,
+			Title:   a.Title,
+			Method:  string(a.Method),
+			Status:  string(a.Status),
+			Notes:   a.Notes,
+		}
+		if a.RepliedAt != nil {
+			rw.Replied = a.RepliedAt.Format(dateLayout)
+		}
+		out = append(out, rw)
+	}
+	return out
+}
+
+func headers(opts Options) []string {
+	h := []string{"Date Applied", "Company", "Position", "Method", "Status", "Reply Date"}
+	if !opts.RedactNotes {
+		h = append(h, "Notes")
+	}
+	return h
+}
+
+func (rw row) fields(opts Options) []string {
+	f := []string{rw.Applied, rw.Company, rw.Title, rw.Method, rw.Status, rw.Replied}
+	if !opts.RedactNotes {
+		f = append(f, rw.Notes)
+	}
+	for i := range f {
+		f[i] = opts.Redact.Scrub(f[i])
+	}
+	return f
+}
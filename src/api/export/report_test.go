@@ -0,0 +1,113 @@
+package export
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/brand"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func day(s string) time.Time {
+	t, _ := time.Parse(dateLayout, s)
+	return t
+}
+
+func dayPtr(s string) *time.Time {
+	t := day(s)
+	return &t
+}
+
+func seededReport() Report {
+	apps := []application.Application{
+		{Company: "Globex", Title: "Platform Engineer", Method: application.MethodPortal,
+			Status: application.StatusInterview, AppliedAt: day("2024-02-12"), RepliedAt: dayPtr("2024-02-20"),
+			Notes: "Referral from | Jane"},
+		{Company: "Acme & Sons", Title: "Senior Go Developer", Method: application.MethodEmail,
+			Status: application.StatusApplied, AppliedAt: day("2024-01-08"),
+			Notes: "Salary 100% remote, $90k_base"},
+		{Company: "Initech", Title: "Backend Engineer", Method: application.MethodEmail,
+			Status: application.StatusRejected, AppliedAt: day("2024-03-01"), RepliedAt: dayPtr("2024-03-15")},
+	}
+	return NewReport(apps, day("2024-01-01"), day("2024-06-30"))
+}
+
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden (run with -update): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s mismatch:\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+func TestReport_Golden(t *testing.T) {
+	writers := map[string]func(*bytes.Buffer, Report, Options) error{
+		"report.csv": func(b *bytes.Buffer, r Report, o Options) error { return WriteCSV(b, r, o) },
+		"report.md":  func(b *bytes.Buffer, r Report, o Options) error { return WriteMarkdown(b, r, o) },
+		"report.tex": func(b *bytes.Buffer, r Report, o Options) error { return WriteLaTeX(b, r, o) },
+	}
+	for name, write := range writers {
+		for _, redact := range []bool{false, true} {
+			golden := name
+			if redact {
+				golden = strings.Replace(name, "report", "report_redacted", 1)
+			}
+			t.Run(golden, func(t *testing.T) {
+				var buf bytes.Buffer
+				if err := write(&buf, seededReport(), Options{RedactNotes: redact}); err != nil {
+					t.Fatal(err)
+				}
+				checkGolden(t, golden, buf.Bytes())
+			})
+		}
+	}
+}
+
+func TestWriteLaTeX_Brand(t *testing.T) {
+	br := brand.Brand{Primary: "#0a3d62", Accent: "#e58", Font: brand.FontSans, Name: "Jane Doe", Tagline: "Go & Rust"}
+	var buf bytes.Buffer
+	if err := WriteLaTeX(&buf, seededReport(), Options{Brand: &br}); err != nil {
+		t.Fatal(err)
+	}
+	tex := buf.String()
+	for _, want := range []string{
+		`\definecolor{brandprimary}{HTML}{0A3D62}`,
+		`\definecolor{brandaccent}{HTML}{EE5588}`,
+		`\renewcommand{\familydefault}{\sfdefault}`,
+		`\section*{\color{brandprimary}Job Application Report}`,
+		`{\large Jane Doe} \textcolor{brandaccent}{Go \& Rust}`,
+	} {
+		if !strings.Contains(tex, want) {
+			t.Errorf("branded report lacks %s", want)
+		}
+	}
+	if strings.Index(tex, `\definecolor`) > strings.Index(tex, `\begin{document}`) {
+		t.Error("colors must be defined in the preamble")
+	}
+}
+
+func TestNewReport_Summary(t *testing.T) {
+	r := seededReport()
+	if r.Summary != (Summary{Total: 3, Responses: 2, Interviews: 1}) {
+		t.Errorf("Summary = %+v", r.Summary)
+	}
+	if r.Applications[0].Company != "Acme & Sons" {
+		t.Errorf("expected chronological order, first is %s", r.Applications[0].Company)
+	}
+}
@@ -1,70 +1,434 @@
 package api
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
+	"sprayer/src/api/application"
+	"sprayer/src/api/health"
 	"sprayer/src/api/job"
 	"sprayer/src/api/profile"
+	"sprayer/src/api/rules"
 	"sprayer/src/api/scraper"
+	"sprayer/src/api/scraperun"
+	"sprayer/src/api/sourcestats"
+	"sprayer/src/api/tracking"
 )
 
 type Handler struct {
 	store        *job.Store
 	profileStore *profile.Store
+
+	scrapeMu sync.Mutex
+	scrape   *ScrapeStatus
+	sources  []scraper.ScraperSource // nil uses scraper.DefaultSources
+
+	dataDir  string         // checked by Ready
+	external []health.Check // optional network checks run by Ready
+
+	appStore *application.Store // set by EnableApplications or EnableTracking
+	signer   *tracking.Signer
+
+	runStore   *scraperun.Store   // set by EnableScrapeHistory
+	statsStore *sourcestats.Store // set by EnableSourceStats
+	ruleStore  *rules.Store       // set by EnableIngestRules
 }
 
 func NewHandler(s *job.Store, p *profile.Store) *Handler {
-	return &Handler{store: s, profileStore: p}
+	return &Handler{store: s, profileStore: p, dataDir: job.DataDir()}
 }
 
+// CheckExternal adds network checks (LLM, SMTP) to readiness.
+func (h *Handler) CheckExternal(checks ...health.Check) {
+	h.external = append(h.external, checks...)
+}
+
+// HealthCheck is the liveness probe: it answers as long as the process
+// serves requests and touches nothing else.
 func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok", "version": "v1"})
 }
 
+// Ready is the readiness probe. It checks the database takes writes and
+// has the expected schema, the data directory is writable, and any
+// external checks; on failure it answers 503 listing the failed checks.
+func (h *Handler) Ready(w http.ResponseWriter, r *http.Request) {
+	checks := append(health.Local(h.store.DB, h.dataDir), h.external...)
+	rep := health.Run(r.Context(), checks...)
+
+	w.Header().Set("Content-Type", "application/json")
+	if !rep.Ready() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(struct {
+			health.Report
+			Failed []health.Result `json:"failed"`
+		}{rep, rep.Failed()})
+		return
+	}
+	json.NewEncoder(w).Encode(rep)
+}
+
+const (
+	defaultJobsLimit = 100
+	maxJobsLimit     = 1000
+)
+
+// JobsPage is the body of GET /jobs: one window of jobs, the number in
+// scope, and the offset of the next window, absent on the last one.
+type JobsPage struct {
+	Jobs       []job.Job `json:"jobs"`
+	Total      int       `json:"total"`
+	Limit      int       `json:"limit"`
+	Offset     int       `json:"offset"`
+	NextOffset *int      `json:"next_offset"`
+}
+
+// ListJobs returns a page of a profile's active jobs. ?profile= selects
+// the profile whose triage state applies (default "default");
+// ?include=hidden,archived,expired widens the set. ?limit= (default 100,
+// at most 1000) and ?offset= or ?page= (from 1) pick the window;
+// ?sort=score|posted_date|company|title and ?order=asc|desc order it.
+//
+// ?profile_id= runs a stored profile's filters, and ?keywords=,
+// ?min_score=, ?location=, ?has_email=, ?hide_applied=, ?exclude_traps=,
+// ?trap_severity= (low, medium or high; implies exclude_traps) and
+// ?posted_after= (a date or RFC 3339 time) filter too, replacing the
+// profile's setting for the same thing. total then counts the jobs that
+// pass.
+//
+// ?profiles=a,b compares stored profiles instead; see compareJobs.
 func (h *Handler) ListJobs(w http.ResponseWriter, r *http.Request) {
-	jobs, err := h.store.All()
+	q := r.URL.Query()
+	if q.Has("profiles") {
+		h.compareJobs(w, r)
+		return
+	}
+	profileID := q.Get("profile")
+	if profileID == "" {
+		profileID = q.Get("profile_id")
+	}
+	if profileID == "" {
+		profileID = "default"
+	}
+	var scope []job.ActiveOption
+	for _, inc := range strings.Split(q.Get("include"), ",") {
+		switch strings.TrimSpace(inc) {
+		case "hidden":
+			scope = append(scope, job.IncludeHidden())
+		case "archived":
+			scope = append(scope, job.IncludeArchived())
+		case "expired":
+			scope = append(scope, job.IncludeExpired())
+		}
+	}
+
+	page, err := parseJobsPage(q)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	
-	// Optional filtering query params could be added here
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(jobs)
-}
-
-func (h *Handler) ScrapeJobs(w http.ResponseWriter, r *http.Request) {
-	// Simple scrape trigger based on query params
-	keywords := r.URL.Query()["keywords"]
-	if len(keywords) == 0 {
-		keywords = []string{"golang", "remote"}
-	}
-	
-	// API only mode for speed via query param?
-	fast := r.URL.Query().Get("fast") == "true"
-	
-	var s job.Scraper
-	if fast {
-		s = scraper.APIOnly()
+	narrow, filter, code, err := h.jobFilter(r.Context(), q)
+	if err != nil {
+		writeError(w, code, err.Error())
+		return
+	}
+	var jobs []job.Job
+	var total int
+	if filter == nil {
+		jobs, total, err = h.store.NarrowedPageContext(r.Context(), profileID, page, narrow, scope...)
 	} else {
-		s = scraper.All(keywords, "Remote")
+		jobs, total, err = h.filteredPage(r.Context(), profileID, page, narrow, filter, scope)
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	body := JobsPage{Jobs: jobs, Total: total, Limit: page.Limit, Offset: page.Offset}
+	if body.Jobs == nil {
+		body.Jobs = []job.Job{}
+	}
+	if next := page.Offset + len(jobs); next < total {
+		body.NextOffset = &next
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// ComparedPage is the body of GET /jobs?profiles=: JobsPage with each
+// job's evaluation against every profile compared.
+type ComparedPage struct {
+	Jobs       []profile.Compared `json:"jobs"`
+	Total      int                `json:"total"`
+	Limit      int                `json:"limit"`
+	Offset     int                `json:"offset"`
+	NextOffset *int               `json:"next_offset"`
+}
+
+// compareJobs answers GET /jobs?profiles=a,b: the jobs any of the stored
+// profiles matches, each with its score for every profile and whether
+// that profile matches it. They are sorted by their score for the first
+// profile, or the one ?by= names, highest first; ?limit=, ?offset= and
+// ?page= pick the window as for a plain listing.
+func (h *Handler) compareJobs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	page, err := parseJobsPage(q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ids := splitList(q.Get("profiles"))
+	if len(ids) == 0 {
+		writeError(w, http.StatusBadRequest, "profiles must name at least one profile")
+		return
+	}
+	by := 0
+	if name := q.Get("by"); name != "" {
+		if by = slices.Index(ids, name); by < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("by must be one of the profiles compared, not %q", name))
+			return
+		}
+	}
+	profiles := make([]profile.Profile, len(ids))
+	for i, id := range ids {
+		stored, err := h.profileStore.ByIDContext(r.Context(), id)
+		if errors.Is(err, sql.ErrNoRows) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("unknown profile %q", id))
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		profiles[i] = *stored
+	}
+
+	compared, err := profile.CompareStored(r.Context(), h.store, profiles, by)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	lo := min(page.Offset, len(compared))
+	hi := min(lo+page.Limit, len(compared))
+	body := ComparedPage{Jobs: compared[lo:hi], Total: len(compared), Limit: page.Limit, Offset: page.Offset}
+	if body.Jobs == nil {
+		body.Jobs = []profile.Compared{}
+	}
+	if hi < len(compared) {
+		body.NextOffset = &hi
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+// parseJobsPage reads the paging query parameters of GET /jobs.
+func parseJobsPage(q url.Values) (job.Page, error) {
+	intParam := func(name string, def int) (int, error) {
+		v := q.Get(name)
+		if v == "" {
+			return def, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%s must be a whole number, got %q", name, v)
+		}
+		return n, nil
+	}
+	limit, err := intParam("limit", defaultJobsLimit)
+	if err != nil {
+		return job.Page{}, err
+	}
+	if limit == 0 || limit > maxJobsLimit {
+		return job.Page{}, fmt.Errorf("limit must be between 1 and %d", maxJobsLimit)
+	}
+	offset, err := intParam("offset", 0)
+	if err != nil {
+		return job.Page{}, err
+	}
+	if q.Get("page") != "" {
+		if q.Get("offset") != "" {
+			return job.Page{}, fmt.Errorf("give offset or page, not both")
+		}
+		n, err := intParam("page", 1)
+		if err != nil || n < 1 {
+			return job.Page{}, fmt.Errorf("page must be 1 or more, got %q", q.Get("page"))
+		}
+		offset = (n - 1) * limit
+	}
+	return job.ParsePage(limit, offset, q.Get("sort"), q.Get("order"))
+}
+
+// jobFilter splits the filters the query asks for into the narrowing SQL
+// runs and a pipeline for the rest, nil when none are left for it. On
+// error it also returns the status to answer with.
+func (h *Handler) jobFilter(ctx context.Context, q url.Values) (job.Narrowing, job.Filter, int, error) {
+	// A profile with the full score range and nothing else set generates
+	// no filters, so explicit parameters alone start from it.
+	p := profile.Profile{MaxScore: 100}
+	filtering := false
+	if id := q.Get("profile_id"); id != "" {
+		stored, err := h.profileStore.ByIDContext(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return job.Narrowing{}, nil, http.StatusNotFound, fmt.Errorf("unknown profile %q", id)
+		}
+		if err != nil {
+			return job.Narrowing{}, nil, http.StatusInternalServerError, err
+		}
+		p, filtering = *stored, true
+	}
+
+	if q.Has("keywords") {
+		p.Keywords, filtering = splitList(q.Get("keywords")), true
+	}
+	if q.Has("location") {
+		p.Locations, filtering = splitList(q.Get("location")), true
+	}
+	if v := q.Get("min_score"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 || n > 100 {
+			return job.Narrowing{}, nil, http.StatusBadRequest, fmt.Errorf("min_score must be between 0 and 100, got %q", v)
+		}
+		p.MinScore, filtering = n, true
+	}
+	for name, dst := range map[string]*bool{"has_email": &p.MustHaveEmail, "exclude_traps": &p.ExcludeTraps, "hide_applied": &p.HideApplied} {
+		if v := q.Get(name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return job.Narrowing{}, nil, http.StatusBadRequest, fmt.Errorf("%s must be true or false, got %q", name, v)
+			}
+			*dst, filtering = b, true
+		}
+	}
+	if v := q.Get("trap_severity"); v != "" {
+		sev, err := job.ParseTrapSeverity(v)
+		if err != nil {
+			return job.Narrowing{}, nil, http.StatusBadRequest, err
+		}
+		p.ExcludeTraps, p.TrapSeverity, filtering = true, sev, true
+	}
+	if v := q.Get("posted_after"); v != "" {
+		t, err := parseDate(v)
+		if err != nil {
+			return job.Narrowing{}, nil, http.StatusBadRequest, fmt.Errorf("posted_after must be a date like 2024-01-31, got %q", v)
+		}
+		p.PostedAfter, filtering = &t, true
+	}
+
+	if !filtering {
+		return job.Narrowing{}, nil, 0, nil
+	}
+	narrow := job.Narrowing{PostedAfter: p.PostedAfter, HasEmail: p.MustHaveEmail}
+	p.PostedAfter, p.MustHaveEmail = nil, false
+	// A zero MaxScore keeps only jobs scored 0, which Narrowing reads as open.
+	if p.MaxScore > 0 {
+		narrow.MinScore, narrow.MaxScore = p.MinScore, p.MaxScore
+		p.MinScore, p.MaxScore = 0, 100
+	}
+	filters := p.GenerateFilters()
+	if len(filters) == 0 {
+		return narrow, nil, 0, nil
+	}
+	return narrow, job.Pipe(filters...), 0, nil
+}
+
+// filteredPage is NarrowedPage for filters SQL cannot run, such as
+// keywords, locations and traps: it loads the whole sorted, narrowed
+// scope, filters it and cuts the window out of what passes.
+func (h *Handler) filteredPage(ctx context.Context, profileID string, page job.Page, narrow job.Narrowing, filter job.Filter, scope []job.ActiveOption) ([]job.Job, int, error) {
+	all, _, err := h.store.NarrowedPageContext(ctx, profileID, job.Page{Sort: page.Sort, Desc: page.Desc}, narrow, scope...)
+	if err != nil {
+		return nil, 0, err
 	}
+	passed := filter(all)
+	lo := min(page.Offset, len(passed))
+	hi := min(lo+page.Limit, len(passed))
+	return passed[lo:hi], len(passed), nil
+}
 
-	go func() {
-		jobs, err := s()
-		if err == nil {
-			h.store.Save(jobs)
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
 		}
-	}()
+	}
+	return out
+}
+
+// parseDate reads a calendar date, taken as midnight UTC, or an RFC 3339
+// time.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// Job serves /jobs/{id}. GET returns the whole job, its description
+// uncut; DELETE removes it, answering 204. An unknown id is a 404.
+func (h *Handler) Job(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/jobs/")
+	if jobID, ok := strings.CutSuffix(id, "/tracking"); ok {
+		h.JobTracking(w, r, jobID)
+		return
+	}
+	if jobID, ok := strings.CutSuffix(id, "/application"); ok {
+		h.JobApplication(w, r, jobID)
+		return
+	}
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
 
-	w.WriteHeader(http.StatusAccepted)
-	json.NewEncoder(w).Encode(map[string]string{"status": "scraping started"})
+	switch r.Method {
+	case http.MethodGet:
+		j, err := h.store.ByIDContext(r.Context(), id)
+		if errors.Is(err, sql.ErrNoRows) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("no job %q", id))
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if j.Truncated {
+			if full, err := h.store.FullDescriptionContext(r.Context(), id); err == nil {
+				j.Description, j.Truncated = full, false
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(j)
+	case http.MethodDelete:
+		err := h.store.DeleteContext(r.Context(), id)
+		if errors.Is(err, sql.ErrNoRows) {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("no job %q", id))
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, DELETE")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
 }
 
 func (h *Handler) ListProfiles(w http.ResponseWriter, r *http.Request) {
-	profiles, err := h.profileStore.All()
+	profiles, err := h.profileStore.AllContext(r.Context())
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
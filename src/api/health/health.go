@@ -0,0 +1,147 @@
+// Package health holds the dependency checks behind the API's readiness
+// endpoint and the doctor command.
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// DefaultTimeout bounds each network check.
+const DefaultTimeout = 2 * time.Second
+
+// Check is one named dependency probe. Run returns nil when the
+// dependency is usable.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Result is the outcome of one Check.
+type Result struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Report is the outcome of a set of checks.
+type Report struct {
+	Status string   `json:"status"` // "ready" or "unavailable"
+	Checks []Result `json:"checks"`
+}
+
+// Ready reports whether every check passed.
+func (r Report) Ready() bool { return r.Status == "ready" }
+
+// Failed lists the checks that did not pass.
+func (r Report) Failed() []Result {
+	var out []Result
+	for _, c := range r.Checks {
+		if !c.OK {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Run runs checks in order and collects their results.
+func Run(ctx context.Context, checks ...Check) Report {
+	rep := Report{Status: "ready"}
+	for _, c := range checks {
+		res := Result{Name: c.Name, OK: true}
+		if err := c.Run(ctx); err != nil {
+			res.OK, res.Error = false, err.Error()
+			rep.Status = "unavailable"
+		}
+		rep.Checks = append(rep.Checks, res)
+	}
+	return rep
+}
+
+// DBWritable writes a row to a scratch table, catching a read-only file
+// or a full disk that reads alone would not.
+func DBWritable(db *sql.DB) Check {
+	return Check{Name: "db_writable", Run: func(ctx context.Context) error {
+		if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS health_probe (id INTEGER PRIMARY KEY, checked_at DATETIME)`); err != nil {
+			return err
+		}
+		_, err := db.ExecContext(ctx, `INSERT OR REPLACE INTO health_probe (id, checked_at) VALUES (1, ?)`, time.Now().UTC())
+		return err
+	}}
+}
+
+// SchemaVersion checks that db was migrated to the schema this binary
+// expects.
+func SchemaVersion(db *sql.DB) Check {
+	return Check{Name: "schema_version", Run: func(ctx context.Context) error {
+		v, err := job.UserVersion(db)
+		if err != nil {
+			return err
+		}
+		if v != job.SchemaVersion {
+			return fmt.Errorf("database schema is version %d, binary expects %d", v, job.SchemaVersion)
+		}
+		return nil
+	}}
+}
+
+// DirWritable checks that files can be created in dir. It does not create
+// dir itself.
+func DirWritable(dir string) Check {
+	return Check{Name: "data_dir_writable", Run: func(ctx context.Context) error {
+		f, err := os.CreateTemp(dir, ".health-*")
+		if err != nil {
+			return err
+		}
+		name := f.Name()
+		f.Close()
+		return os.Remove(name)
+	}}
+}
+
+// LLMReachable checks that the LLM endpoint at baseURL answers HTTP. Any
+// response counts: the point is the network path, not the credentials.
+func LLMReachable(baseURL string, timeout time.Duration) Check {
+	return Check{Name: "llm_reachable", Run: func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, baseURL, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}}
+}
+
+// SMTPReachable checks that a TCP connection to addr (host:port) opens.
+func SMTPReachable(addr string, timeout time.Duration) Check {
+	return Check{Name: "smtp_reachable", Run: func(ctx context.Context) error {
+		if addr == "" {
+			return fmt.Errorf("SMTP is not configured")
+		}
+		d := net.Dialer{Timeout: timeout}
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}}
+}
+
+// Local returns the checks that need no network: database, schema and
+// data directory.
+func Local(db *sql.DB, dataDir string) []Check {
+	return []Check{DBWritable(db), SchemaVersion(db), DirWritable(dataDir)}
+}
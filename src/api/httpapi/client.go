@@ -0,0 +1,259 @@
+// Package httpapi is a small typed JSON client shared by provider
+// integrations (scratch email providers, ATS job APIs). It handles base
+// URLs, auth injection, context, bounded JSON decoding, pagination,
+// Retry-After backoff and uniform errors so each provider only describes
+// its endpoints.
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"sprayer/src/api/offline"
+)
+
+// DefaultMaxBodySize caps decoded response bodies (4MB).
+const DefaultMaxBodySize = 4 << 20
+
+// snippetSize is how much of an error body is kept on Error.
+const snippetSize = 256
+
+// ErrTooLarge is returned when a response body exceeds MaxBodySize.
+var ErrTooLarge = errors.New("httpapi: response body too large")
+
+// Client issues JSON requests against a single API.
+type Client struct {
+	BaseURL     string
+	HTTP        *http.Client
+	MaxBodySize int64
+
+	// Auth decorates outgoing requests (bearer tokens, API keys). It is
+	// applied only to requests for BaseURL's host, so credentials never
+	// follow a pagination link to another site.
+	Auth func(*http.Request) error
+
+	// Feature names what the client is used for in offline errors, such
+	// as "scratch email"; empty names the API by its host.
+	Feature string
+
+	// Reauth is called once when a request comes back 401. If it returns
+	// nil the request is retried with freshly applied Auth.
+	Reauth func(ctx context.Context) error
+
+	// MaxRetryWait is the longest Retry-After waited out before a single
+	// retry; zero never waits. See sendLimited.
+	MaxRetryWait time.Duration
+
+	mu           sync.Mutex
+	limitedUntil time.Time
+	now          func() time.Time                                 // for tests
+	sleep        func(ctx context.Context, d time.Duration) error // for tests
+}
+
+// New returns a client for baseURL with sane defaults.
+func New(baseURL string) *Client {
+	return &Client{
+		BaseURL:      strings.TrimRight(baseURL, "/"),
+		HTTP:         &http.Client{Timeout: 15 * time.Second},
+		MaxBodySize:  DefaultMaxBodySize,
+		MaxRetryWait: DefaultMaxRetryWait,
+	}
+}
+
+// Error describes a non-2xx response.
+type Error struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Snippet    string
+}
+
+func (e *Error) Error() string {
+	if e.Snippet == "" {
+		return fmt.Sprintf("%s %s: HTTP %d", e.Method, e.URL, e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s: HTTP %d: %s", e.Method, e.URL, e.StatusCode, e.Snippet)
+}
+
+// StatusCode extracts the HTTP status from an *Error, or 0.
+func StatusCode(err error) int {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// Get decodes the JSON response of GET path into out.
+func (c *Client) Get(ctx context.Context, path string, out any) error {
+	_, err := c.Do(ctx, http.MethodGet, path, nil, out)
+	return err
+}
+
+// Post sends body as JSON and decodes the response into out.
+func (c *Client) Post(ctx context.Context, path string, body, out any) error {
+	_, err := c.Do(ctx, http.MethodPost, path, body, out)
+	return err
+}
+
+// Delete issues a DELETE request, ignoring any response body.
+func (c *Client) Delete(ctx context.Context, path string) error {
+	_, err := c.Do(ctx, http.MethodDelete, path, nil, nil)
+	return err
+}
+
+// Do performs a request and decodes the JSON response into out (when non-nil).
+// The returned response has its body already consumed; headers remain usable.
+func (c *Client) Do(ctx context.Context, method, path string, body, out any) (*http.Response, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("httpapi: encode body: %w", err)
+		}
+	}
+
+	resp, data, err := c.sendLimited(ctx, method, path, payload)
+	if err != nil && StatusCode(err) == http.StatusUnauthorized && c.Reauth != nil {
+		if rerr := c.Reauth(ctx); rerr != nil {
+			return resp, fmt.Errorf("httpapi: re-authenticate: %w", rerr)
+		}
+		resp, data, err = c.sendLimited(ctx, method, path, payload)
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	if out != nil && len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return resp, fmt.Errorf("httpapi: decode %s %s: %w", method, c.url(path), err)
+		}
+	}
+	return resp, nil
+}
+
+func (c *Client) send(ctx context.Context, method, path string, payload []byte) (*http.Response, []byte, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url(path), reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := offline.CheckHost(c.feature(req.URL.Host), req.URL.Host); err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Auth != nil && c.sameHost(req.URL) {
+		if err := c.Auth(req); err != nil {
+			return nil, nil, fmt.Errorf("httpapi: auth: %w", err)
+		}
+	}
+
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := c.readBody(resp.Body)
+	if err != nil {
+		return resp, nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, data, &Error{
+			Method:     method,
+			URL:        req.URL.String(),
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Snippet:    snippet(data),
+		}
+	}
+	return resp, data, nil
+}
+
+func (c *Client) readBody(r io.Reader) ([]byte, error) {
+	limit := c.MaxBodySize
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
+
+// url resolves path against BaseURL; absolute URLs pass through untouched
+// so pagination links can be followed directly.
+func (c *Client) url(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return c.BaseURL + path
+}
+
+// feature is what an offline error says is unavailable.
+func (c *Client) feature(host string) string {
+	if c.Feature != "" {
+		return c.Feature
+	}
+	return "the " + host + " API"
+}
+
+// sameHost reports whether u is on BaseURL's host, where Auth belongs.
+func (c *Client) sameHost(u *url.URL) bool {
+	base, err := url.Parse(c.BaseURL)
+	return err == nil && strings.EqualFold(base.Host, u.Host)
+}
+
+// snippet is data with its whitespace collapsed, cut to snippetSize bytes
+// on a rune boundary.
+func snippet(data []byte) string {
+	s := strings.Join(strings.Fields(string(data)), " ")
+	if len(s) > snippetSize {
+		cut := snippetSize
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		s = s[:cut] + "..."
+	}
+	return s
+}
+
+// BearerAuth returns an Auth func setting a bearer token obtained from token().
+// The token func is evaluated per request so refreshed tokens are picked up.
+func BearerAuth(token func() string) func(*http.Request) error {
+	return func(r *http.Request) error {
+		if t := token(); t != "" {
+			r.Header.Set("Authorization", "Bearer "+t)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,217 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"sprayer/src/api/offline"
+)
+
+type message struct {
+	ID string `json:"id"`
+}
+
+func TestCollect_HydraPagination(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "", "1":
+			fmt.Fprint(w, `{"hydra:member":[{"id":"a"},{"id":"b"}],"hydra:view":{"hydra:next":"/messages?page=2"}}`)
+		case "2":
+			fmt.Fprint(w, `{"hydra:member":[{"id":"c"}],"hydra:view":{}}`)
+		default:
+			t.Errorf("unexpected page %q", r.URL.RawQuery)
+		}
+	}))
+	defer srv.Close()
+
+	msgs, err := Collect[message](context.Background(), New(srv.URL), "/messages")
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(msgs) != 3 || msgs[2].ID != "c" {
+		t.Errorf("got %+v, want a,b,c", msgs)
+	}
+}
+
+func TestCollect_LinkHeaderPagination(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?cursor=2>; rel="next", <%s/items>; rel="first"`, srv.URL, srv.URL))
+			fmt.Fprint(w, `[{"id":"1"},{"id":"2"}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"id":"3"}]`)
+	}))
+	defer srv.Close()
+
+	items, err := Collect[message](context.Background(), New(srv.URL), "/items")
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	if len(items) != 3 {
+		t.Errorf("got %d items, want 3", len(items))
+	}
+}
+
+func TestCollect_AuthStaysOnBaseHost(t *testing.T) {
+	var elsewhere string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		elsewhere = r.Header.Get("Authorization")
+		fmt.Fprint(w, `[{"id":"2"}]`)
+	}))
+	defer other.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("base host request lacks auth")
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s/items?cursor=2>; rel="next"`, other.URL))
+		fmt.Fprint(w, `[{"id":"1"}]`)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	c.Auth = BearerAuth(func() string { return "secret" })
+	items, err := Collect[message](context.Background(), c, "/items")
+	if err != nil || len(items) != 2 {
+		t.Fatalf("Collect: %d items, %v", len(items), err)
+	}
+	if elsewhere != "" {
+		t.Errorf("auth sent to another host: %q", elsewhere)
+	}
+}
+
+func TestEach_TooManyPages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"hydra:member":[{"id":"a"}],"hydra:view":{"hydra:next":"/loop"}}`)
+	}))
+	defer srv.Close()
+
+	items, err := Collect[message](context.Background(), New(srv.URL), "/loop")
+	if !errors.Is(err, ErrTooManyPages) {
+		t.Errorf("expected ErrTooManyPages, got %v", err)
+	}
+	if len(items) != maxPages {
+		t.Errorf("got %d items, want the %d pages read", len(items), maxPages)
+	}
+}
+
+func TestDo_OfflineNamesFeature(t *testing.T) {
+	defer offline.Force(true)()
+	c := New("https://api.example.com")
+	if err := c.Get(context.Background(), "/me", nil); err == nil || !strings.Contains(err.Error(), "the api.example.com API unavailable") {
+		t.Errorf("offline error = %v", err)
+	}
+	c.Feature = "scratch email"
+	if err := c.Get(context.Background(), "/me", nil); !errors.Is(err, offline.ErrOffline) || !strings.Contains(err.Error(), "scratch email unavailable") {
+		t.Errorf("offline error = %v", err)
+	}
+}
+
+func TestDo_ReauthOn401(t *testing.T) {
+	token := "expired"
+	logins := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"JWT Token expired"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"me"}`)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	c.Auth = BearerAuth(func() string { return token })
+	c.Reauth = func(ctx context.Context) error {
+		logins++
+		token = "fresh"
+		return nil
+	}
+
+	var m message
+	if err := c.Get(context.Background(), "/me", &m); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if m.ID != "me" || logins != 1 {
+		t.Errorf("got id=%q after %d logins, want me after 1", m.ID, logins)
+	}
+}
+
+func TestDo_ReauthFailureSurfaces(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	c.Reauth = func(ctx context.Context) error { return errors.New("bad password") }
+	err := c.Get(context.Background(), "/me", nil)
+	if err == nil || !strings.Contains(err.Error(), "bad password") {
+		t.Errorf("expected re-auth failure, got %v", err)
+	}
+}
+
+func TestDo_OversizedResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":"%s"}`, strings.Repeat("x", 2048))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL)
+	c.MaxBodySize = 1024
+	var m message
+	if err := c.Get(context.Background(), "/big", &m); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+}
+
+func TestDo_ErrorCarriesSnippet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"detail":  "address already used"}`)
+	}))
+	defer srv.Close()
+
+	err := New(srv.URL).Post(context.Background(), "/accounts", map[string]string{"address": "x"}, nil)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *Error, got %T %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity || !strings.Contains(apiErr.Snippet, "address already used") {
+		t.Errorf("unexpected error: %+v", apiErr)
+	}
+	if StatusCode(err) != 422 {
+		t.Errorf("StatusCode = %d", StatusCode(err))
+	}
+}
+
+func TestSnippet_CutsOnRuneBoundary(t *testing.T) {
+	s := snippet([]byte("a" + strings.Repeat("é", snippetSize)))
+	if !utf8.ValidString(s) || !strings.HasSuffix(s, "...") || len(s) > snippetSize+3 {
+		t.Errorf("snippet = %q", s)
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{`<https://x/a?page=2>; rel="next"`, "https://x/a?page=2"},
+		{`<https://x/a?page=1>; rel="prev", <https://x/a?page=3>; rel=next`, "https://x/a?page=3"},
+		{`<https://x/a?page=1>; rel="last"`, ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NextLink(tt.header); got != tt.want {
+			t.Errorf("NextLink(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
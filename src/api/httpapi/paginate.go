@@ -0,0 +1,113 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// maxPages guards against APIs whose next links loop.
+const maxPages = 100
+
+// ErrTooManyPages is returned when a collection still has a next page
+// after maxPages, so a cut listing is never mistaken for a whole one.
+var ErrTooManyPages = errors.New("httpapi: too many pages")
+
+// hydraPage is the JSON-LD collection envelope used by API Platform
+// backends such as mail.tm.
+type hydraPage struct {
+	Member []json.RawMessage `json:"hydra:member"`
+	View   struct {
+		Next string `json:"hydra:next"`
+	} `json:"hydra:view"`
+}
+
+// Collect fetches path and every following page, decoding each item as T.
+// Two pagination styles are understood: hydra collections (items in
+// hydra:member, next page in hydra:view.hydra:next) and plain JSON arrays
+// paginated through an RFC 8288 Link header with rel="next".
+func Collect[T any](ctx context.Context, c *Client, path string) ([]T, error) {
+	var all []T
+	err := Each(ctx, c, path, func(item T) error {
+		all = append(all, item)
+		return nil
+	})
+	return all, err
+}
+
+// Each streams items across pages to fn, stopping at the first error.
+// After maxPages it gives up with ErrTooManyPages.
+func Each[T any](ctx context.Context, c *Client, path string, fn func(T) error) error {
+	next := path
+	for page := 0; next != ""; page++ {
+		if page == maxPages {
+			return fmt.Errorf("%w: %s has more than %d", ErrTooManyPages, path, maxPages)
+		}
+		var raw json.RawMessage
+		resp, err := c.Do(ctx, http.MethodGet, next, nil, &raw)
+		if err != nil {
+			return err
+		}
+
+		items, hydraNext, err := splitPage(raw)
+		if err != nil {
+			return fmt.Errorf("httpapi: page %d of %s: %w", page+1, path, err)
+		}
+		for _, item := range items {
+			var v T
+			if err := json.Unmarshal(item, &v); err != nil {
+				return fmt.Errorf("httpapi: decode item: %w", err)
+			}
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+
+		next = hydraNext
+		if next == "" && resp != nil {
+			next = NextLink(resp.Header.Get("Link"))
+		}
+		if next != "" && !strings.HasPrefix(next, "http") && !strings.HasPrefix(next, "/") {
+			next = "/" + next
+		}
+	}
+	return nil
+}
+
+func splitPage(raw json.RawMessage) ([]json.RawMessage, string, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if strings.HasPrefix(trimmed, "[") {
+		var items []json.RawMessage
+		err := json.Unmarshal(raw, &items)
+		return items, "", err
+	}
+	var page hydraPage
+	if err := json.Unmarshal(raw, &page); err != nil {
+		return nil, "", err
+	}
+	return page.Member, page.View.Next, nil
+}
+
+// NextLink extracts the rel="next" target from a Link header.
+func NextLink(header string) string {
+	for _, part := range strings.Split(header, ",") {
+		segs := strings.Split(part, ";")
+		if len(segs) < 2 {
+			continue
+		}
+		target := strings.TrimSpace(segs[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, param := range segs[1:] {
+			param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+			if param == `rel="next"` || param == "rel=next" {
+				return target[1 : len(target)-1]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,151 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxRetryWait is the longest Retry-After a client sits out before
+// retrying on its own. Longer waits go back to the caller as a
+// *RateLimitError so a poller can reschedule instead of blocking.
+const DefaultMaxRetryWait = 5 * time.Second
+
+// DefaultRateLimitBackoff is how long a client holds off after a 429
+// that carries no usable Retry-After.
+const DefaultRateLimitBackoff = 30 * time.Second
+
+// ErrRateLimited matches every *RateLimitError with errors.Is.
+var ErrRateLimited = errors.New("httpapi: rate limited")
+
+// RateLimitError reports that the API asked the client to back off until
+// Until. Response is the 429 that started the window; it is nil when the
+// request was refused locally because the window was still open.
+type RateLimitError struct {
+	Host     string
+	Until    time.Time
+	Response *Error
+	now      time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	wait := max(e.Until.Sub(e.now), 0).Round(time.Second)
+	return fmt.Sprintf("%s is rate limiting requests; try again in %s (at %s)", e.Host, wait, e.Until.Local().Format("15:04:05"))
+}
+
+func (e *RateLimitError) Is(target error) bool { return target == ErrRateLimited }
+
+func (e *RateLimitError) Unwrap() error {
+	if e.Response == nil {
+		return nil
+	}
+	return e.Response
+}
+
+// RateLimitedUntil returns the end of the rate-limit window err reports.
+func RateLimitedUntil(err error) (time.Time, bool) {
+	var rl *RateLimitError
+	if errors.As(err, &rl) {
+		return rl.Until, true
+	}
+	return time.Time{}, false
+}
+
+// ParseRetryAfter reads a Retry-After header, given either as seconds or
+// as an HTTP date, as a wait from now. A date in the past is no wait.
+func ParseRetryAfter(h string, now time.Time) (time.Duration, bool) {
+	h = strings.TrimSpace(h)
+	if h == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(h); err == nil {
+		return time.Duration(max(secs, 0)) * time.Second, true
+	}
+	if at, err := http.ParseTime(h); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// RateLimitedUntil is the end of the window the API last asked this
+// client to stay quiet for, or zero when requests may go out.
+func (c *Client) RateLimitedUntil() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.clock().Before(c.limitedUntil) {
+		return c.limitedUntil
+	}
+	return time.Time{}
+}
+
+// sendLimited is send behind the client's rate-limit window. Inside a
+// window it fails fast without touching the network. A 429 opens a
+// window from its Retry-After; a short one is waited out and the request
+// retried once, a long one is returned as a *RateLimitError.
+func (c *Client) sendLimited(ctx context.Context, method, path string, payload []byte) (*http.Response, []byte, error) {
+	if until := c.RateLimitedUntil(); !until.IsZero() {
+		return nil, nil, c.rateLimitError(path, until, nil)
+	}
+	for attempt := 0; ; attempt++ {
+		resp, data, err := c.send(ctx, method, path, payload)
+		var apiErr *Error
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+			return resp, data, err
+		}
+
+		now := c.clock()
+		wait, ok := ParseRetryAfter(apiErr.Header.Get("Retry-After"), now)
+		if !ok {
+			wait = DefaultRateLimitBackoff
+		}
+		until := now.Add(wait)
+		c.mu.Lock()
+		c.limitedUntil = until
+		c.mu.Unlock()
+
+		if attempt > 0 || wait > c.MaxRetryWait {
+			return resp, data, c.rateLimitError(path, until, apiErr)
+		}
+		if err := c.wait(ctx, wait); err != nil {
+			return resp, data, err
+		}
+		c.mu.Lock()
+		c.limitedUntil = time.Time{}
+		c.mu.Unlock()
+	}
+}
+
+func (c *Client) rateLimitError(path string, until time.Time, resp *Error) error {
+	host := path
+	if u, err := url.Parse(c.url(path)); err == nil {
+		host = u.Host
+	}
+	return &RateLimitError{Host: host, Until: until, Response: resp, now: c.clock()}
+}
+
+func (c *Client) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// wait sleeps for d or until ctx is done.
+func (c *Client) wait(ctx context.Context, d time.Duration) error {
+	if c.sleep != nil {
+		return c.sleep(ctx, d)
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,149 @@
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// limitedServer answers each request with the next status and
+// Retry-After from script, then 200 once the script runs out.
+func limitedServer(t *testing.T, script ...string) (*httptest.Server, *int) {
+	t.Helper()
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if hits <= len(script) {
+			if script[hits-1] != "" {
+				w.Header().Set("Retry-After", script[hits-1])
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"message":"slow down"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"acc"}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+// fakeTime gives c a clock that only moves when it sleeps.
+func fakeTime(c *Client, start time.Time) (*time.Time, *[]time.Duration) {
+	now := start
+	var slept []time.Duration
+	c.now = func() time.Time { return now }
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		now = now.Add(d)
+		return nil
+	}
+	return &now, &slept
+}
+
+func TestDo_WaitsOutShortRetryAfter(t *testing.T) {
+	srv, hits := limitedServer(t, "2")
+	c := New(srv.URL)
+	_, slept := fakeTime(c, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	var m message
+	if err := c.Get(context.Background(), "/accounts", &m); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if m.ID != "acc" || *hits != 2 {
+		t.Errorf("got %+v after %d requests", m, *hits)
+	}
+	if len(*slept) != 1 || (*slept)[0] != 2*time.Second {
+		t.Errorf("slept %v, want one 2s wait", *slept)
+	}
+	if until := c.RateLimitedUntil(); !until.IsZero() {
+		t.Errorf("still rate limited until %v after a successful retry", until)
+	}
+}
+
+func TestDo_LongRetryAfterFailsFastUntilWindowEnds(t *testing.T) {
+	srv, hits := limitedServer(t, "120")
+	c := New(srv.URL)
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	now, slept := fakeTime(c, start)
+
+	err := c.Post(context.Background(), "/accounts", map[string]string{"address": "x"}, nil)
+	until, ok := RateLimitedUntil(err)
+	if !ok || !until.Equal(start.Add(2*time.Minute)) {
+		t.Fatalf("err = %v, want rate limited until %v", err, start.Add(2*time.Minute))
+	}
+	if !errors.Is(err, ErrRateLimited) || StatusCode(err) != http.StatusTooManyRequests {
+		t.Errorf("err %v should match ErrRateLimited and carry the 429", err)
+	}
+	if len(*slept) != 0 {
+		t.Errorf("slept %v on a long Retry-After", *slept)
+	}
+
+	*now = start.Add(30 * time.Second)
+	err = c.Post(context.Background(), "/accounts", nil, nil)
+	if !errors.Is(err, ErrRateLimited) || *hits != 1 {
+		t.Fatalf("inside the window: err %v after %d requests, want a local refusal", err, *hits)
+	}
+	if !strings.Contains(err.Error(), "try again in 1m30s") {
+		t.Errorf("message %q should give the wait", err)
+	}
+	if got := c.RateLimitedUntil(); !got.Equal(until) {
+		t.Errorf("RateLimitedUntil = %v, want %v", got, until)
+	}
+
+	*now = until.Add(time.Second)
+	if err := c.Post(context.Background(), "/accounts", nil, nil); err != nil || *hits != 2 {
+		t.Errorf("after the window: err %v, %d requests", err, *hits)
+	}
+}
+
+func TestDo_RepeatedLimitGivesUp(t *testing.T) {
+	srv, hits := limitedServer(t, "1", "1", "1")
+	c := New(srv.URL)
+	fakeTime(c, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	if err := c.Get(context.Background(), "/messages", nil); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("err = %v, want rate limited", err)
+	}
+	if *hits != 2 {
+		t.Errorf("%d requests, want the first and one retry", *hits)
+	}
+}
+
+func TestDo_MissingRetryAfterBacksOff(t *testing.T) {
+	srv, _ := limitedServer(t, "")
+	c := New(srv.URL)
+	start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fakeTime(c, start)
+
+	err := c.Get(context.Background(), "/messages", nil)
+	if until, ok := RateLimitedUntil(err); !ok || !until.Equal(start.Add(DefaultRateLimitBackoff)) {
+		t.Errorf("err = %v, want the default backoff", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"30", 30 * time.Second, true},
+		{" 0 ", 0, true},
+		{"Sun, 01 Jun 2025 12:01:30 GMT", 90 * time.Second, true},
+		{"Sun, 01 Jun 2025 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseRetryAfter(tt.in, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseRetryAfter(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Package inbox polls the mailboxes replies to applications arrive in,
+// keeps each message once, and ties it to the job it answers.
+//
+// A Provider fetches messages from one mailbox. Each is polled on its own
+// interval and can be switched off, since hosted providers such as
+// mail.tm rate-limit aggressively.
+package inbox
+
+import (
+	"context"
+	"net/mail"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message is a received email. ProviderID is the provider's own ID for
+// it, which keeps a message from being stored twice; JobID is the job it
+// is a reply about, if any.
+type Message struct {
+	ID         int64     `json:"id"`
+	Provider   string    `json:"provider"`
+	ProviderID string    `json:"provider_id"`
+	MessageID  string    `json:"message_id,omitempty"`
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	Subject    string    `json:"subject"`
+	Body       string    `json:"body"`
+	Date       time.Time `json:"date"`
+	JobID      string    `json:"job_id,omitempty"`
+	Company    string    `json:"company,omitempty"`
+	Read       bool      `json:"read"`
+}
+
+// Provider fetches the messages in one mailbox. Messages already stored
+// may be returned again; they are skipped by ProviderID.
+type Provider interface {
+	Name() string
+	Fetch(ctx context.Context) ([]Message, error)
+}
+
+// Source is a provider with how often it is polled.
+type Source struct {
+	Provider Provider
+	Interval time.Duration
+	Enabled  bool
+}
+
+// DefaultInterval is how often a provider is polled unless configured.
+const DefaultInterval = 5 * time.Minute
+
+// ConfigureSource reads the polling settings for p from the environment:
+// SPRAYER_INBOX_<NAME>_INTERVAL, a duration such as 15m, and
+// SPRAYER_INBOX_<NAME>_ENABLED, 0 or false to switch it off. Unset, p is
+// polled every def.
+func ConfigureSource(p Provider, def time.Duration) Source {
+	prefix := "SPRAYER_INBOX_" + strings.ToUpper(p.Name()) + "_"
+	s := Source{Provider: p, Interval: def, Enabled: true}
+	if d, err := time.ParseDuration(os.Getenv(prefix + "INTERVAL")); err == nil && d > 0 {
+		s.Interval = d
+	}
+	if on, err := strconv.ParseBool(os.Getenv(prefix + "ENABLED")); err == nil {
+		s.Enabled = on
+	}
+	return s
+}
+
+// Correspondent is an address an application went to.
+type Correspondent struct {
+	JobID, Company, Address string
+}
+
+// Matcher ties a message to the job whose application its sender was
+// written to: the same address, else the same company domain.
+type Matcher struct {
+	byAddr, byDomain map[string]Correspondent
+}
+
+// freeMail are domains shared by unrelated senders, never matched on
+// alone.
+var freeMail = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "outlook.com": true, "hotmail.com": true,
+	"yahoo.com": true, "icloud.com": true, "proton.me": true, "protonmail.com": true,
+}
+
+// NewMatcher indexes cs; for an address or domain listed twice the last
+// one wins, so pass the most recent applications last.
+func NewMatcher(cs []Correspondent) *Matcher {
+	m := &Matcher{byAddr: map[string]Correspondent{}, byDomain: map[string]Correspondent{}}
+	for _, c := range cs {
+		addr := address(c.Address)
+		if addr == "" {
+			continue
+		}
+		m.byAddr[addr] = c
+		if d := domain(addr); d != "" && !freeMail[d] {
+			m.byDomain[d] = c
+		}
+	}
+	return m
+}
+
+// Match returns who msg comes from, if it is someone an application went
+// to.
+func (m *Matcher) Match(msg Message) (Correspondent, bool) {
+	addr := address(msg.From)
+	if c, ok := m.byAddr[addr]; ok {
+		return c, true
+	}
+	d := domain(addr)
+	if d == "" {
+		return Correspondent{}, false
+	}
+	c, ok := m.byDomain[d]
+	return c, ok
+}
+
+// address is the bare, lower-cased address of the sender in an address
+// header.
+func address(s string) string {
+	if a, err := mail.ParseAddress(Sender(s)); err == nil {
+		s = a.Address
+	}
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// reverseAliasName matches the sender an alias service such as
+// SimpleLogin writes into the name of a forwarded message's From, "jane
+// at acme.com" for jane@acme.com, the address being its reverse alias.
+var reverseAliasName = regexp.MustCompile(`([^\s"<>@]+) at ([^\s"<>@]+\.[A-Za-z]{2,})`)
+
+// Sender is the From of a message with a forwarding service's reverse
+// alias turned back into the original sender; other addresses are
+// returned as they are.
+func Sender(from string) string {
+	a, err := mail.ParseAddress(from)
+	if err != nil {
+		return from
+	}
+	m := reverseAliasName.FindStringSubmatch(a.Name)
+	if m == nil {
+		return from
+	}
+	name := strings.TrimSpace(strings.Replace(a.Name, m[0], "", 1))
+	name = strings.TrimSpace(strings.TrimSuffix(name, "-"))
+	return (&mail.Address{Name: name, Address: m[1] + "@" + m[2]}).String()
+}
+
+func domain(addr string) string {
+	_, d, _ := strings.Cut(addr, "@")
+	return d
+}
@@ -0,0 +1,227 @@
+package inbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sprayer/src/api/power"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openStore(t *testing.T) *Store {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "inbox.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	s, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// deliver writes a message into dir's new folder under name.
+func deliver(t *testing.T, dir, name, from, subject, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "new"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	raw := "From: " + from + "\r\nTo: me@example.org\r\nSubject: " + subject +
+		"\r\nDate: Mon, 02 Mar 2026 10:00:00 +0000\r\nMessage-ID: <" + name + "@mail>\r\n\r\n" + body
+	if err := os.WriteFile(filepath.Join(dir, "new", name), []byte(raw), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMaildir_Fetch(t *testing.T) {
+	dir := t.TempDir()
+	deliver(t, dir, "1700000000.a.host", "Jane <jane@acme.io>", "=?UTF-8?Q?Re:_Caf=C3=A9?=", "Thanks!\r\n")
+	os.MkdirAll(filepath.Join(dir, "cur"), 0o755)
+	os.Rename(filepath.Join(dir, "new", "1700000000.a.host"), filepath.Join(dir, "cur", "1700000000.a.host:2,S"))
+	multipart := "Content-Type: multipart/alternative; boundary=b\r\n\r\n--b\r\nContent-Type: text/plain\r\n\r\nPlain\r\n--b\r\nContent-Type: text/html\r\n\r\n<p>HTML</p>\r\n--b--\r\n"
+	os.MkdirAll(filepath.Join(dir, "new"), 0o755)
+	os.WriteFile(filepath.Join(dir, "new", "1700000001.b.host"), []byte("From: hr@globex.com\r\nSubject: Interview\r\n"+multipart), 0o644)
+	os.WriteFile(filepath.Join(dir, "new", "junk"), []byte("\x00not mail"), 0o644)
+
+	msgs, err := Maildir{Dir: dir}.Fetch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("fetched %d messages, want 2: %+v", len(msgs), msgs)
+	}
+	byID := map[string]Message{}
+	for _, m := range msgs {
+		byID[m.ProviderID] = m
+	}
+	read := byID["1700000000.a.host"]
+	if read.Subject != "Re: Café" || read.Body != "Thanks!\n" || read.MessageID != "<1700000000.a.host@mail>" || !read.Read {
+		t.Errorf("read message = %+v", read)
+	}
+	if got := byID["1700000001.b.host"].Body; got != "Plain" {
+		t.Errorf("multipart body = %q, want the text/plain part", got)
+	}
+
+	if msgs, err := (Maildir{Dir: filepath.Join(dir, "missing")}).Fetch(context.Background()); err != nil || len(msgs) != 0 {
+		t.Errorf("missing maildir = %v, %v; want nothing", msgs, err)
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	m := NewMatcher([]Correspondent{
+		{JobID: "1", Company: "Acme", Address: "jobs@acme.io"},
+		{JobID: "2", Company: "Globex", Address: "Hiring <HIRING@globex.com>"},
+		{JobID: "3", Company: "Solo", Address: "founder@gmail.com"},
+	})
+	for from, want := range map[string]string{
+		"jobs@acme.io":             "1",
+		"Jane Doe <jane@acme.io>":  "1",
+		"hiring@globex.com":        "2",
+		"founder@gmail.com":        "3",
+		"someone.else@gmail.com":   "",
+		"newsletter@unrelated.dev": "",
+
+		`"Hiring - hiring at globex.com" <ra+x1@simplelogin.co>`: "2",
+	} {
+		c, ok := m.Match(Message{From: from})
+		if ok != (want != "") || c.JobID != want {
+			t.Errorf("Match(%q) = %+v, %v; want job %q", from, c, ok, want)
+		}
+	}
+}
+
+func TestSender(t *testing.T) {
+	for from, want := range map[string]string{
+		`"Jane Doe - jane at acme.com" <ra+x1@simplelogin.co>`: `"Jane Doe" <jane@acme.com>`,
+		`"jane at acme.com" <ra+x1@simplelogin.co>`:            `<jane@acme.com>`,
+		`Jane Doe <jane@acme.com>`:                             `Jane Doe <jane@acme.com>`,
+		`not an address`:                                       `not an address`,
+	} {
+		if got := Sender(from); got != want {
+			t.Errorf("Sender(%q) = %q, want %q", from, got, want)
+		}
+	}
+}
+
+func TestStore_AddSkipsRepeatedMessageID(t *testing.T) {
+	s := openStore(t)
+	for i, m := range []Message{
+		{Provider: "simplelogin", ProviderID: "a/1", MessageID: "<r1@acme.com>"},
+		{Provider: "maildir", ProviderID: "1.host", MessageID: "<r1@acme.com>"},
+		{Provider: "maildir", ProviderID: "2.host"},
+		{Provider: "maildir", ProviderID: "3.host"},
+	} {
+		added, err := s.Add(&m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := i != 1; added != want {
+			t.Errorf("message %d added = %v, want %v", i, added, want)
+		}
+	}
+}
+
+func TestConfigureSource(t *testing.T) {
+	p := Maildir{}
+	if s := ConfigureSource(p, time.Minute); !s.Enabled || s.Interval != time.Minute {
+		t.Errorf("default source = %+v", s)
+	}
+	t.Setenv("SPRAYER_INBOX_MAILDIR_INTERVAL", "15m")
+	t.Setenv("SPRAYER_INBOX_MAILDIR_ENABLED", "false")
+	if s := ConfigureSource(p, time.Minute); s.Enabled || s.Interval != 15*time.Minute {
+		t.Errorf("configured source = %+v", s)
+	}
+}
+
+type failing struct{}
+
+func (failing) Name() string { return "flaky" }
+func (failing) Fetch(context.Context) ([]Message, error) {
+	return nil, errors.New("rate limited")
+}
+
+func TestPoller_Poll(t *testing.T) {
+	s := openStore(t)
+	dir := t.TempDir()
+	deliver(t, dir, "1.host", "Jane <jane@acme.io>", "Re: Application", "Let's talk")
+	deliver(t, dir, "2.host", "promo@shop.example", "Sale", "50% off")
+
+	var replies []Message
+	p := &Poller{
+		Store: s,
+		Sources: []Source{
+			{Provider: Maildir{Dir: dir}, Interval: 5 * time.Minute, Enabled: true},
+			{Provider: failing{}, Interval: time.Minute, Enabled: true},
+			{Provider: Maildir{Dir: t.TempDir()}, Interval: time.Minute, Enabled: false},
+		},
+		Correspondents: func() ([]Correspondent, error) {
+			return []Correspondent{{JobID: "1", Company: "Acme", Address: "jobs@acme.io"}}, nil
+		},
+		OnReply: func(m Message) error { replies = append(replies, m); return nil },
+	}
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	fresh, err := p.Poll(context.Background(), now)
+	if err == nil {
+		t.Error("a failing provider should be reported")
+	}
+	if len(fresh) != 2 {
+		t.Fatalf("first poll stored %d messages, want 2", len(fresh))
+	}
+	if len(replies) != 1 || replies[0].JobID != "1" || replies[0].Company != "Acme" || replies[0].ID == 0 {
+		t.Errorf("replies = %+v, want the Acme message", replies)
+	}
+
+	deliver(t, dir, "3.host", "jane@acme.io", "Re: Re: Application", "Tuesday?")
+	if fresh, _ := p.Poll(context.Background(), now.Add(time.Minute)); len(fresh) != 0 {
+		t.Errorf("polled before the interval passed: %+v", fresh)
+	}
+	fresh, _ = p.Poll(context.Background(), now.Add(5*time.Minute))
+	if len(fresh) != 1 || fresh[0].ProviderID != "3.host" {
+		t.Errorf("second poll = %+v, want only the new message", fresh)
+	}
+
+	all, err := s.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Errorf("stored %d messages, want 3", len(all))
+	}
+	if n, _ := s.Unread(); n != 2 {
+		t.Errorf("unread replies = %d, want 2", n)
+	}
+	if err := s.MarkAllRead(); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := s.Unread(); n != 0 {
+		t.Errorf("unread after MarkAllRead = %d", n)
+	}
+}
+
+func TestPoller_WatchPausesWhilePowerConstrained(t *testing.T) {
+	dir := t.TempDir()
+	deliver(t, dir, "1.host", "jane@acme.io", "Re: Application", "Let's talk")
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Poller{
+		Store:   openStore(t),
+		Sources: []Source{{Provider: Maildir{Dir: dir}, Interval: time.Minute, Enabled: true}},
+		Power: func() power.Decision {
+			cancel()
+			return power.Decision{Metered: true}
+		},
+	}
+	polled := false
+	p.Watch(ctx, func([]Message, error) { polled = true })
+	if polled {
+		t.Error("polled on a metered connection")
+	}
+}
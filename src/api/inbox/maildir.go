@@ -0,0 +1,122 @@
+package inbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Maildir reads the messages delivered to a local Maildir, such as the
+// one mbsync or offlineimap keep in step with a mail account.
+type Maildir struct {
+	Dir string
+}
+
+// DefaultMaildir is the inbox Maildir read unless SPRAYER_INBOX_MAILDIR_DIR
+// names another.
+func DefaultMaildir() Maildir {
+	if dir := os.Getenv("SPRAYER_INBOX_MAILDIR_DIR"); dir != "" {
+		return Maildir{Dir: dir}
+	}
+	return Maildir{Dir: filepath.Join(os.Getenv("HOME"), "Maildir", "INBOX")}
+}
+
+func (Maildir) Name() string { return "maildir" }
+
+// Fetch reads every message in new and cur. A Maildir that does not exist
+// yet holds no messages; a file that does not parse is skipped.
+func (d Maildir) Fetch(ctx context.Context) ([]Message, error) {
+	var out []Message
+	for _, sub := range []string{"new", "cur"} {
+		entries, err := os.ReadDir(filepath.Join(d.Dir, sub))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read maildir: %w", err)
+		}
+		for _, e := range entries {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if e.IsDir() {
+				continue
+			}
+			m, err := readMessage(filepath.Join(d.Dir, sub, e.Name()))
+			if err != nil {
+				continue
+			}
+			// The part after ':' is flags, which change as the
+			// message is read; the unique name is before it.
+			var info string
+			m.ProviderID, info, _ = strings.Cut(e.Name(), ":")
+			_, flags, _ := strings.Cut(info, ",")
+			m.Read = strings.Contains(flags, "S")
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+// readMessage parses the file at path, keeping the first text/plain part
+// of a multipart body.
+func readMessage(path string) (Message, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Message{}, err
+	}
+	defer f.Close()
+	msg, err := mail.ReadMessage(f)
+	if err != nil {
+		return Message{}, err
+	}
+	h := msg.Header
+	dec := new(mime.WordDecoder)
+	subject, err := dec.DecodeHeader(h.Get("Subject"))
+	if err != nil {
+		subject = h.Get("Subject")
+	}
+	m := Message{
+		MessageID: strings.TrimSpace(h.Get("Message-ID")),
+		From:      h.Get("From"),
+		To:        h.Get("To"),
+		Subject:   subject,
+	}
+	if date, err := h.Date(); err == nil {
+		m.Date = date
+	}
+	body, err := textBody(h.Get("Content-Type"), msg.Body)
+	if err != nil {
+		return Message{}, err
+	}
+	m.Body = strings.ReplaceAll(body, "\r\n", "\n")
+	return m, nil
+}
+
+func textBody(contentType string, r io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		b, err := io.ReadAll(r)
+		return string(b), err
+	}
+	mr := multipart.NewReader(r, params["boundary"])
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if ct := p.Header.Get("Content-Type"); ct == "" || strings.HasPrefix(ct, "text/plain") {
+			b, err := io.ReadAll(p)
+			return string(b), err
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sprayer/src/api/notify"
+	"sprayer/src/api/power"
+)
+
+// Tick is how often a Poller is asked to poll; each source is polled
+// only once its own interval has passed.
+const Tick = 30 * time.Second
+
+// Poller fetches from each enabled source when it is due, stores the
+// messages not seen before, and ties them to jobs.
+type Poller struct {
+	Store   *Store
+	Sources []Source
+
+	// Correspondents lists who applications went to, read before each
+	// poll so replies to recent applications match.
+	Correspondents func() ([]Correspondent, error)
+
+	// OnReply is called for each new message tied to a job, to mark the
+	// job replied to.
+	OnReply func(Message) error
+
+	// Power is read before each poll Watch makes. While it says to pause
+	// background work the poll is skipped and the next tick stretched;
+	// nil never holds anything back.
+	Power func() power.Decision
+
+	next map[string]time.Time
+}
+
+// Poll polls the sources due at now and returns the new messages. A
+// source that fails is skipped until its next interval; the errors are
+// joined.
+func (p *Poller) Poll(ctx context.Context, now time.Time) ([]Message, error) {
+	if p.next == nil {
+		p.next = map[string]time.Time{}
+	}
+	var due []Source
+	for _, s := range p.Sources {
+		name := s.Provider.Name()
+		if s.Enabled && !now.Before(p.next[name]) {
+			due = append(due, s)
+			p.next[name] = now.Add(s.Interval)
+		}
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+
+	var matcher *Matcher
+	if p.Correspondents != nil {
+		cs, err := p.Correspondents()
+		if err != nil {
+			return nil, err
+		}
+		matcher = NewMatcher(cs)
+	}
+	var fresh []Message
+	var errs []error
+	for _, s := range due {
+		msgs, err := s.Provider.Fetch(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.Provider.Name(), err))
+			continue
+		}
+		for _, m := range msgs {
+			m.Provider = s.Provider.Name()
+			if matcher != nil {
+				if c, ok := matcher.Match(m); ok {
+					m.JobID, m.Company = c.JobID, c.Company
+				}
+			}
+			added, err := p.Store.Add(&m)
+			if err != nil {
+				return fresh, err
+			}
+			if !added {
+				continue
+			}
+			// Replies to applications, and anything sent to a scratch
+			// address, go to the webhook.
+			if m.JobID != "" || m.Provider != (Maildir{}).Name() {
+				notify.Notify(notify.ReplyReceived(m.From, m.Subject, m.Provider, m.JobID, m.Company))
+			}
+			if m.JobID != "" && p.OnReply != nil {
+				if err := p.OnReply(m); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			fresh = append(fresh, m)
+		}
+	}
+	return fresh, errors.Join(errs...)
+}
+
+// Watch polls every Tick until ctx is done, passing each poll's new
+// messages and error to report. Polls Power holds back are skipped
+// without a report.
+func (p *Poller) Watch(ctx context.Context, report func([]Message, error)) {
+	t := time.NewTimer(0)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		dec := p.power()
+		if !dec.PauseBackground() {
+			report(p.Poll(ctx, time.Now()))
+		}
+		t.Reset(dec.Interval(Tick))
+	}
+}
+
+// power decides what to hold back now.
+func (p *Poller) power() power.Decision {
+	if p.Power == nil {
+		return power.Decision{}
+	}
+	return p.Power()
+}
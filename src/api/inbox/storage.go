@@ -0,0 +1,98 @@
+package inbox
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Store keeps the messages fetched, each once.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore wraps a database connection for the inbox.
+func NewStore(db *sql.DB) (*Store, error) {
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS scratch_messages (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			provider    TEXT NOT NULL,
+			provider_id TEXT NOT NULL,
+			message_id  TEXT DEFAULT '',
+			from_addr   TEXT,
+			to_addr     TEXT,
+			subject     TEXT,
+			body        TEXT,
+			date        DATETIME,
+			job_id      TEXT DEFAULT '',
+			company     TEXT DEFAULT '',
+			read        INTEGER DEFAULT 0,
+			fetched_at  DATETIME,
+			UNIQUE (provider, provider_id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_scratch_messages_job ON scratch_messages(job_id);`)
+	return err
+}
+
+// Add stores m unless its provider already delivered it, or another
+// provider delivered a message with the same Message-ID, as happens when
+// a forwarding alias and the mailbox it forwards to are both polled. It
+// sets m's ID and reports whether m was new.
+func (s *Store) Add(m *Message) (bool, error) {
+	res, err := s.db.Exec(`
+		INSERT OR IGNORE INTO scratch_messages
+			(provider, provider_id, message_id, from_addr, to_addr, subject, body, date, job_id, company, fetched_at)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+		WHERE ? = '' OR NOT EXISTS (SELECT 1 FROM scratch_messages WHERE message_id = ?)`,
+		m.Provider, m.ProviderID, m.MessageID, m.From, m.To, m.Subject, m.Body, m.Date.UTC(), m.JobID, m.Company,
+		time.Now().UTC(), m.MessageID, m.MessageID)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	m.ID, err = res.LastInsertId()
+	return true, err
+}
+
+// All returns the stored messages, newest first.
+func (s *Store) All() ([]Message, error) {
+	rows, err := s.db.Query(`
+		SELECT id, provider, provider_id, message_id, from_addr, to_addr, subject, body, date, job_id, company, read
+		FROM scratch_messages ORDER BY date DESC, id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Message
+	for rows.Next() {
+		var m Message
+		if err := rows.Scan(&m.ID, &m.Provider, &m.ProviderID, &m.MessageID, &m.From, &m.To, &m.Subject,
+			&m.Body, &m.Date, &m.JobID, &m.Company, &m.Read); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+// MarkAllRead marks every stored message read.
+func (s *Store) MarkAllRead() error {
+	_, err := s.db.Exec(`UPDATE scratch_messages SET read = 1 WHERE NOT read`)
+	return err
+}
+
+// Unread counts the messages about a job not yet read.
+func (s *Store) Unread() (int, error) {
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM scratch_messages WHERE NOT read AND job_id != ''`).Scan(&n)
+	return n, err
+}
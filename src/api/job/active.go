@@ -0,0 +1,88 @@
+package job
+
+import "time"
+
+// The "active jobs" predicate is what every listing shows by default: not
+// hidden or archived in the profile, and not past its expiry. It exists
+// twice, as SQL for Store queries and as an in-memory Filter, and the two
+// are cross-checked in tests. Consumers widen it only through the
+// Include* options.
+
+// ActiveOption deliberately widens the active-jobs predicate.
+type ActiveOption func(*activeScope)
+
+type activeScope struct {
+	hidden   bool
+	archived bool
+	expired  bool
+	now      time.Time
+}
+
+// IncludeHidden keeps jobs hidden in the profile.
+func IncludeHidden() ActiveOption { return func(s *activeScope) { s.hidden = true } }
+
+// IncludeArchived keeps jobs archived in the profile.
+func IncludeArchived() ActiveOption { return func(s *activeScope) { s.archived = true } }
+
+// IncludeExpired keeps jobs whose expiry date has passed.
+func IncludeExpired() ActiveOption { return func(s *activeScope) { s.expired = true } }
+
+// IncludeAll disables the predicate entirely.
+func IncludeAll() ActiveOption {
+	return func(s *activeScope) { s.hidden, s.archived, s.expired = true, true, true }
+}
+
+func newActiveScope(opts []ActiveOption) activeScope {
+	s := activeScope{now: time.Now().UTC()}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
+}
+
+// where returns SQL conditions for jobs aliased j joined with their
+// profile state aliased st, plus the arguments they bind.
+func (s activeScope) where() (string, []any) {
+	cond := "1 = 1"
+	var args []any
+	if !s.hidden {
+		cond += " AND COALESCE(st.hidden, 0) = 0"
+	}
+	if !s.archived {
+		cond += " AND COALESCE(st.archived, 0) = 0"
+	}
+	if !s.expired {
+		cond += " AND (j.expires_at IS NULL OR j.expires_at > ?)"
+		args = append(args, s.now)
+	}
+	return cond, args
+}
+
+// match is the in-memory equivalent of where.
+func (s activeScope) match(j Job) bool {
+	if !s.hidden && j.Hidden {
+		return false
+	}
+	if !s.archived && j.Archived {
+		return false
+	}
+	if !s.expired && j.ExpiresAt != nil && !j.ExpiresAt.After(s.now) {
+		return false
+	}
+	return true
+}
+
+// Active keeps only active jobs. Profile state must already be loaded on
+// the jobs, as Store.ForProfile does.
+func Active(opts ...ActiveOption) Filter {
+	scope := newActiveScope(opts)
+	return func(jobs []Job) []Job {
+		var out []Job
+		for _, j := range jobs {
+			if scope.match(j) {
+				out = append(out, j)
+			}
+		}
+		return out
+	}
+}
@@ -0,0 +1,91 @@
+package job
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ids(jobs []Job) string {
+	var out []string
+	for _, j := range jobs {
+		out = append(out, j.ID)
+	}
+	sort.Strings(out)
+	return strings.Join(out, ",")
+}
+
+// TestActive_SQLMatchesFilter seeds every combination of state and checks
+// that Store.ForProfile and the in-memory Active filter agree for every
+// combination of opt-outs.
+func TestActive_SQLMatchesFilter(t *testing.T) {
+	s := openTestStore(t)
+	past := time.Now().Add(-48 * time.Hour)
+	future := time.Now().Add(48 * time.Hour)
+
+	var seed []Job
+	for i, exp := range []*time.Time{nil, &past, &future} {
+		for _, hidden := range []bool{false, true} {
+			for _, archived := range []bool{false, true} {
+				id := string(rune('a'+i)) + map[bool]string{false: "-", true: "h"}[hidden] +
+					map[bool]string{false: "-", true: "a"}[archived]
+				seed = append(seed, Job{ID: id, Title: id, ExpiresAt: exp})
+				if hidden {
+					s.SetHidden(id, "p1", true)
+				}
+				if archived {
+					s.SetArchived(id, "p1", true)
+				}
+			}
+		}
+	}
+	// State recorded for another profile must never leak in.
+	s.SetHidden("a--", "p2", true)
+	if err := s.Save(seed); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	everything, err := s.ForProfile("p1", IncludeAll())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(everything) != len(seed) {
+		t.Fatalf("IncludeAll returned %d of %d jobs", len(everything), len(seed))
+	}
+
+	scopes := map[string][]ActiveOption{
+		"default":          nil,
+		"hidden":           {IncludeHidden()},
+		"archived":         {IncludeArchived()},
+		"expired":          {IncludeExpired()},
+		"hidden+archived":  {IncludeHidden(), IncludeArchived()},
+		"hidden+expired":   {IncludeHidden(), IncludeExpired()},
+		"archived+expired": {IncludeArchived(), IncludeExpired()},
+		"all":              {IncludeAll()},
+	}
+	for name, opts := range scopes {
+		fromSQL, err := s.ForProfile("p1", opts...)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		inMemory := Active(opts...)(everything)
+		if ids(fromSQL) != ids(inMemory) {
+			t.Errorf("%s: SQL=%s in-memory=%s", name, ids(fromSQL), ids(inMemory))
+		}
+	}
+
+	if got := ids(Active()(everything)); got != "a--,c--" {
+		t.Errorf("default active set = %s, want a--,c--", got)
+	}
+}
+
+func TestExcludeAppliedAndArchived(t *testing.T) {
+	jobs := []Job{{ID: "a"}, {ID: "b", Applied: true}, {ID: "c", Archived: true}, {ID: "d", Applied: true, Archived: true}}
+	if got := ExcludeApplied()(jobs); len(got) != 2 || got[0].ID != "a" || got[1].ID != "c" {
+		t.Errorf("ExcludeApplied = %v", got)
+	}
+	if got := ExcludeArchived()(jobs); len(got) != 2 || got[0].ID != "a" || got[1].ID != "b" {
+		t.Errorf("ExcludeArchived = %v", got)
+	}
+}
@@ -0,0 +1,34 @@
+package job
+
+import (
+	"strings"
+	"unicode"
+)
+
+// legalSuffixes are dropped from the end of company names so "Acme, Inc."
+// and "ACME" compare equal.
+var legalSuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "llc": true, "ltd": true, "limited": true,
+	"corp": true, "corporation": true, "co": true, "company": true, "plc": true,
+	"gmbh": true, "ag": true, "sa": true, "sas": true, "sarl": true, "srl": true,
+	"bv": true, "nv": true, "oy": true, "ab": true, "as": true, "aps": true,
+	"pty": true, "ltda": true, "kk": true, "spa": true,
+	"com": true, "io": true, "ai": true, "hq": true,
+}
+
+// NormalizeCompany reduces a company name to a key for matching the same
+// company across sources: lower case, punctuation dropped, and trailing
+// legal forms and domain endings removed. "The Acme Company, Inc." and
+// "acme.io" both become "acme".
+func NormalizeCompany(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&'
+	})
+	if len(words) > 1 && words[0] == "the" {
+		words = words[1:]
+	}
+	for len(words) > 1 && legalSuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
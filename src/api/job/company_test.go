@@ -0,0 +1,25 @@
+package job
+
+import "testing"
+
+func TestNormalizeCompany(t *testing.T) {
+	tests := map[string]string{
+		"Acme":                   "acme",
+		"ACME, Inc.":             "acme",
+		"The Acme Company, Inc.": "acme",
+		"acme.io":                "acme",
+		"Globex GmbH":            "globex",
+		"Initech Ltd":            "initech",
+		"Procter & Gamble Co.":   "procter & gamble",
+		"  Stripe  ":             "stripe",
+		"Inc":                    "inc",
+		"The Company":            "company",
+		"Acme Labs":              "acme labs",
+		"":                       "",
+	}
+	for in, want := range tests {
+		if got := NormalizeCompany(in); got != want {
+			t.Errorf("NormalizeCompany(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
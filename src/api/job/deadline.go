@@ -0,0 +1,116 @@
+package job
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"sprayer/src/api/parse"
+)
+
+// DefaultClosingWindow is how far ahead a deadline counts as closing soon.
+const DefaultClosingWindow = 7 * 24 * time.Hour
+
+// EnvClosingWindow overrides DefaultClosingWindow, as a Go duration
+// ("72h") or a number of days ("5d").
+const EnvClosingWindow = "SPRAYER_CLOSING_WINDOW"
+
+// ClosingWindow returns the configured closing-soon window.
+func ClosingWindow() time.Duration {
+	if d, err := ParseWindow(os.Getenv(EnvClosingWindow)); err == nil && d > 0 {
+		return d
+	}
+	return DefaultClosingWindow
+}
+
+// ParseWindow reads a duration, accepting "Nd" for days.
+func ParseWindow(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid window %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// ClosesAt is when applications close: the earlier of the source's expiry
+// date and a deadline read from the description, or nil if neither is
+// known.
+func (j Job) ClosesAt() *time.Time {
+	switch {
+	case j.Deadline == nil:
+		return j.ExpiresAt
+	case j.ExpiresAt == nil || j.Deadline.Before(*j.ExpiresAt):
+		return j.Deadline
+	default:
+		return j.ExpiresAt
+	}
+}
+
+// ClosingWithin reports whether the job closes between now and now+d.
+func (j Job) ClosingWithin(now time.Time, d time.Duration) bool {
+	at := j.ClosesAt()
+	return at != nil && at.After(now) && !at.After(now.Add(d))
+}
+
+// DeadlineBadge renders the countdown shown in listings ("⏳ 3d", "⏳ 5h")
+// when the job closes within window, and "" otherwise.
+func DeadlineBadge(j Job, now time.Time, window time.Duration) string {
+	if !j.ClosingWithin(now, window) {
+		return ""
+	}
+	left := j.ClosesAt().Sub(now)
+	if left >= 24*time.Hour {
+		return fmt.Sprintf("⏳ %dd", int(left/(24*time.Hour)))
+	}
+	return fmt.Sprintf("⏳ %dh", max(1, int(left/time.Hour)))
+}
+
+// ByDeadlineWithin keeps jobs that close within d from now. Backs the
+// "closing soon" preset.
+func ByDeadlineWithin(d time.Duration) Filter {
+	return func(jobs []Job) []Job {
+		now := time.Now()
+		return Select(jobs, func(j Job) bool { return j.ClosingWithin(now, d) })
+	}
+}
+
+// ByScoreClosingFirst orders by score, breaking ties in favour of jobs
+// closing within window, soonest first.
+func ByScoreClosingFirst(now time.Time, window time.Duration) func(a, b Job) bool {
+	return func(a, b Job) bool {
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		ac, bc := a.ClosingWithin(now, window), b.ClosingWithin(now, window)
+		if ac != bc {
+			return ac
+		}
+		return ac && a.ClosesAt().Before(*b.ClosesAt())
+	}
+}
+
+// ExtractDeadlines reads application deadlines from descriptions, relative
+// to the posting date. Jobs that already have one are left alone.
+func ExtractDeadlines() Filter {
+	return func(jobs []Job) []Job {
+		return Map(jobs, func(j Job) Job {
+			if j.Deadline != nil {
+				return j
+			}
+			ref := j.PostedDate
+			if ref.IsZero() {
+				ref = time.Now()
+			}
+			if d, ok := parse.ExtractDeadline(j.Description, ref); ok {
+				j.Deadline, j.DeadlineText = &d.At, d.Sentence
+			}
+			return j
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package job
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeadline_ClosingSoon(t *testing.T) {
+	at := time.Date(2025, time.June, 4, 12, 0, 0, 0, time.UTC)
+	in := func(d time.Duration) *time.Time { t := at.Add(d); return &t }
+	week := 7 * 24 * time.Hour
+
+	jobs := []Job{
+		{ID: "none", Score: 50},
+		{ID: "3d", Score: 50, Deadline: in(3 * 24 * time.Hour)},
+		{ID: "5h", Score: 50, ExpiresAt: in(5 * time.Hour)},
+		{ID: "far", Score: 50, Deadline: in(30 * 24 * time.Hour)},
+		{ID: "past", Score: 50, Deadline: in(-time.Hour)},
+		{ID: "top", Score: 80},
+		// The source's expiry wins when it is earlier than the text.
+		{ID: "both", Score: 40, Deadline: in(20 * 24 * time.Hour), ExpiresAt: in(26 * time.Hour)},
+	}
+
+	badges := map[string]string{"3d": "⏳ 3d", "5h": "⏳ 5h", "both": "⏳ 1d"}
+	for _, j := range jobs {
+		if got := DeadlineBadge(j, at, week); got != badges[j.ID] {
+			t.Errorf("%s: badge %q, want %q", j.ID, got, badges[j.ID])
+		}
+	}
+
+	if got := ids(Select(jobs, func(j Job) bool { return j.ClosingWithin(at, week) })); got != "3d,5h,both" {
+		t.Errorf("closing within a week = %s", got)
+	}
+
+	sorted := SortBy(ByScoreClosingFirst(at, week))(jobs)
+	var order []string
+	for _, j := range sorted {
+		order = append(order, j.ID)
+	}
+	want := []string{"top", "5h", "3d"} // then the non-closing 50s in any order, then "both"
+	for i, id := range want {
+		if order[i] != id {
+			t.Fatalf("order = %v, want prefix %v", order, want)
+		}
+	}
+	if order[len(order)-1] != "both" {
+		t.Errorf("order = %v: a closing job must not jump a higher score", order)
+	}
+}
+
+func TestDeadline_ByDeadlineWithin(t *testing.T) {
+	soon := time.Now().Add(48 * time.Hour)
+	later := time.Now().Add(10 * 24 * time.Hour)
+	jobs := []Job{{ID: "a", Deadline: &soon}, {ID: "b", Deadline: &later}, {ID: "c"}}
+	if got := ids(ByDeadlineWithin(72 * time.Hour)(jobs)); got != "a" {
+		t.Errorf("ByDeadlineWithin(72h) = %s", got)
+	}
+}
+
+func TestDeadline_ExtractedAndStored(t *testing.T) {
+	s := openTestStore(t)
+	posted := time.Now().UTC().Truncate(time.Second)
+	jobs := ExtractDeadlines()([]Job{
+		{ID: "d", Title: "Go dev", PostedDate: posted, Description: "Great team. Applications close in 5 days. Apply now."},
+		{ID: "n", Title: "Rust dev", PostedDate: posted, Description: "No rush, we hire year round."},
+	})
+	if err := s.Save(jobs); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.ByID("d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Deadline == nil || got.DeadlineText != "Applications close in 5 days." {
+		t.Fatalf("deadline = %v %q", got.Deadline, got.DeadlineText)
+	}
+	if days := got.Deadline.Sub(posted).Hours() / 24; days < 5 || days > 6 {
+		t.Errorf("deadline %s is not 5 days after %s", got.Deadline, posted)
+	}
+	if n, _ := s.ByID("n"); n.Deadline != nil || n.DeadlineText != "" {
+		t.Errorf("unexpected deadline on n: %v %q", n.Deadline, n.DeadlineText)
+	}
+}
+
+func TestParseWindow(t *testing.T) {
+	for in, want := range map[string]time.Duration{"72h": 72 * time.Hour, "5d": 5 * 24 * time.Hour} {
+		if got, err := ParseWindow(in); err != nil || got != want {
+			t.Errorf("ParseWindow(%q) = %v, %v", in, got, err)
+		}
+	}
+	if _, err := ParseWindow("xd"); err == nil {
+		t.Error("ParseWindow(xd) accepted")
+	}
+}
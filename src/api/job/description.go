@@ -0,0 +1,121 @@
+package job
+
+import (
+	"context"
+	"database/sql"
+
+	"sprayer/src/api/parse"
+)
+
+// DescriptionLimit caps the description kept on a job row. Some sources
+// hand over whole career pages; the rest of such a description lives in
+// job_description_overflow and is read only by FullDescription.
+const DescriptionLimit = 16 << 10
+
+// migrateDescriptions creates the overflow table and caps rows saved
+// before the limit existed.
+func migrateDescriptions(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_description_overflow (
+			job_id    TEXT PRIMARY KEY,
+			full_text TEXT NOT NULL
+		)`); err != nil {
+		return err
+	}
+	rows, err := db.Query(`SELECT id, description FROM jobs WHERE length(CAST(description AS BLOB)) > ?`, DescriptionLimit)
+	if err != nil {
+		return err
+	}
+	var oversized []Job
+	for rows.Next() {
+		var j Job
+		if err := rows.Scan(&j.ID, &j.Description); err != nil {
+			rows.Close()
+			return err
+		}
+		oversized = append(oversized, j)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || len(oversized) == 0 {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, j := range oversized {
+		j.CapDescription()
+		if _, err := tx.Exec(`UPDATE jobs SET description = ?, description_truncated = 1 WHERE id = ?`, j.Description, j.ID); err != nil {
+			return err
+		}
+		if err := saveOverflow(tx, j); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CapDescription cuts a description over DescriptionLimit down to its key
+// sections, keeping the original in FullDescription for the overflow
+// table. Scoring and filters see only the capped text from then on.
+// Capping twice does nothing.
+func (j *Job) CapDescription() {
+	capped, cut := parse.TruncateDescription(j.Description, DescriptionLimit)
+	if !cut {
+		return
+	}
+	j.FullDescription = j.Description
+	j.Description = capped
+	j.Truncated = true
+}
+
+// CapDescriptions caps every job's description, so filters run after it
+// see what Save will store.
+func CapDescriptions() Filter {
+	return func(jobs []Job) []Job {
+		return Map(jobs, func(j Job) Job {
+			j.CapDescription()
+			return j
+		})
+	}
+}
+
+// execer is satisfied by *sql.DB and *sql.Tx.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// saveOverflow keeps a capped job's full text. A job saved untruncated
+// drops any overflow left from an earlier, longer version; a truncated
+// job reloaded from the store has no FullDescription and keeps its row.
+func saveOverflow(db execer, j Job) error {
+	switch {
+	case j.FullDescription != "":
+		_, err := db.Exec(`INSERT OR REPLACE INTO job_description_overflow (job_id, full_text) VALUES (?, ?)`, j.ID, j.FullDescription)
+		return err
+	case !j.Truncated:
+		_, err := db.Exec(`DELETE FROM job_description_overflow WHERE job_id = ?`, j.ID)
+		return err
+	}
+	return nil
+}
+
+// FullDescription returns a job's description as scraped, including any
+// part cut by DescriptionLimit. Only views that show the whole posting,
+// or an LLM prompt that asks for it, should need this.
+func (s *Store) FullDescription(id string) (string, error) {
+	return s.FullDescriptionContext(context.Background(), id)
+}
+
+// FullDescriptionContext is FullDescription, abandoning the query if ctx
+// is cancelled.
+func (s *Store) FullDescriptionContext(ctx context.Context, id string) (string, error) {
+	var text string
+	err := s.DB.QueryRowContext(ctx, `
+		SELECT COALESCE(o.full_text, j.description)
+		FROM jobs j LEFT JOIN job_description_overflow o ON o.job_id = j.id
+		WHERE j.id = ?`, id).Scan(&text)
+	return text, err
+}
@@ -0,0 +1,106 @@
+package job
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/parse"
+)
+
+// hugeDescription is a career page with the requirements buried after a
+// long company history, and a keyword only in the part that gets cut.
+func hugeDescription() string {
+	return "Requirements:\n- Go and Postgres\n\n" +
+		"OUR STORY\n" + strings.Repeat("We started in a garage and kept going. ", 2000) +
+		"\nTeam rituals:\nWe also use Haskell for internal tooling."
+}
+
+func TestSave_CapsDescriptionAndKeepsFullText(t *testing.T) {
+	s := openTestStore(t)
+	full := hugeDescription()
+	if err := s.Save([]Job{{ID: "big", Title: "Engineer", Description: full}}); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := s.ByID("big")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !j.Truncated || len(j.Description) > DescriptionLimit || !strings.Contains(j.Description, "Go and Postgres") {
+		t.Errorf("stored description: truncated=%v, %d bytes", j.Truncated, len(j.Description))
+	}
+	if got, _ := s.FullDescription("big"); got != full {
+		t.Errorf("FullDescription returned %d bytes, want the %d scraped", len(got), len(full))
+	}
+
+	// Rescoring saves jobs as loaded; the overflow must survive that.
+	j.Score = 70
+	if err := s.Save([]Job{*j}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.FullDescription("big"); got != full {
+		t.Error("re-saving a loaded job lost the full text")
+	}
+
+	// A rescrape with a short description replaces it entirely.
+	if err := s.Save([]Job{{ID: "big", Description: "Now short."}}); err != nil {
+		t.Fatal(err)
+	}
+	if j, _ := s.ByID("big"); j.Truncated {
+		t.Error("short description still marked truncated")
+	}
+	if got, _ := s.FullDescription("big"); got != "Now short." {
+		t.Errorf("FullDescription = %q after a short rescrape", got)
+	}
+}
+
+// Filters and scoring run on the capped description by design: a term
+// that only appears in the overflow no longer matches. The pipeline caps
+// before scoring so a scraped job and a rescored one agree.
+func TestCapDescriptions_FiltersSeeCappedText(t *testing.T) {
+	jobs := []Job{{ID: "big", Description: hugeDescription()}}
+	if got := ByKeywords([]string{"haskell"})(jobs); len(got) != 1 {
+		t.Fatal("the uncapped description should match")
+	}
+	capped := CapDescriptions()(jobs)
+	if got := ByKeywords([]string{"haskell"})(capped); len(got) != 0 {
+		t.Error("a term only in the overflow still matched after capping")
+	}
+	if got := ByKeywords([]string{"postgres"})(capped); len(got) != 1 {
+		t.Error("the requirements section should still match after capping")
+	}
+}
+
+func TestMigrate_CapsExistingRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sprayer.db")
+	s, err := OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full := hugeDescription()
+	if err := s.Save([]Job{{ID: "old", Title: "Engineer"}}); err != nil {
+		t.Fatal(err)
+	}
+	// As a binary without the limit would have stored it.
+	if _, err := s.DB.Exec(`UPDATE jobs SET description = ? WHERE id = 'old'`, full); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	j, err := s.ByID("old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !j.Truncated || !strings.Contains(j.Description, parse.TruncatedNote) {
+		t.Errorf("existing row not capped: truncated=%v, %d bytes", j.Truncated, len(j.Description))
+	}
+	if got, _ := s.FullDescription("old"); got != full {
+		t.Error("migration lost the full text")
+	}
+}
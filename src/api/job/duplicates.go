@@ -0,0 +1,251 @@
+package job
+
+import (
+	"database/sql"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// URLKey reduces a posting URL to a key for spotting the same posting
+// under another ID: scheme, "www.", fragment, trailing slash and
+// tracking parameters dropped, the host lower-cased and the remaining
+// query sorted. It is empty for an empty or unparsable URL.
+func URLKey(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	q := u.Query()
+	for k := range q {
+		if lk := strings.ToLower(k); strings.HasPrefix(lk, "utm_") || lk == "ref" || lk == "source" {
+			q.Del(k)
+		}
+	}
+	key := host + strings.TrimRight(u.Path, "/")
+	if len(q) > 0 {
+		key += "?" + q.Encode() // Encode sorts by key
+	}
+	return key
+}
+
+// TitleKey is the fuzzy key for the same role posted twice: the
+// normalized company and location with j's title lower-cased,
+// punctuation ignored and bracketed work-mode asides such as "(Remote)"
+// dropped. Other asides stay, so "Engineer (Dublin)" and "Engineer (New
+// York)" differ. It is empty unless both company and title are known.
+func TitleKey(j Job) string {
+	c := NormalizeCompany(j.Company)
+	var b, aside strings.Builder
+	depth := 0
+	for _, r := range strings.ToLower(j.Title) {
+		switch {
+		case r == '(' || r == '[':
+			if depth == 0 {
+				aside.Reset()
+			}
+			depth++
+		case r == ')' || r == ']':
+			if depth == 1 && !workMode(aside.String()) {
+				b.WriteString(" " + aside.String() + " ")
+			}
+			depth = max(depth-1, 0)
+		case depth > 0:
+			aside.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	t := strings.Join(strings.FieldsFunc(b.String(), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	}), " ")
+	if c == "" || t == "" {
+		return ""
+	}
+	return c + "|" + t + "|" + strings.ToLower(NormalizeLocation(Job{Location: j.Location}))
+}
+
+// workMode reports whether a title's aside only says how the job is
+// worked, as "remote" or "hybrid" do.
+func workMode(aside string) bool {
+	switch NormalizeLocation(Job{Location: aside}) {
+	case "Remote", "Hybrid", "On-site":
+		return true
+	}
+	return false
+}
+
+// dupIndex maps the URL and title keys of jobs to the ID of the first
+// job seen with them.
+type dupIndex struct {
+	byURL   map[string]string
+	byTitle map[string]titleEntry
+}
+
+// titleEntry is the first job seen with a title key, and whether it had
+// a URL key.
+type titleEntry struct {
+	id     string
+	hasURL bool
+}
+
+func newDupIndex() *dupIndex {
+	return &dupIndex{byURL: map[string]string{}, byTitle: map[string]titleEntry{}}
+}
+
+// match returns the ID of an earlier job j duplicates, or "". Company
+// and title match only where one side has no URL: two postings at
+// different URLs are different openings, however alike their titles.
+func (d *dupIndex) match(j Job) string {
+	u := URLKey(j.URL)
+	if u != "" {
+		if id, ok := d.byURL[u]; ok {
+			return id
+		}
+	}
+	if k := TitleKey(j); k != "" {
+		if e, ok := d.byTitle[k]; ok && (u == "" || !e.hasURL) {
+			return e.id
+		}
+	}
+	return ""
+}
+
+// add records j's keys under id, unless an earlier job holds them.
+func (d *dupIndex) add(j Job, id string) {
+	u := URLKey(j.URL)
+	if u != "" {
+		if _, ok := d.byURL[u]; !ok {
+			d.byURL[u] = id
+		}
+	}
+	if k := TitleKey(j); k != "" {
+		if _, ok := d.byTitle[k]; !ok {
+			d.byTitle[k] = titleEntry{id: id, hasURL: u != ""}
+		}
+	}
+}
+
+// MatchStored prepares scraped jobs for Save. A job that repeats a
+// stored one by URL or by company, title and location (see match) takes the stored job's ID,
+// so saving it updates that row, and a job repeating an earlier one in
+// jobs is dropped. It returns the jobs left and how many were merged.
+func (s *Store) MatchStored(jobs []Job) ([]Job, int, error) {
+	rows, err := s.DB.Query(`SELECT id, url, company, title, location FROM jobs ORDER BY short_id, id`)
+	if err != nil {
+		return nil, 0, err
+	}
+	stored := map[string]bool{}
+	idx := newDupIndex()
+	for rows.Next() {
+		var j Job
+		var u, company, title, location sql.NullString
+		if err := rows.Scan(&j.ID, &u, &company, &title, &location); err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		j.URL, j.Company, j.Title, j.Location = u.String, company.String, title.String, location.String
+		stored[j.ID] = true
+		idx.add(j, j.ID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	var out []Job
+	seen := map[string]bool{}
+	merged := 0
+	for _, j := range jobs {
+		id := j.ID
+		if !stored[id] {
+			if dup := idx.match(j); dup != "" {
+				id = dup
+			}
+		}
+		if seen[id] || id != j.ID {
+			merged++
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		idx.add(j, id)
+		j.ID = id
+		out = append(out, j)
+	}
+	return out, merged, nil
+}
+
+// DuplicateMerge is a stored job folded into the one it duplicated.
+type DuplicateMerge struct {
+	From string // deleted
+	Into string // kept
+}
+
+// MergeDuplicates folds stored jobs that repeat one another, by URL or
+// by company, title and location (see match), into one each. The job kept is an applied one
+// if any, else the oldest. Triage state moves to it unless it has its
+// own for that profile, as do a status and its history; the duplicates
+// are then deleted.
+func (s *Store) MergeDuplicates() ([]DuplicateMerge, error) {
+	rows, err := s.DB.Query(`SELECT ` + jobColumns + ` FROM jobs`)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := scanJobs(rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(jobs, func(a, b int) bool {
+		if jobs[a].Applied != jobs[b].Applied {
+			return jobs[a].Applied
+		}
+		if jobs[a].ShortID != jobs[b].ShortID {
+			return jobs[a].ShortID < jobs[b].ShortID
+		}
+		return jobs[a].ID < jobs[b].ID
+	})
+
+	var merges []DuplicateMerge
+	idx := newDupIndex()
+	for _, j := range jobs {
+		if into := idx.match(j); into != "" {
+			merges = append(merges, DuplicateMerge{From: j.ID, Into: into})
+			idx.add(j, into)
+			continue
+		}
+		idx.add(j, j.ID)
+	}
+	if len(merges) == 0 {
+		return nil, nil
+	}
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	for _, m := range merges {
+		if _, err := tx.Exec(`
+			INSERT OR IGNORE INTO job_profile_state (job_id, profile_id, hidden, starred, verdict, updated_at, archived, score)
+			SELECT ?, profile_id, hidden, starred, verdict, updated_at, archived, score FROM job_profile_state WHERE job_id = ?`,
+			m.Into, m.From); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`UPDATE jobs SET status = (SELECT status FROM jobs WHERE id = ?) WHERE id = ? AND status = ''`,
+			m.From, m.Into); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(`UPDATE job_status_history SET job_id = ? WHERE job_id = ?`, m.Into, m.From); err != nil {
+			return nil, err
+		}
+		if err := deleteJob(tx, m.From); err != nil {
+			return nil, err
+		}
+	}
+	return merges, tx.Commit()
+}
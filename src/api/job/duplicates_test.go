@@ -0,0 +1,171 @@
+package job
+
+import (
+	"testing"
+	"time"
+)
+
+func TestURLKey(t *testing.T) {
+	same := []string{
+		"https://www.example.com/jobs/42/",
+		"http://example.com/jobs/42?utm_source=hn&ref=feed",
+		"https://EXAMPLE.com/jobs/42#apply",
+	}
+	for _, u := range same {
+		if got := URLKey(u); got != "example.com/jobs/42" {
+			t.Errorf("URLKey(%q) = %q", u, got)
+		}
+	}
+	if URLKey("https://boards.example.com/acme?gh_jid=1") == URLKey("https://boards.example.com/acme?gh_jid=2") {
+		t.Error("query parameters naming the posting were dropped")
+	}
+	if URLKey("") != "" || URLKey("not a url") != "" {
+		t.Error("a missing URL has a key")
+	}
+}
+
+func TestTitleKey(t *testing.T) {
+	a := TitleKey(Job{Company: "Acme, Inc.", Title: "Senior Go Engineer (Remote)", Location: "Berlin"})
+	b := TitleKey(Job{Company: "acme", Title: "Senior Go engineer", Location: " Berlin "})
+	if a == "" || a != b {
+		t.Errorf("TitleKey = %q and %q, want equal", a, b)
+	}
+	if TitleKey(Job{Company: "Acme", Title: "Staff Go Engineer", Location: "Berlin"}) == a {
+		t.Error("different titles share a key")
+	}
+	if TitleKey(Job{Company: "Acme", Title: "Senior Go Engineer", Location: "Dublin"}) == a {
+		t.Error("different locations share a key")
+	}
+	if TitleKey(Job{Company: "Acme", Title: "Software Engineer (Dublin)"}) == TitleKey(Job{Company: "Acme", Title: "Software Engineer (New York)"}) {
+		t.Error("locations named in the title share a key")
+	}
+	if TitleKey(Job{Title: "Senior Go Engineer"}) != "" {
+		t.Error("a job without a company has a key")
+	}
+}
+
+func TestStore_SaveKeepsApplied(t *testing.T) {
+	s := openTestStore(t)
+	applied := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	if err := s.Save([]Job{{ID: "a", Title: "Go Engineer", Applied: true, AppliedDate: applied}}); err != nil {
+		t.Fatal(err)
+	}
+	// A re-scrape knows nothing of the application.
+	if err := s.Save([]Job{{ID: "a", Title: "Go Engineer II"}}); err != nil {
+		t.Fatal(err)
+	}
+	j, err := s.ByID("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.Title != "Go Engineer II" || !j.Applied || !j.AppliedDate.Equal(applied) {
+		t.Errorf("after re-scrape: title %q, applied %v on %v", j.Title, j.Applied, j.AppliedDate)
+	}
+}
+
+func TestStore_MatchStored(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{
+		{ID: "remoteok-1", Company: "Acme", Title: "Go Engineer", URL: "https://acme.com/jobs/1"},
+		{ID: "hn-2", Company: "Globex", Title: "Rust Engineer"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, merged, err := s.MatchStored([]Job{
+		{ID: "remoteok-1", Company: "Acme", Title: "Go Engineer", URL: "https://acme.com/jobs/1"},
+		{ID: "gh-acme-1", Company: "Acme Inc", Title: "Go Engineer", URL: "https://www.acme.com/jobs/1/?utm_source=x"},
+		{ID: "hn-9", Company: "Globex Corp", Title: "Rust engineer (Remote)"},
+		{ID: "new-1", Company: "Initech", Title: "SRE", URL: "https://initech.com/sre"},
+		{ID: "new-2", Company: "Initech", Title: "SRE", URL: "https://initech.com/sre?ref=board"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, j := range jobs {
+		ids = append(ids, j.ID)
+	}
+	want := []string{"remoteok-1", "hn-2", "new-1"}
+	if len(ids) != len(want) || ids[0] != want[0] || ids[1] != want[1] || ids[2] != want[2] {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+	if merged != 3 {
+		t.Errorf("merged = %d, want 3", merged)
+	}
+}
+
+func TestStore_MergeDuplicates(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{
+		{ID: "old", Company: "Acme", Title: "Go Engineer", URL: "https://acme.com/jobs/1"},
+		{ID: "applied", Company: "Acme", Title: "Go Engineer", URL: "https://acme.com/jobs/1?utm_medium=feed", Applied: true},
+		{ID: "retitled", Company: "ACME Inc.", Title: "Go engineer"},
+		{ID: "other", Company: "Globex", Title: "Go Engineer"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetStarred("old", "alice", true); err != nil {
+		t.Fatal(err)
+	}
+
+	merges, err := s.MergeDuplicates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(merges) != 2 {
+		t.Fatalf("merges = %v, want 2", merges)
+	}
+	for _, m := range merges {
+		if m.Into != "applied" {
+			t.Errorf("%s merged into %s, want the applied job", m.From, m.Into)
+		}
+	}
+	all, _ := s.All()
+	if got := byID(all); len(got) != 2 || got["applied"].ID == "" || got["other"].ID == "" {
+		t.Errorf("left %v", got)
+	}
+	starred, err := s.ForProfile("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept := byID(starred)["applied"]; !kept.Starred {
+		t.Error("triage state did not move to the kept job")
+	}
+
+	if again, err := s.MergeDuplicates(); err != nil || len(again) != 0 {
+		t.Errorf("second pass = %v, %v", again, err)
+	}
+}
+
+func TestStore_DistinctURLsAreDistinctJobs(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{
+		{ID: "gh-1", Company: "Acme", Title: "Software Engineer (New York)", URL: "https://acme.com/jobs/1"},
+		{ID: "gh-3", Company: "Acme", Title: "Go Engineer", Location: "Berlin", URL: "https://acme.com/jobs/3"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, merged, err := s.MatchStored([]Job{
+		{ID: "gh-2", Company: "Acme", Title: "Software Engineer (Dublin)", URL: "https://acme.com/jobs/2"},
+		{ID: "gh-4", Company: "Acme", Title: "Go Engineer", Location: "Berlin", URL: "https://acme.com/jobs/4"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged != 0 || len(jobs) != 2 || jobs[0].ID != "gh-2" || jobs[1].ID != "gh-4" {
+		t.Fatalf("got %v, %d merged; want gh-2 and gh-4 kept as new jobs", jobs, merged)
+	}
+	if err := s.Save(jobs); err != nil {
+		t.Fatal(err)
+	}
+
+	merges, err := s.MergeDuplicates()
+	if err != nil || len(merges) != 0 {
+		t.Fatalf("merges = %v, %v; want none", merges, err)
+	}
+	if first, err := s.ByID("gh-1"); err != nil || first.URL != "https://acme.com/jobs/1" {
+		t.Errorf("gh-1 = %+v, %v; want it untouched", first, err)
+	}
+}
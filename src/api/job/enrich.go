@@ -0,0 +1,117 @@
+package job
+
+import (
+	"database/sql"
+	"regexp"
+	"strings"
+	"time"
+
+	"sprayer/src/api/parse"
+)
+
+// EnrichVersion is the revision of the enrichment Enrich runs. A job
+// enriched at an older one is enriched again, so a new step reaches jobs
+// already stored.
+const EnrichVersion = 1
+
+// remoteOnly matches locations that say only that the job is remote.
+var remoteOnly = regexp.MustCompile(`(?i)^(?:100%\s*|fully\s+|full\s+)?(?:remote|anywhere|worldwide|work\s+from\s+home|wfh)(?:\s*[(\-–]?\s*(?:worldwide|anywhere|global)\)?)?$`)
+
+// Enrich fills in what sources leave to the description, the same for
+// every source, after fetching and before scoring: it flags the traps
+// find reports, reads the application addresses, normalizes the location
+// and dates a job with no posted date to when it was first seen, now.
+// Jobs already enriched at EnrichVersion are left as they are, so running
+// it twice changes nothing.
+func Enrich(find func(string) []TrapMatch) Filter {
+	steps := Pipe(FlagTrapsWith(find), ExtractRecipients())
+	return func(jobs []Job) []Job {
+		now := time.Now()
+		return Map(jobs, func(j Job) Job {
+			if j.Enriched >= EnrichVersion {
+				return j
+			}
+			j = steps([]Job{j})[0]
+			if j.Email == "" {
+				j.Email = parse.ExtractFirstEmail(j.Description)
+			}
+			j.Location = NormalizeLocation(j)
+			if j.PostedDate.IsZero() {
+				j.PostedDate = now
+			}
+			j.Enriched = EnrichVersion
+			return j
+		})
+	}
+}
+
+// NormalizeLocation is the job's location with its spacing tidied and a
+// remote-only one, such as "anywhere" or "100% remote", written
+// "Remote". A job without one takes the first its title or description
+// names.
+func NormalizeLocation(j Job) string {
+	loc := strings.Trim(strings.Join(strings.Fields(j.Location), " "), " ,;|/-·")
+	if loc == "" {
+		for _, text := range []string{j.Title, j.Description} {
+			if locs := parse.ExtractLocations(text); len(locs) > 0 {
+				loc = locs[0]
+				break
+			}
+		}
+	}
+	switch {
+	case remoteOnly.MatchString(loc):
+		return "Remote"
+	case strings.EqualFold(loc, "hybrid"):
+		return "Hybrid"
+	case strings.EqualFold(loc, "onsite"), strings.EqualFold(loc, "on-site"):
+		return "On-site"
+	}
+	return loc
+}
+
+// EnrichStored runs enrich over the stored jobs not yet enriched at
+// EnrichVersion, or with force over every one, and saves them. It reads
+// whole descriptions, not the capped ones, and dates a job with no posted
+// date to when it was first stored. It returns how many jobs it enriched.
+func (s *Store) EnrichStored(enrich Filter, force bool) (int, error) {
+	query := `SELECT ` + prefixed("j") + `, COALESCE(o.full_text, j.description), j.created_at
+		FROM jobs j LEFT JOIN job_description_overflow o ON o.job_id = j.id`
+	var args []any
+	if !force {
+		query += ` WHERE j.enriched < ?`
+		args = append(args, EnrichVersion)
+	}
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	var jobs []Job
+	for rows.Next() {
+		var (
+			j       Job
+			full    string
+			created sql.NullTime
+		)
+		if err := scanJob(rows, &j, &full, &created); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		j.Description = full
+		if j.PostedDate.IsZero() && created.Valid {
+			j.PostedDate = created.Time
+		}
+		if force {
+			j.Enriched = 0
+		}
+		jobs = append(jobs, j)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(jobs) == 0 {
+		return 0, nil
+	}
+	return len(jobs), s.Save(enrich(jobs))
+}
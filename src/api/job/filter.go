@@ -1,8 +1,11 @@
 package job
 
 import (
+	"slices"
 	"strings"
 	"time"
+
+	"sprayer/src/api/parse"
 )
 
 // Filter transforms a job list. Chainable via Pipe().
@@ -189,6 +192,66 @@ func ExcludeCompanies(companies []string) Filter {
 	}
 }
 
+// BySource returns jobs from source, ignoring case.
+func BySource(source string) Filter {
+	return func(jobs []Job) []Job {
+		if source == "" {
+			return jobs
+		}
+		var out []Job
+		for _, j := range jobs {
+			if strings.EqualFold(j.Source, strings.TrimSpace(source)) {
+				out = append(out, j)
+			}
+		}
+		return out
+	}
+}
+
+// BySalaryAtLeast returns jobs whose structured salary can reach min.
+// Jobs without a structured salary are dropped.
+func BySalaryAtLeast(min int) Filter {
+	return func(jobs []Job) []Job {
+		var out []Job
+		for _, j := range jobs {
+			if top := max(j.SalaryMin, j.SalaryMax); top > 0 && top >= min {
+				out = append(out, j)
+			}
+		}
+		return out
+	}
+}
+
+// BySalaryBelow returns jobs whose structured salary stays under limit.
+// Jobs without a structured salary are dropped.
+func BySalaryBelow(limit int) Filter {
+	return func(jobs []Job) []Job {
+		var out []Job
+		for _, j := range jobs {
+			if top := max(j.SalaryMin, j.SalaryMax); top > 0 && top < limit {
+				out = append(out, j)
+			}
+		}
+		return out
+	}
+}
+
+// HasTag returns jobs carrying tag, ignoring case.
+func HasTag(tag string) Filter {
+	return func(jobs []Job) []Job {
+		var out []Job
+		for _, j := range jobs {
+			for _, t := range j.Tags {
+				if strings.EqualFold(t, tag) {
+					out = append(out, j)
+					break
+				}
+			}
+		}
+		return out
+	}
+}
+
 // HasEmail returns jobs that have an email field set.
 func HasEmail() Filter {
 	return func(jobs []Job) []Job {
@@ -202,12 +265,13 @@ func HasEmail() Filter {
 	}
 }
 
-// ExcludeTraps filters out jobs that have traps
-func ExcludeTraps() Filter {
+// ExcludeTraps filters out jobs that tripped a trap of severity min or
+// worse; TrapLow, or "", excludes every flagged job.
+func ExcludeTraps(min TrapSeverity) Filter {
 	return func(jobs []Job) []Job {
 		var out []Job
 		for _, j := range jobs {
-			if !j.HasTraps {
+			if !j.TrapsAtLeast(min) {
 				out = append(out, j)
 			}
 		}
@@ -215,6 +279,36 @@ func ExcludeTraps() Filter {
 	}
 }
 
+// ByStatus keeps jobs at one of statuses; StatusNone matches untracked
+// jobs.
+func ByStatus(statuses ...Status) Filter {
+	return func(jobs []Job) []Job {
+		return Select(jobs, func(j Job) bool { return slices.Contains(statuses, j.Status) })
+	}
+}
+
+// ExcludeStatuses drops jobs at any of statuses, e.g. rejected ones.
+func ExcludeStatuses(statuses ...Status) Filter {
+	return func(jobs []Job) []Job {
+		return Select(jobs, func(j Job) bool { return !slices.Contains(statuses, j.Status) })
+	}
+}
+
+// ExcludeApplied drops jobs already applied to.
+func ExcludeApplied() Filter {
+	return func(jobs []Job) []Job {
+		return Select(jobs, func(j Job) bool { return !j.Applied })
+	}
+}
+
+// ExcludeArchived drops jobs archived in the profile, for lists loaded
+// with IncludeArchived that show them only on request.
+func ExcludeArchived() Filter {
+	return func(jobs []Job) []Job {
+		return Select(jobs, func(j Job) bool { return !j.Archived })
+	}
+}
+
 // RemotePreferred prioritizes remote jobs
 func RemotePreferred() Filter {
 	return func(jobs []Job) []Job {
@@ -234,20 +328,20 @@ func RemotePreferred() Filter {
 	}
 }
 
-// BySeniorityLevels returns jobs matching specified seniority levels
-func BySeniorityLevels(levels []string) Filter {
+// BySeniorityLevels returns jobs whose Level is one of levels. Jobs of
+// unknown level are kept only with includeUnknown.
+func BySeniorityLevels(levels []string, includeUnknown bool) Filter {
 	return func(jobs []Job) []Job {
 		if len(levels) == 0 {
 			return jobs
 		}
 		var out []Job
 		for _, j := range jobs {
-			titleLower := strings.ToLower(j.Title)
-			for _, level := range levels {
-				if strings.Contains(titleLower, strings.ToLower(level)) {
-					out = append(out, j)
-					break
-				}
+			level := j.Level()
+			if level == parse.SeniorityUnknown && includeUnknown || slices.ContainsFunc(levels, func(l string) bool {
+				return strings.EqualFold(l, level)
+			}) {
+				out = append(out, j)
 			}
 		}
 		return out
@@ -0,0 +1,85 @@
+package job
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DefaultFollowUp is how long after applying a follow-up falls due when
+// the profile does not say.
+const DefaultFollowUp = 7 * 24 * time.Hour
+
+// FollowUpDue reports whether j is waiting on a follow-up at now.
+func (j Job) FollowUpDue(now time.Time) bool {
+	return j.Applied && !j.FollowUpDone && j.FollowUpAt != nil && !j.FollowUpAt.After(now)
+}
+
+// FollowUpsDue returns the jobs from jobs waiting on a follow-up at now,
+// the longest overdue first.
+func FollowUpsDue(jobs []Job, now time.Time) []Job {
+	var due []Job
+	for _, j := range jobs {
+		if j.FollowUpDue(now) {
+			due = append(due, j)
+		}
+	}
+	return SortBy(func(a, b Job) bool { return a.FollowUpAt.Before(*b.FollowUpAt) })(due)
+}
+
+// MarkApplied records applying to a job at the given time and schedules
+// its follow-up after. A job already applied to keeps its date and
+// follow-up. An unknown job returns sql.ErrNoRows.
+func (s *Store) MarkApplied(jobID string, at time.Time, after time.Duration) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var one int
+	if err := tx.QueryRow(`SELECT 1 FROM jobs WHERE id = ?`, jobID).Scan(&one); err != nil {
+		return err
+	}
+	if err := markApplied(tx, jobID, at, after); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func markApplied(tx *sql.Tx, jobID string, at time.Time, after time.Duration) error {
+	_, err := tx.Exec(`UPDATE jobs SET applied = 1, applied_date = ?, follow_up_at = ?, follow_up_done = 0
+		WHERE id = ? AND NOT applied`, at.UTC(), at.Add(after).UTC(), jobID)
+	return err
+}
+
+// FollowUps returns the applied jobs with a follow-up still to send,
+// soonest first; with dueOnly, just those due at now.
+func (s *Store) FollowUps(now time.Time, dueOnly bool) ([]Job, error) {
+	q := `SELECT ` + jobColumns + ` FROM jobs
+		WHERE applied AND NOT follow_up_done AND follow_up_at IS NOT NULL`
+	args := []any{}
+	if dueOnly {
+		q += ` AND follow_up_at <= ?`
+		args = append(args, now.UTC())
+	}
+	rows, err := s.DB.Query(q+` ORDER BY follow_up_at, short_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanJobs(rows)
+}
+
+// MarkFollowUpDone records that a job's follow-up was sent, so it is no
+// longer due. An unknown job returns sql.ErrNoRows.
+func (s *Store) MarkFollowUpDone(jobID string) error {
+	res, err := s.DB.Exec(`UPDATE jobs SET follow_up_done = 1 WHERE id = ?`, jobID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package job
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStore_FollowUps(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{{ID: "a", Title: "Go Engineer"}, {ID: "b", Title: "SRE"}, {ID: "c", Title: "Rust Engineer"}}); err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	if err := s.MarkApplied("a", day, 3*24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MarkApplied("b", day.AddDate(0, 0, -5), 3*24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetStatus("c", StatusApplied, "", day); err != nil {
+		t.Fatal(err)
+	}
+	// Applying again does not push the follow-up back.
+	if err := s.MarkApplied("a", day.AddDate(0, 0, 2), 3*24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	// Nor does a re-scrape clear it.
+	if err := s.Save([]Job{{ID: "a", Title: "Go Engineer"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	due, err := s.FollowUps(day.AddDate(0, 0, 3), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(due) != 2 || due[0].ID != "b" || due[1].ID != "a" || !due[1].AppliedDate.Equal(day) {
+		t.Fatalf("due = %+v", due)
+	}
+	if all, _ := s.FollowUps(day.AddDate(0, 0, 3), false); len(all) != 3 || !all[2].FollowUpAt.Equal(day.Add(DefaultFollowUp)) {
+		t.Errorf("all = %+v", all)
+	}
+	if got := FollowUpsDue(append(due, Job{ID: "x"}), day.AddDate(0, 0, 3)); len(got) != 2 || got[0].ID != "b" {
+		t.Errorf("FollowUpsDue = %+v", got)
+	}
+
+	if err := s.MarkFollowUpDone("b"); err != nil {
+		t.Fatal(err)
+	}
+	if due, _ := s.FollowUps(day.AddDate(0, 0, 3), true); len(due) != 1 || due[0].ID != "a" {
+		t.Errorf("after done = %+v", due)
+	}
+	if err := s.MarkFollowUpDone("missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown job: %v", err)
+	}
+	if err := s.MarkApplied("missing", day, DefaultFollowUp); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown job: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package job
+
+import (
+	"slices"
+	"strings"
+
+	"sprayer/src/api/parse"
+)
+
+// DetectLanguages records the language of each job's posting. Jobs that
+// already have one are left alone.
+func DetectLanguages() Filter {
+	return func(jobs []Job) []Job {
+		return Map(jobs, func(j Job) Job {
+			if j.Language == "" {
+				j.Language = parse.DetectLanguage(j.Title + "\n" + j.Description)
+			}
+			return j
+		})
+	}
+}
+
+// ByLanguages keeps jobs written in one of langs, ISO 639-1 codes, and
+// those whose language could not be told. Jobs saved before detection
+// ran are detected now.
+func ByLanguages(langs []string) Filter {
+	return func(jobs []Job) []Job {
+		if len(langs) == 0 {
+			return jobs
+		}
+		return Select(jobs, func(j Job) bool {
+			lang := j.Language
+			if lang == "" {
+				lang = parse.DetectLanguage(j.Title + "\n" + j.Description)
+			}
+			return lang == "" || slices.ContainsFunc(langs, func(l string) bool {
+				return strings.EqualFold(strings.TrimSpace(l), lang)
+			})
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package job
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestByLanguages(t *testing.T) {
+	jobs := DetectLanguages()([]Job{
+		{ID: "en", Title: "Backend Engineer", Description: "We are looking for an engineer to join our team. You will work with Go and have experience with databases."},
+		{ID: "de", Title: "Backend-Entwickler", Description: "Wir suchen für unser Team eine Entwicklerin oder einen Entwickler. Du hast Erfahrung mit Go und bist bereit, Verantwortung zu übernehmen."},
+		{ID: "short", Title: "Go dev"},
+		{ID: "stored", Title: "Backend Engineer", Language: "fr"},
+	})
+	if jobs[0].Language != "en" || jobs[1].Language != "de" || jobs[2].Language != "" || jobs[3].Language != "fr" {
+		t.Fatalf("detected %q %q %q %q", jobs[0].Language, jobs[1].Language, jobs[2].Language, jobs[3].Language)
+	}
+	var ids []string
+	for _, j := range ByLanguages([]string{"EN", " fr"})(jobs) {
+		ids = append(ids, j.ID)
+	}
+	if want := []string{"en", "short", "stored"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ByLanguages kept %v, want %v", ids, want)
+	}
+	if got := ByLanguages(nil)(jobs); len(got) != len(jobs) {
+		t.Errorf("no languages kept %d of %d jobs", len(got), len(jobs))
+	}
+
+	s := openTestStore(t)
+	if err := s.Save(jobs[1:2]); err != nil {
+		t.Fatal(err)
+	}
+	if j, err := s.ByID("de"); err != nil || j.Language != "de" {
+		t.Errorf("stored language = %q, %v", j.Language, err)
+	}
+}
@@ -4,7 +4,10 @@ import "time"
 
 // Job represents a scraped job posting.
 type Job struct {
-	ID          string    `json:"id"`
+	ID string `json:"id"`
+	// ShortID is the job's number in this workspace, shown as #1234. It
+	// is assigned on first save and never reused.
+	ShortID     int64     `json:"short_id,omitempty"`
 	Title       string    `json:"title"`
 	Company     string    `json:"company"`
 	Location    string    `json:"location"`
@@ -13,11 +16,66 @@ type Job struct {
 	Source      string    `json:"source"`
 	PostedDate  time.Time `json:"posted_date"`
 	Salary      string    `json:"salary,omitempty"`
-	JobType     string    `json:"job_type,omitempty"`
-	Email       string    `json:"email,omitempty"`
-	Score       int       `json:"score"`
-	HasTraps    bool      `json:"has_traps"`
-	Traps       []string  `json:"traps,omitempty"`
-	Applied     bool      `json:"applied"`
-	AppliedDate time.Time `json:"applied_date,omitempty"`
+	// Structured compensation, when the source provides it. Salary keeps
+	// the display string.
+	SalaryMin      int         `json:"salary_min,omitempty"`
+	SalaryMax      int         `json:"salary_max,omitempty"`
+	SalaryCurrency string      `json:"salary_currency,omitempty"`
+	JobType        string      `json:"job_type,omitempty"`
+	Email          string      `json:"email,omitempty"`
+	Cc             []string    `json:"cc,omitempty"` // further addresses the posting asks to copy
+	Score          int         `json:"score"`
+	HasTraps       bool        `json:"has_traps"`
+	Traps          []TrapMatch `json:"traps,omitempty"`
+	Applied        bool        `json:"applied"`
+	AppliedDate    time.Time   `json:"applied_date,omitempty"`
+	Status         Status      `json:"status,omitempty"`     // set with Store.SetStatus, which keeps the history
+	ExpiresAt      *time.Time  `json:"expires_at,omitempty"` // nil when the source gives no closing date
+	// FollowUpAt is when to chase an application, set on applying;
+	// FollowUpDone marks the follow-up sent.
+	FollowUpAt   *time.Time `json:"follow_up_at,omitempty"`
+	FollowUpDone bool       `json:"follow_up_done,omitempty"`
+	// CVPath is the tailored CV PDF built for this job, if any.
+	CVPath string `json:"cv_path,omitempty"`
+	// Seniority is the level parse.DetectSeniority read from the title and
+	// description; empty until detected. See Level.
+	Seniority string `json:"seniority,omitempty"`
+	// Language is the ISO 639-1 code of the language the posting is
+	// written in, or "" when it is too short to tell.
+	Language string `json:"language,omitempty"`
+	// Enriched is the EnrichVersion the job was last enriched at; 0 for a
+	// job saved before enrichment ran.
+	Enriched int `json:"enriched,omitempty"`
+	// Deadline is an application deadline read from the description, with
+	// the sentence it came from. Extraction can misfire, so DeadlineText is
+	// shown alongside it.
+	Deadline     *time.Time `json:"deadline,omitempty"`
+	DeadlineText string     `json:"deadline_text,omitempty"`
+	// Truncated marks a Description cut to DescriptionLimit. The whole
+	// text is in FullDescription only between capping and saving; after
+	// that Store.FullDescription reads it.
+	Truncated       bool   `json:"truncated,omitempty"`
+	FullDescription string `json:"-"`
+	// Tags, Note and ScoreAdjust are set by ingest rules. ScoreAdjust is
+	// already in Score and is kept so rescoring can apply it again.
+	Tags        []string `json:"tags,omitempty"`
+	Note        string   `json:"note,omitempty"`
+	ScoreAdjust int      `json:"score_adjust,omitempty"`
+
+	// Per-profile triage state. Populated by Store.ForProfile from the
+	// job_profile_state table; zero when the job is loaded without a profile.
+	Hidden   bool    `json:"hidden"`
+	Archived bool    `json:"archived"`
+	Starred  bool    `json:"starred"`
+	Verdict  Verdict `json:"verdict,omitempty"`
 }
+
+// Verdict is the triage decision a profile recorded for a job.
+type Verdict string
+
+const (
+	VerdictNone  Verdict = ""
+	VerdictYes   Verdict = "yes"
+	VerdictMaybe Verdict = "maybe"
+	VerdictNo    Verdict = "no"
+)
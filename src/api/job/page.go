@@ -0,0 +1,136 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// sortColumns maps the sort fields a page can ask for to SQL on jobs
+// aliased j. Text sorts ignore case; score is the profile's.
+var sortColumns = map[string]string{
+	"score":       profileScore,
+	"posted_date": "j.posted_date",
+	"company":     "j.company COLLATE NOCASE",
+	"title":       "j.title COLLATE NOCASE",
+}
+
+// SortFields lists the fields a Page may sort by.
+var SortFields = []string{"score", "posted_date", "company", "title"}
+
+// Page is one window of a profile's jobs. A zero Limit means no limit;
+// an empty Sort is score, highest first.
+type Page struct {
+	Limit  int
+	Offset int
+	Sort   string
+	Desc   bool
+}
+
+// ParsePage checks the sort field and order names a client sent. order
+// is "asc" or "desc"; empty means desc for score and posted_date, which
+// read best newest and highest first, and asc for the rest.
+func ParsePage(limit, offset int, sort, order string) (Page, error) {
+	p := Page{Limit: limit, Offset: offset, Sort: strings.ToLower(strings.TrimSpace(sort))}
+	if p.Sort == "" {
+		p.Sort = "score"
+	}
+	if _, ok := sortColumns[p.Sort]; !ok {
+		return Page{}, fmt.Errorf("unknown sort field %q (want %s)", sort, strings.Join(SortFields, ", "))
+	}
+	switch strings.ToLower(strings.TrimSpace(order)) {
+	case "":
+		p.Desc = p.Sort == "score" || p.Sort == "posted_date"
+	case "asc":
+	case "desc":
+		p.Desc = true
+	default:
+		return Page{}, fmt.Errorf("unknown order %q (want asc or desc)", order)
+	}
+	if limit < 0 || offset < 0 {
+		return Page{}, fmt.Errorf("limit and offset cannot be negative")
+	}
+	return p, nil
+}
+
+func (p Page) orderBy() string {
+	col, ok := sortColumns[p.Sort]
+	if !ok {
+		col = sortColumns["score"]
+	}
+	dir := "ASC"
+	if p.Desc {
+		dir = "DESC"
+	}
+	// The ID breaks ties so pages never overlap or skip a job.
+	return "ORDER BY " + col + " " + dir + ", j.id " + dir
+}
+
+// ForProfilePage is ForProfile one page at a time, sorted and windowed in
+// SQL. total counts every job in scope, not just the page.
+func (s *Store) ForProfilePage(profileID string, p Page, opts ...ActiveOption) (jobs []Job, total int, err error) {
+	return s.ForProfilePageContext(context.Background(), profileID, p, opts...)
+}
+
+// ForProfilePageContext is ForProfilePage, abandoning the queries if ctx
+// is cancelled.
+func (s *Store) ForProfilePageContext(ctx context.Context, profileID string, p Page, opts ...ActiveOption) (jobs []Job, total int, err error) {
+	return s.NarrowedPageContext(ctx, profileID, p, Narrowing{}, opts...)
+}
+
+// Narrowing is what a page's jobs must also match, checked in SQL so a
+// narrowed page is still read one window at a time. The zero value
+// narrows nothing.
+type Narrowing struct {
+	MinScore    int        // lowest profile score kept
+	MaxScore    int        // highest kept; 0 leaves it open
+	PostedAfter *time.Time // keep jobs posted after this
+	HasEmail    bool       // keep jobs with an email address
+}
+
+// where returns the SQL condition on jobs aliased j joined with their
+// profile state aliased st, or "" for none, and the arguments it binds.
+func (n Narrowing) where() (string, []any) {
+	var conds []string
+	var args []any
+	if n.MinScore > 0 || n.MaxScore > 0 && n.MaxScore < 100 {
+		hi := n.MaxScore
+		if hi == 0 {
+			hi = 100
+		}
+		conds = append(conds, profileScore+" BETWEEN ? AND ?")
+		args = append(args, n.MinScore, hi)
+	}
+	if n.PostedAfter != nil {
+		// Dates are stored with their zones; julianday compares instants.
+		conds = append(conds, "julianday(j.posted_date) > julianday(?)")
+		args = append(args, n.PostedAfter.UTC())
+	}
+	if n.HasEmail {
+		conds = append(conds, "COALESCE(j.email, '') != ''")
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// NarrowedPage is ForProfilePage for the jobs matching n; total counts
+// only those.
+func (s *Store) NarrowedPage(profileID string, p Page, n Narrowing, opts ...ActiveOption) (jobs []Job, total int, err error) {
+	return s.NarrowedPageContext(context.Background(), profileID, p, n, opts...)
+}
+
+// NarrowedPageContext is NarrowedPage, abandoning the queries if ctx is
+// cancelled.
+func (s *Store) NarrowedPageContext(ctx context.Context, profileID string, p Page, n Narrowing, opts ...ActiveOption) (jobs []Job, total int, err error) {
+	cond, condArgs := n.where()
+	from, args := profileScope(profileID, cond, condArgs, opts)
+	if err := s.DB.QueryRowContext(ctx, `SELECT COUNT(*) `+from, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	limit := p.Limit
+	if limit <= 0 {
+		limit = -1 // SQLite's "no limit"
+	}
+	jobs, err = s.queryForProfile(ctx, prefixed("j"), profileID, cond, condArgs, p.orderBy()+" LIMIT ? OFFSET ?", opts, limit, p.Offset)
+	return jobs, total, err
+}
@@ -0,0 +1,128 @@
+package job
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func pageIDs(jobs []Job) []string {
+	ids := make([]string, len(jobs))
+	for i, j := range jobs {
+		ids[i] = j.ID
+	}
+	return ids
+}
+
+func TestForProfilePage(t *testing.T) {
+	s := openTestStore(t)
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	var jobs []Job
+	for i := range 7 {
+		jobs = append(jobs, Job{
+			ID:         fmt.Sprintf("j%d", i),
+			Title:      fmt.Sprintf("title %c", 'g'-i),
+			Company:    []string{"beta", "Alpha", "gamma"}[i%3],
+			Score:      []int{50, 90, 50, 10, 70, 30, 90}[i],
+			PostedDate: base.AddDate(0, 0, i),
+		})
+	}
+	if err := s.Save(jobs); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetHidden("j3", "default", true); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		page  Page
+		want  []string
+		total int
+	}{
+		{"score desc, ties by id", Page{Limit: 3, Sort: "score", Desc: true}, []string{"j6", "j1", "j4"}, 6},
+		{"second window", Page{Limit: 3, Offset: 3, Sort: "score", Desc: true}, []string{"j2", "j0", "j5"}, 6},
+		{"past the end", Page{Limit: 3, Offset: 6, Sort: "score", Desc: true}, []string{}, 6},
+		{"company ignores case", Page{Limit: 2, Sort: "company"}, []string{"j1", "j4"}, 6},
+		{"posted asc", Page{Limit: 2, Sort: "posted_date"}, []string{"j0", "j1"}, 6},
+		{"title desc", Page{Limit: 2, Sort: "title", Desc: true}, []string{"j0", "j1"}, 6},
+		{"no limit", Page{Sort: "posted_date", Desc: true}, []string{"j6", "j5", "j4", "j2", "j1", "j0"}, 6},
+	}
+	for _, tt := range tests {
+		got, total, err := s.ForProfilePage("default", tt.page)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if ids := pageIDs(got); !reflect.DeepEqual(ids, tt.want) || total != tt.total {
+			t.Errorf("%s: got %v of %d, want %v of %d", tt.name, ids, total, tt.want, tt.total)
+		}
+	}
+
+	_, total, err := s.ForProfilePage("default", Page{Limit: 1}, IncludeHidden())
+	if err != nil || total != 7 {
+		t.Errorf("total with hidden = %d, %v", total, err)
+	}
+}
+
+func TestNarrowedPage(t *testing.T) {
+	s := openTestStore(t)
+	berlin := time.FixedZone("CET", 3600)
+	after := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	if err := s.Save([]Job{
+		{ID: "a", Score: 80, Email: "jobs@a.test", PostedDate: after.Add(time.Hour)},
+		// 12:30 in Berlin is before noon UTC, though its text sorts after.
+		{ID: "b", Score: 80, Email: "jobs@b.test", PostedDate: time.Date(2025, 6, 10, 12, 30, 0, 0, berlin)},
+		{ID: "c", Score: 20, Email: "jobs@c.test", PostedDate: after.AddDate(0, 0, 1)},
+		{ID: "d", Score: 90, PostedDate: after.AddDate(0, 0, 2)},
+		{ID: "e", Score: 75, Email: "jobs@e.test"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// The profile's own score counts, not the one saved.
+	if err := s.SetScores("default", map[string]int{"c": 95}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		n     Narrowing
+		page  Page
+		want  []string
+		total int
+	}{
+		{"nothing", Narrowing{}, Page{Sort: "score", Desc: true}, []string{"c", "d", "b", "a", "e"}, 5},
+		{"score range", Narrowing{MinScore: 75, MaxScore: 90}, Page{Sort: "score", Desc: true}, []string{"d", "b", "a", "e"}, 4},
+		{"open maximum", Narrowing{MinScore: 85}, Page{Sort: "score", Desc: true}, []string{"c", "d"}, 2},
+		{"posted after", Narrowing{PostedAfter: &after}, Page{Sort: "posted_date"}, []string{"a", "c", "d"}, 3},
+		{"has email", Narrowing{HasEmail: true}, Page{Sort: "score", Desc: true}, []string{"c", "b", "a", "e"}, 4},
+		{"windowed", Narrowing{PostedAfter: &after, HasEmail: true}, Page{Limit: 1, Offset: 1, Sort: "posted_date"}, []string{"c"}, 2},
+	}
+	for _, tt := range tests {
+		got, total, err := s.NarrowedPage("default", tt.page, tt.n)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if ids := pageIDs(got); !reflect.DeepEqual(ids, tt.want) || total != tt.total {
+			t.Errorf("%s: got %v of %d, want %v of %d", tt.name, ids, total, tt.want, tt.total)
+		}
+	}
+}
+
+func TestParsePage(t *testing.T) {
+	p, err := ParsePage(50, 100, "", "")
+	if err != nil || p != (Page{Limit: 50, Offset: 100, Sort: "score", Desc: true}) {
+		t.Errorf("defaults = %+v, %v", p, err)
+	}
+	if p, _ := ParsePage(10, 0, "Company", ""); p.Desc {
+		t.Error("company should default to ascending")
+	}
+	if p, _ := ParsePage(10, 0, "score", "ASC"); p.Desc {
+		t.Error("order=asc ignored")
+	}
+	for _, bad := range [][2]string{{"salary", ""}, {"score", "up"}} {
+		if _, err := ParsePage(10, 0, bad[0], bad[1]); err == nil {
+			t.Errorf("ParsePage(sort=%q, order=%q) should fail", bad[0], bad[1])
+		}
+	}
+}
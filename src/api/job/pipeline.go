@@ -2,6 +2,8 @@ package job
 
 import (
 	"sort"
+	"strings"
+
 	"sprayer/src/api/parse"
 )
 
@@ -23,11 +25,13 @@ func Select(jobs []Job, pred func(Job) bool) []Job {
 	return out
 }
 
+// SortBy orders jobs by less, keeping jobs it ranks equal in the order
+// they came.
 func SortBy(less func(a, b Job) bool) Filter {
 	return func(jobs []Job) []Job {
 		sorted := make([]Job, len(jobs))
 		copy(sorted, jobs)
-		sort.Slice(sorted, func(i, j int) bool {
+		sort.SliceStable(sorted, func(i, j int) bool {
 			return less(sorted[i], sorted[j])
 		})
 		return sorted
@@ -35,15 +39,29 @@ func SortBy(less func(a, b Job) bool) Filter {
 }
 
 var (
-	ByScoreDesc = func(a, b Job) bool { return a.Score > b.Score }
-	ByDateDesc  = func(a, b Job) bool { return a.PostedDate.After(b.PostedDate) }
-	ByTitleAsc  = func(a, b Job) bool { return a.Title < b.Title }
+	ByScoreDesc  = func(a, b Job) bool { return a.Score > b.Score }
+	ByDateDesc   = func(a, b Job) bool { return a.PostedDate.After(b.PostedDate) }
+	ByTitleAsc   = func(a, b Job) bool { return strings.ToLower(a.Title) < strings.ToLower(b.Title) }
+	ByCompanyAsc = func(a, b Job) bool { return strings.ToLower(a.Company) < strings.ToLower(b.Company) }
 )
 
+// FlagTraps flags prompt injection with the built-in grammar alone.
 func FlagTraps() Filter {
+	return FlagTrapsWith(func(text string) []TrapMatch {
+		var out []TrapMatch
+		for _, m := range parse.CheckForTraps(text) {
+			out = append(out, TrapMatch{Name: "prompt-injection", Severity: TrapHigh, Match: m})
+		}
+		return out
+	})
+}
+
+// FlagTrapsWith flags jobs whose description find returns matches for,
+// such as a loaded trap rule set.
+func FlagTrapsWith(find func(string) []TrapMatch) Filter {
 	return func(jobs []Job) []Job {
 		return Map(jobs, func(j Job) Job {
-			traps := parse.CheckForTraps(j.Description)
+			traps := find(j.Description)
 			if len(traps) > 0 {
 				j.HasTraps = true
 				j.Traps = traps
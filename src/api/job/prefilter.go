@@ -0,0 +1,35 @@
+package job
+
+import (
+	"context"
+	"strings"
+)
+
+// ForProfileContaining is ForProfile narrowed in SQL to jobs whose title
+// or description contains at least one of terms, case-insensitively. It
+// is a cheap first pass before an exact in-memory scorer; with no terms
+// it returns no jobs.
+//
+// The match is a LIKE scan. There is no full-text index over jobs yet; a
+// job index would replace it here without changing callers.
+func (s *Store) ForProfileContaining(profileID string, terms []string, opts ...ActiveOption) ([]Job, error) {
+	cond, args := containingAny(terms)
+	return s.forProfile(context.Background(), profileID, cond, args, opts)
+}
+
+// containingAny returns a SQL condition on jobs aliased j. LIKE ignores
+// ASCII case only, the reason CandidateTerms gives up on other text.
+func containingAny(terms []string) (string, []any) {
+	if len(terms) == 0 {
+		return "0 = 1", nil
+	}
+	conds := make([]string, len(terms))
+	args := make([]any, len(terms))
+	for i, t := range terms {
+		conds[i] = `(COALESCE(j.title, '') || ' ' || COALESCE(j.description, '')) LIKE ? ESCAPE '\'`
+		args[i] = "%" + likeEscaper.Replace(t) + "%"
+	}
+	return strings.Join(conds, " OR "), args
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
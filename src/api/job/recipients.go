@@ -0,0 +1,50 @@
+package job
+
+import (
+	"strings"
+
+	"sprayer/src/api/parse"
+)
+
+// ExtractRecipients reads the addresses a description asks applications
+// to go to. A job without an email takes the primary one; the others
+// become Cc. Jobs that already have Cc addresses are left alone.
+func ExtractRecipients() Filter {
+	return func(jobs []Job) []Job {
+		return Map(jobs, func(j Job) Job {
+			if len(j.Cc) > 0 {
+				return j
+			}
+			for _, r := range parse.ExtractRecipients(j.Description) {
+				switch {
+				case j.Email == "":
+					j.Email = r.Address
+				case !strings.EqualFold(r.Address, j.Email):
+					j.Cc = append(j.Cc, r.Address)
+				}
+			}
+			return j
+		})
+	}
+}
+
+// Recipients returns the job's address and Cc list without those in
+// exclude, compared case-insensitively. Excluding the primary address
+// promotes the first remaining Cc to To.
+func (j Job) Recipients(exclude ...string) (to string, cc []string) {
+	skip := make(map[string]bool, len(exclude))
+	for _, e := range exclude {
+		skip[strings.ToLower(strings.TrimSpace(e))] = true
+	}
+	for _, addr := range append([]string{j.Email}, j.Cc...) {
+		if addr == "" || skip[strings.ToLower(addr)] {
+			continue
+		}
+		if to == "" {
+			to = addr
+			continue
+		}
+		cc = append(cc, addr)
+	}
+	return to, cc
+}
@@ -0,0 +1,54 @@
+package job
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractRecipients_FillsEmailAndCc(t *testing.T) {
+	jobs := ExtractRecipients()([]Job{
+		{ID: "a", Description: "Send your CV to jobs@acme.com and cc maria@acme.com. Do not write to noreply@acme.com."},
+		{ID: "b", Email: "lead@acme.com", Description: "Apply to jobs@acme.com, copying LEAD@acme.com"},
+		{ID: "c", Description: "Apply through the portal."},
+	})
+	want := []struct {
+		email string
+		cc    []string
+	}{
+		{"jobs@acme.com", []string{"maria@acme.com"}},
+		{"lead@acme.com", []string{"jobs@acme.com"}},
+		{"", nil},
+	}
+	for i, w := range want {
+		if jobs[i].Email != w.email || !reflect.DeepEqual(jobs[i].Cc, w.cc) {
+			t.Errorf("%s: email %q cc %q, want %q %q", jobs[i].ID, jobs[i].Email, jobs[i].Cc, w.email, w.cc)
+		}
+	}
+}
+
+func TestRecipients_Exclude(t *testing.T) {
+	j := Job{Email: "jobs@acme.com", Cc: []string{"maria@acme.com", "tom@acme.com"}}
+	if to, cc := j.Recipients(); to != "jobs@acme.com" || !reflect.DeepEqual(cc, j.Cc) {
+		t.Errorf("no exclusions: %q %q", to, cc)
+	}
+	if to, cc := j.Recipients(" Maria@acme.com"); to != "jobs@acme.com" || !reflect.DeepEqual(cc, []string{"tom@acme.com"}) {
+		t.Errorf("excluding a cc: %q %q", to, cc)
+	}
+	if to, cc := j.Recipients("jobs@acme.com"); to != "maria@acme.com" || !reflect.DeepEqual(cc, []string{"tom@acme.com"}) {
+		t.Errorf("excluding the primary should promote the first cc: %q %q", to, cc)
+	}
+}
+
+func TestStore_SavesCc(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{{ID: "a", Email: "jobs@acme.com", Cc: []string{"maria@acme.com", "tom@acme.com"}}}); err != nil {
+		t.Fatal(err)
+	}
+	j, err := s.ByID("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(j.Cc, []string{"maria@acme.com", "tom@acme.com"}) {
+		t.Errorf("Cc = %q", j.Cc)
+	}
+}
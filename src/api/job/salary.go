@@ -0,0 +1,53 @@
+package job
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// salaryAmount is a figure in a salary text: digits with thousands
+// separators, and k for thousands.
+var salaryAmount = regexp.MustCompile(`(?i)(\d{1,3}(?:[,.\s]\d{3})+|\d+)(?:\.\d+)?\s*(k)?\b`)
+
+// SalaryBounds is the job's pay range: the structured one when the
+// source gave it, else the one read from the Salary text, as in
+// "$120k - $150k" or "€90,000". ok is false when neither tells; figures
+// under a thousand, such as hourly rates, are not read.
+func (j Job) SalaryBounds() (lo, hi int, ok bool) {
+	if j.SalaryMin > 0 || j.SalaryMax > 0 {
+		lo, hi = j.SalaryMin, j.SalaryMax
+		if lo == 0 {
+			lo = hi
+		}
+		if hi == 0 {
+			hi = lo
+		}
+		return lo, hi, true
+	}
+	var amounts []int
+	thousands := false
+	for _, m := range salaryAmount.FindAllStringSubmatch(j.Salary, 2) {
+		n, err := strconv.Atoi(strings.NewReplacer(",", "", ".", "", " ", "").Replace(m[1]))
+		if err != nil {
+			continue
+		}
+		amounts, thousands = append(amounts, n), m[2] != ""
+		if thousands {
+			amounts[len(amounts)-1] *= 1000
+		}
+	}
+	// In "120-150k" the k is the range's.
+	if len(amounts) == 2 && thousands && amounts[0] < 1000 {
+		amounts[0] *= 1000
+	}
+	amounts = slices.DeleteFunc(amounts, func(n int) bool { return n < 1000 })
+	switch len(amounts) {
+	case 0:
+		return 0, 0, false
+	case 1:
+		return amounts[0], amounts[0], true
+	}
+	return min(amounts[0], amounts[1]), max(amounts[0], amounts[1]), true
+}
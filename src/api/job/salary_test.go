@@ -0,0 +1,26 @@
+package job
+
+import "testing"
+
+func TestSalaryBounds(t *testing.T) {
+	tests := []struct {
+		j      Job
+		lo, hi int
+		ok     bool
+	}{
+		{Job{SalaryMin: 90000, SalaryMax: 120000, Salary: "$1"}, 90000, 120000, true},
+		{Job{SalaryMax: 80000}, 80000, 80000, true},
+		{Job{Salary: "$120k - $150k"}, 120000, 150000, true},
+		{Job{Salary: "120-150K USD"}, 120000, 150000, true},
+		{Job{Salary: "€90,000 – 110.000 per year"}, 90000, 110000, true},
+		{Job{Salary: "£65 000"}, 65000, 65000, true},
+		{Job{Salary: "$60/hr"}, 0, 0, false},
+		{Job{Salary: "competitive"}, 0, 0, false},
+	}
+	for _, tt := range tests {
+		lo, hi, ok := tt.j.SalaryBounds()
+		if lo != tt.lo || hi != tt.hi || ok != tt.ok {
+			t.Errorf("%+v: got %d, %d, %v", tt.j, lo, hi, ok)
+		}
+	}
+}
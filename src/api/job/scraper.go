@@ -1,5 +1,23 @@
 package job
 
+import (
+	"os"
+	"strconv"
+)
+
+// EnvScrapeParallelism caps how many sources Merge scrapes at once; unset
+// or 0 scrapes them all at once.
+const EnvScrapeParallelism = "SPRAYER_SCRAPE_PARALLELISM"
+
+// ScrapeParallelism is the cap EnvScrapeParallelism sets, 0 for none.
+func ScrapeParallelism() int {
+	n, err := strconv.Atoi(os.Getenv(EnvScrapeParallelism))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
 // Scraper fetches jobs from a source. Composable: combine with Merge().
 type Scraper func() ([]Job, error)
 
@@ -11,9 +29,17 @@ func Merge(scrapers ...Scraper) Scraper {
 			err  error
 		}
 		ch := make(chan result, len(scrapers))
+		var sem chan struct{}
+		if n := ScrapeParallelism(); n > 0 {
+			sem = make(chan struct{}, n)
+		}
 		
 		for _, s := range scrapers {
 			go func(s Scraper) {
+				if sem != nil {
+					sem <- struct{}{}
+					defer func() { <-sem }()
+				}
 				jobs, err := s()
 				ch <- result{jobs, err}
 			}(s)
@@ -25,8 +51,7 @@ func Merge(scrapers ...Scraper) Scraper {
 			res := <-ch
 			if res.err != nil {
 				lastErr = res.err
-				// Log error but continue
-				continue
+				// Log error but keep any partial results
 			}
 			all = append(all, res.jobs...)
 		}
@@ -0,0 +1,131 @@
+package job
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// migrateSeen creates the record of postings already scraped, so a scrape
+// can skip them. It drops the per-source high-water marks earlier versions
+// kept: only saved postings are recorded, so a mark would skip older
+// postings another profile may still want.
+func migrateSeen(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS seen_urls (
+			url     TEXT PRIMARY KEY,
+			source  TEXT NOT NULL DEFAULT '',
+			job_id  TEXT NOT NULL DEFAULT '',
+			seen_at DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_seen_urls_source ON seen_urls(source);
+		DROP TABLE IF EXISTS source_marks`)
+	return err
+}
+
+// SeenKey is what identifies a posting across scrapes: its URL, or the
+// source's own ID when it has none.
+func SeenKey(j Job) string {
+	if j.URL != "" {
+		return j.URL
+	}
+	return j.ID
+}
+
+// seenBatch stays under SQLite's limit on query parameters.
+const seenBatch = 500
+
+// SeenURLs reports which of keys (see SeenKey) were scraped before, in
+// one query per 500 keys.
+func (s *Store) SeenURLs(keys []string) (map[string]bool, error) {
+	seen := make(map[string]bool)
+	for len(keys) > 0 {
+		batch := keys[:min(len(keys), seenBatch)]
+		keys = keys[len(batch):]
+		args := make([]any, len(batch))
+		for i, k := range batch {
+			args[i] = k
+		}
+		rows, err := s.DB.Query(`SELECT url FROM seen_urls WHERE url IN (?`+
+			strings.Repeat(", ?", len(batch)-1)+`)`, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var url string
+			if err := rows.Scan(&url); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			seen[url] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return seen, nil
+}
+
+// SkipSeen drops the jobs scraped before, checking each source's jobs in
+// one query, and returns the rest with how many were dropped.
+func (s *Store) SkipSeen(jobs []Job) ([]Job, int, error) {
+	bySource := make(map[string][]string)
+	for _, j := range jobs {
+		bySource[j.Source] = append(bySource[j.Source], SeenKey(j))
+	}
+	seen := make(map[string]bool)
+	for _, keys := range bySource {
+		found, err := s.SeenURLs(keys)
+		if err != nil {
+			return nil, 0, err
+		}
+		for k := range found {
+			seen[k] = true
+		}
+	}
+	fresh := Select(jobs, func(j Job) bool { return !seen[SeenKey(j)] })
+	return fresh, len(jobs) - len(fresh), nil
+}
+
+// MarkSeen records jobs as scraped from source.
+func (s *Store) MarkSeen(source string, jobs []Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	now := time.Now().UTC()
+	stmt, err := tx.Prepare(`INSERT OR IGNORE INTO seen_urls (url, source, job_id, seen_at) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, j := range jobs {
+		if _, err := stmt.Exec(SeenKey(j), source, j.ID, now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// MarkSaved records jobs that were saved as seen under their source, so
+// the next scrape skips them. Only saved jobs are recorded: the seen
+// postings are shared by every profile, and one a profile's filters
+// dropped may be wanted by another, or by the same one with other
+// keywords.
+func (s *Store) MarkSaved(jobs []Job) error {
+	bySource := make(map[string][]Job)
+	for _, j := range jobs {
+		bySource[j.Source] = append(bySource[j.Source], j)
+	}
+	for source, jobs := range bySource {
+		if err := s.MarkSeen(source, jobs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
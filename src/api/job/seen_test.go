@@ -0,0 +1,34 @@
+package job
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestStore_SeenURLs(t *testing.T) {
+	s := openTestStore(t)
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	var scraped []Job
+	for i := range 600 {
+		scraped = append(scraped, Job{ID: fmt.Sprintf("hn-%d", i), URL: fmt.Sprintf("https://news.test/%d", i),
+			Source: "hn", PostedDate: day(1 + i%20)})
+	}
+	scraped = append(scraped, Job{ID: "wwr-1", Source: "wwr"})
+	if err := s.MarkSeen("hn", scraped[:550]); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.MarkSeen("wwr", scraped[600:]); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh, seen, err := s.SkipSeen(append(scraped, Job{ID: "wwr-2", Source: "wwr"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seen != 551 || len(fresh) != 51 || fresh[0].ID != "hn-550" || fresh[50].ID != "wwr-2" {
+		t.Errorf("SkipSeen kept %d, skipped %d", len(fresh), seen)
+	}
+
+}
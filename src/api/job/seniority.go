@@ -0,0 +1,23 @@
+package job
+
+import "sprayer/src/api/parse"
+
+// Level is the job's seniority, detected now for jobs saved before
+// detection ran.
+func (j Job) Level() string {
+	if j.Seniority != "" {
+		return j.Seniority
+	}
+	return parse.DetectSeniority(j.Title, j.Description)
+}
+
+// ExtractSeniority detects each job's level from its title and
+// description, before descriptions are capped.
+func ExtractSeniority() Filter {
+	return func(jobs []Job) []Job {
+		return Map(jobs, func(j Job) Job {
+			j.Seniority = j.Level()
+			return j
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package job
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBySeniorityLevels_Detected(t *testing.T) {
+	jobs := []Job{
+		{ID: "friendly", Title: "Senior-friendly junior role"},
+		{ID: "seniority", Title: "Backend Engineer", Description: "Seniority: none required."},
+		{ID: "years", Title: "Backend Engineer", Description: "5+ years of experience with Go."},
+		{ID: "stored", Title: "Engineer", Seniority: "staff"},
+	}
+	ids := func(jobs []Job) []string {
+		var out []string
+		for _, j := range jobs {
+			out = append(out, j.ID)
+		}
+		return out
+	}
+	if got := ids(BySeniorityLevels([]string{"Senior", "staff"}, false)(jobs)); !reflect.DeepEqual(got, []string{"years", "stored"}) {
+		t.Errorf("senior or staff kept %v", got)
+	}
+	if got := ids(BySeniorityLevels([]string{"junior"}, true)(jobs)); !reflect.DeepEqual(got, []string{"friendly", "seniority"}) {
+		t.Errorf("junior or unknown kept %v", got)
+	}
+}
+
+func TestStore_SavesSeniority(t *testing.T) {
+	s := openTestStore(t)
+	jobs := ExtractSeniority()([]Job{{ID: "1", Title: "Sr. Go Engineer"}, {ID: "2", Title: "Go Engineer"}})
+	if err := s.Save(jobs); err != nil {
+		t.Fatal(err)
+	}
+	for id, want := range map[string]string{"1": "senior", "2": "unknown"} {
+		if j, err := s.ByID(id); err != nil || j.Seniority != want {
+			t.Errorf("job %s seniority = %q, %v; want %q", id, j.Seniority, err, want)
+		}
+	}
+}
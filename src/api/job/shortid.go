@@ -0,0 +1,78 @@
+package job
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// migrateShortIDs creates the short ID sequence and numbers any jobs saved
+// before it existed, oldest first. AUTOINCREMENT keeps numbers from being
+// reused after a job is deleted.
+func migrateShortIDs(db *sql.DB) error {
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_short_ids (
+			short_id INTEGER PRIMARY KEY AUTOINCREMENT,
+			job_id   TEXT NOT NULL UNIQUE
+		)`); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`
+		INSERT INTO job_short_ids (job_id)
+		SELECT id FROM jobs WHERE short_id = 0 AND id NOT IN (SELECT job_id FROM job_short_ids)
+		ORDER BY created_at, id`); err != nil {
+		return err
+	}
+	_, err := db.Exec(`
+		UPDATE jobs SET short_id = (SELECT short_id FROM job_short_ids WHERE job_id = jobs.id)
+		WHERE short_id = 0`)
+	return err
+}
+
+// Ref is how a job is shown to the user: "#1234", or the full ID for a
+// job that has not been saved yet.
+func (j Job) Ref() string {
+	if j.ShortID > 0 {
+		return "#" + strconv.FormatInt(j.ShortID, 10)
+	}
+	return j.ID
+}
+
+// ParseShortID reads a short ID written as "#1234" or "1234".
+func ParseShortID(ref string) (int64, bool) {
+	n, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(ref), "#"), 10, 64)
+	return n, err == nil && n > 0
+}
+
+// ByShortID returns the job numbered n.
+func (s *Store) ByShortID(n int64) (*Job, error) {
+	row := s.DB.QueryRow(`SELECT `+jobColumns+` FROM jobs WHERE short_id = ?`, n)
+
+	var j Job
+	if err := scanJob(row, &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// Resolve finds the job a user referred to, by full ID or short ID. "#12"
+// is always a short ID; a bare number is tried as a full ID first, since
+// some sources use numeric IDs.
+func (s *Store) Resolve(ref string) (*Job, error) {
+	ref = strings.TrimSpace(ref)
+	if !strings.HasPrefix(ref, "#") {
+		j, err := s.ByID(ref)
+		if !errors.Is(err, sql.ErrNoRows) {
+			return j, err
+		}
+	}
+	if n, ok := ParseShortID(ref); ok {
+		j, err := s.ByShortID(n)
+		if !errors.Is(err, sql.ErrNoRows) {
+			return j, err
+		}
+	}
+	return nil, fmt.Errorf("no job %s: %w", ref, sql.ErrNoRows)
+}
@@ -0,0 +1,143 @@
+package job
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestShortID_StableAcrossRescrapes(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{{ID: "gh-acme-1", Title: "Go"}, {ID: "hn-2", Title: "Rust"}}); err != nil {
+		t.Fatal(err)
+	}
+	first, _ := s.ByID("hn-2")
+	if first.ShortID != 2 || first.Ref() != "#2" {
+		t.Fatalf("hn-2 numbered %d, want #2", first.ShortID)
+	}
+
+	// A re-scrape replaces the row; the number must survive it.
+	if err := s.Save([]Job{{ID: "hn-2", Title: "Rust (updated)"}, {ID: "rok-3", Title: "Zig"}}); err != nil {
+		t.Fatal(err)
+	}
+	again, _ := s.ByID("hn-2")
+	if again.ShortID != 2 || again.Title != "Rust (updated)" {
+		t.Errorf("re-scraped hn-2 = #%d %q", again.ShortID, again.Title)
+	}
+	if j, _ := s.ByID("rok-3"); j.ShortID != 3 {
+		t.Errorf("new job numbered %d, want 3", j.ShortID)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{{ID: "gh-acme-1"}, {ID: "2"}, {ID: "wwr-3"}}); err != nil {
+		t.Fatal(err)
+	}
+	for ref, want := range map[string]string{
+		"gh-acme-1": "gh-acme-1",
+		"#1":        "gh-acme-1",
+		" #3 ":      "wwr-3",
+		"3":         "wwr-3",
+		"2":         "2", // a numeric full ID wins over short ID 2
+		"#2":        "2",
+	} {
+		j, err := s.Resolve(ref)
+		if err != nil {
+			t.Errorf("Resolve(%q): %v", ref, err)
+			continue
+		}
+		if j.ID != want {
+			t.Errorf("Resolve(%q) = %s, want %s", ref, j.ID, want)
+		}
+	}
+	for _, ref := range []string{"#9", "nope", "#x", ""} {
+		if _, err := s.Resolve(ref); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Resolve(%q) = %v, want not found", ref, err)
+		}
+	}
+}
+
+func TestShortID_BackfillsExistingJobs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sprayer.db")
+	s, err := OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Save([]Job{{ID: "old-1"}, {ID: "old-2"}})
+	// Simulate a database from before short IDs.
+	if _, err := s.DB.Exec(`UPDATE jobs SET short_id = 0; DROP TABLE job_short_ids`); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	seen := map[int64]bool{}
+	for _, id := range []string{"old-1", "old-2"} {
+		j, _ := s.ByID(id)
+		if j.ShortID == 0 || seen[j.ShortID] {
+			t.Errorf("%s backfilled as %d", id, j.ShortID)
+		}
+		seen[j.ShortID] = true
+	}
+	if err := s.Save([]Job{{ID: "new-3"}}); err != nil {
+		t.Fatal(err)
+	}
+	if j, _ := s.ByID("new-3"); j.ShortID != 3 {
+		t.Errorf("first job after backfill numbered %d, want 3", j.ShortID)
+	}
+}
+
+func TestShortID_ConcurrentSavesNeverCollide(t *testing.T) {
+	s := openTestStore(t)
+	const writers, perWriter = 8, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWriter {
+				// Writers overlap on half their IDs, as concurrent scrapes do.
+				id := fmt.Sprintf("w%d-%d", w, i)
+				if i%2 == 0 {
+					id = fmt.Sprintf("shared-%d", i)
+				}
+				if err := s.Save([]Job{{ID: id}}); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	all, err := s.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[int64]string, len(all))
+	for _, j := range all {
+		if j.ShortID == 0 {
+			t.Errorf("%s has no short ID", j.ID)
+		}
+		if other, dup := seen[j.ShortID]; dup {
+			t.Errorf("#%d assigned to both %s and %s", j.ShortID, other, j.ID)
+		}
+		seen[j.ShortID] = j.ID
+	}
+	if want := writers*(perWriter/2) + (perWriter+1)/2; len(all) != want {
+		t.Errorf("%d jobs saved, want %d", len(all), want)
+	}
+}
@@ -0,0 +1,83 @@
+package job
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SortKey is a column a job list sorts by, named as in SortFields.
+type SortKey string
+
+const (
+	SortScore   SortKey = "score"       // best first
+	SortDate    SortKey = "posted_date" // newest first
+	SortTitle   SortKey = "title"       // A to Z
+	SortCompany SortKey = "company"     // A to Z
+)
+
+// SortKeys are the keys a job list offers, in order.
+var SortKeys = []SortKey{SortScore, SortDate, SortTitle, SortCompany}
+
+// Sort is the order of a job list: by Key in its own direction, or the
+// other way when Reverse.
+type Sort struct {
+	Key     SortKey
+	Reverse bool
+}
+
+// ParseSort reads a sort as its key's name, "-" first to reverse it, as
+// in "-posted_date"; "" sorts by score.
+func ParseSort(s string) (Sort, error) {
+	name, reverse := strings.CutPrefix(strings.ToLower(strings.TrimSpace(s)), "-")
+	if name == "" {
+		return Sort{Key: SortScore, Reverse: reverse}, nil
+	}
+	for _, k := range SortKeys {
+		if SortKey(name) == k {
+			return Sort{Key: k, Reverse: reverse}, nil
+		}
+	}
+	names := make([]string, len(SortKeys))
+	for i, k := range SortKeys {
+		names[i] = string(k)
+	}
+	return Sort{}, fmt.Errorf("unknown sort %q (want %s)", s, strings.Join(names, ", "))
+}
+
+// String is s as ParseSort reads it.
+func (s Sort) String() string {
+	key := string(cmp.Or(s.Key, SortScore))
+	if s.Reverse {
+		return "-" + key
+	}
+	return key
+}
+
+// Descending reports whether s puts the greatest score, the newest date
+// or the last name in the alphabet first.
+func (s Sort) Descending() bool {
+	natural := s.Key != SortTitle && s.Key != SortCompany
+	return natural != s.Reverse
+}
+
+// Less orders jobs by s. A score sort breaks ties in favour of jobs
+// closing within window, as ByScoreClosingFirst.
+func (s Sort) Less(now time.Time, window time.Duration) func(a, b Job) bool {
+	var less func(a, b Job) bool
+	switch s.Key {
+	case SortDate:
+		less = ByDateDesc
+	case SortTitle:
+		less = ByTitleAsc
+	case SortCompany:
+		less = ByCompanyAsc
+	default:
+		less = ByScoreClosingFirst(now, window)
+	}
+	if s.Reverse {
+		return func(a, b Job) bool { return less(b, a) }
+	}
+	return less
+}
@@ -0,0 +1,65 @@
+package job_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+func TestSort_Less(t *testing.T) {
+	day := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	jobs := []job.Job{
+		{ID: "1", Title: "backend dev", Company: "Globex", Score: 80, PostedDate: day},
+		{ID: "2", Title: "API Engineer", Company: "acme", Score: 90, PostedDate: day.AddDate(0, 0, -2)},
+		{ID: "3", Title: "Cloud Engineer", Company: "Acme", Score: 80, PostedDate: day.AddDate(0, 0, 1)},
+		{ID: "4", Title: "Data Engineer", Company: "Initech", Score: 70, PostedDate: day},
+	}
+	for _, tc := range []struct {
+		sort string
+		want string
+	}{
+		{"", "2 1 3 4"},
+		{"-score", "4 1 3 2"}, // ties keep their order either way
+		{"posted_date", "3 1 4 2"},
+		{"-posted_date", "2 1 4 3"},
+		{"title", "2 1 3 4"},
+		{"company", "2 3 1 4"},
+		{"-company", "4 1 2 3"},
+	} {
+		s, err := job.ParseSort(tc.sort)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, j := range job.SortBy(s.Less(day, 0))(jobs) {
+			ids = append(ids, j.ID)
+		}
+		if got := strings.Join(ids, " "); got != tc.want {
+			t.Errorf("%q sorts %s, want %s", tc.sort, got, tc.want)
+		}
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	for in, want := range map[string]job.Sort{
+		"":            {Key: job.SortScore},
+		"Posted_Date": {Key: job.SortDate},
+		"-company":    {Key: job.SortCompany, Reverse: true},
+	} {
+		got, err := job.ParseSort(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSort(%q) = %v, %v; want %v", in, got, err, want)
+		}
+		if back, _ := job.ParseSort(got.String()); back != got {
+			t.Errorf("%v does not round trip through %q", got, got.String())
+		}
+	}
+	if _, err := job.ParseSort("salary"); err == nil {
+		t.Error("ParseSort accepted an unknown key")
+	}
+	if (job.Sort{Key: job.SortDate}).Descending() == (job.Sort{Key: job.SortTitle}).Descending() {
+		t.Error("date and title sort the same way")
+	}
+}
@@ -0,0 +1,142 @@
+package job
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Status is where the user stands with a job, from first interest to an
+// outcome. Unlike triage state it is not scoped to a profile: an
+// application is real whichever profile shows the job.
+type Status string
+
+const (
+	StatusNone       Status = ""
+	StatusInterested Status = "interested"
+	StatusApplied    Status = "applied"
+	StatusScreening  Status = "screening"
+	StatusInterview  Status = "interview"
+	StatusOffer      Status = "offer"
+	StatusRejected   Status = "rejected"
+	StatusGhosted    Status = "ghosted"
+)
+
+// Statuses lists every status in lifecycle order.
+var Statuses = []Status{
+	StatusInterested, StatusApplied, StatusScreening, StatusInterview,
+	StatusOffer, StatusRejected, StatusGhosted,
+}
+
+// ParseStatus reads a status name; "none" clears the status.
+func ParseStatus(s string) (Status, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "none" {
+		return StatusNone, nil
+	}
+	if st := Status(s); slices.Contains(Statuses, st) {
+		return st, nil
+	}
+	names := make([]string, len(Statuses))
+	for i, st := range Statuses {
+		names[i] = string(st)
+	}
+	return "", fmt.Errorf("unknown status %q; use one of %s or none", s, strings.Join(names, ", "))
+}
+
+// Next is the status after s in lifecycle order, wrapping from the last
+// back to none.
+func (s Status) Next() Status {
+	i := slices.Index(Statuses, s)
+	if i == len(Statuses)-1 {
+		return StatusNone
+	}
+	return Statuses[i+1]
+}
+
+// Badge is the status abbreviated for a list row, or "" for none.
+func (s Status) Badge() string {
+	switch s {
+	case StatusNone:
+		return ""
+	case StatusInterview:
+		return "IVW"
+	case StatusOffer:
+		return "OFR"
+	default:
+		return strings.ToUpper(string(s[:3]))
+	}
+}
+
+// StatusChange is one entry in a job's status history.
+type StatusChange struct {
+	From Status    `json:"from"`
+	To   Status    `json:"to"`
+	At   time.Time `json:"at"`
+	Note string    `json:"note,omitempty"`
+}
+
+func migrateStatus(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_status_history (
+			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			job_id      TEXT NOT NULL,
+			from_status TEXT NOT NULL DEFAULT '',
+			to_status   TEXT NOT NULL DEFAULT '',
+			note        TEXT DEFAULT '',
+			changed_at  DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_job_status_history_job ON job_status_history(job_id)`)
+	return err
+}
+
+// SetStatus moves a job to st at the given time, recording the change
+// and note in its history. Moving to applied also marks the job applied,
+// with a follow-up DefaultFollowUp later.
+// An unknown job returns sql.ErrNoRows.
+func (s *Store) SetStatus(jobID string, st Status, note string, at time.Time) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var from Status
+	if err := tx.QueryRow(`SELECT status FROM jobs WHERE id = ?`, jobID).Scan(&from); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE jobs SET status = ? WHERE id = ?`, st, jobID); err != nil {
+		return err
+	}
+	if st == StatusApplied {
+		if err := markApplied(tx, jobID, at, DefaultFollowUp); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(`INSERT INTO job_status_history (job_id, from_status, to_status, note, changed_at)
+		VALUES (?, ?, ?, ?, ?)`, jobID, from, st, note, at.UTC()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// StatusHistory returns a job's status changes, oldest first.
+func (s *Store) StatusHistory(jobID string) ([]StatusChange, error) {
+	rows, err := s.DB.Query(`SELECT from_status, to_status, changed_at, note FROM job_status_history
+		WHERE job_id = ? ORDER BY changed_at, id`, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []StatusChange
+	for rows.Next() {
+		var c StatusChange
+		if err := rows.Scan(&c.From, &c.To, &c.At, &c.Note); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
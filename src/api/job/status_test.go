@@ -0,0 +1,92 @@
+package job
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatus_NextCyclesThroughLifecycle(t *testing.T) {
+	st := StatusNone
+	var seen []Status
+	for range len(Statuses) + 1 {
+		st = st.Next()
+		seen = append(seen, st)
+	}
+	if seen[0] != StatusInterested || seen[len(Statuses)-1] != StatusGhosted || seen[len(Statuses)] != StatusNone {
+		t.Errorf("cycle = %v", seen)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	if st, err := ParseStatus(" Interview "); err != nil || st != StatusInterview {
+		t.Errorf("ParseStatus(Interview) = %q, %v", st, err)
+	}
+	if st, err := ParseStatus("none"); err != nil || st != StatusNone {
+		t.Errorf("ParseStatus(none) = %q, %v", st, err)
+	}
+	if _, err := ParseStatus("hired"); err == nil {
+		t.Error("ParseStatus accepted an unknown status")
+	}
+	for _, st := range Statuses {
+		if b := st.Badge(); len(b) != 3 {
+			t.Errorf("%s badge = %q", st, b)
+		}
+	}
+}
+
+func TestStore_SetStatus(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{{ID: "a", Title: "Go Engineer"}}); err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	for i, st := range []Status{StatusInterested, StatusApplied, StatusInterview} {
+		if err := s.SetStatus("a", st, "", day.AddDate(0, 0, i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.SetStatus("a", StatusRejected, "went with someone local", day.AddDate(0, 0, 7)); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := s.ByID("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.Status != StatusRejected || !j.Applied || !j.AppliedDate.Equal(day.AddDate(0, 0, 1)) {
+		t.Errorf("job = status %q, applied %v on %v", j.Status, j.Applied, j.AppliedDate)
+	}
+
+	// A re-scrape does not know the status.
+	if err := s.Save([]Job{{ID: "a", Title: "Go Engineer"}}); err != nil {
+		t.Fatal(err)
+	}
+	if j, _ := s.ByID("a"); j.Status != StatusRejected {
+		t.Errorf("status after re-scrape = %q", j.Status)
+	}
+
+	hist, err := s.StatusHistory("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hist) != 4 || hist[0].From != StatusNone || hist[3].From != StatusInterview ||
+		hist[3].To != StatusRejected || hist[3].Note != "went with someone local" {
+		t.Errorf("history = %+v", hist)
+	}
+
+	if err := s.SetStatus("missing", StatusApplied, "", day); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown job: %v", err)
+	}
+}
+
+func TestByStatus(t *testing.T) {
+	jobs := []Job{{ID: "a"}, {ID: "b", Status: StatusRejected}, {ID: "c", Status: StatusApplied}}
+	if got := ExcludeStatuses(StatusRejected)(jobs); len(got) != 2 || got[1].ID != "c" {
+		t.Errorf("ExcludeStatuses = %v", got)
+	}
+	if got := ByStatus(StatusNone, StatusApplied)(jobs); len(got) != 2 || got[0].ID != "a" {
+		t.Errorf("ByStatus = %v", got)
+	}
+}
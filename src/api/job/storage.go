@@ -1,7 +1,9 @@
 package job
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -16,12 +18,26 @@ type Store struct {
 	DB *sql.DB
 }
 
+// SchemaVersion is the database schema this binary expects, recorded in
+// SQLite's user_version by migrate.
+const SchemaVersion = 1
+
+// DataDir is where sprayer keeps its database and generated files.
+func DataDir() string {
+	return filepath.Join(os.Getenv("HOME"), ".sprayer")
+}
+
 // NewStore opens (or creates) the SQLite database.
 func NewStore() (*Store, error) {
-	dir := filepath.Join(os.Getenv("HOME"), ".sprayer")
+	dir := DataDir()
 	os.MkdirAll(dir, 0755)
 
-	db, err := sql.Open("sqlite3", filepath.Join(dir, "sprayer.db"))
+	return OpenStore(filepath.Join(dir, "sprayer.db"))
+}
+
+// OpenStore opens (or creates) the SQLite database at path.
+func OpenStore(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
 	if err != nil {
 		return nil, err
 	}
@@ -62,45 +78,269 @@ func migrate(db *sql.DB) error {
 			key TEXT PRIMARY KEY,
 			last_run DATETIME
 		)`)
+	if err != nil {
+		return err
+	}
+	// Triage flags are scoped to a profile: hiding a job in one profile
+	// must not hide it in another. Applied state stays on jobs since an
+	// application is real regardless of profile.
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS job_profile_state (
+			job_id     TEXT NOT NULL,
+			profile_id TEXT NOT NULL,
+			hidden     BOOLEAN DEFAULT 0,
+			starred    BOOLEAN DEFAULT 0,
+			verdict    TEXT DEFAULT '',
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (job_id, profile_id)
+		)`)
+	if err != nil {
+		return err
+	}
+	if err := EnsureColumns(db, "job_profile_state", []Column{
+		{"archived", "BOOLEAN DEFAULT 0"},
+		{"score", "INTEGER DEFAULT NULL"},
+	}); err != nil {
+		return err
+	}
+	if err := EnsureColumns(db, "jobs", []Column{
+		{"salary_min", "INTEGER DEFAULT 0"},
+		{"salary_max", "INTEGER DEFAULT 0"},
+		{"salary_currency", "TEXT DEFAULT ''"},
+		{"expires_at", "DATETIME DEFAULT NULL"},
+		{"deadline", "DATETIME DEFAULT NULL"},
+		{"deadline_text", "TEXT DEFAULT ''"},
+		{"short_id", "INTEGER DEFAULT 0"},
+		{"cc", "TEXT DEFAULT ''"},
+		{"description_truncated", "BOOLEAN DEFAULT 0"},
+		{"tags", "TEXT DEFAULT ''"},
+		{"note", "TEXT DEFAULT ''"},
+		{"score_adjust", "INTEGER DEFAULT 0"},
+		{"status", "TEXT DEFAULT ''"},
+		{"follow_up_at", "DATETIME DEFAULT NULL"},
+		{"follow_up_done", "BOOLEAN DEFAULT 0"},
+		{"cv_path", "TEXT DEFAULT ''"},
+		{"seniority", "TEXT DEFAULT ''"},
+		{"language", "TEXT DEFAULT ''"},
+		{"enriched", "INTEGER DEFAULT 0"},
+	}); err != nil {
+		return err
+	}
+	if err := migrateShortIDs(db); err != nil {
+		return err
+	}
+	if err := migrateDescriptions(db); err != nil {
+		return err
+	}
+	if err := migrateStatus(db); err != nil {
+		return err
+	}
+	if err := migrateSeen(db); err != nil {
+		return err
+	}
+	// Never lower the version: a newer binary may have migrated this file.
+	if v, err := UserVersion(db); err != nil || v >= SchemaVersion {
+		return err
+	}
+	_, err = db.Exec(fmt.Sprintf("PRAGMA user_version = %d", SchemaVersion))
 	return err
 }
 
-// Save upserts jobs into the database.
+// UserVersion reads the schema version recorded in db.
+func UserVersion(db *sql.DB) (int, error) {
+	var v int
+	err := db.QueryRow("PRAGMA user_version").Scan(&v)
+	return v, err
+}
+
+// Column is a column name and its SQLite declaration.
+type Column struct {
+	Name string
+	Decl string
+}
+
+// EnsureColumns adds any of cols missing from table. CREATE TABLE IF NOT
+// EXISTS never alters an existing table, so columns introduced after a
+// table first shipped are added here. Declarations need a DEFAULT so
+// existing rows scan cleanly.
+func EnsureColumns(db *sql.DB, table string, cols []Column) error {
+	rows, err := db.Query(`PRAGMA table_info(` + table + `)`)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			ctype      string
+			notnull    int
+			dflt       sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &ctype, &notnull, &dflt, &primaryKey); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[name] = true
+	}
+	rows.Close()
+
+	for _, c := range cols {
+		if existing[c.Name] {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + c.Name + ` ` + c.Decl); err != nil {
+			return fmt.Errorf("add column %s.%s: %w", table, c.Name, err)
+		}
+	}
+	return nil
+}
+
+// jobColumns lists the jobs table columns in the order scanJob expects.
+const jobColumns = `id, title, company, location, description, url, source,
+	posted_date, salary, salary_min, salary_max, salary_currency, job_type, email,
+	score, has_traps, traps, applied, applied_date, expires_at, deadline, deadline_text, short_id, cc,
+	description_truncated, tags, note, score_adjust, status, follow_up_at, follow_up_done, cv_path, seniority, language, enriched`
+
+// prefixed qualifies every column in jobColumns with a table alias.
+func prefixed(alias string) string {
+	cols := strings.Split(jobColumns, ",")
+	for i, c := range cols {
+		cols[i] = alias + "." + strings.TrimSpace(c)
+	}
+	return strings.Join(cols, ", ")
+}
+
+// upsertSet updates a stored job from a new save of it. A scrape always
+// reports a job unapplied and untracked, so applied, applied_date and the
+// follow-up only ever go from unset to set and an empty status or CV path
+// keeps the stored one; created_at keeps when the job was first seen.
+var upsertSet = func() string {
+	var set []string
+	for _, c := range strings.Split(jobColumns, ",") {
+		switch c = strings.TrimSpace(c); c {
+		case "id", "applied", "applied_date", "status", "follow_up_at", "follow_up_done", "cv_path":
+		default:
+			set = append(set, c+" = excluded."+c)
+		}
+	}
+	set = append(set,
+		"applied_date = CASE WHEN jobs.applied THEN jobs.applied_date ELSE excluded.applied_date END",
+		"applied = jobs.applied OR excluded.applied",
+		"status = CASE WHEN excluded.status = '' THEN jobs.status ELSE excluded.status END",
+		"follow_up_at = COALESCE(jobs.follow_up_at, excluded.follow_up_at)",
+		"follow_up_done = jobs.follow_up_done OR excluded.follow_up_done",
+		"cv_path = CASE WHEN excluded.cv_path = '' THEN jobs.cv_path ELSE excluded.cv_path END")
+	return strings.Join(set, ", ")
+}()
+
+// Save upserts jobs into the database. Saving a job already stored
+// updates it in place, keeping whether it was applied to. The score it
+// is saved with is newer than any SetScores stored for it, so those are
+// dropped.
 func (s *Store) Save(jobs []Job) error {
-	tx, err := s.DB.Begin()
+	return s.SaveContext(context.Background(), jobs)
+}
+
+// SaveContext is Save, rolled back if ctx is cancelled before it commits.
+func (s *Store) SaveContext(ctx context.Context, jobs []Job) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.Prepare(`
-		INSERT OR REPLACE INTO jobs
-		(id, title, company, location, description, url, source, posted_date, salary, job_type, email, score, has_traps, traps, applied, applied_date)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	// The short ID comes from job_short_ids, keyed on the job ID, so a
+	// re-scraped job keeps the number it was first given.
+	// INSERT OR IGNORE would burn a number on every known job.
+	assign, err := tx.PrepareContext(ctx, `INSERT INTO job_short_ids (job_id)
+		SELECT ?1 WHERE NOT EXISTS (SELECT 1 FROM job_short_ids WHERE job_id = ?1)`)
+	if err != nil {
+		return err
+	}
+	defer assign.Close()
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO jobs (`+jobColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?,
+			(SELECT short_id FROM job_short_ids WHERE job_id = ?), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET `+upsertSet)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
+	rescored, err := tx.PrepareContext(ctx, `UPDATE job_profile_state SET score = NULL WHERE job_id = ? AND score IS NOT NULL`)
+	if err != nil {
+		return err
+	}
+	defer rescored.Close()
 
 	for _, j := range jobs {
-		traps := strings.Join(j.Traps, ",")
-		_, err := stmt.Exec(j.ID, j.Title, j.Company, j.Location, j.Description,
-			j.URL, j.Source, j.PostedDate, j.Salary, j.JobType, j.Email,
-			j.Score, j.HasTraps, traps, j.Applied, j.AppliedDate)
+		j.CapDescription()
+		traps := encodeTraps(j.Traps)
+		var expires any
+		if j.ExpiresAt != nil {
+			expires = j.ExpiresAt.UTC() // compared as text by the active predicate
+		}
+		var deadline any
+		if j.Deadline != nil {
+			deadline = j.Deadline.UTC()
+		}
+		var followUp any
+		if j.FollowUpAt != nil {
+			followUp = j.FollowUpAt.UTC()
+		}
+		if _, err := assign.ExecContext(ctx, j.ID); err != nil {
+			return err
+		}
+		_, err := stmt.ExecContext(ctx, j.ID, j.Title, j.Company, j.Location, j.Description,
+			j.URL, j.Source, j.PostedDate, j.Salary, j.SalaryMin, j.SalaryMax, j.SalaryCurrency,
+			j.JobType, j.Email, j.Score, j.HasTraps, traps, j.Applied, j.AppliedDate, expires,
+			deadline, j.DeadlineText, j.ID, strings.Join(j.Cc, ","), j.Truncated,
+			strings.Join(j.Tags, ","), j.Note, j.ScoreAdjust, j.Status, followUp, j.FollowUpDone, j.CVPath, j.Seniority, j.Language, j.Enriched)
 		if err != nil {
 			return err
 		}
+		if _, err := rescored.ExecContext(ctx, j.ID); err != nil {
+			return err
+		}
+		if err := saveOverflow(tx, j); err != nil {
+			return err
+		}
 	}
 
 	return tx.Commit()
 }
 
+// Known reports which of ids are already stored.
+func (s *Store) Known(ids []string) (map[string]bool, error) {
+	known := make(map[string]bool)
+	stmt, err := s.DB.Prepare(`SELECT 1 FROM jobs WHERE id = ?`)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	for _, id := range ids {
+		var one int
+		switch err := stmt.QueryRow(id).Scan(&one); err {
+		case nil:
+			known[id] = true
+		case sql.ErrNoRows:
+		default:
+			return nil, err
+		}
+	}
+	return known, nil
+}
+
 // All returns every job in the database.
 func (s *Store) All() ([]Job, error) {
-	rows, err := s.DB.Query(`
-		SELECT id, title, company, location, description, url, source,
-		       posted_date, salary, job_type, email, score, has_traps, traps, applied, applied_date
-		FROM jobs ORDER BY score DESC`)
+	return s.AllContext(context.Background())
+}
+
+// AllContext is All, abandoning the query if ctx is cancelled.
+func (s *Store) AllContext(ctx context.Context) ([]Job, error) {
+	rows, err := s.DB.QueryContext(ctx, `SELECT `+jobColumns+` FROM jobs ORDER BY score DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -111,42 +351,286 @@ func (s *Store) All() ([]Job, error) {
 
 // ByID returns a single job.
 func (s *Store) ByID(id string) (*Job, error) {
-	row := s.DB.QueryRow(`
-		SELECT id, title, company, location, description, url, source,
-		       posted_date, salary, job_type, email, score, has_traps, traps, applied, applied_date
-		FROM jobs WHERE id = ?`, id)
+	return s.ByIDContext(context.Background(), id)
+}
+
+// ByIDContext is ByID, abandoning the query if ctx is cancelled.
+func (s *Store) ByIDContext(ctx context.Context, id string) (*Job, error) {
+	row := s.DB.QueryRowContext(ctx, `SELECT `+jobColumns+` FROM jobs WHERE id = ?`, id)
 
 	var j Job
-	var trapsStr string
-	err := row.Scan(&j.ID, &j.Title, &j.Company, &j.Location, &j.Description,
-		&j.URL, &j.Source, &j.PostedDate, &j.Salary, &j.JobType, &j.Email,
-		&j.Score, &j.HasTraps, &trapsStr, &j.Applied, &j.AppliedDate)
+	if err := scanJob(row, &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// Delete removes a job with its triage state and any description
+// overflow. Its short ID stays reserved, so a later re-scrape gets the
+// same number back. Deleting an unknown job returns sql.ErrNoRows.
+func (s *Store) Delete(id string) error {
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is Delete, rolled back if ctx is cancelled before it
+// commits.
+func (s *Store) DeleteContext(ctx context.Context, id string) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := deleteJob(tx, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func deleteJob(tx *sql.Tx, id string) error {
+	res, err := tx.Exec(`DELETE FROM jobs WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	for _, q := range []string{
+		`DELETE FROM job_profile_state WHERE job_id = ?`,
+		`DELETE FROM job_description_overflow WHERE job_id = ?`,
+		`DELETE FROM job_status_history WHERE job_id = ?`,
+	} {
+		if _, err := tx.Exec(q, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ForProfile returns the profile's active jobs joined with the triage
+// state it recorded. Pass Include* options to widen the active predicate.
+func (s *Store) ForProfile(profileID string, opts ...ActiveOption) ([]Job, error) {
+	return s.ForProfileContext(context.Background(), profileID, opts...)
+}
+
+// ForProfileContext is ForProfile, abandoning the query if ctx is
+// cancelled.
+func (s *Store) ForProfileContext(ctx context.Context, profileID string, opts ...ActiveOption) ([]Job, error) {
+	return s.forProfile(ctx, profileID, "", nil, opts)
+}
+
+// forProfile is ForProfile with an extra SQL condition on jobs aliased j.
+func (s *Store) forProfile(ctx context.Context, profileID, cond string, condArgs []any, opts []ActiveOption) ([]Job, error) {
+	return s.queryForProfile(ctx, prefixed("j"), profileID, cond, condArgs, "ORDER BY "+profileScore+" DESC", opts)
+}
+
+// summaryColumns are prefixed("j") with the description left empty.
+var summaryColumns = strings.Replace(prefixed("j"), "j.description,", "'' AS description,", 1)
+
+// Summaries is ForProfile without descriptions, for listings of many jobs
+// that show none; Description reads one when it is wanted.
+func (s *Store) Summaries(profileID string, opts ...ActiveOption) ([]Job, error) {
+	return s.SummariesContext(context.Background(), profileID, opts...)
+}
+
+// SummariesContext is Summaries, abandoning the query if ctx is
+// cancelled.
+func (s *Store) SummariesContext(ctx context.Context, profileID string, opts ...ActiveOption) ([]Job, error) {
+	return s.queryForProfile(ctx, summaryColumns, profileID, "", nil, "ORDER BY "+profileScore+" DESC", opts)
+}
+
+// Description returns a job's stored description, capped as saved; see
+// FullDescription for the whole text. An unknown job returns
+// sql.ErrNoRows.
+func (s *Store) Description(id string) (string, error) {
+	return s.DescriptionContext(context.Background(), id)
+}
+
+// DescriptionContext is Description, abandoning the query if ctx is
+// cancelled.
+func (s *Store) DescriptionContext(ctx context.Context, id string) (string, error) {
+	var text string
+	err := s.DB.QueryRowContext(ctx, `SELECT description FROM jobs WHERE id = ?`, id).Scan(&text)
+	return text, err
+}
+
+// profileScope is the FROM and WHERE shared by the profile queries, with
+// the arguments they bind.
+func profileScope(profileID, cond string, condArgs []any, opts []ActiveOption) (string, []any) {
+	where, args := newActiveScope(opts).where()
+	if cond != "" {
+		where += " AND (" + cond + ")"
+		args = append(args, condArgs...)
+	}
+	return `FROM jobs j
+		LEFT JOIN job_profile_state st ON st.job_id = j.id AND st.profile_id = ?
+		WHERE ` + where, append([]any{profileID}, args...)
+}
+
+// profileScore is a job's score for the profile in profileScope: the one
+// SetScores stored for it since it was last saved, else the score it was
+// saved with.
+const profileScore = "COALESCE(st.score, j.score)"
+
+// queryForProfile runs forProfile's query for cols, the job columns
+// aliased j, with tail, an ORDER BY and perhaps a LIMIT, after the WHERE;
+// tail's arguments go at the end. Score reads profileScore.
+func (s *Store) queryForProfile(ctx context.Context, cols, profileID, cond string, condArgs []any, tail string, opts []ActiveOption, tailArgs ...any) ([]Job, error) {
+	cols = strings.Replace(cols, "j.score,", profileScore+",", 1)
+	from, args := profileScope(profileID, cond, condArgs, opts)
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT `+cols+`,
+		       COALESCE(st.hidden, 0), COALESCE(st.archived, 0),
+		       COALESCE(st.starred, 0), COALESCE(st.verdict, '')
+		`+from+`
+		`+tail, append(args, tailArgs...)...)
 	if err != nil {
 		return nil, err
 	}
-	if trapsStr != "" {
-		j.Traps = strings.Split(trapsStr, ",")
+	defer rows.Close()
+
+	var jobs []Job
+	for rows.Next() {
+		var j Job
+		var verdict string
+		if err := scanJob(rows, &j, &j.Hidden, &j.Archived, &j.Starred, &verdict); err != nil {
+			return nil, err
+		}
+		j.Verdict = Verdict(verdict)
+		jobs = append(jobs, j)
 	}
-	return &j, nil
+	return jobs, rows.Err()
+}
+
+// SetHidden hides or unhides a job for a single profile.
+func (s *Store) SetHidden(jobID, profileID string, hidden bool) error {
+	return s.SetHiddenContext(context.Background(), jobID, profileID, hidden)
+}
+
+// SetHiddenContext is SetHidden, abandoned if ctx is cancelled.
+func (s *Store) SetHiddenContext(ctx context.Context, jobID, profileID string, hidden bool) error {
+	return s.setState(ctx, jobID, profileID, "hidden", hidden)
+}
+
+// SetArchived archives or restores a job for a single profile.
+func (s *Store) SetArchived(jobID, profileID string, archived bool) error {
+	return s.SetArchivedContext(context.Background(), jobID, profileID, archived)
+}
+
+// SetArchivedContext is SetArchived, abandoned if ctx is cancelled.
+func (s *Store) SetArchivedContext(ctx context.Context, jobID, profileID string, archived bool) error {
+	return s.setState(ctx, jobID, profileID, "archived", archived)
+}
+
+// SetStarred stars or unstars a job for a single profile.
+func (s *Store) SetStarred(jobID, profileID string, starred bool) error {
+	return s.SetStarredContext(context.Background(), jobID, profileID, starred)
+}
+
+// SetStarredContext is SetStarred, abandoned if ctx is cancelled.
+func (s *Store) SetStarredContext(ctx context.Context, jobID, profileID string, starred bool) error {
+	return s.setState(ctx, jobID, profileID, "starred", starred)
+}
+
+// SetVerdict records a triage verdict for a job within a single profile.
+func (s *Store) SetVerdict(jobID, profileID string, v Verdict) error {
+	return s.SetVerdictContext(context.Background(), jobID, profileID, v)
+}
+
+// SetVerdictContext is SetVerdict, abandoned if ctx is cancelled.
+func (s *Store) SetVerdictContext(ctx context.Context, jobID, profileID string, v Verdict) error {
+	return s.setState(ctx, jobID, profileID, "verdict", string(v))
+}
+
+// SetCVPath links the tailored CV built for a job; "" unlinks it. An
+// unknown job returns sql.ErrNoRows.
+func (s *Store) SetCVPath(jobID, path string) error {
+	res, err := s.DB.Exec(`UPDATE jobs SET cv_path = ? WHERE id = ?`, path, jobID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SetScores stores scores, by job ID, as the jobs' scores for profileID
+// alone, leaving what other profiles see alone.
+func (s *Store) SetScores(profileID string, scores map[string]int) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	stmt, err := tx.Prepare(`
+		INSERT INTO job_profile_state (job_id, profile_id, score, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(job_id, profile_id) DO UPDATE SET
+			score = excluded.score,
+			updated_at = CURRENT_TIMESTAMP`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for id, score := range scores {
+		if _, err := stmt.Exec(id, profileID, score); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// setState upserts one column of job_profile_state. column is never user input.
+func (s *Store) setState(ctx context.Context, jobID, profileID, column string, value any) error {
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT INTO job_profile_state (job_id, profile_id, `+column+`, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(job_id, profile_id) DO UPDATE SET
+			`+column+` = excluded.`+column+`,
+			updated_at = CURRENT_TIMESTAMP`,
+		jobID, profileID, value)
+	return err
+}
+
+// scanner is satisfied by *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanJob scans the jobColumns of one row into j, followed by any extra
+// destinations selected after them.
+func scanJob(sc scanner, j *Job, extra ...any) error {
+	var trapsStr, ccStr, tagsStr string
+	dest := []any{&j.ID, &j.Title, &j.Company, &j.Location, &j.Description,
+		&j.URL, &j.Source, &j.PostedDate, &j.Salary, &j.SalaryMin, &j.SalaryMax, &j.SalaryCurrency,
+		&j.JobType, &j.Email, &j.Score, &j.HasTraps, &trapsStr, &j.Applied, &j.AppliedDate, &j.ExpiresAt,
+		&j.Deadline, &j.DeadlineText, &j.ShortID, &ccStr, &j.Truncated, &tagsStr, &j.Note, &j.ScoreAdjust, &j.Status,
+		&j.FollowUpAt, &j.FollowUpDone, &j.CVPath, &j.Seniority, &j.Language, &j.Enriched}
+	if err := sc.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	j.Traps = decodeTraps(trapsStr)
+	if ccStr != "" {
+		j.Cc = strings.Split(ccStr, ",")
+	}
+	if tagsStr != "" {
+		j.Tags = strings.Split(tagsStr, ",")
+	}
+	return nil
 }
 
 func scanJobs(rows *sql.Rows) ([]Job, error) {
 	var jobs []Job
 	for rows.Next() {
 		var j Job
-		var trapsStr string
-		err := rows.Scan(&j.ID, &j.Title, &j.Company, &j.Location, &j.Description,
-			&j.URL, &j.Source, &j.PostedDate, &j.Salary, &j.JobType, &j.Email,
-			&j.Score, &j.HasTraps, &trapsStr, &j.Applied, &j.AppliedDate)
-		if err != nil {
+		if err := scanJob(rows, &j); err != nil {
 			return nil, err
 		}
-		if trapsStr != "" {
-			j.Traps = strings.Split(trapsStr, ",")
-		}
 		jobs = append(jobs, j)
 	}
-	return jobs, nil
+	return jobs, rows.Err()
 }
 
 // GetLastScrape returns the last time a scrape was run for the given key.
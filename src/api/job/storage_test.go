@@ -0,0 +1,311 @@
+package job
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := OpenStore(filepath.Join(t.TempDir(), "sprayer.db"))
+	if err != nil {
+		t.Fatalf("OpenStore: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func byID(jobs []Job) map[string]Job {
+	m := make(map[string]Job, len(jobs))
+	for _, j := range jobs {
+		m[j.ID] = j
+	}
+	return m
+}
+
+func TestStore_ProfileScopedState(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{
+		{ID: "fe-1", Title: "Frontend Engineer"},
+		{ID: "be-1", Title: "Backend Engineer"},
+	}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := s.SetHidden("fe-1", "backend", true); err != nil {
+		t.Fatalf("SetHidden: %v", err)
+	}
+	if err := s.SetStarred("fe-1", "frontend", true); err != nil {
+		t.Fatalf("SetStarred: %v", err)
+	}
+	if err := s.SetVerdict("be-1", "backend", VerdictYes); err != nil {
+		t.Fatalf("SetVerdict: %v", err)
+	}
+
+	backend, err := s.ForProfile("backend", IncludeHidden())
+	if err != nil {
+		t.Fatalf("ForProfile(backend): %v", err)
+	}
+	frontend, err := s.ForProfile("frontend", IncludeHidden())
+	if err != nil {
+		t.Fatalf("ForProfile(frontend): %v", err)
+	}
+
+	b, f := byID(backend), byID(frontend)
+	if !b["fe-1"].Hidden || b["fe-1"].Starred {
+		t.Errorf("backend fe-1 = hidden:%v starred:%v, want hidden only", b["fe-1"].Hidden, b["fe-1"].Starred)
+	}
+	if f["fe-1"].Hidden || !f["fe-1"].Starred {
+		t.Errorf("frontend fe-1 = hidden:%v starred:%v, want starred only", f["fe-1"].Hidden, f["fe-1"].Starred)
+	}
+	if b["be-1"].Verdict != VerdictYes || f["be-1"].Verdict != VerdictNone {
+		t.Errorf("verdicts leaked across profiles: backend=%q frontend=%q", b["be-1"].Verdict, f["be-1"].Verdict)
+	}
+
+	visible, _ := s.ForProfile("backend")
+	if len(visible) != 1 || visible[0].ID != "be-1" {
+		t.Errorf("ForProfile(backend) = %v, want only be-1", visible)
+	}
+	if all, _ := s.ForProfile("frontend"); len(all) != 2 {
+		t.Errorf("frontend should still see both jobs")
+	}
+}
+
+func TestStore_StateSurvivesRescrape(t *testing.T) {
+	s := openTestStore(t)
+	j := Job{ID: "1", Title: "Go Dev"}
+	s.Save([]Job{j})
+	s.SetHidden("1", "default", true)
+
+	// Re-scraping upserts the job row; the profile state lives elsewhere.
+	j.Title = "Go Developer"
+	if err := s.Save([]Job{j}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	jobs, _ := s.ForProfile("default", IncludeHidden())
+	if len(jobs) != 1 || !jobs[0].Hidden {
+		t.Errorf("hidden flag lost after re-save: %+v", jobs)
+	}
+
+	// Unhiding flips the same row rather than inserting a new one.
+	s.SetHidden("1", "default", false)
+	jobs, _ = s.ForProfile("default")
+	if jobs[0].Hidden {
+		t.Errorf("expected job to be visible after unhide")
+	}
+}
+
+func TestStore_SetScoresIsProfileScoped(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{{ID: "a", Title: "Go Dev", Score: 40}, {ID: "b", Title: "Rust Dev", Score: 60}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetScores("backend", map[string]int{"a": 90}); err != nil {
+		t.Fatalf("SetScores: %v", err)
+	}
+
+	backend, _ := s.ForProfile("backend")
+	if len(backend) != 2 || backend[0].ID != "a" || backend[0].Score != 90 || backend[1].Score != 60 {
+		t.Errorf("backend sees %+v, want a at 90 first and b at 60", backend)
+	}
+	page, _, _ := s.ForProfilePage("backend", Page{Sort: "score", Desc: true})
+	if len(page) != 2 || page[0].ID != "a" {
+		t.Errorf("page not sorted by the profile's score: %+v", page)
+	}
+	frontend, _ := s.ForProfile("frontend")
+	if f := byID(frontend); f["a"].Score != 40 || frontend[0].ID != "b" {
+		t.Errorf("frontend sees %+v, want the saved scores", frontend)
+	}
+}
+
+func TestStore_RescoreThenRescrape(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{{ID: "a", Title: "Go Dev", Score: 50}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetScores("backend", map[string]int{"a": 70}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetStarred("a", "backend", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save([]Job{{ID: "a", Title: "Go Dev", Score: 95}}); err != nil {
+		t.Fatal(err)
+	}
+	backend, err := s.ForProfile("backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backend) != 1 || backend[0].Score != 95 || !backend[0].Starred {
+		t.Errorf("after re-scrape backend sees %+v, want the new score 95, still starred", backend)
+	}
+}
+
+func TestStore_ByID(t *testing.T) {
+	s := openTestStore(t)
+	want := Job{ID: "be-1", Title: "Backend Engineer", Company: "Acme", Description: "Write Go.",
+		Email: "jobs@acme.test", HasTraps: true, Traps: []TrapMatch{{Name: "prompt-injection", Severity: TrapHigh, Match: "subject line"}}}
+	if err := s.Save([]Job{want}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.ByID("be-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Description != want.Description || got.Email != want.Email || !reflect.DeepEqual(got.Traps, want.Traps) {
+		t.Errorf("ByID = %+v", got)
+	}
+	if _, err := s.ByID("nope"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("ByID(unknown) err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestStore_SummariesAndDescription(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{
+		{ID: "be-1", Title: "Backend Engineer", Company: "Acme", Description: "Write Go.", Score: 80, Seniority: "senior"},
+		{ID: "fe-1", Title: "Frontend Engineer", Company: "Initech", Description: "Write TS.", Score: 60},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetHidden("fe-1", "alice", true); err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := s.Summaries("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != "be-1" || jobs[0].Title != "Backend Engineer" || jobs[0].Seniority != "senior" || jobs[0].Description != "" {
+		t.Errorf("Summaries = %+v, want be-1 without its description", jobs)
+	}
+	if text, err := s.Description("be-1"); err != nil || text != "Write Go." {
+		t.Errorf("Description = %q, %v", text, err)
+	}
+	if _, err := s.Description("nope"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Description(unknown) err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestStore_Delete(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{{ID: "big", Description: hugeDescription()}, {ID: "keep"}}); err != nil {
+		t.Fatal(err)
+	}
+	first, _ := s.ByID("big")
+	if err := s.SetStarred("big", "default", true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Delete("big"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.ByID("big"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted job still loads: %v", err)
+	}
+	for _, table := range []string{"job_profile_state", "job_description_overflow"} {
+		var n int
+		s.DB.QueryRow(`SELECT COUNT(*) FROM ` + table + ` WHERE job_id = 'big'`).Scan(&n)
+		if n != 0 {
+			t.Errorf("%d rows left in %s", n, table)
+		}
+	}
+	if err := s.Delete("big"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("second Delete err = %v, want sql.ErrNoRows", err)
+	}
+	if jobs, _ := s.All(); len(jobs) != 1 || jobs[0].ID != "keep" {
+		t.Errorf("other jobs touched: %v", jobs)
+	}
+
+	// A re-scrape brings the job back under its old short ID.
+	s.Save([]Job{{ID: "big"}})
+	if again, _ := s.ByID("big"); again.ShortID != first.ShortID {
+		t.Errorf("short ID %d after re-scrape, was %d", again.ShortID, first.ShortID)
+	}
+}
+
+func TestStore_CancelledContext(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{{ID: "kept"}}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	if err := s.SaveContext(ctx, []Job{{ID: "dropped"}}); !errors.Is(err, context.Canceled) {
+		t.Errorf("SaveContext = %v", err)
+	}
+	if _, err := s.AllContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("AllContext = %v", err)
+	}
+	if _, _, err := s.ForProfilePageContext(ctx, "default", Page{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("ForProfilePageContext = %v", err)
+	}
+	if err := s.DeleteContext(ctx, "kept"); !errors.Is(err, context.Canceled) {
+		t.Errorf("DeleteContext = %v", err)
+	}
+	if err := s.SetHiddenContext(ctx, "kept", "default", true); !errors.Is(err, context.Canceled) {
+		t.Errorf("SetHiddenContext = %v", err)
+	}
+	if err := s.SetVerdictContext(ctx, "kept", "default", VerdictNo); !errors.Is(err, context.Canceled) {
+		t.Errorf("SetVerdictContext = %v", err)
+	}
+	if jobs, _ := s.All(); len(jobs) != 1 || jobs[0].ID != "kept" {
+		t.Errorf("after cancelled writes: %v", jobs)
+	}
+}
+
+func TestStore_SetCVPath(t *testing.T) {
+	s := openTestStore(t)
+	if err := s.Save([]Job{{ID: "a", Title: "Go Engineer"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetCVPath("a", "/data/outputs/cv/a/cv.pdf"); err != nil {
+		t.Fatal(err)
+	}
+	// A re-scrape knows nothing of the CV.
+	if err := s.Save([]Job{{ID: "a", Title: "Go Engineer"}}); err != nil {
+		t.Fatal(err)
+	}
+	if j, _ := s.ByID("a"); j.CVPath != "/data/outputs/cv/a/cv.pdf" {
+		t.Errorf("cv path after re-scrape = %q", j.CVPath)
+	}
+	if err := s.SetCVPath("missing", "x.pdf"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("unknown job: %v", err)
+	}
+}
+
+// BenchmarkStore_Save5k saves 5,000 jobs a transaction each, in the
+// TUI's batches of 50, and in one transaction.
+func BenchmarkStore_Save5k(b *testing.B) {
+	jobs := make([]Job, 5000)
+	for i := range jobs {
+		jobs[i] = Job{ID: fmt.Sprintf("bench-%d", i), Title: "Go Engineer", Company: "Acme",
+			Description: "Write Go services.", Source: "bench", Score: i % 100}
+	}
+	for _, batch := range []int{1, 50, len(jobs)} {
+		b.Run(fmt.Sprintf("batch=%d", batch), func(b *testing.B) {
+			for b.Loop() {
+				b.StopTimer()
+				s, err := OpenStore(filepath.Join(b.TempDir(), "bench.db"))
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+				for lo := 0; lo < len(jobs); lo += batch {
+					if err := s.Save(jobs[lo:min(lo+batch, len(jobs))]); err != nil {
+						b.Fatal(err)
+					}
+				}
+				b.StopTimer()
+				s.Close()
+				b.StartTimer()
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TrapSeverity ranks how bad a trap is.
+type TrapSeverity string
+
+const (
+	TrapLow    TrapSeverity = "low"
+	TrapMedium TrapSeverity = "medium"
+	TrapHigh   TrapSeverity = "high"
+)
+
+// rank orders severities. An unknown one, such as a trap saved before
+// severities were recorded, ranks as high.
+func (s TrapSeverity) rank() int {
+	switch s {
+	case TrapLow:
+		return 1
+	case TrapMedium:
+		return 2
+	}
+	return 3
+}
+
+// AtLeast reports whether s is min or worse. An empty min is low.
+func (s TrapSeverity) AtLeast(min TrapSeverity) bool {
+	return min == "" || s.rank() >= min.rank()
+}
+
+// ParseTrapSeverity reads low, medium or high; "" is low.
+func ParseTrapSeverity(s string) (TrapSeverity, error) {
+	switch sev := TrapSeverity(strings.ToLower(strings.TrimSpace(s))); sev {
+	case "":
+		return TrapLow, nil
+	case TrapLow, TrapMedium, TrapHigh:
+		return sev, nil
+	}
+	return "", fmt.Errorf("trap severity %q is not low, medium or high", s)
+}
+
+// TrapMatch is a trap rule that fired on a job's description.
+type TrapMatch struct {
+	Name     string       `json:"name"`
+	Severity TrapSeverity `json:"severity"`
+	Match    string       `json:"match"` // the text that fired it
+}
+
+// String names the rule and its severity, or gives the matched text for
+// traps saved before rules were recorded.
+func (t TrapMatch) String() string {
+	if t.Name == "" {
+		return fmt.Sprintf("%q", t.Match)
+	}
+	return t.Name + " (" + string(t.Severity) + ")"
+}
+
+// TrapsAtLeast reports whether j tripped a trap of severity min or
+// worse. A job flagged without recorded matches counts as high.
+func (j Job) TrapsAtLeast(min TrapSeverity) bool {
+	if j.HasTraps && len(j.Traps) == 0 {
+		return true
+	}
+	for _, t := range j.Traps {
+		if t.Severity.AtLeast(min) {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeTraps stores matches as JSON.
+func encodeTraps(traps []TrapMatch) string {
+	if len(traps) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(traps)
+	return string(data)
+}
+
+// decodeTraps reads what encodeTraps stored. Rows written before traps
+// were structured hold the matched text, comma-separated; their rule
+// and severity are unknown.
+func decodeTraps(s string) []TrapMatch {
+	if s == "" {
+		return nil
+	}
+	var traps []TrapMatch
+	if strings.HasPrefix(s, "[") && json.Unmarshal([]byte(s), &traps) == nil {
+		return traps
+	}
+	for _, m := range strings.Split(s, ",") {
+		traps = append(traps, TrapMatch{Match: m})
+	}
+	return traps
+}
@@ -0,0 +1,60 @@
+package job
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExcludeTraps_BySeverity(t *testing.T) {
+	jobs := []Job{
+		{ID: "clean"},
+		{ID: "hype", HasTraps: true, Traps: []TrapMatch{{Name: "fast-paced", Severity: TrapLow}}},
+		{ID: "take-home", HasTraps: true, Traps: []TrapMatch{{Name: "unpaid-take-home", Severity: TrapMedium}}},
+		{ID: "trial", HasTraps: true, Traps: []TrapMatch{
+			{Name: "fast-paced", Severity: TrapLow},
+			{Name: "unpaid-trial", Severity: TrapHigh},
+		}},
+		{ID: "legacy", HasTraps: true},
+	}
+	ids := func(jobs []Job) []string {
+		var out []string
+		for _, j := range jobs {
+			out = append(out, j.ID)
+		}
+		return out
+	}
+	for min, want := range map[TrapSeverity][]string{
+		"":         {"clean"},
+		TrapLow:    {"clean"},
+		TrapMedium: {"clean", "hype"},
+		TrapHigh:   {"clean", "hype", "take-home"},
+	} {
+		if got := ids(ExcludeTraps(min)(jobs)); !reflect.DeepEqual(got, want) {
+			t.Errorf("ExcludeTraps(%q) kept %v, want %v", min, got, want)
+		}
+	}
+}
+
+func TestTraps_EncodeDecode(t *testing.T) {
+	traps := []TrapMatch{{Name: "equity-only", Severity: TrapHigh, Match: "equity, only"}}
+	if got := decodeTraps(encodeTraps(traps)); !reflect.DeepEqual(got, traps) {
+		t.Errorf("round trip = %+v", got)
+	}
+	if got := encodeTraps(nil); got != "" {
+		t.Errorf("no traps encoded as %q", got)
+	}
+
+	// Rows from before rules were recorded hold the matched text.
+	got := decodeTraps("subject line,ignore previous")
+	want := []TrapMatch{{Match: "subject line"}, {Match: "ignore previous"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("legacy traps = %+v", got)
+	}
+	if !(Job{HasTraps: true, Traps: got}).TrapsAtLeast(TrapHigh) {
+		t.Error("a legacy trap should count as high")
+	}
+
+	if _, err := ParseTrapSeverity("severe"); err == nil {
+		t.Error("ParseTrapSeverity accepted severe")
+	}
+}
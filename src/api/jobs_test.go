@@ -0,0 +1,287 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+func getJobs(h *Handler, query string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ListJobs(rec, httptest.NewRequest(http.MethodGet, "/jobs?"+query, nil))
+	return rec
+}
+
+func TestListJobs_Paginates(t *testing.T) {
+	h := newScrapeHandler(t)
+	var jobs []job.Job
+	for i := range 250 {
+		jobs = append(jobs, job.Job{ID: fmt.Sprintf("j%03d", i), Title: "Go", Score: i % 100})
+	}
+	if err := h.store.Save(jobs); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := getJobs(h, "limit=50&offset=100&sort=score&order=desc")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	var page JobsPage
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Jobs) != 50 || page.Total != 250 || page.NextOffset == nil || *page.NextOffset != 150 {
+		t.Fatalf("got %d jobs of %d, next %v", len(page.Jobs), page.Total, page.NextOffset)
+	}
+	// Scores 50-99 appear twice and 0-49 three times, so the 101st job
+	// by score desc, ties by ID desc, is the last 49.
+	if first := page.Jobs[0]; first.Score != 49 || first.ID != "j249" {
+		t.Errorf("window starts at score %d (%s)", first.Score, first.ID)
+	}
+	for i := 1; i < len(page.Jobs); i++ {
+		if page.Jobs[i].Score > page.Jobs[i-1].Score {
+			t.Fatalf("not sorted at %d", i)
+		}
+	}
+
+	page = JobsPage{}
+	json.NewDecoder(getJobs(h, "limit=100&page=3").Body).Decode(&page)
+	if page.Offset != 200 || len(page.Jobs) != 50 || page.NextOffset != nil {
+		t.Errorf("last page: offset %d, %d jobs, next %v", page.Offset, len(page.Jobs), page.NextOffset)
+	}
+}
+
+func TestListJobs_StopsWithRequest(t *testing.T) {
+	h := newScrapeHandler(t)
+	if err := h.store.Save([]job.Job{{ID: "j1", Title: "Go"}}); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel() // the client went away
+	rec := httptest.NewRecorder()
+	h.ListJobs(rec, httptest.NewRequest(http.MethodGet, "/jobs", nil).WithContext(ctx))
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "context canceled") {
+		t.Errorf("listing for a cancelled request: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestListJobs_BadParams(t *testing.T) {
+	h := newScrapeHandler(t)
+	for _, q := range []string{"sort=salary", "order=sideways", "limit=0", "limit=5000", "offset=-1", "limit=ten", "page=0", "page=2&offset=10"} {
+		rec := getJobs(h, q)
+		var body map[string]string
+		json.NewDecoder(rec.Body).Decode(&body)
+		if rec.Code != http.StatusBadRequest || body["error"] == "" {
+			t.Errorf("%s: status %d, body %v; want a 400 with an error", q, rec.Code, body)
+		}
+	}
+	rec := getJobs(h, "sort=salary")
+	if !strings.Contains(rec.Body.String(), "score, posted_date, company, title") {
+		t.Errorf("error should list the sort fields: %s", rec.Body)
+	}
+}
+
+func TestListJobs_EmptyIsArray(t *testing.T) {
+	rec := getJobs(newScrapeHandler(t), "")
+	if !strings.Contains(rec.Body.String(), `"jobs":[]`) || !strings.Contains(rec.Body.String(), `"next_offset":null`) {
+		t.Errorf("body = %s", rec.Body)
+	}
+}
+
+func jobRequest(h *Handler, method, id string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.Job(rec, httptest.NewRequest(method, "/jobs/"+id, nil))
+	return rec
+}
+
+func TestJob_Get(t *testing.T) {
+	h := newScrapeHandler(t)
+	long := "Go and Postgres. " + strings.Repeat("More about us. ", 5000)
+	if err := h.store.Save([]job.Job{{ID: "hn-1", Title: "Go", Description: long,
+		Email: "jobs@acme.test", HasTraps: true, Traps: []job.TrapMatch{{Name: "prompt-injection", Severity: job.TrapHigh, Match: "subject line"}}, Applied: true}}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := jobRequest(h, http.MethodGet, "hn-1")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	var j job.Job
+	if err := json.NewDecoder(rec.Body).Decode(&j); err != nil {
+		t.Fatal(err)
+	}
+	if j.Description != long || j.Truncated {
+		t.Errorf("description: %d bytes, truncated %v; want the whole %d", len(j.Description), j.Truncated, len(long))
+	}
+	if j.Email != "jobs@acme.test" || len(j.Traps) != 1 || !j.Applied {
+		t.Errorf("job = %+v", j)
+	}
+
+	rec = jobRequest(h, http.MethodGet, "nope")
+	var body map[string]string
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusNotFound || body["error"] == "" {
+		t.Errorf("unknown id: status %d, body %v", rec.Code, body)
+	}
+}
+
+func TestJob_Delete(t *testing.T) {
+	h := newScrapeHandler(t)
+	if err := h.store.Save([]job.Job{{ID: "bad"}, {ID: "good"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec := jobRequest(h, http.MethodDelete, "bad"); rec.Code != http.StatusNoContent {
+		t.Fatalf("delete: status %d: %s", rec.Code, rec.Body)
+	}
+	if rec := jobRequest(h, http.MethodGet, "bad"); rec.Code != http.StatusNotFound {
+		t.Errorf("deleted job: status %d", rec.Code)
+	}
+	if rec := jobRequest(h, http.MethodDelete, "bad"); rec.Code != http.StatusNotFound {
+		t.Errorf("second delete: status %d", rec.Code)
+	}
+	if rec := jobRequest(h, http.MethodGet, "good"); rec.Code != http.StatusOK {
+		t.Errorf("other job: status %d", rec.Code)
+	}
+	if rec := jobRequest(h, http.MethodPut, "good"); rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") == "" {
+		t.Errorf("PUT: status %d, Allow %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
+
+func listIDs(t *testing.T, h *Handler, query string) []string {
+	t.Helper()
+	rec := getJobs(h, query)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("%s: status %d: %s", query, rec.Code, rec.Body)
+	}
+	var page JobsPage
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != len(page.Jobs) {
+		t.Errorf("%s: total %d for %d jobs", query, page.Total, len(page.Jobs))
+	}
+	ids := make([]string, len(page.Jobs))
+	for i, j := range page.Jobs {
+		ids[i] = j.ID
+	}
+	return ids
+}
+
+func TestListJobs_Filters(t *testing.T) {
+	h := newScrapeHandler(t)
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC) }
+	if err := h.store.Save([]job.Job{
+		{ID: "rust-remote", Title: "Rust engineer", Location: "Remote", Email: "a@x.test", Score: 90, PostedDate: day(20)},
+		{ID: "rust-berlin", Title: "Rust engineer", Location: "Berlin", Score: 60, PostedDate: day(10), Applied: true},
+		{ID: "go-remote", Title: "Go engineer", Location: "Remote (EU)", Email: "b@x.test", Score: 75, PostedDate: day(5)},
+		{ID: "go-trap", Title: "Go engineer", Location: "Remote", Score: 80, HasTraps: true, PostedDate: day(25)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"rust-remote", "go-trap", "go-remote", "rust-berlin"}},
+		{"keywords=rust,go&min_score=70", []string{"rust-remote", "go-trap", "go-remote"}},
+		{"location=remote&has_email=true", []string{"rust-remote", "go-remote"}},
+		{"posted_after=2024-01-08&exclude_traps=true", []string{"rust-remote", "rust-berlin"}},
+		{"posted_after=2024-01-08&trap_severity=high", []string{"rust-remote", "rust-berlin"}},
+		{"posted_after=2024-01-20T13:00:00Z", []string{"go-trap"}},
+		{"hide_applied=true", []string{"rust-remote", "go-trap", "go-remote"}},
+		{"keywords=haskell", []string{}},
+		// alice's profile wants rust; explicit params replace or add to it.
+		{"profile_id=alice", []string{"rust-remote", "rust-berlin"}},
+		{"profile_id=alice&min_score=70", []string{"rust-remote"}},
+		{"profile_id=alice&keywords=go", []string{"go-trap", "go-remote"}},
+		{"profile_id=alice&keywords=go&sort=posted_date&order=asc", []string{"go-remote", "go-trap"}},
+	}
+	for _, tt := range tests {
+		if got := listIDs(t, h, tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	var page JobsPage
+	json.NewDecoder(getJobs(h, "keywords=engineer&limit=2&offset=1").Body).Decode(&page)
+	if page.Total != 4 || len(page.Jobs) != 2 || page.Jobs[0].ID != "go-trap" || page.NextOffset == nil || *page.NextOffset != 3 {
+		t.Errorf("filtered window: %d of %d, next %v", len(page.Jobs), page.Total, page.NextOffset)
+	}
+	rec := getJobs(h, "keywords=haskell")
+	if !strings.Contains(rec.Body.String(), `"jobs":[]`) {
+		t.Errorf("empty filtered result = %s", rec.Body)
+	}
+}
+
+func TestListJobs_BadFilters(t *testing.T) {
+	h := newScrapeHandler(t)
+	for _, q := range []string{"posted_after=yesterday", "posted_after=2024-13-01", "posted_after=01/02/2024",
+		"min_score=high", "min_score=101", "has_email=maybe"} {
+		rec := getJobs(h, q)
+		var body map[string]string
+		json.NewDecoder(rec.Body).Decode(&body)
+		if rec.Code != http.StatusBadRequest || body["error"] == "" {
+			t.Errorf("%s: status %d, body %v; want a 400 with an error", q, rec.Code, body)
+		}
+	}
+	if rec := getJobs(h, "profile_id=nobody"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown profile_id: status %d", rec.Code)
+	}
+}
+
+func TestListJobs_CompareProfiles(t *testing.T) {
+	h := newScrapeHandler(t)
+	if err := h.profileStore.Save(profile.Profile{ID: "bob", Name: "Bob", Keywords: []string{"go"}, MaxScore: 100,
+		PreferRemote: true, ScoringWeights: profile.DefaultScoringWeights()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.store.Save([]job.Job{
+		{ID: "rust-berlin", Title: "Rust engineer", Location: "Berlin", Score: 60},
+		{ID: "go-remote", Title: "Go engineer", Location: "Remote", Score: 75},
+		{ID: "rust-go", Title: "Rust and Go engineer", Location: "Berlin", Score: 90},
+		{ID: "java", Title: "Java engineer", Score: 95},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var page ComparedPage
+	rec := getJobs(h, "profiles=alice,bob&by=bob")
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("status %d, %v", rec.Code, err)
+	}
+	var got []string
+	for _, c := range page.Jobs {
+		got = append(got, fmt.Sprintf("%s %s %d/%d", c.ID, strings.Join(c.Matched(), "+"), c.Profiles[0].Score, c.Profiles[1].Score))
+	}
+	// alice scores everything 50, having no preferences; bob prefers
+	// remote jobs.
+	want := []string{"go-remote bob 50/100", "rust-go alice+bob 50/0", "rust-berlin alice 50/0"}
+	if !reflect.DeepEqual(got, want) || page.Total != 3 {
+		t.Errorf("compared %q of %d, want %q", got, page.Total, want)
+	}
+	if ids, _ := h.store.ForProfile("alice"); ids[0].ID != "java" || ids[0].Score != 95 {
+		t.Errorf("stored scores changed: %+v", ids[0])
+	}
+
+	for q, code := range map[string]int{
+		"profiles=alice,nobody":  http.StatusNotFound,
+		"profiles=alice&by=bob":  http.StatusBadRequest,
+		"profiles=,":             http.StatusBadRequest,
+		"profiles=alice&limit=0": http.StatusBadRequest,
+	} {
+		if rec := getJobs(h, q); rec.Code != code {
+			t.Errorf("%s: status %d, want %d", q, rec.Code, code)
+		}
+	}
+}
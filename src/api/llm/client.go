@@ -1,13 +1,10 @@
 package llm
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
-	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -17,101 +14,159 @@ var (
 	EnvLLMModel = "SPRAYER_LLM_MODEL"
 )
 
+// healthTTL is how long a health check's result is trusted, so callers
+// asking Available before every completion don't probe each time.
+const healthTTL = time.Minute
+
+// healthTimeout bounds a health check; a provider slower than this to
+// list its models would not be pleasant to generate with anyway.
+const healthTimeout = 3 * time.Second
+
+// Client completes prompts with the configured Provider, recording each
+// completion when given a usage log.
 type Client struct {
-	baseURL string
-	apiKey  string
-	model   string
-	http    *http.Client
+	provider Provider
+	usage    *Store
+	prices   Prices
+
+	mu        sync.Mutex
+	checked   time.Time
+	healthErr error
 }
 
+// NewClient returns a client for the provider the environment configures
+// (see ProviderFromEnv).
 func NewClient() *Client {
-	baseURL := os.Getenv(EnvLLMURL)
-	if baseURL == "" {
-		baseURL = "https://api.openai.com/v1"
-	}
-	model := os.Getenv(EnvLLMModel)
-	if model == "" {
-		model = "kimi-k2"
-	}
-	return &Client{
-		baseURL: strings.TrimRight(baseURL, "/"),
-		apiKey:  os.Getenv(EnvLLMKey),
-		model:   model,
-		http:    &http.Client{Timeout: 60 * time.Second},
+	p, err := ProviderFromEnv()
+	if err != nil {
+		p = misconfigured{err}
 	}
+	return NewClientFor(p)
 }
 
-func (c *Client) Available() bool {
-	return c.apiKey != ""
+// NewClientFor returns a client completing with p.
+func NewClientFor(p Provider) *Client {
+	return &Client{provider: p}
 }
 
-type chatRequest struct {
-	Model    string        `json:"model"`
-	Messages []chatMessage `json:"messages"`
+// Provider is the backend completions go to.
+func (c *Client) Provider() Provider { return c.provider }
+
+// BaseURL is the API endpoint requests go to.
+func (c *Client) BaseURL() string { return c.provider.BaseURL() }
+
+// Available reports whether the provider passed its health check.
+func (c *Client) Available() bool {
+	return c.Check(context.Background()) == nil
 }
 
-type chatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+// Check runs the provider's health check, or returns the result of one
+// made within the last minute. The error names the provider and model.
+// A check cut short by cancelling ctx says nothing of the provider and is
+// not remembered.
+func (c *Client) Check(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checked.IsZero() && time.Since(c.checked) < healthTTL {
+		return c.healthErr
+	}
+	hctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+	err := c.wrap(c.provider.Health(hctx))
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	c.healthErr, c.checked = err, time.Now()
+	return c.healthErr
 }
 
-type chatResponse struct {
-	Choices []struct {
-		Message chatMessage `json:"message"`
-	} `json:"choices"`
-	Error *struct {
-		Message string `json:"message"`
-	} `json:"error,omitempty"`
+// RecordUsage has each completion logged to s, priced from prices.
+func (c *Client) RecordUsage(s *Store, prices Prices) {
+	c.usage, c.prices = s, prices
 }
 
+// Complete returns the model's reply to the system and user messages.
 func (c *Client) Complete(system, user string) (string, error) {
-	if !c.Available() {
-		return "", fmt.Errorf("LLM not configured: set SPRAYER_LLM_KEY")
-	}
-
-	req := chatRequest{
-		Model: c.model,
-		Messages: []chatMessage{
-			{Role: "system", Content: system},
-			{Role: "user", Content: user},
-		},
-	}
+	return c.CompleteContext(context.Background(), system, user)
+}
 
-	body, err := json.Marshal(req)
+// CompleteContext is Complete bounded by ctx, recorded under the purpose
+// For tagged it with.
+func (c *Client) CompleteContext(ctx context.Context, system, user string) (string, error) {
+	start := time.Now()
+	out, err := c.provider.Complete(ctx, system, user)
 	if err != nil {
-		return "", err
+		return "", c.wrap(err)
 	}
+	c.record(ctx, system+user, out, start)
+	return out.Text, nil
+}
 
-	httpReq, err := http.NewRequest("POST", c.baseURL+"/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return "", err
+// CompleteStream is CompleteContext sending the reply on chunks as it is
+// written, when the provider streams; otherwise the whole reply is sent
+// once it arrives. The text returned is what Complete would return.
+// chunks is not closed.
+func (c *Client) CompleteStream(ctx context.Context, system, user string, chunks chan<- string) (string, error) {
+	s, ok := c.provider.(Streamer)
+	if !ok {
+		out, err := c.CompleteContext(ctx, system, user)
+		if err != nil {
+			return "", err
+		}
+		return out, send(ctx, chunks, out)
 	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
-
-	resp, err := c.http.Do(httpReq)
+	start := time.Now()
+	out, err := s.Stream(ctx, system, user, chunks)
 	if err != nil {
-		return "", fmt.Errorf("LLM request failed: %w", err)
+		return "", c.wrap(err)
 	}
-	defer resp.Body.Close()
+	c.record(ctx, system+user, out, start)
+	return strings.TrimSpace(out.Text), nil
+}
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
+// record logs a completion of prompt begun at start. Tokens the API did
+// not report are estimated from the text. A log that cannot be written
+// does not fail the completion.
+func (c *Client) record(ctx context.Context, prompt string, out Completion, start time.Time) {
+	if c.usage == nil {
+		return
 	}
-
-	var result chatResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return "", fmt.Errorf("LLM response parse error: %w", err)
+	call := callOf(ctx)
+	u := Usage{
+		At:               start,
+		Purpose:          call.purpose,
+		JobID:            call.jobID,
+		Provider:         c.provider.Name(),
+		Model:            c.provider.Model(),
+		PromptTokens:     out.PromptTokens,
+		CompletionTokens: out.CompletionTokens,
+		Latency:          time.Since(start),
 	}
-
-	if result.Error != nil {
-		return "", fmt.Errorf("LLM error: %s", result.Error.Message)
+	if u.PromptTokens == 0 && u.CompletionTokens == 0 {
+		u.PromptTokens, u.CompletionTokens = EstimateTokens(prompt), EstimateTokens(out.Text)
+		u.Estimated = true
 	}
+	u.Cost = c.prices.Cost(u.Model, u.PromptTokens, u.CompletionTokens)
+	_ = c.usage.Record(u)
+}
 
-	if len(result.Choices) == 0 {
-		return "", fmt.Errorf("LLM returned no choices")
+// send delivers s on chunks unless ctx ends first.
+func send(ctx context.Context, chunks chan<- string, s string) error {
+	select {
+	case chunks <- s:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
 
-	return strings.TrimSpace(result.Choices[0].Message.Content), nil
+// wrap prefixes err with the provider and model it came from.
+func (c *Client) wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := c.provider.(misconfigured); ok {
+		return fmt.Errorf("LLM: %w", err)
+	}
+	return fmt.Errorf("LLM %s (%s): %w", c.provider.Name(), c.provider.Model(), err)
 }
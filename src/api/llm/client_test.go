@@ -0,0 +1,305 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpenAI_Complete(t *testing.T) {
+	var auth string
+	var got chatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/models":
+			if r.Header.Get("Authorization") != "Bearer sk-good" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+			w.Write([]byte(`{"data":[{"id":"gpt-4o"}]}`))
+		case "/v1/chat/completions":
+			auth = r.Header.Get("Authorization")
+			json.NewDecoder(r.Body).Decode(&got)
+			w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":" Hello Acme. "}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv(EnvLLMProvider, "openai")
+	t.Setenv(EnvLLMBaseURL, srv.URL+"/v1/")
+	t.Setenv(EnvLLMModel, "gpt-4o")
+	t.Setenv(EnvLLMKey, "sk-good")
+
+	c := NewClient()
+	if !c.Available() {
+		t.Fatalf("Available = false: %v", c.Check(t.Context()))
+	}
+	out, err := c.Complete("be brief", "greet Acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "Hello Acme." {
+		t.Errorf("Complete = %q", out)
+	}
+	if auth != "Bearer sk-good" || got.Model != "gpt-4o" || len(got.Messages) != 2 ||
+		got.Messages[0] != (chatMessage{"system", "be brief"}) || got.Messages[1] != (chatMessage{"user", "greet Acme"}) {
+		t.Errorf("request = %s %+v", auth, got)
+	}
+
+	t.Setenv(EnvLLMKey, "sk-bad")
+	err = NewClient().Check(t.Context())
+	if err == nil || !strings.Contains(err.Error(), "openai (gpt-4o)") {
+		t.Errorf("Check with a bad key = %v, want it to name the provider and model", err)
+	}
+}
+
+func TestOpenAI_ErrorNamesProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"message":"rate limit reached"}}`))
+	}))
+	defer srv.Close()
+	t.Setenv(EnvLLMProvider, "")
+	t.Setenv(EnvLLMURL, srv.URL)
+	t.Setenv(EnvLLMModel, "")
+	t.Setenv(EnvLLMKey, "sk-test")
+
+	_, err := NewClient().Complete("s", "u")
+	if err == nil || err.Error() != "LLM hosted (kimi-k2): rate limit reached" {
+		t.Errorf("Complete = %v", err)
+	}
+
+	t.Setenv(EnvLLMKey, "")
+	c := NewClient()
+	if c.Available() {
+		t.Error("hosted provider without a key is available")
+	}
+	if _, err := c.Complete("s", "u"); err == nil || !strings.Contains(err.Error(), EnvLLMKey) {
+		t.Errorf("Complete without a key = %v", err)
+	}
+}
+
+func TestOllama_Complete(t *testing.T) {
+	var got ollamaChatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			w.Write([]byte(`{"models":[{"name":"llama3.1:latest"},{"name":"qwen2.5:7b"}]}`))
+		case "/api/chat":
+			if r.Header.Get("Authorization") != "" {
+				t.Error("Ollama request carried an Authorization header")
+			}
+			json.NewDecoder(r.Body).Decode(&got)
+			w.Write([]byte(`{"model":"llama3.1","message":{"role":"assistant","content":"Dear Acme,\n"},"done":true}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv(EnvLLMProvider, "ollama")
+	t.Setenv(EnvLLMBaseURL, srv.URL)
+	t.Setenv(EnvLLMModel, "")
+	t.Setenv(EnvLLMKey, "")
+
+	c := NewClient()
+	if !c.Available() {
+		t.Fatalf("Available = false: %v", c.Check(t.Context()))
+	}
+	out, err := c.Complete("write a letter", "to Acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "Dear Acme," {
+		t.Errorf("Complete = %q", out)
+	}
+	if got.Model != DefaultOllamaModel || got.Stream || len(got.Messages) != 2 || got.Messages[1].Content != "to Acme" {
+		t.Errorf("request = %+v", got)
+	}
+
+	t.Setenv(EnvLLMModel, "mistral")
+	err = NewClient().Check(t.Context())
+	if err == nil || !strings.Contains(err.Error(), "ollama (mistral)") || !strings.Contains(err.Error(), "ollama pull mistral") {
+		t.Errorf("Check for a missing model = %v", err)
+	}
+}
+
+func TestOllama_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+	t.Setenv(EnvLLMProvider, "ollama")
+	t.Setenv(EnvLLMBaseURL, url)
+	t.Setenv(EnvLLMModel, "llama3.1")
+
+	c := NewClient()
+	if c.Available() {
+		t.Error("unreachable Ollama is available")
+	}
+	if _, err := c.Complete("s", "u"); err == nil || !strings.HasPrefix(err.Error(), "LLM ollama (llama3.1): ") {
+		t.Errorf("Complete = %v", err)
+	}
+}
+
+func TestProviderFromEnv(t *testing.T) {
+	t.Setenv(EnvLLMURL, "")
+	t.Setenv(EnvLLMBaseURL, "")
+	t.Setenv(EnvLLMModel, "")
+
+	t.Setenv(EnvLLMProvider, "")
+	if p, err := ProviderFromEnv(); err != nil || p.Name() != ProviderHosted || p.BaseURL() != DefaultHostedURL || p.Model() != DefaultHostedModel {
+		t.Errorf("default provider = %+v, %v", p, err)
+	}
+	t.Setenv(EnvLLMProvider, "Ollama")
+	if p, err := ProviderFromEnv(); err != nil || p.Name() != ProviderOllama || p.BaseURL() != DefaultOllamaURL {
+		t.Errorf("ollama provider = %+v, %v", p, err)
+	}
+	t.Setenv(EnvLLMProvider, "openai")
+	if _, err := ProviderFromEnv(); err == nil {
+		t.Error("openai provider without a base URL was accepted")
+	}
+	t.Setenv(EnvLLMProvider, "lmstudio")
+	if _, err := ProviderFromEnv(); err == nil {
+		t.Error("unknown provider was accepted")
+	}
+	c := NewClient()
+	if err := c.Check(t.Context()); err == nil || !strings.Contains(err.Error(), "lmstudio") {
+		t.Errorf("misconfigured client Check = %v", err)
+	}
+}
+
+// collect runs CompleteStream, returning the chunks it sent and its result.
+func collect(t *testing.T, c *Client) ([]string, string, error) {
+	t.Helper()
+	chunks := make(chan string)
+	var got []string
+	done := make(chan struct{})
+	go func() {
+		for s := range chunks {
+			got = append(got, s)
+		}
+		close(done)
+	}()
+	out, err := c.CompleteStream(t.Context(), "s", "u", chunks)
+	close(chunks)
+	<-done
+	return got, out, err
+}
+
+func TestOpenAI_Stream(t *testing.T) {
+	var streamed bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		streamed = req.Stream
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, part := range []string{`{"choices":[{"delta":{"role":"assistant"}}]}`, `{"choices":[{"delta":{"content":"Dear "}}]}`, `{"choices":[{"delta":{"content":"Acme,\n"}}]}`, `[DONE]`} {
+			fmt.Fprintf(w, "data: %s\n\n", part)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClientFor(&OpenAI{Label: ProviderOpenAI, URL: srv.URL, ModelName: "gpt-4o"})
+	got, out, err := collect(t, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !streamed || strings.Join(got, "|") != "Dear |Acme,\n" || out != "Dear Acme," {
+		t.Errorf("streamed=%v chunks=%q out=%q", streamed, got, out)
+	}
+}
+
+func TestOpenAI_StreamFallsBackToWholeReply(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":" Dear Acme, "}}]}`))
+	}))
+	defer srv.Close()
+
+	c := NewClientFor(&OpenAI{Label: ProviderHosted, URL: srv.URL, APIKey: "k", ModelName: "kimi-k2"})
+	got, out, err := collect(t, c)
+	if err != nil || len(got) != 1 || out != "Dear Acme," {
+		t.Errorf("chunks=%q out=%q err=%v", got, out, err)
+	}
+}
+
+func TestOllama_Stream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ollamaChatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.Stream {
+			t.Error("stream not requested")
+		}
+		for _, line := range []string{
+			`{"message":{"role":"assistant","content":"Dear"},"done":false}`,
+			`{"message":{"role":"assistant","content":" Acme"},"done":false}`,
+			`{"message":{"role":"assistant","content":""},"done":true}`,
+		} {
+			fmt.Fprintln(w, line)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer srv.Close()
+
+	got, out, err := collect(t, NewClientFor(&Ollama{URL: srv.URL, ModelName: "llama3.1"}))
+	if err != nil || strings.Join(got, "|") != "Dear| Acme" || out != "Dear Acme" {
+		t.Errorf("chunks=%q out=%q err=%v", got, out, err)
+	}
+}
+
+func TestCompleteStream_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"message":{"content":"Dear"},"done":false}`)
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	chunks := make(chan string)
+	errc := make(chan error, 1)
+	go func() {
+		_, err := NewClientFor(&Ollama{URL: srv.URL, ModelName: "llama3.1"}).CompleteStream(ctx, "s", "u", chunks)
+		errc <- err
+	}()
+	if s := <-chunks; s != "Dear" {
+		t.Errorf("first chunk = %q", s)
+	}
+	cancel()
+	select {
+	case err := <-errc:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("cancelled stream = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelling did not end the request")
+	}
+}
+
+func TestCheck_CancelledIsNotRemembered(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"models":[{"name":"llama3.1:latest"}]}`))
+	}))
+	defer srv.Close()
+	c := NewClientFor(&Ollama{URL: srv.URL, ModelName: "llama3.1"})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	if err := c.Check(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Check with a cancelled context = %v", err)
+	}
+	if err := c.Check(t.Context()); err != nil {
+		t.Errorf("Check after a cancelled one = %v, want the provider checked again", err)
+	}
+}
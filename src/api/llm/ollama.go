@@ -0,0 +1,167 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"sprayer/src/api/offline"
+)
+
+// Ollama speaks the native API of a local Ollama server.
+type Ollama struct {
+	URL       string
+	ModelName string
+	HTTP      *http.Client
+}
+
+func (*Ollama) Name() string      { return ProviderOllama }
+func (p *Ollama) Model() string   { return p.ModelName }
+func (p *Ollama) BaseURL() string { return strings.TrimRight(p.URL, "/") }
+
+type ollamaChatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+}
+
+type ollamaChatResponse struct {
+	Message         chatMessage `json:"message"`
+	Done            bool        `json:"done"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+	EvalCount       int         `json:"eval_count"`
+	Error           string      `json:"error"`
+}
+
+func (p *Ollama) Complete(ctx context.Context, system, user string) (Completion, error) {
+	req, err := p.chat(ctx, system, user, false)
+	if err != nil {
+		return Completion{}, err
+	}
+	respBody, status, err := do(p.HTTP, req)
+	if err != nil {
+		return Completion{}, err
+	}
+	var result ollamaChatResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return Completion{}, fmt.Errorf("response parse error (HTTP %d): %w", status, err)
+	}
+	if result.Error != "" {
+		return Completion{}, fmt.Errorf("%s", result.Error)
+	}
+	return Completion{
+		Text:             strings.TrimSpace(result.Message.Content),
+		PromptTokens:     result.PromptEvalCount,
+		CompletionTokens: result.EvalCount,
+	}, nil
+}
+
+// Stream reads the reply as Ollama writes it, one JSON object a line.
+// The last one carries the token counts.
+func (p *Ollama) Stream(ctx context.Context, system, user string, chunks chan<- string) (Completion, error) {
+	req, err := p.chat(ctx, system, user, true)
+	if err != nil {
+		return Completion{}, err
+	}
+	c := p.HTTP
+	if c == nil {
+		c = http.DefaultClient
+	}
+	// ctx, not the client's timeout, bounds a reply still being written.
+	resp, err := (&http.Client{Transport: c.Transport}).Do(req)
+	if err != nil {
+		return Completion{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out Completion
+	var full strings.Builder
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var part ollamaChatResponse
+		if err := dec.Decode(&part); err == io.EOF {
+			break
+		} else if err != nil {
+			return Completion{}, fmt.Errorf("stream parse error (HTTP %d): %w", resp.StatusCode, err)
+		}
+		if part.Error != "" {
+			return Completion{}, fmt.Errorf("%s", part.Error)
+		}
+		if text := part.Message.Content; text != "" {
+			full.WriteString(text)
+			if err := send(ctx, chunks, text); err != nil {
+				return Completion{}, err
+			}
+		}
+		if part.Done {
+			out.PromptTokens, out.CompletionTokens = part.PromptEvalCount, part.EvalCount
+			break
+		}
+	}
+	out.Text = full.String()
+	return out, nil
+}
+
+// chat builds a chat request.
+func (p *Ollama) chat(ctx context.Context, system, user string, stream bool) (*http.Request, error) {
+	body, err := json.Marshal(ollamaChatRequest{
+		Model: p.ModelName,
+		Messages: []chatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		},
+		Stream: stream,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p.request(ctx, http.MethodPost, "/api/chat", bytes.NewReader(body))
+}
+
+// Health lists the pulled models and checks that the configured one is
+// among them, since Ollama would otherwise only fail at Complete.
+func (p *Ollama) Health(ctx context.Context) error {
+	req, err := p.request(ctx, http.MethodGet, "/api/tags", nil)
+	if err != nil {
+		return err
+	}
+	respBody, status, err := do(p.HTTP, req)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("list models: HTTP %d", status)
+	}
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(respBody, &tags); err != nil {
+		return fmt.Errorf("list models: %w", err)
+	}
+	for _, m := range tags.Models {
+		if m.Name == p.ModelName || strings.TrimSuffix(m.Name, ":latest") == p.ModelName {
+			return nil
+		}
+	}
+	return fmt.Errorf("model not pulled (run: ollama pull %s)", p.ModelName)
+}
+
+func (p *Ollama) request(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, p.BaseURL()+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := offline.CheckHost("LLM", req.URL.Host); err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
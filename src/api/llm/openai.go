@@ -0,0 +1,251 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"sprayer/src/api/offline"
+)
+
+// OpenAI speaks the OpenAI chat completions API, which the hosted default
+// and most self-hosted servers (vLLM, llama.cpp, LM Studio) serve.
+type OpenAI struct {
+	Label     string
+	URL       string
+	APIKey    string
+	ModelName string
+	// RequireKey refuses to send requests without an API key, as hosted
+	// APIs would only reject them.
+	RequireKey bool
+	HTTP       *http.Client
+}
+
+func (p *OpenAI) Name() string    { return p.Label }
+func (p *OpenAI) Model() string   { return p.ModelName }
+func (p *OpenAI) BaseURL() string { return strings.TrimRight(p.URL, "/") }
+
+type chatRequest struct {
+	Model         string         `json:"model"`
+	Messages      []chatMessage  `json:"messages"`
+	Stream        bool           `json:"stream,omitempty"`
+	StreamOptions *streamOptions `json:"stream_options,omitempty"`
+}
+
+// streamOptions asks a streaming server to end with the usage totals.
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatUsage is the token count servers report with a completion.
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Usage *chatUsage `json:"usage,omitempty"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// chatStreamChunk is one server-sent event of a streamed completion.
+type chatStreamChunk struct {
+	Choices []struct {
+		Delta chatMessage `json:"delta"`
+	} `json:"choices"`
+	Usage *chatUsage `json:"usage,omitempty"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (p *OpenAI) Complete(ctx context.Context, system, user string) (Completion, error) {
+	req, err := p.chat(ctx, system, user, false)
+	if err != nil {
+		return Completion{}, err
+	}
+	respBody, status, err := do(p.HTTP, req)
+	if err != nil {
+		return Completion{}, err
+	}
+	return parseChat(respBody, status)
+}
+
+// Stream asks for server-sent events and sends each delta as it comes.
+// A server that answers with a plain completion instead has it sent
+// whole.
+func (p *OpenAI) Stream(ctx context.Context, system, user string, chunks chan<- string) (Completion, error) {
+	req, err := p.chat(ctx, system, user, true)
+	if err != nil {
+		return Completion{}, err
+	}
+	c := p.HTTP
+	if c == nil {
+		c = http.DefaultClient
+	}
+	// The client's timeout covers reading the body, which a long reply
+	// streams for longer than; ctx bounds it instead.
+	resp, err := (&http.Client{Transport: c.Transport}).Do(req)
+	if err != nil {
+		return Completion{}, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return Completion{}, err
+		}
+		out, err := parseChat(respBody, resp.StatusCode)
+		if err != nil {
+			return Completion{}, err
+		}
+		return out, send(ctx, chunks, out.Text)
+	}
+
+	var out Completion
+	var full strings.Builder
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		data, ok := strings.CutPrefix(sc.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+		var chunk chatStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return Completion{}, fmt.Errorf("stream parse error: %w", err)
+		}
+		if chunk.Error != nil {
+			return Completion{}, fmt.Errorf("%s", chunk.Error.Message)
+		}
+		if u := chunk.Usage; u != nil {
+			out.PromptTokens, out.CompletionTokens = u.PromptTokens, u.CompletionTokens
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		text := chunk.Choices[0].Delta.Content
+		full.WriteString(text)
+		if err := send(ctx, chunks, text); err != nil {
+			return Completion{}, err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return Completion{}, fmt.Errorf("read stream: %w", err)
+	}
+	out.Text = full.String()
+	return out, nil
+}
+
+// chat builds a chat completion request.
+func (p *OpenAI) chat(ctx context.Context, system, user string, stream bool) (*http.Request, error) {
+	cr := chatRequest{
+		Model: p.ModelName,
+		Messages: []chatMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		},
+	}
+	if stream {
+		cr.Stream, cr.StreamOptions = true, &streamOptions{IncludeUsage: true}
+	}
+	body, err := json.Marshal(cr)
+	if err != nil {
+		return nil, err
+	}
+	return p.request(ctx, http.MethodPost, "/chat/completions", bytes.NewReader(body))
+}
+
+// parseChat reads a whole chat completion.
+func parseChat(respBody []byte, status int) (Completion, error) {
+	var result chatResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return Completion{}, fmt.Errorf("response parse error (HTTP %d): %w", status, err)
+	}
+	if result.Error != nil {
+		return Completion{}, fmt.Errorf("%s", result.Error.Message)
+	}
+	if len(result.Choices) == 0 {
+		return Completion{}, fmt.Errorf("returned no choices")
+	}
+	out := Completion{Text: strings.TrimSpace(result.Choices[0].Message.Content)}
+	if u := result.Usage; u != nil {
+		out.PromptTokens, out.CompletionTokens = u.PromptTokens, u.CompletionTokens
+	}
+	return out, nil
+}
+
+// Health lists the models, which costs no tokens. A server without the
+// listing still counts as up; only a refused key or a failing server
+// does not.
+func (p *OpenAI) Health(ctx context.Context) error {
+	req, err := p.request(ctx, http.MethodGet, "/models", nil)
+	if err != nil {
+		return err
+	}
+	_, status, err := do(p.HTTP, req)
+	if err != nil {
+		return err
+	}
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return fmt.Errorf("API key rejected (HTTP %d)", status)
+	case status >= 500:
+		return fmt.Errorf("server error (HTTP %d)", status)
+	}
+	return nil
+}
+
+func (p *OpenAI) request(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	if p.RequireKey && p.APIKey == "" {
+		return nil, fmt.Errorf("not configured: set %s", EnvLLMKey)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.BaseURL()+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := offline.CheckHost("LLM", req.URL.Host); err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if p.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	}
+	return req, nil
+}
+
+// do sends req and reads the whole reply.
+func do(c *http.Client, req *http.Request) ([]byte, int, error) {
+	if c == nil {
+		c = http.DefaultClient
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return body, resp.StatusCode, err
+}
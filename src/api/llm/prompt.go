@@ -42,7 +42,7 @@ func readPromptFile(name string) (string, error) {
 	// Also try relative to source (for dev).
 	_, thisFile, _, ok := runtime.Caller(0)
 	if ok {
-		projectRoot := filepath.Dir(filepath.Dir(filepath.Dir(thisFile)))
+		projectRoot := filepath.Dir(filepath.Dir(filepath.Dir(filepath.Dir(thisFile)))) // src/api/llm/prompt.go
 		candidates = append(candidates, filepath.Join(projectRoot, "prompts", name+".txt"))
 	}
 
@@ -0,0 +1,123 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Provider is an LLM backend: a wire format and where it is served.
+type Provider interface {
+	// Name identifies the provider in errors, e.g. "ollama".
+	Name() string
+	// Model is the model completions are asked of.
+	Model() string
+	// BaseURL is the API root requests go to.
+	BaseURL() string
+	// Complete returns the reply to a system and a user message.
+	Complete(ctx context.Context, system, user string) (Completion, error)
+	// Health checks cheaply that Complete can be expected to work.
+	Health(ctx context.Context) error
+}
+
+// Streamer is a Provider that can send a reply as it is written. Stream
+// sends each piece on chunks and returns the whole reply.
+type Streamer interface {
+	Stream(ctx context.Context, system, user string, chunks chan<- string) (Completion, error)
+}
+
+// Completion is a provider's reply with the tokens it reports using,
+// zero when it reports none.
+type Completion struct {
+	Text             string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+var (
+	EnvLLMProvider = "SPRAYER_LLM_PROVIDER"
+	EnvLLMBaseURL  = "SPRAYER_LLM_BASE_URL"
+)
+
+// Provider names accepted in SPRAYER_LLM_PROVIDER.
+const (
+	ProviderHosted = "hosted"
+	ProviderOpenAI = "openai"
+	ProviderOllama = "ollama"
+)
+
+// Defaults used when the environment leaves them unset.
+const (
+	DefaultHostedURL   = "https://api.openai.com/v1"
+	DefaultHostedModel = "kimi-k2"
+	DefaultOllamaURL   = "http://localhost:11434"
+	DefaultOllamaModel = "llama3.1"
+)
+
+// ProviderFromEnv builds the provider SPRAYER_LLM_PROVIDER names, hosted
+// when unset. SPRAYER_LLM_BASE_URL (or the older SPRAYER_LLM_URL) and
+// SPRAYER_LLM_MODEL override its endpoint and model.
+func ProviderFromEnv() (Provider, error) {
+	baseURL := os.Getenv(EnvLLMBaseURL)
+	if baseURL == "" {
+		baseURL = os.Getenv(EnvLLMURL)
+	}
+	model := os.Getenv(EnvLLMModel)
+	name := strings.ToLower(strings.TrimSpace(os.Getenv(EnvLLMProvider)))
+
+	switch name {
+	case "", ProviderHosted:
+		return &OpenAI{
+			Label:      ProviderHosted,
+			URL:        or(baseURL, DefaultHostedURL),
+			APIKey:     os.Getenv(EnvLLMKey),
+			ModelName:  or(model, DefaultHostedModel),
+			RequireKey: true,
+			HTTP:       &http.Client{Timeout: 60 * time.Second},
+		}, nil
+	case ProviderOpenAI:
+		if baseURL == "" {
+			return nil, fmt.Errorf("%s=%s needs %s", EnvLLMProvider, name, EnvLLMBaseURL)
+		}
+		if model == "" {
+			return nil, fmt.Errorf("%s=%s needs %s", EnvLLMProvider, name, EnvLLMModel)
+		}
+		return &OpenAI{
+			Label:     ProviderOpenAI,
+			URL:       baseURL,
+			APIKey:    os.Getenv(EnvLLMKey),
+			ModelName: model,
+			HTTP:      &http.Client{Timeout: 60 * time.Second},
+		}, nil
+	case ProviderOllama:
+		return &Ollama{
+			URL:       or(baseURL, DefaultOllamaURL),
+			ModelName: or(model, DefaultOllamaModel),
+			// Local models on modest hardware take their time.
+			HTTP: &http.Client{Timeout: 5 * time.Minute},
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown %s %q (want %s, %s or %s)", EnvLLMProvider, name, ProviderHosted, ProviderOpenAI, ProviderOllama)
+}
+
+func or(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// misconfigured stands in for a provider the environment names wrongly,
+// so the mistake is reported wherever the LLM would be used.
+type misconfigured struct{ err error }
+
+func (misconfigured) Name() string    { return "llm" }
+func (misconfigured) Model() string   { return "none" }
+func (misconfigured) BaseURL() string { return "" }
+func (p misconfigured) Complete(context.Context, string, string) (Completion, error) {
+	return Completion{}, p.err
+}
+func (p misconfigured) Health(context.Context) error { return p.err }
@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Store keeps the usage log of LLM completions.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore wraps a database connection for the usage log.
+func NewStore(db *sql.DB) (*Store, error) {
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS llm_usage (
+			id                INTEGER PRIMARY KEY AUTOINCREMENT,
+			at                DATETIME NOT NULL,
+			purpose           TEXT NOT NULL,
+			job_id            TEXT NOT NULL DEFAULT '',
+			provider          TEXT NOT NULL,
+			model             TEXT NOT NULL,
+			prompt_tokens     INTEGER DEFAULT 0,
+			completion_tokens INTEGER DEFAULT 0,
+			estimated         BOOLEAN DEFAULT 0,
+			latency_ms        INTEGER DEFAULT 0,
+			cost              REAL DEFAULT 0
+		);
+		CREATE INDEX IF NOT EXISTS idx_llm_usage_at ON llm_usage(at);`)
+	return err
+}
+
+// Record appends u to the log.
+func (s *Store) Record(u Usage) error {
+	_, err := s.db.Exec(`INSERT INTO llm_usage
+		(at, purpose, job_id, provider, model, prompt_tokens, completion_tokens, estimated, latency_ms, cost)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		u.At.UTC(), u.Purpose, u.JobID, u.Provider, u.Model, u.PromptTokens, u.CompletionTokens,
+		u.Estimated, u.Latency.Milliseconds(), u.Cost)
+	return err
+}
+
+// Total sums the completions made for one purpose.
+type Total struct {
+	Purpose          string        `json:"purpose"`
+	Calls            int           `json:"calls"`
+	PromptTokens     int           `json:"prompt_tokens"`
+	CompletionTokens int           `json:"completion_tokens"`
+	Estimated        int           `json:"estimated"` // calls whose tokens were counted from the text
+	Latency          time.Duration `json:"latency"`   // the calls' average
+	Cost             float64       `json:"cost"`
+}
+
+// Totals sums the completions made since, per purpose, costliest first.
+func (s *Store) Totals(since time.Time) ([]Total, error) {
+	rows, err := s.db.Query(`SELECT purpose, COUNT(*), SUM(prompt_tokens), SUM(completion_tokens),
+			SUM(estimated), AVG(latency_ms), SUM(cost)
+		FROM llm_usage WHERE at >= ? GROUP BY purpose ORDER BY SUM(cost) DESC, purpose`, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Total
+	for rows.Next() {
+		var t Total
+		var latency float64
+		if err := rows.Scan(&t.Purpose, &t.Calls, &t.PromptTokens, &t.CompletionTokens, &t.Estimated, &latency, &t.Cost); err != nil {
+			return nil, err
+		}
+		t.Latency = time.Duration(latency) * time.Millisecond
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// Cost is what the completions made since cost.
+func (s *Store) Cost(since time.Time) (float64, error) {
+	var cost float64
+	err := s.db.QueryRow(`SELECT COALESCE(SUM(cost), 0) FROM llm_usage WHERE at >= ?`, since.UTC()).Scan(&cost)
+	return cost, err
+}
@@ -0,0 +1,121 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Purposes completions are recorded under.
+const (
+	PurposeEmail       = "email"
+	PurposeCoverLetter = "cover_letter"
+	PurposeCVLatex     = "cv_latex"
+	PurposeAnswer      = "answer"
+	PurposeOther       = "other"
+)
+
+// Usage is one completion as the usage log records it.
+type Usage struct {
+	ID               int64         `json:"id"`
+	At               time.Time     `json:"at"`
+	Purpose          string        `json:"purpose"`
+	JobID            string        `json:"job_id,omitempty"`
+	Provider         string        `json:"provider"`
+	Model            string        `json:"model"`
+	PromptTokens     int           `json:"prompt_tokens"`
+	CompletionTokens int           `json:"completion_tokens"`
+	Estimated        bool          `json:"estimated"` // counted from the text, the API reported none
+	Latency          time.Duration `json:"latency"`
+	Cost             float64       `json:"cost"` // US dollars, from the price table
+}
+
+type callKey struct{}
+
+type call struct{ purpose, jobID string }
+
+// For returns ctx tagged so completions made with it are recorded under
+// purpose and jobID.
+func For(ctx context.Context, purpose, jobID string) context.Context {
+	return context.WithValue(ctx, callKey{}, call{purpose, jobID})
+}
+
+func callOf(ctx context.Context) call {
+	if c, ok := ctx.Value(callKey{}).(call); ok && c.purpose != "" {
+		return c
+	}
+	return call{purpose: PurposeOther}
+}
+
+// EstimateTokens guesses the tokens in s at four characters each, about
+// what English text comes to.
+func EstimateTokens(s string) int {
+	return (utf8.RuneCountInString(s) + 3) / 4
+}
+
+// Price is what a model costs, in US dollars per million tokens.
+type Price struct {
+	Prompt     float64 `json:"prompt"`
+	Completion float64 `json:"completion"`
+}
+
+// Prices maps model names to their price.
+type Prices map[string]Price
+
+// DefaultPrices are list prices at the time of writing. Models not in
+// the table, local ones included, cost nothing.
+var DefaultPrices = Prices{
+	"kimi-k2":     {Prompt: 0.60, Completion: 2.50},
+	"gpt-4o":      {Prompt: 2.50, Completion: 10.00},
+	"gpt-4o-mini": {Prompt: 0.15, Completion: 0.60},
+	"deepseek-v3": {Prompt: 0.27, Completion: 1.10},
+}
+
+// EnvLLMPrices names a JSON file of prices, {"model": {"prompt": 0.6,
+// "completion": 2.5}}, overriding and adding to DefaultPrices.
+var EnvLLMPrices = "SPRAYER_LLM_PRICES"
+
+// LoadPrices returns DefaultPrices with the table SPRAYER_LLM_PRICES
+// names, or ~/.sprayer/llm_prices.json, laid over them. A missing file
+// leaves the defaults.
+func LoadPrices() (Prices, error) {
+	path := os.Getenv(EnvLLMPrices)
+	if path == "" {
+		path = filepath.Join(os.Getenv("HOME"), ".sprayer", "llm_prices.json")
+	}
+	prices := Prices{}
+	for m, p := range DefaultPrices {
+		prices[m] = p
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return prices, nil
+	} else if err != nil {
+		return prices, err
+	}
+	var custom Prices
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return prices, err
+	}
+	for m, p := range custom {
+		prices[m] = p
+	}
+	return prices, nil
+}
+
+// Cost prices a completion of model. Ollama's tags are ignored when the
+// table has only the bare model.
+func (t Prices) Cost(model string, promptTokens, completionTokens int) float64 {
+	p, ok := t[model]
+	if !ok {
+		base, _, _ := strings.Cut(model, ":")
+		p = t[base]
+	}
+	return (float64(promptTokens)*p.Prompt + float64(completionTokens)*p.Completion) / 1e6
+}
@@ -0,0 +1,109 @@
+package llm
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openStore(t *testing.T) *Store {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "llm.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	s, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestClient_RecordsUsage(t *testing.T) {
+	reported := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if reported {
+			w.Write([]byte(`{"choices":[{"message":{"content":"Dear Acme"}}],"usage":{"prompt_tokens":1000,"completion_tokens":200}}`))
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"content":"Dear Acme, thanks"}}]}`))
+	}))
+	defer srv.Close()
+	s := openStore(t)
+	c := NewClientFor(&OpenAI{Label: ProviderHosted, URL: srv.URL, APIKey: "k", ModelName: "kimi-k2"})
+	c.RecordUsage(s, Prices{"kimi-k2": {Prompt: 1, Completion: 5}})
+	start := time.Now().Add(-time.Second)
+
+	if _, err := c.CompleteContext(For(context.Background(), PurposeEmail, "job-1"), "system", "user"); err != nil {
+		t.Fatal(err)
+	}
+	reported = false
+	if _, err := c.CompleteContext(For(context.Background(), PurposeCoverLetter, "job-1"), "sys", "a prompt of 24 letters.."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Complete("s", "u"); err != nil {
+		t.Fatal(err)
+	}
+
+	totals, err := s.Totals(start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byPurpose := map[string]Total{}
+	for _, tot := range totals {
+		byPurpose[tot.Purpose] = tot
+	}
+	if e := byPurpose[PurposeEmail]; e.Calls != 1 || e.PromptTokens != 1000 || e.CompletionTokens != 200 || e.Estimated != 0 || e.Cost != 0.002 {
+		t.Errorf("email usage = %+v", e)
+	}
+	if cl := byPurpose[PurposeCoverLetter]; cl.Calls != 1 || cl.Estimated != 1 || cl.PromptTokens != 7 || cl.CompletionTokens != 5 {
+		t.Errorf("estimated cover letter usage = %+v", cl)
+	}
+	if o := byPurpose[PurposeOther]; o.Calls != 1 {
+		t.Errorf("untagged call = %+v", o)
+	}
+	if len(totals) != 3 || totals[0].Purpose != PurposeEmail {
+		t.Errorf("totals = %+v, want three, costliest first", totals)
+	}
+	if cost, err := s.Cost(start); err != nil || cost < 0.002 {
+		t.Errorf("Cost = %v, %v", cost, err)
+	}
+	if cost, _ := s.Cost(time.Now().Add(time.Hour)); cost != 0 {
+		t.Errorf("Cost from the future = %v", cost)
+	}
+}
+
+func TestPrices(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.json")
+	os.WriteFile(path, []byte(`{"llama3.1": {"prompt": 0.1, "completion": 0.2}, "kimi-k2": {"prompt": 1, "completion": 2}}`), 0o644)
+	t.Setenv(EnvLLMPrices, path)
+	prices, err := LoadPrices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := prices.Cost("kimi-k2", 1e6, 1e6); got != 3 {
+		t.Errorf("overridden kimi-k2 cost = %v", got)
+	}
+	if got := prices.Cost("llama3.1:8b", 1e6, 0); got != 0.1 {
+		t.Errorf("tagged model cost = %v", got)
+	}
+	if got := prices.Cost("gpt-4o", 1e6, 0); got != DefaultPrices["gpt-4o"].Prompt {
+		t.Errorf("default gpt-4o cost = %v", got)
+	}
+	if got := prices.Cost("mystery", 1e6, 1e6); got != 0 {
+		t.Errorf("unpriced model cost = %v", got)
+	}
+
+	t.Setenv(EnvLLMPrices, filepath.Join(t.TempDir(), "missing.json"))
+	if prices, err := LoadPrices(); err != nil || len(prices) != len(DefaultPrices) {
+		t.Errorf("missing price file = %v, %v", prices, err)
+	}
+}
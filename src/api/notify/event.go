@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// Kinds of Event.
+const (
+	EventScrapeCompleted = "scrape.completed"
+	EventApplicationSent = "application.sent"
+	EventReplyReceived   = "reply.received"
+	EventFollowUpDue     = "followup.due"
+)
+
+// Event is something in the job hunt worth telling a webhook about. Text
+// says it in one line; the other fields are set as the kind has them.
+type Event struct {
+	Kind    string    `json:"event"`
+	At      time.Time `json:"at"`
+	Text    string    `json:"text"`
+	Profile string    `json:"profile,omitempty"`
+	JobID   string    `json:"job_id,omitempty"`
+	Title   string    `json:"title,omitempty"`
+	Company string    `json:"company,omitempty"`
+	To      string    `json:"to,omitempty"`
+	From    string    `json:"from,omitempty"`
+	Subject string    `json:"subject,omitempty"`
+	Inbox   string    `json:"inbox,omitempty"`
+	Found   int       `json:"found,omitempty"`
+	Seen    int       `json:"seen,omitempty"`
+	Errors  int       `json:"errors,omitempty"`
+}
+
+// ScrapeCompleted reports a scrape for profile that saved found jobs,
+// skipped seen ones scraped before and met errs errors.
+func ScrapeCompleted(profile string, found, seen, errs int) Event {
+	text := fmt.Sprintf("Scrape for %s saved %d jobs", profile, found)
+	if seen > 0 {
+		text += fmt.Sprintf(" (%d seen before)", seen)
+	}
+	if errs > 0 {
+		text += fmt.Sprintf(", %d errors", errs)
+	}
+	return Event{Kind: EventScrapeCompleted, At: time.Now(), Text: text,
+		Profile: profile, Found: found, Seen: seen, Errors: errs}
+}
+
+// ApplicationSent reports an application to j emailed to to.
+func ApplicationSent(j job.Job, to string) Event {
+	return Event{Kind: EventApplicationSent, At: time.Now(),
+		Text:  fmt.Sprintf("Applied to %s at %s", j.Title, j.Company),
+		JobID: j.ID, Title: j.Title, Company: j.Company, To: to}
+}
+
+// ReplyReceived reports a message from from arriving in inbox, about
+// jobID at company when it is tied to one.
+func ReplyReceived(from, subject, inbox, jobID, company string) Event {
+	text := fmt.Sprintf("Reply from %s: %s", from, subject)
+	if company != "" {
+		text = fmt.Sprintf("Reply from %s (%s): %s", company, from, subject)
+	}
+	return Event{Kind: EventReplyReceived, At: time.Now(), Text: text,
+		From: from, Subject: subject, Inbox: inbox, JobID: jobID, Company: company}
+}
+
+// FollowUpDue reports the follow-up of j falling due.
+func FollowUpDue(j job.Job) Event {
+	return Event{Kind: EventFollowUpDue, At: time.Now(),
+		Text:  fmt.Sprintf("Follow-up due for %s at %s", j.Title, j.Company),
+		JobID: j.ID, Title: j.Title, Company: j.Company}
+}
@@ -0,0 +1,95 @@
+// Package notify tells the user what happened while they looked away. It
+// shows desktop notifications, through notify-send on Linux and the BSDs
+// or osascript on macOS; elsewhere, or without those programs, notifying
+// fails with ErrUnavailable and callers carry on without. It also posts
+// Events to the webhook in EnvWebhookURL, queueing them while it is down.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// ErrUnavailable is returned when this system has no way to notify.
+var ErrUnavailable = errors.New("desktop notifications unavailable")
+
+// Timeout bounds how long a notification may take to hand over.
+const Timeout = 5 * time.Second
+
+// Notification is one message for the desktop.
+type Notification struct {
+	Title string
+	Body  string
+}
+
+// Notifier shows notifications. Notify must return within Timeout.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Command notifies by running Name with the arguments Args builds.
+type Command struct {
+	Name string
+	Args func(Notification) []string
+}
+
+// Notify runs the command. A missing program is ErrUnavailable.
+func (c Command) Notify(ctx context.Context, n Notification) error {
+	path, err := exec.LookPath(c.Name)
+	if err != nil {
+		return fmt.Errorf("%w: %s not found", ErrUnavailable, c.Name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	if out, err := exec.CommandContext(ctx, path, c.Args(n)...).CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %w: %s", c.Name, err, msg)
+		}
+		return fmt.Errorf("%s: %w", c.Name, err)
+	}
+	return nil
+}
+
+// NotifySend is notify-send from libnotify.
+var NotifySend = Command{Name: "notify-send", Args: func(n Notification) []string {
+	return []string{"--app-name=sprayer", "--", n.Title, n.Body}
+}}
+
+// OSAScript shows a macOS notification through AppleScript.
+var OSAScript = Command{Name: "osascript", Args: func(n Notification) []string {
+	return []string{"-e", fmt.Sprintf("display notification %s with title %s", strconv.Quote(n.Body), strconv.Quote(n.Title))}
+}}
+
+// unavailable is the Notifier of systems with no known notifier.
+type unavailable struct{}
+
+func (unavailable) Notify(context.Context, Notification) error {
+	return fmt.Errorf("%w on %s", ErrUnavailable, runtime.GOOS)
+}
+
+// Desktop returns the notifier for this system.
+func Desktop() Notifier {
+	switch runtime.GOOS {
+	case "darwin":
+		return OSAScript
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return NotifySend
+	}
+	return unavailable{}
+}
+
+// ForJob is the notification of a new job: its score, title and company.
+func ForJob(j job.Job) Notification {
+	return Notification{
+		Title: fmt.Sprintf("New job (%d): %s", j.Score, j.Title),
+		Body:  j.Company,
+	}
+}
@@ -0,0 +1,35 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"sprayer/src/api/job"
+)
+
+func TestCommand_MissingProgram(t *testing.T) {
+	c := Command{Name: "sprayer-no-such-notifier", Args: func(Notification) []string { return nil }}
+	if err := c.Notify(context.Background(), Notification{Title: "t"}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("got %v, want ErrUnavailable", err)
+	}
+}
+
+func TestCommand_PassesTitleAndBody(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	c := Command{Name: "sh", Args: func(n Notification) []string {
+		return []string{"-c", `printf '%s|%s' "$1" "$2" > "$0"`, out, n.Title, n.Body}
+	}}
+	if err := c.Notify(context.Background(), ForJob(job.Job{Title: "Go Engineer", Company: "Acme", Score: 88})); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "New job (88): Go Engineer|Acme"; string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,219 @@
+package notify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/offline"
+)
+
+// EnvWebhookURL is where events are POSTed; unset sends none.
+const EnvWebhookURL = "SPRAYER_WEBHOOK_URL"
+
+// EnvWebhookSlack, set to 1, posts events as Slack messages ({"text":
+// ...}) rather than the Event JSON.
+const EnvWebhookSlack = "SPRAYER_WEBHOOK_SLACK"
+
+// Attempts is how many times a post is tried while the server answers
+// 5xx or cannot be reached.
+const Attempts = 3
+
+// MaxQueued is the most events kept for later; the oldest go first.
+const MaxQueued = 500
+
+// QueueFile is where events that could not be posted wait for the next.
+func QueueFile() string {
+	return filepath.Join(job.DataDir(), "webhook-queue.jsonl")
+}
+
+// Sink posts events to a webhook, keeping those it could not deliver in
+// Queue to send ahead of the next.
+type Sink struct {
+	URL    string
+	Slack  bool
+	Queue  string
+	Client *http.Client // nil uses a client with a 10s timeout
+
+	// Backoff is the wait before the attempt after attempt n; nil
+	// waits n seconds.
+	Backoff func(n int) time.Duration
+}
+
+// SinkFromEnv configures a Sink from EnvWebhookURL and EnvWebhookSlack,
+// or returns nil when no URL is set.
+func SinkFromEnv() *Sink {
+	url := os.Getenv(EnvWebhookURL)
+	if url == "" {
+		return nil
+	}
+	slack, _ := strconv.ParseBool(os.Getenv(EnvWebhookSlack))
+	return &Sink{URL: url, Slack: slack, Queue: QueueFile()}
+}
+
+// Notify posts e to the webhook configured in the environment, if any.
+// An event that cannot be delivered is queued for the next call, so
+// callers may ignore the error.
+func Notify(e Event) error {
+	s := SinkFromEnv()
+	if s == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return s.Post(ctx, e)
+}
+
+// queueMu keeps posts in this process from interleaving queue rewrites.
+var queueMu sync.Mutex
+
+// Post sends the queued events and then e, in order. Once one fails,
+// it and everything after it are queued.
+func (s *Sink) Post(ctx context.Context, e Event) error {
+	queueMu.Lock()
+	defer queueMu.Unlock()
+	pending, err := s.readQueue()
+	if err != nil {
+		return err
+	}
+	pending = append(pending, e)
+	for i, ev := range pending {
+		if err := s.post(ctx, ev); err != nil {
+			if qerr := s.writeQueue(pending[i:]); qerr != nil {
+				return errors.Join(err, qerr)
+			}
+			return err
+		}
+	}
+	return s.writeQueue(nil)
+}
+
+// errPermanent marks a failure that retrying will not fix.
+type errPermanent struct{ error }
+
+// post delivers one event, retrying on 5xx and network errors. An event
+// the server refuses with 4xx is dropped: it would be refused again.
+func (s *Sink) post(ctx context.Context, e Event) error {
+	var body any = e
+	if s.Slack {
+		body = map[string]string{"text": e.Text}
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	for n := 1; ; n++ {
+		err = s.try(ctx, raw)
+		var perm errPermanent
+		switch {
+		case err == nil:
+			return nil
+		case errors.As(err, &perm):
+			return nil
+		case errors.Is(err, offline.ErrOffline), n >= Attempts:
+			return err
+		}
+		wait := time.Duration(n) * time.Second
+		if s.Backoff != nil {
+			wait = s.Backoff(n)
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (s *Sink) try(ctx context.Context, raw []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(raw))
+	if err != nil {
+		return errPermanent{err}
+	}
+	if err := offline.CheckHost("webhook", req.URL.Host); err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	switch {
+	case resp.StatusCode >= 500:
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	case resp.StatusCode >= 300:
+		return errPermanent{fmt.Errorf("webhook answered %s", resp.Status)}
+	}
+	return nil
+}
+
+func (s *Sink) readQueue() ([]Event, error) {
+	if s.Queue == "" {
+		return nil, nil
+	}
+	f, err := os.Open(s.Queue)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var events []Event
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e Event
+		if json.Unmarshal(sc.Bytes(), &e) == nil {
+			events = append(events, e)
+		}
+	}
+	return events, sc.Err()
+}
+
+// writeQueue replaces the queue with events, the newest MaxQueued.
+func (s *Sink) writeQueue(events []Event) error {
+	if s.Queue == "" {
+		return nil
+	}
+	if len(events) == 0 {
+		if err := os.Remove(s.Queue); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if len(events) > MaxQueued {
+		events = events[len(events)-MaxQueued:]
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Queue), 0755); err != nil {
+		return err
+	}
+	tmp := s.Queue + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Queue)
+}
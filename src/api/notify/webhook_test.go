@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// hook answers each post with the next of codes, then 200, and keeps the
+// bodies it accepted.
+type hook struct {
+	mu    sync.Mutex
+	codes []int
+	posts int
+	got   []map[string]any
+}
+
+func (h *hook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.posts++
+	if len(h.codes) > 0 {
+		code := h.codes[0]
+		h.codes = h.codes[1:]
+		if code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+	}
+	var body map[string]any
+	json.NewDecoder(r.Body).Decode(&body)
+	h.got = append(h.got, body)
+}
+
+func newSink(t *testing.T, h *hook) *Sink {
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return &Sink{URL: srv.URL, Queue: filepath.Join(t.TempDir(), "queue.jsonl"),
+		Backoff: func(int) time.Duration { return 0 }}
+}
+
+func TestSink_RetriesServerErrors(t *testing.T) {
+	h := &hook{codes: []int{502, 503}}
+	s := newSink(t, h)
+	if err := s.Post(context.Background(), ApplicationSent(job.Job{ID: "1", Title: "Go Engineer", Company: "Acme"}, "jobs@acme.com")); err != nil {
+		t.Fatal(err)
+	}
+	if h.posts != 3 || len(h.got) != 1 || h.got[0]["event"] != EventApplicationSent || h.got[0]["company"] != "Acme" {
+		t.Errorf("%d posts, got %v", h.posts, h.got)
+	}
+}
+
+func TestSink_QueuesUntilDelivered(t *testing.T) {
+	h := &hook{codes: []int{500, 500, 500}}
+	s := newSink(t, h)
+	if err := s.Post(context.Background(), ScrapeCompleted("alice", 12, 30, 0)); err == nil {
+		t.Fatal("want an error after every attempt failed")
+	}
+	if q, _ := s.readQueue(); len(q) != 1 {
+		t.Fatalf("queued %d events, want 1", len(q))
+	}
+	if err := s.Post(context.Background(), FollowUpDue(job.Job{Title: "Go Engineer", Company: "Acme"})); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.got) != 2 || h.got[0]["event"] != EventScrapeCompleted || h.got[1]["event"] != EventFollowUpDue {
+		t.Errorf("got %v, want the queued scrape then the follow-up", h.got)
+	}
+	if q, _ := s.readQueue(); len(q) != 0 {
+		t.Errorf("%d events still queued", len(q))
+	}
+}
+
+func TestSink_DropsRefusedEvents(t *testing.T) {
+	h := &hook{codes: []int{400}}
+	s := newSink(t, h)
+	if err := s.Post(context.Background(), ScrapeCompleted("alice", 1, 0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if q, _ := s.readQueue(); h.posts != 1 || len(q) != 0 {
+		t.Errorf("%d posts and %d queued; want one try and nothing kept", h.posts, len(q))
+	}
+}
+
+func TestSink_Slack(t *testing.T) {
+	h := &hook{}
+	s := newSink(t, h)
+	s.Slack = true
+	if err := s.Post(context.Background(), ReplyReceived("jane@acme.com", "Re: Go Engineer", "mailtm", "1", "Acme")); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.got) != 1 || len(h.got[0]) != 1 || h.got[0]["text"] != "Reply from Acme (jane@acme.com): Re: Go Engineer" {
+		t.Errorf("got %v, want only Slack's text", h.got)
+	}
+}
+
+func TestNotify_UnconfiguredSendsNothing(t *testing.T) {
+	t.Setenv(EnvWebhookURL, "")
+	if err := Notify(ScrapeCompleted("alice", 1, 0, 0)); err != nil {
+		t.Error(err)
+	}
+}
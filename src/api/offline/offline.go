@@ -0,0 +1,152 @@
+// Package offline is the shared gate every network call consults, so that
+// without a connection sprayer fails fast and says so instead of waiting
+// out connection timeouts.
+//
+// The mode is probed once per process, on first use, by a short dial;
+// EnvOffline overrides the probe either way.
+package offline
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EnvOffline forces the mode: "1" or "true" is offline, "0" or "false"
+// online. Unset or anything else probes.
+const EnvOffline = "SPRAYER_OFFLINE"
+
+// EnvProbeAddr is the host:port dialled to detect a connection.
+const EnvProbeAddr = "SPRAYER_PROBE_ADDR"
+
+// DefaultProbeAddr is dialled when EnvProbeAddr is unset. An IP address
+// keeps a missing resolver from stretching the probe.
+const DefaultProbeAddr = "1.1.1.1:443"
+
+// ProbeTimeout bounds the connectivity probe.
+const ProbeTimeout = 1500 * time.Millisecond
+
+// ErrOffline matches every *Error with errors.Is.
+var ErrOffline = errors.New("offline")
+
+// Error reports a feature refused because the gate is offline.
+type Error struct {
+	Feature string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("offline — %s unavailable", e.Feature)
+}
+
+// Is makes errors.Is(err, ErrOffline) true.
+func (e *Error) Is(target error) bool { return target == ErrOffline }
+
+// Gate decides whether network features may run.
+type Gate struct {
+	// Probe reports an error when there is no connection.
+	Probe func() error
+
+	mu      sync.Mutex
+	decided bool
+	offline bool
+}
+
+// NewGate returns a gate that asks probe on first use.
+func NewGate(probe func() error) *Gate {
+	return &Gate{Probe: probe}
+}
+
+// Offline reports whether the gate is closed, probing the first time
+// unless EnvOffline decides.
+func (g *Gate) Offline() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.decided {
+		if forced, err := strconv.ParseBool(os.Getenv(EnvOffline)); err == nil {
+			g.offline = forced
+		} else {
+			g.offline = g.Probe != nil && g.Probe() != nil
+		}
+		g.decided = true
+	}
+	return g.offline
+}
+
+// Check returns an *Error naming feature when offline, nil otherwise.
+func (g *Gate) Check(feature string) error {
+	if g.Offline() {
+		return &Error{Feature: feature}
+	}
+	return nil
+}
+
+// CheckHost is Check for a call to host, which may carry a port. Hosts on
+// this machine, such as a local LLM server, stay reachable offline.
+func (g *Gate) CheckHost(feature, host string) error {
+	if isLocal(host) {
+		return nil
+	}
+	return g.Check(feature)
+}
+
+func isLocal(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Set fixes the mode, skipping the probe.
+func (g *Gate) Set(offline bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.offline, g.decided = offline, true
+}
+
+// Dial is the default probe: a TCP connection to EnvProbeAddr.
+func Dial() error {
+	addr := os.Getenv(EnvProbeAddr)
+	if addr == "" {
+		addr = DefaultProbeAddr
+	}
+	conn, err := net.DialTimeout("tcp", addr, ProbeTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Default is the process-wide gate.
+var Default = NewGate(Dial)
+
+// Offline reports whether Default is closed.
+func Offline() bool { return Default.Offline() }
+
+// Check consults Default for feature.
+func Check(feature string) error { return Default.Check(feature) }
+
+// CheckHost consults Default for feature on host.
+func CheckHost(feature, host string) error { return Default.CheckHost(feature, host) }
+
+// Force sets Default's mode and returns a function restoring the previous
+// one, for tests and for flags that override detection.
+func Force(offline bool) (restore func()) {
+	g := Default
+	g.mu.Lock()
+	prevDecided, prevOffline := g.decided, g.offline
+	g.mu.Unlock()
+	g.Set(offline)
+	return func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.decided, g.offline = prevDecided, prevOffline
+	}
+}
@@ -0,0 +1,74 @@
+package offline
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGate_ProbesOnce(t *testing.T) {
+	t.Setenv(EnvOffline, "")
+	calls := 0
+	g := NewGate(func() error { calls++; return errors.New("no route to host") })
+	for range 3 {
+		if !g.Offline() {
+			t.Fatal("failed probe should mean offline")
+		}
+	}
+	if calls != 1 {
+		t.Errorf("probed %d times, want once", calls)
+	}
+
+	err := g.Check("scraping")
+	if !errors.Is(err, ErrOffline) || err.Error() != "offline — scraping unavailable" {
+		t.Errorf("Check = %v", err)
+	}
+	g.Set(false)
+	if err := g.Check("scraping"); err != nil {
+		t.Errorf("Set(false) still refuses: %v", err)
+	}
+}
+
+func TestGate_EnvOverridesProbe(t *testing.T) {
+	probed := false
+	probe := func() error { probed = true; return nil }
+
+	t.Setenv(EnvOffline, "1")
+	if !NewGate(probe).Offline() {
+		t.Error("SPRAYER_OFFLINE=1 should force offline")
+	}
+	t.Setenv(EnvOffline, "false")
+	if NewGate(func() error { probed = true; return errors.New("down") }).Offline() {
+		t.Error("SPRAYER_OFFLINE=false should force online")
+	}
+	if probed {
+		t.Error("forced mode should not probe")
+	}
+}
+
+func TestGate_CheckHostAllowsLocal(t *testing.T) {
+	g := NewGate(nil)
+	g.Set(true)
+	for _, host := range []string{"localhost", "localhost:11434", "127.0.0.1:8080", "[::1]:25"} {
+		if err := g.CheckHost("LLM generation", host); err != nil {
+			t.Errorf("%s refused offline: %v", host, err)
+		}
+	}
+	for _, host := range []string{"api.openai.com", "smtp.gmail.com:587", "10.0.0.5:25"} {
+		if err := g.CheckHost("sending", host); !errors.Is(err, ErrOffline) {
+			t.Errorf("%s allowed offline", host)
+		}
+	}
+}
+
+func TestForce_Restores(t *testing.T) {
+	restore := Force(true)
+	if !Offline() {
+		t.Fatal("Force(true) not applied")
+	}
+	inner := Force(false)
+	inner()
+	if !Offline() {
+		t.Error("inner restore lost the outer mode")
+	}
+	restore()
+}
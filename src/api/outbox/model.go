@@ -0,0 +1,70 @@
+// Package outbox queues outgoing application emails so a send that fails
+// (SMTP down, offline) keeps the message and retries it with backoff
+// instead of losing it.
+package outbox
+
+import (
+	"fmt"
+	"time"
+)
+
+// Status is where a queued message stands.
+type Status string
+
+const (
+	StatusPending Status = "pending" // waiting for its next attempt
+	// StatusSending is saved just before a message is handed to the
+	// mailer. Found later, the send was interrupted and the email may or
+	// may not be out, so it is left for the user to retry or delete.
+	StatusSending Status = "sending"
+	StatusSent    Status = "sent"
+	StatusFailed  Status = "failed" // gave up after MaxAttempts; LastError says why
+)
+
+// MaxAttempts is how many sends a message gets before it is marked failed
+// and left for the user to retry or delete.
+const MaxAttempts = 5
+
+// Backoff is the wait after the given number of failed attempts: a
+// minute, doubling each time, at most an hour.
+func Backoff(attempts int) time.Duration {
+	d := time.Minute
+	for i := 1; i < attempts && d < time.Hour; i++ {
+		d *= 2
+	}
+	return min(d, time.Hour)
+}
+
+// Message is one queued email. Body is the letter as written; tracking
+// and the CV are added when it is sent.
+type Message struct {
+	ID          int64      `json:"id"`
+	JobID       string     `json:"job_id"`
+	ProfileID   string     `json:"profile_id"`
+	To          string     `json:"to"`
+	Cc          []string   `json:"cc,omitempty"`
+	Subject     string     `json:"subject"`
+	Body        string     `json:"body"`
+	Status      Status     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	LastError   string     `json:"last_error,omitempty"`
+	NextAttempt time.Time  `json:"next_attempt"`
+	CreatedAt   time.Time  `json:"created_at"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+}
+
+// Summary counts what a flush did.
+type Summary struct {
+	Sent     int `json:"sent"`
+	Retrying int `json:"retrying"` // failed, to be tried again later
+	Failed   int `json:"failed"`   // failed for the last time
+	Sending  int `json:"sending"`  // left sending by an interrupted run
+}
+
+func (s Summary) String() string {
+	out := fmt.Sprintf("%d sent, %d to retry, %d failed", s.Sent, s.Retrying, s.Failed)
+	if s.Sending > 0 {
+		out += fmt.Sprintf(", %d interrupted while sending (check, then retry or rm)", s.Sending)
+	}
+	return out
+}
@@ -0,0 +1,211 @@
+package outbox
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"sprayer/src/api/offline"
+)
+
+func openStore(t *testing.T) *Store {
+	t.Helper()
+	return openStoreAt(t, filepath.Join(t.TempDir(), "outbox.db"))
+}
+
+func openStoreAt(t *testing.T, path string) *Store {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	s, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func enqueue(t *testing.T, s *Store, to string) *Message {
+	t.Helper()
+	m := &Message{JobID: "job-" + to, ProfileID: "default", To: to, Cc: []string{"cto@example.com"}, Subject: "Hello", Body: "Dear team,"}
+	if err := s.Enqueue(m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestBackoff(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{
+		1: time.Minute, 2: 2 * time.Minute, 3: 4 * time.Minute, 7: time.Hour, 40: time.Hour,
+	} {
+		if got := Backoff(attempts); got != want {
+			t.Errorf("Backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestFlush_RetriesWithBackoff(t *testing.T) {
+	s := openStore(t)
+	a := enqueue(t, s, "a@example.com")
+	b := enqueue(t, s, "b@example.com")
+
+	down := errors.New("connection refused")
+	var tried []string
+	send := func(m Message) error {
+		tried = append(tried, m.To)
+		if m.To == "a@example.com" {
+			return down
+		}
+		return nil
+	}
+	now := time.Now()
+	sum, err := s.Flush(now, send)
+	if err != nil || sum != (Summary{Sent: 1, Retrying: 1}) {
+		t.Fatalf("Flush = %v, %v", sum, err)
+	}
+	got, _ := s.ByID(a.ID)
+	if got.Status != StatusPending || got.Attempts != 1 || got.LastError != "connection refused" ||
+		!got.NextAttempt.Equal(now.Add(time.Minute).UTC()) {
+		t.Errorf("failed message = %+v", got)
+	}
+	if got, _ := s.ByID(b.ID); got.Status != StatusSent || got.SentAt == nil || len(got.Cc) != 1 {
+		t.Errorf("sent message = %+v", got)
+	}
+
+	// Not due again until the backoff has passed.
+	tried = nil
+	if sum, _ := s.Flush(now.Add(30*time.Second), send); sum != (Summary{}) || len(tried) != 0 {
+		t.Errorf("flushed before the backoff: %v, tried %v", sum, tried)
+	}
+
+	// The last attempt marks it failed; Requeue gives it a fresh start.
+	at := now
+	for i := 2; i <= MaxAttempts; i++ {
+		at = at.Add(Backoff(i - 1))
+		sum, _ = s.Flush(at, send)
+	}
+	if sum != (Summary{Failed: 1}) {
+		t.Errorf("last flush = %v", sum)
+	}
+	if got, _ := s.ByID(a.ID); got.Status != StatusFailed || got.Attempts != MaxAttempts {
+		t.Errorf("given-up message = %+v", got)
+	}
+	if unsent, _ := s.Unsent(); len(unsent) != 1 || unsent[0].ID != a.ID {
+		t.Errorf("Unsent = %+v", unsent)
+	}
+	if err := s.Requeue(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(a.ID, time.Now(), func(Message) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.ByID(a.ID); got.Status != StatusSent || got.LastError != "" {
+		t.Errorf("retried message = %+v", got)
+	}
+	if err := s.Requeue(a.ID); err == nil {
+		t.Error("requeued a sent message")
+	}
+}
+
+func TestFlush_OfflineKeepsQueue(t *testing.T) {
+	s := openStore(t)
+	m := enqueue(t, s, "a@example.com")
+	enqueue(t, s, "b@example.com")
+
+	calls := 0
+	_, err := s.Flush(time.Now(), func(Message) error {
+		calls++
+		return &offline.Error{Feature: "sending"}
+	})
+	if !errors.Is(err, offline.ErrOffline) || calls != 1 {
+		t.Fatalf("Flush = %v after %d sends, want to stop offline", err, calls)
+	}
+	if got, _ := s.ByID(m.ID); got.Status != StatusPending || got.Attempts != 0 {
+		t.Errorf("offline counted an attempt: %+v", got)
+	}
+
+	if err := s.Delete(m.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ByID(m.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted message still there: %v", err)
+	}
+	if err := s.Delete(m.ID); err == nil {
+		t.Error("deleting twice succeeded")
+	}
+}
+
+func TestSend_LeavesInterruptedMessages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.db")
+	s := openStoreAt(t, path)
+	a := enqueue(t, s, "a@example.com")
+	enqueue(t, s, "b@example.com")
+	now := time.Now()
+
+	// The process dies while the mailer has the first message.
+	func() {
+		defer func() { recover() }()
+		s.Flush(now, func(m Message) error {
+			if m.To == a.To {
+				panic("killed")
+			}
+			return nil
+		})
+	}()
+
+	s = openStoreAt(t, path)
+	var sent []string
+	record := func(m Message) error {
+		sent = append(sent, m.To)
+		return nil
+	}
+	sum, err := s.Flush(now, record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0] != "b@example.com" {
+		t.Errorf("resumed flush sent %v, want only b@example.com", sent)
+	}
+	if sum.Sent != 1 || sum.Sending != 1 {
+		t.Errorf("summary = %+v (%s)", sum, sum)
+	}
+	if err := s.Send(a.ID, now, record); !errors.Is(err, ErrNotPending) {
+		t.Errorf("sending the interrupted message again: %v, want ErrNotPending", err)
+	}
+
+	if err := s.Requeue(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Send(a.ID, now, record); err != nil || len(sent) != 2 {
+		t.Errorf("after requeue: %v, sent %v", err, sent)
+	}
+}
+
+func TestSend_OneRunPerMessage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.db")
+	first, second := openStoreAt(t, path), openStoreAt(t, path)
+	m := enqueue(t, first, "a@example.com")
+
+	sends := 0
+	var raced error
+	err := first.Send(m.ID, time.Now(), func(Message) error {
+		sends++
+		raced = second.Send(m.ID, time.Now(), func(Message) error {
+			sends++
+			return nil
+		})
+		return nil
+	})
+	if err != nil || sends != 1 || !errors.Is(raced, ErrNotPending) {
+		t.Errorf("sends = %d, first %v, second %v; want one send and the second refused", sends, err, raced)
+	}
+	if got, _ := first.ByID(m.ID); got.Status != StatusSent {
+		t.Errorf("status = %s, want sent", got.Status)
+	}
+}
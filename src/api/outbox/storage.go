@@ -0,0 +1,228 @@
+package outbox
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"sprayer/src/api/offline"
+)
+
+// Store persists queued messages.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore wraps a database connection for the outbox.
+func NewStore(db *sql.DB) (*Store, error) {
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS sent_queue (
+			id           INTEGER PRIMARY KEY AUTOINCREMENT,
+			job_id       TEXT,
+			profile_id   TEXT,
+			recipient    TEXT,
+			cc           TEXT DEFAULT '',
+			subject      TEXT,
+			body         TEXT,
+			status       TEXT,
+			attempts     INTEGER DEFAULT 0,
+			last_error   TEXT DEFAULT '',
+			next_attempt DATETIME,
+			created_at   DATETIME,
+			sent_at      DATETIME
+		);
+		CREATE INDEX IF NOT EXISTS idx_sent_queue_status ON sent_queue(status, next_attempt);`)
+	return err
+}
+
+const columns = `id, job_id, profile_id, recipient, cc, subject, body, status, attempts, last_error, next_attempt, created_at, sent_at`
+
+// Enqueue stores m as pending, due at once, and sets its ID.
+func (s *Store) Enqueue(m *Message) error {
+	now := time.Now().UTC()
+	res, err := s.db.Exec(`
+		INSERT INTO sent_queue (job_id, profile_id, recipient, cc, subject, body, status, next_attempt, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.JobID, m.ProfileID, m.To, strings.Join(m.Cc, ","), m.Subject, m.Body, StatusPending, now, now)
+	if err != nil {
+		return err
+	}
+	if m.ID, err = res.LastInsertId(); err != nil {
+		return err
+	}
+	m.Status, m.Attempts, m.LastError, m.NextAttempt, m.CreatedAt = StatusPending, 0, "", now, now
+	return nil
+}
+
+// ByID loads a message; an unknown ID returns sql.ErrNoRows.
+func (s *Store) ByID(id int64) (*Message, error) {
+	msgs, err := s.query(`SELECT `+columns+` FROM sent_queue WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &msgs[0], nil
+}
+
+// Unsent lists the pending, failed and interrupted messages, oldest first.
+func (s *Store) Unsent() ([]Message, error) {
+	return s.query(`SELECT `+columns+` FROM sent_queue WHERE status != ? ORDER BY created_at, id`, StatusSent)
+}
+
+// Due lists the pending messages whose next attempt is at or before now,
+// oldest first.
+func (s *Store) Due(now time.Time) ([]Message, error) {
+	return s.query(`SELECT `+columns+` FROM sent_queue WHERE status = ? AND next_attempt <= ? ORDER BY created_at, id`,
+		StatusPending, now.UTC())
+}
+
+// Requeue makes a pending, failed or interrupted message due at once with
+// a fresh set of attempts.
+func (s *Store) Requeue(id int64) error {
+	res, err := s.db.Exec(`UPDATE sent_queue SET status = ?, attempts = 0, next_attempt = ? WHERE id = ? AND status != ?`,
+		StatusPending, time.Now().UTC(), id, StatusSent)
+	return oneRow(res, err, id)
+}
+
+// Delete drops a message from the queue.
+func (s *Store) Delete(id int64) error {
+	res, err := s.db.Exec(`DELETE FROM sent_queue WHERE id = ?`, id)
+	return oneRow(res, err, id)
+}
+
+// oneRow turns an update that touched nothing into an error naming id.
+func oneRow(res sql.Result, err error, id int64) error {
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("no unsent message %d in the outbox", id)
+	}
+	return nil
+}
+
+// SendFunc delivers one message.
+type SendFunc func(Message) error
+
+// ErrNotPending is Send finding a message another run is sending, or
+// one an interrupted run left sending, which only Requeue sends again.
+var ErrNotPending = errors.New("not pending")
+
+// Send tries the pending message id now, whether or not it is due, and
+// records the result: sent, pending with the next attempt backed off, or
+// failed once MaxAttempts are used. It is saved as sending first, so
+// neither a second run nor, after a crash, a later one sends it again.
+// Offline nothing is tried, so no attempt is counted. It returns send's
+// error.
+func (s *Store) Send(id int64, now time.Time, send SendFunc) error {
+	m, err := s.ByID(id)
+	if err != nil {
+		return err
+	}
+	switch m.Status {
+	case StatusSent:
+		return fmt.Errorf("message %d was already sent", id)
+	case StatusFailed:
+		return fmt.Errorf("message %d failed for good; requeue it first", id)
+	}
+	res, err := s.db.Exec(`UPDATE sent_queue SET status = ? WHERE id = ? AND status = ?`, StatusSending, id, StatusPending)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("message %d: %w", id, ErrNotPending)
+	}
+	sendErr := send(*m)
+	if errors.Is(sendErr, offline.ErrOffline) {
+		_, err := s.db.Exec(`UPDATE sent_queue SET status = ? WHERE id = ?`, StatusPending, id)
+		return errors.Join(sendErr, err)
+	}
+	if sendErr == nil {
+		_, err = s.db.Exec(`UPDATE sent_queue SET status = ?, attempts = attempts + 1, last_error = '', sent_at = ? WHERE id = ?`,
+			StatusSent, now.UTC(), id)
+		return err
+	}
+	attempts, status := m.Attempts+1, StatusPending
+	if attempts >= MaxAttempts {
+		status = StatusFailed
+	}
+	if _, err := s.db.Exec(`UPDATE sent_queue SET status = ?, attempts = ?, last_error = ?, next_attempt = ? WHERE id = ?`,
+		status, attempts, sendErr.Error(), now.Add(Backoff(attempts)).UTC(), id); err != nil {
+		return err
+	}
+	return sendErr
+}
+
+// Flush sends every message due at now, oldest first. A failed send is
+// recorded and the flush goes on; offline it stops, leaving the rest
+// queued, and returns the offline error. Messages an interrupted run
+// left sending are counted, not sent.
+func (s *Store) Flush(now time.Time, send SendFunc) (Summary, error) {
+	var sum Summary
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM sent_queue WHERE status = ?`, StatusSending).Scan(&sum.Sending); err != nil {
+		return sum, err
+	}
+	due, err := s.Due(now)
+	if err != nil {
+		return sum, err
+	}
+	for _, m := range due {
+		err := s.Send(m.ID, now, send)
+		switch {
+		case errors.Is(err, offline.ErrOffline):
+			return sum, err
+		case errors.Is(err, ErrNotPending):
+			// Another run got to it first.
+		case err == nil:
+			sum.Sent++
+		case m.Attempts+1 >= MaxAttempts:
+			sum.Failed++
+		default:
+			sum.Retrying++
+		}
+	}
+	return sum, nil
+}
+
+func (s *Store) query(q string, args ...any) ([]Message, error) {
+	rows, err := s.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Message
+	for rows.Next() {
+		var m Message
+		var cc string
+		var sent sql.NullTime
+		if err := rows.Scan(&m.ID, &m.JobID, &m.ProfileID, &m.To, &cc, &m.Subject, &m.Body, &m.Status,
+			&m.Attempts, &m.LastError, &m.NextAttempt, &m.CreatedAt, &sent); err != nil {
+			return nil, err
+		}
+		if cc != "" {
+			m.Cc = strings.Split(cc, ",")
+		}
+		if sent.Valid {
+			t := sent.Time
+			m.SentAt = &t
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,216 @@
+package parse
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RequirementKind separates the right to work somewhere from a security
+// clearance.
+type RequirementKind string
+
+const (
+	KindAuthorization RequirementKind = "authorization"
+	KindClearance     RequirementKind = "clearance"
+)
+
+// Requirement is a work authorization or clearance a posting demands of
+// the applicant.
+type Requirement struct {
+	Kind RequirementKind
+	// Region is the region token an authorization is for, such as "US".
+	Region string
+	// Clearance is the level required, or ClearanceAny for an unnamed one.
+	Clearance string
+	// Phrase is the sentence the requirement was found in.
+	Phrase string
+}
+
+// String names the requirement for display: "US work authorization",
+// "TS/SCI clearance".
+func (r Requirement) String() string {
+	if r.Kind == KindClearance {
+		if r.Clearance == ClearanceAny {
+			return "security clearance"
+		}
+		return clearanceNames[r.Clearance] + " clearance"
+	}
+	return r.Region + " work authorization"
+}
+
+// Clearance levels, lowest first within each scheme. ClearanceAny is a
+// posting asking for "a security clearance" without naming one.
+const (
+	ClearanceAny         = "any"
+	ClearancePublicTrust = "public trust"
+	ClearanceSecret      = "secret"
+	ClearanceTopSecret   = "top secret"
+	ClearanceTSSCI       = "ts/sci"
+	ClearanceSC          = "sc"
+	ClearanceDV          = "dv"
+)
+
+// clearanceRank orders levels within the US and UK schemes.
+var clearanceRank = map[string]struct {
+	scheme string
+	rank   int
+}{
+	ClearancePublicTrust: {"us", 1},
+	ClearanceSecret:      {"us", 2},
+	ClearanceTopSecret:   {"us", 3},
+	ClearanceTSSCI:       {"us", 4},
+	ClearanceSC:          {"uk", 1},
+	ClearanceDV:          {"uk", 2},
+}
+
+var clearanceNames = map[string]string{
+	ClearancePublicTrust: "Public Trust",
+	ClearanceSecret:      "Secret",
+	ClearanceTopSecret:   "Top Secret",
+	ClearanceTSSCI:       "TS/SCI",
+	ClearanceSC:          "SC",
+	ClearanceDV:          "DV",
+}
+
+// Clearances lists the levels a profile can declare.
+var Clearances = []string{ClearancePublicTrust, ClearanceSecret, ClearanceTopSecret, ClearanceTSSCI, ClearanceSC, ClearanceDV}
+
+// NormalizeClearance maps how people write a level to one of Clearances,
+// or "" for an unknown one.
+func NormalizeClearance(s string) string {
+	s = strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	switch s {
+	case "ts", "top-secret", "topsecret":
+		return ClearanceTopSecret
+	case "ts-sci", "ts / sci", "top secret/sci", "top secret / sci":
+		return ClearanceTSSCI
+	case "public-trust":
+		return ClearancePublicTrust
+	}
+	if _, ok := clearanceRank[s]; ok {
+		return s
+	}
+	return ""
+}
+
+// ClearanceMeets reports whether holding level held satisfies a posting
+// asking for level need. A higher level in the same scheme counts.
+func ClearanceMeets(held, need string) bool {
+	held = NormalizeClearance(held)
+	if held == "" {
+		return false
+	}
+	if need == ClearanceAny {
+		return true
+	}
+	h, n := clearanceRank[held], clearanceRank[need]
+	return h.scheme == n.scheme && h.rank >= n.rank
+}
+
+// regions maps region tokens to how postings name them. Abbreviations are
+// case-sensitive so "us" the pronoun is not the United States.
+var regions = []struct {
+	token   string
+	pattern *regexp.Regexp
+}{
+	{"US", regexp.MustCompile(`\bU\.S\.(?:A\.)?|\bUSA?\b|(?i:\bunited states\b|\bamerican? citizen)`)},
+	{"UK", regexp.MustCompile(`\bUK\b|\bU\.K\.|(?i:\bunited kingdom\b|\bbritish\b)`)},
+	{"EU", regexp.MustCompile(`\bEU\b|\bEEA\b|(?i:\beuropean union\b|\beuropean economic area\b)`)},
+	{"CA", regexp.MustCompile(`(?i)\bcanad(?:a|ian)\b`)},
+	{"AU", regexp.MustCompile(`(?i)\baustralian?\b`)},
+}
+
+// regionAliases folds the other spellings a profile may use.
+var regionAliases = map[string]string{"USA": "US", "GB": "UK", "EEA": "EU"}
+
+// NormalizeRegion upper-cases a region token and folds aliases.
+func NormalizeRegion(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if alias, ok := regionAliases[s]; ok {
+		return alias
+	}
+	return s
+}
+
+// authCue marks a sentence as being about the right to work, as opposed
+// to where the job is.
+var authCue = regexp.MustCompile(`(?i)\bcitizen(?:s|ship)?\b|\bgreen card\b|\bpermanent residen|\b(?:authori[sz]ed|eligible|permitted|entitled|allowed|legally\s+able)\s+to\s+(?:live\s+and\s+)?work\b|\bright\s+to\s+work\b|\bwork(?:ing)?\s+(?:permit|authori[sz]ation|visa|rights)\b`)
+
+// restrictionCue marks the sentence as a demand rather than a mention.
+var restrictionCue = regexp.MustCompile(`(?i)\bonly\b|\bmust\b|\brequire[sd]?\b|\brequirement\b|\bneeds?\b|\bhave\s+to\b|\bmandatory\b|\bvalid\b|\bexisting\b|\bwithout\b|\bunable\s+to\s+sponsor|\bno\s+(?:visa\s+)?sponsorship`)
+
+// authWaived marks a sentence saying the authorization is not needed.
+var authWaived = regexp.MustCompile(`(?i)\b(?:do|does|did)\s+not\s+(?:require|need)\b|\b(?:don't|doesn't)\s+(?:require|need)\b|\bnot\s+(?:required|necessary|needed)\b|\bregardless\s+of\b|\bwhether\s+or\s+not\b`)
+
+// clearancePattern finds a clearance demand and the level it names.
+var clearancePattern = regexp.MustCompile(`(?i)\b(ts\s*/\s*sci|top[\s-]secret(?:\s*/\s*sci)?|secret|public[\s-]trust|security)\s+clearance\b|\b(SC|DV)\s+(?i:clearance|clear(?:ed)?)\b|(?i:\bclearance)\s*(?:level)?\s*:?\s*(TS/SCI|(?i:top secret|secret))\b`)
+
+// clearanceOptional marks clearances the employer will get the applicant
+// ("ability to obtain") or does not need.
+var clearanceOptional = regexp.MustCompile(`(?i)\b(?:able|ability|eligib\w*|willing(?:ness)?)\s+to\s+(?:obtain|get|acquire|gain|be\s+granted)\b|\bobtainable\b|\bsponsor\w*\b|\bnot\s+(?:required|necessary|needed)\b|\bno\s+(?:\w+\s+)?clearance\b|\bnice\s+to\s+have\b|\bpreferred\b|\ba\s+plus\b`)
+
+// negation in a sponsorship sentence turns an offer into a refusal.
+var negation = regexp.MustCompile(`(?i)\b(?:not|no|unable|cannot|can't|won't|don't|doesn't|does\s+not|do\s+not|never|without|none|nor)\b|\bn't\b`)
+
+// sponsorCue finds sentences about visa sponsorship or relocation help.
+var sponsorCue = regexp.MustCompile(`(?i)\bsponsor(?:s|ed|ing|ship)?\b|\bvisa\s+(?:support|assistance|help)\b|\bhelp\s+(?:you\s+)?with\s+(?:your\s+|the\s+)?visa`)
+
+// OffersSponsorship reports whether text says the employer sponsors visas
+// or helps with them. A sentence that also negates ("we do not sponsor",
+// "without sponsorship") is not an offer.
+func OffersSponsorship(text string) bool {
+	for _, s := range sentences(text) {
+		if sponsorCue.MatchString(s) && !negation.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkRequirements finds the work authorizations and clearances a posting
+// demands. Each sentence is read on its own: an authorization needs a
+// right-to-work phrase, a demand ("only", "must", "required") and a
+// region, and must not waive it ("we do not require"); a clearance needs a level and no "ability to obtain". When the
+// posting offers visa sponsorship, authorizations are not reported.
+// Duplicates are dropped, keeping the first occurrence.
+func WorkRequirements(text string) []Requirement {
+	sponsors := OffersSponsorship(text)
+	var out []Requirement
+	seen := make(map[string]bool)
+	add := func(r Requirement) {
+		if key := r.String(); !seen[key] {
+			seen[key] = true
+			out = append(out, r)
+		}
+	}
+
+	for _, s := range sentences(text) {
+		if !sponsors && authCue.MatchString(s) && restrictionCue.MatchString(s) && !authWaived.MatchString(s) {
+			for _, r := range regions {
+				if r.pattern.MatchString(s) {
+					add(Requirement{Kind: KindAuthorization, Region: r.token, Phrase: s})
+				}
+			}
+		}
+		if m := clearancePattern.FindStringSubmatch(s); m != nil && !clearanceOptional.MatchString(s) {
+			add(Requirement{Kind: KindClearance, Clearance: clearanceLevel(m), Phrase: s})
+		}
+	}
+	return out
+}
+
+// clearanceLevel reads the level from a clearancePattern match.
+func clearanceLevel(m []string) string {
+	for _, g := range m[1:] {
+		if g == "" {
+			continue
+		}
+		if strings.EqualFold(g, "security") {
+			return ClearanceAny
+		}
+		if level := NormalizeClearance(strings.Join(strings.Fields(strings.ReplaceAll(g, "-", " ")), " ")); level != "" {
+			return level
+		}
+	}
+	return ClearanceAny
+}
@@ -0,0 +1,115 @@
+package parse_test
+
+import (
+	"strings"
+	"testing"
+
+	"sprayer/src/api/parse"
+)
+
+func TestWorkRequirements(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string // Requirement.String() values, in order
+	}{
+		// Authorization restrictions.
+		{"US citizens only.", []string{"US work authorization"}},
+		{"This position is open to U.S. citizens only due to contract requirements.", []string{"US work authorization"}},
+		{"Candidates must be authorized to work in the United States.", []string{"US work authorization"}},
+		{"Must be legally authorized to work in the US without sponsorship.", []string{"US work authorization"}},
+		{"Applicants must not now or in the future require sponsorship and must be eligible to work in the USA.", []string{"US work authorization"}},
+		{"Must have EU work permit.", []string{"EU work authorization"}},
+		{"A valid work permit for the European Union is mandatory.", []string{"EU work authorization"}},
+		{"You must have the right to work in the UK.", []string{"UK work authorization"}},
+		{"Unfortunately we are unable to sponsor; candidates need existing right to work in the U.K.", []string{"UK work authorization"}},
+		{"Green card holders or US citizens only, please.", []string{"US work authorization"}},
+		{"Applicants must be Canadian citizens or permanent residents.", []string{"CA work authorization"}},
+		{"Must be eligible to work in Australia.", []string{"AU work authorization"}},
+		{"Must be authorized to work in the US or Canada.", []string{"US work authorization", "CA work authorization"}},
+		{"Requires US citizenship. US citizenship is required for this role.", []string{"US work authorization"}},
+
+		// Clearances.
+		{"Requires active TS/SCI clearance.", []string{"TS/SCI clearance"}},
+		{"Must hold an active Top Secret clearance with polygraph.", []string{"Top Secret clearance"}},
+		{"Active Secret clearance required.", []string{"Secret clearance"}},
+		{"Security clearance required.", []string{"security clearance"}},
+		{"Clearance: TS/SCI", []string{"TS/SCI clearance"}},
+		{"Candidates must hold current SC clearance.", []string{"SC clearance"}},
+		{"DV cleared engineers only.", []string{"DV clearance"}},
+		{"US citizens only; must hold a Secret clearance.", []string{"US work authorization", "Secret clearance"}},
+
+		// Sponsorship offered, or not a restriction: no requirement.
+		{"We sponsor visas.", nil},
+		{"Visa sponsorship is available for the right candidate. Must be authorized to work in the US.", nil},
+		{"Must be authorized to work in the US. We are happy to sponsor H-1B transfers.", nil},
+		{"We offer relocation and visa support to the UK.", nil},
+		{"Candidates who require sponsorship are welcome to apply; US work authorization helps.", nil},
+		{"We do not require US citizenship.", nil},
+		{"We hire regardless of citizenship, US or elsewhere.", nil},
+		{"Our US team works closely with us in the UK.", nil},
+		{"Let us know if you have questions about working in the US.", nil},
+		{"Ability to obtain a Secret clearance.", nil},
+		{"Candidates must be able to obtain a security clearance.", nil},
+		{"No security clearance required.", nil},
+		{"Security clearance not required.", nil},
+		{"Active TS/SCI clearance is a plus.", nil},
+		{"Clearance sponsorship available for TS/SCI clearance.", nil},
+		{"Our office is in the United States.", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, r := range parse.WorkRequirements(tt.text) {
+			got = append(got, r.String())
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%q:\n got  %q\n want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestWorkRequirements_NegatedSponsorshipStillRestricts(t *testing.T) {
+	for _, text := range []string{
+		"Must be authorized to work in the US. We do not sponsor visas.",
+		"Must be authorized to work in the US. Visa sponsorship is not available.",
+		"Must be authorized to work in the US. We cannot offer sponsorship.",
+		"US citizens only. No visa sponsorship.",
+		"Must be authorized to work in the US. Sponsorship: none.",
+	} {
+		if parse.OffersSponsorship(text) {
+			t.Errorf("%q read as offering sponsorship", text)
+		}
+		if rs := parse.WorkRequirements(text); len(rs) != 1 || rs[0].Region != "US" {
+			t.Errorf("%q: requirements %+v, want US authorization", text, rs)
+		}
+	}
+}
+
+func TestWorkRequirements_Phrase(t *testing.T) {
+	rs := parse.WorkRequirements("Great team. Remote within the U.S. Applicants must be U.S. citizens only.\nBenefits")
+	if len(rs) != 1 || rs[0].Phrase != "Remote within the U.S. Applicants must be U.S. citizens only." {
+		t.Errorf("requirements = %+v", rs)
+	}
+}
+
+func TestClearanceMeets(t *testing.T) {
+	tests := []struct {
+		held, need string
+		want       bool
+	}{
+		{"TS/SCI", parse.ClearanceSecret, true},
+		{"top secret", parse.ClearanceTopSecret, true},
+		{"ts", parse.ClearanceTSSCI, false},
+		{"secret", parse.ClearanceTopSecret, false},
+		{"dv", parse.ClearanceSC, true},
+		{"dv", parse.ClearanceSecret, false},
+		{"public trust", parse.ClearanceAny, true},
+		{"", parse.ClearanceAny, false},
+		{"cosmic", parse.ClearanceSecret, false},
+	}
+	for _, tt := range tests {
+		if got := parse.ClearanceMeets(tt.held, tt.need); got != tt.want {
+			t.Errorf("ClearanceMeets(%q, %q) = %v, want %v", tt.held, tt.need, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package parse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Deadline is an application deadline stated in a posting. Sentence is the
+// text it was read from, kept so a questionable match can be checked by eye.
+type Deadline struct {
+	At       time.Time
+	Sentence string
+}
+
+// deadlineCue marks a sentence as talking about the application deadline.
+// Bare "closes" or "due" are too common ("the office closes at 6pm") to
+// count without naming what closes.
+var deadlineCue = regexp.MustCompile(`(?i)\b(?:` +
+	`deadline\b(?:\s+(?:for|to)\s+appl\w*)?(?:\s+is)?\s*:?|` +
+	`apply\s+(?:by|before|until|no\s+later\s+than)|` +
+	`(?:applications?|submissions?|posting|position|role|vacancy|listing|this\s+job)\s+(?:will\s+)?(?:close[sd]?|closing|(?:are|is)\s+due|due|open\s+until|accepted\s+until)|` +
+	`closing\s+date\s*(?:is\s*)?:?|` +
+	`accepting\s+applications\s+(?:until|through)|` +
+	`no\s+later\s+than)`)
+
+// The date must follow the cue closely; anything further is likely another
+// clause.
+const cueReach = 40
+
+var (
+	isoDate    = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	numDate    = regexp.MustCompile(`\b(\d{1,2})[/.](\d{1,2})[/.](\d{4})\b`)
+	monthDay   = regexp.MustCompile(`(?i)\b([a-z]{3,9})\.?\s+(\d{1,2})(?:st|nd|rd|th)?\b(?:,?\s+(\d{4}))?`)
+	dayMonth   = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)?\s+(?:of\s+)?([a-z]{3,9})\b\.?(?:,?\s+(\d{4}))?`)
+	inDuration = regexp.MustCompile(`(?i)\b(?:in|within)\s+(\d{1,2}|one|two|three|four|five|six|seven|ten|fourteen)\s+(day|week)s?\b`)
+	weekdayRef = regexp.MustCompile(`(?i)\b(?:this|next|on|by)?\s*(monday|tuesday|wednesday|thursday|friday|saturday|sunday)\b`)
+	endOfMonth = regexp.MustCompile(`(?i)\b(?:the\s+)?end\s+of\s+(?:the|this)\s+month\b`)
+	tomorrow   = regexp.MustCompile(`(?i)\btomorrow\b`)
+)
+
+var months = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "sept": time.September, "oct": time.October,
+	"nov": time.November, "dec": time.December,
+}
+
+var numberWords = map[string]int{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "ten": 10, "fourteen": 14,
+}
+
+// ExtractDeadline finds the first stated application deadline in text.
+// Relative phrases ("closes in 5 days") and dates without a year are
+// resolved against ref, normally the posting date. The deadline is the
+// end of the named day in ref's location.
+func ExtractDeadline(text string, ref time.Time) (Deadline, bool) {
+	for _, sentence := range sentences(text) {
+		loc := deadlineCue.FindStringIndex(sentence)
+		if loc == nil {
+			continue
+		}
+		rest := sentence[loc[1]:]
+		if len(rest) > cueReach {
+			rest = rest[:cueReach]
+		}
+		if at, ok := resolveDate(rest, ref); ok {
+			return Deadline{At: at, Sentence: sentence}, true
+		}
+	}
+	return Deadline{}, false
+}
+
+// sentences splits on sentence-ending punctuation and line breaks. Dots
+// inside numbers ("30.06.2025") and after dotted abbreviations ("U.S.",
+// "e.g.") do not end a sentence.
+func sentences(text string) []string {
+	var out []string
+	start := 0
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		end := c == '\n'
+		if c == '.' || c == '!' || c == '?' {
+			end = i+1 == len(text) || text[i+1] == ' ' || text[i+1] == '\n'
+			end = end && (c != '.' || !abbreviation(text[start:i]))
+		}
+		if end {
+			if s := strings.TrimSpace(text[start : i+1]); s != "" {
+				out = append(out, s)
+			}
+			start = i + 1
+		}
+	}
+	if s := strings.TrimSpace(text[start:]); s != "" {
+		out = append(out, s)
+	}
+	return out
+}
+
+// abbreviation reports whether text ends in a dotted abbreviation such as
+// "U.S", "e.g" or "Ph.D", whose full stop does not end the sentence.
+func abbreviation(text string) bool {
+	word := text[strings.LastIndexAny(text, " \t\n(")+1:]
+	if !strings.Contains(word, ".") {
+		return false
+	}
+	for _, part := range strings.Split(word, ".") {
+		if len(part) == 0 || len(part) > 2 || strings.IndexFunc(part, func(r rune) bool { return !unicode.IsLetter(r) }) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveDate reads the first date expression in s.
+func resolveDate(s string, ref time.Time) (time.Time, bool) {
+	if m := isoDate.FindStringSubmatch(s); m != nil {
+		return endOfDay(atoi(m[1]), time.Month(atoi(m[2])), atoi(m[3]), ref)
+	}
+	if m := numDate.FindStringSubmatch(s); m != nil {
+		a, b := atoi(m[1]), atoi(m[2])
+		switch {
+		case a > 12 && b <= 12: // 30/06/2025
+			return endOfDay(atoi(m[3]), time.Month(b), a, ref)
+		case b > 12 && a <= 12: // 06/30/2025
+			return endOfDay(atoi(m[3]), time.Month(a), b, ref)
+		}
+		return time.Time{}, false // 05/06/2025 could be either
+	}
+	for _, m := range monthDay.FindAllStringSubmatch(s, -1) {
+		if month, ok := monthOf(m[1]); ok {
+			return dateNear(m[3], month, atoi(m[2]), ref)
+		}
+	}
+	for _, m := range dayMonth.FindAllStringSubmatch(s, -1) {
+		if month, ok := monthOf(m[2]); ok {
+			return dateNear(m[3], month, atoi(m[1]), ref)
+		}
+	}
+	if m := inDuration.FindStringSubmatch(s); m != nil {
+		n, ok := numberWords[strings.ToLower(m[1])]
+		if !ok {
+			n = atoi(m[1])
+		}
+		if strings.EqualFold(m[2], "week") {
+			n *= 7
+		}
+		d := ref.AddDate(0, 0, n)
+		return endOfDay(d.Year(), d.Month(), d.Day(), ref)
+	}
+	if tomorrow.MatchString(s) {
+		d := ref.AddDate(0, 0, 1)
+		return endOfDay(d.Year(), d.Month(), d.Day(), ref)
+	}
+	if endOfMonth.MatchString(s) {
+		d := time.Date(ref.Year(), ref.Month()+1, 0, 0, 0, 0, 0, ref.Location())
+		return endOfDay(d.Year(), d.Month(), d.Day(), ref)
+	}
+	if m := weekdayRef.FindStringSubmatch(s); m != nil {
+		want := weekdays[strings.ToLower(m[1])]
+		days := (int(want) - int(ref.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(m[0])), "next") && days < 7 {
+			days += 7
+		}
+		d := ref.AddDate(0, 0, days)
+		return endOfDay(d.Year(), d.Month(), d.Day(), ref)
+	}
+	return time.Time{}, false
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+	"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+}
+
+// monthOf accepts full names and three-letter abbreviations only, so
+// words like "mayor" or "marching" are not months.
+func monthOf(word string) (time.Month, bool) {
+	w := strings.ToLower(word)
+	if m, ok := months[w]; ok {
+		return m, true
+	}
+	if len(w) < 3 {
+		return 0, false
+	}
+	m, ok := months[w[:3]]
+	if !ok || w != strings.ToLower(m.String()) {
+		return 0, false
+	}
+	return m, true
+}
+
+// dateNear resolves a date without a year to its next occurrence on or
+// after ref.
+func dateNear(year string, month time.Month, day int, ref time.Time) (time.Time, bool) {
+	if year != "" {
+		return endOfDay(atoi(year), month, day, ref)
+	}
+	y := ref.Year()
+	if time.Date(y, month, day, 23, 59, 59, 0, ref.Location()).Before(ref) {
+		y++
+	}
+	return endOfDay(y, month, day, ref)
+}
+
+// endOfDay validates the calendar date and returns its last second.
+func endOfDay(y int, m time.Month, d int, ref time.Time) (time.Time, bool) {
+	t := time.Date(y, m, d, 23, 59, 59, 0, ref.Location())
+	if t.Day() != d || t.Month() != m || y < 2000 || y > 2100 {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
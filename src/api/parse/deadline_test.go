@@ -0,0 +1,80 @@
+package parse_test
+
+import (
+	"testing"
+	"time"
+
+	"sprayer/src/api/parse"
+)
+
+// ref is a Wednesday.
+var ref = time.Date(2025, time.June, 4, 10, 0, 0, 0, time.UTC)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 23, 59, 59, 0, time.UTC)
+}
+
+func TestExtractDeadline(t *testing.T) {
+	tests := []struct {
+		text     string
+		want     time.Time
+		sentence string
+	}{
+		{"We're hiring! Applications close June 30. Remote OK.", day(2025, time.June, 30), "Applications close June 30."},
+		{"Deadline: 2025-07-15", day(2025, time.July, 15), "Deadline: 2025-07-15"},
+		{"Application deadline is 15th July 2025.", day(2025, time.July, 15), "Application deadline is 15th July 2025."},
+		{"Please apply by Friday, July 4th, 2025 with your CV.", day(2025, time.July, 4), "Please apply by Friday, July 4th, 2025 with your CV."},
+		{"Apply before 30/06/2025.", day(2025, time.June, 30), "Apply before 30/06/2025."},
+		{"Apply no later than 06/30/2025", day(2025, time.June, 30), "Apply no later than 06/30/2025"},
+		{"Closing date: 30.06.2025", day(2025, time.June, 30), "Closing date: 30.06.2025"},
+		{"The position closes in 10 days.", day(2025, time.June, 14), "The position closes in 10 days."},
+		{"This role will close within two weeks of posting.", day(2025, time.June, 18), "This role will close within two weeks of posting."},
+		{"Applications are due by the end of the month.", day(2025, time.June, 30), "Applications are due by the end of the month."},
+		{"Applications close this Friday!", day(2025, time.June, 6), "Applications close this Friday!"},
+		{"Applications close next Monday.", day(2025, time.June, 16), "Applications close next Monday."},
+		{"We are accepting applications until Sept 1.", day(2025, time.September, 1), "We are accepting applications until Sept 1."},
+		{"Deadline for applications: 3 Jan", day(2026, time.January, 3), "Deadline for applications: 3 Jan"},
+		{"About us\nThe posting closes tomorrow\nPerks", day(2025, time.June, 5), "The posting closes tomorrow"},
+	}
+	for _, tt := range tests {
+		d, ok := parse.ExtractDeadline(tt.text, ref)
+		if !ok {
+			t.Errorf("%q: no deadline found", tt.text)
+			continue
+		}
+		if !d.At.Equal(tt.want) {
+			t.Errorf("%q: deadline %s, want %s", tt.text, d.At, tt.want)
+		}
+		if d.Sentence != tt.sentence {
+			t.Errorf("%q: sentence %q, want %q", tt.text, d.Sentence, tt.sentence)
+		}
+	}
+}
+
+func TestExtractDeadline_NearMisses(t *testing.T) {
+	for _, text := range []string{
+		"You thrive in a fast-paced, deadline-driven environment since June 2019.",
+		"Comfortable working to tight deadlines; founded March 3, 2015.",
+		"Our office closes at 6pm on Fridays.",
+		"Start date: July 1, 2025.",
+		"Posted June 2 2025. Salary reviewed in 12 months.",
+		"You will close deals by March 31 with enterprise customers.",
+		"Deadline: flexible, we hire on a rolling basis.",
+		"Applications close 05/06/2025.", // day and month are ambiguous
+		"Apply by email to jobs@example.com. We reply within 5 days.",
+		"The role closes the gap between design and engineering, and we meet on Mondays.",
+		"The mayor 12 announced a deadline: soon.",
+	} {
+		if d, ok := parse.ExtractDeadline(text, ref); ok {
+			t.Errorf("%q: unexpected deadline %s from %q", text, d.At, d.Sentence)
+		}
+	}
+}
+
+func TestExtractDeadline_YearRollsForward(t *testing.T) {
+	dec := time.Date(2025, time.December, 20, 9, 0, 0, 0, time.UTC)
+	d, ok := parse.ExtractDeadline("Apply by January 10.", dec)
+	if !ok || !d.At.Equal(day(2026, time.January, 10)) {
+		t.Errorf("got %v %v, want 2026-01-10", d.At, ok)
+	}
+}
@@ -0,0 +1,91 @@
+package parse
+
+import (
+	"strings"
+	"unicode"
+)
+
+// languageWords are common words of each language, function words and
+// the vocabulary of job postings, whose trigrams make up the language's
+// profile.
+var languageWords = []struct {
+	code, words string
+}{
+	{"en", "the and of to in for with you we our are is on as be will your this that team work experience skills role years an or at from have about who us join"},
+	{"de", "der die das und ist wir sie ihr für mit von zu den dem im auf ein eine einer unsere unser sich bei als oder werden du dein deine bieten suchen erfahrung kenntnisse aufgaben stelle ihre nicht"},
+	{"fr", "le la les et des de du un une pour avec vous nous est dans sur en au aux votre vos nos notre qui que être sera équipe poste expérience compétences profil missions"},
+	{"es", "el la los las y de del en para con por que un una es nuestro nuestra su tu se al como equipo experiencia puesto buscamos años conocimientos ofrecemos"},
+	{"pt", "o a os as e de do da dos das em para com por que um uma é nosso nossa seu sua você equipe experiência vaga conhecimento oferecemos não"},
+	{"it", "il lo la le gli e di del della in per con che un una è nostro nostra si al come esperienza lavoro offriamo azienda conoscenza"},
+	{"nl", "de het een en van in voor met je we wij ons onze is op te zijn bij als of jouw ervaring functie werk wat kennis bieden"},
+}
+
+// trigramWeights maps each language to its trigrams. A trigram counts
+// for less the more languages share it.
+var trigramWeights = func() map[string]map[string]float64 {
+	sets := make(map[string]map[string]bool)
+	shared := make(map[string]int)
+	for _, l := range languageWords {
+		set := make(map[string]bool)
+		for _, w := range strings.Fields(l.words) {
+			for _, t := range wordTrigrams(w) {
+				set[t] = true
+			}
+		}
+		for t := range set {
+			shared[t]++
+		}
+		sets[l.code] = set
+	}
+	weights := make(map[string]map[string]float64)
+	for code, set := range sets {
+		weights[code] = make(map[string]float64, len(set))
+		for t := range set {
+			weights[code][t] = 1 / float64(shared[t])
+		}
+	}
+	return weights
+}()
+
+// wordTrigrams returns the trigrams of w padded with a space each side,
+// so that word starts and ends count.
+func wordTrigrams(w string) []string {
+	r := []rune(" " + w + " ")
+	var out []string
+	for i := 0; i+3 <= len(r); i++ {
+		out = append(out, string(r[i:i+3]))
+	}
+	return out
+}
+
+// minTrigrams is the least text DetectLanguage will judge.
+const minTrigrams = 30
+
+// DetectLanguage returns the ISO 639-1 code of the language text is most
+// likely in, among English, German, French, Spanish, Portuguese, Italian
+// and Dutch, or "" when the text is too short to tell.
+func DetectLanguage(text string) string {
+	counts := make(map[string]int)
+	total := 0
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) })
+	for _, w := range words {
+		for _, t := range wordTrigrams(w) {
+			counts[t]++
+			total++
+		}
+	}
+	if total < minTrigrams {
+		return ""
+	}
+	best, bestScore := "", 0.0
+	for _, l := range languageWords {
+		score := 0.0
+		for t, n := range counts {
+			score += float64(n) * trigramWeights[l.code][t]
+		}
+		if score > bestScore {
+			best, bestScore = l.code, score
+		}
+	}
+	return best
+}
@@ -0,0 +1,28 @@
+package parse_test
+
+import (
+	"testing"
+
+	"sprayer/src/api/parse"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"We are looking for a backend engineer to join our team. You will work with Go and Postgres and have at least three years of experience.", "en"},
+		{"Wir suchen eine/n Backend-Entwickler/in für unser Team in Berlin. Du hast Erfahrung mit Go und Kubernetes und bist bereit, Verantwortung zu übernehmen.", "de"},
+		{"Nous recherchons un développeur backend pour rejoindre notre équipe. Vous avez une expérience avec Go et les bases de données.", "fr"},
+		{"Buscamos un desarrollador backend para nuestro equipo. Tienes experiencia con Go y bases de datos y ofrecemos trabajo remoto.", "es"},
+		{"Procuramos uma pessoa desenvolvedora backend para a nossa equipe. Você tem experiência com Go e bancos de dados e não tem medo de desafios.", "pt"},
+		{"Cerchiamo uno sviluppatore backend per il nostro team. Hai esperienza con Go e con le basi di dati della nostra azienda.", "it"},
+		{"Wij zoeken een backend developer voor ons team. Je hebt ervaring met Go en werkt graag samen met de collega's van het platform.", "nl"},
+		{"Senior Software Engineer (m/w/d) Cloud Platform Team. Deine Aufgaben: Design und Development von Microservices mit Go, Kafka und Kubernetes; Code Reviews im Team.", "de"},
+		{"Go, Kubernetes, AWS", ""},
+	}
+	for _, tt := range tests {
+		if got := parse.DetectLanguage(tt.text); got != tt.want {
+			t.Errorf("DetectLanguage(%.40q...) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package parse
+
+import (
+	"regexp"
+	"strings"
+)
+
+// questionsCue introduces a list of questions to answer in the application.
+var questionsCue = regexp.MustCompile(`(?i)(answer|address|respond to)\s+(the\s+)?following|following\s+questions|questions\s*:\s*$|in\s+your\s+(application|cover\s+letter),?\s+please`)
+
+// promptOpener starts an imperative prompt aimed at the applicant.
+var promptOpener = regexp.MustCompile(`(?i)^(describe|tell\s+us|explain|share|walk\s+us\s+through|give\s+(us\s+)?an\s+example|what|why|how|which|have\s+you|when\s+have\s+you)\b`)
+
+// rhetorical questions are marketing copy, not something to answer.
+var rhetorical = regexp.MustCompile(`(?i)sound\s+like\s+you|are\s+you\s+(ready|excited|interested|up\s+for)|interested\?|why\s+(join|work\s+(with|for))\s+us|what\s+(we|you('ll|\s+will))\s+(offer|get|do|love)|what's\s+in\s+it|who\s+are\s+we|ready\s+to`)
+
+var listMarker = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)]|[a-z][.)])\s+`)
+
+// ExtractQuestions finds questions a posting asks applicants to answer:
+// items listed after a cue like "please answer the following", and
+// sentences that prompt the applicant directly ("Describe a time you...").
+// Rhetorical marketing questions are skipped.
+func ExtractQuestions(text string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	add := func(q string) {
+		q = strings.TrimSpace(listMarker.ReplaceAllString(q, ""))
+		key := strings.ToLower(q)
+		if len(strings.Fields(q)) < 4 || len(q) > 300 || rhetorical.MatchString(q) || seen[key] {
+			return
+		}
+		seen[key] = true
+		out = append(out, q)
+	}
+
+	inList := false
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			inList = false
+			continue
+		case questionsCue.MatchString(trimmed):
+			inList = true
+			continue
+		case inList && listMarker.MatchString(line):
+			add(trimmed)
+			continue
+		}
+		inList = false
+		for _, s := range sentences(trimmed) {
+			if isPrompt(listMarker.ReplaceAllString(s, "")) {
+				add(s)
+			}
+		}
+	}
+	return out
+}
+
+// isPrompt reports whether s asks the applicant something: it is
+// addressed to them and opens like a question or instruction.
+func isPrompt(s string) bool {
+	lower := strings.ToLower(s)
+	addressed := strings.Contains(lower, "you") // you, your, you've
+	if !addressed || !promptOpener.MatchString(s) {
+		return false
+	}
+	return strings.HasSuffix(s, "?") || !strings.HasPrefix(lower, "wh") && !strings.HasPrefix(lower, "how")
+}
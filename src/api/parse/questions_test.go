@@ -0,0 +1,41 @@
+package parse_test
+
+import (
+	"reflect"
+	"testing"
+
+	"sprayer/src/api/parse"
+)
+
+func TestExtractQuestions(t *testing.T) {
+	text := `About the role
+We build payment rails. Does this sound like you? Why join us? Are you ready to grow?
+
+What you'll do: own our Go services.
+
+In your application, please answer the following:
+1. Describe a time you led an incident response.
+2. What is your experience with Kafka in production?
+- Salary expectations
+
+Tell us about the most complex system you've designed. We review every application.
+How do you approach code review?
+What we offer? Equity and a laptop.`
+
+	want := []string{
+		"Describe a time you led an incident response.",
+		"What is your experience with Kafka in production?",
+		"Tell us about the most complex system you've designed.",
+		"How do you approach code review?",
+	}
+	if got := parse.ExtractQuestions(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+func TestExtractQuestions_NoneInPlainAd(t *testing.T) {
+	text := "We are a remote-first team. Interested? Send your CV to jobs@example.com.\nWhat we offer: great pay."
+	if got := parse.ExtractQuestions(text); len(got) != 0 {
+		t.Errorf("unexpected questions %q", got)
+	}
+}
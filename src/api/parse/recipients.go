@@ -0,0 +1,73 @@
+package parse
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Role is where an address from a posting belongs on the application.
+type Role string
+
+const (
+	RoleTo Role = "to"
+	RoleCc Role = "cc"
+)
+
+// Recipient is an address found in a posting, with the role its
+// surrounding text gives it and its byte offset in the text.
+type Recipient struct {
+	Address string
+	Role    Role
+	Offset  int
+}
+
+// emailPattern requires a dotted domain ending in letters, so the trailing
+// full stop of a sentence is not taken as part of the address.
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+
+// ccCue in the text just before an address marks it as a copy recipient:
+// "cc maria@x.com", "copying hr@x.com", "with a copy to ...".
+var ccCue = regexp.MustCompile(`(?i)(?:\bcc\b:?|\bcc'?(?:ing|ed)\b|\bcopy(?:ing)?\b(?:\s+(?:to|in))?|\bcarbon\s+copy\b)[^.;\n@]{0,20}$`)
+
+// ccReach is how far before an address a cc cue may sit.
+const ccReach = 30
+
+// denyLocal lists local parts that are never where an application goes.
+var denyLocal = regexp.MustCompile(`(?i)^(?:no-?reply|do-?not-?reply|unsubscribe|mailer-daemon|postmaster|bounces?|abuse|privacy|gdpr|dpo)(?:[+._-].*)?$`)
+
+// Denied reports whether address is an automated or legal mailbox that
+// should never receive an application.
+func Denied(address string) bool {
+	local, _, _ := strings.Cut(address, "@")
+	return denyLocal.MatchString(local)
+}
+
+// ExtractRecipients finds the addresses a posting asks applications to go
+// to. Addresses introduced by "cc" or "copy" are RoleCc, the rest RoleTo.
+// The result lists the To addresses, then the Cc ones, each in text order;
+// duplicates (in any case) and denied mailboxes are dropped. When nothing
+// is RoleTo, the first Cc address is promoted so there is a primary.
+func ExtractRecipients(text string) []Recipient {
+	var to, cc []Recipient
+	seen := make(map[string]bool)
+	for _, loc := range emailPattern.FindAllStringIndex(text, -1) {
+		addr := strings.TrimRight(text[loc[0]:loc[1]], ".-")
+		key := strings.ToLower(addr)
+		if seen[key] || Denied(addr) {
+			continue
+		}
+		seen[key] = true
+
+		r := Recipient{Address: addr, Role: RoleTo, Offset: loc[0]}
+		if ccCue.MatchString(text[max(0, loc[0]-ccReach):loc[0]]) {
+			r.Role = RoleCc
+			cc = append(cc, r)
+			continue
+		}
+		to = append(to, r)
+	}
+	if len(to) == 0 && len(cc) > 0 {
+		cc[0].Role = RoleTo
+	}
+	return append(to, cc...)
+}
@@ -0,0 +1,60 @@
+package parse_test
+
+import (
+	"reflect"
+	"testing"
+
+	"sprayer/src/api/parse"
+)
+
+func TestExtractRecipients(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		text string
+		want []string // "role address"
+	}{
+		{
+			name: "to and cc",
+			text: "Send your CV to jobs@acme.com and cc maria@acme.com.",
+			want: []string{"to jobs@acme.com", "cc maria@acme.com"},
+		},
+		{
+			name: "cc named first",
+			text: "Please copy hr@acme.io on your email to Talent@Acme.io. Questions: tom@acme.io",
+			want: []string{"to Talent@Acme.io", "to tom@acme.io", "cc hr@acme.io"},
+		},
+		{
+			name: "cc colon and duplicates",
+			text: "Apply at jobs@acme.com (CC: lead@acme.com). Again: JOBS@acme.com",
+			want: []string{"to jobs@acme.com", "cc lead@acme.com"},
+		},
+		{
+			name: "only cc is promoted",
+			text: "cc recruiting@acme.co.uk with your portfolio",
+			want: []string{"to recruiting@acme.co.uk"},
+		},
+		{
+			name: "deny list",
+			text: "Email careers@acme.com. Sent from noreply@acme.com; unsubscribe@list.acme.com, no-reply+jobs@acme.com, privacy@acme.com",
+			want: []string{"to careers@acme.com"},
+		},
+		{
+			name: "none",
+			text: "Apply through our portal. Salary 90k@remote is not an address.",
+			want: nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, r := range parse.ExtractRecipients(tc.text) {
+				got = append(got, string(r.Role)+" "+r.Address)
+				if tc.text[r.Offset:r.Offset+len(r.Address)] != r.Address {
+					t.Errorf("%s: offset %d does not point at it", r.Address, r.Offset)
+				}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q\nwant %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package parse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Seniority levels DetectSeniority returns, most junior first.
+// SeniorityUnknown is a posting that names no level and asks for no
+// experience.
+const (
+	SeniorityIntern    = "intern"
+	SeniorityJunior    = "junior"
+	SeniorityMid       = "mid"
+	SenioritySenior    = "senior"
+	SeniorityStaff     = "staff"
+	SeniorityPrincipal = "principal"
+	SeniorityLead      = "lead"
+	SeniorityUnknown   = "unknown"
+)
+
+// SeniorityLevels lists the known levels, for pickers.
+var SeniorityLevels = []string{SeniorityIntern, SeniorityJunior, SeniorityMid, SenioritySenior,
+	SeniorityStaff, SeniorityPrincipal, SeniorityLead}
+
+// seniorityTitles match a level named in a title, as whole words so that
+// "Seniority" and "Internal" do not count.
+var seniorityTitles = []struct {
+	level string
+	re    *regexp.Regexp
+}{
+	{SeniorityIntern, regexp.MustCompile(`(?i)\b(intern|internship|trainee|apprentice|werkstudent)\b`)},
+	{SeniorityJunior, regexp.MustCompile(`(?i)\b(junior|jr|entry[- ]level|graduate|new grad)\b`)},
+	{SeniorityMid, regexp.MustCompile(`(?i)\b(mid([- ]?level|[- ]senior)?|intermediate|(engineer|developer) ii)\b`)},
+	{SenioritySenior, regexp.MustCompile(`(?i)\b(senior|sr|(engineer|developer) iii)\b`)},
+	{SeniorityStaff, regexp.MustCompile(`(?i)\bstaff\b`)},
+	{SeniorityPrincipal, regexp.MustCompile(`(?i)\b(principal|distinguished)\b`)},
+	{SeniorityLead, regexp.MustCompile(`(?i)\b(lead|tech[- ]lead|team[- ]lead|head of)\b`)},
+}
+
+// friendly drops words like "senior-friendly", which describe who else
+// is welcome rather than the role's level.
+var friendly = regexp.MustCompile(`(?i)\b[a-z]+-friendly\b`)
+
+// yearsOfExperience matches "5+ years of experience", "3-5 yrs
+// professional experience", "at least 4 years' experience" and
+// "experience: 2+ years".
+var yearsOfExperience = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(\d{1,2})\s*(?:\+|plus)?\s*(?:(?:-|–|to)\s*\d{1,2}\s*\+?\s*)?(?:years?|yrs?)'?\b[^.\n]{0,40}?\bexperience`),
+	regexp.MustCompile(`(?i)\bexperience\b[^.\n\d]{0,30}?(\d{1,2})\s*(?:\+|plus)?\s*(?:(?:-|–|to)\s*\d{1,2}\s*)?(?:years?|yrs?)\b`),
+}
+
+// DetectSeniority reads a job's level from the level its title names,
+// the first one when it names several, or else from the years of
+// experience its description asks for: under 3 is junior, under 5 mid,
+// more senior.
+func DetectSeniority(title, description string) string {
+	title = friendly.ReplaceAllString(title, "")
+	level, at := SeniorityUnknown, -1
+	for _, t := range seniorityTitles {
+		if loc := t.re.FindStringIndex(title); loc != nil && (at < 0 || loc[0] < at) {
+			level, at = t.level, loc[0]
+		}
+	}
+	if level != SeniorityUnknown {
+		return level
+	}
+
+	years, ok := RequiredYears(description)
+	switch {
+	case !ok:
+		return SeniorityUnknown
+	case years < 3:
+		return SeniorityJunior
+	case years < 5:
+		return SeniorityMid
+	}
+	return SenioritySenior
+}
+
+// RequiredYears returns the years of experience a description first asks
+// for, the lower bound of a range.
+func RequiredYears(description string) (int, bool) {
+	first, years := -1, 0
+	for _, re := range yearsOfExperience {
+		m := re.FindStringSubmatchIndex(description)
+		if m == nil || (first >= 0 && m[0] >= first) {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(description[m[2]:m[3]]))
+		if err != nil {
+			continue
+		}
+		first, years = m[0], n
+	}
+	return years, first >= 0
+}
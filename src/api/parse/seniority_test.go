@@ -0,0 +1,44 @@
+package parse_test
+
+import (
+	"testing"
+
+	"sprayer/src/api/parse"
+)
+
+func TestDetectSeniority(t *testing.T) {
+	tests := []struct {
+		title, description, want string
+	}{
+		{"Senior Go Engineer", "", parse.SenioritySenior},
+		{"Sr. Backend Developer", "", parse.SenioritySenior},
+		{"Software Engineer III", "", parse.SenioritySenior},
+		{"Junior Frontend Developer", "Requires 5+ years of experience.", parse.SeniorityJunior},
+		{"Senior-friendly junior role", "", parse.SeniorityJunior},
+		{"Mid-level Platform Engineer", "", parse.SeniorityMid},
+		{"Staff Engineer", "", parse.SeniorityStaff},
+		{"Principal Architect", "", parse.SeniorityPrincipal},
+		{"Tech Lead, Payments", "", parse.SeniorityLead},
+		{"Senior/Staff Engineer", "", parse.SenioritySenior},
+		{"Software Engineering Intern", "", parse.SeniorityIntern},
+		{"Internal Tools Engineer", "", parse.SeniorityUnknown},
+		{"Backend Engineer", "Seniority: none required. Bring curiosity.", parse.SeniorityUnknown},
+		{"Backend Engineer", "You have 5+ years of professional experience with Go.", parse.SenioritySenior},
+		{"Backend Engineer", "3-5 yrs experience building APIs", parse.SeniorityMid},
+		{"Backend Engineer", "At least 1 year of hands-on experience.", parse.SeniorityJunior},
+		{"Backend Engineer", "Experience: 7+ years", parse.SenioritySenior},
+		{"Backend Engineer", "We have 20 years of history. Apply now.", parse.SeniorityUnknown},
+	}
+	for _, tt := range tests {
+		if got := parse.DetectSeniority(tt.title, tt.description); got != tt.want {
+			t.Errorf("DetectSeniority(%q, %q) = %q, want %q", tt.title, tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestRequiredYears_First(t *testing.T) {
+	years, ok := parse.RequiredYears("2+ years of Go experience. Experience: 8 years with C is a plus.")
+	if !ok || years != 2 {
+		t.Errorf("RequiredYears = %d, %v; want the first mention, 2", years, ok)
+	}
+}
@@ -0,0 +1,127 @@
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// keySection matches the headings of the parts of a posting worth keeping
+// when it has to be cut: what the job is and what it asks for.
+var keySection = regexp.MustCompile(`(?i)require|responsib|qualif|must[\s-]haves?|skills|experience|what (?:you(?:'|’)?ll|you will) (?:do|bring|need)|what we(?:'|’)?re looking for|(?:about|the|your) (?:the |this )?(?:role|position|job)\b|you will\b|you(?:'|’)ll\b|who you are`)
+
+// headingLine is a short line that introduces a section: "Requirements:",
+// "## About the role", "WHAT YOU'LL DO".
+var headingLine = regexp.MustCompile(`^(?:#{1,6}\s+\S.*|[^.!?]{2,60}:|[^a-z.!?]*[A-Z][^a-z.!?]{2,60})$`)
+
+// minKept is the smallest piece of a section worth keeping when the rest
+// does not fit.
+const minKept = 200
+
+// TruncatedNote starts the line TruncateDescription appends, so readers
+// can tell a cut description from a short one.
+const TruncatedNote = "[Description truncated"
+
+const noteFormat = "\n\n%s: %d of %d characters kept. Load the full text to see the rest.]"
+
+// section is a heading and the lines under it; the text before the first
+// heading is a section with no heading.
+type section struct {
+	text string
+	key  bool
+}
+
+func splitSections(text string) []section {
+	var out []section
+	var cur strings.Builder
+	key := false
+	flush := func() {
+		if strings.TrimSpace(cur.String()) != "" {
+			out = append(out, section{text: strings.TrimRight(cur.String(), "\n"), key: key})
+		}
+		cur.Reset()
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if t := strings.TrimSpace(line); t != "" && headingLine.MatchString(t) {
+			flush()
+			key = keySection.MatchString(t)
+		}
+		cur.WriteString(line)
+		cur.WriteByte('\n')
+	}
+	flush()
+	return out
+}
+
+// TruncateDescription cuts text to at most limit bytes, reporting whether
+// it had to. The requirements and responsibilities sections are kept
+// first, then the opening, then the rest in order, each whole when it
+// fits and cut at a paragraph, sentence or word otherwise. Kept sections
+// stay in their original order and a note saying how much was kept ends
+// the result.
+func TruncateDescription(text string, limit int) (string, bool) {
+	if len(text) <= limit {
+		return text, false
+	}
+	total := utf8.RuneCountInString(text)
+	// Reserve the note at its longest: the kept count has as many digits
+	// as the total at most.
+	budget := limit - len(fmt.Sprintf(noteFormat, TruncatedNote, total, total))
+	sections := splitSections(text)
+	kept := make([]string, len(sections))
+
+	take := func(i int) {
+		if kept[i] != "" || budget < minKept {
+			return
+		}
+		s := sections[i].text
+		if len(s)+1 > budget {
+			s = cut(s, budget-1)
+		}
+		kept[i] = s
+		budget -= len(s) + 1
+	}
+	for i, s := range sections {
+		if s.key {
+			take(i)
+		}
+	}
+	if len(sections) > 0 {
+		take(0)
+	}
+	for i := range sections {
+		take(i)
+	}
+
+	var b strings.Builder
+	for _, s := range kept {
+		if s != "" {
+			if b.Len() > 0 {
+				b.WriteByte('\n')
+			}
+			b.WriteString(s)
+		}
+	}
+	fmt.Fprintf(&b, noteFormat, TruncatedNote, utf8.RuneCountInString(b.String()), total)
+	return b.String(), true
+}
+
+// cut shortens s to at most n bytes at the last paragraph break, sentence
+// end or space, marking the cut with an ellipsis.
+func cut(s string, n int) string {
+	n -= len("…")
+	if n <= 0 {
+		return ""
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	head := s[:n]
+	for _, sep := range []string{"\n\n", "\n", ". ", " "} {
+		if i := strings.LastIndex(head, sep); i > n/2 {
+			return strings.TrimRight(head[:i+len(sep)], " \n") + "…"
+		}
+	}
+	return head + "…"
+}
@@ -0,0 +1,63 @@
+package parse_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"sprayer/src/api/parse"
+)
+
+func filler(word string, n int) string {
+	return strings.TrimSpace(strings.Repeat(word+" ", n))
+}
+
+func TestTruncateDescription_KeepsKeySections(t *testing.T) {
+	requirements := "Requirements:\n- 5 years of Go\n- Kubernetes in production\n- Clear writing"
+	responsibilities := "## What you'll do\nDesign and run the billing platform."
+	text := strings.Join([]string{
+		"Acme builds payment rails for small shops.",
+		"OUR HISTORY\n" + filler("Founded long ago, we grew.", 400),
+		requirements,
+		"Perks:\n" + filler("Free snacks and naps.", 400),
+		responsibilities,
+	}, "\n")
+
+	got, cut := parse.TruncateDescription(text, 2000)
+	if !cut {
+		t.Fatal("oversized text was not cut")
+	}
+	if len(got) > 2000 {
+		t.Errorf("result is %d bytes, over the 2000 limit", len(got))
+	}
+	for _, want := range []string{requirements, responsibilities, "Acme builds payment rails"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("result lost %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, requirements) > strings.Index(got, responsibilities) {
+		t.Error("kept sections are out of their original order")
+	}
+	if !strings.Contains(got, parse.TruncatedNote) || !strings.HasSuffix(got, "the rest.]") {
+		t.Errorf("result does not end with the truncation note:\n%s", got[len(got)-200:])
+	}
+}
+
+func TestTruncateDescription_CutsAtWords(t *testing.T) {
+	text := filler("naïve café", 3000)
+	got, cut := parse.TruncateDescription(text, 1000)
+	if !cut || len(got) > 1000 || !utf8.ValidString(got) {
+		t.Fatalf("cut=%v len=%d valid=%v", cut, len(got), utf8.ValidString(got))
+	}
+	body, _, _ := strings.Cut(got, "\n\n"+parse.TruncatedNote)
+	if !strings.HasSuffix(body, "café…") && !strings.HasSuffix(body, "naïve…") {
+		t.Errorf("cut mid-word: %q", body[len(body)-20:])
+	}
+}
+
+func TestTruncateDescription_ShortTextUntouched(t *testing.T) {
+	text := "Requirements:\n- Go"
+	if got, cut := parse.TruncateDescription(text, 100); cut || got != text {
+		t.Errorf("TruncateDescription = %q, %v", got, cut)
+	}
+}
@@ -0,0 +1,49 @@
+package power
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// System returns the Detector for this machine, reading the battery from
+// pmset. macOS keeps Low Data Mode out of networksetup, so metering is
+// reported as unknown.
+func System() Detector {
+	return Darwin{Batt: func() ([]byte, error) {
+		return exec.Command("pmset", "-g", "batt").Output()
+	}}
+}
+
+// Darwin detects state from `pmset -g batt`.
+type Darwin struct {
+	Batt func() ([]byte, error)
+}
+
+// Detect runs pmset; on failure the state is unknown.
+func (d Darwin) Detect() State {
+	out, err := d.Batt()
+	if err != nil {
+		return State{}
+	}
+	return parsePmset(string(out))
+}
+
+var pmsetPercent = regexp.MustCompile(`(\d+)%`)
+
+// parsePmset reads output such as:
+//
+//	Now drawing from 'Battery Power'
+//	 -InternalBattery-0 (id=1234)	15%; discharging; 1:02 remaining present: true
+func parsePmset(out string) State {
+	var s State
+	m := pmsetPercent.FindStringSubmatch(out)
+	if m == nil {
+		return s
+	}
+	s.Battery = true
+	s.Percent, _ = strconv.Atoi(m[1])
+	s.Discharging = strings.Contains(out, "'Battery Power'")
+	return s
+}
@@ -0,0 +1,93 @@
+package power
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// System returns the Detector for this machine: sysfs for the battery
+// and NetworkManager for metering.
+func System() Detector {
+	return Linux{
+		SupplyDir: "/sys/class/power_supply",
+		Metering: func() ([]byte, error) {
+			return exec.Command("nmcli", "-t", "-g", "GENERAL.METERED", "device", "show").Output()
+		},
+	}
+}
+
+// Linux detects state from sysfs and nmcli. Either source may be
+// missing; what it would have reported stays unknown.
+type Linux struct {
+	SupplyDir string
+	Metering  func() ([]byte, error)
+}
+
+// Detect reads every supply under SupplyDir and asks NetworkManager
+// whether any device is metered.
+func (l Linux) Detect() State {
+	var s State
+	entries, _ := os.ReadDir(l.SupplyDir)
+	var total, batteries int
+	mains := false
+	for _, e := range entries {
+		dir := filepath.Join(l.SupplyDir, e.Name())
+		switch readAttr(dir, "type") {
+		case "Battery":
+			if readAttr(dir, "present") == "0" {
+				continue
+			}
+			pct, err := strconv.Atoi(readAttr(dir, "capacity"))
+			if err != nil {
+				continue
+			}
+			batteries++
+			total += pct
+			if readAttr(dir, "status") == "Discharging" {
+				s.Discharging = true
+			}
+		case "Mains", "USB":
+			if readAttr(dir, "online") == "1" {
+				mains = true
+			}
+		}
+	}
+	if batteries > 0 {
+		s.Battery = true
+		s.Percent = total / batteries
+		s.Discharging = s.Discharging && !mains
+	}
+
+	if l.Metering != nil {
+		if out, err := l.Metering(); err == nil {
+			s.Metered, s.MeteredKnown = parseNMMetered(string(out))
+		}
+	}
+	return s
+}
+
+func readAttr(dir, name string) string {
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// parseNMMetered reads nmcli's per-device GENERAL.METERED values: "yes",
+// "no", "yes (guessed)", "no (guessed)" or "unknown". Any yes means the
+// traffic may go over a metered link.
+func parseNMMetered(out string) (metered, known bool) {
+	for _, line := range strings.Split(out, "\n") {
+		switch v := strings.TrimSpace(line); {
+		case strings.HasPrefix(v, "yes"):
+			return true, true
+		case strings.HasPrefix(v, "no"):
+			known = true
+		}
+	}
+	return false, known
+}
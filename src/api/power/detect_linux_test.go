@@ -0,0 +1,61 @@
+package power
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeSupply writes a power_supply entry with the given attributes.
+func fakeSupply(t *testing.T, root, name string, attrs map[string]string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range attrs {
+		if err := os.WriteFile(filepath.Join(dir, k), []byte(v+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func nmcli(out string) func() ([]byte, error) {
+	return func() ([]byte, error) { return []byte(out), nil }
+}
+
+func TestLinux_Detect(t *testing.T) {
+	root := t.TempDir()
+	fakeSupply(t, root, "AC", map[string]string{"type": "Mains", "online": "0"})
+	fakeSupply(t, root, "BAT0", map[string]string{"type": "Battery", "present": "1", "capacity": "14", "status": "Discharging"})
+	fakeSupply(t, root, "BAT1", map[string]string{"type": "Battery", "present": "0"})
+	fakeSupply(t, root, "hidpp_battery_0", map[string]string{"type": "Battery", "capacity_level": "Full"})
+
+	l := Linux{SupplyDir: root, Metering: nmcli("unknown\n\nno (guessed)\n\nyes (guessed)\n")}
+	want := State{Battery: true, Discharging: true, Percent: 14, Metered: true, MeteredKnown: true}
+	if got := l.Detect(); got != want {
+		t.Errorf("Detect = %+v, want %+v", got, want)
+	}
+
+	// Plugged in: the battery may report Discharging briefly, but mains wins.
+	fakeSupply(t, root, "AC", map[string]string{"online": "1"})
+	l.Metering = nmcli("no\n")
+	want = State{Battery: true, Percent: 14, MeteredKnown: true}
+	if got := l.Detect(); got != want {
+		t.Errorf("on mains: Detect = %+v, want %+v", got, want)
+	}
+}
+
+func TestLinux_DetectNothing(t *testing.T) {
+	l := Linux{
+		SupplyDir: filepath.Join(t.TempDir(), "missing"),
+		Metering:  func() ([]byte, error) { return nil, errors.New("nmcli: not found") },
+	}
+	if got := l.Detect(); got != (State{}) {
+		t.Errorf("Detect = %+v, want the unknown state", got)
+	}
+	if d := Decide(l.Detect(), Config{LowBattery: 20, PauseOnMetered: true}); d.Constrained() {
+		t.Errorf("unknown state constrains: %+v", d)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package power
+
+// System returns a Detector that reports nothing, so nothing is held
+// back on platforms without detection.
+func System() Detector {
+	return Fixed{}
+}
@@ -0,0 +1,148 @@
+// Package power reports whether the machine is on a low battery or a
+// metered connection, and decides what sprayer should hold back when it
+// is. Detection sits behind Detector so the decisions can be tested
+// without the hardware.
+package power
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// State is what a Detector saw. Fields a platform cannot report stay at
+// their zero value, which never constrains anything.
+type State struct {
+	Battery     bool // a battery is present
+	Discharging bool // running from it rather than mains
+	Percent     int  // charge, 0-100, when Battery
+
+	Metered      bool
+	MeteredKnown bool
+}
+
+func (s State) String() string {
+	var parts []string
+	switch {
+	case !s.Battery:
+		parts = append(parts, "no battery")
+	case s.Discharging:
+		parts = append(parts, fmt.Sprintf("on battery, %d%%", s.Percent))
+	default:
+		parts = append(parts, fmt.Sprintf("charging, %d%%", s.Percent))
+	}
+	switch {
+	case !s.MeteredKnown:
+		parts = append(parts, "metering unknown")
+	case s.Metered:
+		parts = append(parts, "metered network")
+	default:
+		parts = append(parts, "unmetered network")
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Detector reads the current power and network state.
+type Detector interface {
+	Detect() State
+}
+
+// Fixed is a Detector that always reports itself, for tests and for
+// platforms with nothing to detect.
+type Fixed State
+
+// Detect returns s.
+func (s Fixed) Detect() State { return State(s) }
+
+// EnvLowBattery is the charge, in percent, below which a discharging
+// battery counts as low. 0 disables the check.
+const EnvLowBattery = "SPRAYER_LOW_BATTERY"
+
+// EnvMetered set to "ignore" stops a metered connection from pausing work.
+const EnvMetered = "SPRAYER_METERED"
+
+// DefaultLowBattery is the low-battery threshold when EnvLowBattery is
+// unset.
+const DefaultLowBattery = 20
+
+// Config holds the thresholds Decide applies.
+type Config struct {
+	LowBattery     int
+	PauseOnMetered bool
+}
+
+// ConfigFromEnv reads Config from EnvLowBattery and EnvMetered.
+func ConfigFromEnv() Config {
+	c := Config{LowBattery: DefaultLowBattery, PauseOnMetered: true}
+	if n, err := strconv.Atoi(os.Getenv(EnvLowBattery)); err == nil && n >= 0 && n <= 100 {
+		c.LowBattery = n
+	}
+	if strings.EqualFold(os.Getenv(EnvMetered), "ignore") {
+		c.PauseOnMetered = false
+	}
+	return c
+}
+
+// StretchFactor is how much longer periodic work waits between runs
+// while constrained.
+const StretchFactor = 4
+
+// Decision is what to hold back in a given state.
+type Decision struct {
+	LowBattery bool
+	Metered    bool
+}
+
+// Decide applies c to s.
+func Decide(s State, c Config) Decision {
+	return Decision{
+		LowBattery: s.Battery && s.Discharging && s.Percent < c.LowBattery,
+		Metered:    c.PauseOnMetered && s.MeteredKnown && s.Metered,
+	}
+}
+
+// Constrained reports whether anything should be held back.
+func (d Decision) Constrained() bool { return d.LowBattery || d.Metered }
+
+// ConfirmScrape reports whether a new scrape needs the user's go-ahead.
+func (d Decision) ConfirmScrape() bool { return d.Constrained() }
+
+// PauseBackground reports whether work nobody asked for just now, such as
+// prefetching and polling for replies, should wait.
+func (d Decision) PauseBackground() bool { return d.Constrained() }
+
+// Interval stretches the wait between periodic runs while constrained.
+func (d Decision) Interval(base time.Duration) time.Duration {
+	if d.Constrained() {
+		return base * StretchFactor
+	}
+	return base
+}
+
+// Reason says why work is held back, or "" when it is not.
+func (d Decision) Reason() string {
+	switch {
+	case d.LowBattery && d.Metered:
+		return "battery low and connection metered"
+	case d.LowBattery:
+		return "battery low"
+	case d.Metered:
+		return "connection metered"
+	}
+	return ""
+}
+
+// Glyph is the status-bar marker for d, "" when unconstrained.
+func (d Decision) Glyph() string {
+	switch {
+	case d.LowBattery && d.Metered:
+		return "▁⇅"
+	case d.LowBattery:
+		return "▁"
+	case d.Metered:
+		return "⇅"
+	}
+	return ""
+}
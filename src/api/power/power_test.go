@@ -0,0 +1,65 @@
+package power
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecide(t *testing.T) {
+	cfg := Config{LowBattery: 20, PauseOnMetered: true}
+	tests := []struct {
+		name  string
+		state State
+		cfg   Config
+		want  Decision
+	}{
+		{"desktop", State{}, cfg, Decision{}},
+		{"charging low", State{Battery: true, Percent: 5}, cfg, Decision{}},
+		{"discharging full", State{Battery: true, Discharging: true, Percent: 80}, cfg, Decision{}},
+		{"discharging low", State{Battery: true, Discharging: true, Percent: 19}, cfg, Decision{LowBattery: true}},
+		{"at threshold", State{Battery: true, Discharging: true, Percent: 20}, cfg, Decision{}},
+		{"battery check off", State{Battery: true, Discharging: true, Percent: 3}, Config{PauseOnMetered: true}, Decision{}},
+		{"metered", State{Metered: true, MeteredKnown: true}, cfg, Decision{Metered: true}},
+		{"metered ignored", State{Metered: true, MeteredKnown: true}, Config{LowBattery: 20}, Decision{}},
+		{"both", State{Battery: true, Discharging: true, Percent: 10, Metered: true, MeteredKnown: true}, cfg,
+			Decision{LowBattery: true, Metered: true}},
+	}
+	for _, tt := range tests {
+		if got := Decide(tt.state, tt.cfg); got != tt.want {
+			t.Errorf("%s: Decide = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecision_Effects(t *testing.T) {
+	free, tethered := Decision{}, Decision{Metered: true}
+	if free.ConfirmScrape() || free.PauseBackground() || free.Glyph() != "" || free.Reason() != "" {
+		t.Errorf("unconstrained decision holds something back: %+v", free)
+	}
+	if !tethered.ConfirmScrape() || !tethered.PauseBackground() || tethered.Glyph() == "" {
+		t.Errorf("metered decision holds nothing back")
+	}
+	if got := free.Interval(time.Hour); got != time.Hour {
+		t.Errorf("free interval = %v", got)
+	}
+	if got := tethered.Interval(time.Hour); got != StretchFactor*time.Hour {
+		t.Errorf("stretched interval = %v", got)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvLowBattery, "")
+	t.Setenv(EnvMetered, "")
+	if got := ConfigFromEnv(); got != (Config{LowBattery: DefaultLowBattery, PauseOnMetered: true}) {
+		t.Errorf("defaults = %+v", got)
+	}
+	t.Setenv(EnvLowBattery, "35")
+	t.Setenv(EnvMetered, "Ignore")
+	if got := ConfigFromEnv(); got != (Config{LowBattery: 35}) {
+		t.Errorf("configured = %+v", got)
+	}
+	t.Setenv(EnvLowBattery, "150")
+	if got := ConfigFromEnv(); got.LowBattery != DefaultLowBattery {
+		t.Errorf("out-of-range threshold accepted: %+v", got)
+	}
+}
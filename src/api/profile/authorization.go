@@ -0,0 +1,51 @@
+package profile
+
+import (
+	"slices"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/parse"
+)
+
+// RequirementPenalty is taken off the score of a job whose posting demands
+// a work authorization or clearance the profile does not hold.
+const RequirementPenalty = 40
+
+// UnmetRequirements lists what j's posting demands that p does not hold.
+// Authorizations are only checked once the profile declares some, since
+// an empty list means "not filled in" rather than "none"; an empty
+// clearance does mean none.
+func (p *Profile) UnmetRequirements(j *job.Job) []parse.Requirement {
+	var unmet []parse.Requirement
+	for _, r := range parse.WorkRequirements(j.Description) {
+		if !p.meets(r) {
+			unmet = append(unmet, r)
+		}
+	}
+	return unmet
+}
+
+func (p *Profile) meets(r parse.Requirement) bool {
+	if r.Kind == parse.KindClearance {
+		return parse.ClearanceMeets(p.Clearance, r.Clearance)
+	}
+	if len(p.WorkAuthorizations) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(p.WorkAuthorizations, func(a string) bool {
+		return parse.NormalizeRegion(a) == r.Region
+	})
+}
+
+// ExcludeUnmet drops jobs demanding an authorization or clearance p lacks.
+func (p *Profile) ExcludeUnmet() job.Filter {
+	return func(jobs []job.Job) []job.Job {
+		var out []job.Job
+		for i := range jobs {
+			if len(p.UnmetRequirements(&jobs[i])) == 0 {
+				out = append(out, jobs[i])
+			}
+		}
+		return out
+	}
+}
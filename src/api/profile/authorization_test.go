@@ -0,0 +1,65 @@
+package profile_test
+
+import (
+	"strings"
+	"testing"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+func TestUnmetRequirements(t *testing.T) {
+	usOnly := job.Job{ID: "us", Description: "Great team. US citizens only."}
+	ts := job.Job{ID: "ts", Description: "Requires active TS/SCI clearance."}
+	sponsored := job.Job{ID: "sp", Description: "Must be authorized to work in the US. We sponsor visas."}
+	open := job.Job{ID: "open", Description: "Remote anywhere."}
+
+	tests := []struct {
+		name  string
+		p     profile.Profile
+		unmet map[string]string
+	}{
+		{"undeclared authorizations are not checked", profile.Profile{},
+			map[string]string{"ts": "TS/SCI clearance"}},
+		{"EU holder", profile.Profile{WorkAuthorizations: []string{"eu"}},
+			map[string]string{"us": "US work authorization", "ts": "TS/SCI clearance"}},
+		{"US holder with a lower clearance", profile.Profile{WorkAuthorizations: []string{"USA"}, Clearance: "secret"},
+			map[string]string{"ts": "TS/SCI clearance"}},
+		{"fully cleared", profile.Profile{WorkAuthorizations: []string{"US"}, Clearance: "TS/SCI"},
+			map[string]string{}},
+	}
+	for _, tt := range tests {
+		for _, j := range []job.Job{usOnly, ts, sponsored, open} {
+			var got []string
+			for _, r := range tt.p.UnmetRequirements(&j) {
+				got = append(got, r.String())
+			}
+			if strings.Join(got, "|") != tt.unmet[j.ID] {
+				t.Errorf("%s, job %s: unmet %q, want %q", tt.name, j.ID, got, tt.unmet[j.ID])
+			}
+		}
+	}
+}
+
+func TestExplain_RequirementPenalty(t *testing.T) {
+	p := profile.Profile{WorkAuthorizations: []string{"EU"}}
+	j := job.Job{Title: "Engineer", Description: "Must be authorized to work in the United States."}
+
+	b := p.Explain(&j)
+	if b.RequirementDelta != -profile.RequirementPenalty || b.Total != 50-profile.RequirementPenalty {
+		t.Errorf("breakdown = %+v", b)
+	}
+	lines := strings.Join(b.Lines(), "\n")
+	if !strings.Contains(lines, "requires: US work authorization — not in your profile") {
+		t.Errorf("breakdown lines:\n%s", lines)
+	}
+
+	p.ExcludeUnmetRequirements = true
+	if b := p.Explain(&j); b.RequirementDelta != 0 || len(b.Unmet) != 1 {
+		t.Errorf("excluding profile breakdown = %+v, want the requirement named but not penalized", b)
+	}
+	other := job.Job{ID: "eu", Description: "Must have EU work permit."}
+	if got := p.ExcludeUnmet()([]job.Job{j, other}); len(got) != 1 || got[0].ID != "eu" {
+		t.Errorf("ExcludeUnmet kept %+v", got)
+	}
+}
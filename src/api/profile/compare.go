@@ -0,0 +1,100 @@
+package profile
+
+import (
+	"context"
+	"sort"
+
+	"sprayer/src/api/job"
+)
+
+// Evaluation is how a job fares against one profile.
+type Evaluation struct {
+	ProfileID string `json:"profile_id"`
+	Score     int    `json:"score"`   // ScoreJob's score for the profile
+	Matches   bool   `json:"matches"` // the job passes the profile's filters
+}
+
+// Evaluate scores j for p and runs it through p's filters. The filters
+// see a copy of j scored for p, so its score range applies to that score,
+// and j itself is left as it is.
+func (p *Profile) Evaluate(j job.Job) Evaluation {
+	j.Score = p.ScoreJob(&j)
+	passed := job.Pipe(p.GenerateFilters()...)([]job.Job{j})
+	return Evaluation{ProfileID: p.ID, Score: j.Score, Matches: len(passed) > 0}
+}
+
+// Compared is a job with its evaluation against each profile compared,
+// in the order the profiles were given.
+type Compared struct {
+	job.Job
+	Profiles []Evaluation `json:"profiles"`
+}
+
+// Matched returns the IDs of the profiles the job matches.
+func (c Compared) Matched() []string {
+	var ids []string
+	for _, e := range c.Profiles {
+		if e.Matches {
+			ids = append(ids, e.ProfileID)
+		}
+	}
+	return ids
+}
+
+// Compare evaluates jobs against profiles. sets[i] is the jobs as
+// profiles[i] sees them, with its triage state; a job missing from a set,
+// hidden or archived there, does not match that profile. The jobs at
+// least one profile matches are returned, highest scored for
+// profiles[by] first.
+func Compare(profiles []Profile, sets [][]job.Job, by int) []Compared {
+	var out []Compared
+	var in [][]bool // in[n][i]: out[n] is in sets[i]
+	index := make(map[string]int)
+	for i, set := range sets {
+		for _, j := range set {
+			at, ok := index[j.ID]
+			if !ok {
+				at = len(out)
+				index[j.ID] = at
+				out = append(out, Compared{Job: j, Profiles: make([]Evaluation, len(profiles))})
+				in = append(in, make([]bool, len(profiles)))
+			}
+			out[at].Profiles[i], in[at][i] = profiles[i].Evaluate(j), true
+		}
+	}
+	kept := out[:0]
+	for n, c := range out {
+		for i := range profiles {
+			if !in[n][i] {
+				c.Profiles[i] = Evaluation{ProfileID: profiles[i].ID, Score: profiles[i].ScoreJob(&c.Job)}
+			}
+		}
+		if len(c.Matched()) > 0 {
+			kept = append(kept, c)
+		}
+	}
+	SortCompared(kept, by)
+	return kept
+}
+
+// SortCompared orders jobs by their score for the by'th profile, highest
+// first, keeping the order of ties.
+func SortCompared(jobs []Compared, by int) {
+	sort.SliceStable(jobs, func(a, b int) bool {
+		return jobs[a].Profiles[by].Score > jobs[b].Profiles[by].Score
+	})
+}
+
+// CompareStored compares the profiles on their active jobs in s,
+// abandoning the queries if ctx is cancelled.
+func CompareStored(ctx context.Context, s *job.Store, profiles []Profile, by int, opts ...job.ActiveOption) ([]Compared, error) {
+	sets := make([][]job.Job, len(profiles))
+	for i, p := range profiles {
+		jobs, err := s.ForProfileContext(ctx, p.ID, opts...)
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = jobs
+	}
+	return Compare(profiles, sets, by), nil
+}
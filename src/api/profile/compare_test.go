@@ -0,0 +1,49 @@
+package profile
+
+import (
+	"strings"
+	"testing"
+
+	"sprayer/src/api/job"
+)
+
+func TestCompare(t *testing.T) {
+	backend := Profile{ID: "backend", Keywords: []string{"go"}, MaxScore: 100, ScoringWeights: DefaultScoringWeights()}
+	compilers := Profile{ID: "compilers", Keywords: []string{"llvm", "compiler"}, MinScore: 50, MaxScore: 100,
+		PreferredTech: []string{"llvm"}, ScoringWeights: DefaultScoringWeights()}
+	jobs := []job.Job{
+		{ID: "api", Title: "Go API engineer", Description: "Postgres", Score: 7},
+		{ID: "llvm", Title: "Compiler engineer", Description: "LLVM and Go", Score: 7},
+		{ID: "front", Title: "React developer", Score: 7},
+		{ID: "gc", Title: "Go compiler engineer", Description: "the Go toolchain", Score: 7},
+	}
+	// compilers hid "api": it is still scored for them, but cannot match.
+	got := Compare([]Profile{backend, compilers}, [][]job.Job{jobs, {jobs[1], jobs[2], jobs[3]}}, 1)
+
+	var rows []string
+	for _, c := range got {
+		rows = append(rows, c.ID+":"+strings.Join(c.Matched(), "+"))
+	}
+	// "gc" matches compilers' keywords but not their technology; ties
+	// keep the loaded order.
+	if want := "llvm:backend+compilers api:backend gc:backend"; strings.Join(rows, " ") != want {
+		t.Errorf("compared %q, want %q", strings.Join(rows, " "), want)
+	}
+	if e := got[0].Profiles; e[0] != (Evaluation{"backend", 50, true}) || e[1] != (Evaluation{"compilers", 100, true}) {
+		t.Errorf("llvm evaluations %+v", e)
+	}
+	if e := got[1].Profiles[1]; e != (Evaluation{"compilers", 0, false}) {
+		t.Errorf("api for compilers %+v", e)
+	}
+	for _, j := range jobs {
+		if j.Score != 7 {
+			t.Errorf("%s score changed to %d", j.ID, j.Score)
+		}
+	}
+
+	got[0].Profiles[0].Score = 10
+	SortCompared(got, 0)
+	if got[0].ID != "api" || got[2].ID != "llvm" {
+		t.Errorf("by backend: %s, %s, %s", got[0].ID, got[1].ID, got[2].ID)
+	}
+}
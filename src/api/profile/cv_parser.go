@@ -2,11 +2,12 @@ package profile
 
 import (
 	"fmt"
-	"io/ioutil"
 	"regexp"
 	"strings"
 	"time"
+	"unicode"
 
+	"sprayer/src/api/doctext"
 	"sprayer/src/api/job"
 )
 
@@ -139,17 +140,16 @@ func (p *CVParser) ParseCVFromText(text string) (*CVData, error) {
 		Languages:    []string{},
 	}
 
-	// Extract technologies
-	for _, pattern := range p.techPatterns {
-		if matches := pattern.FindAllString(text, -1); len(matches) > 0 {
-			for _, match := range matches {
-				tech := strings.ToLower(match)
-				if !contains(cv.Technologies, tech) {
-					cv.Technologies = append(cv.Technologies, tech)
-				}
-			}
-		}
-	}
+	cv.Name = cvName(text)
+	cv.Email = cvEmail.FindString(text)
+	cv.Phone = cvPhone(text)
+
+	cv.Technologies = p.technologies(text)
+
+	sections := splitSections(text)
+	cv.Summary = strings.Join(strings.Fields(strings.Join(sections[sectionSummary], " ")), " ")
+	cv.Experience = p.parseExperience(sections[sectionExperience])
+	cv.Education = p.parseEducation(sections[sectionEducation])
 
 	// Extract skills
 	for _, pattern := range p.skillPatterns {
@@ -178,14 +178,67 @@ func (p *CVParser) ParseCVFromText(text string) (*CVData, error) {
 	return cv, nil
 }
 
-// ParseCVFromFile reads and parses a CV file
+// ParseCVFromFile reads and parses a CV file: a PDF, a .docx or text.
+// A PDF that is encrypted or scanned is an error, not an empty CVData.
 func (p *CVParser) ParseCVFromFile(filepath string) (*CVData, error) {
-	content, err := ioutil.ReadFile(filepath)
+	text, err := doctext.ExtractFile(filepath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read CV file: %w", err)
 	}
 
-	return p.ParseCVFromText(string(content))
+	return p.ParseCVFromText(text)
+}
+
+var (
+	cvEmail     = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	cvPhoneLike = regexp.MustCompile(`\+?\(?\d[\d ().-]{6,}\d`)
+	cvYear      = regexp.MustCompile(`\b(19|20)\d\d\b`)
+	cvNotName   = regexp.MustCompile(`(?i)^(curriculum vitae|resume|résumé|cv)$`)
+)
+
+// cvPhone is the first run of 8 to 15 digits, with the spaces, dots,
+// dashes and brackets phone numbers are written with, that is not a span
+// of dates such as 01.2019 - 03.2021.
+func cvPhone(text string) string {
+	for _, m := range cvPhoneLike.FindAllString(text, -1) {
+		n := 0
+		for _, r := range m {
+			if r >= '0' && r <= '9' {
+				n++
+			}
+		}
+		if n >= 8 && n <= 15 && len(cvYear.FindAllString(m, 2)) < 2 {
+			return strings.TrimSpace(m)
+		}
+	}
+	return ""
+}
+
+// cvName takes the name from the top of a CV: the first of its first
+// lines made of two to four capitalised words and nothing else.
+func cvName(text string) string {
+	lines := strings.Split(text, "\n")
+	for _, line := range lines[:min(len(lines), 6)] {
+		line = strings.TrimSpace(line)
+		words := strings.Fields(line)
+		if len(words) < 2 || len(words) > 4 || len(line) > 50 || cvNotName.MatchString(line) {
+			continue
+		}
+		name := true
+		for _, w := range words {
+			r := []rune(w)
+			if !unicode.IsUpper(r[0]) || strings.IndexFunc(w, func(r rune) bool {
+				return !unicode.IsLetter(r) && r != '-' && r != '\'' && r != '.'
+			}) >= 0 {
+				name = false
+				break
+			}
+		}
+		if name {
+			return line
+		}
+	}
+	return ""
 }
 
 // GenerateProfileFromCV creates a profile from CV data
@@ -0,0 +1,157 @@
+package profile
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/doctext"
+)
+
+func TestParseCVFromText_Contact(t *testing.T) {
+	tests := []struct {
+		text               string
+		name, email, phone string
+	}{
+		{"Jane Doe\nSenior Go Engineer\njane.doe@example.com | +44 20 7946 0958\n",
+			"Jane Doe", "jane.doe@example.com", "+44 20 7946 0958"},
+		{"CURRICULUM VITAE\nCurriculum Vitae\nJosé María García-López\nMadrid · (555) 123-4567\nAcme 01.2019 - 03.2021",
+			"José María García-López", "", "(555) 123-4567"},
+		{"Experience\n2015 - 2019 Globex, go and rust\nAcme 01.2019 - 03.2021", "", "", ""},
+	}
+	for _, tt := range tests {
+		cv, err := NewCVParser().ParseCVFromText(tt.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cv.Name != tt.name || cv.Email != tt.email || cv.Phone != tt.phone {
+			t.Errorf("%q: got %q, %q, %q; want %q, %q, %q", tt.text, cv.Name, cv.Email, cv.Phone, tt.name, tt.email, tt.phone)
+		}
+	}
+}
+
+func TestImportProfileFromCV_DOCX(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resume.docx")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	w, _ := zw.Create("word/document.xml")
+	w.Write([]byte(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Jane Doe</w:t></w:r></w:p>
+<w:p><w:r><w:t>jane@example.com</w:t></w:r></w:p>
+<w:p><w:r><w:t>Built Go services on Kubernetes and Postgres.</w:t></w:r></w:p>
+</w:body></w:document>`))
+	zw.Close()
+	f.Close()
+
+	p, err := NewProfileImporter().ImportProfileFromCV(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Jane Doe" || p.ContactEmail != "jane@example.com" || p.CVPath != path {
+		t.Errorf("profile %q, email %q, CV %q", p.Name, p.ContactEmail, p.CVPath)
+	}
+	if got := strings.Join(p.Keywords, ","); got != "go,postgres,kubernetes" {
+		t.Errorf("keywords %s", got)
+	}
+
+	if p, _ := NewProfileImporter().ImportProfileFromCV(path, "Me"); p.Name != "Me" {
+		t.Errorf("name given, profile is %q", p.Name)
+	}
+}
+
+func TestImportProfileFromCV_ScannedPDF(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.pdf")
+	os.WriteFile(path, []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n"+
+		"2 0 obj\n<< /Type /Pages /Kids [3 0 R] /Count 1 >>\nendobj\n"+
+		"3 0 obj\n<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>\nendobj\n"+
+		"4 0 obj\n<< /Length 24 >>\nstream\nq 612 0 0 792 0 0 cm /Im1 Do Q\nendstream\nendobj\n"), 0644)
+
+	_, err := NewProfileImporter().ImportProfileFromCV(path, "Me")
+	if !errors.Is(err, doctext.ErrNoText) {
+		t.Errorf("scanned PDF: %v", err)
+	}
+}
+
+func TestParseCVFromText_Sections(t *testing.T) {
+	text := `Jane Doe
+jane@example.com
+
+Summary
+Backend engineer who likes
+small services.
+
+Work Experience
+Senior Go Engineer — Acme Corp
+Jan 2020 – Present
+- Built billing services in Go on Kubernetes.
+- Moved the queues to Redis.
+
+Globex
+Software Developer
+03/2017 - 12/2019
+Maintained a Django monolith on Postgres.
+
+Education:
+BSc in Computer Science, University of Lisbon, 2013 - 2016
+
+Skills
+Go, Python, Docker`
+
+	cv, err := NewCVParser().ParseCVFromText(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cv.Summary != "Backend engineer who likes small services." {
+		t.Errorf("summary %q", cv.Summary)
+	}
+	want := []Experience{
+		{Company: "Acme Corp", Title: "Senior Go Engineer", Duration: "Jan 2020 - Present",
+			Description:  "- Built billing services in Go on Kubernetes.\n- Moved the queues to Redis.",
+			Technologies: []string{"go", "redis", "kubernetes"}},
+		{Company: "Globex", Title: "Software Developer", Duration: "03/2017 - 12/2019",
+			Description:  "Maintained a Django monolith on Postgres.",
+			Technologies: []string{"django", "postgres"}},
+	}
+	if len(cv.Experience) != len(want) {
+		t.Fatalf("experience %+v", cv.Experience)
+	}
+	for i, x := range cv.Experience {
+		w := want[i]
+		if x.Company != w.Company || x.Title != w.Title || x.Duration != w.Duration || x.Description != w.Description ||
+			strings.Join(x.Technologies, ",") != strings.Join(w.Technologies, ",") {
+			t.Errorf("experience %d:\n got %+v\nwant %+v", i, x, w)
+		}
+	}
+	school := Education{Institution: "University of Lisbon", Degree: "BSc", Field: "Computer Science", Year: "2016"}
+	if len(cv.Education) != 1 || cv.Education[0] != school {
+		t.Errorf("education %+v, want %+v", cv.Education, school)
+	}
+}
+
+func TestParseCVFromText_SectionsInline(t *testing.T) {
+	text := "EXPERIENCE\nBackend Engineer at Initech (2015 to 2019)\nRust and AWS.\n" +
+		"WORK HISTORY\nHooli, Lead Developer, 2019-2021\n" +
+		"EDUCATION\nMIT\nPhD, 2014\nState College\nDiploma"
+
+	cv, _ := NewCVParser().ParseCVFromText(text)
+	if len(cv.Experience) != 2 {
+		t.Fatalf("experience %+v", cv.Experience)
+	}
+	if x := cv.Experience[0]; x.Title != "Backend Engineer" || x.Company != "Initech" || x.Duration != "2015 - 2019" ||
+		strings.Join(x.Technologies, ",") != "rust,aws" {
+		t.Errorf("first job %+v", x)
+	}
+	if x := cv.Experience[1]; x.Title != "Lead Developer" || x.Company != "Hooli" || x.Duration != "2019 - 2021" {
+		t.Errorf("second job %+v", x)
+	}
+	// An undated school is not an entry; only years split the section.
+	if len(cv.Education) != 1 || cv.Education[0] != (Education{Institution: "MIT", Degree: "PhD", Year: "2014"}) {
+		t.Errorf("education %+v", cv.Education)
+	}
+}
@@ -0,0 +1,248 @@
+package profile
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CV sections, by the headers that open them. A header is a short line of
+// its own, in any case, with or without a trailing colon.
+const (
+	sectionExperience = "experience"
+	sectionEducation  = "education"
+	sectionSummary    = "summary"
+	sectionOther      = "other"
+)
+
+var sectionHeaders = map[string]string{
+	"experience":              sectionExperience,
+	"work experience":         sectionExperience,
+	"professional experience": sectionExperience,
+	"relevant experience":     sectionExperience,
+	"work history":            sectionExperience,
+	"employment":              sectionExperience,
+	"employment history":      sectionExperience,
+	"career history":          sectionExperience,
+	"education":               sectionEducation,
+	"education and training":  sectionEducation,
+	"academic background":     sectionEducation,
+	"summary":                 sectionSummary,
+	"profile":                 sectionSummary,
+	"professional summary":    sectionSummary,
+	"about me":                sectionSummary,
+	"skills":                  sectionOther,
+	"technical skills":        sectionOther,
+	"languages":               sectionOther,
+	"projects":                sectionOther,
+	"certifications":          sectionOther,
+	"certificates":            sectionOther,
+	"publications":            sectionOther,
+	"awards":                  sectionOther,
+	"interests":               sectionOther,
+	"volunteering":            sectionOther,
+	"references":              sectionOther,
+	"contact":                 sectionOther,
+}
+
+const (
+	cvMonth = `(?:jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?`
+	cvPoint = `(?:` + cvMonth + `\s+|\d{1,2}[/.])?(?:19|20)\d\d`
+)
+
+var (
+	// cvDateRange matches "Jan 2020 - Present", "03/2018 – 12/2019" or
+	// "2015 to 2019".
+	cvDateRange = regexp.MustCompile(`(?i)` + cvPoint + `\s*(?:-|–|—|to|until)\s*(?:` + cvPoint + `|present|current|now|today)\b`)
+	cvBullet    = regexp.MustCompile(`^[-•*▪◦‣·–]\s*`)
+	cvRangeDash = regexp.MustCompile(`(?i)\s*(?:–|—|\bto\b|\buntil\b|-)\s*`)
+	cvSplit     = regexp.MustCompile(`\s+(?:at|@|—|–|-|\|)\s+|\s*[,|·•]\s*`)
+
+	cvTitleWords = regexp.MustCompile(`(?i)\b(engineer|developer|programmer|manager|lead|intern|architect|consultant|analyst|designer|scientist|director|head|cto|ceo|founder|administrator|specialist|officer|sre|devops|tester|qa|owner)\b`)
+	cvSchool     = regexp.MustCompile(`(?i)(universit|college|school|institut|academy|polytechnic|hochschule|école|ecole|universidad|faculty)`)
+	cvDegree     = regexp.MustCompile(`(?i)^(bachelor|master|b\.?sc|m\.?sc|b\.?a\.?|m\.?a\.?|b\.?s\.?|m\.?s\.?|ph\.?d|doctor|diploma|mba|b\.?eng|m\.?eng|associate|licenciatura|certificate)`)
+	cvDegreeOf   = regexp.MustCompile(`(?i)^(.*?)\s+(?:in|of)\s+(.+)$`)
+)
+
+// splitSections groups the lines of a CV under the section each header
+// opens. Lines before the first header are left out.
+func splitSections(text string) map[string][]string {
+	out := make(map[string][]string)
+	current := ""
+	for _, line := range strings.Split(text, "\n") {
+		key := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(line), ":"))
+		if s, ok := sectionHeaders[key]; ok {
+			current = s
+			continue
+		}
+		if current != "" {
+			out[current] = append(out[current], strings.TrimSpace(line))
+		}
+	}
+	return out
+}
+
+// cvEntry is the lines of one job or school: those naming it, its dates
+// and those describing it.
+type cvEntry struct {
+	header []string
+	dates  string
+	body   []string
+}
+
+// headerLike tells a line naming a job or school from one describing it:
+// short, not a bullet and not a sentence.
+func headerLike(line string) bool {
+	return line != "" && len(line) <= 80 && !cvBullet.MatchString(line) && !strings.HasSuffix(line, ".")
+}
+
+// entries splits a section at the lines dated by match. Each entry takes
+// up to two header-like lines above its date and the lines below it up
+// to the next entry.
+func entries(lines []string, match func(string) string) []cvEntry {
+	var anchors []int
+	for i, l := range lines {
+		if match(l) != "" {
+			anchors = append(anchors, i)
+		}
+	}
+	var out []cvEntry
+	for n, at := range anchors {
+		start, prev := at, -1
+		if n > 0 {
+			prev = anchors[n-1]
+		}
+		for start > prev+1 && start > at-2 && headerLike(lines[start-1]) {
+			start--
+		}
+		if n > 0 {
+			// The lines above this entry's start end the previous one.
+			out[n-1].body = lines[prev+1 : start]
+		}
+		dates := match(lines[at])
+		e := cvEntry{header: append([]string(nil), lines[start:at]...), dates: dates}
+		if rest := strings.TrimSpace(strings.Replace(lines[at], dates, "", 1)); rest != "" {
+			e.header = append(e.header, strings.Trim(rest, " ()[],|–—-"))
+		}
+		out = append(out, e)
+	}
+	if len(out) > 0 {
+		out[len(out)-1].body = lines[anchors[len(anchors)-1]+1:]
+	}
+	return out
+}
+
+// fields splits an entry's header lines at "at", dashes, bars and commas.
+func (e cvEntry) fields() []string {
+	var out []string
+	for _, h := range e.header {
+		for _, f := range cvSplit.Split(h, -1) {
+			if f = strings.Trim(f, " ()[]"); f != "" {
+				out = append(out, f)
+			}
+		}
+	}
+	return out
+}
+
+// description is the body without blank lines, bullets as "- ".
+func (e cvEntry) description() string {
+	var lines []string
+	for _, l := range e.body {
+		if l == "" {
+			continue
+		}
+		if cvBullet.MatchString(l) {
+			l = highlightBullet + cvBullet.ReplaceAllString(l, "")
+		}
+		lines = append(lines, l)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e cvEntry) text() string {
+	return strings.Join(append(append(append([]string(nil), e.header...), e.dates), e.body...), "\n")
+}
+
+// normalizeRange writes a date range with a plain dash between its ends.
+func normalizeRange(r string) string {
+	return strings.Join(strings.Fields(cvRangeDash.ReplaceAllString(r, " - ")), " ")
+}
+
+// parseExperience reads the jobs of an experience section. The field of
+// the header naming a role is the title and the other the company;
+// otherwise the first is the title, as in "Engineer at Acme".
+func (p *CVParser) parseExperience(lines []string) []Experience {
+	var out []Experience
+	for _, e := range entries(lines, func(l string) string { return cvDateRange.FindString(l) }) {
+		x := Experience{Duration: normalizeRange(e.dates), Description: e.description(), Technologies: p.technologies(e.text())}
+		f := e.fields()
+		switch {
+		case len(f) >= 2 && !cvTitleWords.MatchString(f[0]) && cvTitleWords.MatchString(f[1]):
+			x.Title, x.Company = f[1], f[0]
+		case len(f) >= 2:
+			x.Title, x.Company = f[0], f[1]
+		case len(f) == 1:
+			x.Title = f[0]
+		}
+		out = append(out, x)
+	}
+	return out
+}
+
+// parseEducation reads the schools of an education section, dated by a
+// range or a single year: the field naming a school is the institution,
+// one starting with a degree the degree, split at "in" or "of" into the
+// degree and its field.
+func (p *CVParser) parseEducation(lines []string) []Education {
+	match := func(l string) string {
+		if r := cvDateRange.FindString(l); r != "" {
+			return r
+		}
+		return cvYear.FindString(l)
+	}
+	var out []Education
+	for _, e := range entries(lines, match) {
+		ed := Education{}
+		if years := cvYear.FindAllString(e.dates, -1); len(years) > 0 {
+			ed.Year = years[len(years)-1]
+		}
+		if ed.Year == "" || strings.Contains(strings.ToLower(e.dates), "present") {
+			ed.Year = strings.TrimSpace(e.dates)
+		}
+		var rest []string
+		for _, f := range e.fields() {
+			switch {
+			case ed.Institution == "" && cvSchool.MatchString(f):
+				ed.Institution = f
+			case ed.Degree == "" && cvDegree.MatchString(f):
+				ed.Degree = f
+				if m := cvDegreeOf.FindStringSubmatch(f); m != nil {
+					ed.Degree, ed.Field = m[1], m[2]
+				}
+			default:
+				rest = append(rest, f)
+			}
+		}
+		if ed.Institution == "" && len(rest) > 0 {
+			ed.Institution, rest = rest[0], rest[1:]
+		}
+		if ed.Degree == "" && len(rest) > 0 {
+			ed.Degree = rest[0]
+		}
+		out = append(out, ed)
+	}
+	return out
+}
+
+// technologies are the technologies text names, lower-cased, once each.
+func (p *CVParser) technologies(text string) []string {
+	var out []string
+	for _, pattern := range p.techPatterns {
+		for _, m := range pattern.FindAllString(text, -1) {
+			if t := strings.ToLower(m); !contains(out, t) {
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
@@ -60,22 +60,66 @@ func (pi *ProfileImporter) ImportProfile(filepath string, format ImportFormat) (
 	}
 }
 
-// ImportProfileFromCV imports a profile from a CV file
+// ImportProfileFromCV imports a profile from a CV file: a PDF, a .docx
+// or text. The profile is named name, or else the name on the CV, and
+// applies with the CV and the email on it.
 func (pi *ProfileImporter) ImportProfileFromCV(cvPath string, name string) (Profile, error) {
 	cvData, err := pi.parser.ParseCVFromFile(cvPath)
 	if err != nil {
 		return Profile{}, fmt.Errorf("failed to parse CV: %w", err)
 	}
+	if name == "" {
+		name = cvData.Name
+	}
+	if name == "" {
+		return Profile{}, fmt.Errorf("no name found on the CV; give the profile one")
+	}
 
 	profile := GenerateProfileFromCV(cvData, name)
 	profile.CVData = cvData
+	profile.CVPath = cvPath
+	profile.ContactEmail = cvData.Email
 	profile.CVMinScore = 20 // Default minimum CV score
 
 	return profile, nil
 }
 
-// ExportProfile exports a profile to various formats
-func (pi *ProfileImporter) ExportProfile(profile Profile, filepath string, format ImportFormat) error {
+// ExportMode chooses how much of a profile ExportProfile writes.
+type ExportMode string
+
+const (
+	// ExportAll writes the profile as it is stored.
+	ExportAll ExportMode = ""
+	// ExportStripped leaves out what is personal, keeping the filters and
+	// weights, to share the profile as a template.
+	ExportStripped ExportMode = "strip-personal"
+	// ExportWithCV inlines the parsed CV, reading the CV file when the
+	// profile holds none, for a full backup.
+	ExportWithCV ExportMode = "include-cv"
+)
+
+// ExportProfile exports a profile to various formats; with no format
+// given, the one of the file extension, JSON by default.
+func (pi *ProfileImporter) ExportProfile(profile Profile, filepath string, format ImportFormat, mode ExportMode) error {
+	switch mode {
+	case ExportStripped:
+		profile = StripPersonal(profile)
+	case ExportWithCV:
+		if profile.CVData == nil && profile.CVPath != "" {
+			cv, err := pi.parser.ParseCVFromFile(profile.CVPath)
+			if err != nil {
+				return fmt.Errorf("include CV: %w", err)
+			}
+			profile.CVData = cv
+		}
+	}
+	if format == "" {
+		switch strings.ToLower(strings.TrimPrefix(getFileExt(filepath), ".")) {
+		case "yaml", "yml":
+			format = FormatYAML
+		}
+	}
+
 	var content []byte
 	var err error
 
@@ -95,6 +139,26 @@ func (pi *ProfileImporter) ExportProfile(profile Profile, filepath string, forma
 	return ioutil.WriteFile(filepath, content, 0644)
 }
 
+// StripPersonal returns p without what identifies its owner: the contact
+// email, the CV and cover letter paths and the parsed CV.
+func StripPersonal(p Profile) Profile {
+	p.ContactEmail, p.CVPath, p.CoverPath, p.CVData = "", "", "", nil
+	return p
+}
+
+// MissingPersonal names the personal fields p has empty, as a stripped
+// export leaves them, that applying needs filled in.
+func (p Profile) MissingPersonal() []string {
+	var missing []string
+	if p.ContactEmail == "" {
+		missing = append(missing, "contact email")
+	}
+	if p.CVPath == "" {
+		missing = append(missing, "CV")
+	}
+	return missing
+}
+
 // ValidateProfile validates imported profile data
 func (pi *ProfileImporter) ValidateProfile(profile Profile) error {
 	if profile.Name == "" {
@@ -120,8 +184,12 @@ func (pi *ProfileImporter) ValidateProfile(profile Profile) error {
 	return nil
 }
 
-// importFromJSON imports from JSON format
+// importFromJSON imports from JSON format. JSON Resume documents are
+// detected and converted.
 func (pi *ProfileImporter) importFromJSON(content []byte) (Profile, error) {
+	if IsJSONResume(content) {
+		return pi.importFromJSONResume(content)
+	}
 	var profile Profile
 	err := json.Unmarshal(content, &profile)
 	if err != nil {
@@ -135,6 +203,23 @@ func (pi *ProfileImporter) importFromJSON(content []byte) (Profile, error) {
 	return profile, nil
 }
 
+// importFromJSONResume builds a profile around a JSON Resume document.
+func (pi *ProfileImporter) importFromJSONResume(content []byte) (Profile, error) {
+	var r Resume
+	if err := json.Unmarshal(content, &r); err != nil {
+		return Profile{}, fmt.Errorf("failed to parse JSON Resume: %w", err)
+	}
+	cv := FromJSONResume(r)
+	profile := GenerateProfileFromCV(cv, cv.Name)
+	profile.CVData = cv
+	profile.ContactEmail = cv.Email
+
+	if err := pi.ValidateProfile(profile); err != nil {
+		return Profile{}, fmt.Errorf("invalid profile: %w", err)
+	}
+	return profile, nil
+}
+
 // importFromYAML imports from YAML format
 func (pi *ProfileImporter) importFromYAML(content []byte) (Profile, error) {
 	var profile Profile
@@ -0,0 +1,77 @@
+package profile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"sprayer/src/api/job"
+)
+
+// equivalent compares profiles as YAML writes them, an empty list and
+// none alike.
+func equivalent(a, b Profile) bool {
+	ya, _ := yaml.Marshal(a)
+	yb, _ := yaml.Marshal(b)
+	return string(ya) == string(yb)
+}
+
+func TestExportProfile_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	cvPath := filepath.Join(dir, "cv.txt")
+	os.WriteFile(cvPath, []byte("Jane Doe\njane@example.com\nGo and Postgres.\n"), 0644)
+	after := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	p := Profile{
+		ID: "backend", Name: "Backend", Keywords: []string{"go"}, ContactEmail: "jane@example.com",
+		CVPath: cvPath, CoverPath: filepath.Join(dir, "cover.txt"), PreferRemote: true,
+		MinScore: 40, MaxScore: 100, TrapSeverity: job.TrapSeverity("high"),
+		SalaryRange:    SalaryRange{Min: 90000, Currency: "EUR"},
+		SourceWeights:  map[string]int{"Greenhouse": 10},
+		HideStatuses:   []job.Status{"rejected"},
+		PostedAfter:    &after,
+		ScoringWeights: DefaultScoringWeights(),
+	}
+	pi := NewProfileImporter()
+
+	for _, name := range []string{"backup.json", "backup.yaml"} {
+		path := filepath.Join(dir, name)
+		if err := pi.ExportProfile(p, path, "", ExportWithCV); err != nil {
+			t.Fatal(err)
+		}
+		got, err := pi.ImportProfile(path, "")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got.CVData == nil || got.CVData.Email != "jane@example.com" {
+			t.Errorf("%s: CV not inlined: %+v", name, got.CVData)
+		}
+		want := p
+		want.CVData = got.CVData
+		if !equivalent(got, want) {
+			t.Errorf("%s round trip:\n got %+v\nwant %+v", name, got, want)
+		}
+	}
+
+	path := filepath.Join(dir, "share.json")
+	if err := pi.ExportProfile(p, path, FormatJSON, ExportStripped); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "jane") || strings.Contains(string(data), dir) {
+		t.Errorf("stripped export keeps personal data:\n%s", data)
+	}
+	got, err := pi.ImportProfile(path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equivalent(got, StripPersonal(p)) {
+		t.Errorf("stripped round trip:\n got %+v\nwant %+v", got, StripPersonal(p))
+	}
+	if m := strings.Join(got.MissingPersonal(), ", "); m != "contact email, CV" {
+		t.Errorf("missing %q", m)
+	}
+}
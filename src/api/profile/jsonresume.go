@@ -0,0 +1,339 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// JSONResumeSchema is the schema URL written into exported documents.
+const JSONResumeSchema = "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json"
+
+// Resume is the subset of the JSON Resume schema (jsonresume.org) that
+// CVData can represent.
+type Resume struct {
+	Schema    string           `json:"$schema,omitempty"`
+	Basics    ResumeBasics     `json:"basics"`
+	Work      []ResumeWork     `json:"work,omitempty"`
+	Education []ResumeSchool   `json:"education,omitempty"`
+	Skills    []ResumeSkill    `json:"skills,omitempty"`
+	Languages []ResumeLanguage `json:"languages,omitempty"`
+}
+
+type ResumeBasics struct {
+	Name     string          `json:"name"`
+	Label    string          `json:"label,omitempty"`
+	Email    string          `json:"email,omitempty"`
+	Phone    string          `json:"phone,omitempty"`
+	Summary  string          `json:"summary,omitempty"`
+	Location *ResumeLocation `json:"location,omitempty"`
+}
+
+type ResumeLocation struct {
+	City        string `json:"city,omitempty"`
+	Region      string `json:"region,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+type ResumeWork struct {
+	Name       string   `json:"name"`
+	Position   string   `json:"position"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+type ResumeSchool struct {
+	Institution string `json:"institution"`
+	Area        string `json:"area,omitempty"`
+	StudyType   string `json:"studyType,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	EndDate     string `json:"endDate,omitempty"`
+}
+
+type ResumeSkill struct {
+	Name     string   `json:"name"`
+	Level    string   `json:"level,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+type ResumeLanguage struct {
+	Language string `json:"language"`
+	Fluency  string `json:"fluency,omitempty"`
+}
+
+// Work highlights are kept in Experience.Description as bullet lines, and
+// per-job technologies travel as one highlight with this prefix.
+const (
+	highlightBullet = "- "
+	techHighlight   = "Technologies: "
+)
+
+// technologiesSkill names the skill group CVData.Technologies is exported as.
+const technologiesSkill = "Technologies"
+
+// ToJSONResume converts the profile's CV to a JSON Resume document. The
+// profile's contact email, when set, is the one published.
+func ToJSONResume(p Profile) Resume {
+	cv := p.CVData
+	if cv == nil {
+		cv = &CVData{}
+	}
+	r := Resume{
+		Schema: JSONResumeSchema,
+		Basics: ResumeBasics{
+			Name:    firstNonEmpty(cv.Name, p.Name),
+			Label:   cv.Title,
+			Email:   firstNonEmpty(p.ContactEmail, cv.Email),
+			Phone:   cv.Phone,
+			Summary: cv.Summary,
+		},
+	}
+	if cv.Location != "" {
+		r.Basics.Location = splitLocation(cv.Location)
+	}
+	for _, e := range cv.Experience {
+		start, end := splitDuration(e.Duration)
+		summary, highlights := splitHighlights(e.Description)
+		if len(e.Technologies) > 0 {
+			highlights = append(highlights, techHighlight+strings.Join(e.Technologies, ", "))
+		}
+		r.Work = append(r.Work, ResumeWork{
+			Name: e.Company, Position: e.Title, StartDate: start, EndDate: end,
+			Summary: summary, Highlights: highlights,
+		})
+	}
+	for _, e := range cv.Education {
+		r.Education = append(r.Education, ResumeSchool{
+			Institution: e.Institution, Area: e.Field, StudyType: e.Degree, EndDate: resumeDate(e.Year),
+		})
+	}
+	if len(cv.Technologies) > 0 {
+		r.Skills = append(r.Skills, ResumeSkill{Name: technologiesSkill, Keywords: cv.Technologies})
+	}
+	for _, s := range cv.Skills {
+		r.Skills = append(r.Skills, ResumeSkill{Name: s})
+	}
+	for _, l := range cv.Languages {
+		r.Languages = append(r.Languages, ResumeLanguage{Language: l})
+	}
+	return r
+}
+
+// FromJSONResume maps a JSON Resume document onto CVData. Skill names
+// become skills and their keywords technologies.
+func FromJSONResume(r Resume) *CVData {
+	cv := &CVData{
+		Name:    r.Basics.Name,
+		Email:   r.Basics.Email,
+		Phone:   r.Basics.Phone,
+		Title:   r.Basics.Label,
+		Summary: r.Basics.Summary,
+	}
+	if l := r.Basics.Location; l != nil {
+		var parts []string
+		for _, s := range []string{l.City, l.Region, l.CountryCode} {
+			if s != "" {
+				parts = append(parts, s)
+			}
+		}
+		cv.Location = strings.Join(parts, ", ")
+	}
+	for _, w := range r.Work {
+		e := Experience{Company: w.Name, Title: w.Position, Duration: joinDuration(w.StartDate, w.EndDate)}
+		lines := []string{w.Summary}
+		for _, h := range w.Highlights {
+			if techs, ok := strings.CutPrefix(h, techHighlight); ok {
+				e.Technologies = append(e.Technologies, strings.Split(techs, ", ")...)
+				continue
+			}
+			lines = append(lines, highlightBullet+h)
+		}
+		e.Description = strings.TrimSpace(strings.Join(lines, "\n"))
+		cv.Experience = append(cv.Experience, e)
+	}
+	for _, e := range r.Education {
+		cv.Education = append(cv.Education, Education{
+			Institution: e.Institution, Degree: e.StudyType, Field: e.Area, Year: yearOf(e.EndDate),
+		})
+	}
+	for _, s := range r.Skills {
+		if !strings.EqualFold(s.Name, technologiesSkill) {
+			cv.Skills = append(cv.Skills, s.Name)
+		}
+		for _, kw := range s.Keywords {
+			cv.Technologies = append(cv.Technologies, strings.ToLower(kw))
+		}
+	}
+	for _, l := range r.Languages {
+		cv.Languages = append(cv.Languages, l.Language)
+	}
+	return cv
+}
+
+// WriteJSONResume writes p's CV as an indented JSON Resume document.
+func WriteJSONResume(w io.Writer, p Profile) error {
+	r := ToJSONResume(p)
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// IsJSONResume reports whether content looks like a JSON Resume document:
+// it names the schema or carries a basics object.
+func IsJSONResume(content []byte) bool {
+	var probe struct {
+		Schema string          `json:"$schema"`
+		Basics json.RawMessage `json:"basics"`
+	}
+	if json.Unmarshal(content, &probe) != nil {
+		return false
+	}
+	return strings.Contains(probe.Schema, "jsonresume") || len(probe.Basics) > 0
+}
+
+// resumeDatePattern is the schema's iso8601 definition.
+var resumeDatePattern = regexp.MustCompile(`^([1-2][0-9]{3}-[0-1][0-9]-[0-3][0-9]|[1-2][0-9]{3}-[0-1][0-9]|[1-2][0-9]{3})$`)
+
+var resumeEmailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// Validate checks the schema constraints an exported document can break:
+// date formats, the email format, and the fields sprayer relies on.
+func (r Resume) Validate() error {
+	var problems []string
+	if r.Basics.Name == "" {
+		problems = append(problems, "basics.name is required")
+	}
+	if r.Basics.Email != "" && !resumeEmailPattern.MatchString(r.Basics.Email) {
+		problems = append(problems, fmt.Sprintf("basics.email %q is not an email address", r.Basics.Email))
+	}
+	checkDate := func(field, v string) {
+		if v != "" && !resumeDatePattern.MatchString(v) {
+			problems = append(problems, fmt.Sprintf("%s %q is not YYYY, YYYY-MM or YYYY-MM-DD", field, v))
+		}
+	}
+	for i, w := range r.Work {
+		if w.Name == "" || w.Position == "" {
+			problems = append(problems, fmt.Sprintf("work[%d] needs name and position", i))
+		}
+		checkDate(fmt.Sprintf("work[%d].startDate", i), w.StartDate)
+		checkDate(fmt.Sprintf("work[%d].endDate", i), w.EndDate)
+	}
+	for i, e := range r.Education {
+		if e.Institution == "" {
+			problems = append(problems, fmt.Sprintf("education[%d].institution is required", i))
+		}
+		checkDate(fmt.Sprintf("education[%d].startDate", i), e.StartDate)
+		checkDate(fmt.Sprintf("education[%d].endDate", i), e.EndDate)
+	}
+	for i, s := range r.Skills {
+		if s.Name == "" {
+			problems = append(problems, fmt.Sprintf("skills[%d].name is required", i))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid JSON Resume: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+var monthNames = map[string]string{
+	"jan": "01", "feb": "02", "mar": "03", "apr": "04", "may": "05", "jun": "06",
+	"jul": "07", "aug": "08", "sep": "09", "oct": "10", "nov": "11", "dec": "12",
+}
+
+// splitDuration turns free text like "Jan 2020 - Present" or "2018–2021"
+// into schema dates. An open end ("present", "now") has no end date.
+func splitDuration(d string) (start, end string) {
+	d = strings.TrimSpace(strings.NewReplacer("–", "-", "—", "-", " to ", " - ").Replace(d))
+	if resumeDatePattern.MatchString(d) {
+		return d, "" // "2020-01" is a date, not a range
+	}
+	from, to, found := strings.Cut(d, " - ")
+	if !found {
+		from, to, _ = strings.Cut(d, "-")
+	}
+	return resumeDate(from), resumeDate(to)
+}
+
+// resumeDate normalizes "2020", "2020-03", "Mar 2020" or "March 2020";
+// anything else yields "".
+func resumeDate(s string) string {
+	s = strings.TrimSpace(s)
+	if resumeDatePattern.MatchString(s) {
+		return s
+	}
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 2 && len(fields[0]) >= 3 {
+		if m, ok := monthNames[fields[0][:3]]; ok && resumeDatePattern.MatchString(fields[1]) {
+			return fields[1] + "-" + m
+		}
+	}
+	return ""
+}
+
+// splitLocation reads "City, Region, CC"; a trailing two-letter code is
+// taken as the country.
+func splitLocation(s string) *ResumeLocation {
+	var parts []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	loc := &ResumeLocation{}
+	if n := len(parts); n > 1 && len(parts[n-1]) == 2 && strings.ToUpper(parts[n-1]) == parts[n-1] {
+		loc.CountryCode, parts = parts[n-1], parts[:n-1]
+	}
+	if len(parts) > 0 {
+		loc.City = parts[0]
+		loc.Region = strings.Join(parts[1:], ", ")
+	}
+	return loc
+}
+
+// splitHighlights separates the bullet lines of a description from its prose.
+func splitHighlights(desc string) (summary string, highlights []string) {
+	var prose []string
+	for _, line := range strings.Split(desc, "\n") {
+		if h, ok := strings.CutPrefix(strings.TrimSpace(line), highlightBullet); ok {
+			highlights = append(highlights, h)
+		} else {
+			prose = append(prose, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(prose, "\n")), highlights
+}
+
+func joinDuration(start, end string) string {
+	switch {
+	case start == "" && end == "":
+		return ""
+	case end == "":
+		return start + " - Present"
+	default:
+		return start + " - " + end
+	}
+}
+
+func yearOf(date string) string {
+	if len(date) >= 4 {
+		return date[:4]
+	}
+	return date
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
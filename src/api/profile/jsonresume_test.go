@@ -0,0 +1,164 @@
+package profile_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/profile"
+)
+
+const sampleResume = "testdata/sample.resume.json"
+
+func loadSample(t *testing.T) profile.Resume {
+	t.Helper()
+	data, err := os.ReadFile(sampleResume)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r profile.Resume
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestJSONResume_SampleRoundTrip(t *testing.T) {
+	in := loadSample(t)
+	cv := profile.FromJSONResume(in)
+	out := profile.ToJSONResume(profile.Profile{CVData: cv})
+
+	if err := out.Validate(); err != nil {
+		t.Fatalf("exported sample fails validation: %v", err)
+	}
+	if !reflect.DeepEqual(out.Basics, in.Basics) {
+		t.Errorf("basics:\n got %+v\nwant %+v", out.Basics, in.Basics)
+	}
+	if !reflect.DeepEqual(out.Work, in.Work) {
+		t.Errorf("work:\n got %+v\nwant %+v", out.Work, in.Work)
+	}
+	if len(out.Education) != 1 {
+		t.Fatalf("education = %+v", out.Education)
+	}
+	got, want := out.Education[0], in.Education[0]
+	if got.Institution != want.Institution || got.Area != want.Area || got.StudyType != want.StudyType {
+		t.Errorf("education: got %+v, want %+v", got, want)
+	}
+	if got.EndDate != "2014" {
+		t.Errorf("education end = %q, want the year", got.EndDate)
+	}
+
+	// Skill levels are not kept; names and keywords are.
+	wantTech := []string{"html", "css", "javascript", "mpeg", "mp4", "gif"}
+	if !reflect.DeepEqual(cv.Technologies, wantTech) {
+		t.Errorf("technologies = %v, want %v", cv.Technologies, wantTech)
+	}
+	if !reflect.DeepEqual(cv.Skills, []string{"Web Development", "Compression"}) {
+		t.Errorf("skills = %v", cv.Skills)
+	}
+}
+
+func TestJSONResume_CVRoundTrip(t *testing.T) {
+	cv := &profile.CVData{
+		Name:         "Ada Lovelace",
+		Email:        "ada@example.com",
+		Phone:        "+44 20 7946 0000",
+		Location:     "London, GB",
+		Title:        "Backend Engineer",
+		Summary:      "Builds distributed systems.",
+		Technologies: []string{"go", "postgres"},
+		Experience: []profile.Experience{
+			{
+				Company:      "Analytical Engines",
+				Title:        "Senior Engineer",
+				Duration:     "2019-03 - Present",
+				Description:  "Led the payments team.\n- Cut p99 latency by 40%\n- Migrated to Postgres",
+				Technologies: []string{"go", "kafka"},
+			},
+			{Company: "Difference Ltd", Title: "Engineer", Duration: "2015 - 2019"},
+		},
+		Education: []profile.Education{{Institution: "UCL", Degree: "BSc", Field: "Mathematics", Year: "2015"}},
+		Skills:    []string{"System design"},
+		Languages: []string{"English", "French"},
+	}
+
+	r := profile.ToJSONResume(profile.Profile{CVData: cv})
+	if err := r.Validate(); err != nil {
+		t.Fatalf("export fails validation: %v", err)
+	}
+	if got := profile.FromJSONResume(r); !reflect.DeepEqual(got, cv) {
+		t.Errorf("round trip:\n got %+v\nwant %+v", got, cv)
+	}
+
+	// A second pass over an imported document changes nothing either.
+	first := profile.FromJSONResume(loadSample(t))
+	second := profile.FromJSONResume(profile.ToJSONResume(profile.Profile{CVData: first}))
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("sample round trip:\n got %+v\nwant %+v", second, first)
+	}
+}
+
+func TestJSONResume_ExportNormalizesDates(t *testing.T) {
+	cv := &profile.CVData{
+		Name: "Ada",
+		Experience: []profile.Experience{
+			{Company: "A", Title: "Dev", Duration: "Jan 2020 – Present"},
+			{Company: "B", Title: "Dev", Duration: "March 2017 to Dec 2019"},
+			{Company: "C", Title: "Dev", Duration: "a while"},
+		},
+	}
+	r := profile.ToJSONResume(profile.Profile{CVData: cv, ContactEmail: "jobs@example.com"})
+	if err := r.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{{"2020-01", ""}, {"2017-03", "2019-12"}, {"", ""}}
+	for i, w := range r.Work {
+		if w.StartDate != want[i][0] || w.EndDate != want[i][1] {
+			t.Errorf("work[%d] dates = %q..%q, want %q..%q", i, w.StartDate, w.EndDate, want[i][0], want[i][1])
+		}
+	}
+	if r.Basics.Email != "jobs@example.com" {
+		t.Errorf("email = %q, want the profile's contact email", r.Basics.Email)
+	}
+}
+
+func TestJSONResume_Validate(t *testing.T) {
+	r := profile.Resume{
+		Basics: profile.ResumeBasics{Email: "not-an-email"},
+		Work:   []profile.ResumeWork{{Name: "A", Position: "Dev", StartDate: "01/2020"}},
+		Skills: []profile.ResumeSkill{{}},
+	}
+	err := r.Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{"basics.name", "basics.email", "work[0].startDate", "skills[0].name"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := profile.WriteJSONResume(&buf, profile.Profile{}); err == nil {
+		t.Error("WriteJSONResume wrote a document without a name")
+	}
+}
+
+func TestImportProfile_DetectsJSONResume(t *testing.T) {
+	p, err := profile.NewProfileImporter().ImportProfile(sampleResume, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Richard Hendriks" || p.ContactEmail != "richard.hendriks@mail.com" {
+		t.Errorf("profile = %q <%s>", p.Name, p.ContactEmail)
+	}
+	if p.CVData == nil || len(p.CVData.Experience) != 1 {
+		t.Fatalf("CV data not kept: %+v", p.CVData)
+	}
+	if !reflect.DeepEqual(p.Keywords, p.CVData.Technologies) {
+		t.Errorf("keywords = %v, want the CV's technologies", p.Keywords)
+	}
+}
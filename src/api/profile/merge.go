@@ -0,0 +1,194 @@
+package profile
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Resolution is what to do when an imported profile collides with a
+// stored one.
+type Resolution string
+
+const (
+	// ResolveCopy stores the import under a fresh ID, leaving the stored
+	// profile alone. It is the default when nobody can be asked.
+	ResolveCopy Resolution = "copy"
+	// ResolveOverwrite replaces the stored profile with the import.
+	ResolveOverwrite Resolution = "overwrite"
+	// ResolveMerge keeps the stored profile and lays the import's
+	// non-empty fields over it.
+	ResolveMerge Resolution = "merge"
+)
+
+// ParseResolution reads a resolution name; "" is ResolveCopy.
+func ParseResolution(s string) (Resolution, error) {
+	switch r := Resolution(strings.ToLower(strings.TrimSpace(s))); r {
+	case "":
+		return ResolveCopy, nil
+	case ResolveCopy, ResolveOverwrite, ResolveMerge:
+		return r, nil
+	}
+	return "", fmt.Errorf("unknown resolution %q (want copy, overwrite or merge)", s)
+}
+
+// Conflict finds the stored profile an import collides with: the one with
+// its ID, or failing that one with the same name ignoring case. byName
+// reports which matched.
+func Conflict(stored []Profile, imported Profile) (existing *Profile, byName bool) {
+	for i := range stored {
+		if stored[i].ID == imported.ID {
+			return &stored[i], false
+		}
+	}
+	for i := range stored {
+		if imported.Name != "" && strings.EqualFold(stored[i].Name, imported.Name) {
+			return &stored[i], true
+		}
+	}
+	return nil, false
+}
+
+// Resolve applies r to an import colliding with existing. A copy gets an
+// ID and name no stored profile uses; overwrite and merge keep existing's
+// ID so the stored profile is replaced.
+func Resolve(stored []Profile, existing, imported Profile, r Resolution) Profile {
+	switch r {
+	case ResolveOverwrite:
+		imported.ID = existing.ID
+		return imported
+	case ResolveMerge:
+		return Merge(existing, imported)
+	}
+	ids := make(map[string]bool, len(stored))
+	names := make(map[string]bool, len(stored))
+	for _, p := range stored {
+		ids[p.ID] = true
+		names[strings.ToLower(p.Name)] = true
+	}
+	base := imported.ID
+	if base == "" {
+		base = "profile"
+	}
+	for n := 2; ids[imported.ID] || imported.ID == ""; n++ {
+		imported.ID = fmt.Sprintf("%s-%d", base, n)
+	}
+	if names[strings.ToLower(imported.Name)] {
+		name := imported.Name + " (copy)"
+		for n := 2; names[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s (copy %d)", imported.Name, n)
+		}
+		imported.Name = name
+	}
+	return imported
+}
+
+// FieldChange is one field that differs between two profiles. Field is
+// the JSON path, such as "scoring_weights.tech_match"; Old and New are the
+// values as JSON.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+func (c FieldChange) String() string {
+	return fmt.Sprintf("%s: %s → %s", c.Field, c.Old, c.New)
+}
+
+// Diff lists the fields that differ from old to new, descending into
+// nested structs such as ScoringWeights and CVData so a change shows as
+// the one leaf that moved.
+func Diff(old, new Profile) []FieldChange {
+	var out []FieldChange
+	diffValues("", reflect.ValueOf(old), reflect.ValueOf(new), &out)
+	return out
+}
+
+func diffValues(path string, a, b reflect.Value, out *[]FieldChange) {
+	if a.Kind() == reflect.Pointer && isNested(a.Type().Elem()) && !a.IsNil() && !b.IsNil() {
+		a, b = a.Elem(), b.Elem()
+	}
+	if isNested(a.Type()) {
+		for i := 0; i < a.NumField(); i++ {
+			f := a.Type().Field(i)
+			if name := fieldName(f); name != "" {
+				diffValues(join(path, name), a.Field(i), b.Field(i), out)
+			}
+		}
+		return
+	}
+	if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+		*out = append(*out, FieldChange{Field: path, Old: jsonValue(a), New: jsonValue(b)})
+	}
+}
+
+// Merge lays the non-empty fields of imported over base. Nested structs
+// merge field by field; a slice or map replaces the stored one whole
+// when the import has any entries. The ID is always base's. A false bool
+// counts as empty, so merging cannot turn a setting off.
+func Merge(base, imported Profile) Profile {
+	id := base.ID
+	if base.CVData != nil {
+		cv := *base.CVData // keep the stored profile's CVData unchanged
+		base.CVData = &cv
+	}
+	mergeValues(reflect.ValueOf(&base).Elem(), reflect.ValueOf(imported))
+	base.ID = id
+	return base
+}
+
+func mergeValues(dst, src reflect.Value) {
+	switch {
+	case src.IsZero():
+		return
+	case src.Kind() == reflect.Pointer && isNested(src.Type().Elem()) && !dst.IsNil():
+		mergeValues(dst.Elem(), src.Elem())
+	case isNested(src.Type()):
+		for i := 0; i < src.NumField(); i++ {
+			if src.Type().Field(i).IsExported() {
+				mergeValues(dst.Field(i), src.Field(i))
+			}
+		}
+	case (src.Kind() == reflect.Slice || src.Kind() == reflect.Map) && src.Len() == 0:
+	default:
+		dst.Set(src)
+	}
+}
+
+// isNested reports whether t is one of this package's structs, which diff
+// and merge field by field. Other structs, like time.Time, are values.
+func isNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == reflect.TypeOf(Profile{}).PkgPath()
+}
+
+// fieldName is a field's JSON name, or "" for fields JSON skips.
+func fieldName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+	return name
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func jsonValue(v reflect.Value) string {
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprint(v.Interface())
+	}
+	return string(data)
+}
@@ -0,0 +1,112 @@
+package profile_test
+
+import (
+	"strings"
+	"testing"
+
+	"sprayer/src/api/profile"
+)
+
+func tuned() profile.Profile {
+	return profile.Profile{
+		ID: "backend", Name: "Backend", Keywords: []string{"go"}, MinScore: 60,
+		PreferRemote:   true,
+		ScoringWeights: profile.ScoringWeights{TechMatch: 40, RemoteMatch: 10},
+		CVData:         &profile.CVData{Name: "Jane", Email: "jane@example.com", Skills: []string{"go", "sql"}},
+	}
+}
+
+func changes(cs []profile.FieldChange) string {
+	var out []string
+	for _, c := range cs {
+		out = append(out, c.String())
+	}
+	return strings.Join(out, "\n")
+}
+
+func TestDiff_DescendsIntoNestedStructs(t *testing.T) {
+	old := tuned()
+	new := tuned()
+	new.MinScore = 70
+	new.ScoringWeights.TechMatch = 25
+	new.CVData = &profile.CVData{Name: "Jane", Email: "jane@new.dev", Skills: []string{"go", "sql"}}
+
+	want := strings.Join([]string{
+		"min_score: 60 → 70",
+		"scoring_weights.tech_match: 40 → 25",
+		"cv_data.email: \"jane@example.com\" → \"jane@new.dev\"",
+	}, "\n")
+	if got := changes(profile.Diff(old, new)); got != want {
+		t.Errorf("Diff:\n%s\nwant:\n%s", got, want)
+	}
+
+	new.CVData = nil
+	if got := changes(profile.Diff(old, new)); !strings.Contains(got, "cv_data: {") || !strings.HasSuffix(got, "→ null") {
+		t.Errorf("removed CV should show as one change:\n%s", got)
+	}
+	if d := profile.Diff(old, tuned()); len(d) != 0 {
+		t.Errorf("equal profiles differ: %v", d)
+	}
+}
+
+func TestMerge_ImportedNonEmptyFieldsWin(t *testing.T) {
+	base := tuned()
+	imported := profile.Profile{
+		ID:             "other",
+		Locations:      []string{"Berlin"},
+		Keywords:       []string{},
+		ScoringWeights: profile.ScoringWeights{SalaryMatch: 20},
+		CVData:         &profile.CVData{Title: "Staff Engineer"},
+	}
+
+	got := profile.Merge(base, imported)
+	if got.ID != "backend" || got.Name != "Backend" || got.MinScore != 60 || !got.PreferRemote {
+		t.Errorf("empty imported fields overwrote stored ones: %+v", got)
+	}
+	if strings.Join(got.Keywords, ",") != "go" || strings.Join(got.Locations, ",") != "Berlin" {
+		t.Errorf("lists: keywords %v, locations %v", got.Keywords, got.Locations)
+	}
+	if got.ScoringWeights != (profile.ScoringWeights{TechMatch: 40, RemoteMatch: 10, SalaryMatch: 20}) {
+		t.Errorf("ScoringWeights = %+v", got.ScoringWeights)
+	}
+	if got.CVData.Title != "Staff Engineer" || got.CVData.Email != "jane@example.com" {
+		t.Errorf("CVData = %+v", got.CVData)
+	}
+	if base.CVData.Title != "" {
+		t.Error("Merge modified the stored profile's CVData")
+	}
+}
+
+func TestConflictAndResolve(t *testing.T) {
+	stored := []profile.Profile{tuned(), {ID: "backend-2", Name: "Backend (copy)"}}
+
+	imported := tuned()
+	imported.MinScore = 10
+	existing, byName := profile.Conflict(stored, imported)
+	if existing == nil || existing.ID != "backend" || byName {
+		t.Fatalf("Conflict = %v, byName %v", existing, byName)
+	}
+	renamed := profile.Profile{ID: "cv_1", Name: "BACKEND"}
+	if existing, byName := profile.Conflict(stored, renamed); existing == nil || !byName {
+		t.Errorf("name collision not found")
+	}
+	if existing, _ := profile.Conflict(stored, profile.Profile{ID: "new", Name: "New"}); existing != nil {
+		t.Errorf("unrelated profile conflicts with %s", existing.ID)
+	}
+
+	cp := profile.Resolve(stored, *existing, imported, profile.ResolveCopy)
+	if cp.ID != "backend-3" || cp.Name != "Backend (copy 2)" || cp.MinScore != 10 {
+		t.Errorf("copy = %s %q %d", cp.ID, cp.Name, cp.MinScore)
+	}
+	over := profile.Resolve(stored, *existing, renamed, profile.ResolveOverwrite)
+	if over.ID != "backend" || over.Name != "BACKEND" {
+		t.Errorf("overwrite = %s %q", over.ID, over.Name)
+	}
+
+	if r, err := profile.ParseResolution(""); err != nil || r != profile.ResolveCopy {
+		t.Errorf("default resolution = %q, %v", r, err)
+	}
+	if _, err := profile.ParseResolution("replace"); err == nil {
+		t.Error("unknown resolution accepted")
+	}
+}
@@ -21,14 +21,28 @@ type Profile struct {
 	Locations    []string `json:"locations"`
 
 	// Dynamic filtering configuration
-	MinScore        int         `json:"min_score"`
-	MaxScore        int         `json:"max_score"`
-	ExcludeTraps    bool        `json:"exclude_traps"`
-	MustHaveEmail   bool        `json:"must_have_email"`
-	JobTypes        []string    `json:"job_types"`        // "full-time", "contract", "part-time", "internship"
-	SeniorityLevels []string    `json:"seniority_levels"` // "junior", "mid", "senior", "staff", "principal"
-	SalaryRange     SalaryRange `json:"salary_range"`
-	ExcludeKeywords []string    `json:"exclude_keywords"`
+	MinScore        int              `json:"min_score"`
+	MaxScore        int              `json:"max_score"`
+	ExcludeTraps    bool             `json:"exclude_traps"`
+	TrapSeverity    job.TrapSeverity `json:"trap_severity,omitempty"` // least severe trap excluded; empty is any
+	MustHaveEmail   bool             `json:"must_have_email"`
+	JobTypes        []string         `json:"job_types"`        // "full-time", "contract", "part-time", "internship"
+	SeniorityLevels []string         `json:"seniority_levels"` // parse.SeniorityLevels, e.g. "junior", "senior", "lead"
+	SalaryRange     SalaryRange      `json:"salary_range"`
+	ExcludeKeywords []string         `json:"exclude_keywords"`
+	// IncludeUnknownSeniority keeps jobs whose level can't be detected
+	// when SeniorityLevels is set.
+	IncludeUnknownSeniority bool `json:"include_unknown_seniority,omitempty"`
+	// AcceptedLanguages are the ISO 639-1 codes of the languages postings
+	// may be written in; empty accepts any.
+	AcceptedLanguages []string `json:"accepted_languages,omitempty"`
+	// HideStatuses drops jobs at these statuses, e.g. rejected ones.
+	HideStatuses []job.Status `json:"hide_statuses,omitempty"`
+	// HideApplied drops jobs already applied to; the TUI toggles it with h.
+	HideApplied bool `json:"hide_applied,omitempty"`
+	// ListSort is the order the TUI lists jobs in, as job.ParseSort reads
+	// it; empty is by score.
+	ListSort string `json:"list_sort,omitempty"`
 
 	// Technology preferences
 	PreferredTech []string `json:"preferred_tech"`
@@ -38,6 +52,40 @@ type Profile struct {
 	PreferredCompanies []string `json:"preferred_companies"`
 	AvoidCompanies     []string `json:"avoid_companies"`
 
+	// Source configuration. Sources are scraper keys such as "hn" or
+	// "remoteok"; empty runs every source but the opt-in ones, LinkedIn,
+	// Indeed and Glassdoor.
+	Sources       []string       `json:"sources,omitempty"`
+	AshbyOrgs     []string       `json:"ashby_orgs,omitempty"`     // Ashby job board slugs; defaults used when empty
+	SourceWeights map[string]int `json:"source_weights,omitempty"` // Score delta per source, e.g. {"Greenhouse": 10}
+
+	// GreenhouseBoards are Greenhouse board tokens scraped on top of the
+	// default boards.
+	GreenhouseBoards []string `json:"greenhouse_boards,omitempty"`
+	// Feeds are the RSS or Atom feed URLs the rss source scrapes; the
+	// default feeds are used when empty.
+	Feeds []string `json:"feeds,omitempty"`
+
+	// Work eligibility. WorkAuthorizations are region tokens such as "US",
+	// "EU" or "UK"; Clearance is one of parse.Clearances, or empty for
+	// none. Jobs demanding what the profile lacks lose RequirementPenalty
+	// points, or are dropped with ExcludeUnmetRequirements.
+	WorkAuthorizations       []string `json:"work_authorizations,omitempty"`
+	Clearance                string   `json:"clearance,omitempty"`
+	ExcludeUnmetRequirements bool     `json:"exclude_unmet_requirements,omitempty"`
+
+	// Tracking adds open and click tracking to application emails. Off by
+	// default; it also needs a tracking secret and URL configured.
+	Tracking bool `json:"tracking,omitempty"`
+
+	// FollowUpDays is how many days after applying a follow-up falls
+	// due; zero means a week.
+	FollowUpDays int `json:"follow_up_days,omitempty"`
+
+	// NotifyMinScore is the score from which a newly scraped job raises a
+	// desktop notification; zero turns them off.
+	NotifyMinScore int `json:"notify_min_score,omitempty"`
+
 	// Date filtering
 	PostedAfter  *time.Time `json:"posted_after"`
 	PostedBefore *time.Time `json:"posted_before"`
@@ -48,6 +96,9 @@ type Profile struct {
 	// CV-based data
 	CVData     *CVData `json:"cv_data,omitempty"`
 	CVMinScore int     `json:"cv_min_score,omitempty"` // Minimum CV match score
+	// CVTemplate names the LaTeX layout of CVs tailored to a job, a
+	// built-in or a file in ~/.sprayer/templates; empty is compact.
+	CVTemplate string `json:"cv_template,omitempty"`
 }
 
 type SalaryRange struct {
@@ -69,18 +120,28 @@ type ScoringWeights struct {
 func NewDefaultProfile() Profile {
 	now := time.Now()
 	return Profile{
-		ID:              "default",
-		Name:            "Default",
-		Keywords:        []string{"golang", "rust", "remote"},
-		MinScore:        0,
-		MaxScore:        100,
-		ExcludeTraps:    true,
-		MustHaveEmail:   false,
-		JobTypes:        []string{"full-time", "contract"},
-		SeniorityLevels: []string{"mid", "senior", "staff"},
-		PostedAfter:     &now, // Default to jobs posted today
-		ScoringWeights:  DefaultScoringWeights(),
+		ID:                      "default",
+		Name:                    "Default",
+		Keywords:                []string{"golang", "rust", "remote"},
+		MinScore:                0,
+		MaxScore:                100,
+		ExcludeTraps:            true,
+		MustHaveEmail:           false,
+		JobTypes:                []string{"full-time", "contract"},
+		SeniorityLevels:         []string{"mid", "senior", "staff"},
+		IncludeUnknownSeniority: true,
+		AcceptedLanguages:       []string{"en"},
+		PostedAfter:             &now, // Default to jobs posted today
+		ScoringWeights:          DefaultScoringWeights(),
+	}
+}
+
+// FollowUpAfter is how long after applying to chase an application.
+func (p Profile) FollowUpAfter() time.Duration {
+	if p.FollowUpDays > 0 {
+		return time.Duration(p.FollowUpDays) * 24 * time.Hour
 	}
+	return job.DefaultFollowUp
 }
 
 func DefaultScoringWeights() ScoringWeights {
@@ -134,7 +195,24 @@ func (p *Profile) GenerateFilters() []job.Filter {
 
 	// Trap exclusion
 	if p.ExcludeTraps {
-		filters = append(filters, job.ExcludeTraps())
+		filters = append(filters, job.ExcludeTraps(p.TrapSeverity))
+	}
+
+	if len(p.AcceptedLanguages) > 0 {
+		filters = append(filters, job.ByLanguages(p.AcceptedLanguages))
+	}
+
+	// Application status
+	if len(p.HideStatuses) > 0 {
+		filters = append(filters, job.ExcludeStatuses(p.HideStatuses...))
+	}
+	if p.HideApplied {
+		filters = append(filters, job.ExcludeApplied())
+	}
+
+	// Work authorization and clearance
+	if p.ExcludeUnmetRequirements {
+		filters = append(filters, p.ExcludeUnmet())
 	}
 
 	// Remote preference
@@ -144,7 +222,7 @@ func (p *Profile) GenerateFilters() []job.Filter {
 
 	// Seniority level
 	if len(p.SeniorityLevels) > 0 {
-		filters = append(filters, job.BySeniorityLevels(p.SeniorityLevels))
+		filters = append(filters, job.BySeniorityLevels(p.SeniorityLevels, p.IncludeUnknownSeniority))
 	}
 
 	// Technology preferences
@@ -173,7 +251,14 @@ func (p *Profile) GenerateFilters() []job.Filter {
 	return filters
 }
 
-// CalculateJobScore calculates a custom score for a job based on profile preferences
+// CalculateJobScore calculates a custom score for a job based on profile
+// preferences. Each preference the profile sets is a component worth its
+// weight: technologies by the share of them the job names, seniority,
+// locations, companies, remote work when PreferRemote is set, and the
+// salary range against the job's when the job states one. The score is
+// the share of those components' weights the job earns, 0-100; a
+// component the profile leaves unset, or a salary the job does not
+// state, counts for nothing either way.
 func (p *Profile) CalculateJobScore(j *job.Job) int {
 	score := 0
 	maxScore := 0
@@ -182,20 +267,21 @@ func (p *Profile) CalculateJobScore(j *job.Job) int {
 	if len(p.PreferredTech) > 0 {
 		maxScore += p.ScoringWeights.TechMatch
 		titleDesc := strings.ToLower(j.Title + " " + j.Description)
+		matched := 0
 		for _, tech := range p.PreferredTech {
 			if strings.Contains(titleDesc, strings.ToLower(tech)) {
-				score += p.ScoringWeights.TechMatch / len(p.PreferredTech)
-				break
+				matched++
 			}
 		}
+		score += p.ScoringWeights.TechMatch * matched / len(p.PreferredTech)
 	}
 
 	// Seniority matching
 	if len(p.SeniorityLevels) > 0 {
 		maxScore += p.ScoringWeights.SeniorityMatch
-		titleLower := strings.ToLower(j.Title)
-		for _, level := range p.SeniorityLevels {
-			if strings.Contains(titleLower, level) {
+		level := j.Level()
+		for _, want := range p.SeniorityLevels {
+			if strings.EqualFold(want, level) {
 				score += p.ScoringWeights.SeniorityMatch
 				break
 			}
@@ -203,10 +289,14 @@ func (p *Profile) CalculateJobScore(j *job.Job) int {
 	}
 
 	// Location matching
-	if len(p.Locations) > 0 && p.PreferRemote {
+	if len(p.Locations) > 0 {
 		maxScore += p.ScoringWeights.LocationMatch
-		if strings.Contains(strings.ToLower(j.Location), "remote") {
-			score += p.ScoringWeights.LocationMatch
+		location := strings.ToLower(j.Location)
+		for _, want := range p.Locations {
+			if want = strings.ToLower(strings.TrimSpace(want)); want != "" && strings.Contains(location, want) {
+				score += p.ScoringWeights.LocationMatch
+				break
+			}
 		}
 	}
 
@@ -229,6 +319,19 @@ func (p *Profile) CalculateJobScore(j *job.Job) int {
 		}
 	}
 
+	// Salary matching, when the job states a salary in the profile's
+	// currency or an unnamed one
+	if want := p.SalaryRange; want.Min > 0 || want.Max > 0 {
+		lo, hi, ok := j.SalaryBounds()
+		sameCurrency := want.Currency == "" || j.SalaryCurrency == "" || strings.EqualFold(want.Currency, j.SalaryCurrency)
+		if ok && sameCurrency {
+			maxScore += p.ScoringWeights.SalaryMatch
+			if (want.Min == 0 || hi >= want.Min) && (want.Max == 0 || lo <= want.Max) {
+				score += p.ScoringWeights.SalaryMatch
+			}
+		}
+	}
+
 	// Normalize to 0-100 scale
 	if maxScore > 0 {
 		return (score * 100) / maxScore
@@ -254,7 +357,11 @@ func (p *Profile) GetFilterSummary() string {
 	}
 
 	if p.ExcludeTraps {
-		parts = append(parts, "no traps")
+		if p.TrapSeverity == "" || p.TrapSeverity == job.TrapLow {
+			parts = append(parts, "no traps")
+		} else {
+			parts = append(parts, fmt.Sprintf("no %s+ traps", p.TrapSeverity))
+		}
 	}
 
 	if p.MustHaveEmail {
@@ -266,7 +373,15 @@ func (p *Profile) GetFilterSummary() string {
 	}
 
 	if len(p.SeniorityLevels) > 0 {
-		parts = append(parts, fmt.Sprintf("levels: %s", strings.Join(p.SeniorityLevels, ", ")))
+		levels := strings.Join(p.SeniorityLevels, ", ")
+		if p.IncludeUnknownSeniority {
+			levels += " or unknown"
+		}
+		parts = append(parts, fmt.Sprintf("levels: %s", levels))
+	}
+
+	if len(p.AcceptedLanguages) > 0 {
+		parts = append(parts, fmt.Sprintf("languages: %s", strings.Join(p.AcceptedLanguages, ", ")))
 	}
 
 	if len(parts) == 0 {
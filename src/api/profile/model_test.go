@@ -0,0 +1,57 @@
+package profile_test
+
+import (
+	"testing"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+func TestCalculateJobScore_DetectedSeniority(t *testing.T) {
+	p := profile.Profile{SeniorityLevels: []string{"senior"}, ScoringWeights: profile.ScoringWeights{SeniorityMatch: 20}}
+	years := &job.Job{Title: "Backend Engineer", Description: "You bring 6+ years of experience."}
+	substring := &job.Job{Title: "Senior-friendly junior role"}
+	if got, other := p.CalculateJobScore(years), p.CalculateJobScore(substring); got <= other {
+		t.Errorf("a senior job by its years scored %d, a junior one %d", got, other)
+	}
+}
+
+func TestCalculateJobScore_Components(t *testing.T) {
+	// DefaultScoringWeights: tech 30, seniority 20, location 15, company
+	// 10, salary 15, remote 10.
+	weights := profile.DefaultScoringWeights()
+	berlinOrRemote := profile.Profile{Locations: []string{"Berlin"}, PreferRemote: true, ScoringWeights: weights}
+	salary := profile.Profile{SalaryRange: profile.SalaryRange{Min: 100000, Max: 140000}, ScoringWeights: weights}
+	euros := profile.Profile{SalaryRange: profile.SalaryRange{Min: 100000, Currency: "EUR"}, ScoringWeights: weights}
+
+	tests := []struct {
+		name string
+		p    profile.Profile
+		j    job.Job
+		want int
+	}{
+		{"no preferences is neutral", profile.Profile{ScoringWeights: weights}, job.Job{Title: "Engineer"}, 50},
+		// Remote earns the remote component only, not location too.
+		{"remote job, Berlin or remote", berlinOrRemote, job.Job{Location: "Remote"}, 40},
+		{"Berlin job, Berlin or remote", berlinOrRemote, job.Job{Location: "Berlin, Germany"}, 60},
+		{"Berlin job, Berlin only", profile.Profile{Locations: []string{"berlin"}, ScoringWeights: weights}, job.Job{Location: "Berlin"}, 100},
+		{"Munich job, Berlin only", profile.Profile{Locations: []string{"berlin"}, ScoringWeights: weights}, job.Job{Location: "Munich"}, 0},
+		{"two of three technologies", profile.Profile{PreferredTech: []string{"go", "postgres", "kafka"}, ScoringWeights: weights},
+			job.Job{Title: "Go engineer", Description: "Postgres"}, 66},
+		{"salary text in range", salary, job.Job{Salary: "$120k - $150k"}, 100},
+		{"structured salary under range", salary, job.Job{SalaryMin: 70000, SalaryMax: 90000}, 0},
+		{"salary over range", salary, job.Job{SalaryMin: 150000}, 0},
+		{"no salary stated is neutral", salary, job.Job{Salary: "competitive"}, 50},
+		{"salary in another currency is neutral", euros, job.Job{SalaryMin: 120000, SalaryCurrency: "USD"}, 50},
+		{"salary in the profile's currency", euros, job.Job{SalaryMin: 120000, SalaryCurrency: "eur"}, 100},
+		// Tech 30 of 30, location 15 of 15, remote 0 of 10, salary 0 of 15.
+		{"components combined", profile.Profile{PreferredTech: []string{"go"}, Locations: []string{"Berlin"}, PreferRemote: true,
+			SalaryRange: profile.SalaryRange{Min: 100000}, ScoringWeights: weights},
+			job.Job{Title: "Go engineer", Location: "Berlin", SalaryMax: 90000}, 64},
+	}
+	for _, tt := range tests {
+		if got := tt.p.CalculateJobScore(&tt.j); got != tt.want {
+			t.Errorf("%s: score %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
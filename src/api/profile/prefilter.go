@@ -0,0 +1,64 @@
+package profile
+
+import (
+	"unicode"
+
+	"sprayer/src/api/job"
+)
+
+// CandidateTerms returns terms of which a job must contain at least one
+// to score minScore or more with ScoreJob, so the database can rule out
+// the rest before scoring. Technologies alone suffice when skills,
+// languages and title together cannot reach minScore; otherwise those are
+// candidates too. ok is false when no narrowing is safe.
+func (cv *CVData) CandidateTerms(minScore int) (terms []string, ok bool) {
+	if minScore <= 0 {
+		return nil, false
+	}
+	rest := 5*len(cv.Skills) + 2*len(cv.Languages)
+	if cv.Title != "" {
+		rest += 15
+	}
+	terms = append(terms, cv.Technologies...)
+	if rest >= minScore {
+		terms = append(terms, cv.Skills...)
+		terms = append(terms, cv.Languages...)
+		if cv.Title != "" {
+			terms = append(terms, cv.Title)
+		}
+	}
+	for _, t := range terms {
+		// Every job contains "", and SQL LIKE folds only ASCII case.
+		if t == "" || !isASCII(t) {
+			return nil, false
+		}
+	}
+	return terms, true
+}
+
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// StoredMatches loads the profile's active jobs from s and applies its
+// filters. With a CV filter set, the database first narrows the jobs to
+// those containing a CV term, so the scorer only sees candidates; the
+// result is the same as filtering every job.
+func (p *Profile) StoredMatches(s *job.Store, opts ...job.ActiveOption) ([]job.Job, error) {
+	load := func() ([]job.Job, error) { return s.ForProfile(p.ID, opts...) }
+	if p.CVData != nil {
+		if terms, ok := p.CVData.CandidateTerms(p.CVMinScore); ok {
+			load = func() ([]job.Job, error) { return s.ForProfileContaining(p.ID, terms, opts...) }
+		}
+	}
+	jobs, err := load()
+	if err != nil {
+		return nil, err
+	}
+	return job.Pipe(p.GenerateFilters()...)(jobs), nil
+}
@@ -0,0 +1,143 @@
+package profile
+
+import (
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/job"
+)
+
+var (
+	// seedTerms are what CVs in these tests look for; seedWords are not.
+	seedTerms = []string{"golang", "rust", "Kubernetes", "postgres", "react", "kafka", "mentoring", "on-call", "english"}
+	seedWords = strings.Fields("the team builds reliable services for customers across regions with care and pace we value writing clear code shipping small changes")
+)
+
+// seedJobs stores n jobs of about descWords words. A share of them,
+// relevant, sprinkle seedTerms in random case through the filler; the
+// rest never mention one.
+func seedJobs(t testing.TB, n, descWords int, relevant float64) *job.Store {
+	t.Helper()
+	s, err := job.OpenStore(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	rng := rand.New(rand.NewSource(1))
+	jobs := make([]job.Job, n)
+	for i := range jobs {
+		hasTerms := rng.Float64() < relevant
+		words := make([]string, descWords)
+		for w := range words {
+			words[w] = seedWords[rng.Intn(len(seedWords))]
+			if hasTerms && rng.Intn(60) == 0 {
+				term := seedTerms[rng.Intn(len(seedTerms))]
+				if rng.Intn(2) == 0 {
+					term = strings.ToUpper(term)
+				}
+				words[w] = term
+			}
+		}
+		title := "Engineer"
+		if rng.Intn(10) == 0 {
+			title = "Backend Developer"
+		}
+		jobs[i] = job.Job{ID: fmt.Sprintf("job-%05d", i), Title: title, Company: "Acme",
+			Description: strings.Join(words, " "), Score: rng.Intn(100)}
+	}
+	if err := s.Save(jobs); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func cvProfile(minScore int) Profile {
+	return Profile{
+		ID: "cv",
+		CVData: &CVData{
+			Title:        "backend developer",
+			Technologies: []string{"golang", "kubernetes", "postgres"},
+			Skills:       []string{"mentoring", "on-call"},
+			Languages:    []string{"english"},
+		},
+		CVMinScore: minScore,
+		MaxScore:   100,
+	}
+}
+
+func TestStoredMatches_EqualsBruteForce(t *testing.T) {
+	s := seedJobs(t, 600, 300, 0.5)
+	all, err := s.ForProfile("cv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 5 and 20 need skills, languages and title as candidates; 28 and 35
+	// are out of their reach, so technologies alone narrow the set.
+	for _, min := range []int{5, 20, 28, 35} {
+		p := cvProfile(min)
+		want := job.Pipe(p.GenerateFilters()...)(all)
+		got, err := p.StoredMatches(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(want) == 0 || len(want) == len(all) {
+			t.Fatalf("min %d: %d of %d jobs match; the seed does not exercise the filter", min, len(want), len(all))
+		}
+		if !reflect.DeepEqual(ids(got), ids(want)) {
+			t.Errorf("min %d: two-stage matched %d jobs, brute force %d", min, len(got), len(want))
+		}
+	}
+}
+
+func TestCandidateTerms(t *testing.T) {
+	cv := cvProfile(0).CVData
+	if terms, ok := cv.CandidateTerms(30); !ok || len(terms) != 3 {
+		t.Errorf("CandidateTerms(30) = %v, %v; want the three technologies", terms, ok)
+	}
+	if terms, ok := cv.CandidateTerms(10); !ok || len(terms) != 7 {
+		t.Errorf("CandidateTerms(10) = %v, %v; want every term", terms, ok)
+	}
+	if _, ok := cv.CandidateTerms(0); ok {
+		t.Error("no CV filter should not narrow")
+	}
+	unicodeCV := &CVData{Technologies: []string{"golang", "ökonomie"}}
+	if _, ok := unicodeCV.CandidateTerms(10); ok {
+		t.Error("non-ASCII terms should not narrow: LIKE only folds ASCII case")
+	}
+}
+
+func ids(jobs []job.Job) []string {
+	out := make([]string, len(jobs))
+	for i, j := range jobs {
+		out[i] = j.ID
+	}
+	return out
+}
+
+func BenchmarkCVMatch20k(b *testing.B) {
+	s := seedJobs(b, 20000, 1500, 0.1)
+	p := cvProfile(30)
+
+	b.Run("brute-force", func(b *testing.B) {
+		for b.Loop() {
+			all, err := s.ForProfile(p.ID)
+			if err != nil {
+				b.Fatal(err)
+			}
+			job.Pipe(p.GenerateFilters()...)(all)
+		}
+	})
+	b.Run("two-stage", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := p.StoredMatches(s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
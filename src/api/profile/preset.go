@@ -0,0 +1,230 @@
+package profile
+
+import (
+	"cmp"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// FilterPreset is a named set of filters kept apart from profiles, so the
+// jobs listed can change without touching who applies. It layers on a
+// profile: each field it sets replaces the profile's, the rest are left
+// as the profile has them. Unset bools are nil, unset scores zero.
+type FilterPreset struct {
+	Name string `json:"name"`
+
+	Keywords           []string `json:"keywords,omitempty"`
+	ExcludeKeywords    []string `json:"exclude_keywords,omitempty"`
+	Locations          []string `json:"locations,omitempty"`
+	PreferredTech      []string `json:"preferred_tech,omitempty"`
+	AvoidTech          []string `json:"avoid_tech,omitempty"`
+	PreferredCompanies []string `json:"preferred_companies,omitempty"`
+	AvoidCompanies     []string `json:"avoid_companies,omitempty"`
+	SeniorityLevels    []string `json:"seniority_levels,omitempty"`
+	AcceptedLanguages  []string `json:"accepted_languages,omitempty"`
+
+	HideStatuses []job.Status     `json:"hide_statuses,omitempty"`
+	MinScore     int              `json:"min_score,omitempty"`
+	MaxScore     int              `json:"max_score,omitempty"`
+	TrapSeverity job.TrapSeverity `json:"trap_severity,omitempty"`
+	// PostedWithinDays keeps jobs posted in the last so many days,
+	// counted from when the preset is applied.
+	PostedWithinDays int `json:"posted_within_days,omitempty"`
+
+	PreferRemote  *bool `json:"prefer_remote,omitempty"`
+	MustHaveEmail *bool `json:"must_have_email,omitempty"`
+	ExcludeTraps  *bool `json:"exclude_traps,omitempty"`
+	HideApplied   *bool `json:"hide_applied,omitempty"`
+}
+
+// Apply returns p with f's filters in place of its own; p itself is not
+// changed. Posting age counts back from now.
+func (f FilterPreset) Apply(p Profile, now time.Time) Profile {
+	lists := []struct{ dst, src *[]string }{
+		{&p.Keywords, &f.Keywords},
+		{&p.ExcludeKeywords, &f.ExcludeKeywords},
+		{&p.Locations, &f.Locations},
+		{&p.PreferredTech, &f.PreferredTech},
+		{&p.AvoidTech, &f.AvoidTech},
+		{&p.PreferredCompanies, &f.PreferredCompanies},
+		{&p.AvoidCompanies, &f.AvoidCompanies},
+		{&p.SeniorityLevels, &f.SeniorityLevels},
+		{&p.AcceptedLanguages, &f.AcceptedLanguages},
+	}
+	for _, l := range lists {
+		if len(*l.src) > 0 {
+			*l.dst = *l.src
+		}
+	}
+	if len(f.HideStatuses) > 0 {
+		p.HideStatuses = f.HideStatuses
+	}
+	if f.MinScore > 0 {
+		p.MinScore = f.MinScore
+	}
+	if f.MaxScore > 0 {
+		p.MaxScore = f.MaxScore
+	}
+	if f.TrapSeverity != "" {
+		p.TrapSeverity = f.TrapSeverity
+	}
+	if f.PostedWithinDays > 0 {
+		after := now.AddDate(0, 0, -f.PostedWithinDays)
+		p.PostedAfter = &after
+	}
+	for _, b := range []struct {
+		dst *bool
+		src *bool
+	}{
+		{&p.PreferRemote, f.PreferRemote},
+		{&p.MustHaveEmail, f.MustHaveEmail},
+		{&p.ExcludeTraps, f.ExcludeTraps},
+		{&p.HideApplied, f.HideApplied},
+	} {
+		if b.src != nil {
+			*b.dst = *b.src
+		}
+	}
+	return p
+}
+
+// Summary describes what f sets in one line, as
+// "keywords rust · remote · last 1 day".
+func (f FilterPreset) Summary() string {
+	var parts []string
+	list := func(name string, v []string) {
+		if len(v) > 0 {
+			parts = append(parts, name+" "+strings.Join(v, ", "))
+		}
+	}
+	flag := func(on, off string, v *bool) {
+		switch {
+		case v == nil:
+		case *v:
+			parts = append(parts, on)
+		default:
+			parts = append(parts, off)
+		}
+	}
+	list("keywords", f.Keywords)
+	list("not", f.ExcludeKeywords)
+	list("in", f.Locations)
+	list("tech", f.PreferredTech)
+	list("no tech", f.AvoidTech)
+	list("at", f.PreferredCompanies)
+	list("not at", f.AvoidCompanies)
+	list("level", f.SeniorityLevels)
+	list("language", f.AcceptedLanguages)
+	if len(f.HideStatuses) > 0 {
+		names := make([]string, len(f.HideStatuses))
+		for i, s := range f.HideStatuses {
+			names[i] = string(s)
+		}
+		list("hiding", names)
+	}
+	if f.MinScore > 0 || f.MaxScore > 0 {
+		parts = append(parts, "score "+strconv.Itoa(f.MinScore)+"–"+strconv.Itoa(cmp.Or(f.MaxScore, 100)))
+	}
+	if f.PostedWithinDays > 0 {
+		parts = append(parts, "last "+strconv.Itoa(f.PostedWithinDays)+plural(f.PostedWithinDays, " day", " days"))
+	}
+	flag("remote", "remote or not", f.PreferRemote)
+	flag("has email", "email or not", f.MustHaveEmail)
+	flag("no traps", "traps shown", f.ExcludeTraps)
+	flag("not applied", "applied shown", f.HideApplied)
+	if f.TrapSeverity != "" {
+		parts = append(parts, "traps from "+string(f.TrapSeverity))
+	}
+	if len(parts) == 0 {
+		return "no filters"
+	}
+	return strings.Join(parts, " · ")
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// ErrNoPresetName is returned saving a preset without a name.
+var ErrNoPresetName = errors.New("a filter preset needs a name")
+
+func migratePresets(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS filter_presets (
+			name TEXT PRIMARY KEY COLLATE NOCASE,
+			data TEXT NOT NULL
+		)`)
+	return err
+}
+
+// SavePreset stores f under its name, replacing a preset of that name in
+// any case.
+func (s *Store) SavePreset(f FilterPreset) error {
+	if f.Name = strings.TrimSpace(f.Name); f.Name == "" {
+		return ErrNoPresetName
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT OR REPLACE INTO filter_presets (name, data) VALUES (?, ?)`, f.Name, string(data))
+	return err
+}
+
+// Presets returns the saved presets by name.
+func (s *Store) Presets() ([]FilterPreset, error) {
+	rows, err := s.db.Query(`SELECT data FROM filter_presets ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var presets []FilterPreset
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var f FilterPreset
+		if err := json.Unmarshal([]byte(data), &f); err != nil {
+			return nil, err
+		}
+		presets = append(presets, f)
+	}
+	return presets, rows.Err()
+}
+
+// Preset returns the preset named name, in any case, or sql.ErrNoRows.
+func (s *Store) Preset(name string) (*FilterPreset, error) {
+	var data string
+	err := s.db.QueryRow(`SELECT data FROM filter_presets WHERE name = ?`, strings.TrimSpace(name)).Scan(&data)
+	if err != nil {
+		return nil, err
+	}
+	var f FilterPreset
+	if err := json.Unmarshal([]byte(data), &f); err != nil {
+		return nil, fmt.Errorf("filter preset %q: %w", name, err)
+	}
+	return &f, nil
+}
+
+// DeletePreset removes the preset named name, or returns sql.ErrNoRows.
+func (s *Store) DeletePreset(name string) error {
+	res, err := s.db.Exec(`DELETE FROM filter_presets WHERE name = ?`, strings.TrimSpace(name))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package profile
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+func TestFilterPreset_Apply(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	p := NewDefaultProfile()
+	p.MustHaveEmail = true
+	p.Locations = []string{"Berlin"}
+	yes, no := true, false
+	f := FilterPreset{Name: "remote rust", Keywords: []string{"rust"}, PostedWithinDays: 1, PreferRemote: &yes, MustHaveEmail: &no}
+
+	got := f.Apply(p, now)
+	if !reflect.DeepEqual(got.Keywords, []string{"rust"}) || !got.PreferRemote || got.MustHaveEmail {
+		t.Errorf("preset fields not applied: %+v", got)
+	}
+	if !reflect.DeepEqual(got.Locations, []string{"Berlin"}) || got.MinScore != p.MinScore || got.CVPath != p.CVPath {
+		t.Errorf("fields the preset leaves unset changed: %+v", got)
+	}
+	if want := now.AddDate(0, 0, -1); got.PostedAfter == nil || !got.PostedAfter.Equal(want) {
+		t.Errorf("posted after %v, want %v", got.PostedAfter, want)
+	}
+	if !p.MustHaveEmail || p.PreferRemote || p.Keywords[0] != "golang" {
+		t.Errorf("Apply changed the profile: %+v", p)
+	}
+
+	jobs := []job.Job{
+		{ID: "1", Title: "Rust Engineer", Location: "Remote, Berlin", PostedDate: now, Language: "en"},
+		{ID: "2", Title: "Go Engineer", Location: "Berlin", Email: "hr@acme.com", PostedDate: now, Language: "en"},
+	}
+	p.PostedAfter, p.SeniorityLevels = nil, nil
+	layered := f.Apply(p, now)
+	if out := job.Pipe(layered.GenerateFilters()...)(jobs); len(out) != 1 || out[0].ID != "1" {
+		t.Errorf("preset filters passed %v", out)
+	}
+	if out := job.Pipe(p.GenerateFilters()...)(jobs); len(out) != 0 {
+		t.Errorf("profile filters passed %v, want none (golang, with email)", out)
+	}
+}
+
+func TestFilterPreset_Summary(t *testing.T) {
+	yes := true
+	f := FilterPreset{Keywords: []string{"rust", "go"}, MinScore: 70, PostedWithinDays: 1, MustHaveEmail: &yes}
+	if got, want := f.Summary(), "keywords rust, go · score 70–100 · last 1 day · has email"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	if got := (FilterPreset{Name: "empty"}).Summary(); got != "no filters" {
+		t.Errorf("empty preset summary %q", got)
+	}
+}
+
+func TestStore_Presets(t *testing.T) {
+	js, err := job.OpenStore(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { js.Close() })
+	s, err := NewStore(js.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	yes := true
+	for _, f := range []FilterPreset{
+		{Name: "Remote Rust", Keywords: []string{"rust"}, PreferRemote: &yes},
+		{Name: "email contact", MustHaveEmail: &yes},
+		{Name: "remote rust", Keywords: []string{"rust", "wasm"}}, // replaces the first
+	} {
+		if err := s.SavePreset(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.SavePreset(FilterPreset{Name: "  "}); !errors.Is(err, ErrNoPresetName) {
+		t.Errorf("saved a preset without a name: %v", err)
+	}
+
+	all, err := s.Presets()
+	if err != nil || len(all) != 2 || all[0].Name != "email contact" || all[1].Name != "remote rust" {
+		t.Fatalf("Presets() = %+v, %v", all, err)
+	}
+	got, err := s.Preset("REMOTE RUST")
+	if err != nil || !reflect.DeepEqual(got.Keywords, []string{"rust", "wasm"}) || got.PreferRemote != nil {
+		t.Errorf("Preset() = %+v, %v", got, err)
+	}
+
+	if err := s.DeletePreset("Email Contact"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeletePreset("email contact"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleting it again: %v", err)
+	}
+	if _, err := s.Preset("email contact"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("deleted preset still read: %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package profile
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sprayer/src/api/job"
+)
+
+// KnownSources lists the Job.Source values the scrapers produce, the
+// default RSS feeds' names among them. Source weights are validated
+// against it so a typo does not silently do nothing.
+var KnownSources = []string{
+	"arbeitnow", "ashby", "authenticjobs", "crypto-jobs", "dice", "functional-works",
+	"glassdoor", "golang-cafe", "greenhouse", "hackernews", "indeed", "jobicy",
+	"linkedin", "nodejs-jobs", "pythonjobs", "remote.co", "remoteok", "remotive",
+	"rustjobs", "weworkremotely", "yc_work_at_startup",
+}
+
+// IsKnownSource reports whether name matches a scraper source or one of
+// extra, ignoring case.
+func IsKnownSource(name string, extra ...string) bool {
+	name = strings.TrimSpace(name)
+	for _, s := range slices.Concat(KnownSources, extra) {
+		if strings.EqualFold(s, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSourceWeights reads a "source:delta" comma list such as
+// "Greenhouse:+10, glassdoor:-15". Keys keep the spelling the user typed.
+// feeds names the profile's own RSS feeds, which are sources too.
+func ParseSourceWeights(s string, feeds ...string) (map[string]int, error) {
+	weights := map[string]int{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, delta, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("%q: expected source:delta", part)
+		}
+		name = strings.TrimSpace(name)
+		if !IsKnownSource(name, feeds...) {
+			return nil, fmt.Errorf("unknown source %q (known: %s)", name, strings.Join(slices.Concat(KnownSources, feeds), ", "))
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(delta))
+		if err != nil {
+			return nil, fmt.Errorf("%q: delta must be a whole number", part)
+		}
+		weights[name] = n
+	}
+	if len(weights) == 0 {
+		return nil, nil
+	}
+	return weights, nil
+}
+
+// FormatSourceWeights is the inverse of ParseSourceWeights, sorted by source.
+func FormatSourceWeights(weights map[string]int) string {
+	names := make([]string, 0, len(weights))
+	for name := range weights {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s:%+d", name, weights[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sourceWeight finds the configured delta for source, ignoring case.
+func (p *Profile) sourceWeight(source string) (string, int, bool) {
+	for name, delta := range p.SourceWeights {
+		if strings.EqualFold(name, source) {
+			return name, delta, true
+		}
+	}
+	return "", 0, false
+}
+
+// ScoreBreakdown explains how ScoreJob arrived at a job's score.
+type ScoreBreakdown struct {
+	Base        int    // CalculateJobScore result
+	Source      string // SourceWeights key that matched, if any
+	SourceDelta int
+	// Unmet names the authorizations and clearances the posting demands
+	// that the profile lacks; RequirementDelta is the penalty for them.
+	Unmet            []string
+	RequirementDelta int
+	// RuleDelta is what ingest rules added to the job's score.
+	RuleDelta int
+	Total     int // the sum of the above, clamped to 0-100
+}
+
+// Lines renders the breakdown for display, one step per line.
+func (b ScoreBreakdown) Lines() []string {
+	lines := []string{fmt.Sprintf("profile match: %d", b.Base)}
+	if b.Source != "" {
+		lines = append(lines, fmt.Sprintf("source adjustment: %+d (%s)", b.SourceDelta, b.Source))
+	}
+	for _, u := range b.Unmet {
+		lines = append(lines, fmt.Sprintf("requires: %s — not in your profile", u))
+	}
+	if b.RequirementDelta != 0 {
+		lines = append(lines, fmt.Sprintf("requirement penalty: %+d", b.RequirementDelta))
+	}
+	if b.RuleDelta != 0 {
+		lines = append(lines, fmt.Sprintf("rule adjustment: %+d", b.RuleDelta))
+	}
+	return append(lines, fmt.Sprintf("total: %d", b.Total))
+}
+
+// Explain scores j and reports each contribution.
+func (p *Profile) Explain(j *job.Job) ScoreBreakdown {
+	b := ScoreBreakdown{Base: p.CalculateJobScore(j)}
+	if name, delta, ok := p.sourceWeight(j.Source); ok {
+		b.Source, b.SourceDelta = name, delta
+	}
+	for _, r := range p.UnmetRequirements(j) {
+		b.Unmet = append(b.Unmet, r.String())
+	}
+	// An excluded job is filtered out instead of marked down.
+	if len(b.Unmet) > 0 && !p.ExcludeUnmetRequirements {
+		b.RequirementDelta = -RequirementPenalty
+	}
+	b.RuleDelta = j.ScoreAdjust
+	b.Total = min(max(b.Base+b.SourceDelta+b.RequirementDelta+b.RuleDelta, 0), 100)
+	return b
+}
+
+// ScoreJob is CalculateJobScore with the profile's per-source adjustment,
+// requirement penalty and any ingest rule adjustment applied. Scrapers and rescoring use it so both agree.
+func (p *Profile) ScoreJob(j *job.Job) int {
+	return p.Explain(j).Total
+}
@@ -0,0 +1,75 @@
+package profile_test
+
+import (
+	"strings"
+	"testing"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+func TestParseSourceWeights(t *testing.T) {
+	w, err := profile.ParseSourceWeights(" Greenhouse:+10, glassdoor:-15 ,")
+	if err != nil {
+		t.Fatalf("ParseSourceWeights: %v", err)
+	}
+	if w["Greenhouse"] != 10 || w["glassdoor"] != -15 || len(w) != 2 {
+		t.Errorf("weights = %v", w)
+	}
+	if got := profile.FormatSourceWeights(w); got != "Greenhouse:+10, glassdoor:-15" {
+		t.Errorf("FormatSourceWeights = %q", got)
+	}
+
+	for _, bad := range []string{"monster:+5", "greenhouse", "greenhouse:lots"} {
+		if _, err := profile.ParseSourceWeights(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+	if _, err := profile.ParseSourceWeights("monster:+5"); !strings.Contains(err.Error(), "unknown source") {
+		t.Errorf("unknown source error should say so: %v", err)
+	}
+}
+
+func TestExplain_SourceAdjustment(t *testing.T) {
+	// No scoring preferences, so the base is the neutral 50.
+	p := profile.Profile{SourceWeights: map[string]int{"Greenhouse": 10, "Glassdoor": -15}}
+
+	tests := []struct {
+		source string
+		delta  int
+		total  int
+	}{
+		{"greenhouse", 10, 60},
+		{"glassdoor", -15, 35},
+		{"remotive", 0, 50},
+	}
+	for _, tt := range tests {
+		j := job.Job{Title: "Engineer", Source: tt.source}
+		b := p.Explain(&j)
+		if b.Base != 50 || b.SourceDelta != tt.delta || b.Total != tt.total {
+			t.Errorf("%s: breakdown = %+v", tt.source, b)
+		}
+		if p.ScoreJob(&j) != b.Total {
+			t.Errorf("%s: ScoreJob disagrees with Explain", tt.source)
+		}
+	}
+
+	j := job.Job{Source: "greenhouse"}
+	lines := strings.Join(p.Explain(&j).Lines(), "\n")
+	if !strings.Contains(lines, "source adjustment: +10 (Greenhouse)") {
+		t.Errorf("breakdown lines:\n%s", lines)
+	}
+}
+
+func TestScoreJob_Clamps(t *testing.T) {
+	j := job.Job{Source: "dice"}
+
+	p := profile.Profile{SourceWeights: map[string]int{"dice": 80}}
+	if got := p.ScoreJob(&j); got != 100 {
+		t.Errorf("expected clamp to 100, got %d", got)
+	}
+	p.SourceWeights["dice"] = -80
+	if got := p.ScoreJob(&j); got != 0 {
+		t.Errorf("expected clamp to 0, got %d", got)
+	}
+}
@@ -1,9 +1,12 @@
 package profile
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"strings"
+
+	"sprayer/src/api/job"
 )
 
 // Store handles profile persistence.
@@ -31,26 +34,50 @@ func migrate(db *sql.DB) error {
 			prefer_remote BOOLEAN DEFAULT 0,
 			locations     TEXT
 		)`)
-	return err
+	if err != nil {
+		return err
+	}
+	if err := migratePresets(db); err != nil {
+		return err
+	}
+	// data holds the full profile as JSON; the flat columns above predate
+	// it and are kept for older readers.
+	return job.EnsureColumns(db, "profiles", []job.Column{
+		{Name: "data", Decl: "TEXT DEFAULT ''"},
+	})
 }
 
 // Save upserts a profile.
 func (s *Store) Save(p Profile) error {
+	return s.SaveContext(context.Background(), p)
+}
+
+// SaveContext is Save, abandoning the write if ctx is cancelled.
+func (s *Store) SaveContext(ctx context.Context, p Profile) error {
 	kw, _ := json.Marshal(p.Keywords)
 	locs, _ := json.Marshal(p.Locations)
-	_, err := s.db.Exec(`
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
 		INSERT OR REPLACE INTO profiles
-		(id, name, keywords, cv_path, cover_path, contact_email, prefer_remote, locations)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		(id, name, keywords, cv_path, cover_path, contact_email, prefer_remote, locations, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		p.ID, p.Name, string(kw), p.CVPath, p.CoverPath,
-		p.ContactEmail, p.PreferRemote, string(locs))
+		p.ContactEmail, p.PreferRemote, string(locs), string(data))
 	return err
 }
 
 // All returns all profiles.
 func (s *Store) All() ([]Profile, error) {
-	rows, err := s.db.Query(`
-		SELECT id, name, keywords, cv_path, cover_path, contact_email, prefer_remote, locations
+	return s.AllContext(context.Background())
+}
+
+// AllContext is All, abandoning the query if ctx is cancelled.
+func (s *Store) AllContext(ctx context.Context) ([]Profile, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, name, keywords, cv_path, cover_path, contact_email, prefer_remote, locations, data
 		FROM profiles ORDER BY name`)
 	if err != nil {
 		return nil, err
@@ -59,40 +86,66 @@ func (s *Store) All() ([]Profile, error) {
 
 	var profiles []Profile
 	for rows.Next() {
-		var p Profile
-		var kwJSON, locsJSON string
-		err := rows.Scan(&p.ID, &p.Name, &kwJSON, &p.CVPath, &p.CoverPath,
-			&p.ContactEmail, &p.PreferRemote, &locsJSON)
+		p, err := scanProfile(rows)
 		if err != nil {
 			return nil, err
 		}
-		json.Unmarshal([]byte(kwJSON), &p.Keywords)
-		json.Unmarshal([]byte(locsJSON), &p.Locations)
 		profiles = append(profiles, p)
 	}
-	return profiles, nil
+	return profiles, rows.Err()
 }
 
 // ByID returns a single profile.
 func (s *Store) ByID(id string) (*Profile, error) {
-	row := s.db.QueryRow(`
-		SELECT id, name, keywords, cv_path, cover_path, contact_email, prefer_remote, locations
+	return s.ByIDContext(context.Background(), id)
+}
+
+// ByIDContext is ByID, abandoning the query if ctx is cancelled.
+func (s *Store) ByIDContext(ctx context.Context, id string) (*Profile, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, name, keywords, cv_path, cover_path, contact_email, prefer_remote, locations, data
 		FROM profiles WHERE id = ?`, strings.ToLower(id))
 
-	var p Profile
-	var kwJSON, locsJSON string
-	err := row.Scan(&p.ID, &p.Name, &kwJSON, &p.CVPath, &p.CoverPath,
-		&p.ContactEmail, &p.PreferRemote, &locsJSON)
+	p, err := scanProfile(row)
 	if err != nil {
 		return nil, err
 	}
+	return &p, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanProfile reads a profile row, preferring the full JSON document and
+// falling back to the flat columns for rows saved before it existed.
+func scanProfile(sc scanner) (Profile, error) {
+	var p Profile
+	var kwJSON, locsJSON, data string
+	err := sc.Scan(&p.ID, &p.Name, &kwJSON, &p.CVPath, &p.CoverPath,
+		&p.ContactEmail, &p.PreferRemote, &locsJSON, &data)
+	if err != nil {
+		return Profile{}, err
+	}
+	if data != "" {
+		id := p.ID
+		if err := json.Unmarshal([]byte(data), &p); err == nil {
+			p.ID = id
+			return p, nil
+		}
+	}
 	json.Unmarshal([]byte(kwJSON), &p.Keywords)
 	json.Unmarshal([]byte(locsJSON), &p.Locations)
-	return &p, nil
+	return p, nil
 }
 
 // Delete removes a profile.
 func (s *Store) Delete(id string) error {
-	_, err := s.db.Exec("DELETE FROM profiles WHERE id = ?", id)
+	return s.DeleteContext(context.Background(), id)
+}
+
+// DeleteContext is Delete, abandoning the write if ctx is cancelled.
+func (s *Store) DeleteContext(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM profiles WHERE id = ?", id)
 	return err
 }
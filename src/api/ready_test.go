@@ -0,0 +1,121 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"sprayer/src/api/health"
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+// newReadyHandler returns a handler over a fresh database at path, with a
+// writable data directory.
+func newReadyHandler(t *testing.T, path string) *Handler {
+	t.Helper()
+	s, err := job.OpenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	ps, err := profile.NewStore(s.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHandler(s, ps)
+	h.dataDir = t.TempDir()
+	return h
+}
+
+type readyBody struct {
+	Status string          `json:"status"`
+	Failed []health.Result `json:"failed"`
+}
+
+func getReady(t *testing.T, h *Handler) (int, readyBody) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.Ready(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	var body readyBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, body
+}
+
+func assertFailed(t *testing.T, h *Handler, check string) {
+	t.Helper()
+	code, body := getReady(t, h)
+	if code != http.StatusServiceUnavailable || body.Status != "unavailable" {
+		t.Fatalf("got %d %q, want 503 unavailable", code, body.Status)
+	}
+	if len(body.Failed) != 1 || body.Failed[0].Name != check || body.Failed[0].Error == "" {
+		t.Fatalf("failed checks = %+v, want only %s with an error", body.Failed, check)
+	}
+}
+
+func TestReady_OK(t *testing.T) {
+	h := newReadyHandler(t, filepath.Join(t.TempDir(), "jobs.db"))
+	if code, body := getReady(t, h); code != http.StatusOK || body.Status != "ready" {
+		t.Fatalf("got %d %q, want 200 ready", code, body.Status)
+	}
+}
+
+func TestReady_ReadOnlyDB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	h := newReadyHandler(t, path)
+	ro, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ro.Close() })
+	h.store = &job.Store{DB: ro}
+	assertFailed(t, h, "db_writable")
+}
+
+func TestReady_MissingDataDir(t *testing.T) {
+	h := newReadyHandler(t, filepath.Join(t.TempDir(), "jobs.db"))
+	h.dataDir = filepath.Join(t.TempDir(), "gone")
+	assertFailed(t, h, "data_dir_writable")
+}
+
+func TestReady_SchemaMismatch(t *testing.T) {
+	h := newReadyHandler(t, filepath.Join(t.TempDir(), "jobs.db"))
+	if _, err := h.store.DB.Exec("PRAGMA user_version = 999"); err != nil {
+		t.Fatal(err)
+	}
+	assertFailed(t, h, "schema_version")
+}
+
+func TestReady_UnreachableExternal(t *testing.T) {
+	// A listener closed straight away leaves a port nothing answers on.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	h := newReadyHandler(t, filepath.Join(t.TempDir(), "jobs.db"))
+	h.CheckExternal(health.LLMReachable("http://"+addr, health.DefaultTimeout))
+	assertFailed(t, h, "llm_reachable")
+
+	h = newReadyHandler(t, filepath.Join(t.TempDir(), "jobs.db"))
+	h.CheckExternal(health.SMTPReachable(addr, health.DefaultTimeout))
+	assertFailed(t, h, "smtp_reachable")
+}
+
+func TestHealthCheck_Liveness(t *testing.T) {
+	h := newReadyHandler(t, filepath.Join(t.TempDir(), "jobs.db"))
+	h.dataDir = filepath.Join(t.TempDir(), "gone")
+	rec := httptest.NewRecorder()
+	h.HealthCheck(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("liveness got %d, want 200 even when not ready", rec.Code)
+	}
+}
@@ -0,0 +1,230 @@
+// Package redact scrubs personal data (name, email, phone) out of text
+// before it reaches logs, exports or an LLM provider.
+package redact
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sprayer/src/api/profile"
+)
+
+// Mask replaces every redacted value.
+const Mask = "[REDACTED]"
+
+// EnvExtra lists further strings to redact, comma-separated.
+const EnvExtra = "SPRAYER_REDACT"
+
+// EnvPrompts enables redaction of LLM prompt variables the model does not
+// need (see MaskVars).
+const EnvPrompts = "SPRAYER_LLM_REDACT"
+
+// minLen keeps short values from masking ordinary words.
+const minLen = 4
+
+// Redactor scrubs a fixed set of values. The zero value and nil scrub
+// nothing. It is safe for concurrent use.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// New builds a Redactor for values. Matching is case-insensitive, phone
+// numbers match whatever their separators, and values shorter than four
+// characters are ignored.
+func New(values ...string) *Redactor {
+	seen := make(map[string]bool)
+	r := &Redactor{}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		key := strings.ToLower(v)
+		if len(v) < minLen || seen[key] {
+			continue
+		}
+		seen[key] = true
+		r.patterns = append(r.patterns, pattern(v))
+	}
+	return r
+}
+
+// FromProfiles collects the personal fields of every profile, plus the
+// extra values configured in EnvExtra.
+func FromProfiles(profiles []profile.Profile) *Redactor {
+	var values []string
+	for _, p := range profiles {
+		values = append(values, p.ContactEmail)
+		if cv := p.CVData; cv != nil {
+			values = append(values, cv.Name, cv.Email, cv.Phone)
+		}
+	}
+	values = append(values, strings.Split(os.Getenv(EnvExtra), ",")...)
+	return New(values...)
+}
+
+var phoneChars = regexp.MustCompile(`^\+?[\d\s().\-/]+$`)
+
+// nationalDigits is how many trailing digits of an international number
+// are matched on their own, so "020 7946 0958" is caught for
+// "+44 20 7946 0958".
+const nationalDigits = 9
+
+// pattern matches v literally, or as its digits with any separators in
+// between when v looks like a phone number.
+func pattern(v string) *regexp.Regexp {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, v)
+	if !phoneChars.MatchString(v) || len(digits) < 7 {
+		return regexp.MustCompile(`(?i)` + regexp.QuoteMeta(v))
+	}
+	expr := `\+?` + digitPattern(digits)
+	if strings.HasPrefix(v, "+") && len(digits) > nationalDigits {
+		expr += `|` + digitPattern(digits[len(digits)-nationalDigits:])
+	}
+	return regexp.MustCompile(expr)
+}
+
+func digitPattern(digits string) string {
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 {
+			b.WriteString(`[\s().\-/]*`)
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
+// Scrub replaces every occurrence of the configured values with Mask.
+// Overlapping occurrences of different values are masked as one span.
+func (r *Redactor) Scrub(s string) string {
+	if r == nil || len(r.patterns) == 0 || s == "" {
+		return s
+	}
+	// Masking can butt two fragments together into a new match; rescan
+	// until nothing is left.
+	for range 4 {
+		spans := r.spans(s)
+		if len(spans) == 0 {
+			break
+		}
+		s = mask(s, spans)
+	}
+	return s
+}
+
+// spans returns the merged [start, end) ranges of all matches.
+func (r *Redactor) spans(s string) [][2]int {
+	var all [][2]int
+	for _, p := range r.patterns {
+		for _, m := range p.FindAllStringIndex(s, -1) {
+			all = append(all, [2]int{m[0], m[1]})
+		}
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i][0] < all[j][0] })
+	merged := all[:1]
+	for _, sp := range all[1:] {
+		last := &merged[len(merged)-1]
+		if sp[0] <= last[1] {
+			last[1] = max(last[1], sp[1])
+			continue
+		}
+		merged = append(merged, sp)
+	}
+	return merged
+}
+
+func mask(s string, spans [][2]int) string {
+	var b strings.Builder
+	prev := 0
+	for _, sp := range spans {
+		b.WriteString(s[prev:sp[0]])
+		b.WriteString(Mask)
+		prev = sp[1]
+	}
+	b.WriteString(s[prev:])
+	return b.String()
+}
+
+// ReplaceAttr scrubs attribute keys and values, for use as
+// slog.HandlerOptions.ReplaceAttr. Values are scrubbed before the handler
+// encodes them, so JSON output stays well-formed.
+func (r *Redactor) ReplaceAttr(_ []string, a slog.Attr) slog.Attr {
+	a.Key = r.Scrub(a.Key)
+	switch a.Value.Kind() {
+	case slog.KindString:
+		a.Value = slog.StringValue(r.Scrub(a.Value.String()))
+	case slog.KindAny:
+		if _, isLevel := a.Value.Any().(slog.Level); !isLevel {
+			a.Value = slog.StringValue(r.Scrub(fmt.Sprint(a.Value.Any())))
+		}
+	}
+	return a
+}
+
+// HandlerOptions returns a copy of opts whose ReplaceAttr scrubs after
+// any ReplaceAttr already set.
+func (r *Redactor) HandlerOptions(opts *slog.HandlerOptions) *slog.HandlerOptions {
+	out := slog.HandlerOptions{}
+	if opts != nil {
+		out = *opts
+	}
+	inner := out.ReplaceAttr
+	out.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+		if inner != nil {
+			a = inner(groups, a)
+		}
+		return r.ReplaceAttr(groups, a)
+	}
+	return &out
+}
+
+// Install makes a scrubbing text handler writing to w the default slog
+// handler. The standard log package then goes through it too.
+func Install(w io.Writer, r *Redactor) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(w, r.HandlerOptions(nil))))
+}
+
+// PromptsEnabled reports whether EnvPrompts is set.
+func PromptsEnabled() bool {
+	switch strings.ToLower(os.Getenv(EnvPrompts)) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+// MaskVars replaces the prompt variables named in placeholders with their
+// placeholder, e.g. {"applicant_phone": "[PHONE]"}, and scrubs the values
+// from every other variable. The returned function puts the real values
+// back into the model's output.
+func MaskVars(vars map[string]string, placeholders map[string]string) func(string) string {
+	var (
+		values  []string
+		restore []string
+	)
+	for key, ph := range placeholders {
+		if v := vars[key]; v != "" {
+			values = append(values, v)
+			restore = append(restore, ph, v)
+			vars[key] = ph
+		}
+	}
+	r := New(values...)
+	for key, v := range vars {
+		if _, masked := placeholders[key]; !masked {
+			vars[key] = r.Scrub(v)
+		}
+	}
+	return strings.NewReplacer(restore...).Replace
+}
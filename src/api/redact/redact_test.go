@@ -0,0 +1,136 @@
+package redact_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"sprayer/src/api/profile"
+	"sprayer/src/api/redact"
+)
+
+func TestScrub(t *testing.T) {
+	r := redact.New("Ada Lovelace", "Lovelace Labs", "ada@lovelace.dev", "+44 20 7946 0958", "Al")
+	tests := []struct{ in, want string }{
+		{"Contact Ada Lovelace today", "Contact [REDACTED] today"},
+		{"ADA LOVELACE / ada@LOVELACE.dev", "[REDACTED] / [REDACTED]"},
+		// Overlapping values are masked as one span.
+		{"at Ada Lovelace Labs.", "at [REDACTED]."},
+		{"call +442079460958 or (020) 7946-0958", "call [REDACTED] or (02[REDACTED]"},
+		{"call 20-7946-0958", "call 2[REDACTED]"},
+		// Values under four characters are ignored.
+		{"Al Green", "Al Green"},
+	}
+	for _, tt := range tests {
+		if got := r.Scrub(tt.in); got != tt.want {
+			t.Errorf("Scrub(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	var none *redact.Redactor
+	if got := none.Scrub("Ada Lovelace"); got != "Ada Lovelace" {
+		t.Errorf("nil redactor changed input: %q", got)
+	}
+}
+
+func TestFromProfiles(t *testing.T) {
+	t.Setenv(redact.EnvExtra, "Secret Project, ")
+	r := redact.FromProfiles([]profile.Profile{{
+		Name:         "default",
+		ContactEmail: "jobs@example.com",
+		CVData:       &profile.CVData{Name: "Grace Hopper", Email: "grace@example.com", Phone: "555 010 9999"},
+	}})
+	got := r.Scrub("default: Grace Hopper <grace@example.com>, jobs@example.com, 5550109999, secret project")
+	want := "default: [REDACTED] <[REDACTED]>, [REDACTED], [REDACTED], [REDACTED]"
+	if got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+// secret is a random value for property tests: long enough to be
+// redacted and unlikely to occur by chance.
+type secret string
+
+func (secret) Generate(rnd *rand.Rand, _ int) reflect.Value {
+	const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ \"\\<>&{}"
+	b := make([]byte, 6+rnd.Intn(10))
+	for i := range b {
+		b[i] = letters[rnd.Intn(len(letters))]
+	}
+	b[0] = 'q' // keep at least one letter ahead of any quote or space
+	return reflect.ValueOf(secret(b))
+}
+
+func TestHandler_NeverLeaks(t *testing.T) {
+	f := func(a, b secret, prefix, suffix string) bool {
+		r := redact.New(string(a), string(b))
+		for _, newHandler := range []func(*bytes.Buffer, *slog.HandlerOptions) slog.Handler{
+			func(w *bytes.Buffer, o *slog.HandlerOptions) slog.Handler { return slog.NewJSONHandler(w, o) },
+			func(w *bytes.Buffer, o *slog.HandlerOptions) slog.Handler { return slog.NewTextHandler(w, o) },
+		} {
+			var buf bytes.Buffer
+			log := slog.New(newHandler(&buf, r.HandlerOptions(nil)))
+			msg := prefix + string(a) + string(b) + suffix
+			log.Info(msg,
+				"email", string(a),
+				slog.Group("cv", "name", prefix+string(b), "err", errors.New("failed for "+string(a))),
+				string(b), 1)
+			out := buf.String()
+			if strings.Contains(out, string(a)) || strings.Contains(out, string(b)) {
+				t.Logf("leak in %s", out)
+				return false
+			}
+			if out[0] == '{' && !json.Valid(buf.Bytes()) {
+				t.Logf("invalid JSON: %s", out)
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScrub_NeverLeaks(t *testing.T) {
+	f := func(a, b secret, parts []string) bool {
+		r := redact.New(string(a), string(b))
+		s := strings.Join(append(parts, string(a), strings.ToUpper(string(b)), string(a)+string(b)), " ")
+		out := strings.ToLower(r.Scrub(s))
+		return !strings.Contains(out, strings.ToLower(string(a))) && !strings.Contains(out, strings.ToLower(string(b)))
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMaskVars(t *testing.T) {
+	vars := map[string]string{
+		"applicant_name":  "Ada Lovelace",
+		"applicant_phone": "+44 20 7946 0958",
+		"applicant_email": "ada@lovelace.dev",
+		"summary":         "Reach me at ada@lovelace.dev or +44 20 7946 0958.",
+	}
+	restore := redact.MaskVars(vars, map[string]string{
+		"applicant_phone": "[PHONE]",
+		"applicant_email": "[EMAIL]",
+	})
+	want := map[string]string{
+		"applicant_name":  "Ada Lovelace",
+		"applicant_phone": "[PHONE]",
+		"applicant_email": "[EMAIL]",
+		"summary":         "Reach me at [REDACTED] or [REDACTED].",
+	}
+	if !reflect.DeepEqual(vars, want) {
+		t.Errorf("vars = %v", vars)
+	}
+	if got := restore("Phone: [PHONE] | Email: [EMAIL]"); got != "Phone: +44 20 7946 0958 | Email: ada@lovelace.dev" {
+		t.Errorf("restore = %q", got)
+	}
+}
@@ -0,0 +1,177 @@
+package richtext
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// htmlToken matches a comment or a tag, capturing the slash of a closing
+// tag and the name.
+var htmlToken = regexp.MustCompile(`(?s)<!--.*?-->|<(/?)([a-zA-Z][a-zA-Z0-9]*)\b[^>]*>`)
+
+// FromHTML reads HTML: paragraphs, headings, nested lists, <pre> blocks
+// and table rows become blocks, other tags are dropped and entities
+// decoded. Scripts and styles are skipped.
+func FromHTML(text string) []Block {
+	p := htmlParser{}
+	last := 0
+	for _, m := range htmlToken.FindAllStringSubmatchIndex(text, -1) {
+		p.text(text[last:m[0]])
+		last = m[1]
+		if m[4] < 0 {
+			continue // a comment
+		}
+		p.tag(strings.ToLower(text[m[4]:m[5]]), m[3] > m[2])
+	}
+	p.text(text[last:])
+	p.flush()
+	return p.done()
+}
+
+type htmlParser struct {
+	builder
+	buf   strings.Builder
+	kind  Kind
+	level int
+
+	lists  []int // per open list, -1 for <ul>, else the last <ol> number
+	pre    bool
+	skip   string // the script or style being skipped
+	row    []string
+	inCell bool
+	cell   strings.Builder
+}
+
+func (p *htmlParser) text(s string) {
+	switch {
+	case p.skip != "":
+	case p.inCell:
+		p.cell.WriteString(s)
+	default:
+		p.buf.WriteString(s)
+	}
+}
+
+func (p *htmlParser) tag(name string, closing bool) {
+	if p.skip != "" {
+		if closing && name == p.skip {
+			p.skip = ""
+		}
+		return
+	}
+	if p.pre && !(closing && name == "pre") {
+		if name == "br" {
+			p.buf.WriteString("\n")
+		}
+		return
+	}
+	switch name {
+	case "script", "style", "head", "title":
+		if !closing {
+			p.skip = name
+		}
+	case "br":
+		if p.inCell {
+			p.cell.WriteString(" ")
+		} else {
+			p.flush()
+		}
+	case "p", "blockquote", "section", "article", "header", "footer":
+		p.flush()
+		p.brk()
+	case "div", "dt", "dd":
+		p.flush()
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		p.flush()
+		p.brk()
+		if !closing {
+			p.kind, p.level = Heading, int(name[1]-'0')
+		}
+	case "ul", "ol":
+		p.flush()
+		if !closing {
+			n := -1
+			if name == "ol" {
+				n = 0
+			}
+			p.lists = append(p.lists, n)
+		} else if len(p.lists) > 0 {
+			p.lists = p.lists[:len(p.lists)-1]
+			if len(p.lists) == 0 {
+				p.brk()
+			}
+		}
+	case "li":
+		p.flush()
+		if closing {
+			return
+		}
+		p.kind, p.level = Item, max(len(p.lists)-1, 0)
+		if n := len(p.lists) - 1; n >= 0 && p.lists[n] >= 0 {
+			p.lists[n]++
+			p.buf.WriteString(strconv.Itoa(p.lists[n]) + ". ")
+		}
+	case "pre":
+		p.flush()
+		p.brk()
+		if !closing {
+			p.pre = true
+			return
+		}
+		p.pre = false
+		code := strings.Trim(html.UnescapeString(p.buf.String()), "\n")
+		p.buf.Reset()
+		for _, line := range strings.Split(code, "\n") {
+			p.add(Block{Kind: Code, Text: strings.TrimRight(line, " \t\r")})
+		}
+		p.brk()
+	case "table":
+		p.flush()
+		p.brk()
+	case "tr":
+		p.flush()
+		if closing && len(p.row) > 0 {
+			p.add(Block{Kind: Table, Text: strings.Join(p.row, " │ ")})
+		}
+		p.row = nil
+	case "td", "th":
+		if !closing {
+			p.flush()
+			p.inCell = true
+			p.cell.Reset()
+			return
+		}
+		p.inCell = false
+		p.row = append(p.row, inlineText(p.cell.String()))
+	}
+}
+
+// flush ends the block being written, if it has any text.
+func (p *htmlParser) flush() {
+	if p.pre || p.inCell {
+		return
+	}
+	if s := inlineText(p.buf.String()); s != "" && !(p.kind == Item && isNumberOnly(s)) {
+		p.add(Block{Kind: p.kind, Text: s, Level: p.level})
+	}
+	p.buf.Reset()
+	if p.kind == Heading {
+		p.brk()
+	}
+	p.kind, p.level = Paragraph, 0
+}
+
+// inlineText decodes the entities of inline HTML text and squashes its
+// whitespace, non-breaking spaces included.
+func inlineText(s string) string {
+	return squash(strings.ReplaceAll(html.UnescapeString(s), "\u00a0", " "))
+}
+
+// isNumberOnly reports a list number left alone by an empty item.
+func isNumberOnly(s string) bool {
+	n := strings.TrimSuffix(s, ".")
+	_, err := strconv.Atoi(n)
+	return err == nil && n != s
+}
@@ -0,0 +1,130 @@
+package richtext
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	mdFence     = regexp.MustCompile("^\\s*(```|~~~)")
+	mdHeading   = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdItem      = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdRule      = regexp.MustCompile(`^\s*([-*_]\s*){3,}$`)
+	mdTableRow  = regexp.MustCompile(`^\s*\|.*\|\s*$`)
+	mdTableRule = regexp.MustCompile(`^[\s|:-]+$`)
+
+	mdImage  = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink   = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	mdStrong = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	mdEm     = regexp.MustCompile(`(^|[^\w*])[*_](\S(?:[^*_]*?\S)?)[*_]($|[^\w*])`)
+	mdCode   = regexp.MustCompile("`+([^`]+)`+")
+	mdEscape = regexp.MustCompile(`\\([\\` + "`" + `*_{}\[\]()#+\-.!|])`)
+)
+
+// FromMarkdown reads Markdown: headings, nested lists, fenced code,
+// tables and paragraphs, with emphasis, code spans and links reduced to
+// their text.
+func FromMarkdown(text string) []Block {
+	var b builder
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			b.add(Block{Kind: Paragraph, Text: mdInline(strings.Join(para, " "))})
+			para = nil
+		}
+	}
+	inCode, inItem := false, false
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if mdFence.MatchString(line) {
+			flush()
+			b.brk()
+			inCode, inItem = !inCode, false
+			continue
+		}
+		if inCode {
+			b.add(Block{Kind: Code, Text: strings.TrimRight(line, " \t")})
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			flush()
+			b.brk()
+			inItem = false
+			continue
+		}
+		if m := mdHeading.FindStringSubmatch(line); m != nil {
+			flush()
+			b.brk()
+			b.add(Block{Kind: Heading, Text: mdInline(m[2]), Level: len(m[1])})
+			b.brk()
+			inItem = false
+			continue
+		}
+		if mdRule.MatchString(line) {
+			flush()
+			b.brk()
+			inItem = false
+			continue
+		}
+		if m := mdItem.FindStringSubmatch(line); m != nil {
+			flush()
+			text := mdInline(m[3])
+			if m[2][0] >= '0' && m[2][0] <= '9' {
+				text = strings.TrimRight(m[2], ".)") + ". " + text
+			}
+			b.add(Block{Kind: Item, Text: text, Level: indentOf(m[1]) / 2})
+			inItem = true
+			continue
+		}
+		if mdTableRow.MatchString(line) {
+			flush()
+			if !mdTableRule.MatchString(line) {
+				cells := strings.Split(strings.Trim(strings.TrimSpace(line), "|"), "|")
+				for i, c := range cells {
+					cells[i] = mdInline(c)
+				}
+				b.add(Block{Kind: Table, Text: strings.Join(cells, " │ ")})
+			}
+			inItem = false
+			continue
+		}
+		if inItem {
+			// A lazy continuation of the item above.
+			last := &b.blocks[len(b.blocks)-1]
+			last.Text += " " + mdInline(line)
+			continue
+		}
+		para = append(para, strings.TrimSpace(line))
+	}
+	flush()
+	return b.done()
+}
+
+// mdInline reduces inline Markdown to its text. Escaped characters are
+// moved into the private use area while markup is stripped, so they
+// survive it.
+func mdInline(s string) string {
+	s = mdEscape.ReplaceAllStringFunc(s, func(e string) string {
+		return string(rune(e[1]) + escapeShift)
+	})
+	s = mdCode.ReplaceAllString(s, "$1")
+	s = mdImage.ReplaceAllString(s, "$1")
+	s = mdLink.ReplaceAllString(s, "$1")
+	s = mdStrong.ReplaceAllString(s, "$2")
+	s = mdEm.ReplaceAllString(s, "$1$2$3")
+	s = strings.Map(func(r rune) rune {
+		if r >= escapeShift && r < escapeShift+0x80 {
+			return r - escapeShift
+		}
+		return r
+	}, s)
+	return squash(html.UnescapeString(s))
+}
+
+// escapeShift moves an ASCII character into the private use area.
+const escapeShift = 0xE000
+
+// indentOf is the width of leading whitespace, a tab counting four.
+func indentOf(s string) int {
+	return len(strings.ReplaceAll(s, "\t", "    "))
+}
@@ -0,0 +1,119 @@
+// Package richtext reads job descriptions, which arrive as HTML
+// (RemoteOK, Greenhouse, HN), Markdown or plain text, into blocks a
+// terminal can lay out: paragraphs, headings, list items, code and table
+// rows. Inline markup is dropped and entities decoded.
+package richtext
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Format is how a description is written.
+type Format int
+
+const (
+	Plain Format = iota
+	HTML
+	Markdown
+)
+
+func (f Format) String() string {
+	switch f {
+	case HTML:
+		return "html"
+	case Markdown:
+		return "markdown"
+	}
+	return "plain"
+}
+
+var (
+	htmlSniff = regexp.MustCompile(`(?i)</?(p|br|div|span|ul|ol|li|h[1-6]|strong|b|em|i|a|pre|code|table|tr|td)\b[^>]*>|&(amp|lt|gt|nbsp|quot|#\d+|#x[0-9a-f]+);`)
+	mdSniff   = regexp.MustCompile("(?m)^(#{1,6} |\\s*[-*+] |\\s*\\d+[.)] |```|\\|.*\\|\\s*$)|\\*\\*[^*\\n]+\\*\\*|\\[[^\\]\\n]+\\]\\([^)\\n]+\\)")
+)
+
+// Sniff guesses the Format of text: HTML when it has tags or entities,
+// Markdown when it has headings, lists, fences, tables, bold or links.
+func Sniff(text string) Format {
+	switch {
+	case htmlSniff.MatchString(text):
+		return HTML
+	case mdSniff.MatchString(text):
+		return Markdown
+	}
+	return Plain
+}
+
+// Kind is the kind of a Block.
+type Kind int
+
+const (
+	Paragraph Kind = iota
+	Heading
+	Item  // a list item; Level is its depth from 0
+	Code  // one line of a code block, kept as is
+	Table // one row of a table, cells joined by " │ "
+	Break // a blank line between blocks
+)
+
+// Block is one piece of laid-out text.
+type Block struct {
+	Kind  Kind
+	Text  string
+	Level int
+}
+
+// Parse reads text in the Format Sniff finds.
+func Parse(text string) []Block {
+	switch Sniff(text) {
+	case HTML:
+		return FromHTML(text)
+	case Markdown:
+		return FromMarkdown(text)
+	}
+	return FromPlain(text)
+}
+
+// FromPlain keeps plain text's lines, one Paragraph each, with runs of
+// blank lines as one Break.
+func FromPlain(text string) []Block {
+	var b builder
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			b.brk()
+			continue
+		}
+		b.add(Block{Kind: Paragraph, Text: line})
+	}
+	return b.done()
+}
+
+// builder collects blocks, keeping Breaks single and off the ends.
+type builder struct {
+	blocks  []Block
+	pending bool // a Break is due before the next block
+}
+
+func (b *builder) brk() {
+	if len(b.blocks) > 0 {
+		b.pending = true
+	}
+}
+
+func (b *builder) add(bl Block) {
+	if b.pending {
+		b.blocks = append(b.blocks, Block{Kind: Break})
+		b.pending = false
+	}
+	b.blocks = append(b.blocks, bl)
+}
+
+func (b *builder) done() []Block { return b.blocks }
+
+var spaces = regexp.MustCompile(`\s+`)
+
+// squash collapses runs of whitespace to one space and trims the ends.
+func squash(s string) string {
+	return strings.TrimSpace(spaces.ReplaceAllString(s, " "))
+}
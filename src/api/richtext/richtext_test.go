@@ -0,0 +1,102 @@
+package richtext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSniff(t *testing.T) {
+	for _, tc := range []struct {
+		text string
+		want Format
+	}{
+		{"Build APIs in Go.\nRemote friendly.", Plain},
+		{"<p>Build APIs</p>", HTML},
+		{"Salary &amp; equity", HTML},
+		{"## About\nWe build things.", Markdown},
+		{"Perks:\n- remote\n- 4 day week", Markdown},
+		{"See [our site](https://acme.example).", Markdown},
+		{"Go, Rust - or both", Plain},
+	} {
+		if got := Sniff(tc.text); got != tc.want {
+			t.Errorf("Sniff(%q) = %v, want %v", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestFromHTML(t *testing.T) {
+	in := `<h2>About&nbsp;us</h2><p>We   build <b>tools</b> &amp; more.</p>
+<script>var x = "<p>no</p>";</script>
+<ul><li>Go<ul><li>generics</li></ul></li><li>SQL</li></ul>
+<ol><li>Apply</li><li>Interview</li></ol>
+<pre>func main() {
+	fmt.Println(&quot;hi&quot;)
+}</pre>
+<table><tr><th>Level</th><th>Pay</th></tr><tr><td>Senior</td><td>$150k</td></tr></table>
+<p>Thanks<br>Acme</p>`
+	want := []Block{
+		{Kind: Heading, Text: "About us", Level: 2},
+		{Kind: Break},
+		{Kind: Paragraph, Text: "We build tools & more."},
+		{Kind: Break},
+		{Kind: Item, Text: "Go"},
+		{Kind: Item, Text: "generics", Level: 1},
+		{Kind: Item, Text: "SQL"},
+		{Kind: Break},
+		{Kind: Item, Text: "1. Apply"},
+		{Kind: Item, Text: "2. Interview"},
+		{Kind: Break},
+		{Kind: Code, Text: "func main() {"},
+		{Kind: Code, Text: "\tfmt.Println(\"hi\")"},
+		{Kind: Code, Text: "}"},
+		{Kind: Break},
+		{Kind: Table, Text: "Level │ Pay"},
+		{Kind: Table, Text: "Senior │ $150k"},
+		{Kind: Break},
+		{Kind: Paragraph, Text: "Thanks"},
+		{Kind: Paragraph, Text: "Acme"},
+	}
+	if got := FromHTML(in); !reflect.DeepEqual(got, want) {
+		t.Errorf("FromHTML:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestFromMarkdown(t *testing.T) {
+	in := "# Senior Go Engineer\n\nWe build **fast** tools, see [the site](https://acme.example).\nRemote `first`.\n\n" +
+		"- Go\n  - generics\n- SQL and\n  Postgres\n\n1. Apply\n2) Interview\n\n" +
+		"```go\nfunc main() {}\n```\n\n| Level | Pay |\n|---|---:|\n| Senior | $150k |\n\n---\nThanks \\*all\\*"
+	want := []Block{
+		{Kind: Heading, Text: "Senior Go Engineer", Level: 1},
+		{Kind: Break},
+		{Kind: Paragraph, Text: "We build fast tools, see the site. Remote first."},
+		{Kind: Break},
+		{Kind: Item, Text: "Go"},
+		{Kind: Item, Text: "generics", Level: 1},
+		{Kind: Item, Text: "SQL and Postgres"},
+		{Kind: Break},
+		{Kind: Item, Text: "1. Apply"},
+		{Kind: Item, Text: "2. Interview"},
+		{Kind: Break},
+		{Kind: Code, Text: "func main() {}"},
+		{Kind: Break},
+		{Kind: Table, Text: "Level │ Pay"},
+		{Kind: Table, Text: "Senior │ $150k"},
+		{Kind: Break},
+		{Kind: Paragraph, Text: "Thanks *all*"},
+	}
+	if got := FromMarkdown(in); !reflect.DeepEqual(got, want) {
+		t.Errorf("FromMarkdown:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestFromPlain(t *testing.T) {
+	want := []Block{
+		{Kind: Paragraph, Text: "Line one"},
+		{Kind: Paragraph, Text: "Line two"},
+		{Kind: Break},
+		{Kind: Paragraph, Text: "Line three"},
+	}
+	if got := FromPlain("\nLine one\nLine two\n\n\n  Line three  \n"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FromPlain:\n got %+v\nwant %+v", got, want)
+	}
+}
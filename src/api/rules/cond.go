@@ -0,0 +1,321 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"sprayer/src/api/job"
+)
+
+// Cond decides whether a rule applies to a job.
+type Cond interface {
+	Match(j job.Job) bool
+	String() string
+}
+
+// Fields lists the predicates a condition can use.
+var Fields = []string{"keyword", "title", "company", "source", "location", "salary", "tag"}
+
+// predicate is one field:value test, run through the job filter that does
+// the same test on a list.
+type predicate struct {
+	field, value string
+	filter       job.Filter
+}
+
+func (p predicate) Match(j job.Job) bool { return len(p.filter([]job.Job{j})) == 1 }
+
+func (p predicate) String() string {
+	if strings.ContainsAny(p.value, " ()\"") || p.value == "" {
+		return p.field + ":" + strconv.Quote(p.value)
+	}
+	return p.field + ":" + p.value
+}
+
+type allOf []Cond
+
+func (c allOf) Match(j job.Job) bool {
+	for _, sub := range c {
+		if !sub.Match(j) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c allOf) String() string {
+	parts := make([]string, len(c))
+	for i, sub := range c {
+		parts[i] = sub.String()
+		if _, ok := sub.(anyOf); ok {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " and ")
+}
+
+type anyOf []Cond
+
+func (c anyOf) Match(j job.Job) bool {
+	for _, sub := range c {
+		if sub.Match(j) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c anyOf) String() string {
+	parts := make([]string, len(c))
+	for i, sub := range c {
+		parts[i] = sub.String()
+	}
+	return strings.Join(parts, " or ")
+}
+
+type not struct{ Cond }
+
+func (c not) Match(j job.Job) bool { return !c.Cond.Match(j) }
+
+func (c not) String() string {
+	if _, ok := c.Cond.(predicate); ok {
+		return "not " + c.Cond.String()
+	}
+	return "not (" + c.Cond.String() + ")"
+}
+
+// ParseCond reads a condition such as
+//
+//	keyword:on-call or (company:"Acme Recruiting" and not location:remote)
+//
+// Predicates are field:value, with the value quoted when it holds spaces
+// or parentheses. "not" binds tightest, then "and", then "or"; predicates
+// side by side are joined with "and". Text predicates match substrings
+// ignoring case, as the list filters do; salary takes a comparison such
+// as salary:>=90000 or salary:<60k.
+func ParseCond(s string) (Cond, error) {
+	toks, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(toks) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+	p := &parser{toks: toks}
+	c, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(toks) {
+		return nil, fmt.Errorf("unexpected %q", toks[p.pos])
+	}
+	return c, nil
+}
+
+// tokenize splits s into parentheses and words, keeping quoted text in a
+// word whole.
+func tokenize(s string) ([]string, error) {
+	var toks []string
+	var cur strings.Builder
+	inQuote := false
+	flush := func() {
+		if cur.Len() > 0 {
+			toks = append(toks, cur.String())
+			cur.Reset()
+		}
+	}
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			cur.WriteRune(r)
+		case inQuote:
+			cur.WriteRune(r)
+		case r == '(' || r == ')':
+			flush()
+			toks = append(toks, string(r))
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	flush()
+	return toks, nil
+}
+
+type parser struct {
+	toks []string
+	pos  int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.toks) {
+		return p.toks[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) isWord(w string) bool { return strings.EqualFold(p.peek(), w) }
+
+func (p *parser) or() (Cond, error) {
+	var alts anyOf
+	for {
+		c, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		alts = append(alts, c)
+		if !p.isWord("or") {
+			break
+		}
+		p.pos++
+	}
+	if len(alts) == 1 {
+		return alts[0], nil
+	}
+	return alts, nil
+}
+
+func (p *parser) and() (Cond, error) {
+	var all allOf
+	for {
+		c, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, c)
+		if p.isWord("and") {
+			p.pos++
+			continue
+		}
+		if next := p.peek(); next == "" || next == ")" || p.isWord("or") {
+			break
+		}
+	}
+	if len(all) == 1 {
+		return all[0], nil
+	}
+	return all, nil
+}
+
+func (p *parser) unary() (Cond, error) {
+	tok := p.peek()
+	switch {
+	case tok == "":
+		if p.pos == 0 {
+			return nil, fmt.Errorf("empty condition")
+		}
+		return nil, fmt.Errorf("condition ends after %q", p.toks[p.pos-1])
+	case strings.EqualFold(tok, "not"):
+		p.pos++
+		c, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return not{c}, nil
+	case tok == "(":
+		p.pos++
+		c, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return c, nil
+	case tok == ")" || strings.EqualFold(tok, "and") || strings.EqualFold(tok, "or"):
+		return nil, fmt.Errorf("unexpected %q", tok)
+	}
+	p.pos++
+	return parsePredicate(tok)
+}
+
+func parsePredicate(tok string) (Cond, error) {
+	field, value, ok := strings.Cut(tok, ":")
+	if !ok {
+		return nil, fmt.Errorf("%q is not field:value (fields: %s)", tok, strings.Join(Fields, ", "))
+	}
+	field = strings.ToLower(field)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+	if strings.TrimSpace(value) == "" {
+		return nil, fmt.Errorf("%s: needs a value", field)
+	}
+
+	p := predicate{field: field, value: value}
+	switch field {
+	case "keyword":
+		p.filter = job.ByKeywords([]string{value})
+	case "title":
+		p.filter = func(jobs []job.Job) []job.Job {
+			return job.Select(jobs, func(j job.Job) bool {
+				return strings.Contains(strings.ToLower(j.Title), strings.ToLower(value))
+			})
+		}
+	case "company":
+		p.filter = job.ByCompany(value)
+	case "source":
+		p.filter = job.BySource(value)
+	case "location":
+		p.filter = job.ByLocation(value)
+	case "tag":
+		p.filter = job.HasTag(value)
+	case "salary":
+		f, err := salaryFilter(value)
+		if err != nil {
+			return nil, err
+		}
+		p.filter = f
+	default:
+		return nil, fmt.Errorf("unknown field %q (fields: %s)", field, strings.Join(Fields, ", "))
+	}
+	return p, nil
+}
+
+// salaryFilter reads ">=90000", "<60k" and the like.
+func salaryFilter(value string) (job.Filter, error) {
+	var op string
+	for _, o := range []string{">=", "<=", ">", "<"} {
+		if strings.HasPrefix(value, o) {
+			op = o
+			break
+		}
+	}
+	amount, err := parseAmount(strings.TrimPrefix(value, op))
+	if err != nil {
+		return nil, fmt.Errorf("salary:%s: %v", value, err)
+	}
+	switch op {
+	case ">=":
+		return job.BySalaryAtLeast(amount), nil
+	case ">":
+		return job.BySalaryAtLeast(amount + 1), nil
+	case "<":
+		return job.BySalaryBelow(amount), nil
+	case "<=":
+		return job.BySalaryBelow(amount + 1), nil
+	}
+	return nil, fmt.Errorf("salary:%s: want a comparison such as >=90000 or <60k", value)
+}
+
+// parseAmount reads a whole number with an optional k suffix.
+func parseAmount(s string) (int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	mult := 1
+	if rest, ok := strings.CutSuffix(s, "k"); ok {
+		s, mult = rest, 1000
+	}
+	n, err := strconv.Atoi(strings.ReplaceAll(s, ",", ""))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q is not an amount", s)
+	}
+	return n * mult, nil
+}
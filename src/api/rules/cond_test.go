@@ -0,0 +1,106 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+
+	"sprayer/src/api/job"
+)
+
+var (
+	onCall = job.Job{ID: "a", Title: "SRE", Company: "Acme", Source: "remoteok", Location: "Remote, US",
+		Description: "Weekly on-call rotation", SalaryMin: 90000, SalaryMax: 120000}
+	agency = job.Job{ID: "b", Title: "Go Developer", Company: "Talent Recruiting Ltd", Source: "hn",
+		Location: "London", Description: "Our client is hiring", Tags: []string{"agency"}}
+	plain = job.Job{ID: "c", Title: "Backend Engineer", Company: "Widgets", Source: "remotive",
+		Location: "Berlin", Description: "Build APIs", SalaryMin: 50000}
+)
+
+func TestParseCond_Composition(t *testing.T) {
+	tests := []struct {
+		expr string
+		want []bool // onCall, agency, plain
+	}{
+		{"keyword:on-call", []bool{true, false, false}},
+		{"KEYWORD:ON-CALL", []bool{true, false, false}},
+		{"company:recruiting or source:remotive", []bool{false, true, true}},
+		{"location:remote and keyword:on-call", []bool{true, false, false}},
+		{"location:remote keyword:build", []bool{false, false, false}},
+		{"not location:remote", []bool{false, true, true}},
+		{"not (location:remote or location:london)", []bool{false, false, true}},
+		{"keyword:hiring or keyword:apis and location:berlin", []bool{false, true, true}},
+		{"(keyword:hiring or keyword:apis) and location:berlin", []bool{false, false, true}},
+		{`company:"talent recruiting"`, []bool{false, true, false}},
+		{"title:engineer", []bool{false, false, true}},
+		{"tag:agency", []bool{false, true, false}},
+		{"salary:>=100000", []bool{true, false, false}},
+		{"salary:<60k", []bool{false, false, true}},
+		{"salary:>50000", []bool{true, false, false}},
+		{"salary:<=50000", []bool{false, false, true}},
+		{"source:HN", []bool{false, true, false}},
+	}
+	for _, tt := range tests {
+		c, err := ParseCond(tt.expr)
+		if err != nil {
+			t.Errorf("ParseCond(%q): %v", tt.expr, err)
+			continue
+		}
+		for i, j := range []job.Job{onCall, agency, plain} {
+			if got := c.Match(j); got != tt.want[i] {
+				t.Errorf("%q on %s = %v, want %v", tt.expr, j.Title, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestParseCond_StringRoundTrips(t *testing.T) {
+	for _, expr := range []string{
+		"keyword:on-call",
+		`company:"Talent Recruiting" and not location:remote`,
+		"(keyword:a or keyword:b) and salary:>=90000",
+		"not (tag:agency or source:hn)",
+	} {
+		c, err := ParseCond(expr)
+		if err != nil {
+			t.Fatalf("ParseCond(%q): %v", expr, err)
+		}
+		if c.String() != expr {
+			t.Errorf("String() = %q, want %q", c.String(), expr)
+		}
+		again, err := ParseCond(c.String())
+		if err != nil || again.String() != expr {
+			t.Errorf("reparsing %q: %v, %v", c.String(), again, err)
+		}
+	}
+}
+
+func TestParseCond_Errors(t *testing.T) {
+	tests := map[string]string{
+		"":                     "empty",
+		"on-call":              "not field:value",
+		"colour:red":           "unknown field",
+		"keyword:":             "needs a value",
+		"keyword:a and":        "ends after",
+		"(keyword:a":           "missing )",
+		"keyword:a)":           `unexpected ")"`,
+		"or keyword:a":         `unexpected "or"`,
+		`company:"acme`:        "unterminated quote",
+		"salary:lots":          "not an amount",
+		"salary:90000":         "want a comparison",
+		"keyword:a or or b:c":  `unexpected "or"`,
+		"not":                  "ends after",
+		"keyword:a and ( )":    `unexpected ")"`,
+		"location:remote)(":    `unexpected ")"`,
+		"salary:>=-5":          "not an amount",
+		"keyword:a not":        "ends after",
+		"keyword:a and and b":  `unexpected "and"`,
+		"keyword:x or (tag:)":  "needs a value",
+		"company:acme salary:": "needs a value",
+	}
+	for expr, want := range tests {
+		_, err := ParseCond(expr)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseCond(%q) = %v, want an error containing %q", expr, err, want)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+// Package rules tags, rescores and hides jobs as they are ingested, by
+// user-defined rules kept in the database. A rule pairs a condition with
+// actions:
+//
+//	when: keyword:on-call or keyword:pager
+//	do:   tag:on-call, score:-15
+//
+// Enabled rules run in position order on every job a scrape saves, each
+// seeing what earlier rules did, so a later rule can match tag:on-call.
+package rules
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// ActionKind is what an action does to a matching job.
+type ActionKind string
+
+const (
+	ActionTag   ActionKind = "tag"   // add a tag
+	ActionScore ActionKind = "score" // adjust the score by a delta
+	ActionHide  ActionKind = "hide"  // hide the job in the ingesting profile
+	ActionNote  ActionKind = "note"  // set the prefix of the job's note
+)
+
+// Action is one step a rule takes on a job it matches.
+type Action struct {
+	Kind  ActionKind
+	Value string // the tag or note prefix
+	Delta int    // the score adjustment
+}
+
+// ParseAction reads an action as written on the command line: tag:NAME,
+// score:+N or score:-N, hide, or note:TEXT.
+func ParseAction(s string) (Action, error) {
+	kind, value, _ := strings.Cut(strings.TrimSpace(s), ":")
+	switch a := (Action{Kind: ActionKind(strings.ToLower(kind)), Value: value}); a.Kind {
+	case ActionTag:
+		a.Value = strings.TrimSpace(a.Value)
+		if a.Value == "" || strings.Contains(a.Value, ",") {
+			return Action{}, fmt.Errorf("tag:%s: want a tag without commas", value)
+		}
+		return a, nil
+	case ActionScore:
+		d, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || d == 0 {
+			return Action{}, fmt.Errorf("score:%s: want a delta such as +10 or -20", value)
+		}
+		a.Value, a.Delta = "", d
+		return a, nil
+	case ActionHide:
+		if value != "" {
+			return Action{}, fmt.Errorf("hide takes no value")
+		}
+		return a, nil
+	case ActionNote:
+		if a.Value = strings.TrimSpace(a.Value); a.Value == "" {
+			return Action{}, fmt.Errorf("note: needs text")
+		}
+		return a, nil
+	}
+	return Action{}, fmt.Errorf("unknown action %q (want tag:NAME, score:±N, hide or note:TEXT)", s)
+}
+
+// ParseActions reads a comma- or newline-separated action list. A note's
+// text runs to the end of its line, so it may hold commas.
+func ParseActions(s string) ([]Action, error) {
+	var out []Action
+	for _, line := range strings.Split(s, "\n") {
+		for rest := strings.TrimSpace(line); rest != ""; {
+			item := rest
+			if strings.HasPrefix(strings.ToLower(rest), string(ActionNote)+":") {
+				rest = ""
+			} else {
+				item, rest, _ = strings.Cut(rest, ",")
+				rest = strings.TrimSpace(rest)
+			}
+			if strings.TrimSpace(item) == "" {
+				continue
+			}
+			a, err := ParseAction(item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, a)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("a rule needs at least one action")
+	}
+	return out, nil
+}
+
+func (a Action) String() string {
+	switch a.Kind {
+	case ActionScore:
+		return fmt.Sprintf("score:%+d", a.Delta)
+	case ActionHide:
+		return string(ActionHide)
+	}
+	return string(a.Kind) + ":" + a.Value
+}
+
+// FormatActions writes actions back in the form ParseActions reads, a
+// note last on its own line.
+func FormatActions(actions []Action) string {
+	var parts, notes []string
+	for _, a := range actions {
+		if a.Kind == ActionNote {
+			notes = append(notes, a.String())
+		} else {
+			parts = append(parts, a.String())
+		}
+	}
+	if len(parts) > 0 {
+		notes = append([]string{strings.Join(parts, ", ")}, notes...)
+	}
+	return strings.Join(notes, "\n")
+}
+
+// Rule is a named condition and the actions taken on jobs matching it.
+type Rule struct {
+	ID       int64
+	Name     string
+	When     string // condition source, see ParseCond
+	Actions  []Action
+	Enabled  bool
+	Position int // rules run in ascending position
+	Hits     int // new jobs matched at ingest
+	LastHit  *time.Time
+}
+
+// Outcome is what a set of rules did to one job.
+type Outcome struct {
+	Matched []Rule
+	// Hide is set when a matching rule hides the job; hiding is profile
+	// state, so the caller records it.
+	Hide bool
+}
+
+// Set is an ordered, compiled list of enabled rules.
+type Set struct {
+	rules []Rule
+	conds []Cond
+}
+
+// Compile prepares the enabled rules in position order, ties broken by
+// ID so evaluation never depends on how they were loaded.
+func Compile(rules []Rule) (*Set, error) {
+	sorted := append([]Rule(nil), rules...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		if sorted[a].Position != sorted[b].Position {
+			return sorted[a].Position < sorted[b].Position
+		}
+		return sorted[a].ID < sorted[b].ID
+	})
+	s := &Set{}
+	for _, r := range sorted {
+		if !r.Enabled {
+			continue
+		}
+		c, err := ParseCond(r.When)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+		s.rules = append(s.rules, r)
+		s.conds = append(s.conds, c)
+	}
+	return s, nil
+}
+
+// Rules returns the compiled rules in evaluation order.
+func (s *Set) Rules() []Rule {
+	return append([]Rule(nil), s.rules...)
+}
+
+// Apply runs the rules over j in order. Each rule sees j as the rules
+// before it left it. Tags are added once each, score deltas add up with
+// Score kept within 0-100, and the last note action to match is put
+// in front of the job's note. j should be as scraped; Reset undoes the
+// rules on a stored job.
+func (s *Set) Apply(j *job.Job) Outcome {
+	var out Outcome
+	prefix := ""
+	for i, r := range s.rules {
+		if !s.conds[i].Match(*j) {
+			continue
+		}
+		out.Matched = append(out.Matched, r)
+		for _, a := range r.Actions {
+			switch a.Kind {
+			case ActionTag:
+				if len(job.HasTag(a.Value)([]job.Job{*j})) == 0 {
+					j.Tags = append(j.Tags, a.Value)
+				}
+			case ActionScore:
+				j.ScoreAdjust += a.Delta
+				j.Score = min(max(j.Score+a.Delta, 0), 100)
+			case ActionHide:
+				out.Hide = true
+			case ActionNote:
+				prefix = a.Value
+			}
+		}
+	}
+	if prefix != "" {
+		j.Note = strings.TrimSpace(prefix + " " + j.Note)
+	}
+	return out
+}
+
+// Reset removes what rules did to a stored job, so Apply can show what
+// the current rules would do instead. A score clamped at ingest is not
+// restored exactly.
+func Reset(j job.Job) job.Job {
+	j.Score = min(max(j.Score-j.ScoreAdjust, 0), 100)
+	j.ScoreAdjust, j.Tags, j.Note = 0, nil, ""
+	return j
+}
@@ -0,0 +1,164 @@
+package rules
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mustActions(t *testing.T, s string) []Action {
+	t.Helper()
+	a, err := ParseActions(s)
+	if err != nil {
+		t.Fatalf("ParseActions(%q): %v", s, err)
+	}
+	return a
+}
+
+func TestParseActions(t *testing.T) {
+	got := mustActions(t, "tag:on-call, score:-15, hide\nnote:Agency, check the client")
+	want := []Action{
+		{Kind: ActionTag, Value: "on-call"},
+		{Kind: ActionScore, Delta: -15},
+		{Kind: ActionHide},
+		{Kind: ActionNote, Value: "Agency, check the client"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v\nwant %+v", got, want)
+	}
+	if s := FormatActions(got); !reflect.DeepEqual(mustActions(t, s), want) {
+		t.Errorf("FormatActions = %q does not parse back", s)
+	}
+
+	for _, bad := range []string{"", "tag:", "score:lots", "score:0", "hide:now", "note:", "star"} {
+		if _, err := ParseActions(bad); err == nil {
+			t.Errorf("ParseActions(%q) should fail", bad)
+		}
+	}
+}
+
+func compile(t *testing.T, rules ...Rule) *Set {
+	t.Helper()
+	for i := range rules {
+		rules[i].Enabled = !strings.HasPrefix(rules[i].Name, "off-")
+		if rules[i].Position == 0 {
+			rules[i].Position = i + 1
+		}
+		rules[i].ID = int64(i + 1)
+	}
+	s, err := Compile(rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestApply_LaterRulesSeeEarlierActions(t *testing.T) {
+	s := compile(t,
+		Rule{Name: "on-call", When: "keyword:on-call", Actions: mustActions(t, "tag:on-call, score:-10")},
+		Rule{Name: "on-call-remote", When: "tag:on-call and location:remote", Actions: mustActions(t, "tag:remote-pager")},
+	)
+	j := onCall
+	j.Score = 50
+	out := s.Apply(&j)
+	if len(out.Matched) != 2 || out.Hide {
+		t.Fatalf("matched %v, hide %v", out.Matched, out.Hide)
+	}
+	if !reflect.DeepEqual(j.Tags, []string{"on-call", "remote-pager"}) || j.Score != 40 || j.ScoreAdjust != -10 {
+		t.Errorf("job = tags %v score %d adjust %d", j.Tags, j.Score, j.ScoreAdjust)
+	}
+	if onCall.Tags != nil {
+		t.Error("Apply changed the original job's tags")
+	}
+}
+
+func TestApply_PositionDecidesOrder(t *testing.T) {
+	// Listed out of order; positions put the tagging rule first.
+	s := compile(t,
+		Rule{Name: "needs-tag", When: "tag:pager", Actions: mustActions(t, "score:+5"), Position: 2},
+		Rule{Name: "tagger", When: "keyword:on-call", Actions: mustActions(t, "tag:pager"), Position: 1},
+	)
+	var names []string
+	for _, r := range s.Rules() {
+		names = append(names, r.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"tagger", "needs-tag"}) {
+		t.Fatalf("order = %v", names)
+	}
+	j := onCall
+	s.Apply(&j)
+	if j.ScoreAdjust != 5 {
+		t.Errorf("the later rule should see the tag: adjust %d", j.ScoreAdjust)
+	}
+}
+
+func TestApply_Actions(t *testing.T) {
+	s := compile(t,
+		Rule{Name: "first-note", When: "keyword:on-call", Actions: mustActions(t, "note:Pager duty")},
+		Rule{Name: "off-disabled", When: "keyword:on-call", Actions: mustActions(t, "hide")},
+		Rule{Name: "big-penalty", When: "keyword:on-call", Actions: mustActions(t, "score:-80, tag:on-call, tag:ON-CALL")},
+		Rule{Name: "last-note", When: "location:remote", Actions: mustActions(t, "note:Remote pager")},
+		Rule{Name: "no-match", When: "company:widgets", Actions: mustActions(t, "hide")},
+	)
+	j := onCall
+	j.Score, j.Note = 30, "seen at meetup"
+	out := s.Apply(&j)
+
+	if out.Hide {
+		t.Error("disabled and unmatched rules must not hide")
+	}
+	if j.Score != 0 || j.ScoreAdjust != -80 {
+		t.Errorf("score %d adjust %d, want clamped to 0 with the full delta kept", j.Score, j.ScoreAdjust)
+	}
+	if !reflect.DeepEqual(j.Tags, []string{"on-call"}) {
+		t.Errorf("tags = %v, want one on-call", j.Tags)
+	}
+	if j.Note != "Remote pager seen at meetup" {
+		t.Errorf("note = %q, want the last matching prefix", j.Note)
+	}
+
+	hide := compile(t, Rule{Name: "hide-agencies", When: "tag:agency", Actions: mustActions(t, "hide")})
+	a := agency
+	if !hide.Apply(&a).Hide {
+		t.Error("hide rule did not hide")
+	}
+}
+
+func TestApply_Deterministic(t *testing.T) {
+	rules := []Rule{
+		{Name: "a", When: "keyword:on-call", Actions: mustActions(t, "tag:x, score:+3")},
+		{Name: "b", When: "tag:x or company:acme", Actions: mustActions(t, "tag:y, note:b")},
+		{Name: "c", When: "not tag:y", Actions: mustActions(t, "tag:z")},
+	}
+	first := onCall
+	compile(t, rules...).Apply(&first)
+	for range 20 {
+		j := onCall
+		compile(t, rules...).Apply(&j)
+		if !reflect.DeepEqual(j, first) {
+			t.Fatalf("run gave %+v, first gave %+v", j, first)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	s := compile(t, Rule{Name: "r", When: "keyword:on-call", Actions: mustActions(t, "tag:x, score:+10, note:n")})
+	j := onCall
+	j.Score = 40
+	s.Apply(&j)
+	again := Reset(j)
+	s.Apply(&again)
+	if !reflect.DeepEqual(again, j) {
+		t.Errorf("reapplying after Reset gave %+v, want %+v", again, j)
+	}
+}
+
+func TestCompile_BadCondition(t *testing.T) {
+	_, err := Compile([]Rule{{Name: "broken", When: "colour:red", Enabled: true}})
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("err = %v, want the rule named", err)
+	}
+	if _, err := Compile([]Rule{{Name: "off", When: "colour:red"}}); err != nil {
+		t.Errorf("a disabled rule should not be compiled: %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package rules
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// Store persists rules next to the jobs they act on.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore wraps a database connection for rule storage.
+func NewStore(db *sql.DB) (*Store, error) {
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS ingest_rules (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			name       TEXT NOT NULL UNIQUE,
+			position   INTEGER NOT NULL,
+			condition  TEXT NOT NULL,
+			actions    TEXT NOT NULL,
+			enabled    BOOLEAN DEFAULT 1,
+			hits       INTEGER DEFAULT 0,
+			last_hit   DATETIME DEFAULT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`)
+	return err
+}
+
+const columns = `id, name, position, condition, actions, enabled, hits, last_hit`
+
+func scanRule(sc interface{ Scan(...any) error }) (Rule, error) {
+	var r Rule
+	var actions string
+	if err := sc.Scan(&r.ID, &r.Name, &r.Position, &r.When, &actions, &r.Enabled, &r.Hits, &r.LastHit); err != nil {
+		return r, err
+	}
+	var err error
+	if r.Actions, err = ParseActions(actions); err != nil {
+		return r, fmt.Errorf("rule %s: %w", r.Name, err)
+	}
+	return r, nil
+}
+
+// All returns every rule in evaluation order, disabled ones included.
+func (s *Store) All() ([]Rule, error) {
+	rows, err := s.db.Query(`SELECT ` + columns + ` FROM ingest_rules ORDER BY position, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Rule
+	for rows.Next() {
+		r, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// ByName returns the named rule.
+func (s *Store) ByName(name string) (*Rule, error) {
+	r, err := scanRule(s.db.QueryRow(`SELECT `+columns+` FROM ingest_rules WHERE name = ?`, name))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no rule named %q", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Add checks r and stores it after every existing rule, filling in its
+// ID and position. A new rule is enabled.
+func (s *Store) Add(r *Rule) error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("a rule needs a name")
+	}
+	if _, err := ParseCond(r.When); err != nil {
+		return fmt.Errorf("rule %s: %w", r.Name, err)
+	}
+	if len(r.Actions) == 0 {
+		return fmt.Errorf("rule %s: needs at least one action", r.Name)
+	}
+	if existing, _ := s.ByName(r.Name); existing != nil {
+		return fmt.Errorf("a rule named %q already exists", r.Name)
+	}
+	r.Enabled = true
+	res, err := s.db.Exec(`
+		INSERT INTO ingest_rules (name, position, condition, actions, enabled)
+		VALUES (?, (SELECT COALESCE(MAX(position), 0) + 1 FROM ingest_rules), ?, ?, 1)`,
+		r.Name, r.When, FormatActions(r.Actions))
+	if err != nil {
+		return err
+	}
+	if r.ID, err = res.LastInsertId(); err != nil {
+		return err
+	}
+	return s.db.QueryRow(`SELECT position FROM ingest_rules WHERE id = ?`, r.ID).Scan(&r.Position)
+}
+
+// SetEnabled turns the named rule on or off.
+func (s *Store) SetEnabled(name string, enabled bool) error {
+	return s.update(name, `UPDATE ingest_rules SET enabled = ? WHERE name = ?`, enabled, name)
+}
+
+// Remove deletes the named rule.
+func (s *Store) Remove(name string) error {
+	return s.update(name, `DELETE FROM ingest_rules WHERE name = ?`, name)
+}
+
+func (s *Store) update(name, query string, args ...any) error {
+	res, err := s.db.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("no rule named %q", name)
+	}
+	return nil
+}
+
+// RecordHits adds counts, keyed by rule ID, to the rules' hit totals.
+func (s *Store) RecordHits(counts map[int64]int, at time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for id, n := range counts {
+		if _, err := tx.Exec(`UPDATE ingest_rules SET hits = hits + ?, last_hit = ? WHERE id = ?`, n, at.UTC(), id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Enabled compiles the enabled rules.
+func (s *Store) Enabled() (*Set, error) {
+	all, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	return Compile(all)
+}
+
+// Ingest runs the enabled rules over jobs on their way into js and
+// returns them changed. Every job gets tags, note and score adjustment
+// from the current rules, so a re-scraped job keeps them; only jobs not
+// stored yet count as hits and are hidden in profileID, so a job the
+// user has since unhidden stays visible.
+func (s *Store) Ingest(js *job.Store, profileID string, jobs []job.Job) ([]job.Job, error) {
+	set, err := s.Enabled()
+	if err != nil || len(set.rules) == 0 {
+		return jobs, err
+	}
+	ids := make([]string, len(jobs))
+	for i, j := range jobs {
+		ids[i] = j.ID
+	}
+	known, err := js.Known(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]job.Job, len(jobs))
+	hits := make(map[int64]int)
+	for i, j := range jobs {
+		o := set.Apply(&j)
+		out[i] = j
+		if known[j.ID] {
+			continue
+		}
+		for _, r := range o.Matched {
+			hits[r.ID]++
+		}
+		if o.Hide {
+			if err := js.SetHidden(j.ID, profileID, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(hits) > 0 {
+		if err := s.RecordHits(hits, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,145 @@
+package rules
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+func openTestStores(t *testing.T) (*Store, *job.Store) {
+	t.Helper()
+	js, err := job.OpenStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { js.Close() })
+	s, err := NewStore(js.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, js
+}
+
+func TestStore_AddListToggle(t *testing.T) {
+	s, _ := openTestStores(t)
+	for _, r := range []Rule{
+		{Name: "on-call", When: "keyword:on-call", Actions: mustActions(t, "tag:on-call, score:-10")},
+		{Name: "agencies", When: "company:recruit", Actions: mustActions(t, "hide\nnote:Agency, ask who the client is")},
+	} {
+		if err := s.Add(&r); err != nil {
+			t.Fatalf("Add %s: %v", r.Name, err)
+		}
+	}
+	if err := s.Add(&Rule{Name: "on-call", When: "keyword:x", Actions: mustActions(t, "hide")}); err == nil {
+		t.Error("a second rule with the same name should be refused")
+	}
+	if err := s.Add(&Rule{Name: "broken", When: "colour:red", Actions: mustActions(t, "hide")}); err == nil {
+		t.Error("a rule with a bad condition should be refused")
+	}
+
+	all, err := s.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].Name != "on-call" || all[1].Position <= all[0].Position || !all[1].Enabled {
+		t.Fatalf("All = %+v", all)
+	}
+	if want := mustActions(t, "hide\nnote:Agency, ask who the client is"); !reflect.DeepEqual(all[1].Actions, want) {
+		t.Errorf("actions = %+v, want %+v", all[1].Actions, want)
+	}
+
+	if err := s.SetEnabled("agencies", false); err != nil {
+		t.Fatal(err)
+	}
+	set, err := s.Enabled()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rules := set.Rules(); len(rules) != 1 || rules[0].Name != "on-call" {
+		t.Errorf("enabled = %+v", rules)
+	}
+	if err := s.SetEnabled("missing", true); err == nil {
+		t.Error("toggling a missing rule should fail")
+	}
+	if err := s.Remove("on-call"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ByName("on-call"); err == nil {
+		t.Error("removed rule still found")
+	}
+}
+
+func TestStore_Ingest(t *testing.T) {
+	s, js := openTestStores(t)
+	for _, r := range []Rule{
+		{Name: "on-call", When: "keyword:on-call", Actions: mustActions(t, "tag:on-call, score:-10")},
+		{Name: "agencies", When: "company:recruiting", Actions: mustActions(t, "hide, tag:agency")},
+	} {
+		if err := s.Add(&r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The agency job was saved before and the user unhid it since.
+	if err := js.Save([]job.Job{{ID: agency.ID, Title: agency.Title, Company: agency.Company}}); err != nil {
+		t.Fatal(err)
+	}
+
+	scraped := []job.Job{onCall, agency, plain}
+	scraped[0].Score = 50
+	out, err := s.Ingest(js, "default", scraped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(out[0].Tags, []string{"on-call"}) || out[0].Score != 40 {
+		t.Errorf("on-call job = %+v", out[0])
+	}
+	if !reflect.DeepEqual(out[1].Tags, []string{"agency"}) {
+		t.Errorf("a known job should still be tagged: %v", out[1].Tags)
+	}
+	if out[2].Tags != nil {
+		t.Errorf("unmatched job tagged %v", out[2].Tags)
+	}
+	if err := js.Save(out); err != nil {
+		t.Fatal(err)
+	}
+
+	visible, err := js.ForProfile("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(visible) != 3 {
+		t.Errorf("the known agency job was hidden again: %d visible", len(visible))
+	}
+	stored, err := js.ByID(onCall.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(stored.Tags, []string{"on-call"}) || stored.ScoreAdjust != -10 {
+		t.Errorf("stored = tags %v adjust %d", stored.Tags, stored.ScoreAdjust)
+	}
+
+	all, _ := s.All()
+	if all[0].Hits != 1 || all[0].LastHit == nil || all[1].Hits != 0 {
+		t.Errorf("hits = %d (%v), %d; only new jobs count", all[0].Hits, all[0].LastHit, all[1].Hits)
+	}
+
+	// A new agency job is hidden in the ingesting profile only.
+	fresh := agency
+	fresh.ID = "d"
+	if _, err := s.Ingest(js, "default", []job.Job{fresh}); err != nil {
+		t.Fatal(err)
+	}
+	js.Save([]job.Job{fresh})
+	if jobs, _ := js.ForProfile("default"); len(jobs) != 3 {
+		t.Errorf("new agency job not hidden: %d visible", len(jobs))
+	}
+	if jobs, _ := js.ForProfile("other"); len(jobs) != 4 {
+		t.Errorf("hidden outside the ingesting profile: %d visible", len(jobs))
+	}
+	if all, _ := s.All(); all[1].Hits != 1 || all[1].LastHit.After(time.Now()) {
+		t.Errorf("agency hits = %d", all[1].Hits)
+	}
+}
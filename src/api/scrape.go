@@ -0,0 +1,428 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/notify"
+	"sprayer/src/api/profile"
+	"sprayer/src/api/rules"
+	"sprayer/src/api/scraper"
+	"sprayer/src/api/scraperun"
+	"sprayer/src/api/sourcestats"
+	"sprayer/src/api/traps"
+)
+
+// EnableScrapeHistory records each scrape in runs, served by /scrapes.
+func (h *Handler) EnableScrapeHistory(runs *scraperun.Store) {
+	h.runStore = runs
+}
+
+// EnableSourceStats records how each scrape's sources fared in stats.
+func (h *Handler) EnableSourceStats(stats *sourcestats.Store) {
+	h.statsStore = stats
+}
+
+// EnableIngestRules runs the rules in rs over scraped jobs before they
+// are saved.
+func (h *Handler) EnableIngestRules(rs *rules.Store) {
+	h.ruleStore = rs
+}
+
+const (
+	defaultScrapeJobs = 200
+	maxScrapeJobs     = 1000
+)
+
+// ScrapeRequest is the optional body of POST /jobs/scrape. Every field may
+// be omitted: the default profile, all sources and its keywords are used.
+// Postings scraped before are skipped unless Force is set.
+type ScrapeRequest struct {
+	ProfileID        string   `json:"profile_id"`
+	Sources          []string `json:"sources"`
+	KeywordsOverride []string `json:"keywords_override"`
+	MaxJobs          int      `json:"max_jobs"`
+	Force            bool     `json:"force"`
+}
+
+// ScrapeConfig is what a scrape actually ran with, after defaults.
+type ScrapeConfig struct {
+	ProfileID string   `json:"profile_id"`
+	Sources   []string `json:"sources"`
+	Keywords  []string `json:"keywords"`
+	MaxJobs   int      `json:"max_jobs"`
+}
+
+// ScrapeStatus reports the latest scrape.
+type ScrapeStatus struct {
+	State      string       `json:"state"` // "running", "done" or "cancelled"
+	Config     ScrapeConfig `json:"config"`
+	JobsFound  int          `json:"jobs_found"`
+	Fetched    int          `json:"fetched"` // postings the sources returned
+	Errors     []string     `json:"errors,omitempty"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty"`
+}
+
+// ScrapeJobs starts a background scrape. The body (or, for older clients,
+// ?keywords= and ?fast=true) selects the profile, sources and limits.
+func (h *Handler) ScrapeJobs(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeScrapeRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	is, status, code, err := h.startScrape(context.Background(), req)
+	if err != nil {
+		writeError(w, code, err.Error())
+		return
+	}
+
+	go h.runScrape(is, status, nil)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(status)
+}
+
+// ErrScrapeRunning is returned when a scrape starts while another runs.
+var ErrScrapeRunning = errors.New("a scrape is already running")
+
+// Scrape runs a scrape to the end under ctx and returns its final status
+// with the jobs it saved. Cancelling ctx stops the sources; the jobs found
+// by then are still saved.
+func (h *Handler) Scrape(ctx context.Context, req ScrapeRequest) (ScrapeStatus, []job.Job, error) {
+	is, status, _, err := h.startScrape(ctx, req)
+	if err != nil {
+		return ScrapeStatus{}, nil, err
+	}
+	var final ScrapeStatus
+	var saved []job.Job
+	h.runScrape(is, status, &scrapeEvents{
+		saved: func(jobs []job.Job) { saved = append(saved, jobs...) },
+		done:  func(s ScrapeStatus) { final = s },
+	})
+	return final, saved, nil
+}
+
+// startScrape validates req, builds its scraper under ctx and records it
+// as the running scrape. On error it also returns the status to answer
+// with.
+func (h *Handler) startScrape(ctx context.Context, req ScrapeRequest) (*scraper.IncrementalScraper, *ScrapeStatus, int, error) {
+	prof := profile.NewDefaultProfile()
+	if req.ProfileID != "" {
+		p, err := h.profileStore.ByIDContext(ctx, req.ProfileID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, http.StatusNotFound, fmt.Errorf("unknown profile %q", req.ProfileID)
+		}
+		if err != nil {
+			return nil, nil, http.StatusInternalServerError, err
+		}
+		prof = *p
+	} else if p, err := h.profileStore.ByIDContext(ctx, prof.ID); err == nil {
+		prof = *p
+	}
+
+	switch {
+	case req.MaxJobs < 0:
+		return nil, nil, http.StatusBadRequest, errors.New("max_jobs must not be negative")
+	case req.MaxJobs == 0:
+		req.MaxJobs = defaultScrapeJobs
+	case req.MaxJobs > maxScrapeJobs:
+		req.MaxJobs = maxScrapeJobs
+	}
+
+	opts := []scraper.IncrementalOption{scraper.WithMaxJobs(req.MaxJobs), scraper.WithSources(req.Sources...),
+		scraper.WithSeen(h.store, req.Force), scraper.WithEnrich(job.Enrich(trapMatcher()))}
+	if h.sources != nil {
+		opts = append(opts, scraper.WithSourceSet(h.sources))
+	}
+	if h.statsStore != nil {
+		opts = append(opts, scraper.WithStats(h.statsStore))
+	}
+	if len(req.KeywordsOverride) > 0 {
+		opts = append(opts, scraper.WithKeywords(req.KeywordsOverride))
+	}
+	is := scraper.NewIncrementalScraper(ctx, prof, opts...)
+	// Catches a typo in the request and a stale name in the profile.
+	if err := is.Validate(); err != nil {
+		is.Stop()
+		return nil, nil, http.StatusBadRequest, err
+	}
+
+	status := &ScrapeStatus{
+		State: "running",
+		Config: ScrapeConfig{
+			ProfileID: prof.ID,
+			Sources:   is.Sources(),
+			Keywords:  is.Keywords(),
+			MaxJobs:   req.MaxJobs,
+		},
+		StartedAt: time.Now(),
+	}
+
+	h.scrapeMu.Lock()
+	defer h.scrapeMu.Unlock()
+	if h.scrape != nil && h.scrape.State == "running" {
+		is.Stop()
+		return nil, nil, http.StatusConflict, ErrScrapeRunning
+	}
+	h.scrape = status
+	return is, status, 0, nil
+}
+
+// GetScrapeStatus returns the state of the latest scrape.
+func (h *Handler) GetScrapeStatus(w http.ResponseWriter, r *http.Request) {
+	h.scrapeMu.Lock()
+	defer h.scrapeMu.Unlock()
+	if h.scrape == nil {
+		writeError(w, http.StatusNotFound, "no scrape has been started")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.scrape)
+}
+
+// scrapeEvents receives what a scrape produces as it happens: progress,
+// each job found, each error, the jobs once saved, and the final status.
+// Any of them may be nil.
+type scrapeEvents struct {
+	progress func(scraper.ScraperProgress)
+	job      func(job.Job)
+	err      func(string)
+	saved    func([]job.Job)
+	done     func(ScrapeStatus)
+}
+
+// runScrape runs is to the end, saves what it found and records the
+// outcome in status. A scrape whose context was cancelled still saves the
+// jobs found before it stopped.
+func (h *Handler) runScrape(is *scraper.IncrementalScraper, status *ScrapeStatus, ev *scrapeEvents) {
+	if ev == nil {
+		ev = &scrapeEvents{}
+	}
+	var errs []string
+	addErr := func(msg string) {
+		errs = append(errs, msg)
+		if ev.err != nil {
+			ev.err(msg)
+		}
+	}
+	var runID int64
+	if h.runStore != nil {
+		var err error
+		if runID, err = h.runStore.Start(status.Config.ProfileID, len(status.Config.Sources), status.StartedAt); err != nil {
+			addErr(fmt.Sprintf("recording the run: %v", err))
+		}
+	}
+	is.Start()
+
+	// Found jobs are saved a batch at a time, so a crash mid-scrape loses
+	// at most the batch being gathered.
+	var pending []job.Job
+	var pendingSince time.Time
+	found := 0
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		jobs := h.saveScraped(status.Config.ProfileID, pending, addErr)
+		found += len(pending)
+		pending = nil
+		if jobs != nil && ev.saved != nil {
+			ev.saved(jobs)
+		}
+	}
+
+	// Every channel must be drained or the scraper blocks, even after
+	// the client watching it has gone.
+	seen, fetched := 0, 0
+	progress, results, scrapeErrs := is.Progress(), is.Results(), is.Errors()
+	for progress != nil || results != nil || scrapeErrs != nil {
+		var due <-chan time.Time
+		if len(pending) > 0 {
+			due = time.After(time.Until(pendingSince.Add(saveEvery)))
+		}
+		select {
+		case p, ok := <-progress:
+			if !ok {
+				progress = nil
+				break
+			}
+			seen += p.Seen
+			fetched += p.Fetched
+			if ev.progress != nil {
+				ev.progress(p)
+			}
+		case j, ok := <-results:
+			if !ok {
+				results = nil
+				break
+			}
+			if len(pending) == 0 {
+				pendingSince = time.Now()
+			}
+			pending = append(pending, j)
+			if ev.job != nil {
+				ev.job(j)
+			}
+			if len(pending) >= saveBatch {
+				flush()
+			}
+		case err, ok := <-scrapeErrs:
+			if !ok {
+				scrapeErrs = nil
+			} else {
+				addErr(err.Error())
+			}
+		case <-due:
+			flush()
+		}
+	}
+	flush()
+
+	now := time.Now()
+	if runID != 0 {
+		if err := h.runStore.Finish(runID, found, errs, now); err != nil {
+			addErr(fmt.Sprintf("recording the run: %v", err))
+		} else if err := h.runStore.RecordFetched(runID, fetched); err != nil {
+			addErr(fmt.Sprintf("recording the run: %v", err))
+		}
+	}
+	h.scrapeMu.Lock()
+	status.State = "done"
+	select {
+	case <-is.Done():
+		status.State = "cancelled"
+	default:
+	}
+	status.JobsFound = found
+	status.Fetched = fetched
+	status.Errors = errs
+	status.FinishedAt = &now
+	final := *status
+	h.scrapeMu.Unlock()
+	is.Stop() // release the context
+	if ev.done != nil {
+		ev.done(final)
+	}
+	notify.Notify(notify.ScrapeCompleted(final.Config.ProfileID, final.JobsFound, seen, len(final.Errors)))
+}
+
+// A scrape's jobs are saved in batches: once saveBatch have gathered,
+// once the first of them has waited saveEvery, and when the scrape ends.
+const saveBatch = 50
+
+var saveEvery = 2 * time.Second
+
+// saveScraped runs jobs through the ingest pipeline and the profile's
+// rules, saves them and marks them seen, returning them as saved, or nil
+// when saving failed. Failures are reported to addErr.
+func (h *Handler) saveScraped(profileID string, jobs []job.Job, addErr func(string)) []job.Job {
+	jobs = job.Pipe(job.ExtractDeadlines(), job.ExtractSeniority(), job.DetectLanguages(), job.CapDescriptions())(jobs)
+	if matched, _, err := h.store.MatchStored(jobs); err != nil {
+		addErr(fmt.Sprintf("matching stored jobs: %v", err))
+	} else {
+		jobs = matched
+	}
+	if ruled, err := h.ingestRules(profileID, jobs); err != nil {
+		addErr(fmt.Sprintf("ingest rules: %v", err))
+	} else {
+		jobs = ruled
+	}
+	if err := h.store.Save(jobs); err != nil {
+		addErr(fmt.Sprintf("saving jobs: %v", err))
+		return nil
+	}
+	if err := h.store.MarkSaved(jobs); err != nil {
+		addErr(fmt.Sprintf("recording jobs seen: %v", err))
+	}
+	return jobs
+}
+
+const (
+	defaultScrapeRuns = 20
+	maxScrapeRuns     = 500
+)
+
+// ListScrapes returns recent scrape runs, newest first, with the jobs
+// each found and the sources that failed. ?limit= defaults to 20. It is
+// a 404 without EnableScrapeHistory.
+func (h *Handler) ListScrapes(w http.ResponseWriter, r *http.Request) {
+	if h.runStore == nil {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	limit := defaultScrapeRuns
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxScrapeRuns {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxScrapeRuns))
+			return
+		}
+		limit = n
+	}
+	recent, err := h.runStore.Recent(limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if recent == nil {
+		recent = []scraperun.Run{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recent)
+}
+
+// trapMatcher finds the loaded trap rules' matches in a text, or the
+// built-in rules' when the rules directory is broken.
+func trapMatcher() func(string) []job.TrapMatch {
+	set, err := traps.Load()
+	if err != nil {
+		set, _ = traps.Compile(traps.Builtin())
+	}
+	return set.Matches
+}
+
+// ingestRules runs the stored ingest rules over scraped jobs, hiding
+// matches in profileID. Without EnableIngestRules jobs pass unchanged.
+func (h *Handler) ingestRules(profileID string, jobs []job.Job) ([]job.Job, error) {
+	if h.ruleStore == nil {
+		return jobs, nil
+	}
+	return h.ruleStore.Ingest(h.store, profileID, jobs)
+}
+
+// decodeScrapeRequest reads the JSON body, falling back to the query
+// parameters the endpoint accepted before it took a body.
+func decodeScrapeRequest(r *http.Request) (ScrapeRequest, error) {
+	var req ScrapeRequest
+	if r.Body != nil {
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return req, fmt.Errorf("invalid request body: %v", err)
+		}
+	}
+	q := r.URL.Query()
+	if len(req.KeywordsOverride) == 0 {
+		req.KeywordsOverride = q["keywords"]
+	}
+	if len(req.Sources) == 0 && q.Get("fast") == "true" {
+		req.Sources = scraper.APISourceKeys
+	}
+	return req, nil
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
@@ -0,0 +1,290 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+	"sprayer/src/api/rules"
+	"sprayer/src/api/scraper"
+	"sprayer/src/api/scraperun"
+	"sprayer/src/api/sourcestats"
+)
+
+// fakeSource returns n Go jobs without touching the network.
+func fakeSource(key string, n int) scraper.ScraperSource {
+	return scraper.NewScraperSource(key, "Fake "+key, func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+		jobs := make([]job.Job, n)
+		for i := range jobs {
+			jobs[i] = job.Job{
+				ID:     fmt.Sprintf("%s-%d", key, i),
+				Title:  "Go Engineer",
+				Source: key,
+			}
+		}
+		return jobs, nil
+	})
+}
+
+func newScrapeHandler(t *testing.T) *Handler {
+	t.Helper()
+	s, err := job.OpenStore(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	ps, err := profile.NewStore(s.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ps.Save(profile.Profile{ID: "alice", Name: "Alice", Keywords: []string{"rust"}, MaxScore: 100}); err != nil {
+		t.Fatal(err)
+	}
+	h := NewHandler(s, ps)
+	h.sources = []scraper.ScraperSource{fakeSource("fake", 5), fakeSource("other", 2)}
+	runs, err := scraperun.NewStore(s.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.EnableScrapeHistory(runs)
+	stats, err := sourcestats.NewStore(s.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.EnableSourceStats(stats)
+	rs, err := rules.NewStore(s.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.EnableIngestRules(rs)
+	return h
+}
+
+func postScrape(h *Handler, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ScrapeJobs(rec, httptest.NewRequest(http.MethodPost, "/jobs/scrape", strings.NewReader(body)))
+	return rec
+}
+
+func TestScrapeJobs_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+		want string
+	}{
+		{"bad json", `{"sources":`, http.StatusBadRequest, "invalid request body"},
+		{"unknown profile", `{"profile_id":"bob"}`, http.StatusNotFound, "unknown profile"},
+		{"unknown source", `{"sources":["fake","monster"]}`, http.StatusBadRequest, "valid sources: fake, other"},
+		{"negative max", `{"max_jobs":-1}`, http.StatusBadRequest, "max_jobs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postScrape(newScrapeHandler(t), tt.body)
+			if rec.Code != tt.code || !strings.Contains(rec.Body.String(), tt.want) {
+				t.Errorf("got %d %s, want %d containing %q", rec.Code, rec.Body.String(), tt.code, tt.want)
+			}
+		})
+	}
+}
+
+func TestScrapeJobs_CapsMaxJobs(t *testing.T) {
+	h := newScrapeHandler(t)
+	rec := postScrape(h, `{"max_jobs":50000}`)
+	var status ScrapeStatus
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusAccepted || status.Config.MaxJobs != maxScrapeJobs {
+		t.Errorf("got %d with max_jobs %d", rec.Code, status.Config.MaxJobs)
+	}
+	waitForScrape(t, h)
+}
+
+func TestScrapeJobs_AppliesOverrides(t *testing.T) {
+	h := newScrapeHandler(t)
+	rec := postScrape(h, `{"profile_id":"alice","sources":["fake"],"keywords_override":["go"],"max_jobs":3}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	status := waitForScrape(t, h)
+	cfg := status.Config
+	if cfg.ProfileID != "alice" || strings.Join(cfg.Sources, ",") != "fake" ||
+		strings.Join(cfg.Keywords, ",") != "go" || cfg.MaxJobs != 3 {
+		t.Errorf("effective config = %+v", cfg)
+	}
+	if status.JobsFound != 3 || len(status.Errors) != 0 {
+		t.Errorf("status = %+v", status)
+	}
+
+	saved, err := h.store.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != 3 || saved[0].Source != "fake" {
+		t.Errorf("saved %d jobs: %+v", len(saved), saved)
+	}
+}
+
+// waitForScrape polls the status endpoint until the scrape finishes.
+func waitForScrape(t *testing.T, h *Handler) ScrapeStatus {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		rec := httptest.NewRecorder()
+		h.GetScrapeStatus(rec, httptest.NewRequest(http.MethodGet, "/jobs/scrape/status", nil))
+		var status ScrapeStatus
+		if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+			t.Fatal(err)
+		}
+		if status.State == "done" || status.State == "cancelled" {
+			return status
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("scrape did not finish")
+	return ScrapeStatus{}
+}
+
+func TestListScrapes_RecordsEachRun(t *testing.T) {
+	h := newScrapeHandler(t)
+	h.sources = append(h.sources, scraper.NewScraperSource("broken", "Broken", func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+		return nil, fmt.Errorf("HTTP 503")
+	}))
+	postScrape(h, `{"profile_id":"alice","keywords_override":["go"],"sources":["fake","broken"]}`)
+	waitForScrape(t, h)
+	postScrape(h, `{"profile_id":"alice","keywords_override":["go"],"sources":["other"]}`)
+	waitForScrape(t, h)
+
+	rec := httptest.NewRecorder()
+	h.ListScrapes(rec, httptest.NewRequest(http.MethodGet, "/scrapes", nil))
+	var runs []scraperun.Run
+	if err := json.NewDecoder(rec.Body).Decode(&runs); err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("got %d runs, want 2", len(runs))
+	}
+	latest, first := runs[0], runs[1]
+	if latest.JobsFound != 2 || latest.SourcesCount != 1 || len(latest.Errors) != 0 || latest.FinishedAt == nil {
+		t.Errorf("latest run = %+v", latest)
+	}
+	if first.JobsFound != 5 || first.SourcesCount != 2 || first.ProfileID != "alice" ||
+		len(first.Errors) != 1 || !strings.Contains(first.Errors[0], "Broken: HTTP 503") {
+		t.Errorf("first run = %+v", first)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ListScrapes(rec, httptest.NewRequest(http.MethodGet, "/scrapes?limit=0", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("limit=0: status %d", rec.Code)
+	}
+}
+
+func TestScrape_WithoutOptionalStores(t *testing.T) {
+	h := newScrapeHandler(t)
+	h.runStore, h.statsStore, h.ruleStore = nil, nil, nil
+	status, jobs, err := h.Scrape(context.Background(), ScrapeRequest{ProfileID: "alice", KeywordsOverride: []string{"go"}})
+	if err != nil || len(status.Errors) != 0 || len(jobs) != 7 {
+		t.Fatalf("got %d jobs, errors %v, %v; want 7 saved without complaint", len(jobs), status.Errors, err)
+	}
+	rec := httptest.NewRecorder()
+	h.ListScrapes(rec, httptest.NewRequest(http.MethodGet, "/scrapes", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("/scrapes without history: status %d, want 404", rec.Code)
+	}
+}
+
+func TestScrapeJobs_ProfileSources(t *testing.T) {
+	h := newScrapeHandler(t)
+	h.profileStore.Save(profile.Profile{ID: "narrow", Keywords: []string{"go"}, MaxScore: 100, Sources: []string{"other"}})
+	h.profileStore.Save(profile.Profile{ID: "stale", MaxScore: 100, Sources: []string{"fake", "gone"}})
+
+	rec := postScrape(h, `{"profile_id":"stale"}`)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `unknown source \"gone\"`) {
+		t.Errorf("stale profile source: %d %s", rec.Code, rec.Body)
+	}
+
+	if rec := postScrape(h, `{"profile_id":"narrow"}`); rec.Code != http.StatusAccepted {
+		t.Fatalf("got %d: %s", rec.Code, rec.Body)
+	}
+	status := waitForScrape(t, h)
+	if strings.Join(status.Config.Sources, ",") != "other" || status.JobsFound != 2 {
+		t.Errorf("status = %+v, want only the profile's source", status)
+	}
+}
+
+func TestScrapeJobs_SkipsSeen(t *testing.T) {
+	h := newScrapeHandler(t)
+	scrape := func(body string) ScrapeStatus {
+		t.Helper()
+		if rec := postScrape(h, body); rec.Code != http.StatusAccepted {
+			t.Fatalf("got %d: %s", rec.Code, rec.Body)
+		}
+		return waitForScrape(t, h)
+	}
+
+	// The job limit leaves two postings unsent; they are not seen yet.
+	if status := scrape(`{"profile_id":"alice","keywords_override":["go"],"sources":["fake"],"max_jobs":3}`); status.JobsFound != 3 {
+		t.Fatalf("first scrape found %d", status.JobsFound)
+	}
+	if status := scrape(`{"profile_id":"alice","keywords_override":["go"],"sources":["fake"]}`); status.JobsFound != 2 {
+		t.Errorf("second scrape found %d, want the 2 not sent before", status.JobsFound)
+	}
+	if status := scrape(`{"profile_id":"alice","keywords_override":["go"],"sources":["fake"]}`); status.JobsFound != 0 {
+		t.Errorf("third scrape found %d, want none", status.JobsFound)
+	}
+	if status := scrape(`{"profile_id":"alice","keywords_override":["go"],"sources":["fake"],"force":true}`); status.JobsFound != 5 {
+		t.Errorf("forced scrape found %d, want all 5", status.JobsFound)
+	}
+}
+
+func TestScrape_ReturnsSavedJobs(t *testing.T) {
+	h := newScrapeHandler(t)
+	status, jobs, err := h.Scrape(context.Background(), ScrapeRequest{ProfileID: "alice", KeywordsOverride: []string{"go"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.State != "done" || status.JobsFound != 7 || len(jobs) != 7 {
+		t.Fatalf("got %s with %d found, %d returned; want done with 7", status.State, status.JobsFound, len(jobs))
+	}
+	stored, err := h.store.All()
+	if err != nil || len(stored) != 7 {
+		t.Errorf("stored %d jobs (%v), want 7", len(stored), err)
+	}
+
+	h.scrape.State = "running"
+	if _, _, err := h.Scrape(context.Background(), ScrapeRequest{}); err != ErrScrapeRunning {
+		t.Errorf("got %v, want ErrScrapeRunning while another scrape runs", err)
+	}
+}
+
+func TestScrape_SavesInBatches(t *testing.T) {
+	h := newScrapeHandler(t)
+	h.sources = []scraper.ScraperSource{fakeSource("fake", 2*saveBatch+20)}
+	is, status, _, err := h.startScrape(context.Background(), ScrapeRequest{KeywordsOverride: []string{"go"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var batches []int
+	var final ScrapeStatus
+	h.runScrape(is, status, &scrapeEvents{
+		saved: func(jobs []job.Job) { batches = append(batches, len(jobs)) },
+		done:  func(s ScrapeStatus) { final = s },
+	})
+	if fmt.Sprint(batches) != fmt.Sprint([]int{saveBatch, saveBatch, 20}) {
+		t.Errorf("saved in batches of %v", batches)
+	}
+	if final.JobsFound != 2*saveBatch+20 {
+		t.Errorf("JobsFound = %d", final.JobsFound)
+	}
+}
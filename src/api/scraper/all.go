@@ -1,60 +1,96 @@
 package scraper
 
 import (
+	"context"
+
 	"sprayer/src/api/job"
+	"sprayer/src/api/offline"
+	"sprayer/src/api/profile"
 )
 
-// All returns a merged scraper that hits every source.
-// API-based scrapers run first (fast), browser-based scrapers follow.
-func All(keywords []string, location string) job.Scraper {
-	query := ""
-	if len(keywords) > 0 {
-		query = keywords[0]
+// All returns a merged scraper that hits every source, with prof's
+// Greenhouse boards and Ashby orgs. API-based scrapers run first (fast), browser-based
+// scrapers follow.
+func All(prof profile.Profile, keywords []string, location string) job.Scraper {
+	return AllContext(context.Background(), prof, keywords, location)
+}
+
+// AllContext is All, its API sources abandoning their requests if ctx is
+// cancelled.
+func AllContext(ctx context.Context, prof profile.Profile, keywords []string, location string) job.Scraper {
+	// Merge all: API first, then browser
+	all := append(apiScrapers(ctx, prof, keywords, location), browserScrapers(keywords, location)...)
+	return gated(job.Merge(all...))
+}
+
+// APIOnly returns a merged scraper with only API-based sources (no browser
+// needed), searching those that can for keywords.
+func APIOnly(prof profile.Profile, keywords []string) job.Scraper {
+	return APIOnlyContext(context.Background(), prof, keywords)
+}
+
+// APIOnlyContext is APIOnly, abandoning the requests if ctx is cancelled.
+func APIOnlyContext(ctx context.Context, prof profile.Profile, keywords []string) job.Scraper {
+	return gated(job.Merge(apiScrapers(ctx, prof, keywords, "")...))
+}
+
+// SourceCount is how many sources All runs, or APIOnly when apiOnly is
+// set, counting each RSS feed.
+func SourceCount(apiOnly bool) int {
+	n := len(apiScrapers(context.Background(), profile.Profile{}, nil, ""))
+	if !apiOnly {
+		n += len(browserScrapers(nil, ""))
 	}
+	return n
+}
 
-	// API-based (fast, reliable)
+// apiScrapers are the API-based (fast, reliable) sources and prof's RSS
+// feeds, those that can searching for keywords in location, all bound
+// by ctx.
+func apiScrapers(ctx context.Context, prof profile.Profile, keywords []string, location string) []job.Scraper {
+	terms := searchTerms(keywords)
 	api := []job.Scraper{
-		HN(),
-		RemoteOK(),
-		Remotive(),
-		Greenhouse(DefaultGreenhouseBoards),
-		AuthenticJobs(),
-		RemoteCo(),
-		WeWorkRemotely(),
-		Arbeitnow(),
-		Jobicy(),
+		hn(ctx, terms),
+		remoteOK(ctx, terms),
+		remotive(ctx),
+		greenhouse(ctx, GreenhouseBoards(prof)),
+		ashby(ctx, AshbyOrgs(prof)),
+		authenticJobs(ctx),
+		remoteCo(ctx),
+		weWorkRemotely(ctx, terms),
+		arbeitnow(ctx, terms, remoteLocation(location)),
+		jobicy(ctx, terms, location),
 	}
+	return append(api, feedScrapers(ctx, Feeds(prof))...)
+}
 
-	// Add RSS feeds
-	api = append(api, CommonRSSFeeds()...)
-
-	// Browser-based (slower, JS-rendered)
-	browser := []job.Scraper{
-		LinkedIn(keywords, location),
-		Indeed(query, location),
-		Glassdoor(query),
+// browserScrapers are the browser-based (slower, JS-rendered) sources
+// that run without being named; LinkedIn, Indeed and Glassdoor are
+// OptInSourceKeys, run only through Selected.
+func browserScrapers(keywords []string, location string) []job.Scraper {
+	return []job.Scraper{
 		Dice(keywords, location),
 		YCWorkAtStartup(keywords, location),
 	}
+}
 
-	// Merge all: API first, then browser
-	all := append(api, browser...)
-	return job.Merge(all...)
+// gated fails s at once while offline instead of letting every source
+// time out.
+func gated(s job.Scraper) job.Scraper {
+	return func() ([]job.Job, error) {
+		if err := offline.Check("scraping"); err != nil {
+			return nil, err
+		}
+		return s()
+	}
 }
 
-// APIOnly returns a merged scraper with only API-based sources (no browser needed).
-func APIOnly() job.Scraper {
-	api := []job.Scraper{
-		HN(),
-		RemoteOK(),
-		Remotive(),
-		Greenhouse(DefaultGreenhouseBoards),
-		AuthenticJobs(),
-		RemoteCo(),
-		WeWorkRemotely(),
-		Arbeitnow(),
-		Jobicy(),
+// gatedFunc is gated for one incremental source.
+func gatedFunc(fn ScraperFunc) ScraperFunc {
+	return func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+		if err := offline.Check("scraping"); err != nil {
+			return nil, err
+		}
+		return fn(ctx, keywords, location)
 	}
-	api = append(api, CommonRSSFeeds()...)
-	return job.Merge(api...)
 }
@@ -0,0 +1,193 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"sprayer/src/api/httpapi"
+	"sprayer/src/api/job"
+	"sprayer/src/api/parse"
+	"sprayer/src/api/profile"
+)
+
+// DefaultAshbyOrgs is a curated list of companies hosting boards on Ashby.
+var DefaultAshbyOrgs = []string{
+	"ramp", "posthog", "supabase", "replit", "vanta",
+}
+
+// ashbyAPIBase is the public posting API root; tests point it at a fixture server.
+var ashbyAPIBase = "https://api.ashbyhq.com/posting-api/job-board"
+
+// AshbyOrgError reports a single org that could not be scraped, e.g. one
+// that disabled its public posting API. Other orgs are unaffected.
+type AshbyOrgError struct {
+	Org string
+	Err error
+}
+
+func (e *AshbyOrgError) Error() string {
+	if httpapi.StatusCode(e.Err) == http.StatusNotFound {
+		return fmt.Sprintf("ashby %s: board not found or public API disabled", e.Org)
+	}
+	return fmt.Sprintf("ashby %s: %v", e.Org, e.Err)
+}
+
+func (e *AshbyOrgError) Unwrap() error { return e.Err }
+
+// AshbyOrgs is the orgs scraped for p: its own, else DefaultAshbyOrgs.
+func AshbyOrgs(p profile.Profile) []string {
+	if len(p.AshbyOrgs) == 0 {
+		return DefaultAshbyOrgs
+	}
+	return p.AshbyOrgs
+}
+
+// Ashby scrapes the public Ashby posting API for a set of organizations.
+func Ashby(orgs []string) job.Scraper {
+	return ashby(context.Background(), orgs)
+}
+
+// ashby is Ashby, giving up when ctx is done.
+func ashby(ctx context.Context, orgs []string) job.Scraper {
+	return func() ([]job.Job, error) {
+		return scrapeAshby(ctx, orgs)
+	}
+}
+
+// scrapeAshby fetches every org, returning the jobs it could get along
+// with a joined error of per-org failures. Requests take their turn with
+// the API host like every other source's.
+func scrapeAshby(ctx context.Context, orgs []string) ([]job.Job, error) {
+	client := httpapi.New(ashbyAPIBase)
+	client.Feature = "scraping"
+	base, err := url.Parse(ashbyAPIBase)
+	if err != nil {
+		return nil, err
+	}
+
+	var all []job.Job
+	var errs []error
+	for _, org := range orgs {
+		if err := hosts.wait(ctx, base.Host); err != nil {
+			return all, err
+		}
+		jobs, err := scrapeAshbyOrg(ctx, client, org)
+		if ctx.Err() != nil {
+			return all, ctx.Err()
+		}
+		if err != nil {
+			errs = append(errs, &AshbyOrgError{Org: org, Err: err})
+			continue
+		}
+		all = append(all, jobs...)
+	}
+	return all, errors.Join(errs...)
+}
+
+func scrapeAshbyOrg(ctx context.Context, client *httpapi.Client, org string) ([]job.Job, error) {
+	var board struct {
+		Jobs []ashbyJob `json:"jobs"`
+	}
+	path := "/" + url.PathEscape(org) + "?includeCompensation=true"
+	if err := client.Get(ctx, path, &board); err != nil {
+		return nil, err
+	}
+
+	var jobs []job.Job
+	for _, aj := range board.Jobs {
+		if !aj.IsListed {
+			continue
+		}
+		jobs = append(jobs, aj.toJob(org))
+	}
+	return jobs, nil
+}
+
+type ashbyJob struct {
+	ID                 string `json:"id"`
+	Title              string `json:"title"`
+	Department         string `json:"department"`
+	Team               string `json:"team"`
+	EmploymentType     string `json:"employmentType"`
+	Location           string `json:"location"`
+	SecondaryLocations []struct {
+		Location string `json:"location"`
+	} `json:"secondaryLocations"`
+	IsRemote         bool      `json:"isRemote"`
+	IsListed         bool      `json:"isListed"`
+	PublishedAt      time.Time `json:"publishedAt"`
+	JobURL           string    `json:"jobUrl"`
+	DescriptionPlain string    `json:"descriptionPlain"`
+	DescriptionHTML  string    `json:"descriptionHtml"`
+	Compensation     *struct {
+		Summary           string `json:"compensationTierSummary"`
+		SummaryComponents []struct {
+			CompensationType string   `json:"compensationType"`
+			Interval         string   `json:"interval"`
+			CurrencyCode     string   `json:"currencyCode"`
+			MinValue         *float64 `json:"minValue"`
+			MaxValue         *float64 `json:"maxValue"`
+		} `json:"summaryComponents"`
+	} `json:"compensation"`
+}
+
+func (aj ashbyJob) toJob(org string) job.Job {
+	desc := aj.DescriptionPlain
+	if desc == "" {
+		desc = stripHTML(aj.DescriptionHTML)
+	}
+
+	locs := []string{aj.Location}
+	for _, sl := range aj.SecondaryLocations {
+		locs = append(locs, sl.Location)
+	}
+	locs = slices.DeleteFunc(locs, func(l string) bool { return strings.TrimSpace(l) == "" })
+	if aj.IsRemote && !strings.Contains(strings.ToLower(strings.Join(locs, " ")), "remote") {
+		locs = append([]string{"Remote"}, locs...)
+	}
+	loc := strings.Join(locs, " / ")
+
+	j := job.Job{
+		ID:          fmt.Sprintf("ashby-%s-%s", org, aj.ID),
+		Title:       aj.Title,
+		Company:     org,
+		Location:    loc,
+		Description: desc,
+		URL:         aj.JobURL,
+		Source:      "ashby",
+		PostedDate:  aj.PublishedAt,
+		Email:       parse.ExtractFirstEmail(desc),
+		JobType:     aj.EmploymentType,
+		Score:       50,
+	}
+
+	if aj.Compensation != nil {
+		j.Salary = aj.Compensation.Summary
+		for _, c := range aj.Compensation.SummaryComponents {
+			if c.CompensationType != "Salary" || c.Interval != "1 YEAR" {
+				continue
+			}
+			if c.MinValue != nil {
+				j.SalaryMin = int(*c.MinValue)
+			}
+			if c.MaxValue != nil {
+				j.SalaryMax = int(*c.MaxValue)
+			}
+			j.SalaryCurrency = c.CurrencyCode
+			break
+		}
+	}
+	if j.Salary == "" {
+		j.Salary = parse.ExtractSalary(desc)
+	}
+	if j.PostedDate.IsZero() {
+		j.PostedDate = time.Now()
+	}
+	return j
+}
@@ -0,0 +1,125 @@
+package scraper
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/profile"
+)
+
+func ashbyFixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	fixture, err := os.ReadFile("testdata/ashby_acme.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/acme":
+			if r.URL.Query().Get("includeCompensation") != "true" {
+				t.Errorf("compensation not requested: %s", r.URL.RawQuery)
+			}
+			w.Write(fixture)
+		case "/private":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"success":false,"errors":["Job board not found"]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	old := ashbyAPIBase
+	ashbyAPIBase = srv.URL
+	t.Cleanup(func() {
+		ashbyAPIBase = old
+		srv.Close()
+	})
+	return srv
+}
+
+func TestAshby_MapsFixture(t *testing.T) {
+	ashbyFixtureServer(t)
+
+	jobs, err := Ashby([]string{"acme"})()
+	if err != nil {
+		t.Fatalf("Ashby: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 listed jobs, got %d", len(jobs))
+	}
+
+	be := jobs[0]
+	if be.ID != "ashby-acme-4b2a7f6e-1111-4c7e-9d35-1f0a2b3c4d5e" || be.Source != "ashby" {
+		t.Errorf("unexpected identity: %s / %s", be.ID, be.Source)
+	}
+	if be.Location != "Remote / New York / San Francisco" {
+		t.Errorf("Location = %q", be.Location)
+	}
+	if be.JobType != "FullTime" {
+		t.Errorf("JobType = %q", be.JobType)
+	}
+	if be.SalaryMin != 180000 || be.SalaryMax != 220000 || be.SalaryCurrency != "USD" {
+		t.Errorf("structured salary = %d-%d %s", be.SalaryMin, be.SalaryMax, be.SalaryCurrency)
+	}
+	if !strings.Contains(be.Salary, "$180K") {
+		t.Errorf("Salary summary = %q", be.Salary)
+	}
+	if be.PostedDate.Year() != 2024 {
+		t.Errorf("PostedDate = %v", be.PostedDate)
+	}
+
+	intern := jobs[1]
+	if intern.Description != "Paid internship, €2,000 per month." {
+		t.Errorf("HTML fallback description = %q", intern.Description)
+	}
+	if intern.SalaryMin != 0 || intern.Salary == "" {
+		t.Errorf("expected parsed salary string only, got %q / %d", intern.Salary, intern.SalaryMin)
+	}
+}
+
+func TestAshby_DisabledOrgDoesNotFailSource(t *testing.T) {
+	ashbyFixtureServer(t)
+
+	jobs, err := Ashby([]string{"private", "acme"})()
+	if len(jobs) != 2 {
+		t.Errorf("expected acme jobs despite private failing, got %d", len(jobs))
+	}
+	var orgErr *AshbyOrgError
+	if !errors.As(err, &orgErr) || orgErr.Org != "private" {
+		t.Fatalf("expected AshbyOrgError for private, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "public API disabled") {
+		t.Errorf("error should explain the 404: %v", err)
+	}
+}
+
+func TestAshby_LocationWithoutPrimary(t *testing.T) {
+	aj := ashbyJob{ID: "1", Title: "SRE"}
+	aj.SecondaryLocations = append(aj.SecondaryLocations, struct {
+		Location string `json:"location"`
+	}{"Berlin"})
+	if loc := aj.toJob("acme").Location; loc != "Berlin" {
+		t.Errorf("Location = %q, want Berlin", loc)
+	}
+	aj.IsRemote = true
+	if loc := aj.toJob("acme").Location; loc != "Remote / Berlin" {
+		t.Errorf("remote Location = %q", loc)
+	}
+	aj.SecondaryLocations = nil
+	if loc := aj.toJob("acme").Location; loc != "Remote" {
+		t.Errorf("remote-only Location = %q", loc)
+	}
+}
+
+func TestAshbyOrgs(t *testing.T) {
+	if got := AshbyOrgs(profile.Profile{}); !slices.Equal(got, DefaultAshbyOrgs) {
+		t.Errorf("AshbyOrgs() = %v, want the defaults", got)
+	}
+	if got := AshbyOrgs(profile.Profile{AshbyOrgs: []string{"acme"}}); !slices.Equal(got, []string{"acme"}) {
+		t.Errorf("AshbyOrgs(acme) = %v", got)
+	}
+}
@@ -1,6 +1,7 @@
 package scraper
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
 	"strings"
@@ -12,11 +13,21 @@ import (
 
 // AuthenticJobs scrapes the Authentic Jobs RSS feed.
 func AuthenticJobs() job.Scraper {
+	return authenticJobs(context.Background())
+}
+
+// authenticJobs is AuthenticJobs, abandoning the crawl delay and request
+// if ctx is cancelled.
+func authenticJobs(ctx context.Context) job.Scraper {
 	return func() ([]job.Job, error) {
 		// Implement 3-second crawl delay to respect rate limiting
-		time.Sleep(3 * time.Second)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(3 * time.Second):
+		}
 
-		data, err := httpGet("https://authenticjobs.com/?feed=job_feed")
+		data, err := httpGet(ctx, "https://authenticjobs.com/?feed=job_feed")
 		if err != nil {
 			return nil, fmt.Errorf("AuthenticJobs RSS: %w", err)
 		}
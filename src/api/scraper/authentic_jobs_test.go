@@ -1,8 +1,11 @@
 package scraper
 
 import (
+	"context"
 	"testing"
 	"time"
+
+	"sprayer/src/api/job"
 )
 
 func TestAuthenticJobs(t *testing.T) {
@@ -45,3 +48,17 @@ func TestAuthenticJobs(t *testing.T) {
 	t.Logf("Job type: %s", job.JobType)
 	t.Logf("Posted: %s", job.PostedDate.Format(time.RFC3339))
 }
+
+func TestAPIScrapers_StopWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	for i, s := range []job.Scraper{authenticJobs(ctx), remotive(ctx), greenhouse(ctx, []string{"acme"})} {
+		if jobs, _ := s(); len(jobs) != 0 {
+			t.Errorf("scraper %d returned %d jobs after cancel", i, len(jobs))
+		}
+	}
+	if took := time.Since(start); took > time.Second {
+		t.Errorf("cancelled scrapers took %s; they should not wait out crawl delays", took)
+	}
+}
@@ -0,0 +1,95 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"sprayer/src/api/job"
+
+	"github.com/go-rod/rod"
+)
+
+// OptInSourceKeys are the sources that block scrapers aggressively. They
+// run only when a profile's sources or a -sources flag name them, never
+// as part of "every source".
+var OptInSourceKeys = []string{"linkedin", "indeed", "glassdoor"}
+
+// BlockedError reports a site that answered with a block or captcha
+// page instead of results. Skipped is set when the source was not asked
+// at all, having blocked an earlier request this session.
+type BlockedError struct {
+	Source  string
+	Skipped bool
+}
+
+func (e *BlockedError) Error() string {
+	if e.Skipped {
+		return fmt.Sprintf("%s blocked an earlier request, so it was skipped — consider disabling this source", e.Source)
+	}
+	return fmt.Sprintf("%s blocked the request — consider disabling this source", e.Source)
+}
+
+// blockedSources records the sources that blocked a request, so the
+// rest of the session does not ask them again.
+var blockedSources sync.Map
+
+var (
+	// blockURL matches the login walls and challenge pages sites redirect
+	// a blocked scraper to.
+	blockURL = regexp.MustCompile(`(?i)/(?:authwall|checkpoint|captcha|challenge|blocked)|[?&]captcha`)
+	// blockText matches what block and captcha pages say.
+	blockText = regexp.MustCompile(`(?i)captcha|are you a robot|verify (?:that )?you are (?:a )?human|unusual traffic|access denied|security check|just a moment|pardon our interruption|request (?:was )?blocked`)
+)
+
+// isBlockPage reports a page, by its URL, title and text, that is a
+// block or captcha page rather than search results.
+func isBlockPage(pageURL, title, text string) bool {
+	return blockURL.MatchString(pageURL) || blockText.MatchString(title) || blockText.MatchString(text)
+}
+
+// blockable is BrowserScrape for a site that blocks scrapers: a block
+// page becomes a BlockedError instead of whatever extract makes of it,
+// and once blocked the site is skipped for the rest of the session.
+func blockable(source, url string, extract ExtractFn) job.Scraper {
+	scrape := BrowserScrape(url, func(page *rod.Page) ([]job.Job, error) {
+		var pageURL, title, text string
+		if info, err := page.Info(); err == nil {
+			pageURL, title = info.URL, info.Title
+		}
+		if body, err := page.Element("body"); err == nil {
+			text, _ = body.Text()
+		}
+		if isBlockPage(pageURL, title, text) {
+			blockedSources.Store(source, true)
+			return nil, &BlockedError{Source: source}
+		}
+		return extract(page)
+	})
+	return func() ([]job.Job, error) {
+		if _, blocked := blockedSources.Load(source); blocked {
+			return nil, &BlockedError{Source: source, Skipped: true}
+		}
+		return scrape()
+	}
+}
+
+// needsKeyword is the scraper for a source that searches by keyword and
+// was given none.
+func needsKeyword(source string) job.Scraper {
+	return func() ([]job.Job, error) {
+		return nil, fmt.Errorf("%s searches by keyword; give it at least one", source)
+	}
+}
+
+// firstKeyword is the keyword a single-query source searches for, or ""
+// when there is none.
+func firstKeyword(keywords []string) string {
+	for _, kw := range keywords {
+		if kw = strings.TrimSpace(kw); kw != "" {
+			return kw
+		}
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"sprayer/src/api/profile"
+)
+
+func TestIsBlockPage(t *testing.T) {
+	for _, tc := range []struct {
+		url, title, text string
+		want             bool
+	}{
+		{"https://www.linkedin.com/authwall?trk=x", "LinkedIn", "", true},
+		{"https://www.indeed.com/jobs?q=go", "Just a moment...", "", true},
+		{"https://www.glassdoor.com/Job/jobs.htm", "Jobs", "Help us protect Glassdoor. Please complete the CAPTCHA.", true},
+		{"https://www.indeed.com/jobs?q=go", "Go jobs", "Senior Go Engineer at Acme, Remote", false},
+	} {
+		if got := isBlockPage(tc.url, tc.title, tc.text); got != tc.want {
+			t.Errorf("isBlockPage(%q, %q) = %v, want %v", tc.url, tc.title, got, tc.want)
+		}
+	}
+}
+
+func TestBlockable_SkipsBlockedSource(t *testing.T) {
+	blockedSources.Store("Indeed", true)
+	t.Cleanup(func() { blockedSources.Delete("Indeed") })
+
+	_, err := Indeed("golang", "Remote")()
+	var be *BlockedError
+	if !errors.As(err, &be) || !be.Skipped || be.Source != "Indeed" {
+		t.Fatalf("err = %v, want Indeed skipped", err)
+	}
+	if failed, ok := BoardFailures(err); !ok || len(failed) != 1 {
+		t.Errorf("a blocked source should leave the others' jobs: %v, %v", failed, ok)
+	}
+}
+
+func TestBrowserSources_NeedKeyword(t *testing.T) {
+	for name, s := range map[string]func() error{
+		"linkedin":  func() error { _, err := LinkedIn(nil, "Remote")(); return err },
+		"indeed":    func() error { _, err := Indeed(firstKeyword(nil), "Remote")(); return err },
+		"glassdoor": func() error { _, err := Glassdoor(firstKeyword([]string{" "}))(); return err },
+	} {
+		if err := s(); err == nil {
+			t.Errorf("%s without keywords: no error", name)
+		}
+	}
+}
+
+func TestIncrementalScraper_OptInSources(t *testing.T) {
+	is := NewIncrementalScraper(context.Background(), profile.Profile{MaxScore: 100})
+	for _, key := range is.Sources() {
+		for _, optIn := range OptInSourceKeys {
+			if key == optIn {
+				t.Errorf("%s runs without being named", key)
+			}
+		}
+	}
+	is = NewIncrementalScraper(context.Background(), profile.Profile{MaxScore: 100, Sources: []string{"linkedin"}})
+	if got := is.Sources(); len(got) != 1 || got[0] != "linkedin" {
+		t.Errorf("Sources() = %v, want the named opt-in source", got)
+	}
+}
@@ -58,13 +58,16 @@ func BrowserScrape(url string, extract ExtractFn) job.Scraper {
 
 // LinkedIn returns a browser-based scraper for LinkedIn job search.
 func LinkedIn(keywords []string, location string) job.Scraper {
-	query := strings.Join(keywords, " ")
+	query := strings.TrimSpace(strings.Join(keywords, " "))
+	if query == "" {
+		return needsKeyword("LinkedIn")
+	}
 	url := fmt.Sprintf("https://www.linkedin.com/jobs/search/?keywords=%s&location=%s&f_WT=2",
 		strings.ReplaceAll(query, " ", "%20"),
 		strings.ReplaceAll(location, " ", "%20"),
 	)
 
-	return BrowserScrape(url, func(page *rod.Page) ([]job.Job, error) {
+	return blockable("LinkedIn", url, func(page *rod.Page) ([]job.Job, error) {
 		// Wait for job cards to load
 		page.MustWaitStable()
 
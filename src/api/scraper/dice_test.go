@@ -3,6 +3,8 @@ package scraper
 import (
 	"testing"
 	"time"
+
+	"sprayer/src/api/profile"
 )
 
 func TestDiceScraper(t *testing.T) {
@@ -65,7 +67,7 @@ func TestDiceInAllScrapers(t *testing.T) {
 	keywords := []string{"software engineer"}
 	location := "remote"
 
-	allScraper := All(keywords, location)
+	allScraper := All(profile.Profile{}, keywords, location)
 
 	if allScraper == nil {
 		t.Fatal("Expected All() to return a scraper, got nil")
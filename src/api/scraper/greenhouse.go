@@ -1,13 +1,21 @@
 package scraper
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"sprayer/src/api/job"
 	"sprayer/src/api/parse"
+	"sprayer/src/api/profile"
 )
 
 // DefaultGreenhouseBoards is a curated list of companies using Greenhouse.
@@ -16,25 +24,135 @@ var DefaultGreenhouseBoards = []string{
 	"notion", "vercel", "planetscale", "linear",
 }
 
+// greenhouseAPIBase is the boards API root; tests point it at a fixture server.
+var greenhouseAPIBase = "https://boards-api.greenhouse.io/v1/boards"
+
+// GreenhouseBoardError reports a single board that could not be scraped.
+// Other boards are unaffected.
+type GreenhouseBoardError struct {
+	Board string
+	Err   error
+}
+
+// NotFound tells whether the API does not know the board: a typo, or a
+// company that has left Greenhouse.
+func (e *GreenhouseBoardError) NotFound() bool {
+	var se *statusError
+	return errors.As(e.Err, &se) && se.Code == http.StatusNotFound
+}
+
+func (e *GreenhouseBoardError) Error() string {
+	if !e.NotFound() {
+		return fmt.Sprintf("greenhouse %s: %v", e.Board, e.Err)
+	}
+	if slices.Contains(DefaultGreenhouseBoards, e.Board) {
+		return fmt.Sprintf("greenhouse %s: board not found", e.Board)
+	}
+	return fmt.Sprintf("greenhouse %s: board not found; remove it with 'sprayer profile boards rm %s'", e.Board, e.Board)
+}
+
+func (e *GreenhouseBoardError) Unwrap() error { return e.Err }
+
+// missingBoards holds the boards the API answered 404 for in this
+// process. They are reported again but not fetched again, so a daemon
+// does not ask for a dead board every run.
+var missingBoards sync.Map
+
+// GreenhouseBoards is the boards scraped for p: the defaults, then the
+// profile's own, once each.
+func GreenhouseBoards(p profile.Profile) []string {
+	boards := slices.Clone(DefaultGreenhouseBoards)
+	for _, b := range p.GreenhouseBoards {
+		if !slices.Contains(boards, b) {
+			boards = append(boards, b)
+		}
+	}
+	return boards
+}
+
+// MissingGreenhouseBoards returns those of boards the API did not know
+// when last asked.
+func MissingGreenhouseBoards(boards []string) []string {
+	var out []string
+	for _, b := range boards {
+		if _, ok := missingBoards.Load(b); ok {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// BoardFailures splits err into the boards that failed, Ashby orgs,
+// Greenhouse boards or feeds, and the sites that blocked the request,
+// each of which leaves the others' jobs to keep. ok is false when err is
+// anything else.
+func BoardFailures(err error) (failures []error, ok bool) {
+	if joined, isJoined := err.(interface{ Unwrap() []error }); isJoined {
+		for _, e := range joined.Unwrap() {
+			more, ok := BoardFailures(e)
+			if !ok {
+				return nil, false
+			}
+			failures = append(failures, more...)
+		}
+		return failures, true
+	}
+	switch err.(type) {
+	case *GreenhouseBoardError, *AshbyOrgError, *FeedError, *BlockedError:
+		return []error{err}, true
+	}
+	return nil, false
+}
+
 // Greenhouse scrapes the Greenhouse JSON API for a set of company boards.
+// Failing boards are skipped; MissingGreenhouseBoards names those not
+// found.
 func Greenhouse(boards []string) job.Scraper {
+	return greenhouse(context.Background(), boards)
+}
+
+// greenhouse is Greenhouse, stopping at the board in progress if ctx is
+// cancelled.
+func greenhouse(ctx context.Context, boards []string) job.Scraper {
 	return func() ([]job.Job, error) {
-		var all []job.Job
-		for _, board := range boards {
-			jobs, err := scrapeGreenhouseBoard(board)
-			if err != nil {
-				continue // Skip failing boards
+		jobs, _ := scrapeGreenhouse(ctx, boards)
+		return jobs, nil
+	}
+}
+
+// scrapeGreenhouse fetches every board, returning the jobs it could get
+// along with a joined error of per-board failures.
+func scrapeGreenhouse(ctx context.Context, boards []string) ([]job.Job, error) {
+	var all []job.Job
+	var errs []error
+	for _, board := range boards {
+		if _, missing := missingBoards.Load(board); missing {
+			errs = append(errs, &GreenhouseBoardError{Board: board, Err: &statusError{Code: http.StatusNotFound, URL: greenhouseBoardURL(board)}})
+			continue
+		}
+		jobs, err := scrapeGreenhouseBoard(ctx, board)
+		if ctx.Err() != nil {
+			return all, ctx.Err()
+		}
+		if err != nil {
+			e := &GreenhouseBoardError{Board: board, Err: err}
+			if e.NotFound() {
+				missingBoards.Store(board, true)
 			}
-			all = append(all, jobs...)
-			time.Sleep(300 * time.Millisecond) // Rate limit
+			errs = append(errs, e)
+			continue
 		}
-		return all, nil
+		all = append(all, jobs...)
 	}
+	return all, errors.Join(errs...)
 }
 
-func scrapeGreenhouseBoard(board string) ([]job.Job, error) {
-	url := fmt.Sprintf("https://boards-api.greenhouse.io/v1/boards/%s/jobs?content=true", board)
-	data, err := httpGet(url)
+func greenhouseBoardURL(board string) string {
+	return greenhouseAPIBase + "/" + url.PathEscape(board) + "/jobs?content=true"
+}
+
+func scrapeGreenhouseBoard(ctx context.Context, board string) ([]job.Job, error) {
+	data, err := httpGet(ctx, greenhouseBoardURL(board))
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +192,7 @@ type greenhouseJob struct {
 	ID          int       `json:"id"`
 	Title       string    `json:"title"`
 	Content     string    `json:"content"`
-	AbsoluteURL string   `json:"absolute_url"`
+	AbsoluteURL string    `json:"absolute_url"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	Location    struct {
 		Name string `json:"name"`
@@ -97,6 +215,38 @@ func GreenhouseForKeywords(boards []string, keywords []string) job.Scraper {
 	}
 }
 
+// greenhouseToken is the form of a board token.
+var greenhouseToken = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ParseGreenhouseBoard reads a board token, or the board of a Greenhouse
+// job or board URL, such as https://boards.greenhouse.io/flyio/jobs/123,
+// https://job-boards.greenhouse.io/embed/job_app?for=flyio&token=123 or
+// the boards API's.
+func ParseGreenhouseBoard(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if token := strings.ToLower(s); greenhouseToken.MatchString(token) {
+		return token, nil
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Host != "greenhouse.io" && !strings.HasSuffix(u.Host, ".greenhouse.io")) {
+		return "", fmt.Errorf("%q is neither a Greenhouse board token nor a Greenhouse URL", s)
+	}
+	board := u.Query().Get("for")
+	if board == "" {
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		switch {
+		case len(parts) >= 3 && parts[0] == "v1" && parts[1] == "boards":
+			board = parts[2]
+		case parts[0] != "embed":
+			board = parts[0]
+		}
+	}
+	if board = strings.ToLower(board); !greenhouseToken.MatchString(board) {
+		return "", fmt.Errorf("no board in Greenhouse URL %s", s)
+	}
+	return board, nil
+}
+
 // CompanyNameFromBoard prettifies a board slug.
 func CompanyNameFromBoard(board string) string {
 	replacer := strings.NewReplacer(
@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"sprayer/src/api/profile"
+)
+
+func TestParseGreenhouseBoard(t *testing.T) {
+	for in, want := range map[string]string{
+		"flyio":       "flyio",
+		" Acme-Corp ": "acme-corp",
+		"https://boards.greenhouse.io/flyio/jobs/5012345":                     "flyio",
+		"https://job-boards.greenhouse.io/cloudflare/jobs/6012345?gh_src=abc": "cloudflare",
+		"https://job-boards.eu.greenhouse.io/acme/jobs/1":                     "acme",
+		"https://boards.greenhouse.io/embed/job_app?for=figma&token=123":      "figma",
+		"https://boards-api.greenhouse.io/v1/boards/vercel/jobs?content=true": "vercel",
+	} {
+		if got, err := ParseGreenhouseBoard(in); err != nil || got != want {
+			t.Errorf("ParseGreenhouseBoard(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"https://acme.com/careers?gh_jid=1", "https://boards.greenhouse.io/embed/job_app", "not a board"} {
+		if got, err := ParseGreenhouseBoard(in); err == nil {
+			t.Errorf("ParseGreenhouseBoard(%q) = %q, want an error", in, got)
+		}
+	}
+}
+
+func TestGreenhouseBoards_MergesProfile(t *testing.T) {
+	p := profile.Profile{GreenhouseBoards: []string{"acme", "flyio"}}
+	got := GreenhouseBoards(p)
+	if len(got) != len(DefaultGreenhouseBoards)+1 || got[len(got)-1] != "acme" {
+		t.Errorf("GreenhouseBoards = %v", got)
+	}
+}
+
+func TestGreenhouse_MissingBoardReportedNotRefetched(t *testing.T) {
+	var gone atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/acme/jobs":
+			w.Write([]byte(`{"jobs":[{"id":7,"title":"Go Engineer","content":"&lt;p&gt;Build things&lt;/p&gt;","absolute_url":"https://boards.greenhouse.io/acme/jobs/7","location":{"name":"Remote"}}]}`))
+		case "/gone/jobs":
+			gone.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	old := greenhouseAPIBase
+	greenhouseAPIBase = srv.URL
+	t.Cleanup(func() {
+		greenhouseAPIBase = old
+		missingBoards.Clear()
+		srv.Close()
+	})
+
+	for range 2 {
+		jobs, err := scrapeGreenhouse(context.Background(), []string{"acme", "gone"})
+		if len(jobs) != 1 || jobs[0].ID != "gh-acme-7" {
+			t.Fatalf("jobs = %+v", jobs)
+		}
+		var be *GreenhouseBoardError
+		if !errors.As(err, &be) || be.Board != "gone" || !be.NotFound() {
+			t.Fatalf("err = %v, want gone not found", err)
+		}
+		if !strings.Contains(err.Error(), "sprayer profile boards rm gone") {
+			t.Errorf("no removal hint: %v", err)
+		}
+		if failed, ok := BoardFailures(err); !ok || len(failed) != 1 {
+			t.Errorf("BoardFailures = %v, %v", failed, ok)
+		}
+	}
+	if gone.Load() != 1 {
+		t.Errorf("missing board fetched %d times, want once", gone.Load())
+	}
+	if got := MissingGreenhouseBoards([]string{"acme", "gone"}); len(got) != 1 || got[0] != "gone" {
+		t.Errorf("MissingGreenhouseBoards = %v", got)
+	}
+}
@@ -1,10 +1,11 @@
 package scraper
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -14,10 +15,16 @@ import (
 
 // HN scrapes the monthly "Who is Hiring?" thread via the HN Algolia API.
 func HN() job.Scraper {
+	return hn(context.Background(), nil)
+}
+
+// hn fetches the thread's postings that mention any of terms, one search
+// per term, or every posting when there are none.
+func hn(ctx context.Context, terms []string) job.Scraper {
 	return func() ([]job.Job, error) {
 		// Find the latest "Who is Hiring?" story
 		storyURL := "https://hn.algolia.com/api/v1/search?query=%22Ask%20HN%3A%20Who%20is%20hiring%22&tags=story&hitsPerPage=1"
-		storyResp, err := httpGet(storyURL)
+		storyResp, err := httpGet(ctx, storyURL)
 		if err != nil {
 			return nil, fmt.Errorf("HN story search: %w", err)
 		}
@@ -38,49 +45,66 @@ func HN() job.Scraper {
 		storyID := storyResult.Hits[0].ObjectID
 
 		// Fetch top-level comments (job postings)
+		queries := []string{""}
+		if len(terms) > 0 {
+			queries = terms
+		}
 		var allJobs []job.Job
-		page := 0
-		for {
-			commentsURL := fmt.Sprintf(
-				"https://hn.algolia.com/api/v1/search?tags=comment,story_%s&hitsPerPage=100&page=%d",
-				storyID, page,
-			)
-			commentsResp, err := httpGet(commentsURL)
-			if err != nil {
-				break
-			}
+		got := make(map[string]bool)
+		for _, query := range queries {
+			allJobs = hnComments(ctx, storyID, query, got, allJobs)
+		}
+		return allJobs, nil
+	}
+}
 
-			var commentsResult struct {
-				Hits []struct {
-					ObjectID  string `json:"objectID"`
-					CommentText string `json:"comment_text"`
-					CreatedAt string `json:"created_at"`
-				} `json:"hits"`
-				NbPages int `json:"nbPages"`
-			}
-			if err := json.Unmarshal(commentsResp, &commentsResult); err != nil {
-				break
-			}
+// hnComments appends the postings in story storyID matching query, or
+// all of them for an empty one, skipping those already got.
+func hnComments(ctx context.Context, storyID, query string, got map[string]bool, allJobs []job.Job) []job.Job {
+	page := 0
+	for {
+		commentsURL := fmt.Sprintf(
+			"https://hn.algolia.com/api/v1/search?tags=comment,story_%s&hitsPerPage=100&page=%d",
+			storyID, page,
+		)
+		if query != "" {
+			commentsURL += "&query=" + url.QueryEscape(query)
+		}
+		commentsResp, err := httpGet(ctx, commentsURL)
+		if err != nil {
+			break
+		}
 
-			for _, hit := range commentsResult.Hits {
-				text := hit.CommentText
-				if len(text) < 50 {
-					continue // Skip very short comments (replies, not job posts)
-				}
+		var commentsResult struct {
+			Hits []struct {
+				ObjectID    string `json:"objectID"`
+				CommentText string `json:"comment_text"`
+				CreatedAt   string `json:"created_at"`
+			} `json:"hits"`
+			NbPages int `json:"nbPages"`
+		}
+		if err := json.Unmarshal(commentsResp, &commentsResult); err != nil {
+			break
+		}
 
-				j := parseHNComment(hit.ObjectID, text, hit.CreatedAt)
-				allJobs = append(allJobs, j)
+		for _, hit := range commentsResult.Hits {
+			text := hit.CommentText
+			if len(text) < 50 || got[hit.ObjectID] {
+				continue // Skip very short comments (replies, not job posts)
 			}
+			got[hit.ObjectID] = true
 
-			page++
-			if page >= commentsResult.NbPages || page >= 5 {
-				break
-			}
-			time.Sleep(200 * time.Millisecond) // Rate limit
+			j := parseHNComment(hit.ObjectID, text, hit.CreatedAt)
+			allJobs = append(allJobs, j)
 		}
 
-		return allJobs, nil
+		page++
+		if page >= commentsResult.NbPages || page >= 5 {
+			break
+		}
+		time.Sleep(200 * time.Millisecond) // Rate limit
 	}
+	return allJobs
 }
 
 func parseHNComment(id, text, createdAt string) job.Job {
@@ -169,30 +193,6 @@ func stripHTML(s string) string {
 	return strings.TrimSpace(out.String())
 }
 
-func httpGet(url string) ([]byte, error) {
-	client := &http.Client{Timeout: 15 * time.Second}
-	resp, err := client.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP %d from %s", resp.StatusCode, url)
-	}
-
-	var buf [1 << 20]byte // 1MB max
-	n := 0
-	for {
-		read, err := resp.Body.Read(buf[n:])
-		n += read
-		if err != nil {
-			break
-		}
-	}
-	return buf[:n], nil
-}
-
 // idFromContent generates a deterministic ID from content.
 func idFromContent(source, content string) string {
 	h := sha256.Sum256([]byte(content))
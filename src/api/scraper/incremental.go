@@ -3,6 +3,8 @@ package scraper
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +23,73 @@ type IncrementalScraper struct {
 	totalJobs     int
 	processedJobs int
 	mu            sync.RWMutex
+
+	// Overrides set through IncrementalOption; zero values mean "from the
+	// profile" or "everything".
+	sources  []ScraperSource
+	only     []string
+	keywords []string
+	maxJobs  int
+	seen     SeenStore
+	force    bool
+	enrich   job.Filter
+	stats    StatsRecorder
+}
+
+// SeenStore remembers the postings scraped before; *job.Store satisfies
+// it. Postings are recorded by whoever saves the results, with
+// job.Store.MarkSaved, so one a profile filtered out or a save lost is
+// not skipped next time.
+type SeenStore interface {
+	SkipSeen(jobs []job.Job) ([]job.Job, int, error)
+}
+
+// StatsRecorder records how each run of a source went, and the runs cut
+// short by the scrape being cancelled; *sourcestats.Store satisfies it.
+type StatsRecorder interface {
+	Record(source string, jobs int, err error, latency time.Duration, now time.Time) error
+	RecordPartial(source string, jobs int, latency time.Duration, now time.Time) error
+}
+
+// IncrementalOption adjusts what an IncrementalScraper runs.
+type IncrementalOption func(*IncrementalScraper)
+
+// WithSources restricts scraping to the sources with the given keys.
+func WithSources(keys ...string) IncrementalOption {
+	return func(is *IncrementalScraper) { is.only = keys }
+}
+
+// WithKeywords searches for kw instead of the profile's keywords.
+func WithKeywords(kw []string) IncrementalOption {
+	return func(is *IncrementalScraper) { is.keywords = kw }
+}
+
+// WithMaxJobs stops sending results after n jobs.
+func WithMaxJobs(n int) IncrementalOption {
+	return func(is *IncrementalScraper) { is.maxJobs = n }
+}
+
+// WithSeen skips postings s has seen saved, so a scrape handles each
+// posting once. force processes them all again.
+func WithSeen(s SeenStore, force bool) IncrementalOption {
+	return func(is *IncrementalScraper) { is.seen, is.force = s, force }
+}
+
+// WithStats records each source's run in stats as it completes, so the
+// sources a cancelled scrape got through are recorded too.
+func WithStats(stats StatsRecorder) IncrementalOption {
+	return func(is *IncrementalScraper) { is.stats = stats }
+}
+
+// WithEnrich runs each source's jobs through enrich before they are
+// scored, so scoring and the trap filter see what it fills in.
+func WithEnrich(enrich job.Filter) IncrementalOption {
+	return func(is *IncrementalScraper) { is.enrich = enrich }
+}
+
+// WithSourceSet replaces the built-in sources, e.g. with fakes in tests.
+func WithSourceSet(sources []ScraperSource) IncrementalOption {
+	return func(is *IncrementalScraper) { is.sources = sources }
 }
 
 // Done returns a channel that's closed when the scraper is done
@@ -36,22 +105,57 @@ type ScraperProgress struct {
 	CurrentSource int
 	ElapsedTime   time.Duration
 	Status        string
+	// New and Seen count the source's postings not scraped before and
+	// those skipped for having been, once it completes under WithSeen.
+	New, Seen int
+	// Fetched counts the postings the source returned, once it completes.
+	Fetched int
+}
+
+// Counts reads "42 new / 310 seen", or "" when seen postings are not
+// tracked.
+func (p ScraperProgress) Counts() string {
+	if p.New == 0 && p.Seen == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d new / %d seen", p.New, p.Seen)
 }
 
 // ScraperSource represents a scraper source with metadata
 type ScraperSource struct {
+	key  string // short identifier used in requests, e.g. "hn"
 	name string
 	fn   ScraperFunc
 }
 
+// NewScraperSource wraps fn as a source selectable by key.
+func NewScraperSource(key, name string, fn ScraperFunc) ScraperSource {
+	return ScraperSource{key: key, name: name, fn: fn}
+}
+
+// Key returns the identifier accepted by WithSources.
+func (s ScraperSource) Key() string { return s.key }
+
+// Name returns the display name used in progress updates.
+func (s ScraperSource) Name() string { return s.name }
+
+// SourceKeys lists the keys of sources, in order.
+func SourceKeys(sources []ScraperSource) []string {
+	keys := make([]string, len(sources))
+	for i, s := range sources {
+		keys[i] = s.key
+	}
+	return keys
+}
+
 // ScraperFunc represents a scraping function with context support
 type ScraperFunc func(ctx context.Context, keywords []string, location string) ([]job.Job, error)
 
 // NewIncrementalScraper creates a new incremental scraper
-func NewIncrementalScraper(ctx context.Context, prof profile.Profile) *IncrementalScraper {
+func NewIncrementalScraper(ctx context.Context, prof profile.Profile, opts ...IncrementalOption) *IncrementalScraper {
 	ctx, cancel := context.WithCancel(ctx)
 
-	return &IncrementalScraper{
+	is := &IncrementalScraper{
 		ctx:      ctx,
 		cancel:   cancel,
 		profile:  prof,
@@ -59,6 +163,42 @@ func NewIncrementalScraper(ctx context.Context, prof profile.Profile) *Increment
 		errors:   make(chan error, 10),
 		progress: make(chan ScraperProgress, 10),
 	}
+	for _, opt := range opts {
+		opt(is)
+	}
+	// Without WithSources, the profile's own selection applies.
+	if len(is.only) == 0 {
+		is.only = prof.Sources
+	}
+	// Overridden keywords drive the profile's keyword filter too, not
+	// just the search.
+	if len(is.keywords) > 0 {
+		is.profile.Keywords = is.keywords
+	}
+	return is
+}
+
+// Keywords returns the keywords the scraper searches for.
+func (is *IncrementalScraper) Keywords() []string {
+	if len(is.profile.Keywords) > 0 {
+		return is.profile.Keywords
+	}
+	return []string{"golang", "rust", "remote"}
+}
+
+// Validate reports a selected source that does not exist, naming the
+// valid ones. Start checks it too, sending the error and running nothing.
+func (is *IncrementalScraper) Validate() error {
+	all := is.sources
+	if all == nil {
+		all = DefaultSources(is.profile)
+	}
+	return CheckSources(all, is.only)
+}
+
+// Sources returns the keys of the sources the scraper will run.
+func (is *IncrementalScraper) Sources() []string {
+	return SourceKeys(is.getScraperSources())
 }
 
 // Start begins incremental scraping
@@ -98,6 +238,10 @@ func (is *IncrementalScraper) runScraping() {
 	defer close(is.errors)
 	defer close(is.progress)
 
+	if err := is.Validate(); err != nil {
+		is.errors <- err
+		return
+	}
 	startTime := time.Now()
 	sources := is.getScraperSources()
 
@@ -105,11 +249,8 @@ func (is *IncrementalScraper) runScraping() {
 	is.totalJobs = len(sources)
 	is.mu.Unlock()
 
-	// Create base keywords from profile
-	keywords := is.profile.Keywords
-	if len(keywords) == 0 {
-		keywords = []string{"golang", "rust", "remote"}
-	}
+	keywords := is.Keywords()
+	sent := 0
 
 	location := ""
 	if is.profile.PreferRemote {
@@ -133,12 +274,34 @@ func (is *IncrementalScraper) runScraping() {
 
 		// Run scraper with timeout
 		ctx, cancel := context.WithTimeout(is.ctx, 30*time.Second)
+		began := time.Now()
 		jobs, err := source.fn(ctx, keywords, location)
 		cancel()
+		is.recordStats(source, len(jobs), err, time.Since(began))
 
 		if err != nil {
-			is.errors <- fmt.Errorf("error scraping %s: %w", sourceName, err)
-			continue
+			// Sources of many boards or feeds report each that failed.
+			errs := []error{err}
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				errs = joined.Unwrap()
+			}
+			for _, err := range errs {
+				is.errors <- fmt.Errorf("error scraping %s: %w", sourceName, err)
+			}
+			// Sources like Ashby return what they could fetch alongside
+			// per-board errors; keep going if anything came back.
+			if len(jobs) == 0 {
+				continue
+			}
+		}
+
+		fetched, seen := len(jobs), 0
+		if is.seen != nil && !is.force {
+			if fresh, n, err := is.seen.SkipSeen(jobs); err != nil {
+				is.errors <- fmt.Errorf("checking %s for jobs already seen: %w", sourceName, err)
+			} else {
+				jobs, seen = fresh, n
+			}
 		}
 
 		// Apply profile scoring and filtering incrementally
@@ -148,12 +311,22 @@ func (is *IncrementalScraper) runScraping() {
 		is.processedJobs++
 		is.mu.Unlock()
 
-		is.sendProgress(sourceName, len(filteredJobs), len(sources), i+1, time.Since(startTime), "Complete")
+		done := ScraperProgress{Source: sourceName, JobsFound: len(filteredJobs), TotalSources: len(sources),
+			CurrentSource: i + 1, ElapsedTime: time.Since(startTime), Status: "Complete", Fetched: fetched}
+		if is.seen != nil {
+			done.New, done.Seen = len(jobs), seen
+		}
+		is.send(done)
 
-		// Send results as they're processed
-		for _, job := range filteredJobs {
+		// Send results as they're processed.
+		for _, j := range filteredJobs {
+			if is.maxJobs > 0 && sent >= is.maxJobs {
+				is.sendProgress(sourceName, sent, len(sources), i+1, time.Since(startTime), "Job limit reached")
+				return
+			}
 			select {
-			case is.results <- job:
+			case is.results <- j:
+				sent++
 			case <-is.ctx.Done():
 				return
 			}
@@ -169,16 +342,20 @@ func (is *IncrementalScraper) runScraping() {
 func (is *IncrementalScraper) processJobsIncrementally(jobs []job.Job) []job.Job {
 	var filteredJobs []job.Job
 
+	if is.enrich != nil {
+		jobs = is.enrich(jobs)
+	}
 	for _, j := range jobs {
-		// Apply profile scoring
-		j.Score = is.profile.CalculateJobScore(&j)
+		// Apply profile scoring to the description as it will be stored
+		j.CapDescription()
+		j.Score = is.profile.ScoreJob(&j)
 
 		// Apply basic filters
 		if j.Score < is.profile.MinScore || j.Score > is.profile.MaxScore {
 			continue
 		}
 
-		if is.profile.ExcludeTraps && j.HasTraps {
+		if is.profile.ExcludeTraps && j.TrapsAtLeast(is.profile.TrapSeverity) {
 			continue
 		}
 
@@ -190,58 +367,163 @@ func (is *IncrementalScraper) processJobsIncrementally(jobs []job.Job) []job.Job
 	return job.Pipe(filters...)(filteredJobs)
 }
 
+// recordStats records a run of source under WithStats. A source cut
+// short by the scrape being cancelled is recorded as a partial run, whose
+// error says nothing about the source.
+func (is *IncrementalScraper) recordStats(source ScraperSource, jobs int, err error, latency time.Duration) {
+	if is.stats == nil {
+		return
+	}
+	if is.ctx.Err() != nil {
+		err = is.stats.RecordPartial(source.key, jobs, latency, time.Now())
+	} else {
+		err = is.stats.Record(source.key, jobs, err, latency, time.Now())
+	}
+	if err != nil {
+		select {
+		case is.errors <- fmt.Errorf("recording stats for %s: %w", source.name, err):
+		default:
+		}
+	}
+}
+
 func (is *IncrementalScraper) sendProgress(sourceName string, jobsFound, totalSources, currentSource int, elapsed time.Duration, status string) {
-	select {
-	case is.progress <- ScraperProgress{
+	is.send(ScraperProgress{
 		Source:        sourceName,
 		JobsFound:     jobsFound,
 		TotalSources:  totalSources,
 		CurrentSource: currentSource,
 		ElapsedTime:   elapsed,
 		Status:        status,
-	}:
+	})
+}
+
+func (is *IncrementalScraper) send(p ScraperProgress) {
+	select {
+	case is.progress <- p:
 	case <-is.ctx.Done():
 	}
 }
 
+// getScraperSources returns the sources to run: the configured set (or the
+// built-in one), narrowed to WithSources keys when given, else without
+// the OptInSourceKeys.
 func (is *IncrementalScraper) getScraperSources() []ScraperSource {
+	all := is.sources
+	if all == nil {
+		all = DefaultSources(is.profile)
+		for i := range all {
+			all[i].fn = gatedFunc(all[i].fn)
+		}
+	}
+	if len(is.only) == 0 {
+		return slices.DeleteFunc(slices.Clone(all), func(s ScraperSource) bool {
+			return slices.Contains(OptInSourceKeys, s.key)
+		})
+	}
+	var picked []ScraperSource
+	for _, s := range all {
+		for _, key := range is.only {
+			if s.key == key {
+				picked = append(picked, s)
+				break
+			}
+		}
+	}
+	return picked
+}
+
+// CheckSources returns an error naming every key that is not one of
+// sources, and the keys that are.
+func CheckSources(sources []ScraperSource, keys []string) error {
+	valid := SourceKeys(sources)
+	var unknown []string
+	for _, key := range keys {
+		if !slices.Contains(valid, key) {
+			unknown = append(unknown, fmt.Sprintf("%q", key))
+		}
+	}
+	switch len(unknown) {
+	case 0:
+		return nil
+	case 1:
+		return fmt.Errorf("unknown source %s; valid sources: %s", unknown[0], strings.Join(valid, ", "))
+	default:
+		return fmt.Errorf("unknown sources %s; valid sources: %s", strings.Join(unknown, ", "), strings.Join(valid, ", "))
+	}
+}
+
+// KnownSourceKeys lists the keys of DefaultSources, for pickers and flags.
+func KnownSourceKeys() []string {
+	return SourceKeys(DefaultSources(profile.Profile{}))
+}
+
+// Selected merges the DefaultSources of prof named by keys into one
+// scraper, searching for keywords in location. It errors on an unknown
+// key rather than skipping it.
+func Selected(prof profile.Profile, keys, keywords []string, location string) (job.Scraper, error) {
+	return SelectedContext(context.Background(), prof, keys, keywords, location)
+}
+
+// SelectedContext is Selected, its sources abandoning their requests if
+// ctx is cancelled.
+func SelectedContext(ctx context.Context, prof profile.Profile, keys, keywords []string, location string) (job.Scraper, error) {
+	all := DefaultSources(prof)
+	if err := CheckSources(all, keys); err != nil {
+		return nil, err
+	}
+	var picked []job.Scraper
+	for _, s := range all {
+		if slices.Contains(keys, s.key) {
+			fn := gatedFunc(s.fn)
+			picked = append(picked, func() ([]job.Job, error) {
+				return fn(ctx, keywords, location)
+			})
+		}
+	}
+	return job.Merge(picked...), nil
+}
+
+// APISourceKeys are the DefaultSources that need no browser.
+var APISourceKeys = []string{"hn", "remoteok", "greenhouse", "ashby", "weworkremotely", "arbeitnow", "jobicy"}
+
+// DefaultSources is the built-in source set for prof. Sources search for
+// the keywords and location they are given where their APIs can (see
+// search.go), and otherwise fetch everything for the profile's filters.
+func DefaultSources(prof profile.Profile) []ScraperSource {
 	return []ScraperSource{
-		{name: "Hacker News", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
-			scraper := HN()
-			return scraper()
+		{key: "hn", name: "Hacker News", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+			return hn(ctx, searchTerms(keywords))()
+		}},
+		{key: "remoteok", name: "RemoteOK", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+			return remoteOK(ctx, searchTerms(keywords))()
 		}},
-		{name: "RemoteOK", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
-			scraper := RemoteOK()
-			return scraper()
+		{key: "greenhouse", name: "Greenhouse", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+			return scrapeGreenhouse(ctx, GreenhouseBoards(prof))
 		}},
-		{name: "Greenhouse", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
-			scraper := Greenhouse(DefaultGreenhouseBoards)
-			return scraper()
+		{key: "ashby", name: "Ashby", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+			return scrapeAshby(ctx, AshbyOrgs(prof))
 		}},
-		{name: "We Work Remotely", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
-			scraper := WeWorkRemotely()
-			return scraper()
+		{key: "weworkremotely", name: "We Work Remotely", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+			return weWorkRemotely(ctx, searchTerms(keywords))()
 		}},
-		{name: "Arbeitnow", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
-			scraper := Arbeitnow()
-			return scraper()
+		{key: "arbeitnow", name: "Arbeitnow", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+			return arbeitnow(ctx, searchTerms(keywords), remoteLocation(location))()
 		}},
-		{name: "Jobicy", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
-			scraper := Jobicy()
-			return scraper()
+		{key: "jobicy", name: "Jobicy", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+			return jobicy(ctx, searchTerms(keywords), location)()
 		}},
-		{name: "RSS Feeds", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
-			// RSS feeds need to be handled differently - return empty for now
-			return []job.Job{}, nil
+		{key: "rss", name: "RSS Feeds", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+			return scrapeFeeds(ctx, Feeds(prof))
 		}},
-		{name: "LinkedIn", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+		{key: "linkedin", name: "LinkedIn", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
 			return LinkedIn(keywords, location)()
 		}},
-		{name: "Indeed", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
-			return Indeed(keywords[0], location)()
+		{key: "indeed", name: "Indeed", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+			return Indeed(firstKeyword(keywords), location)()
 		}},
-		{name: "Glassdoor", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
-			return Glassdoor(keywords[0])()
+		{key: "glassdoor", name: "Glassdoor", fn: func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+			return Glassdoor(firstKeyword(keywords))()
 		}},
 	}
 }
@@ -13,12 +13,15 @@ import (
 
 // Indeed returns a browser-based scraper for Indeed job search.
 func Indeed(query, location string) job.Scraper {
+	if strings.TrimSpace(query) == "" {
+		return needsKeyword("Indeed")
+	}
 	url := fmt.Sprintf("https://www.indeed.com/jobs?q=%s&l=%s&fromage=7",
 		strings.ReplaceAll(query, " ", "+"),
 		strings.ReplaceAll(location, " ", "+"),
 	)
 
-	return BrowserScrape(url, func(page *rod.Page) ([]job.Job, error) {
+	return blockable("Indeed", url, func(page *rod.Page) ([]job.Job, error) {
 		page.MustWaitStable()
 
 		elements, err := page.Elements(".job_seen_beacon, .jobsearch-ResultsList .result")
@@ -83,11 +86,14 @@ func Indeed(query, location string) job.Scraper {
 
 // Glassdoor returns a browser-based scraper for Glassdoor job search.
 func Glassdoor(query string) job.Scraper {
+	if strings.TrimSpace(query) == "" {
+		return needsKeyword("Glassdoor")
+	}
 	url := fmt.Sprintf("https://www.glassdoor.com/Job/jobs.htm?sc.keyword=%s",
 		strings.ReplaceAll(query, " ", "+"),
 	)
 
-	return BrowserScrape(url, func(page *rod.Page) ([]job.Job, error) {
+	return blockable("Glassdoor", url, func(page *rod.Page) ([]job.Job, error) {
 		page.MustWaitStable()
 
 		elements, err := page.Elements("[data-test='jobListing'], .react-job-listing")
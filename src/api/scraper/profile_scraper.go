@@ -22,7 +22,7 @@ func ProfileBasedScraper(profile profile.Profile) job.Scraper {
 	}
 
 	// Create base scraper
-	baseScraper := All(keywords, location)
+	baseScraper := All(profile, keywords, location)
 
 	// Apply profile-based post-processing
 	return func() ([]job.Job, error) {
@@ -34,8 +34,10 @@ func ProfileBasedScraper(profile profile.Profile) job.Scraper {
 		// Apply profile scoring and filtering
 		scoredJobs := make([]job.Job, len(jobs))
 		for i, job := range jobs {
-			// Calculate custom score based on profile
-			job.Score = profile.CalculateJobScore(&job)
+			// Calculate custom score based on profile, on the
+			// description as it will be stored
+			job.CapDescription()
+			job.Score = profile.ScoreJob(&job)
 			scoredJobs[i] = job
 		}
 
@@ -55,7 +57,7 @@ func FastProfileScraper(profile profile.Profile) job.Scraper {
 	}
 
 	// Use only fast API sources
-	baseScraper := APIOnly()
+	baseScraper := APIOnly(profile, keywords)
 
 	return func() ([]job.Job, error) {
 		jobs, err := baseScraper()
@@ -70,7 +72,8 @@ func FastProfileScraper(profile profile.Profile) job.Scraper {
 		// Apply profile scoring and filtering
 		scoredJobs := make([]job.Job, len(keywordJobs))
 		for i, job := range keywordJobs {
-			job.Score = profile.CalculateJobScore(&job)
+			job.CapDescription()
+			job.Score = profile.ScoreJob(&job)
 			scoredJobs[i] = job
 		}
 
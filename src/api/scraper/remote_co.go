@@ -1,6 +1,7 @@
 package scraper
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -43,9 +44,14 @@ import (
 // - Network routing problems
 // - Firewall restrictions
 func RemoteCo() job.Scraper {
+	return remoteCo(context.Background())
+}
+
+// remoteCo is RemoteCo, abandoning its requests if ctx is cancelled.
+func remoteCo(ctx context.Context) job.Scraper {
 	return func() ([]job.Job, error) {
 		// Test basic connectivity first
-		if err := testRemoteCoConnectivity(); err != nil {
+		if err := testRemoteCoConnectivity(ctx); err != nil {
 			return nil, fmt.Errorf("remote.co accessibility issue: %w", err)
 		}
 
@@ -60,7 +66,7 @@ func RemoteCo() job.Scraper {
 		}
 
 		for _, endpoint := range endpoints {
-			jobs, err := scrapeRemoteCoEndpoint(endpoint)
+			jobs, err := scrapeRemoteCoEndpoint(ctx, endpoint)
 			if err == nil && len(jobs) > 0 {
 				all = append(all, jobs...)
 			}
@@ -74,8 +80,8 @@ func RemoteCo() job.Scraper {
 	}
 }
 
-func scrapeRemoteCoEndpoint(url string) ([]job.Job, error) {
-	data, err := httpGet(url)
+func scrapeRemoteCoEndpoint(ctx context.Context, url string) ([]job.Job, error) {
+	data, err := httpGet(ctx, url)
 	if err != nil {
 		// Provide more specific error messages based on common issues
 		if strings.Contains(err.Error(), "timeout") {
@@ -206,9 +212,13 @@ func TestRemoteCoConnectivity() error {
 }
 
 // testRemoteCoConnectivity checks if Remote.co is accessible from this environment
-func testRemoteCoConnectivity() error {
+func testRemoteCoConnectivity(ctx context.Context) error {
 	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get("https://remote.co")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://remote.co", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("connection failed: %w", err)
 	}
@@ -1,8 +1,11 @@
 package scraper
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
@@ -11,52 +14,92 @@ import (
 
 // RemoteOK scrapes the RemoteOK public JSON API.
 func RemoteOK() job.Scraper {
-	return func() ([]job.Job, error) {
-		data, err := httpGet("https://remoteok.com/api")
-		if err != nil {
-			return nil, fmt.Errorf("RemoteOK API: %w", err)
-		}
+	return remoteOK(context.Background(), nil)
+}
 
-		// RemoteOK returns an array where [0] is metadata, rest are jobs
-		var raw []json.RawMessage
-		if err := json.Unmarshal(data, &raw); err != nil {
-			return nil, fmt.Errorf("RemoteOK parse: %w", err)
-		}
+// remoteOKAPI is the RemoteOK API; tests point it elsewhere.
+var remoteOKAPI = "https://remoteok.com/api"
 
-		var jobs []job.Job
-		for i, entry := range raw {
-			if i == 0 {
-				continue // Skip metadata entry
+// remoteOK fetches the postings tagged with any of terms, one request per
+// tag, or every posting when there are none. A tag that fails leaves the
+// others' postings; it errors only when every request did.
+func remoteOK(ctx context.Context, terms []string) job.Scraper {
+	return func() ([]job.Job, error) {
+		urls := []string{remoteOKAPI}
+		if len(terms) > 0 {
+			urls = nil
+			for _, t := range terms {
+				urls = append(urls, remoteOKAPI+"?tag="+url.QueryEscape(tagSlug(t)))
 			}
-
-			var r remoteOKJob
-			if err := json.Unmarshal(entry, &r); err != nil {
-				// Debug: print the error and raw data
-				fmt.Printf("RemoteOK JSON parse error: %v\n", err)
-				fmt.Printf("Raw data: %s\n", string(entry)[:100])
+		}
+		var jobs []job.Job
+		var errs []error
+		got := make(map[string]bool)
+		for _, u := range urls {
+			page, err := remoteOKPage(ctx, u)
+			if err != nil {
+				errs = append(errs, err)
 				continue
 			}
+			for _, j := range page {
+				if !got[j.ID] {
+					got[j.ID] = true
+					jobs = append(jobs, j)
+				}
+			}
+		}
+		if len(errs) == len(urls) {
+			return nil, errors.Join(errs...)
+		}
+		return jobs, nil
+	}
+}
+
+func remoteOKPage(ctx context.Context, apiURL string) ([]job.Job, error) {
+	data, err := httpGet(ctx, apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("RemoteOK API: %w", err)
+	}
 
-			posted := time.Unix(r.Epoch, 0)
+	// RemoteOK returns an array where [0] is metadata, rest are jobs
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("RemoteOK parse: %w", err)
+	}
 
-			j := job.Job{
-				ID:          fmt.Sprintf("rok-%s", r.ID),
-				Title:       r.Position,
-				Company:     r.Company,
-				Location:    r.Location, // Direct assignment since it's now a string
-				Description: stripHTML(r.Description),
-				URL:         fmt.Sprintf("https://remoteok.com/remote-jobs/%s", r.Slug),
-				Source:      "remoteok",
-				PostedDate:  posted,
-				Salary:      formatSalary(r.SalaryMin, r.SalaryMax),
-				JobType:     strings.Join(r.Tags, ", "),
-				Score:       50,
-			}
-			jobs = append(jobs, j)
+	var jobs []job.Job
+	for i, entry := range raw {
+		if i == 0 {
+			continue // Skip metadata entry
 		}
 
-		return jobs, nil
+		var r remoteOKJob
+		if err := json.Unmarshal(entry, &r); err != nil {
+			// Debug: print the error and raw data
+			fmt.Printf("RemoteOK JSON parse error: %v\n", err)
+			fmt.Printf("Raw data: %s\n", string(entry)[:100])
+			continue
+		}
+
+		posted := time.Unix(r.Epoch, 0)
+
+		j := job.Job{
+			ID:          fmt.Sprintf("rok-%s", r.ID),
+			Title:       r.Position,
+			Company:     r.Company,
+			Location:    r.Location, // Direct assignment since it's now a string
+			Description: stripHTML(r.Description),
+			URL:         fmt.Sprintf("https://remoteok.com/remote-jobs/%s", r.Slug),
+			Source:      "remoteok",
+			PostedDate:  posted,
+			Salary:      formatSalary(r.SalaryMin, r.SalaryMax),
+			JobType:     strings.Join(r.Tags, ", "),
+			Score:       50,
+		}
+		jobs = append(jobs, j)
 	}
+
+	return jobs, nil
 }
 
 type remoteOKJob struct {
@@ -1,6 +1,7 @@
 package scraper
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -12,9 +13,14 @@ import (
 
 // Remotive scrapes the Remotive public JSON API.
 func Remotive() job.Scraper {
+	return remotive(context.Background())
+}
+
+// remotive is Remotive, abandoning the request if ctx is cancelled.
+func remotive(ctx context.Context) job.Scraper {
 	return func() ([]job.Job, error) {
 		// Only fetch software dev jobs to keep it relevant and mostly within limit
-		data, err := httpGet("https://remotive.com/api/remote-jobs?category=software-dev")
+		data, err := httpGet(ctx, "https://remotive.com/api/remote-jobs?category=software-dev")
 		if err != nil {
 			return nil, fmt.Errorf("Remotive API: %w", err)
 		}
@@ -0,0 +1,186 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"sprayer/src/api/httpapi"
+)
+
+// RetryPolicy is how a source retries a request that failed for a
+// reason that may pass: a network error, a 408, a 429 or a 5xx.
+type RetryPolicy struct {
+	Attempts int           // tries in all, the first included
+	Base     time.Duration // wait before the second try, doubled for each one after
+	Max      time.Duration // longest single wait, a Retry-After included
+}
+
+// DefaultRetry is the policy httpGet uses for every source.
+var DefaultRetry = RetryPolicy{Attempts: 3, Base: time.Second, Max: 30 * time.Second}
+
+// RetryError is what a request that kept failing returns: the error of
+// every attempt, in order.
+type RetryError struct {
+	Errs []error
+}
+
+func (e *RetryError) Error() string {
+	parts := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		parts[i] = fmt.Sprintf("attempt %d: %v", i+1, err)
+	}
+	return fmt.Sprintf("gave up after %d attempts: %s", len(e.Errs), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the last attempt's error.
+func (e *RetryError) Unwrap() error { return e.Errs[len(e.Errs)-1] }
+
+// Do calls fn until it succeeds, fails for good or the attempts run
+// out, backing off in between. Cancelling ctx ends a backoff at once and
+// returns ctx's error. A failure after more than one attempt is a
+// *RetryError.
+func (p RetryPolicy) Do(ctx context.Context, fn func(context.Context) error) error {
+	var errs []error
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		errs = append(errs, err)
+		if !retryable(err) || attempt >= p.Attempts {
+			break
+		}
+		if err := sleepCtx(ctx, p.backoff(attempt, err)); err != nil {
+			return err
+		}
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return &RetryError{Errs: errs}
+}
+
+// backoff is the wait after the given failed attempt: Base doubled per
+// attempt, or the server's Retry-After when longer, capped at Max.
+func (p RetryPolicy) backoff(attempt int, err error) time.Duration {
+	d := p.Base << (attempt - 1)
+	var se *statusError
+	if errors.As(err, &se) && se.RetryAfter > d {
+		d = se.RetryAfter
+	}
+	if p.Max > 0 && (d > p.Max || d < 0) {
+		d = p.Max
+	}
+	return d
+}
+
+// statusError is a response other than 200 OK.
+type statusError struct {
+	Code       int
+	URL        string
+	RetryAfter time.Duration // zero when the response gave none
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("HTTP %d from %s", e.Code, e.URL)
+}
+
+func retryable(err error) bool {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.Code == http.StatusRequestTimeout || se.Code == http.StatusTooManyRequests || se.Code >= 500
+	}
+	return true
+}
+
+// sleepCtx sleeps for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// hostLimiter spaces requests to the same host at least interval apart,
+// whichever source or goroutine makes them.
+type hostLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time // earliest start of the host's next request
+}
+
+func newHostLimiter(interval time.Duration) *hostLimiter {
+	return &hostLimiter{interval: interval, next: map[string]time.Time{}}
+}
+
+// wait blocks until host may be sent another request, or ctx is done.
+func (l *hostLimiter) wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	l.next[host] = at.Add(l.interval)
+	l.mu.Unlock()
+	return sleepCtx(ctx, at.Sub(now))
+}
+
+var (
+	httpClient = &http.Client{Timeout: 15 * time.Second}
+	hosts      = newHostLimiter(300 * time.Millisecond)
+)
+
+// httpGet fetches rawURL, taking its turn with the host and retrying
+// transient failures under DefaultRetry. It reads at most 1MB.
+func httpGet(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	var body []byte
+	err = DefaultRetry.Do(ctx, func(ctx context.Context) error {
+		if err := hosts.wait(ctx, u.Host); err != nil {
+			return err
+		}
+		body, err = getOnce(ctx, rawURL)
+		return err
+	})
+	return body, err
+}
+
+func getOnce(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		wait, _ := httpapi.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return nil, &statusError{Code: resp.StatusCode, URL: rawURL, RetryAfter: wait}
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
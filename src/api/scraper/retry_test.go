@@ -0,0 +1,150 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fastRetries makes httpGet retry without real waits for the test.
+func fastRetries(t *testing.T) {
+	t.Helper()
+	oldRetry, oldHosts := DefaultRetry, hosts
+	DefaultRetry = RetryPolicy{Attempts: 3, Base: time.Millisecond, Max: 10 * time.Millisecond}
+	hosts = newHostLimiter(0)
+	t.Cleanup(func() { DefaultRetry, hosts = oldRetry, oldHosts })
+}
+
+// failingServer answers the first fails requests with code, then 200.
+func failingServer(t *testing.T, code, fails int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(calls.Add(1)) <= fails {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(code)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestHTTPGet_RetriesTransientFailures(t *testing.T) {
+	fastRetries(t)
+	srv, calls := failingServer(t, http.StatusTooManyRequests, 2)
+
+	body, err := httpGet(context.Background(), srv.URL)
+	if err != nil || string(body) != "ok" {
+		t.Fatalf("httpGet = %q, %v", body, err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("%d requests, want 3", calls.Load())
+	}
+}
+
+func TestHTTPGet_GivesUpAfterAttempts(t *testing.T) {
+	fastRetries(t)
+	srv, calls := failingServer(t, http.StatusServiceUnavailable, 10)
+
+	_, err := httpGet(context.Background(), srv.URL)
+	var re *RetryError
+	if !errors.As(err, &re) || len(re.Errs) != 3 {
+		t.Fatalf("err = %v, want a RetryError of 3 attempts", err)
+	}
+	for _, want := range []string{"gave up after 3 attempts", "attempt 1: HTTP 503", "attempt 3: HTTP 503"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	if calls.Load() != 3 {
+		t.Errorf("%d requests, want 3", calls.Load())
+	}
+}
+
+func TestHTTPGet_DoesNotRetryClientErrors(t *testing.T) {
+	fastRetries(t)
+	srv, calls := failingServer(t, http.StatusNotFound, 10)
+
+	_, err := httpGet(context.Background(), srv.URL)
+	var re *RetryError
+	if err == nil || errors.As(err, &re) || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Errorf("err = %v, want a plain 404", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("%d requests, want 1", calls.Load())
+	}
+}
+
+func TestRetryPolicy_CancelInterruptsBackoff(t *testing.T) {
+	p := RetryPolicy{Attempts: 3, Base: time.Hour, Max: time.Hour}
+	ctx, cancel := context.WithCancel(context.Background())
+	tries := 0
+	done := make(chan error)
+	go func() {
+		done <- p.Do(ctx, func(context.Context) error {
+			tries++
+			return errors.New("connection reset")
+		})
+	}()
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) || tries != 1 {
+			t.Errorf("Do = %v after %d tries, want context.Canceled after 1", err, tries)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelling did not interrupt the backoff")
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{Attempts: 5, Base: time.Second, Max: 5 * time.Second}
+	for _, tc := range []struct {
+		attempt int
+		err     error
+		want    time.Duration
+	}{
+		{1, errors.New("reset"), time.Second},
+		{2, errors.New("reset"), 2 * time.Second},
+		{4, errors.New("reset"), 5 * time.Second},
+		{1, &statusError{Code: 429, RetryAfter: 3 * time.Second}, 3 * time.Second},
+		{1, &statusError{Code: 429, RetryAfter: time.Minute}, 5 * time.Second},
+	} {
+		if got := p.backoff(tc.attempt, tc.err); got != tc.want {
+			t.Errorf("backoff(%d, %v) = %v, want %v", tc.attempt, tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestHostLimiter_SpacesRequestsPerHost(t *testing.T) {
+	l := newHostLimiter(30 * time.Millisecond)
+	ctx := context.Background()
+	start := time.Now()
+	for range 3 {
+		l.wait(ctx, "a.example")
+	}
+	if took := time.Since(start); took < 60*time.Millisecond {
+		t.Errorf("three requests to one host took %v, want at least 60ms", took)
+	}
+
+	start = time.Now()
+	l.wait(ctx, "b.example")
+	if took := time.Since(start); took > 20*time.Millisecond {
+		t.Errorf("first request to another host waited %v", took)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	l.wait(ctx, "c.example")
+	if err := l.wait(cancelled, "c.example"); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait on a cancelled context = %v", err)
+	}
+}
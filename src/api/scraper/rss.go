@@ -1,100 +1,387 @@
 package scraper
 
 import (
+	"context"
+	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
+	"sprayer/src/api/httpapi"
 	"sprayer/src/api/job"
 	"sprayer/src/api/parse"
+	"sprayer/src/api/profile"
 )
 
+// Feed is an RSS or Atom job feed and the source name its jobs carry.
+type Feed struct {
+	Name string
+	URL  string
+}
+
+// DefaultFeeds are the well-known job feeds scraped for a profile that
+// names none.
+var DefaultFeeds = []Feed{
+	{"crypto-jobs", "https://crypto.jobs/feed"},
+	{"nodejs-jobs", "https://nodesk.co/remote-jobs/rss/"},
+	{"golang-cafe", "https://golang.cafe/Ede/rss.xml"},
+	{"rustjobs", "https://rustjobs.dev/feed.xml"},
+	{"functional-works", "https://functional.works-hub.com/feed"},
+	{"pythonjobs", "https://pythonjobs.dev/feed.xml"},
+}
+
+// Feeds is the feeds scraped for p: its own, named by their host, else
+// DefaultFeeds.
+func Feeds(p profile.Profile) []Feed {
+	if len(p.Feeds) == 0 {
+		return DefaultFeeds
+	}
+	var feeds []Feed
+	for _, raw := range p.Feeds {
+		name := raw
+		for _, d := range DefaultFeeds {
+			if d.URL == raw {
+				name = d.Name
+			}
+		}
+		if name == raw {
+			if u, err := url.Parse(raw); err == nil && u.Host != "" {
+				name = strings.TrimPrefix(u.Hostname(), "www.")
+			}
+		}
+		feeds = append(feeds, Feed{Name: name, URL: raw})
+	}
+	return feeds
+}
+
+// CheckFeedURL reports a feed URL that is not an absolute http(s) URL.
+func CheckFeedURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q is not an http(s) feed URL", raw)
+	}
+	return nil
+}
+
+// FeedError reports a single feed that could not be scraped. Other feeds
+// are unaffected.
+type FeedError struct {
+	URL string
+	Err error
+}
+
+func (e *FeedError) Error() string { return fmt.Sprintf("feed %s: %v", e.URL, e.Err) }
+
+func (e *FeedError) Unwrap() error { return e.Err }
+
 // RSS creates a scraper from any RSS/Atom job board feed.
 // Higher-order: takes a source name and URL, returns a Scraper.
 func RSS(source, feedURL string) job.Scraper {
+	return rss(context.Background(), Feed{Name: source, URL: feedURL})
+}
+
+// rss is RSS for f, abandoning the request if ctx is cancelled.
+func rss(ctx context.Context, f Feed) job.Scraper {
 	return func() ([]job.Job, error) {
-		data, err := httpGet(feedURL)
-		if err != nil {
-			return nil, fmt.Errorf("RSS %s: %w", source, err)
-		}
+		return scrapeFeed(ctx, f)
+	}
+}
 
-		var feed rssFeed
-		if err := xml.Unmarshal(data, &feed); err != nil {
-			return nil, fmt.Errorf("RSS %s parse: %w", source, err)
-		}
+// CommonRSSFeeds returns scrapers for DefaultFeeds.
+func CommonRSSFeeds() []job.Scraper {
+	return feedScrapers(context.Background(), DefaultFeeds)
+}
 
-		var jobs []job.Job
-		for _, item := range feed.Channel.Items {
-			desc := stripHTML(item.Description)
-			posted, _ := time.Parse(time.RFC1123Z, item.PubDate)
-			if posted.IsZero() {
-				posted, _ = time.Parse(time.RFC1123, item.PubDate)
-			}
-			if posted.IsZero() {
-				posted = time.Now()
-			}
+func feedScrapers(ctx context.Context, feeds []Feed) []job.Scraper {
+	var scrapers []job.Scraper
+	for _, f := range feeds {
+		scrapers = append(scrapers, rss(ctx, f))
+	}
+	return scrapers
+}
 
-			j := job.Job{
-				ID:          idFromContent(source, item.Link+item.Title),
-				Title:       item.Title,
-				Company:     extractCompanyFromTitle(item.Title),
-				Location:    strings.Join(parse.ExtractLocations(desc), ", "),
-				Description: desc,
-				URL:         item.Link,
-				Source:      source,
-				PostedDate:  posted,
-				Email:       parse.ExtractFirstEmail(desc),
-				Salary:      parse.ExtractSalary(desc),
-				Score:       50,
-			}
-			jobs = append(jobs, j)
+// scrapeFeeds fetches every feed, returning the jobs it could get along
+// with a joined error of per-feed failures.
+func scrapeFeeds(ctx context.Context, feeds []Feed) ([]job.Job, error) {
+	var all []job.Job
+	var errs []error
+	for _, f := range feeds {
+		jobs, err := scrapeFeed(ctx, f)
+		if ctx.Err() != nil {
+			return all, ctx.Err()
 		}
-		return jobs, nil
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		all = append(all, jobs...)
+	}
+	return all, errors.Join(errs...)
+}
+
+// scrapeFeed fetches and maps one feed. A feed unchanged since the last
+// run yields no jobs.
+func scrapeFeed(ctx context.Context, f Feed) ([]job.Job, error) {
+	data, err := fetchFeed(ctx, f.URL)
+	if err != nil {
+		return nil, &FeedError{URL: f.URL, Err: err}
+	}
+	if data == nil {
+		return nil, nil
+	}
+	jobs, err := parseFeed(data, f.Name)
+	if err != nil {
+		return nil, &FeedError{URL: f.URL, Err: err}
 	}
+	return jobs, nil
 }
 
-type rssFeed struct {
+// feedDoc decodes RSS 2.0, RSS 1.0, whose items sit beside the channel,
+// and Atom.
+type feedDoc struct {
+	XMLName xml.Name
 	Channel struct {
 		Items []rssItem `xml:"item"`
 	} `xml:"channel"`
+	Items   []rssItem   `xml:"item"`
+	Entries []atomEntry `xml:"entry"`
 }
 
 type rssItem struct {
 	Title       string `xml:"title"`
 	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
 	Description string `xml:"description"`
+	Content     string `xml:"encoded"` // content:encoded
 	PubDate     string `xml:"pubDate"`
+	Date        string `xml:"date"` // dc:date
 }
 
-func extractCompanyFromTitle(title string) string {
-	// Many RSS job feeds use "Role at Company" or "Company - Role"
-	if idx := strings.Index(title, " at "); idx > 0 {
-		return strings.TrimSpace(title[idx+4:])
+type atomEntry struct {
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Summary   string `xml:"summary"`
+	Content   string `xml:"content"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+}
+
+// link is the entry's alternate link, the one without a rel or with
+// rel="alternate".
+func (e atomEntry) link() string {
+	for _, l := range e.Links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return l.Href
+		}
 	}
-	if parts := strings.SplitN(title, " - ", 2); len(parts) == 2 {
-		return strings.TrimSpace(parts[0])
+	return ""
+}
+
+// parseFeed maps the items of an RSS or Atom feed to jobs of source.
+func parseFeed(data []byte, source string) ([]job.Job, error) {
+	var doc feedDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	var jobs []job.Job
+	for _, item := range append(doc.Channel.Items, doc.Items...) {
+		link := strings.TrimSpace(item.Link)
+		if link == "" && strings.HasPrefix(item.GUID, "http") {
+			link = strings.TrimSpace(item.GUID)
+		}
+		jobs = append(jobs, feedJob(source, item.Title, link, firstNonEmpty(item.Content, item.Description), firstNonEmpty(item.PubDate, item.Date)))
+	}
+	for _, e := range doc.Entries {
+		jobs = append(jobs, feedJob(source, e.Title, e.link(), firstNonEmpty(e.Content, e.Summary), firstNonEmpty(e.Published, e.Updated)))
+	}
+	return jobs, nil
+}
+
+func feedJob(source, title, link, body, date string) job.Job {
+	title = strings.TrimSpace(html.UnescapeString(title))
+	desc := strings.TrimSpace(html.UnescapeString(stripHTML(body)))
+	role, company := splitFeedTitle(title)
+	return job.Job{
+		ID:          idFromContent(source, link+title),
+		Title:       role,
+		Company:     company,
+		Location:    strings.Join(parse.ExtractLocations(desc), ", "),
+		Description: desc,
+		URL:         link,
+		Source:      source,
+		PostedDate:  parseFeedDate(date),
+		Email:       parse.ExtractFirstEmail(desc),
+		Salary:      parse.ExtractSalary(desc),
+		Score:       50,
+	}
+}
+
+func firstNonEmpty(s ...string) string {
+	for _, v := range s {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
 	}
 	return ""
 }
 
-// CommonRSSFeeds returns scrapers for well-known RSS job feeds.
-func CommonRSSFeeds() []job.Scraper {
-	feeds := []struct {
-		name string
-		url  string
-	}{
-		{"crypto-jobs", "https://crypto.jobs/feed"},
-		{"nodejs-jobs", "https://nodesk.co/remote-jobs/rss/"},
-		{"golang-cafe", "https://golang.cafe/Ede/rss.xml"},
-		{"rustjobs", "https://rustjobs.dev/feed.xml"},
-		{"functional-works", "https://functional.works-hub.com/feed"},
-		{"pythonjobs", "https://pythonjobs.dev/feed.xml"},
+// feedDateLayouts are the dates feeds write: RFC 822 in RSS, RFC 3339 in
+// Atom and dc:date, and the variants seen in the wild.
+var feedDateLayouts = []string{
+	time.RFC1123Z, time.RFC1123, time.RFC3339, time.RFC822Z, time.RFC822,
+	"Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST", "2006-01-02T15:04:05", "2006-01-02",
+}
+
+// parseFeedDate reads a feed date, or returns the zero time for the
+// enrichment stage to fill in.
+func parseFeedDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range feedDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
 	}
+	return time.Time{}
+}
 
-	var scrapers []job.Scraper
-	for _, f := range feeds {
-		scrapers = append(scrapers, RSS(f.name, f.url))
+var (
+	// feedTitleAt splits "Role at Company" and "Role @ Company".
+	feedTitleAt = regexp.MustCompile(`^(.+?)\s+(?:at|@)\s+(.+)$`)
+	// feedTitleDash splits "Company — Role", "Company - Role",
+	// "Company: Role" and "Company | Role".
+	feedTitleDash = regexp.MustCompile(`^(.+?)\s*(?:\s[—–-]\s|:\s|\s\|\s)\s*(.+)$`)
+	// notCompany matches a title side that says where or how, not who.
+	notCompany = regexp.MustCompile(`(?i)^(?:remote|hybrid|on-?site|worldwide|anywhere|full[- ]time|part[- ]time|contract)\b`)
+	roleWords  = regexp.MustCompile(`(?i)\b(engineer|developer|programmer|manager|lead|intern|architect|consultant|analyst|designer|scientist|director|head|administrator|specialist|sre|devops|tester|qa|writer|researcher)\b`)
+)
+
+// splitFeedTitle reads the role and company from a feed item's title, as
+// in "Role at Company" or "Company — Role". A side of a dash that names a
+// role is the role whichever side it is on, and one like "Remote" is no
+// company. Titles that fit neither form are all role, with no company.
+func splitFeedTitle(title string) (role, company string) {
+	if m := feedTitleAt.FindStringSubmatch(title); m != nil {
+		return strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
 	}
-	return scrapers
+	if m := feedTitleDash.FindStringSubmatch(title); m != nil {
+		left, right := strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+		switch {
+		case notCompany.MatchString(right):
+			return left, ""
+		case notCompany.MatchString(left):
+			return right, ""
+		}
+		if roleWords.MatchString(left) && !roleWords.MatchString(right) {
+			return left, right
+		}
+		return right, left
+	}
+	return title, ""
+}
+
+// feedValidators are the ETag and Last-Modified a feed was last served
+// with, sent back so an unchanged feed is not downloaded again.
+type feedValidators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+var (
+	// feedCachePath is where the validators are kept between runs; tests
+	// point it elsewhere.
+	feedCachePath = func() string { return filepath.Join(job.DataDir(), "feed-cache.json") }
+	feedCacheMu   sync.Mutex
+)
+
+func readFeedCache() map[string]feedValidators {
+	cache := make(map[string]feedValidators)
+	if data, err := os.ReadFile(feedCachePath()); err == nil {
+		json.Unmarshal(data, &cache)
+	}
+	return cache
+}
+
+func loadFeedValidators(feedURL string) feedValidators {
+	feedCacheMu.Lock()
+	defer feedCacheMu.Unlock()
+	return readFeedCache()[feedURL]
+}
+
+func saveFeedValidators(feedURL string, v feedValidators) error {
+	feedCacheMu.Lock()
+	defer feedCacheMu.Unlock()
+	cache := readFeedCache()
+	cache[feedURL] = v
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(feedCachePath()), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(feedCachePath(), data, 0644)
+}
+
+// fetchFeed fetches a feed as httpGet does, sending the validators of the
+// last fetch. It returns a nil body when the feed has not changed since.
+func fetchFeed(ctx context.Context, feedURL string) ([]byte, error) {
+	u, err := url.Parse(feedURL)
+	if err != nil {
+		return nil, err
+	}
+	last := loadFeedValidators(feedURL)
+	var body []byte
+	var next feedValidators
+	err = DefaultRetry.Do(ctx, func(ctx context.Context) error {
+		if err := hosts.wait(ctx, u.Host); err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+		if err != nil {
+			return err
+		}
+		if last.ETag != "" {
+			req.Header.Set("If-None-Match", last.ETag)
+		}
+		if last.LastModified != "" {
+			req.Header.Set("If-Modified-Since", last.LastModified)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusNotModified:
+			body = nil
+			return nil
+		case http.StatusOK:
+			next = feedValidators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+			body, err = io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+			return err
+		}
+		wait, _ := httpapi.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		return &statusError{Code: resp.StatusCode, URL: feedURL, RetryAfter: wait}
+	})
+	if err != nil || body == nil {
+		return nil, err
+	}
+	if next != (feedValidators{}) {
+		// A cache that cannot be written only costs a full fetch next time.
+		saveFeedValidators(feedURL, next)
+	}
+	return body, nil
 }
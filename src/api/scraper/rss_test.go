@@ -0,0 +1,118 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"sprayer/src/api/profile"
+)
+
+const testRSS = `<?xml version="1.0"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel><title>Jobs</title>
+<item>
+  <title>Senior Go Engineer at Acme &amp; Co</title>
+  <link>https://example.com/jobs/1</link>
+  <description>&lt;p&gt;Remote, full time.&lt;/p&gt;</description>
+  <pubDate>Mon, 02 Jan 2006 15:04:05 -0700</pubDate>
+</item>
+</channel></rss>`
+
+const testAtom = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom"><title>Jobs</title>
+<entry>
+  <title>Globex — Backend Developer</title>
+  <link rel="alternate" href="https://example.com/jobs/2"/>
+  <summary>Build services.</summary>
+  <updated>2024-03-01T10:00:00Z</updated>
+</entry>
+</feed>`
+
+func TestParseFeed_RSSAndAtom(t *testing.T) {
+	jobs, err := parseFeed([]byte(testRSS), "feed")
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("rss: %+v, %v", jobs, err)
+	}
+	j := jobs[0]
+	if j.Title != "Senior Go Engineer" || j.Company != "Acme & Co" || j.URL != "https://example.com/jobs/1" || j.PostedDate.Year() != 2006 {
+		t.Errorf("rss job = %+v", j)
+	}
+
+	jobs, err = parseFeed([]byte(testAtom), "feed")
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("atom: %+v, %v", jobs, err)
+	}
+	j = jobs[0]
+	if j.Title != "Backend Developer" || j.Company != "Globex" || j.URL != "https://example.com/jobs/2" || j.PostedDate.Year() != 2024 {
+		t.Errorf("atom job = %+v", j)
+	}
+}
+
+func TestSplitFeedTitle(t *testing.T) {
+	for title, want := range map[string][2]string{
+		"Go Developer @ Initech":           {"Go Developer", "Initech"},
+		"Acme - Staff Engineer":            {"Staff Engineer", "Acme"},
+		"Site Reliability Engineer | Umbr": {"Site Reliability Engineer", "Umbr"},
+		"Platform Engineer - Remote":       {"Platform Engineer", ""},
+		"Rust wizard wanted":               {"Rust wizard wanted", ""},
+	} {
+		if role, company := splitFeedTitle(title); role != want[0] || company != want[1] {
+			t.Errorf("splitFeedTitle(%q) = %q, %q; want %q, %q", title, role, company, want[0], want[1])
+		}
+	}
+}
+
+func TestScrapeFeeds_CachesAndReportsPerFeed(t *testing.T) {
+	dir := t.TempDir()
+	old := feedCachePath
+	feedCachePath = func() string { return filepath.Join(dir, "feed-cache.json") }
+	t.Cleanup(func() { feedCachePath = old })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rss":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(testRSS))
+		case "/broken":
+			w.Write([]byte("<rss><channel><item>"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	feeds := []Feed{{"good", srv.URL + "/rss"}, {"broken", srv.URL + "/broken"}, {"gone", srv.URL + "/gone"}}
+	jobs, err := scrapeFeeds(context.Background(), feeds)
+	if len(jobs) != 1 || jobs[0].Source != "good" {
+		t.Fatalf("jobs = %+v", jobs)
+	}
+	failed, ok := BoardFailures(err)
+	if !ok || len(failed) != 2 {
+		t.Fatalf("err = %v, want two feed failures", err)
+	}
+	var fe *FeedError
+	if !errors.As(failed[1], &fe) || fe.URL != srv.URL+"/gone" {
+		t.Errorf("failure = %v", failed[1])
+	}
+
+	jobs, err = scrapeFeeds(context.Background(), feeds[:1])
+	if err != nil || len(jobs) != 0 {
+		t.Errorf("unchanged feed: %+v, %v; want no jobs", jobs, err)
+	}
+}
+
+func TestDefaultFeeds_AreKnownSources(t *testing.T) {
+	for _, f := range DefaultFeeds {
+		if !profile.IsKnownSource(f.Name) {
+			t.Errorf("default feed %s is missing from profile.KnownSources", f.Name)
+		}
+	}
+}
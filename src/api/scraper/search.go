@@ -0,0 +1,53 @@
+package scraper
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Sources that can search push a profile's keywords and location
+// upstream, so a niche keyword set downloads a fraction of each board
+// rather than all of it:
+//
+//	hn              Algolia full-text query within the hiring thread, per keyword
+//	remoteok        ?tag= per keyword
+//	weworkremotely  only the categories the keywords fall in
+//	arbeitnow       ?search= per keyword, and remote=true for a remote location
+//	jobicy          ?tag= per keyword, and ?geo= for a region it knows
+//	linkedin, indeed, glassdoor  their own search pages
+//
+// The rest cannot: a Greenhouse or Ashby board lists every opening in one
+// response and a feed is what its publisher writes, so the keyword filter
+// after fetching is all they get.
+
+// notSearchTerm matches keywords that describe where or how the work is
+// done, which boards do not tag and would match every posting.
+var notSearchTerm = regexp.MustCompile(`(?i)^(?:remote|hybrid|on-?site|worldwide|anywhere|global|full[- ]?time|part[- ]?time|contract|freelance)$`)
+
+// searchTerms are the keywords worth searching a board for, lowercased
+// and without duplicates. None means the board is fetched whole.
+func searchTerms(keywords []string) []string {
+	var terms []string
+	for _, kw := range keywords {
+		kw = strings.ToLower(strings.TrimSpace(kw))
+		if kw == "" || notSearchTerm.MatchString(kw) || slices.Contains(terms, kw) {
+			continue
+		}
+		terms = append(terms, kw)
+	}
+	return terms
+}
+
+// tagSlug writes a term as boards write their tags: "machine learning"
+// becomes "machine-learning".
+func tagSlug(term string) string {
+	return strings.Join(strings.Fields(term), "-")
+}
+
+// remoteLocation reports a location that asks only for remote work.
+func remoteLocation(location string) bool {
+	return remoteOnly.MatchString(strings.TrimSpace(location))
+}
+
+var remoteOnly = regexp.MustCompile(`(?i)^(?:remote|anywhere|worldwide|global)$`)
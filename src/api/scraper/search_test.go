@@ -0,0 +1,94 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	got := searchTerms([]string{"Golang", " rust ", "remote", "golang", "Full-time", "machine learning"})
+	if strings.Join(got, "|") != "golang|rust|machine learning" {
+		t.Errorf("searchTerms = %q", got)
+	}
+	if searchTerms([]string{"remote"}) != nil {
+		t.Error("only work-mode keywords should leave nothing to search for")
+	}
+	if tagSlug("machine  learning") != "machine-learning" {
+		t.Errorf("tagSlug = %q", tagSlug("machine  learning"))
+	}
+}
+
+func TestWWRCategoriesFor(t *testing.T) {
+	for _, tc := range []struct {
+		terms []string
+		want  string
+	}{
+		{nil, "programming,devops-sysadmin,design"},
+		{[]string{"golang", "rust"}, "programming"},
+		{[]string{"kubernetes", "figma"}, "devops-sysadmin,design"},
+	} {
+		if got := strings.Join(wwrCategoriesFor(tc.terms), ","); got != tc.want {
+			t.Errorf("wwrCategoriesFor(%v) = %s, want %s", tc.terms, got, tc.want)
+		}
+	}
+}
+
+// TestSources_SearchUpstream checks each searching source asks its API
+// for the keywords rather than for everything.
+func TestSources_SearchUpstream(t *testing.T) {
+	var mu sync.Mutex
+	var asked []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		asked = append(asked, r.URL.RequestURI())
+		mu.Unlock()
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/remoteok"):
+			tag := r.URL.Query().Get("tag")
+			w.Write([]byte(`[{"legal":"meta"},{"id":"1","position":"Engineer","tags":["` + tag + `"]},{"id":"shared","position":"Polyglot"}]`))
+		case strings.HasPrefix(r.URL.Path, "/jobicy"):
+			w.Write([]byte(`{"jobs":[{"id":1,"jobTitle":"Engineer"}]}`))
+		case strings.HasPrefix(r.URL.Path, "/arbeitnow"):
+			w.Write([]byte(`{"data":[{"slug":"a-` + r.URL.Query().Get("search") + `","title":"Engineer"}],"links":{}}`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer srv.Close()
+	for _, base := range []*string{&remoteOKAPI, &jobicyAPI, &arbeitnowAPI, &wwrBase} {
+		old := *base
+		t.Cleanup(func() { *base = old })
+	}
+	remoteOKAPI, jobicyAPI, arbeitnowAPI, wwrBase = srv.URL+"/remoteok", srv.URL+"/jobicy", srv.URL+"/arbeitnow", srv.URL+"/wwr"
+
+	ctx, terms := context.Background(), searchTerms([]string{"golang", "rust", "remote"})
+	if jobs, err := remoteOK(ctx, terms)(); err != nil || len(jobs) != 2 {
+		t.Errorf("remoteok: %d jobs, %v; want the shared posting once", len(jobs), err)
+	}
+	if jobs, err := jobicy(ctx, terms, "Europe")(); err != nil || len(jobs) != 1 {
+		t.Errorf("jobicy: %d jobs, %v", len(jobs), err)
+	}
+	if jobs, err := arbeitnow(ctx, terms, remoteLocation("Remote"))(); err != nil || len(jobs) != 2 {
+		t.Errorf("arbeitnow: %d jobs, %v", len(jobs), err)
+	}
+	weWorkRemotely(ctx, terms)()
+
+	for _, want := range []string{
+		"/remoteok?tag=golang", "/remoteok?tag=rust",
+		"/jobicy?count=50&geo=europe&industry=tech&tag=golang", "/jobicy?count=50&geo=europe&industry=tech&tag=rust",
+		"/arbeitnow?page=1&remote=true&search=golang", "/arbeitnow?page=1&remote=true&search=rust",
+		"/wwr/categories/programming/jobs.json",
+	} {
+		if !slices.Contains(asked, want) {
+			t.Errorf("never asked for %s; asked %v", want, asked)
+		}
+	}
+	if len(asked) != 7 {
+		t.Errorf("asked %d times, want 7: %v", len(asked), asked)
+	}
+}
@@ -1,8 +1,14 @@
 package scraper
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,16 +18,58 @@ import (
 
 // WeWorkRemotely scrapes the WWR JSON feed.
 func WeWorkRemotely() job.Scraper {
+	return weWorkRemotely(context.Background(), nil)
+}
+
+// wwrBase is the We Work Remotely site; tests point it elsewhere.
+var wwrBase = "https://weworkremotely.com"
+
+// wwrCategories are the WWR categories scraped, each with the keywords
+// that fall in it. Keywords that fall in none are programming's.
+var wwrCategories = []struct {
+	slug  string
+	terms *regexp.Regexp
+}{
+	{"programming", nil},
+	{"devops-sysadmin", regexp.MustCompile(`(?i)devops|\bsre\b|sysadmin|kubernetes|\bk8s\b|terraform|infrastructure|cloud|linux`)},
+	{"design", regexp.MustCompile(`(?i)design|\bux\b|\bui\b|figma`)},
+}
+
+// wwrCategoriesFor is the categories terms fall in, or all of them when
+// there are no terms. WWR has no search, so a category is the closest it
+// filters.
+func wwrCategoriesFor(terms []string) []string {
+	var cats []string
+	if len(terms) == 0 {
+		for _, c := range wwrCategories {
+			cats = append(cats, c.slug)
+		}
+	}
+	for _, t := range terms {
+		cat := wwrCategories[0].slug
+		for _, c := range wwrCategories[1:] {
+			if c.terms.MatchString(t) {
+				cat = c.slug
+				break
+			}
+		}
+		if !slices.Contains(cats, cat) {
+			cats = append(cats, cat)
+		}
+	}
+	return cats
+}
+
+// weWorkRemotely fetches the categories terms fall in.
+func weWorkRemotely(ctx context.Context, terms []string) job.Scraper {
 	return func() ([]job.Job, error) {
 		// WWR exposes category-based JSON feeds
-		categories := []string{
-			"programming", "devops-sysadmin", "design",
-		}
+		categories := wwrCategoriesFor(terms)
 
 		var all []job.Job
 		for _, cat := range categories {
-			url := fmt.Sprintf("https://weworkremotely.com/categories/%s/jobs.json", cat)
-			data, err := httpGet(url)
+			url := fmt.Sprintf("%s/categories/%s/jobs.json", wwrBase, cat)
+			data, err := httpGet(ctx, url)
 			if err != nil {
 				continue
 			}
@@ -58,7 +106,6 @@ func WeWorkRemotely() job.Scraper {
 				}
 				all = append(all, j)
 			}
-			time.Sleep(200 * time.Millisecond)
 		}
 		return all, nil
 	}
@@ -66,118 +113,206 @@ func WeWorkRemotely() job.Scraper {
 
 // Arbeitnow scrapes the Arbeitnow public JSON API (EU-focused remote jobs).
 func Arbeitnow() job.Scraper {
-	return func() ([]job.Job, error) {
-		var all []job.Job
-		page := 1
-		for page <= 3 {
-			url := fmt.Sprintf("https://www.arbeitnow.com/api/job-board-api?page=%d", page)
-			data, err := httpGet(url)
-			if err != nil {
-				break
-			}
-
-			var result struct {
-				Data []struct {
-					Slug        string   `json:"slug"`
-					Title       string   `json:"title"`
-					CompanyName string   `json:"company_name"`
-					Description string   `json:"description"`
-					Location    string   `json:"location"`
-					Remote      bool     `json:"remote"`
-					URL         string   `json:"url"`
-					Tags        []string `json:"tags"`
-					CreatedAt   int64    `json:"created_at"`
-				} `json:"data"`
-				Links struct {
-					Next string `json:"next"`
-				} `json:"links"`
-			}
-			if err := json.Unmarshal(data, &result); err != nil {
-				break
-			}
+	return arbeitnow(context.Background(), nil, false)
+}
 
-			for _, d := range result.Data {
-				loc := d.Location
-				if d.Remote {
-					loc = "Remote / " + loc
-				}
-				desc := stripHTML(d.Description)
-				j := job.Job{
-					ID:          fmt.Sprintf("an-%s", d.Slug),
-					Title:       d.Title,
-					Company:     d.CompanyName,
-					Location:    loc,
-					Description: desc,
-					URL:         d.URL,
-					Source:      "arbeitnow",
-					PostedDate:  time.Unix(d.CreatedAt, 0),
-					Email:       parse.ExtractFirstEmail(desc),
-					Salary:      parse.ExtractSalary(desc),
-					JobType:     strings.Join(d.Tags, ", "),
-					Score:       50,
-				}
-				all = append(all, j)
-			}
+// arbeitnowAPI is the Arbeitnow job board API; tests point it elsewhere.
+var arbeitnowAPI = "https://www.arbeitnow.com/api/job-board-api"
 
-			if result.Links.Next == "" {
-				break
-			}
-			page++
-			time.Sleep(300 * time.Millisecond)
+// arbeitnow fetches up to three pages of the postings matching each of
+// terms, or of all postings when there are none, only remote ones when
+// remote is set.
+func arbeitnow(ctx context.Context, terms []string, remote bool) job.Scraper {
+	return func() ([]job.Job, error) {
+		queries := []string{""}
+		if len(terms) > 0 {
+			queries = terms
+		}
+		var all []job.Job
+		got := make(map[string]bool)
+		for _, query := range queries {
+			all = arbeitnowPages(ctx, query, remote, got, all)
 		}
 		return all, nil
 	}
 }
 
-// Jobicy scrapes the Jobicy public API (remote tech jobs).
-func Jobicy() job.Scraper {
-	return func() ([]job.Job, error) {
-		data, err := httpGet("https://jobicy.com/api/v2/remote-jobs?count=50&industry=tech")
+// arbeitnowPages appends the postings matching query, skipping those
+// already got.
+func arbeitnowPages(ctx context.Context, query string, remote bool, got map[string]bool, all []job.Job) []job.Job {
+	page := 1
+	for page <= 3 {
+		params := url.Values{"page": {fmt.Sprint(page)}}
+		if query != "" {
+			params.Set("search", query)
+		}
+		if remote {
+			params.Set("remote", "true")
+		}
+		data, err := httpGet(ctx, arbeitnowAPI+"?"+params.Encode())
 		if err != nil {
-			return nil, fmt.Errorf("Jobicy API: %w", err)
+			break
 		}
 
 		var result struct {
-			Jobs []struct {
-				ID          int    `json:"id"`
-				URL         string `json:"url"`
-				JobTitle    string `json:"jobTitle"`
-				CompanyName string `json:"companyName"`
-				JobGeo      string `json:"jobGeo"`
-				JobType     string `json:"jobType"`
-				AnnSalaryMin string `json:"annualSalaryMin"`
-				AnnSalaryMax string `json:"annualSalaryMax"`
-				SalaryCurrency string `json:"salaryCurrency"`
-				PubDate     string `json:"pubDate"`
-				JobExcerpt  string `json:"jobExcerpt"`
-			} `json:"jobs"`
+			Data []struct {
+				Slug        string   `json:"slug"`
+				Title       string   `json:"title"`
+				CompanyName string   `json:"company_name"`
+				Description string   `json:"description"`
+				Location    string   `json:"location"`
+				Remote      bool     `json:"remote"`
+				URL         string   `json:"url"`
+				Tags        []string `json:"tags"`
+				CreatedAt   int64    `json:"created_at"`
+			} `json:"data"`
+			Links struct {
+				Next string `json:"next"`
+			} `json:"links"`
 		}
 		if err := json.Unmarshal(data, &result); err != nil {
-			return nil, fmt.Errorf("Jobicy parse: %w", err)
+			break
 		}
 
-		var jobs []job.Job
-		for _, jj := range result.Jobs {
-			salary := ""
-			if jj.AnnSalaryMin != "" && jj.AnnSalaryMax != "" {
-				salary = fmt.Sprintf("%s %s - %s", jj.SalaryCurrency, jj.AnnSalaryMin, jj.AnnSalaryMax)
+		for _, d := range result.Data {
+			if got[d.Slug] {
+				continue
 			}
-			posted, _ := time.Parse("2006-01-02 15:04:05", jj.PubDate)
+			got[d.Slug] = true
+			loc := d.Location
+			if d.Remote {
+				loc = "Remote / " + loc
+			}
+			desc := stripHTML(d.Description)
 			j := job.Job{
-				ID:          fmt.Sprintf("jcy-%d", jj.ID),
-				Title:       jj.JobTitle,
-				Company:     jj.CompanyName,
-				Location:    jj.JobGeo,
-				Description: jj.JobExcerpt,
-				URL:         jj.URL,
-				Source:      "jobicy",
-				PostedDate:  posted,
-				Salary:      salary,
-				JobType:     jj.JobType,
+				ID:          fmt.Sprintf("an-%s", d.Slug),
+				Title:       d.Title,
+				Company:     d.CompanyName,
+				Location:    loc,
+				Description: desc,
+				URL:         d.URL,
+				Source:      "arbeitnow",
+				PostedDate:  time.Unix(d.CreatedAt, 0),
+				Email:       parse.ExtractFirstEmail(desc),
+				Salary:      parse.ExtractSalary(desc),
+				JobType:     strings.Join(d.Tags, ", "),
 				Score:       50,
 			}
-			jobs = append(jobs, j)
+			all = append(all, j)
+		}
+
+		if result.Links.Next == "" {
+			break
+		}
+		page++
+	}
+	return all
+}
+
+// Jobicy scrapes the Jobicy public API (remote tech jobs).
+func Jobicy() job.Scraper {
+	return jobicy(context.Background(), nil, "")
+}
+
+// jobicyAPI is the Jobicy remote jobs API; tests point it elsewhere.
+var jobicyAPI = "https://jobicy.com/api/v2/remote-jobs"
+
+// jobicyGeos maps locations to the regions Jobicy filters by.
+var jobicyGeos = map[string]string{
+	"us": "usa", "usa": "usa", "united states": "usa",
+	"canada": "canada", "uk": "uk", "united kingdom": "uk",
+	"europe": "europe", "eu": "europe", "emea": "emea",
+	"latam": "latam", "apac": "apac", "germany": "germany",
+}
+
+// jobicy fetches the postings tagged with any of terms, one request per
+// tag, or the latest postings when there are none, in location's region
+// when Jobicy knows it. A tag that fails leaves the others' postings; it
+// errors only when every request did.
+func jobicy(ctx context.Context, terms []string, location string) job.Scraper {
+	return func() ([]job.Job, error) {
+		params := url.Values{"count": {"50"}, "industry": {"tech"}}
+		if geo, ok := jobicyGeos[strings.ToLower(strings.TrimSpace(location))]; ok {
+			params.Set("geo", geo)
+		}
+		queries := []url.Values{params}
+		if len(terms) > 0 {
+			queries = nil
+			for _, t := range terms {
+				q := maps.Clone(params)
+				q.Set("tag", t)
+				queries = append(queries, q)
+			}
+		}
+		var jobs []job.Job
+		var errs []error
+		got := make(map[string]bool)
+		for _, q := range queries {
+			page, err := jobicyPage(ctx, jobicyAPI+"?"+q.Encode())
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			for _, j := range page {
+				if !got[j.ID] {
+					got[j.ID] = true
+					jobs = append(jobs, j)
+				}
+			}
+		}
+		if len(errs) == len(queries) {
+			return nil, errors.Join(errs...)
 		}
 		return jobs, nil
 	}
 }
+
+func jobicyPage(ctx context.Context, apiURL string) ([]job.Job, error) {
+	data, err := httpGet(ctx, apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("Jobicy API: %w", err)
+	}
+
+	var result struct {
+		Jobs []struct {
+			ID             int    `json:"id"`
+			URL            string `json:"url"`
+			JobTitle       string `json:"jobTitle"`
+			CompanyName    string `json:"companyName"`
+			JobGeo         string `json:"jobGeo"`
+			JobType        string `json:"jobType"`
+			AnnSalaryMin   string `json:"annualSalaryMin"`
+			AnnSalaryMax   string `json:"annualSalaryMax"`
+			SalaryCurrency string `json:"salaryCurrency"`
+			PubDate        string `json:"pubDate"`
+			JobExcerpt     string `json:"jobExcerpt"`
+		} `json:"jobs"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("Jobicy parse: %w", err)
+	}
+
+	var jobs []job.Job
+	for _, jj := range result.Jobs {
+		salary := ""
+		if jj.AnnSalaryMin != "" && jj.AnnSalaryMax != "" {
+			salary = fmt.Sprintf("%s %s - %s", jj.SalaryCurrency, jj.AnnSalaryMin, jj.AnnSalaryMax)
+		}
+		posted, _ := time.Parse("2006-01-02 15:04:05", jj.PubDate)
+		j := job.Job{
+			ID:          fmt.Sprintf("jcy-%d", jj.ID),
+			Title:       jj.JobTitle,
+			Company:     jj.CompanyName,
+			Location:    jj.JobGeo,
+			Description: jj.JobExcerpt,
+			URL:         jj.URL,
+			Source:      "jobicy",
+			PostedDate:  posted,
+			Salary:      salary,
+			JobType:     jj.JobType,
+			Score:       50,
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,153 @@
+// Package scraperun records each scrape: when it ran, for which profile,
+// how many sources it tried, how many postings they returned, what it
+// found and which sources failed.
+package scraperun
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// Run is one scrape. FinishedAt is nil while it runs, and stays nil for
+// a run the process died in.
+type Run struct {
+	ID           int64      `json:"id"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+	ProfileID    string     `json:"profile_id"`
+	SourcesCount int        `json:"sources_count"`
+	JobsFound    int        `json:"jobs_found"`
+	Errors       []string   `json:"errors,omitempty"` // one per failed source
+
+	// Fetched is how many postings the sources returned, before the
+	// profile filtered them; zero for runs that did not record it.
+	Fetched int `json:"fetched,omitempty"`
+}
+
+// Duration is how long a finished run took, or zero.
+func (r Run) Duration() time.Duration {
+	if r.FinishedAt == nil {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// Store persists runs next to the jobs they found.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore wraps a database connection for run storage.
+func NewStore(db *sql.DB) (*Store, error) {
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS scrape_runs (
+			id            INTEGER PRIMARY KEY AUTOINCREMENT,
+			started_at    DATETIME NOT NULL,
+			finished_at   DATETIME DEFAULT NULL,
+			profile_id    TEXT NOT NULL DEFAULT '',
+			sources_count INTEGER DEFAULT 0,
+			jobs_found    INTEGER DEFAULT 0,
+			errors        TEXT DEFAULT '[]'
+		)`)
+	if err != nil {
+		return err
+	}
+	return job.EnsureColumns(db, "scrape_runs", []job.Column{{Name: "fetched", Decl: "INTEGER DEFAULT 0"}})
+}
+
+// Start records a run beginning now and returns its ID for Finish.
+func (s *Store) Start(profileID string, sources int, now time.Time) (int64, error) {
+	res, err := s.db.Exec(`INSERT INTO scrape_runs (started_at, profile_id, sources_count) VALUES (?, ?, ?)`,
+		now.UTC(), profileID, sources)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// Finish records what run id found.
+func (s *Store) Finish(id int64, jobsFound int, errs []string, now time.Time) error {
+	data, err := json.Marshal(errs)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`UPDATE scrape_runs SET finished_at = ?, jobs_found = ?, errors = ? WHERE id = ?`,
+		now.UTC(), jobsFound, string(data), id)
+	return err
+}
+
+// RecordFetched records how many postings run id's sources returned.
+func (s *Store) RecordFetched(id int64, fetched int) error {
+	_, err := s.db.Exec(`UPDATE scrape_runs SET fetched = ? WHERE id = ?`, fetched, id)
+	return err
+}
+
+// PreviousFetched returns how many postings the last run of profileID
+// before run id fetched, and false when none recorded it.
+func (s *Store) PreviousFetched(profileID string, id int64) (int, bool, error) {
+	var n int
+	err := s.db.QueryRow(`SELECT fetched FROM scrape_runs
+		WHERE profile_id = ? AND id < ? AND fetched > 0 ORDER BY id DESC LIMIT 1`, profileID, id).Scan(&n)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	return n, err == nil, err
+}
+
+const columns = `id, started_at, finished_at, profile_id, sources_count, jobs_found, errors, fetched`
+
+func scanRun(sc interface{ Scan(...any) error }) (Run, error) {
+	var r Run
+	var finished sql.NullTime
+	var errs string
+	if err := sc.Scan(&r.ID, &r.StartedAt, &finished, &r.ProfileID, &r.SourcesCount, &r.JobsFound, &errs, &r.Fetched); err != nil {
+		return r, err
+	}
+	if finished.Valid {
+		r.FinishedAt = &finished.Time
+	}
+	json.Unmarshal([]byte(errs), &r.Errors)
+	return r, nil
+}
+
+// Recent returns up to limit runs, newest first.
+func (s *Store) Recent(limit int) ([]Run, error) {
+	rows, err := s.db.Query(`SELECT `+columns+` FROM scrape_runs ORDER BY started_at DESC, id DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Run
+	for rows.Next() {
+		r, err := scanRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// LastFinished returns the run that finished most recently, or nil
+// before the first one.
+func (s *Store) LastFinished() (*Run, error) {
+	r, err := scanRun(s.db.QueryRow(`SELECT ` + columns + ` FROM scrape_runs
+		WHERE finished_at IS NOT NULL ORDER BY finished_at DESC, id DESC LIMIT 1`))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
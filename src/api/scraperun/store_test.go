@@ -0,0 +1,89 @@
+package scraperun
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	js, err := job.OpenStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { js.Close() })
+	s, err := NewStore(js.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestStore_Runs(t *testing.T) {
+	s := openTestStore(t)
+	if last, err := s.LastFinished(); last != nil || err != nil {
+		t.Fatalf("LastFinished on an empty store = %v, %v", last, err)
+	}
+
+	t0 := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	first, err := s.Start("default", 10, t0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Finish(first, 212, []string{"error scraping LinkedIn: launch browser"}, t0.Add(90*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	second, _ := s.Start("alice", 3, t0.Add(2*time.Hour))
+	s.Finish(second, 7, nil, t0.Add(2*time.Hour+time.Minute))
+	s.Start("alice", 3, t0.Add(3*time.Hour)) // still running
+
+	runs, err := s.Recent(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 3 || runs[0].FinishedAt != nil || runs[1].ID != second || runs[2].ID != first {
+		t.Fatalf("Recent = %+v", runs)
+	}
+	r := runs[2]
+	if r.ProfileID != "default" || r.SourcesCount != 10 || r.JobsFound != 212 || r.Duration() != 90*time.Second ||
+		!reflect.DeepEqual(r.Errors, []string{"error scraping LinkedIn: launch browser"}) {
+		t.Errorf("first run = %+v", r)
+	}
+	if runs[1].Errors != nil {
+		t.Errorf("run without errors has %v", runs[1].Errors)
+	}
+	if got, _ := s.Recent(1); len(got) != 1 {
+		t.Errorf("Recent(1) returned %d runs", len(got))
+	}
+
+	last, err := s.LastFinished()
+	if err != nil || last == nil || last.ID != second {
+		t.Errorf("LastFinished = %+v, %v; want run %d", last, err, second)
+	}
+}
+
+func TestStore_Fetched(t *testing.T) {
+	s := openTestStore(t)
+	t0 := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+	first, _ := s.Start("default", 7, t0)
+	other, _ := s.Start("alice", 7, t0.Add(time.Minute))
+	third, _ := s.Start("default", 7, t0.Add(time.Hour))
+	if _, ok, err := s.PreviousFetched("default", first); ok || err != nil {
+		t.Fatalf("PreviousFetched before any = %v, %v", ok, err)
+	}
+	s.RecordFetched(first, 1204)
+	s.RecordFetched(other, 30)
+	s.RecordFetched(third, 84)
+
+	if n, ok, err := s.PreviousFetched("default", third); n != 1204 || !ok || err != nil {
+		t.Errorf("PreviousFetched = %d, %v, %v; want the profile's last run, 1204", n, ok, err)
+	}
+	runs, _ := s.Recent(1)
+	if runs[0].Fetched != 84 {
+		t.Errorf("Fetched = %d", runs[0].Fetched)
+	}
+}
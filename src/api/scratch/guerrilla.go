@@ -0,0 +1,145 @@
+package scratch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GuerrillaBaseURL is the Guerrilla Mail API root.
+const GuerrillaBaseURL = "https://api.guerrillamail.com"
+
+// Guerrilla makes addresses on Guerrilla Mail, which needs no account:
+// an address is a session. Its sid_token lapses after a few idle minutes,
+// so each use binds a session to the address's stored user name again,
+// keeping whichever token that returns in ProviderData.
+type Guerrilla struct {
+	BaseURL string
+}
+
+// NewGuerrilla returns a provider for the public Guerrilla Mail API.
+func NewGuerrilla() *Guerrilla {
+	return &Guerrilla{BaseURL: GuerrillaBaseURL}
+}
+
+func (*Guerrilla) Name() string { return "guerrilla" }
+
+// ProviderData keys used by Guerrilla.
+const (
+	guerrillaUser = "email_user"
+	guerrillaSID  = "sid_token"
+)
+
+// guerrillaAddress is the reply of get_email_address and set_email_user.
+type guerrillaAddress struct {
+	Addr string `json:"email_addr"`
+	SID  string `json:"sid_token"`
+}
+
+// guerrillaMail is a message as check_email lists it and fetch_email
+// returns it, the latter with its body.
+type guerrillaMail struct {
+	ID        json.Number `json:"mail_id"`
+	From      string      `json:"mail_from"`
+	Subject   string      `json:"mail_subject"`
+	Excerpt   string      `json:"mail_excerpt"`
+	Body      string      `json:"mail_body"`
+	Timestamp json.Number `json:"mail_timestamp"`
+	Read      json.Number `json:"mail_read"`
+}
+
+// Create starts a session, whose random address becomes the scratch
+// address.
+func (p *Guerrilla) Create(ctx context.Context) (*ScratchEmail, error) {
+	var a guerrillaAddress
+	if err := p.call(ctx, "get_email_address", url.Values{"lang": {"en"}}, &a); err != nil {
+		return nil, err
+	}
+	user, _, ok := strings.Cut(a.Addr, "@")
+	if !ok || a.SID == "" {
+		return nil, fmt.Errorf("unexpected address %q", a.Addr)
+	}
+	return &ScratchEmail{
+		Address:      a.Addr,
+		ProviderData: map[string]string{guerrillaUser: user, guerrillaSID: a.SID},
+	}, nil
+}
+
+// CheckInbox lists the messages at e and fetches each one's body, kept
+// as text and HTML.
+func (p *Guerrilla) CheckInbox(ctx context.Context, e *ScratchEmail) ([]Email, error) {
+	if err := p.resume(ctx, e); err != nil {
+		return nil, err
+	}
+	var list struct {
+		List []guerrillaMail `json:"list"`
+	}
+	sid := e.ProviderData[guerrillaSID]
+	if err := p.call(ctx, "check_email", url.Values{"seq": {"0"}, guerrillaSID: {sid}}, &list); err != nil {
+		return nil, err
+	}
+	emails := make([]Email, 0, len(list.List))
+	for _, m := range list.List {
+		full := m
+		if err := p.call(ctx, "fetch_email", url.Values{"email_id": {m.ID.String()}, guerrillaSID: {sid}}, &full); err != nil {
+			return emails, fmt.Errorf("read message %s: %w", m.ID, err)
+		}
+		em := Email{
+			ID: m.ID.String(), From: full.From, To: e.Address, Subject: full.Subject,
+			HTML: full.Body, Text: textFromHTML(full.Body), Read: m.Read.String() == "1",
+		}
+		if em.Text == "" {
+			em.Text = m.Excerpt
+		}
+		if ts, err := full.Timestamp.Int64(); err == nil {
+			em.Date = time.Unix(ts, 0)
+		}
+		emails = append(emails, em)
+	}
+	return emails, nil
+}
+
+// Deactivate asks Guerrilla Mail to forget the address.
+func (p *Guerrilla) Deactivate(ctx context.Context, e *ScratchEmail) error {
+	if err := p.resume(ctx, e); err != nil {
+		return err
+	}
+	var forgotten bool
+	return p.call(ctx, "forget_me", url.Values{"email_addr": {e.Address}, guerrillaSID: {e.ProviderData[guerrillaSID]}}, &forgotten)
+}
+
+// resume binds a session to e's user name, reusing the stored token when
+// it is still good.
+func (p *Guerrilla) resume(ctx context.Context, e *ScratchEmail) error {
+	user := e.ProviderData[guerrillaUser]
+	if user == "" {
+		return fmt.Errorf("resume %s: no stored user name", e.Address)
+	}
+	var a guerrillaAddress
+	params := url.Values{"email_user": {user}, "lang": {"en"}, guerrillaSID: {e.ProviderData[guerrillaSID]}}
+	if err := p.call(ctx, "set_email_user", params, &a); err != nil {
+		return fmt.Errorf("resume %s: %w", e.Address, err)
+	}
+	if got, _, _ := strings.Cut(a.Addr, "@"); got != user {
+		return fmt.Errorf("resume %s: got %q instead", e.Address, a.Addr)
+	}
+	if a.SID != "" {
+		e.ProviderData[guerrillaSID] = a.SID
+	}
+	return nil
+}
+
+// call invokes one API function. Guerrilla Mail asks clients to name
+// themselves and the user's IP, which a local tool leaves as loopback.
+func (p *Guerrilla) call(ctx context.Context, f string, params url.Values, out any) error {
+	params.Set("f", f)
+	params.Set("ip", "127.0.0.1")
+	params.Set("agent", "sprayer")
+	if err := newClient(p.BaseURL).Get(ctx, "/ajax.php?"+params.Encode(), out); err != nil {
+		return fmt.Errorf("%s: %w", f, err)
+	}
+	return nil
+}
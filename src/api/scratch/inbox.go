@@ -0,0 +1,56 @@
+package scratch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sprayer/src/api/inbox"
+)
+
+// Inboxes returns an inbox provider per scratch provider, reading the
+// active addresses made with it. Each is named after its provider, so
+// SPRAYER_INBOX_MAILTM_INTERVAL and the like configure it.
+func (m *Manager) Inboxes() []inbox.Provider {
+	var out []inbox.Provider
+	for _, p := range m.providers {
+		out = append(out, managerInbox{m: m, provider: p.Name()})
+	}
+	return out
+}
+
+type managerInbox struct {
+	m        *Manager
+	provider string
+}
+
+func (i managerInbox) Name() string { return i.provider }
+
+// Fetch checks each address in turn; one that fails does not keep the
+// others from being read.
+func (i managerInbox) Fetch(ctx context.Context) ([]inbox.Message, error) {
+	es, err := i.m.activeWith(i.provider)
+	if err != nil {
+		return nil, err
+	}
+	var out []inbox.Message
+	var errs []error
+	for _, e := range es {
+		emails, err := i.m.CheckInbox(ctx, &e)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Address, err))
+		}
+		for _, em := range emails {
+			out = append(out, inbox.Message{
+				ProviderID: e.Address + "/" + em.ID,
+				MessageID:  em.MessageID,
+				From:       em.From,
+				To:         e.Address,
+				Subject:    em.Subject,
+				Body:       em.Text,
+				Date:       em.Date,
+			})
+		}
+	}
+	return out, errors.Join(errs...)
+}
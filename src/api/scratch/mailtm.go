@@ -0,0 +1,187 @@
+package scratch
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"sprayer/src/api/httpapi"
+)
+
+// MailTMBaseURL is the mail.tm API root.
+const MailTMBaseURL = "https://api.mail.tm"
+
+// MailTM creates accounts on mail.tm. Each address keeps its account
+// ID, password and bearer token in ProviderData; the token is replaced
+// by logging in again with the password whenever it is missing or the
+// API answers 401, so addresses outlive the process that created them.
+type MailTM struct {
+	BaseURL string
+}
+
+// NewMailTM returns a provider for the public mail.tm API.
+func NewMailTM() *MailTM {
+	return &MailTM{BaseURL: MailTMBaseURL}
+}
+
+func (*MailTM) Name() string { return "mailtm" }
+
+// ProviderData keys used by MailTM.
+const (
+	mailTMAccount  = "account_id"
+	mailTMPassword = "password"
+	mailTMToken    = "token"
+)
+
+// Create registers a random address on the first active domain and logs
+// in to it.
+func (p *MailTM) Create(ctx context.Context) (*ScratchEmail, error) {
+	c := newClient(p.BaseURL)
+	domains, err := httpapi.Collect[struct {
+		Domain   string `json:"domain"`
+		IsActive bool   `json:"isActive"`
+	}](ctx, c, "/domains")
+	if err != nil {
+		return nil, fmt.Errorf("list domains: %w", err)
+	}
+	domain := ""
+	for _, d := range domains {
+		if d.IsActive {
+			domain = d.Domain
+			break
+		}
+	}
+	if domain == "" {
+		return nil, errors.New("no active domain")
+	}
+
+	local, password := randomHex(6), randomHex(12)
+	e := &ScratchEmail{
+		Address:      "sprayer-" + local + "@" + domain,
+		ProviderData: map[string]string{mailTMPassword: password},
+	}
+	var account struct {
+		ID string `json:"id"`
+	}
+	if err := c.Post(ctx, "/accounts", map[string]string{"address": e.Address, "password": password}, &account); err != nil {
+		return nil, fmt.Errorf("create account: %w", err)
+	}
+	e.ProviderData[mailTMAccount] = account.ID
+	if err := p.login(ctx, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// CheckInbox returns every message at e with its text and HTML bodies.
+func (p *MailTM) CheckInbox(ctx context.Context, e *ScratchEmail) ([]Email, error) {
+	c, err := p.client(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+	type address struct {
+		Address string `json:"address"`
+		Name    string `json:"name"`
+	}
+	type message struct {
+		ID        string    `json:"id"`
+		From      address   `json:"from"`
+		To        []address `json:"to"`
+		Subject   string    `json:"subject"`
+		Intro     string    `json:"intro"`
+		Seen      bool      `json:"seen"`
+		CreatedAt time.Time `json:"createdAt"`
+		Text      string    `json:"text"`
+		HTML      []string  `json:"html"`
+	}
+	list, err := httpapi.Collect[message](ctx, c, "/messages")
+	if err != nil {
+		return nil, fmt.Errorf("list messages: %w", err)
+	}
+	emails := make([]Email, 0, len(list))
+	for _, m := range list {
+		full := m
+		if err := c.Get(ctx, "/messages/"+url.PathEscape(m.ID), &full); err != nil {
+			return emails, fmt.Errorf("read message %s: %w", m.ID, err)
+		}
+		em := Email{
+			ID: m.ID, Subject: full.Subject, Text: full.Text, HTML: strings.Join(full.HTML, ""),
+			Date: full.CreatedAt, Read: m.Seen,
+			From: (&mail.Address{Name: full.From.Name, Address: full.From.Address}).String(),
+		}
+		if em.Text == "" {
+			em.Text = full.Intro
+		}
+		var to []string
+		for _, a := range full.To {
+			to = append(to, a.Address)
+		}
+		em.To = strings.Join(to, ", ")
+		emails = append(emails, em)
+	}
+	return emails, nil
+}
+
+// Deactivate deletes the account behind e. An account already gone counts
+// as deleted.
+func (p *MailTM) Deactivate(ctx context.Context, e *ScratchEmail) error {
+	c, err := p.client(ctx, e)
+	if err != nil {
+		return err
+	}
+	err = c.Delete(ctx, "/accounts/"+url.PathEscape(e.ProviderData[mailTMAccount]))
+	if httpapi.StatusCode(err) == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// client returns an API client authenticated as e, logging in first when
+// e has no token yet and again when the token is rejected.
+func (p *MailTM) client(ctx context.Context, e *ScratchEmail) (*httpapi.Client, error) {
+	if e.ProviderData == nil {
+		e.ProviderData = map[string]string{}
+	}
+	if e.ProviderData[mailTMToken] == "" {
+		if err := p.login(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+	c := newClient(p.BaseURL)
+	c.Auth = httpapi.BearerAuth(func() string { return e.ProviderData[mailTMToken] })
+	c.Reauth = func(ctx context.Context) error { return p.login(ctx, e) }
+	return c, nil
+}
+
+// login exchanges e's stored password for a token and keeps it on e.
+func (p *MailTM) login(ctx context.Context, e *ScratchEmail) error {
+	password := e.ProviderData[mailTMPassword]
+	if password == "" {
+		return fmt.Errorf("log in as %s: no stored password", e.Address)
+	}
+	var tok struct {
+		ID    string `json:"id"`
+		Token string `json:"token"`
+	}
+	if err := newClient(p.BaseURL).Post(ctx, "/token", map[string]string{"address": e.Address, "password": password}, &tok); err != nil {
+		return fmt.Errorf("log in as %s: %w", e.Address, err)
+	}
+	e.ProviderData[mailTMToken] = tok.Token
+	if tok.ID != "" {
+		e.ProviderData[mailTMAccount] = tok.ID
+	}
+	return nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
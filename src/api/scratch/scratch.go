@@ -0,0 +1,269 @@
+// Package scratch creates throwaway email addresses to apply from and
+// reads what arrives at them.
+//
+// A Provider talks to one disposable-mail service. Whatever it needs to
+// reach an address again (an account ID, a password, a session token) is
+// kept in the address's ProviderData, which the Manager stores, so an
+// address created in one run can still be read after a restart.
+package scratch
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"maps"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"sprayer/src/api/httpapi"
+)
+
+// ScratchEmail is a disposable address created with a provider.
+type ScratchEmail struct {
+	ID           int64             `json:"id"`
+	Provider     string            `json:"provider"`
+	Address      string            `json:"address"`
+	ProviderData map[string]string `json:"-"`
+	Active       bool              `json:"active"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// Email is a message received at a scratch address. MessageID is its
+// Message-ID header when the provider knows it. A Partial email is only
+// what the provider logged about it, without its subject or body.
+type Email struct {
+	ID        string
+	MessageID string
+	From      string
+	To        string
+	Subject   string
+	Text      string
+	HTML      string
+	Date      time.Time
+	Read      bool
+	Partial   bool
+}
+
+// Provider is a disposable-mail service. CheckInbox and Deactivate may
+// update e.ProviderData, for example with a refreshed token; the Manager
+// saves it afterwards.
+type Provider interface {
+	Name() string
+	Create(ctx context.Context) (*ScratchEmail, error)
+	CheckInbox(ctx context.Context, e *ScratchEmail) ([]Email, error)
+	Deactivate(ctx context.Context, e *ScratchEmail) error
+}
+
+// newClient is an API client for a provider at base, named in offline
+// errors.
+func newClient(base string) *httpapi.Client {
+	c := httpapi.New(base)
+	c.Feature = "scratch email"
+	return c
+}
+
+// Manager creates scratch addresses and keeps them, with their provider
+// data, in the database. Each address is read through the provider that
+// created it.
+type Manager struct {
+	db        *sql.DB
+	providers []Provider
+}
+
+// NewManager stores the addresses providers create in db. New addresses
+// are made with the first provider that manages to.
+func NewManager(db *sql.DB, providers ...Provider) (*Manager, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("scratch: no providers")
+	}
+	if err := migrate(db); err != nil {
+		return nil, err
+	}
+	return &Manager{db: db, providers: providers}, nil
+}
+
+// EnvProvider names the provider new addresses are made with first.
+const EnvProvider = "SPRAYER_SCRATCH_EMAIL_PROVIDER"
+
+// Providers returns every provider, the one EnvProvider names (mailtm,
+// guerrilla or simplelogin) first and mail.tm first otherwise.
+func Providers() []Provider {
+	all := []Provider{NewMailTM(), NewGuerrilla(), NewSimpleLogin()}
+	want := strings.ToLower(strings.TrimSpace(os.Getenv(EnvProvider)))
+	for i, p := range all {
+		if p.Name() == want {
+			return append([]Provider{p}, append(all[:i:i], all[i+1:]...)...)
+		}
+	}
+	return all
+}
+
+func migrate(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS scratch_emails (
+			id            INTEGER PRIMARY KEY AUTOINCREMENT,
+			provider      TEXT NOT NULL,
+			address       TEXT NOT NULL UNIQUE,
+			provider_data TEXT DEFAULT '{}',
+			active        INTEGER DEFAULT 1,
+			created_at    DATETIME
+		)`)
+	return err
+}
+
+// Create makes a new address and stores it. A provider that fails, say
+// because its service is down or refuses new accounts, is passed over for
+// the next.
+func (m *Manager) Create(ctx context.Context) (*ScratchEmail, error) {
+	var errs []error
+	for _, p := range m.providers {
+		e, err := p.Create(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: create address: %w", p.Name(), err))
+			continue
+		}
+		e.Provider, e.Active = p.Name(), true
+		if e.CreatedAt.IsZero() {
+			e.CreatedAt = time.Now()
+		}
+		data, err := json.Marshal(e.ProviderData)
+		if err != nil {
+			return nil, err
+		}
+		res, err := m.db.Exec(`INSERT INTO scratch_emails (provider, address, provider_data, active, created_at) VALUES (?, ?, ?, 1, ?)`,
+			e.Provider, e.Address, string(data), e.CreatedAt.UTC())
+		if err != nil {
+			return nil, err
+		}
+		e.ID, err = res.LastInsertId()
+		return e, err
+	}
+	return nil, errors.Join(errs...)
+}
+
+// CheckInbox reads e's messages and saves any provider data the provider
+// refreshed on the way, even when the check then failed.
+func (m *Manager) CheckInbox(ctx context.Context, e *ScratchEmail) ([]Email, error) {
+	p, err := m.provider(e)
+	if err != nil {
+		return nil, err
+	}
+	before := maps.Clone(e.ProviderData)
+	emails, err := p.CheckInbox(ctx, e)
+	if !maps.Equal(e.ProviderData, before) {
+		if serr := m.save(e); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	return emails, err
+}
+
+// Deactivate closes e at the provider and marks it inactive.
+func (m *Manager) Deactivate(ctx context.Context, e *ScratchEmail) error {
+	p, err := m.provider(e)
+	if err != nil {
+		return err
+	}
+	if err := p.Deactivate(ctx, e); err != nil {
+		m.save(e)
+		return fmt.Errorf("%s: deactivate %s: %w", e.Provider, e.Address, err)
+	}
+	e.Active = false
+	return m.save(e)
+}
+
+// provider returns the provider e was created with.
+func (m *Manager) provider(e *ScratchEmail) (Provider, error) {
+	for _, p := range m.providers {
+		if p.Name() == e.Provider {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: unknown provider %q", e.Address, e.Provider)
+}
+
+// save writes e's provider data and state back.
+func (m *Manager) save(e *ScratchEmail) error {
+	data, err := json.Marshal(e.ProviderData)
+	if err != nil {
+		return err
+	}
+	_, err = m.db.Exec(`UPDATE scratch_emails SET provider_data = ?, active = ? WHERE id = ?`, string(data), e.Active, e.ID)
+	return err
+}
+
+// ByID returns a stored address, or sql.ErrNoRows.
+func (m *Manager) ByID(id int64) (*ScratchEmail, error) {
+	es, err := m.query(`SELECT `+columns+` FROM scratch_emails WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(es) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &es[0], nil
+}
+
+// Active returns the addresses still in use, oldest first.
+func (m *Manager) Active() ([]ScratchEmail, error) {
+	return m.query(`SELECT ` + columns + ` FROM scratch_emails WHERE active ORDER BY id`)
+}
+
+func (m *Manager) activeWith(provider string) ([]ScratchEmail, error) {
+	return m.query(`SELECT `+columns+` FROM scratch_emails WHERE active AND provider = ? ORDER BY id`, provider)
+}
+
+// All returns every stored address, oldest first.
+func (m *Manager) All() ([]ScratchEmail, error) {
+	return m.query(`SELECT ` + columns + ` FROM scratch_emails ORDER BY id`)
+}
+
+const columns = `id, provider, address, provider_data, active, created_at`
+
+func (m *Manager) query(q string, args ...any) ([]ScratchEmail, error) {
+	rows, err := m.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ScratchEmail
+	for rows.Next() {
+		var e ScratchEmail
+		var data string
+		if err := rows.Scan(&e.ID, &e.Provider, &e.Address, &data, &e.Active, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(data), &e.ProviderData); err != nil {
+			return nil, fmt.Errorf("scratch email %d: provider data: %w", e.ID, err)
+		}
+		if e.ProviderData == nil {
+			e.ProviderData = map[string]string{}
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+var (
+	htmlBreak = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|tr|h[1-6])>`)
+	htmlTag   = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankRun  = regexp.MustCompile(`\n{3,}`)
+)
+
+// textFromHTML is the readable text of an HTML body, for providers that
+// only deliver HTML.
+func textFromHTML(s string) string {
+	s = htmlBreak.ReplaceAllString(s, "\n")
+	s = html.UnescapeString(htmlTag.ReplaceAllString(s, ""))
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+	return strings.TrimSpace(blankRun.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
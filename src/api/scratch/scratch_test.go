@@ -0,0 +1,363 @@
+package scratch
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"sprayer/src/api/inbox"
+)
+
+// fakeMailTM is a mail.tm API holding one account. Tokens it issued stay
+// valid until expire is called.
+type fakeMailTM struct {
+	mu       sync.Mutex
+	address  string
+	password string
+	tokens   map[string]bool
+	issued   int
+	logins   int
+	deleted  bool
+}
+
+func (f *fakeMailTM) expire() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokens = map[string]bool{}
+}
+
+func (f *fakeMailTM) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var creds struct{ Address, Password string }
+	switch {
+	case r.URL.Path == "/domains":
+		fmt.Fprint(w, `{"hydra:member":[{"domain":"old.test","isActive":false},{"domain":"mail.test","isActive":true}]}`)
+		return
+	case r.URL.Path == "/accounts" && r.Method == http.MethodPost:
+		json.NewDecoder(r.Body).Decode(&creds)
+		f.address, f.password = creds.Address, creds.Password
+		fmt.Fprint(w, `{"id":"acc-1"}`)
+		return
+	case r.URL.Path == "/token":
+		json.NewDecoder(r.Body).Decode(&creds)
+		if creds.Address != f.address || creds.Password != f.password {
+			http.Error(w, `{"message":"Invalid credentials."}`, http.StatusUnauthorized)
+			return
+		}
+		f.issued++
+		f.logins++
+		tok := fmt.Sprintf("tok-%d", f.issued)
+		f.tokens[tok] = true
+		fmt.Fprintf(w, `{"id":"acc-1","token":%q}`, tok)
+		return
+	}
+	if !f.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")] {
+		http.Error(w, `{"code":401,"message":"Expired JWT Token"}`, http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.URL.Path == "/messages":
+		fmt.Fprint(w, `{"hydra:member":[{"id":"m1","from":{"address":"jane@acme.com","name":"Jane"},
+			"to":[{"address":"`+f.address+`"}],"subject":"Re: Backend Engineer","intro":"Are you free…","seen":false,
+			"createdAt":"2026-03-02T10:00:00+00:00"}]}`)
+	case r.URL.Path == "/messages/m1":
+		fmt.Fprint(w, `{"id":"m1","from":{"address":"jane@acme.com","name":"Jane"},"to":[{"address":"`+f.address+`"}],
+			"subject":"Re: Backend Engineer","text":"Are you free on Tuesday?","html":["<p>Are you free on Tuesday?</p>"],
+			"createdAt":"2026-03-02T10:00:00+00:00"}`)
+	case r.URL.Path == "/accounts/acc-1" && r.Method == http.MethodDelete:
+		f.deleted = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "scratch.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestMailTM_ReloginAfterRestart(t *testing.T) {
+	fake := &fakeMailTM{tokens: map[string]bool{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	db := openDB(t)
+	ctx := context.Background()
+
+	m, err := NewManager(db, &MailTM{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := m.Create(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(created.Address, "@mail.test") || created.ProviderData[mailTMToken] != "tok-1" {
+		t.Fatalf("created %+v", created)
+	}
+
+	// A new process reads the address back from the database, and by
+	// then the token it stored has expired.
+	fake.expire()
+	m, err = NewManager(db, &MailTM{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	active, err := m.Active()
+	if err != nil || len(active) != 1 {
+		t.Fatalf("active = %+v, %v", active, err)
+	}
+	e := active[0]
+	emails, err := m.CheckInbox(ctx, &e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emails) != 1 || emails[0].Text != "Are you free on Tuesday?" || emails[0].From != `"Jane" <jane@acme.com>` ||
+		emails[0].HTML != "<p>Are you free on Tuesday?</p>" {
+		t.Errorf("emails = %+v", emails)
+	}
+	stored, err := m.ByID(e.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stored.ProviderData[mailTMToken]; got != "tok-2" {
+		t.Errorf("stored token = %q, want the refreshed tok-2", got)
+	}
+
+	// The refreshed token is used as is next time.
+	if _, err := m.CheckInbox(ctx, stored); err != nil {
+		t.Fatal(err)
+	}
+	if fake.logins != 2 {
+		t.Errorf("logged in %d times, want 2", fake.logins)
+	}
+
+	fake.expire()
+	if err := m.Deactivate(ctx, stored); err != nil {
+		t.Fatal(err)
+	}
+	if !fake.deleted {
+		t.Error("account not deleted")
+	}
+	if active, _ := m.Active(); len(active) != 0 {
+		t.Errorf("deactivated address still active: %+v", active)
+	}
+}
+
+func TestManagerInbox(t *testing.T) {
+	fake := &fakeMailTM{tokens: map[string]bool{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	m, err := NewManager(openDB(t), &MailTM{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := m.Create(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := m.Inboxes()[0]
+	if p.Name() != "mailtm" {
+		t.Errorf("name = %q", p.Name())
+	}
+	msgs, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 || msgs[0].ProviderID != e.Address+"/m1" || msgs[0].To != e.Address || msgs[0].Body != "Are you free on Tuesday?" {
+		t.Errorf("messages = %+v", msgs)
+	}
+}
+
+// guerrillaFixture serves the recorded Guerrilla Mail responses in
+// testdata/guerrilla, one file per API function, keeping the calls made.
+func guerrillaFixture(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		name := q.Get("f")
+		if id := q.Get("email_id"); id != "" {
+			name += "_" + id
+		}
+		mu.Lock()
+		calls = append(calls, name+" "+q.Get("sid_token"))
+		mu.Unlock()
+		data, err := os.ReadFile(filepath.Join("testdata", "guerrilla", name+".json"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestGuerrilla_FallbackAndInbox(t *testing.T) {
+	srv, calls := guerrillaFixture(t)
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	ctx := context.Background()
+
+	// mail.tm is unreachable, so the address comes from Guerrilla Mail.
+	m, err := NewManager(openDB(t), &MailTM{BaseURL: down.URL}, &Guerrilla{BaseURL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := m.Create(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Provider != "guerrilla" || e.Address != "qwvtgfke@guerrillamailblock.com" {
+		t.Fatalf("created %+v", e)
+	}
+
+	emails, err := m.CheckInbox(ctx, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emails) != 2 {
+		t.Fatalf("got %d emails, want 2", len(emails))
+	}
+	reply := emails[1]
+	if reply.From != "jane@acme.com" || reply.Subject != "Re: Backend Engineer" || reply.Read ||
+		reply.Text != "Hi,\nthanks for applying & your CV.\nAre you free on Tuesday?" ||
+		!strings.Contains(reply.HTML, "<p>Are you free on Tuesday?</p>") || !reply.Date.Equal(time.Unix(1772449200, 0)) {
+		t.Errorf("reply = %+v", reply)
+	}
+	stored, err := m.ByID(e.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stored.ProviderData[guerrillaSID]; got != "pn1ff07r39gqo2i1gvhc4ce1v3" {
+		t.Errorf("stored sid_token = %q, want the resumed session's", got)
+	}
+
+	if err := m.Deactivate(ctx, stored); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"get_email_address ",
+		"set_email_user k7p1c2tu9mq6nlbmb5h1bkk3n4",
+		"check_email pn1ff07r39gqo2i1gvhc4ce1v3",
+		"fetch_email_1 pn1ff07r39gqo2i1gvhc4ce1v3",
+		"fetch_email_245938210 pn1ff07r39gqo2i1gvhc4ce1v3",
+		"set_email_user pn1ff07r39gqo2i1gvhc4ce1v3",
+		"forget_me pn1ff07r39gqo2i1gvhc4ce1v3",
+	}
+	if strings.Join(*calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls:\n%s\nwant:\n%s", strings.Join(*calls, "\n"), strings.Join(want, "\n"))
+	}
+	if active, _ := m.Active(); len(active) != 0 {
+		t.Errorf("forgotten address still active: %+v", active)
+	}
+}
+
+func TestManager_CreateAllDown(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	m, err := NewManager(openDB(t), &MailTM{BaseURL: down.URL}, &Guerrilla{BaseURL: down.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = m.Create(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "mailtm: create address") || !strings.Contains(err.Error(), "guerrilla: create address") {
+		t.Errorf("error = %v, want both providers' failures", err)
+	}
+}
+
+func TestProviders(t *testing.T) {
+	names := func() []string {
+		var out []string
+		for _, p := range Providers() {
+			out = append(out, p.Name())
+		}
+		return out
+	}
+	if got := strings.Join(names(), ","); got != "mailtm,guerrilla,simplelogin" {
+		t.Errorf("default order = %s", got)
+	}
+	t.Setenv(EnvProvider, "Guerrilla")
+	if got := strings.Join(names(), ","); got != "guerrilla,mailtm,simplelogin" {
+		t.Errorf("with %s=Guerrilla = %s", EnvProvider, got)
+	}
+}
+
+func TestSimpleLogin_CheckInbox(t *testing.T) {
+	forwardedAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authentication") != "sl-key" {
+			http.Error(w, `{"error":"Wrong api key"}`, http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/alias/random/new":
+			fmt.Fprint(w, `{"id":42,"email":"quiet.moon123@slmail.me"}`)
+		case "/api/aliases/42/activities":
+			fmt.Fprintf(w, `{"activities":[
+				{"action":"forward","from":"jane@acme.com","to":"quiet.moon123@slmail.me","timestamp":%d},
+				{"action":"reply","from":"quiet.moon123@slmail.me","to":"jane@acme.com","timestamp":%d},
+				{"action":"forward","from":"hr@globex.com","to":"quiet.moon123@slmail.me","timestamp":%d}]}`,
+				forwardedAt.Unix()+30, forwardedAt.Unix()+600, forwardedAt.Unix()+3600)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "cur"), 0o755)
+	os.WriteFile(filepath.Join(dir, "cur", "1.host:2,S"), []byte("From: \"Jane Doe - jane at acme.com\" <ra+x1@simplelogin.co>\r\n"+
+		"To: quiet.moon123@slmail.me\r\nSubject: Re: Backend Engineer\r\nMessage-ID: <r1@acme.com>\r\n"+
+		"Date: Mon, 02 Mar 2026 10:00:00 +0000\r\n\r\nAre you free on Tuesday?\r\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "cur", "2.host:2,"), []byte("From: news@example.com\r\nTo: me@example.org\r\nSubject: News\r\n\r\nHi\r\n"), 0o644)
+
+	p := &SimpleLogin{BaseURL: srv.URL, APIKey: "sl-key", Mailbox: inbox.Maildir{Dir: dir}}
+	m, err := NewManager(openDB(t), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := m.Create(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	emails, err := m.CheckInbox(context.Background(), e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emails) != 2 {
+		t.Fatalf("got %d emails, want the forwarded one and a partial one: %+v", len(emails), emails)
+	}
+	full, partial := emails[0], emails[1]
+	if full.Partial || full.From != `"Jane Doe" <jane@acme.com>` || full.Subject != "Re: Backend Engineer" ||
+		full.Text != "Are you free on Tuesday?\n" || !full.Read || full.MessageID != "<r1@acme.com>" {
+		t.Errorf("forwarded email = %+v", full)
+	}
+	if !partial.Partial || partial.From != "hr@globex.com" || partial.Text != "" {
+		t.Errorf("activity-only email = %+v", partial)
+	}
+
+	p.APIKey = ""
+	if _, err := m.CheckInbox(context.Background(), e); err == nil || !strings.Contains(err.Error(), "SPRAYER_SIMPLELOGIN_API_KEY") {
+		t.Errorf("missing key error = %v", err)
+	}
+}
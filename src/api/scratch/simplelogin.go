@@ -0,0 +1,170 @@
+package scratch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"sprayer/src/api/httpapi"
+	"sprayer/src/api/inbox"
+)
+
+// SimpleLoginBaseURL is the SimpleLogin API root.
+const SimpleLoginBaseURL = "https://app.simplelogin.io"
+
+// SimpleLogin makes aliases on SimpleLogin, which forwards what they
+// receive to the account's real mailbox. Its API only says who wrote to
+// an alias and when, so the messages themselves are read from Mailbox,
+// the forward-to mailbox (see inbox.Maildir); a forward not found there
+// is returned with just the activity data and marked Partial.
+type SimpleLogin struct {
+	BaseURL string
+	APIKey  string
+	Mailbox inbox.Provider
+}
+
+// NewSimpleLogin returns a provider using SPRAYER_SIMPLELOGIN_API_KEY that
+// reads forwarded messages from the default Maildir.
+func NewSimpleLogin() *SimpleLogin {
+	return &SimpleLogin{
+		BaseURL: SimpleLoginBaseURL,
+		APIKey:  os.Getenv("SPRAYER_SIMPLELOGIN_API_KEY"),
+		Mailbox: inbox.DefaultMaildir(),
+	}
+}
+
+func (*SimpleLogin) Name() string { return "simplelogin" }
+
+// simpleLoginAlias is the ProviderData key of the alias ID.
+const simpleLoginAlias = "alias_id"
+
+// simpleLoginActivity is one entry of an alias's activity log.
+type simpleLoginActivity struct {
+	Action    string `json:"action"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// forwardWindow is how far apart a forward's logged time and the
+// forwarded message's Date may be for the two to be the same email.
+const forwardWindow = 10 * time.Minute
+
+// Create makes a random alias.
+func (p *SimpleLogin) Create(ctx context.Context) (*ScratchEmail, error) {
+	c, err := p.client()
+	if err != nil {
+		return nil, err
+	}
+	var alias struct {
+		ID    int64  `json:"id"`
+		Email string `json:"email"`
+	}
+	if err := c.Post(ctx, "/api/alias/random/new?hostname=sprayer", map[string]string{"note": "sprayer scratch address"}, &alias); err != nil {
+		return nil, err
+	}
+	return &ScratchEmail{
+		Address:      alias.Email,
+		ProviderData: map[string]string{simpleLoginAlias: strconv.FormatInt(alias.ID, 10)},
+	}, nil
+}
+
+// CheckInbox returns the messages forwarded from e, in full when the
+// forward-to mailbox holds them.
+func (p *SimpleLogin) CheckInbox(ctx context.Context, e *ScratchEmail) ([]Email, error) {
+	c, err := p.client()
+	if err != nil {
+		return nil, err
+	}
+	var log struct {
+		Activities []simpleLoginActivity `json:"activities"`
+	}
+	path := "/api/aliases/" + e.ProviderData[simpleLoginAlias] + "/activities?page_id=0"
+	if err := c.Get(ctx, path, &log); err != nil {
+		return nil, fmt.Errorf("list activities: %w", err)
+	}
+
+	var full []Email
+	if p.Mailbox != nil {
+		msgs, err := p.Mailbox.Fetch(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("read forward-to mailbox: %w", err)
+		}
+		for _, m := range msgs {
+			if !strings.Contains(strings.ToLower(m.To), strings.ToLower(e.Address)) {
+				continue
+			}
+			full = append(full, Email{
+				ID: m.ProviderID, MessageID: m.MessageID, From: inbox.Sender(m.From), To: e.Address,
+				Subject: m.Subject, Text: m.Body, Date: m.Date, Read: m.Read,
+			})
+		}
+	}
+
+	emails := full
+	for _, a := range log.Activities {
+		if a.Action != "forward" {
+			continue
+		}
+		at := time.Unix(a.Timestamp, 0)
+		if forwarded(full, a.From, at) {
+			continue
+		}
+		emails = append(emails, Email{
+			ID:      fmt.Sprintf("activity-%d-%s", a.Timestamp, a.From),
+			From:    a.From,
+			To:      e.Address,
+			Subject: "Forwarded email",
+			Date:    at,
+			Partial: true,
+		})
+	}
+	return emails, nil
+}
+
+// forwarded reports whether emails holds the message from sender that
+// was forwarded at.
+func forwarded(emails []Email, sender string, at time.Time) bool {
+	sender = strings.ToLower(sender)
+	for _, em := range emails {
+		a, err := mail.ParseAddress(em.From)
+		if err != nil || strings.ToLower(a.Address) != sender {
+			continue
+		}
+		if d := em.Date.Sub(at); d < forwardWindow && d > -forwardWindow {
+			return true
+		}
+	}
+	return false
+}
+
+// Deactivate deletes the alias. An alias already gone counts as deleted.
+func (p *SimpleLogin) Deactivate(ctx context.Context, e *ScratchEmail) error {
+	c, err := p.client()
+	if err != nil {
+		return err
+	}
+	err = c.Delete(ctx, "/api/aliases/"+e.ProviderData[simpleLoginAlias])
+	if httpapi.StatusCode(err) == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (p *SimpleLogin) client() (*httpapi.Client, error) {
+	if p.APIKey == "" {
+		return nil, errors.New("set SPRAYER_SIMPLELOGIN_API_KEY")
+	}
+	c := newClient(p.BaseURL)
+	c.Auth = func(r *http.Request) error {
+		r.Header.Set("Authentication", p.APIKey)
+		return nil
+	}
+	return c, nil
+}
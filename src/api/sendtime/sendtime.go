@@ -0,0 +1,95 @@
+// Package sendtime suggests when to send an application so it lands in the
+// recipient's morning rather than overnight.
+package sendtime
+
+import (
+	"fmt"
+	"time"
+)
+
+// Config sets the morning window, as offsets from local midnight.
+type Config struct {
+	WindowStart time.Duration // earliest acceptable send time
+	WindowEnd   time.Duration // latest acceptable send time (exclusive)
+	Preferred   time.Duration // time suggested when waiting for the next window
+	Local       *time.Location
+}
+
+// DefaultConfig aims for 09:15 within an 08:00-10:00 window, falling back
+// to the machine's zone.
+func DefaultConfig() Config {
+	return Config{
+		WindowStart: 8 * time.Hour,
+		WindowEnd:   10 * time.Hour,
+		Preferred:   9*time.Hour + 15*time.Minute,
+		Local:       time.Local,
+	}
+}
+
+// Suggestion is a proposed send time.
+type Suggestion struct {
+	At    time.Time // in the recipient's zone
+	Known bool      // false when the location was unknown and Local was used
+}
+
+// Suggest returns the next weekday morning window in the zone of location.
+// If now already falls inside a window it is returned unchanged.
+func Suggest(location string, now time.Time, cfg Config) Suggestion {
+	zone, ok := Zone(location)
+	if !ok {
+		zone = cfg.Local
+		if zone == nil {
+			zone = time.UTC
+		}
+	}
+
+	t := now.In(zone)
+	if isWeekday(t) {
+		start, end := clock(t, cfg.WindowStart), clock(t, cfg.WindowEnd)
+		if !t.Before(start) && t.Before(end) {
+			return Suggestion{At: t, Known: ok}
+		}
+		if at := clock(t, cfg.Preferred); t.Before(at) {
+			return Suggestion{At: at, Known: ok}
+		}
+	}
+	day := t
+	for {
+		// AddDate moves by calendar day, so DST changes don't shift the
+		// wall-clock time picked below.
+		day = day.AddDate(0, 0, 1)
+		if isWeekday(day) {
+			return Suggestion{At: clock(day, cfg.Preferred), Known: ok}
+		}
+	}
+}
+
+// String renders the suggestion relative to now, e.g.
+// "suggested: Tue 09:15 CET — in 16h".
+func (s Suggestion) String(now time.Time) string {
+	out := fmt.Sprintf("suggested: %s — %s", s.At.Format("Mon 15:04 MST"), until(s.At.Sub(now)))
+	if !s.Known {
+		out += " (your time; location unknown)"
+	}
+	return out
+}
+
+// clock returns t's date at offset d past midnight in t's zone.
+func clock(t time.Time, d time.Duration) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), int(d/time.Hour), int(d%time.Hour/time.Minute), 0, 0, t.Location())
+}
+
+func isWeekday(t time.Time) bool {
+	return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday
+}
+
+func until(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("in %dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("in %dh", int(d.Round(time.Hour).Hours()))
+	}
+}
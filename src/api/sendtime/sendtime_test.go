@@ -0,0 +1,121 @@
+package sendtime
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustZone(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestZone(t *testing.T) {
+	tests := []struct {
+		location string
+		want     string
+	}{
+		{"Berlin, Germany", "Europe/Berlin"},
+		{"Remote (US)", "America/New_York"},
+		{"Vancouver, Canada", "America/Vancouver"},
+		{"San Francisco, CA / Remote", "America/Los_Angeles"},
+		{"São Paulo, Brasil", "America/Sao_Paulo"},
+		{"Auckland, New Zealand", "Pacific/Auckland"},
+		{"Austin, TX", "America/Chicago"},
+		{"EU timezones", "Europe/Berlin"},
+		{"SF Bay Area", "America/Los_Angeles"},
+		{"Washington, DC", "America/New_York"},
+		{"Washington D.C.", "America/New_York"},
+		{"Remote - U.S. only", "America/New_York"},
+		{"Seattle, Washington", "America/Los_Angeles"},
+	}
+	for _, tt := range tests {
+		loc, ok := Zone(tt.location)
+		if !ok || loc.String() != tt.want {
+			t.Errorf("Zone(%q) = %v, %v; want %s", tt.location, loc, ok, tt.want)
+		}
+	}
+
+	for _, unknown := range []string{"", "Remote", "Anywhere", "Busan",
+		"Redmond, Washington", "Remote, come work with us", "Eu moro aqui", "sf"} {
+		if _, ok := Zone(unknown); ok {
+			t.Errorf("Zone(%q) should be unknown", unknown)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	utc := func(s string) time.Time {
+		v, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	cfg := DefaultConfig()
+	cfg.Local = mustZone(t, "America/Sao_Paulo")
+
+	tests := []struct {
+		name     string
+		location string
+		now      string
+		want     string // RFC3339 in the target zone
+	}{
+		{"before window same day", "Berlin", "2024-06-04T03:00:00Z", "2024-06-04T09:15:00+02:00"},
+		{"inside window is now", "Berlin", "2024-06-04T06:30:00Z", "2024-06-04T08:30:00+02:00"},
+		{"after window next day", "Berlin", "2024-06-04T12:00:00Z", "2024-06-05T09:15:00+02:00"},
+		{"friday evening skips weekend", "London", "2024-06-07T18:00:00Z", "2024-06-10T09:15:00+01:00"},
+		{"saturday morning skips weekend", "London", "2024-06-08T08:30:00Z", "2024-06-10T09:15:00+01:00"},
+
+		// Europe springs forward on 2024-03-31 (a Sunday) and falls back on 2024-10-27.
+		{"eu spring forward", "Paris", "2024-03-29T12:00:00Z", "2024-04-01T09:15:00+02:00"},
+		{"eu fall back", "Paris", "2024-10-25T12:00:00Z", "2024-10-28T09:15:00+01:00"},
+		// The US changes on different weekends, so Europe and New York are
+		// briefly only five hours apart.
+		{"us spring forward", "New York", "2024-03-08T20:00:00Z", "2024-03-11T09:15:00-04:00"},
+		{"us dst gap week", "New York", "2024-03-12T10:00:00Z", "2024-03-12T09:15:00-04:00"},
+		{"us fall back", "New York", "2024-11-01T20:00:00Z", "2024-11-04T09:15:00-05:00"},
+		// Southern hemisphere: Sydney leaves DST on 2024-04-07.
+		{"sydney leaves dst", "Sydney", "2024-04-05T00:00:00Z", "2024-04-08T09:15:00+10:00"},
+
+		// Date line: it is already Tuesday in Auckland and Kiritimati while
+		// still Monday in Honolulu.
+		{"auckland past window", "Auckland", "2024-06-03T23:00:00Z", "2024-06-05T09:15:00+12:00"},
+		{"kiritimati ahead", "Kiribati", "2024-06-03T17:00:00Z", "2024-06-04T09:15:00+14:00"},
+		{"honolulu behind", "Honolulu", "2024-06-03T19:00:00Z", "2024-06-03T09:00:00-10:00"},
+		{"samoa friday is done", "Samoa", "2024-06-07T00:00:00Z", "2024-06-10T09:15:00+13:00"},
+
+		{"unknown uses local", "Remote", "2024-06-04T20:00:00Z", "2024-06-05T09:15:00-03:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Suggest(tt.location, utc(tt.now), cfg)
+			if got.At.Format(time.RFC3339) != tt.want {
+				t.Errorf("Suggest = %s, want %s", got.At.Format(time.RFC3339), tt.want)
+			}
+			if got.At.Before(utc(tt.now)) {
+				t.Errorf("suggested a time in the past")
+			}
+		})
+	}
+}
+
+func TestSuggestion_String(t *testing.T) {
+	now := time.Date(2024, 1, 15, 16, 15, 0, 0, time.UTC) // Monday
+	s := Suggest("Berlin", now, DefaultConfig())
+	if got := s.String(now); got != "suggested: Tue 09:15 CET — in 16h" {
+		t.Errorf("String = %q", got)
+	}
+
+	cfg := DefaultConfig()
+	cfg.Local = time.UTC
+	s = Suggest("", now, cfg)
+	if got := s.String(now); !strings.Contains(got, "location unknown") {
+		t.Errorf("fallback should say so: %q", got)
+	}
+}
@@ -0,0 +1,105 @@
+package sendtime
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	// Embedded so suggestions don't depend on the host's zoneinfo.
+	_ "time/tzdata"
+)
+
+type zoneKey struct {
+	name string // normalized, words separated by single spaces
+	zone string
+}
+
+// A two-letter name is also a word ("work with us", Portuguese "eu"), so
+// it counts only as a whole word written in capitals: "Remote (US)".
+func (k zoneKey) abbreviation() bool { return len(k.name) == 2 }
+
+// cityZones is checked before countryZones so "Vancouver, Canada" resolves
+// to Pacific time rather than the country default.
+var cityZones = []zoneKey{
+	{"new york", "America/New_York"}, {"nyc", "America/New_York"},
+	{"boston", "America/New_York"}, {"washington dc", "America/New_York"},
+	{"d c", "America/New_York"}, // D.C.; a bare "Washington" may be the state
+	{"miami", "America/New_York"}, {"atlanta", "America/New_York"},
+	{"toronto", "America/Toronto"}, {"montreal", "America/Toronto"},
+	{"chicago", "America/Chicago"}, {"austin", "America/Chicago"},
+	{"dallas", "America/Chicago"}, {"denver", "America/Denver"},
+	{"san francisco", "America/Los_Angeles"}, {"sf", "America/Los_Angeles"},
+	{"bay area", "America/Los_Angeles"}, {"los angeles", "America/Los_Angeles"},
+	{"seattle", "America/Los_Angeles"}, {"portland", "America/Los_Angeles"},
+	{"vancouver", "America/Vancouver"}, {"honolulu", "Pacific/Honolulu"},
+	{"sao paulo", "America/Sao_Paulo"}, {"mexico city", "America/Mexico_City"},
+	{"london", "Europe/London"}, {"manchester", "Europe/London"},
+	{"edinburgh", "Europe/London"}, {"dublin", "Europe/Dublin"},
+	{"lisbon", "Europe/Lisbon"}, {"porto", "Europe/Lisbon"},
+	{"madrid", "Europe/Madrid"}, {"barcelona", "Europe/Madrid"},
+	{"paris", "Europe/Paris"}, {"amsterdam", "Europe/Amsterdam"},
+	{"brussels", "Europe/Brussels"}, {"berlin", "Europe/Berlin"},
+	{"munich", "Europe/Berlin"}, {"hamburg", "Europe/Berlin"},
+	{"zurich", "Europe/Zurich"}, {"vienna", "Europe/Vienna"},
+	{"milan", "Europe/Rome"}, {"rome", "Europe/Rome"},
+	{"copenhagen", "Europe/Copenhagen"}, {"stockholm", "Europe/Stockholm"},
+	{"oslo", "Europe/Oslo"}, {"helsinki", "Europe/Helsinki"},
+	{"warsaw", "Europe/Warsaw"}, {"prague", "Europe/Prague"},
+	{"tel aviv", "Asia/Jerusalem"}, {"dubai", "Asia/Dubai"},
+	{"bangalore", "Asia/Kolkata"}, {"bengaluru", "Asia/Kolkata"},
+	{"singapore", "Asia/Singapore"}, {"tokyo", "Asia/Tokyo"},
+	{"sydney", "Australia/Sydney"}, {"melbourne", "Australia/Melbourne"},
+	{"perth", "Australia/Perth"}, {"auckland", "Pacific/Auckland"},
+	{"wellington", "Pacific/Auckland"},
+}
+
+// countryZones picks one zone per country; for countries spanning several
+// that is the most populous business zone.
+var countryZones = []zoneKey{
+	{"united states", "America/New_York"}, {"usa", "America/New_York"}, {"us", "America/New_York"},
+	{"u s", "America/New_York"},
+	{"canada", "America/Toronto"}, {"mexico", "America/Mexico_City"},
+	{"brazil", "America/Sao_Paulo"}, {"argentina", "America/Argentina/Buenos_Aires"},
+	{"united kingdom", "Europe/London"}, {"uk", "Europe/London"}, {"england", "Europe/London"},
+	{"ireland", "Europe/Dublin"}, {"portugal", "Europe/Lisbon"}, {"spain", "Europe/Madrid"},
+	{"france", "Europe/Paris"}, {"netherlands", "Europe/Amsterdam"}, {"belgium", "Europe/Brussels"},
+	{"germany", "Europe/Berlin"}, {"deutschland", "Europe/Berlin"}, {"switzerland", "Europe/Zurich"},
+	{"austria", "Europe/Vienna"}, {"italy", "Europe/Rome"}, {"denmark", "Europe/Copenhagen"},
+	{"sweden", "Europe/Stockholm"}, {"norway", "Europe/Oslo"}, {"finland", "Europe/Helsinki"},
+	{"poland", "Europe/Warsaw"}, {"czechia", "Europe/Prague"}, {"czech republic", "Europe/Prague"},
+	{"israel", "Asia/Jerusalem"}, {"uae", "Asia/Dubai"}, {"india", "Asia/Kolkata"},
+	{"singapore", "Asia/Singapore"}, {"japan", "Asia/Tokyo"},
+	{"australia", "Australia/Sydney"}, {"new zealand", "Pacific/Auckland"},
+	{"samoa", "Pacific/Apia"}, {"kiribati", "Pacific/Kiritimati"},
+	{"europe", "Europe/Berlin"}, {"eu", "Europe/Berlin"}, {"cet", "Europe/Berlin"},
+}
+
+// Zone maps a free-form job location ("Berlin, Germany", "Remote (US)")
+// to a time zone. ok is false when nothing in the location is recognized.
+func Zone(location string) (loc *time.Location, ok bool) {
+	text := " " + normalize(location) + " "
+	caps := " " + words(location) + " "
+	for _, table := range [][]zoneKey{cityZones, countryZones} {
+		for _, k := range table {
+			in, name := text, k.name
+			if k.abbreviation() {
+				in, name = caps, strings.ToUpper(k.name)
+			}
+			if strings.Contains(in, " "+name+" ") {
+				loc, err := time.LoadLocation(k.zone)
+				return loc, err == nil
+			}
+		}
+	}
+	return nil, false
+}
+
+// normalize lowercases s and splits it into words.
+func normalize(s string) string { return words(strings.ToLower(s)) }
+
+// words folds the accents our tables care about and collapses everything
+// in s that is not a letter into single spaces.
+func words(s string) string {
+	s = strings.NewReplacer("ã", "a", "á", "a", "é", "e", "ü", "u", "ö", "o", "ä", "a", "ç", "c").Replace(s)
+	return strings.Join(strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) }), " ")
+}
@@ -0,0 +1,199 @@
+// Package settings keeps the app-wide preferences in the database: the
+// default profile, exports, scraping, the LLM and the mail server. Each
+// setting has an environment variable, which wins over the stored value,
+// so .env files and one-off overrides keep working.
+package settings
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"sprayer/src/api/export"
+	"sprayer/src/api/job"
+	"sprayer/src/api/llm"
+)
+
+// Environment variables of the settings that had none before.
+const (
+	EnvDefaultProfile = "SPRAYER_DEFAULT_PROFILE"
+	EnvExportFormat   = "SPRAYER_EXPORT_FORMAT"
+	EnvExportDir      = "SPRAYER_EXPORT_DIR"
+	EnvTheme          = "SPRAYER_THEME"
+)
+
+// Themes are the TUI themes: dark, or mono for no colour at all.
+var Themes = []string{"dark", "mono"}
+
+// Settings are the stored preferences. Empty fields are unset and fall
+// back to the environment's value or the built-in default. Secrets, the
+// SMTP password and LLM key, stay in the environment.
+type Settings struct {
+	DefaultProfile    string `json:"default_profile,omitempty"`
+	ExportFormat      string `json:"export_format,omitempty"`
+	ExportDir         string `json:"export_dir,omitempty"`
+	ScrapeParallelism int    `json:"scrape_parallelism,omitempty"`
+	LLMProvider       string `json:"llm_provider,omitempty"`
+	LLMModel          string `json:"llm_model,omitempty"`
+	LLMBaseURL        string `json:"llm_base_url,omitempty"`
+	SMTPHost          string `json:"smtp_host,omitempty"`
+	SMTPPort          string `json:"smtp_port,omitempty"`
+	SMTPUser          string `json:"smtp_user,omitempty"`
+	SMTPFrom          string `json:"smtp_from,omitempty"`
+	Theme             string `json:"theme,omitempty"`
+}
+
+// Field is one setting: its key in the settings table and in exported
+// files, the environment variable overriding it, and its default.
+type Field struct {
+	Key     string
+	Env     string
+	Older   string // an older variable that overrides it too, if any
+	Title   string
+	Default string
+
+	str func(*Settings) *string // the text field; nil for ScrapeParallelism
+}
+
+// Fields lists every setting, in the order they are shown.
+var Fields = []Field{
+	{Key: "default_profile", Env: EnvDefaultProfile, Title: "Default profile", Default: "default",
+		str: func(s *Settings) *string { return &s.DefaultProfile }},
+	{Key: "export_format", Env: EnvExportFormat, Title: "Export format", Default: string(export.JobsCSV),
+		str: func(s *Settings) *string { return &s.ExportFormat }},
+	{Key: "export_dir", Env: EnvExportDir, Title: "Export directory", Default: "~/.sprayer/exports",
+		str: func(s *Settings) *string { return &s.ExportDir }},
+	{Key: "scrape_parallelism", Env: job.EnvScrapeParallelism, Title: "Sources scraped at once", Default: "0 (all)"},
+	{Key: "llm_provider", Env: llm.EnvLLMProvider, Title: "LLM provider", Default: llm.ProviderHosted,
+		str: func(s *Settings) *string { return &s.LLMProvider }},
+	{Key: "llm_model", Env: llm.EnvLLMModel, Title: "LLM model", Default: "the provider's",
+		str: func(s *Settings) *string { return &s.LLMModel }},
+	{Key: "llm_base_url", Env: llm.EnvLLMBaseURL, Older: llm.EnvLLMURL, Title: "LLM base URL", Default: "the provider's",
+		str: func(s *Settings) *string { return &s.LLMBaseURL }},
+	{Key: "smtp_host", Env: "SPRAYER_SMTP_HOST", Title: "SMTP host",
+		str: func(s *Settings) *string { return &s.SMTPHost }},
+	{Key: "smtp_port", Env: "SPRAYER_SMTP_PORT", Title: "SMTP port", Default: "587",
+		str: func(s *Settings) *string { return &s.SMTPPort }},
+	{Key: "smtp_user", Env: "SPRAYER_SMTP_USER", Title: "SMTP user",
+		str: func(s *Settings) *string { return &s.SMTPUser }},
+	{Key: "smtp_from", Env: "SPRAYER_SMTP_FROM", Title: "Send as", Default: "the SMTP user",
+		str: func(s *Settings) *string { return &s.SMTPFrom }},
+	{Key: "theme", Env: EnvTheme, Title: "Theme", Default: Themes[0],
+		str: func(s *Settings) *string { return &s.Theme }},
+}
+
+// Get is f's value in s as text, "" when unset.
+func (f Field) Get(s Settings) string {
+	if f.str == nil {
+		if s.ScrapeParallelism == 0 {
+			return ""
+		}
+		return strconv.Itoa(s.ScrapeParallelism)
+	}
+	return *f.str(&s)
+}
+
+// Set sets f in s from text; "" unsets it.
+func (f Field) Set(s *Settings, v string) error {
+	v = strings.TrimSpace(v)
+	if f.str != nil {
+		*f.str(s) = v
+		return nil
+	}
+	if v == "" {
+		s.ScrapeParallelism = 0
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("%s: %q is not a number", f.Key, v)
+	}
+	s.ScrapeParallelism = n
+	return nil
+}
+
+// Validate reports the first setting in s that holds a value the app
+// would refuse.
+func (s Settings) Validate() error {
+	if s.ExportFormat != "" {
+		if _, err := export.ParseJobFormat(s.ExportFormat); err != nil {
+			return fmt.Errorf("export_format: %w", err)
+		}
+	}
+	if s.ScrapeParallelism < 0 {
+		return errors.New("scrape_parallelism: must be 0 (all at once) or more")
+	}
+	if p := strings.ToLower(s.LLMProvider); p != "" && p != llm.ProviderHosted && p != llm.ProviderOpenAI && p != llm.ProviderOllama {
+		return fmt.Errorf("llm_provider: %q is not %s, %s or %s", s.LLMProvider, llm.ProviderHosted, llm.ProviderOpenAI, llm.ProviderOllama)
+	}
+	if s.SMTPPort != "" {
+		if n, err := strconv.Atoi(s.SMTPPort); err != nil || n <= 0 || n > 65535 {
+			return fmt.Errorf("smtp_port: %q is not a port", s.SMTPPort)
+		}
+	}
+	if s.Theme != "" && !slices.Contains(Themes, s.Theme) {
+		return fmt.Errorf("theme: %q is not one of %s", s.Theme, strings.Join(Themes, ", "))
+	}
+	return nil
+}
+
+// Overrides maps the key of each setting the environment sets to the
+// value it gives.
+type Overrides map[string]string
+
+// Apply puts the settings s has values for into the environment, where
+// the rest of the app reads them, except those the environment already
+// sets: those are returned. Call it once at startup, after loading .env.
+func Apply(s Settings) Overrides {
+	env := make(Overrides)
+	for _, f := range Fields {
+		if v := cmp.Or(os.Getenv(f.Env), os.Getenv(f.Older)); v != "" {
+			env[f.Key] = v
+			continue
+		}
+		if v := f.Get(s); v != "" {
+			os.Setenv(f.Env, v)
+		}
+	}
+	return env
+}
+
+// Source is where a setting's value comes from.
+type Source string
+
+const (
+	FromEnv     Source = "environment"
+	FromStore   Source = "stored"
+	FromDefault Source = "default"
+)
+
+// Precedence says which source wins, for --show.
+const Precedence = "environment (and .env) > stored settings > default"
+
+// Value is a setting as it applies.
+type Value struct {
+	Field
+	Value  string
+	Source Source
+}
+
+// Resolve is every setting with the value it takes, given the stored
+// settings and what the environment overrides.
+func Resolve(s Settings, env Overrides) []Value {
+	out := make([]Value, len(Fields))
+	for i, f := range Fields {
+		switch v, ok := env[f.Key]; {
+		case ok:
+			out[i] = Value{f, v, FromEnv}
+		case f.Get(s) != "":
+			out[i] = Value{f, f.Get(s), FromStore}
+		default:
+			out[i] = Value{f, f.Default, FromDefault}
+		}
+	}
+	return out
+}
@@ -0,0 +1,115 @@
+package settings
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	s, err := NewStore(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestStore_RoundTrip(t *testing.T) {
+	s := openTestStore(t)
+	if got, err := s.Load(); err != nil || got != (Settings{}) {
+		t.Fatalf("nothing stored loads %+v, %v", got, err)
+	}
+
+	want := Settings{DefaultProfile: "rust", ExportFormat: "markdown", ScrapeParallelism: 3, SMTPPort: "465", Theme: "mono"}
+	if err := s.Save(want); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Load(); err != nil || got != want {
+		t.Fatalf("loaded %+v, %v; want %+v", got, err, want)
+	}
+
+	// Clearing a setting removes it.
+	want.Theme, want.ScrapeParallelism = "", 0
+	if err := s.Save(want); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := s.Load(); got != want {
+		t.Errorf("after clearing loaded %+v", got)
+	}
+
+	if err := s.Save(Settings{SMTPPort: "mail"}); err == nil {
+		t.Error("saved an invalid port")
+	}
+	if got, _ := s.Load(); got != want {
+		t.Errorf("a refused save changed the settings to %+v", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, bad := range []Settings{
+		{ExportFormat: "pdf"},
+		{ScrapeParallelism: -1},
+		{LLMProvider: "gemini"},
+		{SMTPPort: "70000"},
+		{Theme: "solarized"},
+	} {
+		if bad.Validate() == nil {
+			t.Errorf("%+v validated", bad)
+		}
+	}
+	ok := Settings{ExportFormat: "json", LLMProvider: "Ollama", SMTPPort: "587", Theme: "dark"}
+	if err := ok.Validate(); err != nil {
+		t.Errorf("%+v: %v", ok, err)
+	}
+}
+
+func TestApply_EnvironmentWins(t *testing.T) {
+	for _, f := range Fields {
+		t.Setenv(f.Env, "")
+		os.Unsetenv(f.Env)
+	}
+	t.Setenv("SPRAYER_LLM_URL", "http://old:11434")
+	t.Setenv(EnvTheme, "dark")
+
+	s := Settings{LLMBaseURL: "http://stored:11434", LLMModel: "llama3", Theme: "mono", ScrapeParallelism: 2}
+	env := Apply(s)
+
+	if got := os.Getenv("SPRAYER_LLM_MODEL"); got != "llama3" {
+		t.Errorf("stored model not applied: %q", got)
+	}
+	if got := os.Getenv("SPRAYER_SCRAPE_PARALLELISM"); got != "2" {
+		t.Errorf("stored parallelism not applied: %q", got)
+	}
+	if got := os.Getenv(EnvTheme); got != "dark" {
+		t.Errorf("stored theme replaced the environment's: %q", got)
+	}
+	if got := os.Getenv("SPRAYER_LLM_BASE_URL"); got != "" {
+		t.Errorf("stored base URL applied over the older variable: %q", got)
+	}
+	if env["theme"] != "dark" || env["llm_base_url"] != "http://old:11434" || len(env) != 2 {
+		t.Errorf("overrides %v", env)
+	}
+
+	byKey := make(map[string]Value)
+	for _, v := range Resolve(s, env) {
+		byKey[v.Key] = v
+	}
+	for key, want := range map[string]Value{
+		"theme":           {Value: "dark", Source: FromEnv},
+		"llm_model":       {Value: "llama3", Source: FromStore},
+		"default_profile": {Value: "default", Source: FromDefault},
+	} {
+		if got := byKey[key]; got.Value != want.Value || got.Source != want.Source {
+			t.Errorf("%s resolves to %q from %s, want %q from %s", key, got.Value, got.Source, want.Value, want.Source)
+		}
+	}
+}
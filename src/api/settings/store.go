@@ -0,0 +1,92 @@
+package settings
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// Store keeps the settings in the settings table, a row per setting set
+// with its value as JSON.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore wraps a database connection for the settings, creating the
+// table if needed.
+func NewStore(db *sql.DB) (*Store, error) {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS settings (
+			key   TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		)`)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Load returns the stored settings; none stored is the zero Settings.
+// Rows for settings this version does not know are ignored.
+func (s *Store) Load() (Settings, error) {
+	var out Settings
+	rows, err := s.db.Query(`SELECT key, value FROM settings`)
+	if err != nil {
+		return out, err
+	}
+	defer rows.Close()
+	values := make(map[string]json.RawMessage)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return out, err
+		}
+		values[key] = json.RawMessage(value)
+	}
+	if err := rows.Err(); err != nil {
+		return out, err
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return out, err
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, fmt.Errorf("stored settings: %w", err)
+	}
+	return out, nil
+}
+
+// Save replaces the stored settings with st, after validating them.
+// Settings left empty are removed.
+func (s *Store) Save(st Settings) error {
+	if err := st.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, f := range Fields {
+		v, ok := values[f.Key]
+		if !ok {
+			_, err = tx.Exec(`DELETE FROM settings WHERE key = ?`, f.Key)
+		} else {
+			_, err = tx.Exec(`INSERT INTO settings (key, value) VALUES (?, ?)
+				ON CONFLICT(key) DO UPDATE SET value = excluded.value`, f.Key, string(v))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
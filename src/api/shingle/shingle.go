@@ -0,0 +1,151 @@
+// Package shingle compares texts by their overlapping word n-grams
+// (shingles), for spotting passages reused between cover letters.
+package shingle
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// N is the shingle length in words. Five words is long enough that stock
+// phrases ("I look forward to hearing") rarely fill a whole shingle with
+// the words around them, and short enough to catch a reused clause.
+const N = 5
+
+// Set is the sorted, deduplicated hashes of a text's shingles.
+type Set []uint64
+
+// word is a token of the original text and its byte span.
+type word struct {
+	norm       string
+	start, end int
+}
+
+// words splits text into lower-cased runs of letters and digits.
+func words(text string) []word {
+	var out []word
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			out = append(out, word{strings.ToLower(text[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		out = append(out, word{strings.ToLower(text[start:]), start, len(text)})
+	}
+	return out
+}
+
+// hashes returns the hash of the shingle starting at each word.
+func hashes(ws []word) []uint64 {
+	if len(ws) < N {
+		return nil
+	}
+	out := make([]uint64, len(ws)-N+1)
+	for i := range out {
+		h := fnv.New64a()
+		for _, w := range ws[i : i+N] {
+			h.Write([]byte(w.norm))
+			h.Write([]byte{' '})
+		}
+		out[i] = h.Sum64()
+	}
+	return out
+}
+
+// Of returns the shingle set of text. Case and punctuation are ignored;
+// a text under N words has none.
+func Of(text string) Set {
+	s := Set(hashes(words(text)))
+	slices.Sort(s)
+	return slices.Compact(s)
+}
+
+// Has reports whether h is in s.
+func (s Set) Has(h uint64) bool {
+	_, ok := slices.BinarySearch(s, h)
+	return ok
+}
+
+// Jaccard returns |a ∩ b| / |a ∪ b|, 0 when both are empty.
+func Jaccard(a, b Set) float64 {
+	common := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			common++
+			i++
+			j++
+		}
+	}
+	union := len(a) + len(b) - common
+	if union == 0 {
+		return 0
+	}
+	return float64(common) / float64(union)
+}
+
+// Passages returns the runs of text made of shingles in other, longest
+// first, with whitespace collapsed. At most max are returned; max <= 0
+// returns all.
+func Passages(text string, other Set, max int) []string {
+	ws := words(text)
+	type span struct{ start, end, words int }
+	var spans []span
+	run := -1
+	hs := hashes(ws)
+	for i := 0; i <= len(hs); i++ {
+		hit := i < len(hs) && other.Has(hs[i])
+		switch {
+		case hit && run < 0:
+			run = i
+		case !hit && run >= 0:
+			last := i - 1 + N - 1
+			spans = append(spans, span{ws[run].start, ws[last].end, last - run + 1})
+			run = -1
+		}
+	}
+	slices.SortStableFunc(spans, func(a, b span) int { return b.words - a.words })
+	if max > 0 && len(spans) > max {
+		spans = spans[:max]
+	}
+	out := make([]string, len(spans))
+	for i, sp := range spans {
+		out[i] = strings.Join(strings.Fields(text[sp.start:sp.end]), " ")
+	}
+	return out
+}
+
+// Encode packs s for storage.
+func (s Set) Encode() []byte {
+	b := make([]byte, 8*len(s))
+	for i, h := range s {
+		binary.LittleEndian.PutUint64(b[8*i:], h)
+	}
+	return b
+}
+
+// Decode unpacks a Set written by Encode.
+func Decode(b []byte) (Set, error) {
+	if len(b)%8 != 0 {
+		return nil, errors.New("shingle: truncated set")
+	}
+	s := make(Set, len(b)/8)
+	for i := range s {
+		s[i] = binary.LittleEndian.Uint64(b[8*i:])
+	}
+	return s, nil
+}
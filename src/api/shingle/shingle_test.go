@@ -0,0 +1,58 @@
+package shingle
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOf_IgnoresCaseAndPunctuation(t *testing.T) {
+	a := Of("I built the payment service in Go.")
+	b := Of("i BUILT the payment-service, in go")
+	if len(a) != 3 || !reflect.DeepEqual(a, b) {
+		t.Errorf("Of differs by case or punctuation: %v vs %v", a, b)
+	}
+	if got := Of("four words only here"); len(got) != 0 {
+		t.Errorf("text under N words has shingles: %v", got)
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	a := Of("one two three four five six seven")
+	if got := Jaccard(a, a); got != 1 {
+		t.Errorf("Jaccard(a, a) = %v", got)
+	}
+	if got := Jaccard(a, Of("eight nine ten eleven twelve")); got != 0 {
+		t.Errorf("disjoint sets = %v", got)
+	}
+	// Three shingles each, sharing two of them.
+	if got := Jaccard(a, Of("two three four five six seven eight")); got != 0.5 {
+		t.Errorf("overlap = %v, want 2/4", got)
+	}
+	if got := Jaccard(nil, nil); got != 0 {
+		t.Errorf("empty sets = %v", got)
+	}
+}
+
+func TestPassages(t *testing.T) {
+	old := Of("We ship small changes daily. Unrelated filler text goes here. I led the migration to Kafka last year.")
+	text := "Hi! I led the   migration to Kafka last year, and we\nship small changes daily."
+	got := Passages(text, old, 0)
+	want := []string{"I led the migration to Kafka last year", "we ship small changes daily"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Passages = %q, want %q", got, want)
+	}
+	if got := Passages(text, old, 1); len(got) != 1 {
+		t.Errorf("max 1 returned %d", len(got))
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	s := Of("the quick brown fox jumps over the lazy dog")
+	got, err := Decode(s.Encode())
+	if err != nil || !reflect.DeepEqual(got, s) {
+		t.Errorf("round trip = %v, %v; want %v", got, err, s)
+	}
+	if _, err := Decode([]byte{1, 2, 3}); err == nil {
+		t.Error("truncated input should fail")
+	}
+}
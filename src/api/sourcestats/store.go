@@ -0,0 +1,155 @@
+// Package sourcestats keeps per-source statistics across scrapes: how
+// often each source ran, what it returned, how it failed and how long it
+// took, so a source that quietly broke stands out.
+package sourcestats
+
+import (
+	"database/sql"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// DefaultZeroRuns is how many runs in a row a source may return nothing
+// before it is flagged.
+const DefaultZeroRuns = 3
+
+// Stats is one source's record across every scrape that ran it.
+type Stats struct {
+	Source      string        `json:"source"`
+	Runs        int           `json:"runs"`
+	Jobs        int           `json:"jobs"`      // returned over all runs
+	LastJobs    int           `json:"last_jobs"` // returned by the last run
+	Errors      int           `json:"errors"`
+	LastError   string        `json:"last_error,omitempty"`
+	Latency     time.Duration `json:"total_latency"`
+	LastRun     time.Time     `json:"last_run"`
+	LastSuccess *time.Time    `json:"last_success,omitempty"`
+	// ZeroStreak is how many runs in a row, up to the last, returned no
+	// jobs, failed or not.
+	ZeroStreak int `json:"zero_streak"`
+	// Partial counts the runs, among Runs, cut short by their scrape
+	// being cancelled.
+	Partial int `json:"partial"`
+}
+
+// AvgLatency is how long a run of the source takes on average.
+func (s Stats) AvgLatency() time.Duration {
+	if s.Runs == 0 {
+		return 0
+	}
+	return s.Latency / time.Duration(s.Runs)
+}
+
+// Flagged reports a source that returned no jobs in each of its last n
+// runs.
+func (s Stats) Flagged(n int) bool {
+	return n > 0 && s.ZeroStreak >= n
+}
+
+// Store persists the stats next to the jobs.
+type Store struct {
+	db *sql.DB
+}
+
+// NewStore wraps a database connection for source stats.
+func NewStore(db *sql.DB) (*Store, error) {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS source_stats (
+			source       TEXT PRIMARY KEY,
+			runs         INTEGER DEFAULT 0,
+			jobs         INTEGER DEFAULT 0,
+			last_jobs    INTEGER DEFAULT 0,
+			errors       INTEGER DEFAULT 0,
+			last_error   TEXT DEFAULT '',
+			latency_ms   INTEGER DEFAULT 0,
+			last_run     DATETIME NOT NULL,
+			last_success DATETIME DEFAULT NULL,
+			zero_streak  INTEGER DEFAULT 0
+		)`)
+	if err != nil {
+		return nil, err
+	}
+	if err := job.EnsureColumns(db, "source_stats", []job.Column{{Name: "partial", Decl: "INTEGER DEFAULT 0"}}); err != nil {
+		return nil, err
+	}
+	return &Store{db: db}, nil
+}
+
+// Record adds a run of source at now that returned jobs postings in
+// latency, failing with err unless it is nil.
+func (s *Store) Record(source string, jobs int, err error, latency time.Duration, now time.Time) error {
+	failed, msg := 0, ""
+	var success any
+	if err != nil {
+		failed, msg = 1, err.Error()
+	} else {
+		success = now.UTC()
+	}
+	zero := 0
+	if jobs == 0 {
+		zero = 1
+	}
+	_, dbErr := s.db.Exec(`
+		INSERT INTO source_stats (source, runs, jobs, last_jobs, errors, last_error, latency_ms, last_run, last_success, zero_streak)
+		VALUES (?, 1, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(source) DO UPDATE SET
+			runs = runs + 1,
+			jobs = jobs + excluded.jobs,
+			last_jobs = excluded.last_jobs,
+			errors = errors + excluded.errors,
+			last_error = CASE WHEN excluded.errors > 0 THEN excluded.last_error ELSE last_error END,
+			latency_ms = latency_ms + excluded.latency_ms,
+			last_run = excluded.last_run,
+			last_success = COALESCE(excluded.last_success, last_success),
+			zero_streak = CASE WHEN excluded.zero_streak > 0 THEN zero_streak + 1 ELSE 0 END`,
+		source, jobs, jobs, failed, msg, latency.Milliseconds(), now.UTC(), success, zero)
+	return dbErr
+}
+
+// RecordPartial adds a run of source at now that was cut short by its
+// scrape being cancelled, after returning jobs postings in latency. It
+// counts towards the totals, but says nothing about whether the source
+// works: errors, the last success and the zero streak are left alone.
+func (s *Store) RecordPartial(source string, jobs int, latency time.Duration, now time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT INTO source_stats (source, runs, partial, jobs, last_jobs, latency_ms, last_run)
+		VALUES (?, 1, 1, ?, ?, ?, ?)
+		ON CONFLICT(source) DO UPDATE SET
+			runs = runs + 1,
+			partial = partial + 1,
+			jobs = jobs + excluded.jobs,
+			last_jobs = excluded.last_jobs,
+			latency_ms = latency_ms + excluded.latency_ms,
+			last_run = excluded.last_run`,
+		source, jobs, jobs, latency.Milliseconds(), now.UTC())
+	return err
+}
+
+// All returns every source's stats, by source.
+func (s *Store) All() ([]Stats, error) {
+	rows, err := s.db.Query(`SELECT source, runs, jobs, last_jobs, errors, last_error, latency_ms, last_run, last_success, zero_streak, partial
+		FROM source_stats ORDER BY source`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Stats
+	for rows.Next() {
+		var (
+			st      Stats
+			ms      int64
+			success sql.NullTime
+		)
+		if err := rows.Scan(&st.Source, &st.Runs, &st.Jobs, &st.LastJobs, &st.Errors, &st.LastError, &ms,
+			&st.LastRun, &success, &st.ZeroStreak, &st.Partial); err != nil {
+			return nil, err
+		}
+		st.Latency = time.Duration(ms) * time.Millisecond
+		if success.Valid {
+			st.LastSuccess = &success.Time
+		}
+		out = append(out, st)
+	}
+	return out, rows.Err()
+}
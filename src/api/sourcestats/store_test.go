@@ -0,0 +1,78 @@
+package sourcestats
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	js, err := job.OpenStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { js.Close() })
+	s, err := NewStore(js.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestStore_Record(t *testing.T) {
+	s := openTestStore(t)
+	t0 := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	s.Record("hn", 40, nil, 2*time.Second, t0)
+	s.Record("hn", 0, nil, 4*time.Second, t0.Add(time.Hour))
+	s.Record("hn", 0, errors.New("HN story search: 503"), 6*time.Second, t0.Add(2*time.Hour))
+	s.Record("remoteok", 12, nil, time.Second, t0)
+
+	all, err := s.All()
+	if err != nil || len(all) != 2 {
+		t.Fatalf("All = %+v, %v", all, err)
+	}
+	hn := all[0]
+	if hn.Source != "hn" || hn.Runs != 3 || hn.Jobs != 40 || hn.LastJobs != 0 || hn.Errors != 1 ||
+		hn.LastError != "HN story search: 503" || hn.AvgLatency() != 4*time.Second || hn.ZeroStreak != 2 {
+		t.Errorf("hn = %+v", hn)
+	}
+	if hn.LastSuccess == nil || !hn.LastSuccess.Equal(t0.Add(time.Hour)) || !hn.LastRun.Equal(t0.Add(2*time.Hour)) {
+		t.Errorf("hn last success %v, last run %v", hn.LastSuccess, hn.LastRun)
+	}
+	if hn.Flagged(3) || !hn.Flagged(2) {
+		t.Errorf("hn with 2 empty runs: Flagged(3) = %v, Flagged(2) = %v", hn.Flagged(3), hn.Flagged(2))
+	}
+
+	s.Record("hn", 5, nil, time.Second, t0.Add(3*time.Hour))
+	all, _ = s.All()
+	if all[0].ZeroStreak != 0 || all[0].LastError != "HN story search: 503" {
+		t.Errorf("after jobs came back: %+v", all[0])
+	}
+}
+
+func TestStore_RecordPartial(t *testing.T) {
+	s := openTestStore(t)
+	t0 := time.Date(2025, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	s.Record("hn", 0, errors.New("HN story search: 503"), time.Second, t0)
+	s.RecordPartial("hn", 3, 2*time.Second, t0.Add(time.Hour))
+	s.RecordPartial("remoteok", 0, time.Second, t0)
+
+	all, err := s.All()
+	if err != nil || len(all) != 2 {
+		t.Fatalf("All = %+v, %v", all, err)
+	}
+	hn := all[0]
+	if hn.Runs != 2 || hn.Partial != 1 || hn.Jobs != 3 || hn.Errors != 1 || hn.ZeroStreak != 1 ||
+		hn.LastSuccess != nil || !hn.LastRun.Equal(t0.Add(time.Hour)) {
+		t.Errorf("hn = %+v", hn)
+	}
+	if ro := all[1]; ro.Runs != 1 || ro.Partial != 1 || ro.ZeroStreak != 0 || ro.Flagged(1) {
+		t.Errorf("remoteok = %+v; a partial run should not count towards flagging", ro)
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/scraper"
+)
+
+// ScrapeProgressEvent is the data of a "progress" event: source
+// CurrentSource of TotalSources is at Status ("Scraping", "Complete",
+// "Finished", ...). A completed source counts its postings not scraped
+// before and those skipped, also as Counts: "42 new / 310 seen".
+type ScrapeProgressEvent struct {
+	Source        string `json:"source,omitempty"`
+	Status        string `json:"status"`
+	JobsFound     int    `json:"jobs_found"`
+	New           int    `json:"new,omitempty"`
+	Seen          int    `json:"seen,omitempty"`
+	Counts        string `json:"counts,omitempty"`
+	CurrentSource int    `json:"current_source"`
+	TotalSources  int    `json:"total_sources"`
+	ElapsedMS     int64  `json:"elapsed_ms"`
+}
+
+// StreamScrape serves GET /jobs/scrape/stream: it runs a scrape and
+// reports it as Server-Sent Events while it goes. "progress" carries a
+// ScrapeProgressEvent, "job" each job as scraped, "error" an
+// {"error": ...} object, and "done" the final ScrapeStatus once the jobs
+// are saved. ?profile_id=, ?sources=, ?keywords= (both comma-separated),
+// ?max_jobs= and ?force=true mirror the POST /jobs/scrape body. Closing the
+// connection cancels the scrape; jobs found by then are still saved.
+func (h *Handler) StreamScrape(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+	q := r.URL.Query()
+	req := ScrapeRequest{
+		ProfileID:        q.Get("profile_id"),
+		Sources:          splitList(q.Get("sources")),
+		KeywordsOverride: splitList(q.Get("keywords")),
+		Force:            q.Get("force") == "true",
+	}
+	if v := q.Get("max_jobs"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("max_jobs must be a number, got %q", v))
+			return
+		}
+		req.MaxJobs = n
+	}
+
+	is, status, code, err := h.startScrape(r.Context(), req)
+	if err != nil {
+		writeError(w, code, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Once the client has gone the scrape is still drained to the end,
+	// but nothing more is written.
+	send := func(event string, v any) {
+		if r.Context().Err() != nil {
+			return
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+		flusher.Flush()
+	}
+	h.runScrape(is, status, &scrapeEvents{
+		progress: func(p scraper.ScraperProgress) {
+			send("progress", ScrapeProgressEvent{
+				Source:        p.Source,
+				Status:        p.Status,
+				JobsFound:     p.JobsFound,
+				New:           p.New,
+				Seen:          p.Seen,
+				Counts:        p.Counts(),
+				CurrentSource: p.CurrentSource,
+				TotalSources:  p.TotalSources,
+				ElapsedMS:     p.ElapsedTime.Milliseconds(),
+			})
+		},
+		job:  func(j job.Job) { send("job", j) },
+		err:  func(msg string) { send("error", map[string]string{"error": msg}) },
+		done: func(s ScrapeStatus) { send("done", s) },
+	})
+}
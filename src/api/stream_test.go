@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/scraper"
+)
+
+type sseEvent struct {
+	name string
+	data string
+}
+
+func readEvents(t *testing.T, body string) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	var ev sseEvent
+	sc := bufio.NewScanner(strings.NewReader(body))
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			ev.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			ev.data = strings.TrimPrefix(line, "data: ")
+		case line == "" && ev.name != "":
+			events = append(events, ev)
+			ev = sseEvent{}
+		}
+	}
+	return events
+}
+
+func TestStreamScrape(t *testing.T) {
+	h := newScrapeHandler(t)
+	rec := httptest.NewRecorder()
+	h.StreamScrape(rec, httptest.NewRequest(http.MethodGet, "/jobs/scrape/stream?profile_id=alice&sources=fake&max_jobs=3&keywords=go", nil))
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	events := readEvents(t, rec.Body.String())
+	counts := map[string]int{}
+	for _, ev := range events {
+		counts[ev.name]++
+	}
+	if counts["progress"] == 0 || counts["job"] != 3 || counts["done"] != 1 || counts["error"] != 0 {
+		t.Fatalf("events = %v", counts)
+	}
+
+	var p ScrapeProgressEvent
+	if err := json.Unmarshal([]byte(events[0].data), &p); err != nil || p.Source != "Fake fake" || p.TotalSources != 1 {
+		t.Errorf("first progress = %+v, %v", p, err)
+	}
+	last := events[len(events)-1]
+	var status ScrapeStatus
+	if err := json.Unmarshal([]byte(last.data), &status); err != nil || last.name != "done" {
+		t.Fatalf("last event %s: %s", last.name, last.data)
+	}
+	if status.State != "done" || status.JobsFound != 3 || status.Config.Sources[0] != "fake" {
+		t.Errorf("done = %+v", status)
+	}
+	if saved, _ := h.store.All(); len(saved) != 3 {
+		t.Errorf("saved %d jobs, want 3", len(saved))
+	}
+}
+
+func TestStreamScrape_Errors(t *testing.T) {
+	h := newScrapeHandler(t)
+	h.sources = append(h.sources, scraper.NewScraperSource("broken", "Broken", func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+		return nil, context.DeadlineExceeded
+	}))
+	rec := httptest.NewRecorder()
+	h.StreamScrape(rec, httptest.NewRequest(http.MethodGet, "/jobs/scrape/stream?sources=broken", nil))
+	var got []string
+	for _, ev := range readEvents(t, rec.Body.String()) {
+		if ev.name == "error" {
+			got = append(got, ev.data)
+		}
+	}
+	if len(got) != 1 || !strings.Contains(got[0], `"error":"error scraping Broken`) {
+		t.Errorf("error events = %v", got)
+	}
+
+	rec = httptest.NewRecorder()
+	h.StreamScrape(rec, httptest.NewRequest(http.MethodGet, "/jobs/scrape/stream?profile_id=bob", nil))
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "unknown profile") {
+		t.Errorf("unknown profile: %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestStreamScrape_CancelOnDisconnect(t *testing.T) {
+	h := newScrapeHandler(t)
+	started := make(chan struct{})
+	h.sources = []scraper.ScraperSource{
+		fakeSource("fake", 2),
+		scraper.NewScraperSource("slow", "Slow", func(ctx context.Context, keywords []string, location string) ([]job.Job, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/jobs/scrape/stream?profile_id=alice&keywords=go", nil).WithContext(ctx)
+	finished := make(chan struct{})
+	go func() {
+		h.StreamScrape(httptest.NewRecorder(), req)
+		close(finished)
+	}()
+
+	<-started
+	cancel()
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler kept running after the client left")
+	}
+	status := waitForScrape(t, h)
+	if status.State != "cancelled" {
+		t.Errorf("state = %q, want cancelled", status.State)
+	}
+	if saved, _ := h.store.All(); len(saved) != 2 {
+		t.Errorf("saved %d jobs found before the cancel, want 2", len(saved))
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/tracking"
+)
+
+// EnableTracking serves the open and click endpoints, recording hits in
+// apps. Without it both answer 404.
+func (h *Handler) EnableTracking(apps *application.Store, s *tracking.Signer) {
+	h.appStore, h.signer = apps, s
+}
+
+// TrackOpen serves /t/open/{token}.gif: a 1x1 pixel, recording the open.
+func (h *Handler) TrackOpen(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/t/open/"), ".gif")
+	if _, ok := h.trackHit(r, token, application.TrackOpen); !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "image/gif")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(tracking.Pixel)
+}
+
+// TrackClick serves /t/click/{token}: it records the click and redirects
+// to the URL stored for the token. The target never comes from the
+// request, so the endpoint cannot be used as an open redirect.
+func (h *Handler) TrackClick(w http.ResponseWriter, r *http.Request) {
+	l, ok := h.trackHit(r, strings.TrimPrefix(r.URL.Path, "/t/click/"), application.TrackClick)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	http.Redirect(w, r, l.URL, http.StatusFound)
+}
+
+// JobTracking is what GET /jobs/{id}/tracking returns.
+type JobTracking struct {
+	JobID  string                    `json:"job_id"`
+	Opens  int                       `json:"opens"`
+	Clicks int                       `json:"clicks"`
+	Events []application.TrackingHit `json:"events"`
+}
+
+// JobTracking serves /jobs/{id}/tracking: every open and click recorded
+// on the job's emails, oldest first. Repeated opens are separate events.
+func (h *Handler) JobTracking(w http.ResponseWriter, r *http.Request, jobID string) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if h.signer == nil {
+		writeError(w, http.StatusNotFound, "tracking is not enabled")
+		return
+	}
+	if jobID == "" || strings.Contains(jobID, "/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if _, err := h.store.ByIDContext(r.Context(), jobID); errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("no job %q", jobID))
+		return
+	}
+	hits, err := h.appStore.JobHits(jobID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	out := JobTracking{JobID: jobID, Events: hits}
+	if out.Events == nil {
+		out.Events = []application.TrackingHit{}
+	}
+	for _, hit := range hits {
+		if hit.Kind == application.TrackOpen {
+			out.Opens++
+		} else {
+			out.Clicks++
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
+
+// trackHit resolves token to a link of the given kind and records the hit
+// unless it looks automated. A failure to record still serves the pixel
+// or redirect.
+func (h *Handler) trackHit(r *http.Request, token string, kind application.TrackKind) (*application.TrackingLink, bool) {
+	if h.signer == nil {
+		return nil, false
+	}
+	id, err := h.signer.ID(token)
+	if err != nil {
+		return nil, false
+	}
+	l, err := h.appStore.TrackingLinkByID(id)
+	if err != nil || l.Kind != kind {
+		return nil, false
+	}
+	now, ua := time.Now(), r.UserAgent()
+	if !tracking.Ignore(*l, now, ua) {
+		h.appStore.RecordHit(l.ID, now, tracking.Family(ua))
+	}
+	return l, true
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/job"
+	"sprayer/src/api/tracking"
+)
+
+const browserUA = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko)"
+
+func newTrackingHandler(t *testing.T) (*Handler, *application.Store, *tracking.Signer) {
+	t.Helper()
+	h := newReadyHandler(t, filepath.Join(t.TempDir(), "jobs.db"))
+	apps, err := application.NewStore(h.store.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := tracking.NewSigner([]byte("test-secret"))
+	h.EnableTracking(apps, signer)
+	return h, apps, signer
+}
+
+// sentLink stores a link made long enough ago that hits count.
+func sentLink(t *testing.T, apps *application.Store, kind application.TrackKind, url string) *application.TrackingLink {
+	t.Helper()
+	l := &application.TrackingLink{JobID: "job-1", ProfileID: "alice", Kind: kind, URL: url, CreatedAt: time.Now().Add(-time.Hour)}
+	if err := apps.AddTrackingLink(l); err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+func track(h *Handler, path, ua string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("User-Agent", ua)
+	rec := httptest.NewRecorder()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/t/open/", h.TrackOpen)
+	mux.HandleFunc("/t/click/", h.TrackClick)
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTrackClick_RedirectsOnlyToStoredURL(t *testing.T) {
+	h, apps, signer := newTrackingHandler(t)
+	click := sentLink(t, apps, application.TrackClick, "https://github.com/alice")
+	open := sentLink(t, apps, application.TrackOpen, "")
+
+	rec := track(h, "/t/click/"+signer.Token(click.ID)+"?url=https://evil.example", browserUA)
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "https://github.com/alice" {
+		t.Fatalf("got %d to %q, want 302 to the stored URL", rec.Code, rec.Header().Get("Location"))
+	}
+
+	for name, path := range map[string]string{
+		"open token as click": "/t/click/" + signer.Token(open.ID),
+		"unsigned ID":         "/t/click/2",
+		"foreign key":         "/t/click/" + tracking.NewSigner([]byte("other")).Token(click.ID),
+		"unknown link":        "/t/click/" + signer.Token(999),
+	} {
+		if rec := track(h, path, browserUA); rec.Code != http.StatusNotFound {
+			t.Errorf("%s: got %d, want 404", name, rec.Code)
+		}
+	}
+}
+
+func TestTrackOpen_RecordsHumanHitsOnly(t *testing.T) {
+	h, apps, signer := newTrackingHandler(t)
+	open := sentLink(t, apps, application.TrackOpen, "")
+	fresh := &application.TrackingLink{JobID: "job-1", ProfileID: "alice", Kind: application.TrackOpen}
+	if err := apps.AddTrackingLink(fresh); err != nil {
+		t.Fatal(err)
+	}
+	a := &application.Application{JobID: "job-1", ProfileID: "alice", Method: application.MethodEmail}
+	if err := apps.Add(a); err != nil {
+		t.Fatal(err)
+	}
+
+	// Scanner hits, and any hit right after sending, still get the pixel
+	// but are not recorded.
+	for _, c := range []struct{ token, ua string }{
+		{signer.Token(open.ID), "Mozilla/5.0 (compatible; bingbot/2.0)"},
+		{signer.Token(fresh.ID), browserUA},
+	} {
+		rec := track(h, "/t/open/"+c.token+".gif", c.ua)
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/gif" {
+			t.Fatalf("got %d %q, want the pixel", rec.Code, rec.Header().Get("Content-Type"))
+		}
+	}
+	if hits, _ := apps.Hits("job-1", "alice"); len(hits) != 0 {
+		t.Fatalf("recorded %d automated hits", len(hits))
+	}
+
+	track(h, "/t/open/"+signer.Token(open.ID)+".gif", browserUA)
+	hits, err := apps.Hits("job-1", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || hits[0].Kind != application.TrackOpen || hits[0].UAFamily != "Apple Mail" {
+		t.Fatalf("hits = %+v", hits)
+	}
+	got, err := apps.ByID(a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.OpenedAt == nil || got.ClickedAt != nil {
+		t.Errorf("OpenedAt = %v, ClickedAt = %v", got.OpenedAt, got.ClickedAt)
+	}
+}
+
+func TestJobTracking_ListsEveryHit(t *testing.T) {
+	h, apps, signer := newTrackingHandler(t)
+	if err := h.store.Save([]job.Job{{ID: "job-1", Title: "Go Engineer"}}); err != nil {
+		t.Fatal(err)
+	}
+	open := sentLink(t, apps, application.TrackOpen, "")
+	click := sentLink(t, apps, application.TrackClick, "https://github.com/alice")
+	track(h, "/t/open/"+signer.Token(open.ID)+".gif", browserUA)
+	track(h, "/t/open/"+signer.Token(open.ID)+".gif", browserUA)
+	track(h, "/t/click/"+signer.Token(click.ID), browserUA)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.Job(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+	rec := get("/jobs/job-1/tracking")
+	var got JobTracking
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("got %d, %v", rec.Code, err)
+	}
+	if got.Opens != 2 || got.Clicks != 1 || len(got.Events) != 3 || got.Events[2].URL != "https://github.com/alice" {
+		t.Errorf("tracking = %+v", got)
+	}
+	if counts, err := apps.OpenCounts(); err != nil || counts["job-1"] != 2 {
+		t.Errorf("OpenCounts = %v, %v", counts, err)
+	}
+
+	if rec := get("/jobs/nope/tracking"); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown job: got %d, want 404", rec.Code)
+	}
+}
+
+func TestTrack_DisabledIs404(t *testing.T) {
+	h := newReadyHandler(t, filepath.Join(t.TempDir(), "jobs.db"))
+	if rec := track(h, "/t/open/anything.gif", browserUA); rec.Code != http.StatusNotFound {
+		t.Errorf("got %d, want 404 with tracking off", rec.Code)
+	}
+	rec := httptest.NewRecorder()
+	h.Job(rec, httptest.NewRequest(http.MethodGet, "/jobs/job-1/tracking", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("job tracking: got %d, want 404 with tracking off", rec.Code)
+	}
+}
@@ -0,0 +1,186 @@
+// Package tracking instruments application emails with open and click
+// tracking URLs, served by the API server. It is off unless a profile
+// opts in and a signing secret and public URL are configured.
+package tracking
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/profile"
+)
+
+// EnvSecret is the key tokens are signed with.
+const EnvSecret = "SPRAYER_TRACKING_SECRET"
+
+// EnvBaseURL is the public URL of the API server serving /t/.
+const EnvBaseURL = "SPRAYER_TRACKING_URL"
+
+// SendGrace is how long after sending hits are ignored: mail servers and
+// security scanners fetch images and links on delivery, before anyone
+// reads the message.
+const SendGrace = 10 * time.Second
+
+// ErrBadToken is returned for tokens that were not signed by us.
+var ErrBadToken = errors.New("invalid tracking token")
+
+// macSize is how much of the HMAC a token carries.
+const macSize = 12
+
+// Signer turns link IDs into tokens that cannot be guessed or enumerated
+// without the key.
+type Signer struct {
+	key []byte
+}
+
+// NewSigner returns a Signer for key.
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// Token encodes id with its signature, URL-safe.
+func (s *Signer) Token(id int64) string {
+	buf := make([]byte, 8, 8+macSize)
+	binary.BigEndian.PutUint64(buf, uint64(id))
+	return base64.RawURLEncoding.EncodeToString(append(buf, s.mac(buf)...))
+}
+
+// ID decodes a token made by Token.
+func (s *Signer) ID(token string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) != 8+macSize {
+		return 0, ErrBadToken
+	}
+	if !hmac.Equal(raw[8:], s.mac(raw[:8])) {
+		return 0, ErrBadToken
+	}
+	return int64(binary.BigEndian.Uint64(raw[:8])), nil
+}
+
+func (s *Signer) mac(b []byte) []byte {
+	m := hmac.New(sha256.New, s.key)
+	m.Write(b)
+	return m.Sum(nil)[:macSize]
+}
+
+// SignerFromEnv returns a Signer for EnvSecret, or nil if it is unset.
+func SignerFromEnv() *Signer {
+	secret := os.Getenv(EnvSecret)
+	if secret == "" {
+		return nil
+	}
+	return NewSigner([]byte(secret))
+}
+
+// Tracker rewrites email bodies to go through the tracking endpoints.
+type Tracker struct {
+	BaseURL string
+	Signer  *Signer
+	Links   LinkStore
+}
+
+// LinkStore saves the links a Tracker hands out.
+type LinkStore interface {
+	AddTrackingLink(l *application.TrackingLink) error
+}
+
+// ForProfile returns a Tracker when p opted in and tracking is
+// configured, and nil otherwise.
+func ForProfile(p profile.Profile, links LinkStore) *Tracker {
+	base := strings.TrimRight(os.Getenv(EnvBaseURL), "/")
+	signer := SignerFromEnv()
+	if !p.Tracking || base == "" || signer == nil {
+		return nil
+	}
+	return &Tracker{BaseURL: base, Signer: signer, Links: links}
+}
+
+var linkPattern = regexp.MustCompile(`https?://[^\s<>"'()\[\]]+`)
+
+// Instrument replaces the links in body with click-tracking URLs and
+// returns it with the URL of an open-tracking pixel.
+func (t *Tracker) Instrument(jobID, profileID, body string) (string, string, error) {
+	open := &application.TrackingLink{JobID: jobID, ProfileID: profileID, Kind: application.TrackOpen}
+	if err := t.Links.AddTrackingLink(open); err != nil {
+		return "", "", err
+	}
+	pixel := fmt.Sprintf("%s/t/open/%s.gif", t.BaseURL, t.Signer.Token(open.ID))
+
+	var err error
+	body = linkPattern.ReplaceAllStringFunc(body, func(u string) string {
+		trail := ""
+		for strings.ContainsAny(u[len(u)-1:], ".,;:!?") {
+			u, trail = u[:len(u)-1], u[len(u)-1:]+trail
+		}
+		if err != nil || strings.HasPrefix(u, t.BaseURL+"/t/") {
+			return u + trail
+		}
+		l := &application.TrackingLink{JobID: jobID, ProfileID: profileID, Kind: application.TrackClick, URL: u}
+		if err = t.Links.AddTrackingLink(l); err != nil {
+			return u + trail
+		}
+		return fmt.Sprintf("%s/t/click/%s", t.BaseURL, t.Signer.Token(l.ID)) + trail
+	})
+	return body, pixel, err
+}
+
+// scanners are user agents of link checkers, mail security gateways and
+// bots that fetch URLs without a person reading anything.
+var scanners = regexp.MustCompile(`(?i)bot\b|crawler|spider|scan|preview|proofpoint|mimecast|barracuda|urldefense|safelinks|forcepoint|symantec|trendmicro|sophos|curl/|wget/|python-|go-http-client|java/|okhttp|headless|slurp`)
+
+// IsBot reports whether ua belongs to an automated fetcher. Gmail's and
+// Yahoo's image proxies are not bots: they fetch when the message is
+// opened.
+func IsBot(ua string) bool {
+	return strings.TrimSpace(ua) == "" || scanners.MatchString(ua)
+}
+
+// Ignore reports whether a hit on l at the given time should not count:
+// bots, and anything within SendGrace of the link being made.
+func Ignore(l application.TrackingLink, at time.Time, ua string) bool {
+	return IsBot(ua) || at.Sub(l.CreatedAt) < SendGrace
+}
+
+// families maps user-agent substrings to a short client name, first
+// match wins.
+var families = []struct{ needle, name string }{
+	{"googleimageproxy", "Gmail"},
+	{"yahoomailproxy", "Yahoo Mail"},
+	{"outlook", "Outlook"},
+	{"microsoft office", "Outlook"},
+	{"thunderbird", "Thunderbird"},
+	{"edg/", "Edge"},
+	{"firefox", "Firefox"},
+	{"chrome", "Chrome"},
+	{"safari", "Safari"},
+	{"iphone", "Apple Mail"},
+	{"macintosh", "Apple Mail"},
+}
+
+// Family reduces a user agent to the client family kept with a hit; the
+// full string is not stored.
+func Family(ua string) string {
+	lower := strings.ToLower(ua)
+	for _, f := range families {
+		if strings.Contains(lower, f.needle) {
+			return f.name
+		}
+	}
+	return "Other"
+}
+
+// Pixel is a transparent 1x1 GIF.
+var Pixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
@@ -0,0 +1,100 @@
+package tracking
+
+import (
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"sprayer/src/api/application"
+)
+
+func TestSigner_RoundTrip(t *testing.T) {
+	s := NewSigner([]byte("secret"))
+	f := func(id int64) bool {
+		got, err := s.ID(s.Token(id))
+		return err == nil && got == id
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSigner_RejectsForeignTokens(t *testing.T) {
+	s := NewSigner([]byte("secret"))
+	tok := s.Token(42)
+
+	if _, err := NewSigner([]byte("other")).ID(tok); err != ErrBadToken {
+		t.Errorf("token from another key: err = %v", err)
+	}
+	// Flip a character of the ID part: the signature no longer matches.
+	tampered := []byte(tok)
+	tampered[3] ^= 1
+	for _, bad := range []string{string(tampered), "", "42", tok[:len(tok)-2], tok + "AA"} {
+		if _, err := s.ID(bad); err != ErrBadToken {
+			t.Errorf("ID(%q) err = %v, want ErrBadToken", bad, err)
+		}
+	}
+	if s.Token(1) == s.Token(2) {
+		t.Error("tokens for different IDs collide")
+	}
+}
+
+type memLinks []application.TrackingLink
+
+func (m *memLinks) AddTrackingLink(l *application.TrackingLink) error {
+	l.ID = int64(len(*m) + 1)
+	*m = append(*m, *l)
+	return nil
+}
+
+func TestTracker_Instrument(t *testing.T) {
+	var links memLinks
+	tr := &Tracker{BaseURL: "https://t.example.com", Signer: NewSigner([]byte("k")), Links: &links}
+	body := "My work: https://github.com/alice. Portfolio (https://alice.dev/work) and mail me."
+
+	got, pixel, err := tr.Instrument("job-1", "alice", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "github.com") || strings.Contains(got, "alice.dev") {
+		t.Errorf("links not rewritten: %s", got)
+	}
+	if !strings.Contains(got, "/t/click/") || !strings.Contains(got, ". Portfolio (https://t.example.com/t/click/") {
+		t.Errorf("trailing punctuation not kept outside the link: %s", got)
+	}
+	if !strings.HasPrefix(pixel, "https://t.example.com/t/open/") || !strings.HasSuffix(pixel, ".gif") {
+		t.Errorf("pixel = %q", pixel)
+	}
+	if len(links) != 3 || links[0].Kind != application.TrackOpen ||
+		links[1].URL != "https://github.com/alice" || links[2].URL != "https://alice.dev/work" {
+		t.Errorf("links = %+v", links)
+	}
+}
+
+func TestIgnore(t *testing.T) {
+	sent := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	l := application.TrackingLink{CreatedAt: sent}
+	gmail := "Mozilla/5.0 (Windows NT 5.1; rv:11.0) Gecko Firefox/11.0 (via ggpht.com GoogleImageProxy)"
+
+	cases := []struct {
+		ua   string
+		at   time.Duration
+		want bool
+	}{
+		{gmail, time.Hour, false},
+		{gmail, 2 * time.Second, true}, // fetched on delivery
+		{"Mozilla/5.0 (compatible; Googlebot/2.1)", time.Hour, true},
+		{"Proofpoint URL Defense", time.Hour, true},
+		{"python-requests/2.31", time.Hour, true},
+		{"", time.Hour, true},
+	}
+	for _, c := range cases {
+		if got := Ignore(l, sent.Add(c.at), c.ua); got != c.want {
+			t.Errorf("Ignore(%q, +%s) = %v, want %v", c.ua, c.at, got, c.want)
+		}
+	}
+	if f := Family(gmail); f != "Gmail" {
+		t.Errorf("Family = %q, want Gmail", f)
+	}
+}
@@ -0,0 +1,331 @@
+// Package traps detects red flags in job descriptions with named rules:
+// the built-in prompt-injection grammar plus shareable YAML rule files.
+//
+// A rule file holds a list of rules:
+//
+//	rules:
+//	  - name: equity-only
+//	    description: Pays in equity instead of salary
+//	    severity: high
+//	    language: en
+//	    pattern: '\b(equity|stock)[- ]only\b'
+//	  - name: unpaid-take-home
+//	    severity: medium
+//	    phrases: ["unpaid take-home", "unpaid assignment"]
+//	  - name: prompt-injection
+//	    disabled: true
+//
+// Rules are read from DataDir/traps.yaml, then from the files of a
+// directory in name order. A rule replaces any earlier rule of the same
+// name, built-ins included, and disabled: true removes it.
+package traps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/parse"
+)
+
+// EnvDir names the directory rule files are loaded from, DataDir/traps
+// by default.
+const EnvDir = "SPRAYER_TRAP_RULES"
+
+// Dir returns the configured rules directory.
+func Dir() string {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir
+	}
+	return filepath.Join(job.DataDir(), "traps")
+}
+
+// RulesFile returns the single rule file read before Dir.
+func RulesFile() string {
+	return filepath.Join(job.DataDir(), "traps.yaml")
+}
+
+// Severity ranks how bad a hit is; jobs record it with each match.
+type Severity = job.TrapSeverity
+
+const (
+	Low    = job.TrapLow
+	Medium = job.TrapMedium
+	High   = job.TrapHigh
+)
+
+// Rule is one named red flag. It matches either a regular expression or
+// any of a list of phrases, both case-insensitively.
+type Rule struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description,omitempty"`
+	Severity    Severity `yaml:"severity,omitempty"`
+	Language    string   `yaml:"language,omitempty"`
+	Pattern     string   `yaml:"pattern,omitempty"`
+	Phrases     []string `yaml:"phrases,omitempty"`
+	Disabled    bool     `yaml:"disabled,omitempty"`
+
+	// Source is the file the rule came from, or "built-in".
+	Source string `yaml:"-"`
+
+	// find replaces Pattern and Phrases for built-ins backed by code.
+	find func(string) []string
+}
+
+// File is the on-disk rule file format.
+type File struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Builtin returns the rules that apply with no files loaded.
+func Builtin() []Rule {
+	return []Rule{{
+		Name:        "prompt-injection",
+		Description: "Instructions aimed at an LLM writing the application",
+		Severity:    High,
+		Source:      "built-in",
+		find:        parse.CheckForTraps,
+	}}
+}
+
+// Parse decodes a rule file, rejecting unknown keys so a typo does not
+// silently drop a field.
+func Parse(data []byte, source string) ([]Rule, error) {
+	var f File
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	for i := range f.Rules {
+		f.Rules[i].Source = source
+	}
+	return f.Rules, nil
+}
+
+// LoadDir reads every .yaml and .yml file in dir, in name order. A
+// missing directory holds no rules.
+func LoadDir(dir string) ([][]Rule, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if !e.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var files [][]Rule
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		rules, err := Parse(data, path)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, rules)
+	}
+	return files, nil
+}
+
+// Merge layers files over base. A rule replaces the earlier one of the
+// same name in its place, a new name is appended, and a disabled rule
+// removes its name. The result does not depend on map order.
+func Merge(base []Rule, files ...[]Rule) []Rule {
+	merged := append([]Rule(nil), base...)
+	index := func(name string) int {
+		for i, r := range merged {
+			if r.Name == name {
+				return i
+			}
+		}
+		return -1
+	}
+	for _, rules := range files {
+		for _, r := range rules {
+			i := index(r.Name)
+			switch {
+			case r.Disabled && i >= 0:
+				merged = append(merged[:i], merged[i+1:]...)
+			case r.Disabled:
+			case i >= 0:
+				merged[i] = r
+			default:
+				merged = append(merged, r)
+			}
+		}
+	}
+	return merged
+}
+
+// Problem is one lint finding.
+type Problem struct {
+	Rule    string
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Rule == "" {
+		return p.Message
+	}
+	return p.Rule + ": " + p.Message
+}
+
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z]{2})?$`)
+
+// Lint checks rules as written in one file: every rule is named once,
+// has a known severity, and matches something a compiled pattern can
+// find.
+func Lint(rules []Rule) []Problem {
+	var problems []Problem
+	seen := make(map[string]bool)
+	for i, r := range rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+			problems = append(problems, Problem{name, "missing name"})
+		} else if seen[name] {
+			problems = append(problems, Problem{name, "defined twice in one file"})
+		}
+		seen[r.Name] = true
+
+		switch r.Severity {
+		case "", Low, Medium, High:
+		default:
+			problems = append(problems, Problem{name, fmt.Sprintf("severity %q is not low, medium or high", r.Severity)})
+		}
+		if r.Language != "" && !languageCode.MatchString(r.Language) {
+			problems = append(problems, Problem{name, fmt.Sprintf("language %q is not a code like en or pt-BR", r.Language)})
+		}
+		if r.Disabled {
+			continue
+		}
+		switch {
+		case r.Pattern == "" && len(r.Phrases) == 0:
+			problems = append(problems, Problem{name, "needs a pattern or phrases"})
+		case r.Pattern != "" && len(r.Phrases) > 0:
+			problems = append(problems, Problem{name, "has both a pattern and phrases"})
+		case r.Pattern != "":
+			if re, err := regexp.Compile(r.Pattern); err != nil {
+				problems = append(problems, Problem{name, "pattern: " + err.Error()})
+			} else if re.MatchString("") {
+				problems = append(problems, Problem{name, "pattern matches empty text"})
+			}
+		}
+		for _, p := range r.Phrases {
+			if strings.TrimSpace(p) == "" {
+				problems = append(problems, Problem{name, "empty phrase"})
+			}
+		}
+	}
+	return problems
+}
+
+// Hit is a rule firing on some text.
+type Hit struct {
+	Rule  Rule
+	Match string
+}
+
+// Set is a merged, compiled list of rules.
+type Set struct {
+	rules []Rule
+	finds []func(string) []string
+}
+
+// Compile prepares rules for matching. It fails on the problems Lint
+// reports, naming the rule's file.
+func Compile(rules []Rule) (*Set, error) {
+	s := &Set{}
+	for _, r := range rules {
+		find := r.find
+		if find == nil {
+			if problems := Lint([]Rule{r}); len(problems) > 0 {
+				return nil, fmt.Errorf("%s: %s", r.Source, problems[0])
+			}
+			find = finder(r)
+		}
+		if r.Severity == "" {
+			r.Severity = Medium
+		}
+		s.rules = append(s.rules, r)
+		s.finds = append(s.finds, find)
+	}
+	return s, nil
+}
+
+// finder matches a linted rule case-insensitively.
+func finder(r Rule) func(string) []string {
+	expr := r.Pattern
+	if expr == "" {
+		quoted := make([]string, len(r.Phrases))
+		for i, p := range r.Phrases {
+			quoted[i] = regexp.QuoteMeta(strings.TrimSpace(p))
+		}
+		expr = strings.Join(quoted, "|")
+	}
+	re := regexp.MustCompile("(?i)" + expr)
+	return func(text string) []string { return re.FindAllString(text, -1) }
+}
+
+// Load merges RulesFile and the rule files in Dir over the built-ins.
+func Load() (*Set, error) {
+	var files [][]Rule
+	if data, err := os.ReadFile(RulesFile()); err == nil {
+		rules, err := Parse(data, RulesFile())
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, rules)
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	more, err := LoadDir(Dir())
+	if err != nil {
+		return nil, err
+	}
+	return Compile(Merge(Builtin(), append(files, more...)...))
+}
+
+// Rules returns the rules in s, in merge order.
+func (s *Set) Rules() []Rule {
+	return append([]Rule(nil), s.rules...)
+}
+
+// Find returns every hit in text, in rule order.
+func (s *Set) Find(text string) []Hit {
+	var hits []Hit
+	for i, find := range s.finds {
+		for _, m := range find(text) {
+			hits = append(hits, Hit{Rule: s.rules[i], Match: m})
+		}
+	}
+	return hits
+}
+
+// Matches returns the rules that fire on text, once each with their
+// first hit, the form job.FlagTrapsWith records on a job.
+func (s *Set) Matches(text string) []job.TrapMatch {
+	var out []job.TrapMatch
+	for i, find := range s.finds {
+		if hits := find(text); len(hits) > 0 {
+			r := s.rules[i]
+			out = append(out, job.TrapMatch{Name: r.Name, Severity: r.Severity, Match: hits[0]})
+		}
+	}
+	return out
+}
@@ -0,0 +1,317 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/brand"
+	"sprayer/src/api/export"
+	"sprayer/src/api/job"
+	"sprayer/src/api/notify"
+	"sprayer/src/api/profile"
+	"sprayer/src/api/tracking"
+)
+
+func (c *CLI) handleApplications() {
+	if len(os.Args) > 2 && os.Args[2] == "show" {
+		c.handleApplicationShow(os.Args[3:])
+		return
+	}
+
+	fs := flag.NewFlagSet("applications", flag.ExitOnError)
+	report := fs.Bool("report", false, "Generate a chronological report")
+	from := fs.String("from", "", "Start date (YYYY-MM-DD)")
+	to := fs.String("to", "", "End date (YYYY-MM-DD, inclusive)")
+	format := fs.String("format", "markdown", "Report format: pdf, csv or markdown")
+	out := fs.String("out", "", "Output file (default stdout; required for pdf)")
+	redact := fs.Bool("redact-notes", false, "Leave personal notes out of the report")
+	withdraw := fs.Bool("withdraw", false, "Withdraw the selected applications")
+	statuses := fs.String("status", "", "With --withdraw: statuses to cover, e.g. interview,sent")
+	except := fs.String("except", "", "With --withdraw: comma-separated companies to keep")
+	reason := fs.String("reason", "", "With --withdraw: reason given in the email and kept on record")
+	send := fs.Bool("send", false, "With --withdraw: send via SMTP instead of saving drafts")
+	yes := fs.Bool("yes", false, "With --withdraw: do not ask for confirmation")
+	asJSON := fs.Bool("json", false, "Print the applications as JSON instead of a list")
+	fs.Parse(os.Args[2:])
+
+	if *withdraw {
+		sts, err := application.ParseStatuses(*statuses)
+		if err != nil {
+			fmt.Printf("Invalid --status: %v\n", err)
+			return
+		}
+		sel := application.Selection{Statuses: sts}
+		for _, co := range strings.Split(*except, ",") {
+			if co = strings.TrimSpace(co); co != "" {
+				sel.Except = append(sel.Except, co)
+			}
+		}
+		if err := c.withdraw(os.Stdin, sel, *reason, *send, *yes); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+		return
+	}
+
+	fromT, err := parseDay(*from)
+	if err != nil {
+		fmt.Printf("Invalid --from: %v\n", err)
+		return
+	}
+	toT, err := parseDay(*to)
+	if err != nil {
+		fmt.Printf("Invalid --to: %v\n", err)
+		return
+	}
+	if !toT.IsZero() {
+		toT = toT.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	apps, err := c.appStore.Between(fromT, toT)
+	if err != nil {
+		fmt.Printf("Error loading applications: %v\n", err)
+		return
+	}
+
+	if *asJSON && !*report {
+		writeJSON(os.Stdout, apps)
+		return
+	}
+	if !*report {
+		for _, a := range apps {
+			fmt.Printf("%s  %-10s %s @ %s (%s)%s\n", a.AppliedAt.Format("2006-01-02"), a.Status, a.Title, a.Company, a.Method, engagement(a))
+		}
+		return
+	}
+
+	r := export.NewReport(apps, fromT, toT)
+	opts := export.Options{RedactNotes: *redact, Redact: c.redactor()}
+
+	if *format == "pdf" {
+		if *out == "" {
+			fmt.Println("Error: --out is required for pdf reports")
+			return
+		}
+		br, err := brand.FromEnv()
+		if err != nil {
+			fmt.Printf("Error in brand settings: %v\n", err)
+			return
+		}
+		opts.Brand = &br
+		if err := export.WritePDF(*out, r, opts); err != nil {
+			fmt.Printf("Error writing PDF: %v\n", err)
+			return
+		}
+		fmt.Printf("Report written to %s\n", *out)
+		return
+	}
+
+	var write func(io.Writer, export.Report, export.Options) error
+	switch *format {
+	case "csv":
+		write = export.WriteCSV
+	case "markdown", "md":
+		write = export.WriteMarkdown
+	default:
+		fmt.Printf("Unknown format %q (want pdf, csv or markdown)\n", *format)
+		return
+	}
+	if *out == "" {
+		err = write(os.Stdout, r, opts)
+	} else {
+		err = writeReport(*out, write, r, opts)
+	}
+	if err != nil {
+		fmt.Printf("Error writing report: %v\n", err)
+	}
+}
+
+// writeReport writes r to a file at path, reporting a failed close as a
+// failed write since the report may not be on disk.
+func writeReport(path string, write func(io.Writer, export.Report, export.Options) error, r export.Report, opts export.Options) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f, r, opts); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func parseDay(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
+
+// recordApplication logs a submitted application with what was sent, if
+// known, keeps the letter written for duplicate checks, and schedules the
+// follow-up; failures are reported but never undo the send.
+func (c *CLI) recordApplication(j job.Job, p profile.Profile, method application.Method, body string, sent *application.Sent) {
+	now := time.Now()
+	if sent != nil && sent.SentAt.IsZero() {
+		sent.SentAt = now
+	}
+	a := &application.Application{
+		JobID:     j.ID,
+		ProfileID: p.ID,
+		Company:   j.Company,
+		Title:     j.Title,
+		Method:    method,
+		AppliedAt: now,
+		Posting:   application.SnapshotPosting(c.withDescription(j), now),
+		Sent:      sent,
+	}
+	if err := c.appStore.Add(a); err != nil {
+		fmt.Printf("Warning: could not record application: %v\n", err)
+		return
+	}
+	if err := c.store.MarkApplied(j.ID, now, p.FollowUpAfter()); err != nil {
+		fmt.Printf("Warning: could not schedule the follow-up: %v\n", err)
+	}
+	if sent != nil {
+		notify.Notify(notify.ApplicationSent(j, sent.To))
+	}
+	if body == "" {
+		return
+	}
+	if err := c.appStore.AddLetter(&application.Letter{ApplicationID: a.ID, Company: j.Company, Body: body, SentAt: now}); err != nil {
+		fmt.Printf("Warning: could not keep the letter for duplicate checks: %v\n", err)
+	}
+}
+
+// lintEchoes warns when body repeats much of a recently sent letter,
+// quoting the longest shared passages so they can be rephrased. who, if
+// set, names the letter being checked. Warnings go to w.
+func (c *CLI) lintEchoes(w io.Writer, body, who string) {
+	echoes, err := c.appStore.Echoes(body, application.EchoThreshold())
+	if err != nil {
+		fmt.Fprintf(w, "Warning: could not compare with sent letters: %v\n", err)
+		return
+	}
+	if who != "" {
+		who += ": "
+	}
+	for _, e := range echoes {
+		fmt.Fprintf(w, "! %s%.0f%% like the letter sent to %s on %s\n",
+			who, 100*e.Similarity, e.Letter.Company, e.Letter.SentAt.Local().Format("2006-01-02"))
+		for _, passage := range e.Passages {
+			fmt.Fprintf(w, "    %q\n", passage)
+		}
+	}
+}
+
+func (c *CLI) handleApplicationShow(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Error: application ID is required")
+		return
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid application ID %q\n", args[0])
+		return
+	}
+	if err := c.showApplication(os.Stdout, id); err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
+// showApplication renders an application with the posting as it read when
+// applying, followed by the email thread.
+func (c *CLI) showApplication(w io.Writer, id int64) error {
+	a, err := c.appStore.ByID(id)
+	if err != nil {
+		return fmt.Errorf("application %d: %w", id, err)
+	}
+	thread, err := c.appStore.Thread(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "#%d %s @ %s\n", a.ID, a.Title, a.Company)
+	fmt.Fprintf(w, "Status: %s (%s, applied %s)\n", a.Status, a.Method, a.AppliedAt.Format("2006-01-02"))
+
+	if p := a.Posting; p != nil {
+		fmt.Fprintf(w, "\n--- posting as of %s ---\n", p.CapturedAt.Format("2006-01-02"))
+		fmt.Fprintf(w, "%s\n", p.Title)
+		for _, field := range [][2]string{{"Location", p.Location}, {"Salary", p.Salary}, {"URL", p.URL}} {
+			if field[1] != "" {
+				fmt.Fprintf(w, "%s: %s\n", field[0], field[1])
+			}
+		}
+		fmt.Fprintf(w, "\n%s\n", p.Description)
+	} else {
+		fmt.Fprintln(w, "\n(no posting snapshot; applied before snapshots were kept)")
+	}
+
+	if sent := a.Sent; sent != nil {
+		fmt.Fprintf(w, "\n--- sent %s ---\n", sent.SentAt.Local().Format("2006-01-02 15:04"))
+		fmt.Fprintf(w, "From: %s\nTo: %s\n", sent.From, sent.To)
+		if len(sent.Cc) > 0 {
+			fmt.Fprintf(w, "Cc: %s\n", strings.Join(sent.Cc, ", "))
+		}
+		for _, field := range [][2]string{{"CV", sent.CVPath}, {"Cover letter", sent.CoverLetterPath}} {
+			if field[1] != "" {
+				fmt.Fprintf(w, "%s: %s\n", field[0], field[1])
+			}
+		}
+		fmt.Fprintf(w, "Subject: %s\n\n%s\n", sent.Subject, sent.Body)
+	}
+
+	hits, err := c.appStore.Hits(a.JobID, a.ProfileID)
+	if err != nil {
+		return err
+	}
+	for _, m := range thread {
+		arrow := "<-"
+		if m.Direction == application.Outgoing {
+			arrow = "->"
+		}
+		fmt.Fprintf(w, "\n%s %s  %s\nSubject: %s\n\n%s\n", arrow, m.Date.Format("2006-01-02 15:04"), m.From, m.Subject, m.Body)
+	}
+	if len(hits) > 0 {
+		fmt.Fprintln(w, "\n--- tracking ---")
+	}
+	for _, h := range hits {
+		what := "opened"
+		if h.Kind == application.TrackClick {
+			what = "clicked " + h.URL
+		}
+		fmt.Fprintf(w, "%s  %s (%s)\n", h.At.Local().Format("2006-01-02 15:04"), what, h.UAFamily)
+	}
+	return nil
+}
+
+// engagement marks applications whose email was opened or clicked.
+func engagement(a application.Application) string {
+	switch {
+	case a.ClickedAt != nil:
+		return "  [clicked]"
+	case a.OpenedAt != nil:
+		return "  [opened]"
+	}
+	return ""
+}
+
+// instrument adds open and click tracking to body when p opted in. On
+// failure the email goes out untracked.
+func (c *CLI) instrument(jobID string, p profile.Profile, body string) (string, string) {
+	t := tracking.ForProfile(p, c.appStore)
+	if t == nil {
+		return body, ""
+	}
+	tracked, pixel, err := t.Instrument(jobID, p.ID, body)
+	if err != nil {
+		fmt.Printf("Warning: sending without tracking: %v\n", err)
+		return body, ""
+	}
+	return tracked, pixel
+}
@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+func newTestCLI(t *testing.T) *CLI {
+	t.Helper()
+	s, err := job.OpenStore(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	apps, err := application.NewStore(s.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &CLI{store: s, appStore: apps}
+}
+
+func TestShowApplication_UsesPostingSnapshot(t *testing.T) {
+	c := newTestCLI(t)
+	j := job.Job{
+		ID: "gh-1", Title: "Backend Engineer", Company: "Acme", Source: "greenhouse",
+		Location: "Remote (EU)", Salary: "€80k-€95k", URL: "https://example.com/jobs/1",
+		Description: "Four-day week, fully remote, Go and Postgres.",
+	}
+	if err := c.store.Save([]job.Job{j}); err != nil {
+		t.Fatal(err)
+	}
+	c.recordApplication(j, profile.NewDefaultProfile(), application.MethodEmail, "Dear Acme,", &application.Sent{
+		From: "jane@example.com", To: "jobs@acme.com", Subject: "Backend Engineer", Body: "Dear Acme,\n\nhttps://t.example.com/c/1",
+		CVPath: "/cv/acme.pdf",
+	})
+
+	j.Description = "Five days in the office."
+	j.Salary = "Competitive"
+	if err := c.store.Save([]job.Job{j}); err != nil {
+		t.Fatal(err)
+	}
+
+	apps, err := c.appStore.All()
+	if err != nil || len(apps) != 1 {
+		t.Fatalf("expected one application, got %d (%v)", len(apps), err)
+	}
+	var out strings.Builder
+	if err := c.showApplication(&out, apps[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	view := out.String()
+	for _, want := range []string{"posting as of", "Four-day week, fully remote", "€80k-€95k", "Remote (EU)",
+		"--- sent", "From: jane@example.com\nTo: jobs@acme.com\nCV: /cv/acme.pdf\nSubject: Backend Engineer\n\nDear Acme,\n\nhttps://t.example.com/c/1"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "Five days in the office") || strings.Contains(view, "Competitive") {
+		t.Errorf("view shows the edited posting:\n%s", view)
+	}
+}
+
+func TestRecordApplication_SnapshotsFullDescription(t *testing.T) {
+	c := newTestCLI(t)
+	tail := "Closing note: we sponsor visas."
+	j := job.Job{
+		ID: "gh-2", Title: "Backend Engineer", Company: "Acme", Source: "greenhouse",
+		Description: strings.Repeat("Go and Postgres. ", job.DescriptionLimit/8) + tail,
+	}
+	if err := c.store.Save([]job.Job{j}); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := c.store.ByID(j.ID)
+	if err != nil || !stored.Truncated || strings.Contains(stored.Description, tail) {
+		t.Fatalf("description not cut on saving (%v)", err)
+	}
+	c.recordApplication(*stored, profile.NewDefaultProfile(), application.MethodEmail, "", nil)
+
+	apps, err := c.appStore.All()
+	if err != nil || len(apps) != 1 {
+		t.Fatalf("expected one application, got %d (%v)", len(apps), err)
+	}
+	if p := apps[0].Posting; p == nil || !strings.HasSuffix(p.Description, tail) {
+		t.Errorf("snapshot lacks the cut part of the description")
+	}
+}
+
+func TestApplicationsReport_UnknownFormatWritesNothing(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "report.docx")
+	printed := runCommand(t, newHomeCLI(t), "applications", "--report", "--format", "docx", "--out", out)
+	if !strings.Contains(printed, `Unknown format "docx"`) {
+		t.Errorf("printed %q", printed)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("an unknown format left %s behind (%v)", out, err)
+	}
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"sprayer/src/api/apply"
+	"sprayer/src/api/job"
+	"sprayer/src/api/llm"
+	"sprayer/src/api/outbox"
+	"sprayer/src/api/profile"
+)
+
+func TestApply_Headless(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(llm.EnvLLMURL, "")
+	t.Setenv(llm.EnvLLMKey, "")
+	c := newTestCLI(t)
+	ps, err := profile.NewStore(c.store.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.profileStore, c.llmClient = ps, llm.NewClient()
+	p := profile.NewDefaultProfile()
+	p.Name, p.ContactEmail = "Jane Doe", "jane@example.com"
+	if err := ps.Save(p); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.store.Save([]job.Job{
+		{ID: "a-1", Title: "Go Engineer", Company: "Acme", Email: "jobs@acme.com", URL: "https://acme.example/1"},
+		{ID: "g-1", Title: "SRE", Company: "Globex", URL: "https://globex.example/1"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err = c.apply(&out, []string{"a-1", "--template", "email", "--print", "--cover-letter", "--profile", "default"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); !strings.HasPrefix(got, "From: jane@example.com\nTo: jobs@acme.com\nSubject: ") || !strings.Contains(got, "Acme") {
+		t.Errorf("printed message:\n%s", got)
+	}
+	letter, err := os.ReadFile(filepath.Join(apply.CoverLetterDir(), "a-1.txt"))
+	if err != nil || !strings.Contains(string(letter), "Acme") {
+		t.Errorf("cover letter %q, %v", letter, err)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		code int
+	}{
+		{[]string{"g-1", "--template", "email"}, exitNoEmail},
+		{[]string{"a-1", "--llm-only", "--print"}, exitLLMUnavailable},
+		{[]string{"a-1", "--send", "--draft"}, 2},
+		{[]string{"a-1", "--cv", "fancy"}, 2},
+		{[]string{"--template", "email"}, 2},
+		{[]string{"nope", "--template", "email"}, 1},
+	} {
+		err := c.apply(&out, tc.args)
+		if got := applyExitCode(err); got != tc.code {
+			t.Errorf("apply %v: exit %d (%v), want %d", tc.args, got, err, tc.code)
+		}
+	}
+
+	var down atomic.Bool
+	down.Store(true)
+	fakeSMTP(t, &down)
+	if c.outbox, err = outbox.NewStore(c.store.DB); err != nil {
+		t.Fatal(err)
+	}
+	err = c.apply(&out, []string{"a-1", "--template", "email", "--send", "--force"})
+	if got := applyExitCode(err); got != exitSendFailed {
+		t.Errorf("refused send: exit %d (%v), want %d", got, err, exitSendFailed)
+	}
+}
+
+func TestApply_PrintKeepsWarningsOffStdout(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(llm.EnvLLMURL, "")
+	t.Setenv(llm.EnvLLMKey, "")
+	c := newTestCLI(t)
+	ps, err := profile.NewStore(c.store.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.profileStore, c.llmClient = ps, llm.NewClient()
+	p := profile.NewDefaultProfile()
+	p.Name, p.ContactEmail = "Jane Doe", "jane@example.com"
+	if err := ps.Save(p); err != nil {
+		t.Fatal(err)
+	}
+	j := job.Job{ID: "q-1", Title: "Go Engineer", Company: "Acme", Email: "jobs@acme.com",
+		Description: "Tell us about a payments system you have built."}
+	if err := c.store.Save([]job.Job{j}); err != nil {
+		t.Fatal(err)
+	}
+
+	stderr := os.Stderr
+	r, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stderr = pw
+	var out bytes.Buffer
+	err = c.apply(&out, []string{"q-1", "--print"})
+	os.Stderr = stderr
+	pw.Close()
+	var progress bytes.Buffer
+	progress.ReadFrom(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// No LLM and an unanswered question: both are warned of, on stderr.
+	for _, want := range []string{"using the built-in", "? Unanswered question"} {
+		if !strings.Contains(progress.String(), want) {
+			t.Errorf("stderr lacks %q:\n%s", want, progress.String())
+		}
+	}
+	subject, body, err := apply.RenderTemplate(apply.TemplateFor("email_cold"), j, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want bytes.Buffer
+	printMessage(&want, j, p.ContactEmail, subject, body)
+	if out.String() != want.String() {
+		t.Errorf("stdout is not the message alone:\n%s\nwant:\n%s", out.String(), want.String())
+	}
+}
@@ -0,0 +1,263 @@
+package ui
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/apply"
+	"sprayer/src/api/batch"
+	"sprayer/src/api/offline"
+	"sprayer/src/api/profile"
+)
+
+const batchUsage = `Usage:
+  sprayer batch new [-profile id] [-template name] <job-id>...
+  sprayer batch list
+  sprayer batch show <batch>
+  sprayer batch approve <batch> [item...]   (all pending when none given)
+  sprayer batch skip <batch> <item>...
+  sprayer batch send <batch>
+  sprayer batch retry <batch> [item...]     (all failed when none given;
+                                            name an interrupted item to resend it)
+  sprayer batch abandon <batch>`
+
+func (c *CLI) handleBatch() {
+	if len(os.Args) < 3 {
+		fmt.Println(batchUsage)
+		return
+	}
+	sub, args := os.Args[2], os.Args[3:]
+	if sub == "new" {
+		c.batchNew(args)
+		return
+	}
+	if sub == "list" {
+		c.batchList()
+		return
+	}
+
+	if len(args) == 0 {
+		fmt.Println(batchUsage)
+		return
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid batch ID %q\n", args[0])
+		return
+	}
+	positions, err := parsePositions(args[1:])
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	switch sub {
+	case "show":
+	case "approve":
+		err = c.batchMark(id, positions, batch.ItemPending, batch.ItemApproved)
+	case "skip":
+		if len(positions) == 0 {
+			fmt.Println("Error: give the items to skip")
+			return
+		}
+		err = c.batchMark(id, positions, "", batch.ItemSkipped)
+	case "send":
+		err = c.batchSend(id)
+	case "retry":
+		if err = c.batchStore.Retry(id, positions...); err == nil {
+			err = c.batchSend(id)
+		}
+	case "abandon":
+		var sum batch.Summary
+		if sum, err = c.batchStore.Abandon(id); err == nil {
+			fmt.Printf("Batch %d abandoned: %s\n", id, sum)
+			return
+		}
+	default:
+		fmt.Println(batchUsage)
+		return
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+	c.batchShow(id)
+}
+
+// batchNew drafts an application per job into the outbox and records them
+// as a pending batch for review.
+func (c *CLI) batchNew(args []string) {
+	fs := flag.NewFlagSet("batch new", flag.ExitOnError)
+	profileID := fs.String("profile", c.defaultProfile(), "Profile to apply with")
+	prompt := fs.String("prompt", "email_cold", "Message prompt template")
+	tmpl := fs.String("template", "", "Use a built-in template instead of the LLM")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fmt.Println("Error: at least one job ID is required")
+		return
+	}
+	p := c.batchProfile(*profileID)
+
+	var items []batch.Item
+	for _, id := range fs.Args() {
+		j, err := c.store.Resolve(id)
+		if err != nil {
+			fmt.Printf("Skipping %s: %v\n", id, err)
+			continue
+		}
+		subject, body, err := c.compose(os.Stdout, *j, p, *prompt, *tmpl)
+		if err != nil {
+			fmt.Printf("Skipping %s: %v\n", id, err)
+			continue
+		}
+		path, err := apply.Draft(*j, p, subject, body)
+		if err != nil {
+			fmt.Printf("Skipping %s: %v\n", id, err)
+			continue
+		}
+		c.lintEchoes(os.Stdout, body, j.Company)
+		items = append(items, batch.Item{JobID: j.ID, To: j.Email, Cc: j.Cc, Subject: subject, Body: body, DraftPath: path})
+	}
+	if len(items) == 0 {
+		fmt.Println("Nothing to batch.")
+		return
+	}
+
+	b, err := c.batchStore.Create(p.ID, items)
+	if err != nil {
+		fmt.Printf("Error saving batch: %v\n", err)
+		return
+	}
+	fmt.Printf("Batch %d created with %d draft(s). Review, then: sprayer batch approve %d\n", b.ID, len(items), b.ID)
+}
+
+func (c *CLI) batchList() {
+	open, err := c.batchStore.Open()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	for _, b := range open {
+		fmt.Printf("#%d  open since %s  %s\n", b.ID, b.CreatedAt.Local().Format("2006-01-02 15:04"), b.Summary())
+	}
+	hist, err := c.batchStore.History()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	for _, h := range hist {
+		fmt.Printf("#%d  %s %s  %d sent, %d failed, %d skipped\n",
+			h.BatchID, h.State, h.ClosedAt.Local().Format("2006-01-02 15:04"), h.Sent, h.Failed, h.Skipped)
+	}
+}
+
+// batchShow prints each item's state, with the last error inline.
+func (c *CLI) batchShow(id int64) {
+	b, err := c.batchStore.ByID(id)
+	if err != nil {
+		fmt.Printf("Batch %d: %v\n", id, err)
+		return
+	}
+	fmt.Printf("Batch %d (%s): %s\n", b.ID, b.State, b.Summary())
+	for _, it := range b.Items {
+		fmt.Printf("  %2d. %-8s %s  %s\n", it.Position, it.State, it.To, it.Subject)
+		if it.Error != "" {
+			fmt.Printf("      error: %s\n", it.Error)
+		}
+	}
+}
+
+// batchMark moves items to to. With no positions, every item in state from
+// is moved.
+func (c *CLI) batchMark(id int64, positions []int, from, to batch.ItemState) error {
+	if len(positions) == 0 {
+		b, err := c.batchStore.ByID(id)
+		if err != nil {
+			return err
+		}
+		for _, it := range b.Items {
+			if it.State == from {
+				positions = append(positions, it.Position)
+			}
+		}
+	}
+	for _, pos := range positions {
+		if err := c.batchStore.SetItemState(id, pos, to, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchSend sends approved items and closes the batch once nothing is left.
+func (c *CLI) batchSend(id int64) error {
+	b, err := c.batchStore.ByID(id)
+	if err != nil {
+		return err
+	}
+	p := c.batchProfile(b.ProfileID)
+
+	sum, err := c.batchStore.Send(id, func(it batch.Item) error {
+		fmt.Printf("Sending %d/%d to %s...\n", it.Position, len(b.Items), it.To)
+		body, pixel := c.instrument(it.JobID, p, it.Body)
+		cv := apply.ResolveCV(p, it.JobID)
+		j, jerr := c.store.ByID(it.JobID)
+		if jerr == nil {
+			cv = apply.ResolveJobCV(p, *j)
+		}
+		if err := apply.SendTracked(it.To, it.Subject, body, cv.Path, pixel, it.Cc...); err != nil {
+			return err
+		}
+		if jerr == nil {
+			c.recordApplication(*j, p, application.MethodEmail, it.Body, &application.Sent{
+				From: apply.SMTPFrom(), To: it.To, Cc: it.Cc, Subject: it.Subject, Body: body,
+				CVPath: cv.Path, CoverLetterPath: p.CoverPath,
+			})
+		}
+		return nil
+	})
+	if errors.Is(err, offline.ErrOffline) {
+		fmt.Printf("%v; approved items stay queued. When back online: sprayer batch send %d\n", err, id)
+		return nil
+	}
+	if err != nil {
+		fmt.Printf("Stopped: %v\nFix the problem, then: sprayer batch retry %d\n", err, id)
+		return nil
+	}
+	if sum.Sending > 0 {
+		fmt.Printf("%d item(s) were interrupted while sending and may have gone out. Check your sent mail, then resend with\n"+
+			"sprayer batch retry %d <item>, or mark them done with sprayer batch skip %d <item>.\n", sum.Sending, id, id)
+	}
+	if sum.Sent+sum.Skipped == sum.Total {
+		if _, err := c.batchStore.Complete(id); err != nil {
+			return err
+		}
+		fmt.Printf("Batch %d complete: %s\n", id, sum)
+	}
+	return nil
+}
+
+func (c *CLI) batchProfile(id string) profile.Profile {
+	if p, err := c.profileStore.ByID(id); err == nil {
+		return *p
+	}
+	p := profile.NewDefaultProfile()
+	p.ID = id
+	return p
+}
+
+func parsePositions(args []string) ([]int, error) {
+	var out []int
+	for _, a := range args {
+		n, err := strconv.Atoi(a)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid item %q", a)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"sprayer/src/api/scraper"
+)
+
+// profileBoards lists the Greenhouse boards a profile scrapes, or adds
+// boards to it, by token or from a job URL, or removes them.
+func (c *CLI) profileBoards(w io.Writer, args []string) error {
+	action := "list"
+	if len(args) > 0 && (args[0] == "add" || args[0] == "rm") {
+		action, args = args[0], args[1:]
+	}
+	fs := flag.NewFlagSet("profile boards", flag.ExitOnError)
+	profileID := fs.String("profile", c.defaultProfile(), "Profile whose boards to list or change")
+	fs.Parse(args)
+
+	p := c.batchProfile(*profileID)
+	switch action {
+	case "list":
+		fmt.Fprintf(w, "Greenhouse boards scraped for %s:\n", p.ID)
+		for _, b := range scraper.GreenhouseBoards(p) {
+			if slices.Contains(p.GreenhouseBoards, b) {
+				fmt.Fprintf(w, "  %s\n", b)
+			} else {
+				fmt.Fprintf(w, "  %s (default)\n", b)
+			}
+		}
+		return nil
+	case "add":
+		if fs.NArg() == 0 {
+			return fmt.Errorf("name a board token or a Greenhouse job URL to add")
+		}
+		for _, arg := range fs.Args() {
+			b, err := scraper.ParseGreenhouseBoard(arg)
+			if err != nil {
+				return err
+			}
+			if slices.Contains(scraper.GreenhouseBoards(p), b) {
+				fmt.Fprintf(w, "%s is already scraped.\n", b)
+				continue
+			}
+			p.GreenhouseBoards = append(p.GreenhouseBoards, b)
+			fmt.Fprintf(w, "Added board %s to %s.\n", b, p.ID)
+		}
+	case "rm":
+		for _, arg := range fs.Args() {
+			b := strings.ToLower(strings.TrimSpace(arg))
+			i := slices.Index(p.GreenhouseBoards, b)
+			switch {
+			case i >= 0:
+				p.GreenhouseBoards = slices.Delete(p.GreenhouseBoards, i, i+1)
+				fmt.Fprintf(w, "Removed board %s from %s.\n", b, p.ID)
+			case slices.Contains(scraper.DefaultGreenhouseBoards, b):
+				return fmt.Errorf("%s is a default board, scraped for every profile", b)
+			default:
+				return fmt.Errorf("%s has no board %s", p.ID, b)
+			}
+		}
+	}
+	return c.profileStore.Save(p)
+}
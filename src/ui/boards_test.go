@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProfileBoards(t *testing.T) {
+	c := newHomeCLI(t)
+	id := c.defaultProfile()
+
+	var out bytes.Buffer
+	if err := c.profileBoards(&out, []string{"add", "https://boards.greenhouse.io/Acme/jobs/4012", "flyio"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Added board acme to "+id) || !strings.Contains(out.String(), "flyio is already scraped.") {
+		t.Errorf("add:\n%s", out.String())
+	}
+	if p := c.batchProfile(id); strings.Join(p.GreenhouseBoards, ",") != "acme" {
+		t.Errorf("boards saved: %v", p.GreenhouseBoards)
+	}
+
+	if list := runCommand(t, c, "profile", "boards"); !strings.Contains(list, "  acme\n") || !strings.Contains(list, "  flyio (default)\n") {
+		t.Errorf("list:\n%s", list)
+	}
+
+	if err := c.profileBoards(&out, []string{"rm", "flyio"}); err == nil {
+		t.Error("removed a default board")
+	}
+	out.Reset()
+	if err := c.profileBoards(&out, []string{"rm", "acme"}); err != nil {
+		t.Fatal(err)
+	}
+	if p := c.batchProfile(id); len(p.GreenhouseBoards) != 0 {
+		t.Errorf("boards after rm: %v", p.GreenhouseBoards)
+	}
+	if err := c.profileBoards(&out, []string{"add", "https://acme.com/careers?gh_jid=1"}); err == nil {
+		t.Error("added a board from a URL without one")
+	}
+}
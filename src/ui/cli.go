@@ -1,24 +1,61 @@
 package ui
 
 import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"slices"
 	"strings"
 	"time"
 
+	"sprayer/src/api/application"
 	"sprayer/src/api/apply"
+	"sprayer/src/api/batch"
+	"sprayer/src/api/contact"
+	"sprayer/src/api/export"
+	"sprayer/src/api/inbox"
 	"sprayer/src/api/job"
 	"sprayer/src/api/llm"
+	"sprayer/src/api/notify"
+	"sprayer/src/api/offline"
+	"sprayer/src/api/outbox"
+	"sprayer/src/api/power"
 	"sprayer/src/api/profile"
+	"sprayer/src/api/redact"
+	"sprayer/src/api/rules"
 	"sprayer/src/api/scraper"
+	"sprayer/src/api/scraperun"
+	"sprayer/src/api/sourcestats"
+	"sprayer/src/api/scratch"
+	"sprayer/src/api/sendtime"
+	"sprayer/src/api/settings"
 )
 
 // CLI implements the command-line interface logic.
 type CLI struct {
 	store        *job.Store
 	profileStore *profile.Store
+	appStore     *application.Store
+	batchStore   *batch.Store
+	outbox       *outbox.Store
+	inbox        *inbox.Store
+	scratch      *scratch.Manager
+	contactStore *contact.Store
+	ruleStore    *rules.Store
+	runStore     *scraperun.Store
+	statsStore   *sourcestats.Store
 	llmClient    *llm.Client
+	llmUsage     *llm.Store
+	power        power.Detector
+
+	settingsStore *settings.Store
+	settingsEnv   settings.Overrides // the settings the environment overrides
 }
 
 func NewCLI() (*CLI, error) {
@@ -30,11 +67,94 @@ func NewCLI() (*CLI, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &CLI{
+	aStore, err := application.NewStore(s.DB)
+	if err != nil {
+		return nil, err
+	}
+	bStore, err := batch.NewStore(s.DB)
+	if err != nil {
+		return nil, err
+	}
+	oStore, err := outbox.NewStore(s.DB)
+	if err != nil {
+		return nil, err
+	}
+	iStore, err := inbox.NewStore(s.DB)
+	if err != nil {
+		return nil, err
+	}
+	scratchMgr, err := scratch.NewManager(s.DB, scratch.Providers()...)
+	if err != nil {
+		return nil, err
+	}
+	cStore, err := contact.NewStore(s.DB)
+	if err != nil {
+		return nil, err
+	}
+	rStore, err := rules.NewStore(s.DB)
+	if err != nil {
+		return nil, err
+	}
+	runStore, err := scraperun.NewStore(s.DB)
+	if err != nil {
+		return nil, err
+	}
+	statsStore, err := sourcestats.NewStore(s.DB)
+	if err != nil {
+		return nil, err
+	}
+	usageStore, err := llm.NewStore(s.DB)
+	if err != nil {
+		return nil, err
+	}
+	// The stored settings go into the environment before anything reads it.
+	setStore, err := settings.NewStore(s.DB)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := setStore.Load()
+	if err != nil {
+		return nil, err
+	}
+	setEnv := settings.Apply(stored)
+	llmClient := llm.NewClient()
+	prices, err := llm.LoadPrices()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Reading LLM prices: %v; using the defaults.\n", err)
+	}
+	llmClient.RecordUsage(usageStore, prices)
+	c := &CLI{
 		store:        s,
 		profileStore: pStore,
-		llmClient:    llm.NewClient(),
-	}, nil
+		appStore:     aStore,
+		batchStore:   bStore,
+		outbox:       oStore,
+		inbox:        iStore,
+		scratch:      scratchMgr,
+		contactStore: cStore,
+		ruleStore:    rStore,
+		runStore:     runStore,
+		statsStore:   statsStore,
+		llmClient:    llmClient,
+		llmUsage:     usageStore,
+		power:        power.System(),
+
+		settingsStore: setStore,
+		settingsEnv:   setEnv,
+	}
+	redact.Install(os.Stderr, c.redactor())
+	return c, nil
+}
+
+// defaultProfile is the profile commands use when given none.
+func (c *CLI) defaultProfile() string {
+	return cmp.Or(os.Getenv(settings.EnvDefaultProfile), "default")
+}
+
+// redactor scrubs the personal data of every stored profile.
+func (c *CLI) redactor() *redact.Redactor {
+	profiles, _ := c.profileStore.All()
+	return redact.FromProfiles(profiles)
 }
 
 func (c *CLI) Run() {
@@ -46,39 +166,141 @@ func (c *CLI) Run() {
 	switch os.Args[1] {
 	case "scrape":
 		c.handleScrape()
+	case "daemon":
+		c.handleDaemon()
+	case "sources":
+		c.handleSources()
 	case "list":
 		c.handleList()
+	case "filter":
+		c.handleFilter()
 	case "apply":
 		c.handleApply()
+	case "hide":
+		c.handleHide()
+	case "jobs":
+		c.handleJobs()
+	case "followups":
+		c.handleFollowups()
 	case "profile":
 		c.handleProfile()
+	case "applications":
+		c.handleApplications()
+	case "reply":
+		c.handleReply()
+	case "batch":
+		c.handleBatch()
+	case "outbox":
+		c.handleOutbox()
+	case "llm":
+		c.handleLLM()
+	case "inbox":
+		c.handleInbox()
+	case "contacts":
+		c.handleContacts()
+	case "rescore":
+		c.handleRescore()
+	case "questions":
+		c.handleQuestions()
+	case "cv":
+		c.handleCV()
 	case "setup":
 		c.handleSetup()
+	case "settings":
+		c.handleSettings()
+	case "doctor":
+		c.handleDoctor()
+	case "traps":
+		c.handleTraps()
+	case "rules":
+		c.handleRules()
+	case "completion":
+		c.handleCompletion()
+	case "__complete":
+		c.handleComplete()
+	case "help", "-h", "-help", "--help":
+		c.printUsage()
 	default:
 		c.printUsage()
 	}
 }
 
-func (c *CLI) printUsage() {
-	fmt.Println(`Sprayer - The Agentic Job Application Tool
+// usage lists every command Run dispatches, each on a line of its own
+// starting with two spaces; the lines under it, indented further, say
+// more.
+const usage = `Sprayer - The Agentic Job Application Tool
 
 Usage:
   sprayer <command> [flags]
 
 Commands:
-  scrape   Fetch jobs from all sources
-  list     List and filter jobs (pipeable)
-  apply    Apply to a specific job (generates draft)
-  list     List and filter jobs (pipeable)
-  apply    Apply to a specific job (generates draft)
-   profile  Manage profiles
-   setup    Configure SMTP and LLM settings`)
+  scrape        Fetch jobs from all sources (asks first on a low battery or metered network)
+                  scrape history: recent scrapes with their job counts and failed sources
+  daemon        Scrape every few hours and send the best new jobs to a webhook or email (--every 6h)
+  sources       status: runs, jobs, errors, latency and last success per source, flagging those gone quiet
+  list          List and filter jobs (pipeable)
+                  --closing-soon: jobs whose application deadline is within the window
+                  --format csv|markdown|json [--columns title,company,score,url]: export the listed jobs
+  filter        The profile's matches, with a saved filter preset on top (--preset name; presets, save, rm)
+  apply         Apply to a specific job (generates draft)
+                  apply <job-id> [--profile id] [--draft|--send] [--print] [--cover-letter] [--cv original|custom]
+                  exits 3 when the job has no email, 4 when the LLM is unavailable, 5 when sending fails
+  hide          Hide a job in a profile's list (--undo to restore)
+  jobs          Stored jobs: dedup merges repeats; enrich [--force] backfills traps, emails, locations and dates;
+                  status tracks where you stand (interested ... offer) with a history
+  followups     Applied jobs due a follow-up (list [-all]; --mark-done <id> once sent)
+  applications  List applications (--report for a dated report; show <id> for one; --withdraw to pull out)
+  reply         Reply to a recruiter email (.eml), threaded
+  batch         Draft, review and send applications in bulk (resumable)
+  outbox        Emails waiting to go out: list, flush (retry with backoff), retry <id>, rm <id>
+  inbox         Replies arriving in your mailboxes: list, poll, watch (notifies per matched job), scratch [new|rm <id>]
+  llm           LLM calls made: usage [--since 7d] totals tokens and estimated cost per purpose
+  contacts      Recruiters and referrers you know (list, search, add, duplicates, merge)
+  questions     Answer a job's application questions and reuse past answers
+  rescore       Recompute job scores for a profile (--explain for breakdowns)
+  profile       List profiles (profile edit [id] opens the editor; --create-from-cv cv.pdf [--name n] makes one)
+                  --export file [--profile id] [--strip-personal|--include-cv] writes one; --import file reads it back
+                  boards [add <token|job URL>|rm <token>] [--profile id]: Greenhouse boards scraped with the defaults
+  cv            Export a CV as JSON Resume (--export-jsonresume), import one, or build one for a job (--generate)
+                  cv inspect cv.pdf [--json]: the contact, jobs and schools read from a CV
+  setup         Configure SMTP and LLM settings
+  settings      Show the settings in effect and where each comes from (--show), or --export/--import them as JSON
+  doctor        Check the database, data directory, LLM and SMTP (--offline skips the network)
+  traps         List trap rules (--lint rules.yaml validates a file, --test "text" shows hits)
+  rules         Tag, rescore or hide jobs as they are scraped (list, add, test, enable, disable, rm, edit)
+  completion    Print a bash, zsh or fish completion script
+  help          Show this list
+
+Commands that list things take --json to print them as a JSON array:
+  list, filter, applications, followups list, outbox list, inbox list,
+  contacts list|search, profile, scrape history, filter presets, sources status`
+
+func (c *CLI) printUsage() {
+	fmt.Println(usage)
+}
+
+// writeJSON writes items as an indented JSON array, empty rather than
+// null when there are none, for the --json flag of listing commands.
+func writeJSON[T any](w io.Writer, items []T) error {
+	if items == nil {
+		items = []T{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(items)
 }
 
 func (c *CLI) handleScrape() {
+	if len(os.Args) > 2 && os.Args[2] == "history" {
+		c.handleScrapeHistory(os.Args[3:])
+		return
+	}
 	fs := flag.NewFlagSet("scrape", flag.ExitOnError)
 	fast := fs.Bool("fast", false, "Skip browser-based scrapers (API only)")
-	force := fs.Bool("force", false, "Force scrape even if recently run")
+	force := fs.Bool("force", false, "Scrape even if recently run, and reprocess postings already seen")
+	yes := fs.Bool("yes", false, "Scrape without asking on a low battery or metered connection")
+	profileID := fs.String("profile", c.defaultProfile(), "Profile whose sources run and that ingest rules hide jobs in")
+	sources := fs.String("sources", "", "Comma-separated sources to run, e.g. hn,remoteok (default: the profile's, else all)")
 
 	// Parse flags first
 	if len(os.Args) > 2 {
@@ -90,49 +312,239 @@ func (c *CLI) handleScrape() {
 		keywords = []string{"golang", "rust", "remote"}
 	}
 
+	// Ctrl-C stops the sources and saves what they found; a second one
+	// kills the process as usual.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	selected, sourceCount, err := c.selectedScraper(ctx, *profileID, *sources, *fast, keywords)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
 	fmt.Printf("Scraping for: %v (fast=%v)\n", keywords, *fast)
 
 	// Check history
+	if err := offline.Check("scraping"); err != nil {
+		fmt.Printf("%v; saved jobs still work: sprayer list\n", err)
+		return
+	}
+
 	cacheKey := fmt.Sprintf("%v-fast=%v", keywords, *fast)
+	if selected != nil {
+		cacheKey += "-sources=" + *sources
+	}
 	lastRun, _ := c.store.GetLastScrape(cacheKey)
 	if !*force && time.Since(lastRun) < 15*time.Minute {
 		fmt.Printf("Skipping scrape (run %v ago). Use --force to override.\n", time.Since(lastRun).Round(time.Second))
 		return
 	}
+	if !c.confirmScrape(os.Stdin, *yes) {
+		return
+	}
 
-	var s job.Scraper
-	if *fast {
-		s = scraper.APIOnly()
-	} else {
-		s = scraper.All(keywords, "Remote")
+	prof := c.batchProfile(*profileID)
+	s := selected
+	switch {
+	case s != nil:
+	case *fast:
+		s = scraper.APIOnlyContext(ctx, prof, keywords)
+	default:
+		s = scraper.AllContext(ctx, prof, keywords, "Remote")
 	}
 
+	run := c.startRun(*profileID, sourceCount)
 	jobs, err := s()
-	if err != nil {
+	// A board that failed leaves the other boards' jobs worth saving.
+	failed, boardsOnly := scraper.BoardFailures(err)
+	if ctx.Err() != nil {
+		fmt.Printf("Stopped mid-scrape; saving the %d jobs found so far.\n", len(jobs))
+	} else if err != nil && !boardsOnly {
+		c.finishRun(run, 0, []string{err.Error()})
 		fmt.Printf("Scrape error: %v\n", err)
 		return
 	}
+	var failures []string
+	for _, e := range failed {
+		failures = append(failures, e.Error())
+		// Greenhouse boards not found close the summary.
+		if gh, ok := e.(*scraper.GreenhouseBoardError); !ok || !gh.NotFound() {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", e)
+		}
+	}
+	scraped, seen := jobs, 0
+	if !*force {
+		fresh, skipped, err := c.store.SkipSeen(jobs)
+		if err != nil {
+			c.finishRun(run, 0, []string{err.Error()})
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		jobs, seen = fresh, skipped
+		fmt.Printf("%d new / %d seen\n", len(fresh), seen)
+	}
 
-	// Flag and sanitize before saving
-	pipeline := job.Pipe(job.FlagTraps(), job.SanitizeDescriptions())
-	processed := pipeline(jobs)
+	pipeline := c.scrapePipeline()
+	// Match stored jobs first, so rules hide a repeat under the ID it is saved as
+	matched, merged, err := c.store.MatchStored(pipeline(jobs))
+	if err != nil {
+		c.finishRun(run, 0, []string{err.Error()})
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	processed := c.ingestRules(*profileID, matched)
 
-	c.store.Save(processed)
+	if err := c.store.Save(processed); err != nil {
+		c.finishRun(run, 0, []string{err.Error()})
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if err := c.store.MarkSaved(processed); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: recording jobs seen: %v\n", err)
+	}
 	c.store.SetLastScrape(cacheKey)
-	fmt.Printf("Saved %d jobs.\n", len(processed))
+	c.finishRun(run, len(processed), failures)
+	notify.Notify(notify.ScrapeCompleted(*profileID, len(processed), seen, 0))
+	if merged > 0 {
+		fmt.Printf("Saved %d jobs (%d duplicates merged into jobs already seen).\n", len(processed), merged)
+	} else {
+		fmt.Printf("Saved %d jobs.\n", len(processed))
+	}
+	fmt.Println(c.recordFetched(run, *profileID, len(scraped)))
+	printMissingBoards(os.Stdout, prof)
+}
+
+// printMissingBoards ends a scrape's summary with the Greenhouse boards
+// that were not found, and how to drop the profile's own.
+func printMissingBoards(w io.Writer, prof profile.Profile) {
+	for _, b := range scraper.MissingGreenhouseBoards(scraper.GreenhouseBoards(prof)) {
+		if slices.Contains(prof.GreenhouseBoards, b) {
+			fmt.Fprintf(w, "Greenhouse board %q not found; remove it with 'sprayer profile boards rm %s --profile %s'\n", b, b, prof.ID)
+		} else {
+			fmt.Fprintf(w, "Greenhouse board %q not found.\n", b)
+		}
+	}
+}
+
+// selectedScraper builds the scraper for the sources named by -sources,
+// or else by the profile, with sourceCount how many it runs. With no
+// sources named it returns a nil scraper and the count for running them
+// all. fast drops browser sources from the selection; cancelling ctx
+// stops the selected sources.
+func (c *CLI) selectedScraper(ctx context.Context, profileID, sources string, fast bool, keywords []string) (s job.Scraper, sourceCount int, err error) {
+	prof := profile.NewDefaultProfile()
+	if c.profileStore != nil {
+		if p, err := c.profileStore.ByID(profileID); err == nil {
+			prof = *p
+		}
+	}
+	keys := prof.Sources
+	if sources != "" {
+		keys = nil
+		for _, k := range strings.Split(sources, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				keys = append(keys, k)
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return nil, scraper.SourceCount(fast), nil
+	}
+	if err := scraper.CheckSources(scraper.DefaultSources(prof), keys); err != nil {
+		return nil, 0, err
+	}
+	if fast {
+		var api []string
+		for _, k := range keys {
+			if slices.Contains(scraper.APISourceKeys, k) {
+				api = append(api, k)
+			}
+		}
+		if len(api) == 0 {
+			return nil, 0, fmt.Errorf("-fast skips every selected source (%s needs a browser)", strings.Join(keys, ", "))
+		}
+		keys = api
+	}
+	s, err = scraper.SelectedContext(ctx, prof, keys, keywords, "Remote")
+	return s, len(keys), err
 }
 
-func (c *CLI) handleList() {
-	fs := flag.NewFlagSet("list", flag.ExitOnError)
+func (c *CLI) handleList() { c.listJobs("list", os.Args[2:], false) }
+
+// listJobs lists the jobs args ask for, as the command name would. match
+// is -match's default: whether the profile's filters apply.
+func (c *CLI) listJobs(name string, args []string, match bool) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
 	keywords := fs.String("keywords", "", "Filter by keywords (comma-sep)")
 	minScore := fs.Int("min-score", 0, "Filter by minimum score")
-	fs.Parse(os.Args[2:])
+	profileID := fs.String("profile", c.defaultProfile(), "Profile whose hidden/starred state applies")
+	showHidden := fs.Bool("all", false, "Include jobs hidden or archived in the profile")
+	closingSoon := fs.Bool("closing-soon", false, "Preset: only jobs whose application deadline is near")
+	window := fs.String("closing-window", "", "How near counts as closing soon, e.g. 72h or 5d (default $"+job.EnvClosingWindow+" or 7d)")
+	matchProfile := fs.Bool("match", match, "Apply the profile's filters, including its CV match")
+	preset := fs.String("preset", "", "Apply this saved filter preset on top of the profile's filters (implies -match)")
+	statuses := fs.String("status", "", "Only jobs at these statuses (comma-sep; none for untracked)")
+	format := fs.String("format", "", "Export as csv, markdown or json instead of the plain list")
+	columns := fs.String("columns", "", "Columns to export, comma-sep (default "+export.DefaultJobColumns+"; have "+strings.Join(export.JobColumnNames(), ",")+")")
+	sortBy := fs.String("sort", "", "Sort by score, posted_date, title or company; a leading - reverses (default score)")
+	asJSON := fs.Bool("json", false, "Print the jobs as JSON (--format json)")
+	fs.Parse(args)
+	if *asJSON {
+		*format = "json"
+	}
+
+	order, err := job.ParseSort(*sortBy)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	var exportAs export.JobFormat
+	var exportCols []export.JobColumn
+	if *format != "" {
+		if exportAs, err = export.ParseJobFormat(*format); err == nil {
+			exportCols, err = export.ParseJobColumns(*columns)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+	}
 
-	jobs, _ := c.store.All()
+	closing := job.ClosingWindow()
+	if *window != "" {
+		d, err := job.ParseWindow(*window)
+		if err != nil || d <= 0 {
+			fmt.Printf("Invalid --closing-window %q\n", *window)
+			return
+		}
+		closing = d
+	}
+
+	var scope []job.ActiveOption
+	if *showHidden {
+		scope = append(scope, job.IncludeHidden(), job.IncludeArchived())
+	}
+	var jobs []job.Job
+	if *matchProfile || *preset != "" {
+		p, err := c.presetProfile(*profileID, *preset)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		jobs, _ = p.StoredMatches(c.store, scope...)
+	} else {
+		jobs, _ = c.store.ForProfile(*profileID, scope...)
+	}
 
 	filters := []job.Filter{
 		job.Dedup(),
-		job.FlagTraps(),
+		c.flagTraps(),
 		job.SanitizeDescriptions(),
 	}
 	if *keywords != "" {
@@ -141,82 +553,455 @@ func (c *CLI) handleList() {
 	if *minScore > 0 {
 		filters = append(filters, job.ByMinScore(*minScore))
 	}
+	if *statuses != "" {
+		var want []job.Status
+		for _, name := range strings.Split(*statuses, ",") {
+			st, err := job.ParseStatus(name)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			want = append(want, st)
+		}
+		filters = append(filters, job.ByStatus(want...))
+	}
+	if *closingSoon {
+		filters = append(filters, job.ByDeadlineWithin(closing))
+	}
+	now := time.Now()
+	filters = append(filters, job.SortBy(order.Less(now, closing)))
 
 	pipeline := job.Pipe(filters...)
 	filtered := pipeline(jobs)
 
+	if exportAs != "" {
+		if err := export.WriteJobs(os.Stdout, filtered, exportAs, exportCols); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		return
+	}
+
 	for _, j := range filtered {
 		trapIndicator := ""
-		if j.HasTraps {
+		if len(j.Traps) > 0 {
+			names := make([]string, len(j.Traps))
+			for i, t := range j.Traps {
+				names[i] = t.String()
+			}
+			trapIndicator = " [!] TRAPS: " + strings.Join(names, ", ")
+		} else if j.HasTraps {
 			trapIndicator = " [!] TRAPS FOUND"
 		}
-		fmt.Printf("[%d]%s %s @ %s (%s)\n", j.Score, trapIndicator, j.Title, j.Company, j.ID)
+		star := ""
+		if j.Starred {
+			star = " *"
+		}
+		deadline := ""
+		if badge := job.DeadlineBadge(j, now, closing); badge != "" {
+			deadline = " " + badge
+		}
+		status := ""
+		if badge := j.Status.Badge(); badge != "" {
+			status = " [" + badge + "]"
+		}
+		tags := ""
+		if len(j.Tags) > 0 {
+			tags = " {" + strings.Join(j.Tags, ", ") + "}"
+		}
+		fmt.Printf("%s [%d]%s%s%s%s %s @ %s (%s)%s\n", j.Ref(), j.Score, star, status, trapIndicator, deadline, j.Title, j.Company, j.ID, tags)
+		if j.Note != "" {
+			fmt.Printf("    note: %s\n", j.Note)
+		}
+		if *closingSoon && j.DeadlineText != "" {
+			fmt.Printf("    %q\n", j.DeadlineText)
+		}
+	}
+}
+
+// apply's exit codes, so a script applying from cron can tell why a job
+// was skipped. Other failures exit 1, bad flags 2.
+const (
+	exitNoEmail        = 3
+	exitLLMUnavailable = 4
+	exitSendFailed     = 5
+)
+
+var (
+	errNoEmail        = errors.New("the job has no email address to apply to")
+	errLLMUnavailable = errors.New("LLM unavailable")
+	errSendFailed     = errors.New("send failed")
+)
+
+// applyExitCode is the exit code apply ends with after err.
+func applyExitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errNoEmail):
+		return exitNoEmail
+	case errors.Is(err, errLLMUnavailable):
+		return exitLLMUnavailable
+	case errors.Is(err, errSendFailed):
+		return exitSendFailed
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, new(usageError)):
+		return 2
 	}
+	return 1
 }
 
+// usageError is a flag apply cannot make sense of.
+type usageError struct{ error }
+
 func (c *CLI) handleApply() {
-	fs := flag.NewFlagSet("apply", flag.ExitOnError)
-	jobID := fs.String("job", "", "Job ID to apply to")
+	err := c.apply(os.Stdout, os.Args[2:])
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		fmt.Printf("Error: %v\n", err)
+	}
+	if code := applyExitCode(err); code != 0 {
+		os.Exit(code)
+	}
+}
+
+// apply composes the application to one job and drafts, prints or sends
+// it, as args say. Progress goes to w, or to stderr when the message is
+// printed, so that stdout holds the message alone.
+func (c *CLI) apply(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("apply", flag.ContinueOnError)
+	jobID := fs.String("job", "", "Job to apply to, by ID or #short ID (or give it as the first argument)")
+	profileID := fs.String("profile", c.defaultProfile(), "Profile to apply with")
 	prompt := fs.String("prompt", "email_cold", "Message prompt template")
+	tmpl := fs.String("template", "", "Use a built-in template instead of the LLM ("+strings.Join(apply.BuiltinTemplates(), ", ")+")")
+	llmOnly := fs.Bool("llm-only", false, "Fail instead of falling back to a built-in template when the LLM is unavailable")
 	send := fs.Bool("send", false, "Send email immediately via SMTP")
-	fs.Parse(os.Args[2:])
+	draft := fs.Bool("draft", false, "Write the draft and never send (the default); with --print, write it as well as printing")
+	printOnly := fs.Bool("print", false, "Print the message to stdout instead of writing a draft")
+	coverLetter := fs.Bool("cover-letter", false, "Also write a cover letter for the job to outputs/cover-letters")
+	cvChoice := fs.String("cv", "original", "CV to attach: original (the profile's, or one tailored earlier) or custom (tailored for this job)")
+	force := fs.Bool("force", false, "Send even if the CV cannot be attached")
+	fixCV := fs.Bool("fix-cv", false, "Rewrite the CV contact address to match the sender")
+	exclude := fs.String("exclude", "", "Recipients to leave out, comma-separated (the first one left is To, the rest Cc)")
+	tailorCV := fs.Bool("tailor-cv", false, "Attach a CV built for this job from the profile CV (needs a LaTeX engine)")
+	regenerateCV := fs.Bool("regenerate-cv", false, "Like --tailor-cv, overwriting a tailored CV built earlier")
+	fs.SetOutput(w)
+	// The job may come first, with flags after it.
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return err
+			}
+			return usageError{err}
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		if *jobID != "" {
+			return usageError{fmt.Errorf("one job at a time: %q given after %q", fs.Arg(0), *jobID)}
+		}
+		*jobID, args = fs.Arg(0), fs.Args()[1:]
+	}
 
-	if *jobID == "" {
-		fmt.Println("Error: --job is required")
-		return
+	switch {
+	case *jobID == "":
+		return usageError{errors.New("a job ID is required: sprayer apply <job-id>")}
+	case *send && (*draft || *printOnly):
+		return usageError{errors.New("--send cannot be combined with --draft or --print")}
+	case *cvChoice != "original" && *cvChoice != "custom":
+		return usageError{fmt.Errorf("--cv is original or custom, not %q", *cvChoice)}
+	}
+	if *cvChoice == "custom" {
+		*tailorCV = true
+	}
+	out := w
+	if *printOnly {
+		w = os.Stderr
 	}
 
-	j, err := c.store.ByID(*jobID)
+	j, err := c.store.Resolve(*jobID)
 	if err != nil {
-		fmt.Printf("Job not found: %v\n", err)
-		return
+		return fmt.Errorf("job not found: %w", err)
 	}
 
-	profiles, _ := c.profileStore.All()
-	// Use first profile for now - can be enhanced later
-	var p profile.Profile
-	if len(profiles) > 0 {
-		p = profiles[0]
-	} else {
-		p = profile.NewDefaultProfile()
+	p := c.batchProfile(*profileID)
+
+	if *exclude != "" {
+		j.Email, j.Cc = j.Recipients(strings.Split(*exclude, ",")...)
 	}
+	if j.Email == "" {
+		return fmt.Errorf("%w: %s at %s", errNoEmail, j.Title, j.Company)
+	}
+	printRecipients(w, *j)
+	printRequirements(w, p, *j)
 
-	fmt.Printf("Generating application for %s using profile %s...\n", j.Company, p.Name)
+	c.knownAt(w, j.Company)
+	fmt.Fprintf(w, "Generating application for %s using profile %s...\n", j.Company, p.Name)
 
-	subject, body, err := apply.GenerateEmail(*j, p, c.llmClient, *prompt)
+	if *tmpl == "" && *llmOnly {
+		if err := c.llmClient.Check(context.Background()); err != nil {
+			return fmt.Errorf("%w: %v (pass --template to use a built-in one)", errLLMUnavailable, err)
+		}
+	}
+	subject, body, err := c.compose(w, *j, p, *prompt, *tmpl)
 	if err != nil {
-		fmt.Printf("Generation failed: %v\n", err)
-		return
+		if *tmpl == "" {
+			return fmt.Errorf("%w: generation failed: %v", errLLMUnavailable, err)
+		}
+		return fmt.Errorf("generation failed: %w", err)
+	}
+
+	from := p.ContactEmail
+	if *send {
+		from = apply.SMTPFrom()
+	}
+	c.lintAddresses(w, p, from, body, *fixCV)
+	c.lintEchoes(w, body, "")
+
+	if *coverLetter {
+		letterTmpl := ""
+		if *tmpl != "" {
+			letterTmpl = "cover_letter"
+		}
+		_, letter, err := c.compose(w, *j, p, "cover_letter", letterTmpl)
+		if err != nil {
+			if *tmpl == "" {
+				return fmt.Errorf("%w: cover letter: %v", errLLMUnavailable, err)
+			}
+			return fmt.Errorf("cover letter: %w", err)
+		}
+		path, err := apply.WriteCoverLetter(j.ID, letter)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Cover letter written: %s\n", path)
+	}
+
+	if *tailorCV || *regenerateCV {
+		if reused, err := c.tailorCV(context.Background(), j, p, *regenerateCV); err != nil {
+			fmt.Fprintf(w, "Tailored CV failed: %v\n", err)
+		} else if reused {
+			fmt.Fprintf(w, "Reusing tailored CV %s (--regenerate-cv to rebuild)\n", j.CVPath)
+		} else {
+			fmt.Fprintf(w, "Tailored CV built: %s\n", j.CVPath)
+		}
+	}
+
+	cv := apply.ResolveJobCV(p, *j)
+	if *printOnly {
+		printMessage(out, *j, from, subject, body)
+		if *draft {
+			path, err := apply.Draft(*j, p, subject, body)
+			if err != nil {
+				return fmt.Errorf("draft failed: %w", err)
+			}
+			fmt.Fprintf(w, "Draft created: %s\n", path)
+		}
+		fmt.Fprintf(w, "%s %s\n", severityMark(cv.Severity()), cv.Label(time.Now()))
+		return nil
 	}
 
 	path, err := apply.Draft(*j, p, subject, body)
 	if err != nil {
-		fmt.Printf("Draft failed: %v\n", err)
+		return fmt.Errorf("draft failed: %w", err)
+	}
+	fmt.Fprintf(w, "Draft created: %s\n", path)
+	fmt.Fprintf(w, "%s %s\n", severityMark(cv.Severity()), cv.Label(time.Now()))
+
+	if !*send {
+		now := time.Now()
+		fmt.Fprintf(w, "Send time %s\n", sendtime.Suggest(j.Location, now, sendtime.DefaultConfig()).String(now))
+		return nil
+	}
+
+	if cv.Blocking() && !*force {
+		return errors.New("not sending: fix the CV or pass --force to send without it")
+	}
+	fmt.Fprintf(w, "Sending email via SMTP...\n")
+	m := &outbox.Message{JobID: j.ID, ProfileID: p.ID, To: j.Email, Cc: j.Cc, Subject: subject, Body: body}
+	if err := c.sendNow(m); err != nil {
+		var dry *apply.DryRunError
+		if errors.As(err, &dry) {
+			fmt.Fprintf(w, "%s=1: message written to %s, not sent\n", apply.EnvSMTPDryRun, dry.Path)
+			return nil
+		}
+		return fmt.Errorf("%w: %v", errSendFailed, err)
+	}
+	fmt.Fprintf(w, "Email sent successfully to %s!\n", j.Email)
+	return nil
+}
+
+// printMessage writes the application as apply --print shows it: its
+// headers, a blank line and the body.
+func printMessage(w io.Writer, j job.Job, from, subject, body string) {
+	fmt.Fprintf(w, "From: %s\n", from)
+	fmt.Fprintf(w, "To: %s\n", j.Email)
+	if len(j.Cc) > 0 {
+		fmt.Fprintf(w, "Cc: %s\n", strings.Join(j.Cc, ", "))
+	}
+	fmt.Fprintf(w, "Subject: %s\n\n%s\n", subject, strings.TrimRight(body, "\n"))
+}
+
+// printRecipients lists who the application goes to, with the flag that
+// leaves an address out when the posting names more than one.
+func printRecipients(w io.Writer, j job.Job) {
+	if j.Email == "" {
 		return
 	}
+	fmt.Fprintf(w, "To: %s\n", j.Email)
+	if len(j.Cc) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "Cc: %s\n", strings.Join(j.Cc, ", "))
+	fmt.Fprintln(w, "  Leave any out with --exclude addr[,addr]")
+}
 
-	fmt.Printf("Draft created: %s\n", path)
+// printRequirements warns about work authorizations and clearances the
+// posting demands that the profile does not hold.
+func printRequirements(w io.Writer, p profile.Profile, j job.Job) {
+	for _, r := range p.UnmetRequirements(&j) {
+		fmt.Fprintf(w, "! requires: %s — not in your profile\n", r)
+		fmt.Fprintf(w, "  %q\n", r.Phrase)
+	}
+}
 
-	if *send {
-		fmt.Printf("Sending email via SMTP...\n")
-		// Assume CV is attached if path exists and ends with .pdf, but Draft only returns .eml path?
-		// apply.Draft saves .eml. Attachment is usually handled inside Draft or external.
-		// Wait, Draft function saves the .eml file.
-		// SendDirect needs the attachment path (PDF) separately.
-		// Let's assume Profile has CV path.
-		cvPath := p.CVPath
-		err := apply.SendDirect(j.Email, subject, body, cvPath)
+// compose writes the application email, from a built-in template when tmpl
+// is set, no LLM is configured or the LLM is out of reach offline. Notes
+// on how it was written go to w.
+func (c *CLI) compose(w io.Writer, j job.Job, p profile.Profile, prompt, tmpl string) (string, string, error) {
+	answers := c.prepareQuestions(w, j, p)
+	if tmpl != "" {
+		return apply.RenderTemplate(tmpl, j, p, answers...)
+	}
+	if err := c.llmClient.Check(context.Background()); err != nil {
+		fmt.Fprintf(w, "%v; using the built-in %q template.\n", err, apply.TemplateFor(prompt))
+	}
+	subject, body, err := apply.GenerateEmail(j, p, c.llmClient, prompt, answers...)
+	if errors.Is(err, offline.ErrOffline) {
+		fmt.Fprintf(w, "%v; using the built-in %q template.\n", err, apply.TemplateFor(prompt))
+		return apply.RenderTemplate(apply.TemplateFor(prompt), j, p, answers...)
+	}
+	return subject, body, err
+}
+
+// lintAddresses warns when the CV or signature carries a different address
+// than the one the application goes out from, on out, and optionally
+// fixes the CV.
+func (c *CLI) lintAddresses(out io.Writer, p profile.Profile, from, body string, fix bool) {
+	for _, w := range apply.CheckAddresses(apply.ComposedAddresses(p, from, body, c.scratchAddress())) {
+		fmt.Fprintf(out, "! %s\n", w)
+		if w.Role != apply.RoleCV {
+			continue
+		}
+		if !fix {
+			fmt.Fprintf(out, "  Pass --fix-cv to rewrite the CV contact to %s.\n", w.Want)
+			continue
+		}
+		if err := apply.FixCVContact(p, w.Want); err != nil {
+			fmt.Fprintf(out, "  Could not fix CV: %v\n", err)
+			continue
+		}
+		fmt.Fprintf(out, "  CV contact rewritten to %s; rebuild the PDF before sending.\n", w.Want)
+	}
+}
+
+// scratchAddress is the scratch address applications go out from: the
+// newest active one, or "" when there is none.
+func (c *CLI) scratchAddress() string {
+	if c.scratch == nil {
+		return ""
+	}
+	active, err := c.scratch.Active()
+	if err != nil || len(active) == 0 {
+		return ""
+	}
+	return active[len(active)-1].Address
+}
+
+func severityMark(s apply.Severity) string {
+	switch s {
+	case apply.SeverityOK:
+		return "✓"
+	case apply.SeverityWarning:
+		return "!"
+	}
+	return "✗"
+}
+
+func (c *CLI) handleHide() {
+	fs := flag.NewFlagSet("hide", flag.ExitOnError)
+	profileID := fs.String("profile", c.defaultProfile(), "Profile to hide the job in")
+	undo := fs.Bool("undo", false, "Unhide the job instead")
+	fs.Parse(os.Args[2:])
+
+	if fs.NArg() == 0 {
+		fmt.Println("Error: job ID is required")
+		return
+	}
+	updated := 0
+	for _, ref := range fs.Args() {
+		j, err := c.store.Resolve(ref)
+		if err == nil {
+			err = c.store.SetHidden(j.ID, *profileID, !*undo)
+		}
 		if err != nil {
-			fmt.Printf("Failed to send: %v\n", err)
-		} else {
-			fmt.Printf("Email sent successfully to %s!\n", j.Email)
+			fmt.Printf("Failed to update %s: %v\n", ref, err)
+			continue
 		}
+		updated++
 	}
+	fmt.Printf("Updated %d job(s) in profile %s.\n", updated, *profileID)
 }
 
 func (c *CLI) handleProfile() {
-	// Stub for now
-	profiles, _ := c.profileStore.All()
+	if len(os.Args) > 2 && os.Args[2] == "edit" {
+		c.handleProfileEdit(os.Args[3:])
+		return
+	}
+	if len(os.Args) > 2 && os.Args[2] == "boards" {
+		if err := c.profileBoards(os.Stdout, os.Args[3:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+		return
+	}
+	fs := flag.NewFlagSet("profile", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print the profiles as JSON")
+	fromCV := fs.String("create-from-cv", "", "Create a profile from a CV: a PDF, a .docx or text")
+	name := fs.String("name", "", "Name of the profile --create-from-cv creates (default: the name on the CV)")
+	exportTo := fs.String("export", "", "Write the profile to this file, JSON or, by its extension, YAML")
+	profileID := fs.String("profile", c.defaultProfile(), "Profile to --export")
+	strip := fs.Bool("strip-personal", false, "Leave the contact email, CV and cover letter out of the export, to share it")
+	includeCV := fs.Bool("include-cv", false, "Inline the parsed CV in the export, for a full backup")
+	importPath := fs.String("import", "", "Create a profile from an exported profile, JSON or YAML")
+	onConflict := fs.String("on-conflict", "", "When the import's ID or name is taken: copy, overwrite or merge (asks when interactive, else copy)")
+	fs.Parse(os.Args[2:])
+
+	switch {
+	case *fromCV != "":
+		if err := c.createProfileFromCV(*fromCV, *name); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+		return
+	case *exportTo != "":
+		if err := c.exportProfile(*profileID, *exportTo, *strip, *includeCV); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+		return
+	case *importPath != "":
+		if err := c.importCV(os.Stdin, isTerminal(os.Stdin), *importPath, *onConflict); err != nil {
+			fmt.Printf("Import failed: %v\n", err)
+		}
+		return
+	}
+
+	profiles, err := c.profileStore.All()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if *asJSON {
+		writeJSON(os.Stdout, profiles)
+		return
+	}
 	for _, p := range profiles {
 		fmt.Printf("- %s (%s)\n", p.Name, p.ID)
 	}
@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/contact"
+	"sprayer/src/api/job"
+	"sprayer/src/api/outbox"
+	"sprayer/src/api/profile"
+)
+
+// newHomeCLI is the CLI as main builds it, on a database in a temporary
+// home directory.
+func newHomeCLI(t *testing.T) *CLI {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	c, err := NewCLI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.store.Close() })
+	return c
+}
+
+// runCommand runs `sprayer args...` through Run and returns what it
+// printed.
+func runCommand(t *testing.T, c *CLI, args ...string) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, argv := os.Stdout, os.Args
+	os.Stdout, os.Args = w, append([]string{"sprayer"}, args...)
+	out := make(chan string)
+	go func() {
+		var b bytes.Buffer
+		io.Copy(&b, r)
+		out <- b.String()
+	}()
+	defer func() {
+		os.Stdout, os.Args = stdout, argv
+	}()
+	c.Run()
+	w.Close()
+	return <-out
+}
+
+func TestRun_JSON(t *testing.T) {
+	c := newHomeCLI(t)
+	now := time.Now()
+	j := job.Job{ID: "a-1", Title: "Go Engineer", Company: "Acme", Email: "jobs@acme.com", Score: 80, PostedDate: now}
+	open := job.Job{ID: "g-1", Title: "Rust Developer", Company: "Globex", Score: 70, PostedDate: now, Language: "en"}
+	if err := c.store.Save([]job.Job{j, open}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.profileStore.Save(profile.Profile{ID: "default", Name: "Jane", MaxScore: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.profileStore.SavePreset(profile.FilterPreset{Name: "rust", Keywords: []string{"rust"}}); err != nil {
+		t.Fatal(err)
+	}
+	// Applied ten days ago, so its follow-up is due.
+	if err := c.store.MarkApplied(j.ID, now.AddDate(0, 0, -10), 7*24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	c.recordApplication(j, profile.Profile{ID: "default"}, application.MethodEmail, "Dear Acme,", nil)
+	if err := c.contactStore.Save(&contact.Contact{Name: "Ann", Email: "ann@acme.com", Company: "Acme", Source: contact.SourceReferral}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.outbox.Enqueue(&outbox.Message{JobID: j.ID, To: j.Email, Subject: "Go Engineer"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		n    int
+		want string // a field of an item listed
+	}{
+		{[]string{"list", "--json"}, 2, `"title": "Go Engineer"`},
+		{[]string{"filter", "--json", "--preset", "rust"}, 1, `"title": "Rust Developer"`},
+		{[]string{"filter", "presets", "--json"}, 1, `"name": "rust"`},
+		{[]string{"applications", "--json"}, 1, `"company": "Acme"`},
+		{[]string{"followups", "list", "--json"}, 1, `"id": "a-1"`},
+		{[]string{"outbox", "list", "--json"}, 1, `"to": "jobs@acme.com"`},
+		{[]string{"contacts", "list", "--json"}, 1, `"email": "ann@acme.com"`},
+		{[]string{"contacts", "search", "--json", "ann"}, 1, `"email": "ann@acme.com"`},
+		{[]string{"profile", "--json"}, 1, `"name": "Jane"`},
+	} {
+		out := runCommand(t, c, tc.args...)
+		var items []map[string]any
+		if err := json.Unmarshal([]byte(out), &items); err != nil {
+			t.Errorf("%v: not JSON: %v\n%s", tc.args, err, out)
+			continue
+		}
+		if len(items) != tc.n || !strings.Contains(out, tc.want) {
+			t.Errorf("%v: want %d item(s) with %s, got\n%s", tc.args, tc.n, tc.want, out)
+		}
+	}
+
+	// Nothing to list is an empty array, not null.
+	for _, args := range [][]string{{"inbox", "list", "--json"}, {"scrape", "history", "--json"}} {
+		if out := runCommand(t, c, args...); strings.TrimSpace(out) != "[]" {
+			t.Errorf("%v: got %q, want []", args, out)
+		}
+	}
+}
+
+// TestUsage_ListsDispatchedCommands keeps help to the commands Run knows,
+// and every one of them in it.
+func TestUsage_ListsDispatchedCommands(t *testing.T) {
+	src, err := os.ReadFile("cli.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := string(src)
+	run = run[strings.Index(run, "func (c *CLI) Run()"):]
+	run = run[:strings.Index(run, "\n}\n")]
+	dispatched := make(map[string]bool)
+	for _, m := range regexp.MustCompile(`case "([a-z-]+)"`).FindAllStringSubmatch(run, -1) {
+		dispatched[m[1]] = true
+	}
+	delete(dispatched, "__complete")
+
+	commands := usage[strings.Index(usage, "Commands:"):]
+	commands = commands[:strings.Index(commands, "\n\n")]
+	listed := make(map[string]bool)
+	for _, m := range regexp.MustCompile(`(?m)^  ([a-z]+) `).FindAllStringSubmatch(commands, -1) {
+		if listed[m[1]] {
+			t.Errorf("help lists %s twice", m[1])
+		}
+		listed[m[1]] = true
+		if !dispatched[m[1]] {
+			t.Errorf("help lists %s, which Run does not dispatch", m[1])
+		}
+	}
+	for name := range dispatched {
+		if !listed[name] {
+			t.Errorf("help leaves out %s", name)
+		}
+	}
+
+	if out := runCommand(t, newTestCLI(t), "help"); out != usage+"\n" {
+		t.Errorf("sprayer help printed\n%s", out)
+	}
+}
@@ -0,0 +1,576 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"sprayer/src/api/apply"
+)
+
+// argKind is what a flag value or positional argument holds, which decides
+// how the shell completes it.
+type argKind int
+
+const (
+	argNone    argKind = iota // boolean flag, or no positional arguments
+	argValue                  // free text, not completed
+	argJob                    // job ID, from `sprayer __complete jobs`
+	argProfile                // profile ID, from `sprayer __complete profiles`
+	argFile
+	argChoice // one of spec.Choices
+)
+
+type flagSpec struct {
+	Name    string
+	Arg     argKind
+	Choices []string
+}
+
+// commandSpec describes a command for completion: its flags, positional
+// arguments and subcommands. It is kept by hand next to the handlers, so
+// a new flag needs adding here too.
+type commandSpec struct {
+	Name    string
+	Summary string
+	Flags   []flagSpec
+	Args    argKind
+	Choices []string
+	Subs    []commandSpec
+}
+
+var (
+	profileFlag = flagSpec{Name: "profile", Arg: argProfile}
+	jsonFlag    = flagSpec{Name: "json"}
+)
+
+func commandSpecs() []commandSpec {
+	templates := apply.BuiltinTemplates()
+	return []commandSpec{
+		{Name: "scrape", Summary: "Fetch jobs from all sources", Flags: []flagSpec{
+			{Name: "fast"}, {Name: "force"}, {Name: "yes"}, profileFlag, {Name: "sources", Arg: argValue},
+		}, Args: argValue, Subs: []commandSpec{
+			{Name: "history", Summary: "List recent scrapes", Flags: []flagSpec{{Name: "n", Arg: argValue}, jsonFlag}},
+		}},
+		{Name: "sources", Summary: "How each scraper source has fared", Subs: []commandSpec{
+			{Name: "status", Summary: "Runs, jobs, errors and latency per source", Flags: []flagSpec{{Name: "zero-runs", Arg: argValue}, jsonFlag}},
+		}},
+		{Name: "daemon", Summary: "Scrape on a schedule", Flags: []flagSpec{
+			{Name: "every", Arg: argValue}, profileFlag, {Name: "max-jobs", Arg: argValue},
+			{Name: "webhook", Arg: argValue}, {Name: "email"}, {Name: "email-to", Arg: argValue}, {Name: "once"},
+		}},
+		{Name: "list", Summary: "List and filter jobs", Flags: []flagSpec{
+			{Name: "keywords", Arg: argValue}, {Name: "min-score", Arg: argValue}, profileFlag, {Name: "all"},
+			{Name: "closing-soon"}, {Name: "closing-window", Arg: argValue}, {Name: "match"},
+			{Name: "status", Arg: argValue},
+			{Name: "format", Arg: argChoice, Choices: []string{"csv", "markdown", "json"}}, {Name: "columns", Arg: argValue},
+			{Name: "sort", Arg: argChoice, Choices: []string{"score", "posted_date", "title", "company", "-score", "-posted_date", "-title", "-company"}},
+			{Name: "preset", Arg: argValue}, jsonFlag,
+		}},
+		{Name: "filter", Summary: "List the profile's matches through a filter preset", Flags: []flagSpec{
+			profileFlag, {Name: "preset", Arg: argValue}, {Name: "all"}, {Name: "keywords", Arg: argValue},
+			{Name: "min-score", Arg: argValue}, {Name: "status", Arg: argValue}, {Name: "sort", Arg: argValue}, jsonFlag,
+		}, Subs: []commandSpec{
+			{Name: "presets", Summary: "List saved filter presets", Flags: []flagSpec{jsonFlag}},
+			{Name: "save", Summary: "Save a filter preset", Flags: []flagSpec{
+				{Name: "keywords", Arg: argValue}, {Name: "exclude", Arg: argValue}, {Name: "locations", Arg: argValue},
+				{Name: "tech", Arg: argValue}, {Name: "min-score", Arg: argValue}, {Name: "max-score", Arg: argValue},
+				{Name: "days", Arg: argValue}, {Name: "remote"}, {Name: "has-email"}, {Name: "no-traps"}, {Name: "hide-applied"},
+			}, Args: argValue},
+			{Name: "rm", Summary: "Delete a filter preset", Args: argValue},
+		}},
+		{Name: "apply", Summary: "Apply to a job", Flags: []flagSpec{
+			{Name: "job", Arg: argJob}, profileFlag, {Name: "prompt", Arg: argValue}, {Name: "template", Arg: argChoice, Choices: templates},
+			{Name: "llm-only"}, {Name: "send"}, {Name: "draft"}, {Name: "print"}, {Name: "cover-letter"},
+			{Name: "cv", Arg: argChoice, Choices: []string{"original", "custom"}},
+			{Name: "force"}, {Name: "fix-cv"}, {Name: "exclude", Arg: argValue},
+			{Name: "tailor-cv"}, {Name: "regenerate-cv"},
+		}, Args: argJob},
+		{Name: "hide", Summary: "Hide a job in a profile's list", Flags: []flagSpec{profileFlag, {Name: "undo"}}, Args: argJob},
+		{Name: "jobs", Summary: "Manage stored jobs", Subs: []commandSpec{
+			{Name: "dedup", Summary: "Merge duplicate jobs"},
+			{Name: "enrich", Summary: "Enrich stored jobs", Flags: []flagSpec{{Name: "force"}}},
+			{Name: "status", Summary: "Show or change a job's status", Flags: []flagSpec{{Name: "note", Arg: argValue}}, Args: argJob},
+		}},
+		{Name: "followups", Summary: "Applied jobs due a follow-up", Flags: []flagSpec{{Name: "mark-done", Arg: argJob}}, Subs: []commandSpec{
+			{Name: "list", Summary: "List follow-ups due", Flags: []flagSpec{{Name: "all"}, jsonFlag}},
+		}},
+		{Name: "profile", Summary: "Manage profiles", Flags: []flagSpec{
+			jsonFlag, {Name: "create-from-cv", Arg: argFile}, {Name: "name", Arg: argValue},
+			{Name: "export", Arg: argFile}, profileFlag, {Name: "strip-personal"}, {Name: "include-cv"},
+			{Name: "import", Arg: argFile}, {Name: "on-conflict", Arg: argChoice, Choices: []string{"copy", "overwrite", "merge"}},
+		}, Subs: []commandSpec{
+			{Name: "edit", Summary: "Edit a profile", Args: argProfile},
+			{Name: "boards", Summary: "Greenhouse boards a profile scrapes", Flags: []flagSpec{profileFlag}, Subs: []commandSpec{
+				{Name: "add", Summary: "Add boards by token or job URL", Flags: []flagSpec{profileFlag}, Args: argValue},
+				{Name: "rm", Summary: "Remove boards", Flags: []flagSpec{profileFlag}, Args: argValue},
+			}},
+		}},
+		{Name: "applications", Summary: "List applications", Flags: []flagSpec{
+			{Name: "report"}, {Name: "from", Arg: argValue}, {Name: "to", Arg: argValue},
+			{Name: "format", Arg: argChoice, Choices: []string{"markdown", "csv", "pdf"}},
+			{Name: "out", Arg: argFile}, {Name: "redact-notes"},
+			{Name: "withdraw"}, {Name: "status", Arg: argValue}, {Name: "except", Arg: argValue},
+			{Name: "reason", Arg: argValue}, {Name: "send"}, {Name: "yes"}, jsonFlag,
+		}, Subs: []commandSpec{
+			{Name: "show", Summary: "Show an application and its thread", Args: argValue},
+		}},
+		{Name: "reply", Summary: "Reply to a recruiter email", Flags: []flagSpec{
+			{Name: "app", Arg: argValue}, {Name: "m", Arg: argValue}, {Name: "send"},
+		}, Args: argFile},
+		{Name: "contacts", Summary: "Recruiters and referrers you know", Subs: []commandSpec{
+			{Name: "list", Summary: "List contacts", Flags: []flagSpec{jsonFlag}},
+			{Name: "search", Summary: "Search contacts", Flags: []flagSpec{jsonFlag}, Args: argValue},
+			{Name: "add", Summary: "Add a referral", Flags: []flagSpec{
+				{Name: "email", Arg: argValue}, {Name: "name", Arg: argValue}, {Name: "company", Arg: argValue}, {Name: "notes", Arg: argValue},
+			}},
+			{Name: "duplicates", Summary: "List contacts that may be the same person"},
+			{Name: "merge", Summary: "Merge two contacts", Args: argValue},
+		}},
+		{Name: "batch", Summary: "Draft, review and send applications in bulk", Subs: []commandSpec{
+			{Name: "new", Summary: "Draft a batch", Flags: []flagSpec{
+				profileFlag, {Name: "prompt", Arg: argValue}, {Name: "template", Arg: argChoice, Choices: templates},
+			}, Args: argJob},
+			{Name: "list", Summary: "List batches"},
+			{Name: "show", Summary: "Show a batch", Args: argValue},
+			{Name: "approve", Summary: "Approve items", Args: argValue},
+			{Name: "skip", Summary: "Skip items", Args: argValue},
+			{Name: "send", Summary: "Send approved items", Args: argValue},
+			{Name: "retry", Summary: "Retry failed items", Args: argValue},
+			{Name: "abandon", Summary: "Abandon a batch", Args: argValue},
+		}},
+		{Name: "outbox", Summary: "Emails waiting to go out", Subs: []commandSpec{
+			{Name: "list", Summary: "List queued and failed messages", Flags: []flagSpec{jsonFlag}},
+			{Name: "flush", Summary: "Send what is due"},
+			{Name: "retry", Summary: "Send a message now", Args: argValue},
+			{Name: "rm", Summary: "Delete a message", Args: argValue},
+		}},
+		{Name: "inbox", Summary: "Replies arriving in your mailboxes", Subs: []commandSpec{
+			{Name: "list", Summary: "List received messages", Flags: []flagSpec{jsonFlag}},
+			{Name: "poll", Summary: "Check the mailboxes once"},
+			{Name: "watch", Summary: "Keep checking until interrupted"},
+			{Name: "scratch", Summary: "Throwaway addresses to apply from", Subs: []commandSpec{
+				{Name: "new", Summary: "Create an address"},
+				{Name: "rm", Summary: "Deactivate an address", Args: argValue},
+			}},
+		}},
+		{Name: "llm", Summary: "LLM calls made", Subs: []commandSpec{
+			{Name: "usage", Summary: "Tokens and cost per purpose", Flags: []flagSpec{{Name: "since", Arg: argValue}}},
+		}},
+		{Name: "rescore", Summary: "Recompute job scores", Flags: []flagSpec{profileFlag, {Name: "explain"}}},
+		{Name: "questions", Summary: "Answer a job's application questions", Subs: []commandSpec{
+			{Name: "list", Summary: "List a job's questions", Flags: []flagSpec{profileFlag}, Args: argJob},
+			{Name: "detect", Summary: "Find questions in the posting", Flags: []flagSpec{profileFlag}, Args: argJob},
+			{Name: "add", Summary: "Add a question", Flags: []flagSpec{profileFlag}, Args: argJob},
+			{Name: "answer", Summary: "Answer a question", Flags: []flagSpec{{Name: "draft"}, {Name: "m", Arg: argValue}}, Args: argValue},
+			{Name: "search", Summary: "Search previous answers", Args: argValue},
+			{Name: "rm", Summary: "Delete a question", Args: argValue},
+		}},
+		{Name: "cv", Summary: "Export, import or generate a CV", Flags: []flagSpec{
+			profileFlag, {Name: "export-jsonresume", Arg: argFile}, {Name: "import", Arg: argFile},
+			{Name: "on-conflict", Arg: argChoice, Choices: []string{"copy", "overwrite", "merge"}},
+			{Name: "generate", Arg: argJob}, {Name: "template", Arg: argChoice, Choices: apply.CVTemplates()},
+		}, Subs: []commandSpec{
+			{Name: "inspect", Summary: "Print what is read from a CV file", Flags: []flagSpec{jsonFlag}, Args: argFile},
+		}},
+		{Name: "setup", Summary: "Configure SMTP and LLM settings"},
+		{Name: "settings", Summary: "Show, export or import the settings", Flags: []flagSpec{
+			{Name: "show"}, {Name: "export", Arg: argFile}, {Name: "import", Arg: argFile},
+		}},
+		{Name: "doctor", Summary: "Check dependencies", Flags: []flagSpec{{Name: "offline"}}},
+		{Name: "traps", Summary: "List, lint and test trap rules", Flags: []flagSpec{
+			{Name: "lint", Arg: argFile}, {Name: "test", Arg: argValue},
+		}},
+		{Name: "rules", Summary: "Tag, rescore or hide jobs as they are scraped", Subs: []commandSpec{
+			{Name: "list", Summary: "List rules and their hit counts"},
+			{Name: "add", Summary: "Add a rule", Flags: []flagSpec{
+				{Name: "name", Arg: argValue}, {Name: "when", Arg: argValue}, {Name: "do", Arg: argValue},
+			}},
+			{Name: "test", Summary: "Show which stored jobs rules match", Flags: []flagSpec{
+				{Name: "when", Arg: argValue}, {Name: "do", Arg: argValue},
+			}, Args: argValue},
+			{Name: "enable", Summary: "Enable a rule", Args: argValue},
+			{Name: "disable", Summary: "Disable a rule", Args: argValue},
+			{Name: "rm", Summary: "Delete a rule", Args: argValue},
+			{Name: "edit", Summary: "Toggle rules in a list"},
+		}},
+		{Name: "completion", Summary: "Print a shell completion script", Args: argChoice, Choices: []string{"bash", "zsh", "fish"}},
+		{Name: "help", Summary: "List the commands"},
+	}
+}
+
+// handleCompletion prints the completion script for a shell.
+func (c *CLI) handleCompletion() {
+	if len(os.Args) < 3 {
+		fmt.Println("Usage: sprayer completion bash|zsh|fish")
+		return
+	}
+	var err error
+	switch os.Args[2] {
+	case "bash":
+		err = writeBashCompletion(os.Stdout, commandSpecs())
+	case "zsh":
+		err = writeZshCompletion(os.Stdout, commandSpecs())
+	case "fish":
+		err = writeFishCompletion(os.Stdout, commandSpecs())
+	default:
+		fmt.Printf("Unknown shell %q (want bash, zsh or fish)\n", os.Args[2])
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+}
+
+// handleComplete serves the completion scripts: `__complete jobs
+// [profile]` and `__complete profiles` print one candidate per line as
+// "id<TAB>label". Errors print nothing, so a broken store never garbles
+// the shell.
+func (c *CLI) handleComplete() {
+	args := os.Args[2:]
+	if len(args) == 0 {
+		return
+	}
+	profileID := c.defaultProfile()
+	if len(args) > 1 && args[1] != "" {
+		profileID = args[1]
+	}
+	c.complete(os.Stdout, args[0], profileID)
+}
+
+func (c *CLI) complete(w io.Writer, kind, profileID string) error {
+	switch kind {
+	case "jobs":
+		// Only the profile's active jobs: hidden, archived and expired
+		// ones are not worth offering.
+		jobs, err := c.store.ForProfile(profileID)
+		if err != nil {
+			return err
+		}
+		for _, j := range jobs {
+			fmt.Fprintf(w, "%s\t%s\n", j.ID, completionLabel(j.Company+" — "+j.Title))
+		}
+	case "profiles":
+		profiles, err := c.profileStore.All()
+		if err != nil {
+			return err
+		}
+		for _, p := range profiles {
+			fmt.Fprintf(w, "%s\t%s\n", p.ID, completionLabel(p.Name))
+		}
+	default:
+		return fmt.Errorf("unknown completion %q", kind)
+	}
+	return nil
+}
+
+// completionLabel keeps a label on one line, without tabs, and short
+// enough for a completion menu.
+func completionLabel(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > 60 {
+		s = string(r[:59]) + "…"
+	}
+	return s
+}
+
+// commandWords lists the names of commands or subcommands.
+func commandWords(specs []commandSpec) string {
+	names := make([]string, len(specs))
+	for i, s := range specs {
+		names[i] = s.Name
+	}
+	return strings.Join(names, " ")
+}
+
+// flagWords lists flags as --name.
+func flagWords(flags []flagSpec) string {
+	var names []string
+	for _, f := range flags {
+		names = append(names, "--"+f.Name)
+	}
+	return strings.Join(names, " ")
+}
+
+// specPath is a command or "command subcommand" with its spec.
+type specPath struct {
+	Path string
+	Spec commandSpec
+}
+
+// specPaths flattens specs into commands and their subcommands.
+func specPaths(specs []commandSpec) []specPath {
+	var out []specPath
+	for _, s := range specs {
+		out = append(out, specPath{s.Name, s})
+		for _, sub := range s.Subs {
+			out = append(out, specPath{s.Name + " " + sub.Name, sub})
+		}
+	}
+	return out
+}
+
+func writeBashCompletion(w io.Writer, specs []commandSpec) error {
+	var b strings.Builder
+	b.WriteString(`# bash completion for sprayer; load with: source <(sprayer completion bash)
+
+# _sprayer_ids completes $cur with IDs of kind $1, shown with their labels
+# while more than one matches: "a1b2c3 (Acme — Go Engineer)".
+_sprayer_ids() {
+    local profile=default i id label
+    local -a ids=() shown=()
+    for ((i = 1; i < ${#COMP_WORDS[@]}; i++)); do
+        case "${COMP_WORDS[i]}" in -profile|--profile) profile="${COMP_WORDS[i+1]}" ;; esac
+    done
+    while IFS=$'\t' read -r id label; do
+        [[ $id == "$cur"* ]] || continue
+        ids+=("$id")
+        shown+=("$id ($label)")
+    done < <(sprayer __complete "$1" "$profile" 2>/dev/null)
+    if ((${#ids[@]} == 1)); then
+        COMPREPLY=("${ids[@]}")
+    else
+        COMPREPLY=("${shown[@]}")
+    fi
+}
+
+_sprayer() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"
+    local cmdpath="${COMP_WORDS[1]}" words=""
+    COMPREPLY=()
+    if ((COMP_CWORD == 1)); then
+        COMPREPLY=($(compgen -W "` + commandWords(specs) + `" -- "$cur"))
+        return
+    fi
+    case "$cmdpath" in
+`)
+	for _, s := range specs {
+		if len(s.Subs) > 0 {
+			fmt.Fprintf(&b, "        %s) ((COMP_CWORD > 2)) && cmdpath=\"$cmdpath ${COMP_WORDS[2]}\" ;;\n", s.Name)
+		}
+	}
+	b.WriteString("    esac\n\n    case \"$cmdpath\" in\n")
+	for _, p := range specPaths(specs) {
+		fmt.Fprintf(&b, "        %q)\n", p.Path)
+		for _, f := range p.Spec.Flags {
+			if f.Arg == argNone {
+				continue
+			}
+			fmt.Fprintf(&b, "            case \"$prev\" in -%[1]s|--%[1]s) %s; return ;; esac\n", f.Name, bashReply(f.Arg, f.Choices))
+		}
+		words := flagWords(p.Spec.Flags)
+		if subs := commandWords(p.Spec.Subs); subs != "" {
+			words = strings.TrimSpace(subs + " " + words)
+		}
+		fmt.Fprintf(&b, "            words=%q\n", words)
+		switch p.Spec.Args {
+		case argJob, argProfile:
+			fmt.Fprintf(&b, "            [[ $cur != -* ]] && { _sprayer_ids %s; return; }\n", idKind(p.Spec.Args))
+		case argFile:
+			b.WriteString("            [[ $cur != -* ]] && { COMPREPLY=($(compgen -f -- \"$cur\")); return; }\n")
+		case argChoice:
+			fmt.Fprintf(&b, "            words=\"$words %s\"\n", strings.Join(p.Spec.Choices, " "))
+		}
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString(`    esac
+    COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+
+complete -F _sprayer sprayer
+`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// bashReply is the bash statement completing a value of kind.
+func bashReply(kind argKind, choices []string) string {
+	switch kind {
+	case argJob, argProfile:
+		return "_sprayer_ids " + idKind(kind)
+	case argFile:
+		return `COMPREPLY=($(compgen -f -- "$cur"))`
+	case argChoice:
+		return fmt.Sprintf(`COMPREPLY=($(compgen -W %q -- "$cur"))`, strings.Join(choices, " "))
+	}
+	return "COMPREPLY=()"
+}
+
+func idKind(kind argKind) string {
+	if kind == argProfile {
+		return "profiles"
+	}
+	return "jobs"
+}
+
+func writeZshCompletion(w io.Writer, specs []commandSpec) error {
+	var b strings.Builder
+	b.WriteString(`#compdef sprayer
+# zsh completion for sprayer; load with: source <(sprayer completion zsh)
+
+_sprayer_ids() {
+    local profile=default i
+    local -a items
+    i=${words[(I)-profile|--profile]}
+    ((i > 0)) && profile=${words[i+1]}
+    items=(${(f)"$(sprayer __complete $1 $profile 2>/dev/null | sed -e 's/:/\\:/g' -e $'s/\t/:/')"})
+    _describe -t $1 $1 items
+}
+
+_sprayer() {
+    local -a commands
+    commands=(
+`)
+	for _, s := range specs {
+		fmt.Fprintf(&b, "        %s\n", zshQuote(s.Name+":"+s.Summary))
+	}
+	b.WriteString(`    )
+    if ((CURRENT == 2)); then
+        _describe -t commands command commands
+        return
+    fi
+    # not "path": zsh ties that to $PATH
+    local cmdpath=$words[2] prev=$words[CURRENT-1]
+    case $cmdpath in
+`)
+	for _, s := range specs {
+		if len(s.Subs) > 0 {
+			fmt.Fprintf(&b, "        %s) ((CURRENT > 3)) && cmdpath=\"$cmdpath $words[3]\" ;;\n", s.Name)
+		}
+	}
+	b.WriteString("    esac\n\n    case $cmdpath in\n")
+	for _, p := range specPaths(specs) {
+		fmt.Fprintf(&b, "        %s)\n", zshQuote(p.Path))
+		for _, f := range p.Spec.Flags {
+			if f.Arg == argNone {
+				continue
+			}
+			fmt.Fprintf(&b, "            case $prev in -%[1]s|--%[1]s) %s; return ;; esac\n", f.Name, zshReply(f.Arg, f.Choices))
+		}
+		if len(p.Spec.Subs) > 0 {
+			b.WriteString("            ((CURRENT == 3)) && {\n                local -a subs\n                subs=(")
+			for i, sub := range p.Spec.Subs {
+				if i > 0 {
+					b.WriteString(" ")
+				}
+				b.WriteString(zshQuote(sub.Name + ":" + sub.Summary))
+			}
+			b.WriteString(")\n                _describe -t commands subcommand subs\n            }\n")
+		}
+		if flags := flagWords(p.Spec.Flags); flags != "" {
+			fmt.Fprintf(&b, "            compadd -- %s\n", flags)
+		}
+		if p.Spec.Args != argNone && p.Spec.Args != argValue {
+			fmt.Fprintf(&b, "            %s\n", zshReply(p.Spec.Args, p.Spec.Choices))
+		}
+		b.WriteString("            ;;\n")
+	}
+	b.WriteString(`    esac
+}
+
+compdef _sprayer sprayer
+`)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func zshReply(kind argKind, choices []string) string {
+	switch kind {
+	case argJob, argProfile:
+		return "_sprayer_ids " + idKind(kind)
+	case argFile:
+		return "_files"
+	case argChoice:
+		return "compadd -- " + strings.Join(choices, " ")
+	}
+	return ":"
+}
+
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func writeFishCompletion(w io.Writer, specs []commandSpec) error {
+	var b strings.Builder
+	b.WriteString(`# fish completion for sprayer; load with: sprayer completion fish | source
+
+function __sprayer_ids
+    set -l profile default
+    set -l words (commandline -opc)
+    for i in (seq (count $words))
+        if contains -- $words[$i] -profile --profile; and test $i -lt (count $words)
+            set profile $words[(math $i + 1)]
+        end
+    end
+    sprayer __complete $argv[1] $profile 2>/dev/null
+end
+
+# __sprayer_using succeeds when the command line is sprayer $argv[1] or,
+# given a second argument, sprayer $argv[1] $argv[2]. Subcommand names
+# repeat across commands (list, show, send), so position matters.
+function __sprayer_using
+    set -l words (commandline -opc)
+    test "$words[2]" = "$argv[1]"; or return 1
+    test (count $argv) -eq 1; or test "$words[3]" = "$argv[2]"
+end
+
+function __sprayer_needs_sub
+    set -l words (commandline -opc)
+    test (count $words) -eq 2; and test "$words[2]" = "$argv[1]"
+end
+
+complete -c sprayer -f
+`)
+	for _, s := range specs {
+		fmt.Fprintf(&b, "complete -c sprayer -n __fish_use_subcommand -a %s -d %s\n", s.Name, fishQuote(s.Summary))
+	}
+	for _, s := range specs {
+		for _, sub := range s.Subs {
+			fmt.Fprintf(&b, "complete -c sprayer -n %s -a %s -d %s\n",
+				fishQuote("__sprayer_needs_sub "+s.Name), sub.Name, fishQuote(sub.Summary))
+		}
+		writeFishSpec(&b, "__sprayer_using "+s.Name, s)
+		for _, sub := range s.Subs {
+			writeFishSpec(&b, "__sprayer_using "+s.Name+" "+sub.Name, sub)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeFishSpec(b *strings.Builder, cond string, s commandSpec) {
+	for _, f := range s.Flags {
+		fmt.Fprintf(b, "complete -c sprayer -n %s -l %s%s\n", fishQuote(cond), f.Name, fishArg(f.Arg, f.Choices, true))
+	}
+	if s.Args != argNone && s.Args != argValue {
+		fmt.Fprintf(b, "complete -c sprayer -n %s%s\n", fishQuote(cond), fishArg(s.Args, s.Choices, false))
+	}
+}
+
+// fishArg is the tail of a complete line for a value of kind; flag values
+// need -x or -r so fish knows the flag takes one. Files are otherwise off.
+func fishArg(kind argKind, choices []string, flag bool) string {
+	takes := ""
+	if flag {
+		takes = " -x"
+	}
+	switch kind {
+	case argJob, argProfile:
+		return takes + " -a " + fishQuote("(__sprayer_ids "+idKind(kind)+")")
+	case argFile:
+		if flag {
+			return " -r -F"
+		}
+		return " -F"
+	case argChoice:
+		return takes + " -a " + fishQuote(strings.Join(choices, " "))
+	case argValue:
+		return takes
+	}
+	return ""
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
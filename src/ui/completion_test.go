@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+var candidateLine = regexp.MustCompile(`^[^\t\n]+\t[^\t\n]+$`)
+
+func TestComplete_Jobs(t *testing.T) {
+	c := newTestCLI(t)
+	jobs := []job.Job{
+		{ID: "a1b2c3", Title: "Go Engineer", Company: "Acme"},
+		{ID: "d4e5f6", Title: "Rust\tDeveloper,\nplatform team " + strings.Repeat("x", 80), Company: "Initech"},
+		{ID: "hidden1", Title: "Hidden", Company: "Globex"},
+	}
+	if err := c.store.Save(jobs); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.store.SetHidden("hidden1", "alice", true); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := c.complete(&out, "jobs", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d candidates, want the 2 jobs active in alice's profile:\n%s", len(lines), out.String())
+	}
+	for _, l := range lines {
+		if !candidateLine.MatchString(l) || len([]rune(l)) > 80 {
+			t.Errorf("malformed candidate %q", l)
+		}
+	}
+	if !strings.Contains(out.String(), "a1b2c3\tAcme — Go Engineer\n") {
+		t.Errorf("missing labelled job:\n%s", out.String())
+	}
+
+	out.Reset()
+	c.complete(&out, "jobs", "default")
+	if !strings.Contains(out.String(), "hidden1\t") {
+		t.Error("a job hidden in another profile should still complete in this one")
+	}
+}
+
+func TestComplete_Profiles(t *testing.T) {
+	c := newTestCLI(t)
+	ps, err := profile.NewStore(c.store.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.profileStore = ps
+	if err := ps.Save(profile.Profile{ID: "alice", Name: "Alice Backend"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := c.complete(&out, "profiles", ""); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "alice\tAlice Backend\n" {
+		t.Errorf("got %q", out.String())
+	}
+	if err := c.complete(&out, "nope", ""); err == nil {
+		t.Error("unknown kind should fail")
+	}
+}
+
+// TestCompletion_CoversCommands keeps the completion registry in step
+// with the commands Run dispatches.
+func TestCompletion_CoversCommands(t *testing.T) {
+	src, err := os.ReadFile("cli.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := string(src)
+	run = run[strings.Index(run, "func (c *CLI) Run()"):]
+	run = run[:strings.Index(run, "\n}\n")]
+
+	known := make(map[string]bool)
+	for _, s := range commandSpecs() {
+		known[s.Name] = true
+	}
+	for _, m := range regexp.MustCompile(`case "([a-z-]+)":`).FindAllStringSubmatch(run, -1) {
+		if !known[m[1]] {
+			t.Errorf("command %q has no completion spec", m[1])
+		}
+	}
+
+	for shell, write := range map[string]func(*bytes.Buffer) error{
+		"bash": func(b *bytes.Buffer) error { return writeBashCompletion(b, commandSpecs()) },
+		"zsh":  func(b *bytes.Buffer) error { return writeZshCompletion(b, commandSpecs()) },
+		"fish": func(b *bytes.Buffer) error { return writeFishCompletion(b, commandSpecs()) },
+	} {
+		var b bytes.Buffer
+		if err := write(&b); err != nil {
+			t.Fatal(err)
+		}
+		for name := range known {
+			if !strings.Contains(b.String(), name) {
+				t.Errorf("%s script misses %s", shell, name)
+			}
+		}
+		if !strings.Contains(b.String(), "__complete") {
+			t.Errorf("%s script never asks for IDs", shell)
+		}
+		// Check the syntax with the shell itself where it is installed.
+		if path, err := exec.LookPath(shell); err == nil {
+			cmd := exec.Command(path, "-n")
+			cmd.Stdin = &b
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("%s -n: %v\n%s", shell, err, out)
+			}
+		}
+	}
+}
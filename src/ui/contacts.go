@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/apply"
+	"sprayer/src/api/contact"
+)
+
+const contactsUsage = `Usage:
+  sprayer contacts list [-json]
+  sprayer contacts search [-json] <text>
+  sprayer contacts add -email addr [-name n] [-company c] [-notes text]   (a referral)
+  sprayer contacts duplicates
+  sprayer contacts merge <keep-id> <drop-id>`
+
+func (c *CLI) handleContacts() {
+	if len(os.Args) < 3 {
+		fmt.Println(contactsUsage)
+		return
+	}
+	sub, args := os.Args[2], os.Args[3:]
+
+	var err error
+	switch sub {
+	case "list", "search":
+		fs := flag.NewFlagSet("contacts "+sub, flag.ExitOnError)
+		asJSON := fs.Bool("json", false, "Print the contacts as JSON")
+		fs.Parse(args)
+		var found []contact.Contact
+		if sub == "list" {
+			found, err = c.contactStore.All()
+		} else {
+			found, err = c.contactStore.Search(strings.Join(fs.Args(), " "))
+		}
+		switch {
+		case err != nil:
+		case *asJSON:
+			err = writeJSON(os.Stdout, found)
+		default:
+			c.printContacts(os.Stdout, found)
+		}
+	case "add":
+		err = c.addReferral(args)
+	case "duplicates":
+		var groups [][]contact.Contact
+		if groups, err = c.contactStore.Duplicates(); err == nil {
+			for _, g := range groups {
+				c.printContacts(os.Stdout, g)
+				fmt.Printf("  Merge with: sprayer contacts merge %d %d\n\n", g[0].ID, g[1].ID)
+			}
+		}
+	case "merge":
+		err = c.mergeContacts(args)
+	default:
+		fmt.Println(contactsUsage)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
+func (c *CLI) printContacts(w io.Writer, cs []contact.Contact) {
+	for _, ct := range cs {
+		at := ""
+		if ct.Company != "" {
+			at = " @ " + ct.Company
+		}
+		fmt.Fprintf(w, "%3d  %s <%s>%s  (%s, last %s)\n",
+			ct.ID, ct.Label(), ct.Email, at, ct.Source, ct.LastInteraction.Local().Format("2006-01-02"))
+	}
+}
+
+func (c *CLI) addReferral(args []string) error {
+	fs := flag.NewFlagSet("contacts add", flag.ExitOnError)
+	email := fs.String("email", "", "Email address (required)")
+	name := fs.String("name", "", "Full name")
+	company := fs.String("company", "", "Company they work at")
+	notes := fs.String("notes", "", "How you know them")
+	fs.Parse(args)
+
+	ct := &contact.Contact{Name: *name, Email: *email, Company: *company, Notes: *notes, Source: contact.SourceReferral}
+	if err := c.contactStore.Save(ct); err != nil {
+		return err
+	}
+	fmt.Printf("Saved contact %d: %s <%s>\n", ct.ID, ct.Label(), ct.Email)
+	return nil
+}
+
+func (c *CLI) mergeContacts(args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("merge needs the contact to keep and the one to fold into it")
+	}
+	keep, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid contact ID %q", args[0])
+	}
+	drop, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid contact ID %q", args[1])
+	}
+	if err := c.contactStore.Merge(keep, drop); err != nil {
+		return err
+	}
+	fmt.Printf("Merged contact %d into %d.\n", drop, keep)
+	return nil
+}
+
+// noteReplySender records the sender of a reply as a contact, at the
+// application's company and linked to it when appID is set.
+func (c *CLI) noteReplySender(orig apply.Message, appID int64) {
+	if c.contactStore == nil {
+		return
+	}
+	ct, err := contact.FromAddress(orig.From, contact.SourceReply, orig.Date)
+	if err != nil {
+		fmt.Printf("Warning: sender not saved as a contact: %v\n", err)
+		return
+	}
+	var a *application.Application
+	if appID > 0 {
+		if a, err = c.appStore.ByID(appID); err == nil {
+			ct.Company = a.Company
+		}
+	}
+	if err := c.contactStore.Save(&ct); err != nil {
+		fmt.Printf("Warning: sender not saved as a contact: %v\n", err)
+		return
+	}
+	if a != nil {
+		if err := c.contactStore.Link(ct.ID, a.ID); err != nil {
+			fmt.Printf("Warning: could not link contact: %v\n", err)
+		}
+	}
+}
+
+// knownAt prints the people already known at company, if any.
+func (c *CLI) knownAt(w io.Writer, company string) error {
+	if c.contactStore == nil {
+		return nil
+	}
+	known, err := c.contactStore.AtCompany(company)
+	if err != nil || len(known) == 0 {
+		return err
+	}
+	people := "person"
+	if len(known) > 1 {
+		people = "people"
+	}
+	lines := make([]string, len(known))
+	for i, ct := range known {
+		ids, err := c.contactStore.Applications(ct.ID)
+		if err != nil {
+			return err
+		}
+		var apps []application.Application
+		for _, id := range ids {
+			if a, err := c.appStore.ByID(id); err == nil {
+				apps = append(apps, *a)
+			}
+		}
+		lines[i] = ct.Label() + " — " + contact.Describe(ct, apps)
+	}
+	if len(lines) == 1 {
+		fmt.Fprintf(w, "You know 1 person at %s: %s\n", company, lines[0])
+		return nil
+	}
+	fmt.Fprintf(w, "You know %d %s at %s:\n", len(known), people, company)
+	for _, l := range lines {
+		fmt.Fprintf(w, "  %s\n", l)
+	}
+	return nil
+}
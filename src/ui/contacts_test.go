@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/apply"
+	"sprayer/src/api/contact"
+)
+
+func TestNoteReplySender_CreatesLinkedContact(t *testing.T) {
+	c := newTestCLI(t)
+	cs, err := contact.NewStore(c.store.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.contactStore = cs
+
+	a := &application.Application{JobID: "gh-1", Company: "Acme, Inc.", Title: "Backend Engineer",
+		AppliedAt: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)}
+	if err := c.appStore.Add(a); err != nil {
+		t.Fatal(err)
+	}
+	replied := time.Date(2023, 4, 3, 10, 0, 0, 0, time.UTC)
+	c.noteReplySender(apply.Message{From: "Jane Doe <Jane@acme.com>", Date: replied}, a.ID)
+	// A second reply from the same person, without an application.
+	c.noteReplySender(apply.Message{From: "jane@acme.com", Date: replied.AddDate(1, 0, 0)}, 0)
+
+	all, err := cs.All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 {
+		t.Fatalf("got %d contacts, want one per address", len(all))
+	}
+	jane := all[0]
+	if jane.Name != "Jane Doe" || jane.Company != "Acme, Inc." || jane.Source != contact.SourceReply ||
+		jane.LastInteraction.Year() != 2024 {
+		t.Errorf("contact = %+v", jane)
+	}
+
+	var out strings.Builder
+	if err := c.knownAt(&out, "ACME"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "You know 1 person at ACME: Jane Doe — replied to your 2023 application\n"; out.String() != want {
+		t.Errorf("knownAt = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	c.knownAt(&out, "Globex")
+	if out.Len() != 0 {
+		t.Errorf("no contacts at Globex, got %q", out.String())
+	}
+}
@@ -0,0 +1,316 @@
+package ui
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sprayer/src/api/apply"
+	"sprayer/src/api/export"
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+// handleCV converts between a profile's CV and JSON Resume documents.
+func (c *CLI) handleCV() {
+	if len(os.Args) > 2 && os.Args[2] == "inspect" {
+		if err := inspectCV(os.Stdout, os.Args[3:]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+		return
+	}
+	fs := flag.NewFlagSet("cv", flag.ExitOnError)
+	profileID := fs.String("profile", c.defaultProfile(), "Profile whose CV to export")
+	export := fs.String("export-jsonresume", "", "Write the CV as a JSON Resume document to this file")
+	importPath := fs.String("import", "", "Create a profile from a JSON Resume (or profile JSON/YAML) file")
+	onConflict := fs.String("on-conflict", "", "When the import's ID or name is taken: copy, overwrite or merge (asks when interactive, else copy)")
+	generate := fs.String("generate", "", "Build the CV tailored to this job, by ID or #short ID, as a PDF")
+	tmpl := fs.String("template", "", "CV template for -generate: "+strings.Join(apply.CVTemplates(), ", ")+", or a .tex file (default: the profile's)")
+	fs.Parse(os.Args[2:])
+
+	switch {
+	case *generate != "":
+		c.generateCV(*profileID, *generate, *tmpl)
+	case *importPath != "":
+		if err := c.importCV(os.Stdin, isTerminal(os.Stdin), *importPath, *onConflict); err != nil {
+			fmt.Printf("Import failed: %v\n", err)
+		}
+	case *export != "":
+		c.exportJSONResume(*profileID, *export)
+	default:
+		fmt.Println("Usage: sprayer cv inspect cv.pdf [-json] | [-profile id] -export-jsonresume out.json | -import resume.json [-on-conflict copy|overwrite|merge] | -generate job [-template name]")
+	}
+}
+
+// generateCV builds the profile's CV tailored to a job, overwriting one
+// built before, and links it from the job.
+func (c *CLI) generateCV(profileID, jobRef, tmpl string) {
+	p, err := c.profileStore.ByID(profileID)
+	if err != nil || p == nil {
+		fmt.Printf("Profile not found: %s\n", profileID)
+		return
+	}
+	j, err := c.store.Resolve(jobRef)
+	if err != nil {
+		fmt.Printf("Job not found: %v\n", err)
+		return
+	}
+	if tmpl != "" {
+		p.CVTemplate = tmpl
+	}
+	if _, err := c.tailorCV(context.Background(), j, *p, true); err != nil {
+		fmt.Printf("CV generation failed: %v\n", err)
+		return
+	}
+	fmt.Printf("Tailored CV built: %s\n", j.CVPath)
+}
+
+func (c *CLI) exportJSONResume(profileID, out string) {
+	p, err := c.profileStore.ByID(profileID)
+	if err != nil || p == nil {
+		fmt.Printf("Profile not found: %s\n", profileID)
+		return
+	}
+	if p.CVData == nil && p.CVPath != "" {
+		if p.CVData, err = profile.NewCVParser().ParseCVFromFile(p.CVPath); err != nil {
+			fmt.Printf("Failed to read CV: %v\n", err)
+			return
+		}
+	}
+	if p.CVData == nil {
+		fmt.Printf("Profile %s has no CV to export\n", p.Name)
+		return
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if err := profile.WriteJSONResume(f, *p); err != nil {
+		f.Close()
+		os.Remove(out)
+		fmt.Printf("Export failed: %v\n", err)
+		return
+	}
+	if err := f.Close(); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	fmt.Printf("Exported %s's CV to %s\n", p.Name, out)
+}
+
+// importCV creates a profile from a file. When the import's ID or name
+// is already taken, onConflict decides what happens; with none given the
+// user is shown what overwriting and merging would change and asked, and
+// without a terminal to ask on the import is stored as a copy.
+func (c *CLI) importCV(in io.Reader, interactive bool, path, onConflict string) error {
+	p, err := profile.NewProfileImporter().ImportProfile(path, "")
+	if err != nil {
+		return err
+	}
+	stored, err := c.profileStore.All()
+	if err != nil {
+		return err
+	}
+
+	if existing, byName := profile.Conflict(stored, p); existing != nil {
+		how := "ID " + existing.ID
+		if byName {
+			how = "name"
+		}
+		fmt.Printf("Profile %s (%s) already has this %s.\n", existing.Name, existing.ID, how)
+
+		r, err := profile.ParseResolution(onConflict)
+		if err != nil {
+			return err
+		}
+		if onConflict == "" && interactive {
+			r = askResolution(in, stored, *existing, p)
+		}
+		p = profile.Resolve(stored, *existing, p, r)
+		fmt.Printf("Resolved by %s.\n", r)
+	}
+
+	if err := c.profileStore.Save(p); err != nil {
+		return fmt.Errorf("save profile: %w", err)
+	}
+	fmt.Printf("Imported profile %s (%s)\n", p.Name, p.ID)
+	if missing := p.MissingPersonal(); len(missing) > 0 {
+		fmt.Printf("It has no %s; fill them in with 'sprayer profile edit %s' before applying.\n", strings.Join(missing, " or "), p.ID)
+	}
+	return nil
+}
+
+// exportProfile writes a profile to out: stripped of its personal fields
+// to share it, with its CV inlined as a backup, or as stored.
+func (c *CLI) exportProfile(profileID, out string, strip, includeCV bool) error {
+	mode := profile.ExportAll
+	switch {
+	case strip && includeCV:
+		return fmt.Errorf("--strip-personal and --include-cv exclude each other")
+	case strip:
+		mode = profile.ExportStripped
+	case includeCV:
+		mode = profile.ExportWithCV
+	}
+	p, err := c.profileStore.ByID(profileID)
+	if err != nil || p == nil {
+		return fmt.Errorf("profile not found: %s", profileID)
+	}
+	if err := profile.NewProfileImporter().ExportProfile(*p, out, "", mode); err != nil {
+		return err
+	}
+	fmt.Printf("Exported profile %s to %s\n", p.Name, out)
+	return nil
+}
+
+// createProfileFromCV creates a profile from a CV document, named name
+// or else after the name on it, and says what was read from it.
+func (c *CLI) createProfileFromCV(path, name string) error {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	p, err := profile.NewProfileImporter().ImportProfileFromCV(path, name)
+	if err != nil {
+		return err
+	}
+	if err := c.profileStore.Save(p); err != nil {
+		return fmt.Errorf("save profile: %w", err)
+	}
+	fmt.Printf("Created profile %s (%s) from %s\n", p.Name, p.ID, filepath.Base(path))
+	cv := p.CVData
+	for _, f := range []struct{ label, value string }{
+		{"Email", cv.Email},
+		{"Phone", cv.Phone},
+		{"Keywords", strings.Join(p.Keywords, ", ")},
+		{"Skills", strings.Join(cv.Skills, ", ")},
+		{"Languages", strings.Join(cv.Languages, ", ")},
+	} {
+		if f.value == "" {
+			f.value = "none found"
+		}
+		fmt.Printf("  %-10s %s\n", f.label+":", f.value)
+	}
+	fmt.Printf("Review it with 'sprayer profile edit %s'.\n", p.ID)
+	return nil
+}
+
+// inspectCV prints what the parser reads from a CV file: the contact,
+// the technologies and the jobs and schools of its sections.
+func inspectCV(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("cv inspect", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "Print the parsed CV as JSON")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: sprayer cv inspect cv.pdf [-json]")
+	}
+	cv, err := profile.NewCVParser().ParseCVFromFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(cv)
+	}
+
+	for _, f := range []struct{ label, value string }{
+		{"Name", cv.Name},
+		{"Email", cv.Email},
+		{"Phone", cv.Phone},
+		{"Summary", cv.Summary},
+		{"Technologies", strings.Join(cv.Technologies, ", ")},
+		{"Skills", strings.Join(cv.Skills, ", ")},
+		{"Languages", strings.Join(cv.Languages, ", ")},
+	} {
+		if f.value == "" {
+			f.value = "none found"
+		}
+		fmt.Fprintf(w, "%-13s %s\n", f.label+":", f.value)
+	}
+
+	fmt.Fprintf(w, "\nExperience (%d):\n", len(cv.Experience))
+	for _, x := range cv.Experience {
+		fmt.Fprintf(w, "  %s\n", strings.Join(nonEmpty(x.Title, x.Company, x.Duration), " | "))
+		if len(x.Technologies) > 0 {
+			fmt.Fprintf(w, "    tech: %s\n", strings.Join(x.Technologies, ", "))
+		}
+		for _, line := range strings.Split(x.Description, "\n") {
+			if line != "" {
+				fmt.Fprintf(w, "    %s\n", line)
+			}
+		}
+	}
+	fmt.Fprintf(w, "\nEducation (%d):\n", len(cv.Education))
+	for _, e := range cv.Education {
+		degree := e.Degree
+		if e.Field != "" {
+			degree = strings.TrimSpace(degree + " in " + e.Field)
+		}
+		fmt.Fprintf(w, "  %s\n", strings.Join(nonEmpty(e.Institution, degree, e.Year), " | "))
+	}
+	return nil
+}
+
+// nonEmpty drops the empty strings of ss.
+func nonEmpty(ss ...string) []string {
+	var out []string
+	for _, s := range ss {
+		if s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// askResolution previews overwrite and merge as field diffs against the
+// stored profile and reads the user's choice, copy by default.
+func askResolution(in io.Reader, stored []profile.Profile, existing, imported profile.Profile) profile.Resolution {
+	for _, r := range []profile.Resolution{profile.ResolveOverwrite, profile.ResolveMerge} {
+		changes := profile.Diff(existing, profile.Resolve(stored, existing, imported, r))
+		fmt.Printf("\n%s would change %d field(s):\n", strings.ToUpper(string(r[:1]))+string(r[1:]), len(changes))
+		for _, ch := range changes {
+			fmt.Printf("  %s\n", ch)
+		}
+	}
+	fmt.Print("\n[c]opy as a new profile, [o]verwrite, or [m]erge? [c] ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "o", "overwrite":
+		return profile.ResolveOverwrite
+	case "m", "merge":
+		return profile.ResolveMerge
+	}
+	return profile.ResolveCopy
+}
+
+// tailorCV builds (or, unless regenerate, reuses) the CV tailored to j
+// and links it from the job record, setting j.CVPath. Cancelling ctx
+// abandons the build.
+func (c *CLI) tailorCV(ctx context.Context, j *job.Job, p profile.Profile, regenerate bool) (reused bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, export.CompileTimeout)
+	defer cancel()
+	pdf, reused, err := apply.TailorCV(ctx, *j, p, regenerate)
+	if err != nil {
+		return false, err
+	}
+	if err := c.store.SetCVPath(j.ID, pdf); err != nil {
+		return false, err
+	}
+	j.CVPath = pdf
+	return reused, nil
+}
+
+// isTerminal reports whether f is an interactive terminal rather than a
+// pipe or file.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
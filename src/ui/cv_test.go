@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/profile"
+)
+
+func TestImportCV_ResolvesCollisions(t *testing.T) {
+	c := newTestCLI(t)
+	ps, err := profile.NewStore(c.store.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.profileStore = ps
+	stored := profile.Profile{ID: "backend", Name: "Backend", Keywords: []string{"go"}, MinScore: 80, MaxScore: 100}
+	if err := ps.Save(stored); err != nil {
+		t.Fatal(err)
+	}
+
+	imported := profile.Profile{ID: "backend", Name: "Backend", Keywords: []string{"rust"}, MaxScore: 100}
+	data, _ := json.Marshal(imported)
+	path := filepath.Join(t.TempDir(), "backend.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nobody to ask: the tuned profile survives and the import is a copy.
+	if err := c.importCV(nil, false, path, ""); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := ps.ByID("backend"); p.MinScore != 80 || p.Keywords[0] != "go" {
+		t.Errorf("stored profile overwritten: %+v", p)
+	}
+	if p, err := ps.ByID("backend-2"); err != nil || p.Keywords[0] != "rust" || p.Name != "Backend (copy)" {
+		t.Errorf("copy = %+v, %v", p, err)
+	}
+
+	// Asked, the user merges: imported keywords win, the empty min score
+	// does not.
+	if err := c.importCV(strings.NewReader("m\n"), true, path, ""); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := ps.ByID("backend"); p.MinScore != 80 || p.Keywords[0] != "rust" {
+		t.Errorf("merged profile = %+v", p)
+	}
+
+	if err := c.importCV(nil, false, path, "overwrite"); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := ps.ByID("backend"); p.MinScore != 0 {
+		t.Errorf("overwrite kept min score %d", p.MinScore)
+	}
+}
+
+func TestProfile_CreateFromCV(t *testing.T) {
+	c := newHomeCLI(t)
+	path := filepath.Join(t.TempDir(), "cv.txt")
+	os.WriteFile(path, []byte("Jane Doe\njane@example.com\nGo and Rust on AWS. English, German.\n"), 0644)
+
+	out := runCommand(t, c, "profile", "--create-from-cv", path, "--name", "Me")
+	if !strings.Contains(out, "Created profile Me") || !strings.Contains(out, "jane@example.com") {
+		t.Fatalf("printed\n%s", out)
+	}
+	all, _ := c.profileStore.All()
+	if len(all) != 1 || all[0].Name != "Me" || all[0].ContactEmail != "jane@example.com" || all[0].CVPath != path {
+		t.Errorf("stored %+v", all)
+	}
+
+	empty := filepath.Join(t.TempDir(), "scan.pdf")
+	os.WriteFile(empty, []byte("%PDF-1.4\n"), 0644)
+	if out := runCommand(t, c, "profile", "--create-from-cv", empty); !strings.HasPrefix(out, "Error: failed to parse CV") {
+		t.Errorf("unreadable PDF printed %q", out)
+	}
+}
+
+func TestCV_Inspect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cv.txt")
+	os.WriteFile(path, []byte("Jane Doe\njane@example.com\n\nExperience\nGo Engineer at Acme, 2019 - Present\n- Ran Postgres.\n\nEducation\nUniversity of Porto\nMSc in Physics, 2018\n"), 0644)
+
+	var out strings.Builder
+	if err := inspectCV(&out, []string{path}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Name:         Jane Doe\n",
+		"Experience (1):\n  Go Engineer | Acme | 2019 - Present\n    tech: go, postgres\n    - Ran Postgres.\n",
+		"Education (1):\n  University of Porto | MSc in Physics | 2018\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("missing %q in\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := inspectCV(&out, []string{"-json", path}); err != nil {
+		t.Fatal(err)
+	}
+	var cv profile.CVData
+	if err := json.Unmarshal([]byte(out.String()), &cv); err != nil || len(cv.Experience) != 1 || cv.Experience[0].Company != "Acme" {
+		t.Errorf("JSON %+v, %v", cv, err)
+	}
+}
+
+func TestProfile_ExportStrippedAndImport(t *testing.T) {
+	c := newHomeCLI(t)
+	cv := filepath.Join(t.TempDir(), "cv.txt")
+	os.WriteFile(cv, []byte("Jane Doe\njane@example.com\nGo on AWS.\n"), 0644)
+	runCommand(t, c, "profile", "--create-from-cv", cv)
+	all, _ := c.profileStore.All()
+	id := all[0].ID
+
+	shared := filepath.Join(t.TempDir(), "shared.yaml")
+	if out := runCommand(t, c, "profile", "--export", shared, "--profile", id, "--strip-personal"); !strings.Contains(out, "Exported profile Jane Doe") {
+		t.Fatalf("export printed %q", out)
+	}
+	data, _ := os.ReadFile(shared)
+	if strings.Contains(string(data), "jane@") || strings.Contains(string(data), cv) {
+		t.Errorf("shared export keeps personal data:\n%s", data)
+	}
+
+	out := runCommand(t, c, "profile", "--import", shared)
+	if !strings.Contains(out, "It has no contact email or CV; fill them in with 'sprayer profile edit") {
+		t.Errorf("import printed\n%s", out)
+	}
+	if all, _ := c.profileStore.All(); len(all) != 2 || strings.Join(all[1].Keywords, ",") != strings.Join(all[0].Keywords, ",") {
+		t.Errorf("stored %+v", all)
+	}
+
+	if out := runCommand(t, c, "profile", "--export", shared, "--profile", id, "--strip-personal", "--include-cv"); !strings.HasPrefix(out, "Error: --strip-personal and --include-cv") {
+		t.Errorf("both modes printed %q", out)
+	}
+}
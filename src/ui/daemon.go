@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"sprayer/src/api"
+	"sprayer/src/api/daemon"
+	"sprayer/src/api/job"
+	"sprayer/src/api/notify"
+	"sprayer/src/api/profile"
+)
+
+func (c *CLI) handleDaemon() {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	every := fs.Duration("every", daemon.DefaultEvery, "How often to scrape, e.g. 6h or 90m")
+	profileID := fs.String("profile", "", "Profile to scrape for (default: the default profile)")
+	maxJobs := fs.Int("max-jobs", 0, "Most jobs to keep per run (default 200)")
+	webhook := fs.String("webhook", "", "URL to POST each run's summary to as JSON")
+	email := fs.Bool("email", false, "Email each run's summary to the configured SMTP address")
+	emailTo := fs.String("email-to", "", "Email each run's summary to this address instead")
+	once := fs.Bool("once", false, "Scrape and notify once, then exit")
+	fs.Parse(os.Args[2:])
+
+	prof := profile.NewDefaultProfile()
+	if *profileID != "" {
+		prof.ID = *profileID
+	}
+	if p, err := c.profileStore.ByID(prof.ID); err == nil {
+		prof = *p
+	} else if *profileID != "" {
+		fmt.Printf("Error: unknown profile %q\n", *profileID)
+		return
+	}
+
+	d := &daemon.Daemon{
+		Scrape:   c.daemonScrape(api.ScrapeRequest{ProfileID: *profileID, MaxJobs: *maxJobs}),
+		Every:    *every,
+		MinScore: prof.MinScore,
+		Profile:  prof.ID,
+		Logf:     daemonLogf,
+		Power:    c.powerDecision,
+		FollowUps: func(now time.Time) ([]job.Job, error) {
+			return c.store.FollowUps(now, true)
+		},
+	}
+	if *webhook != "" {
+		d.Notifiers = append(d.Notifiers, daemon.Webhook{URL: *webhook})
+	}
+	if *email || *emailTo != "" {
+		d.Notifiers = append(d.Notifiers, daemon.Email{To: *emailTo})
+	}
+	if prof.NotifyMinScore > 0 {
+		d.Notifiers = append(d.Notifiers, daemon.Desktop{Notifier: notify.Desktop(), MinScore: prof.NotifyMinScore})
+	}
+
+	// A stop mid-scrape still saves what was found; a second signal kills
+	// the process as usual.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	if *once {
+		if err := d.RunOnce(ctx); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+		return
+	}
+	daemonLogf("Scraping for profile %s every %s (Ctrl-C to stop)...", prof.ID, *every)
+	d.Run(ctx)
+	daemonLogf("Stopped.")
+}
+
+// daemonScrape scrapes through the API handler, which saves, dedups and
+// records the run exactly as POST /jobs/scrape does.
+func (c *CLI) daemonScrape(req api.ScrapeRequest) daemon.ScrapeFunc {
+	h := api.NewHandler(c.store, c.profileStore)
+	h.EnableScrapeHistory(c.runStore)
+	h.EnableSourceStats(c.statsStore)
+	h.EnableIngestRules(c.ruleStore)
+	return func(ctx context.Context) ([]job.Job, error) {
+		status, jobs, err := h.Scrape(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range status.Errors {
+			daemonLogf("  %s", e)
+		}
+		if status.State == "cancelled" {
+			daemonLogf("Stopped mid-scrape; saved the %d jobs found so far.", status.JobsFound)
+		}
+		return jobs, nil
+	}
+}
+
+func daemonLogf(format string, args ...any) {
+	fmt.Printf("%s  %s\n", time.Now().Format("2006-01-02 15:04:05"), fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"sprayer/src/api/apply"
+	"sprayer/src/api/brand"
+	"sprayer/src/api/health"
+	"sprayer/src/api/job"
+	"sprayer/src/api/offline"
+)
+
+// handleDoctor runs the same checks as the API's /ready endpoint and
+// prints each outcome. -offline skips the LLM and SMTP checks.
+func (c *CLI) handleDoctor() {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	skipNetwork := fs.Bool("offline", false, "Skip the LLM and SMTP reachability checks")
+	fs.Parse(os.Args[2:])
+
+	checks := health.Local(c.store.DB, job.DataDir())
+	if !*skipNetwork {
+		if err := offline.Check("network checks"); err != nil {
+			fmt.Printf("! %v (set %s=0 to force online)\n", err, offline.EnvOffline)
+		} else {
+			checks = append(checks,
+				health.LLMReachable(c.llmClient.BaseURL(), health.DefaultTimeout),
+				health.SMTPReachable(apply.SMTPAddr(), health.DefaultTimeout),
+			)
+		}
+	}
+	rep := health.Run(context.Background(), checks...)
+
+	r := c.redactor()
+	for _, res := range rep.Checks {
+		if res.OK {
+			fmt.Printf("✓ %s\n", res.Name)
+			continue
+		}
+		fmt.Printf("✗ %s: %s\n", res.Name, r.Scrub(res.Error))
+	}
+	// Power and metering never fail the check; they only explain pauses.
+	ps, pd := c.powerState()
+	if pd.Constrained() {
+		fmt.Printf("! power: %s (%s; scrapes ask first, background work pauses)\n", ps, pd.Reason())
+	} else {
+		fmt.Printf("✓ power: %s\n", ps)
+	}
+	brandErr := c.checkBrand()
+	if !rep.Ready() || brandErr != nil {
+		os.Exit(1)
+	}
+}
+
+// checkBrand reports whether the brand settings in .env are usable.
+func (c *CLI) checkBrand() error {
+	if _, err := brand.FromEnv(); err != nil {
+		fmt.Printf("✗ brand: %v\n", err)
+		return err
+	}
+	fmt.Println("✓ brand")
+	return nil
+}
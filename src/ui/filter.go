@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"sprayer/src/api/profile"
+)
+
+const filterUsage = `Usage:
+  sprayer filter [-profile id] [-preset name] [list flags]   the profile's matches, with the preset on top
+  sprayer filter presets [-json]
+  sprayer filter save <name> [-keywords a,b] [-exclude a,b] [-locations a,b] [-tech a,b]
+                             [-min-score N] [-max-score N] [-days N]
+                             [-remote] [-has-email] [-no-traps] [-hide-applied]
+  sprayer filter rm <name>
+
+A preset replaces only the filters it sets and never changes the profile;
+without -preset the profile's own filters apply. Flags not given to save
+are left to the profile, and -remote=false sets one off.`
+
+// handleFilter lists the profile's matches through a filter preset, or
+// manages the presets.
+func (c *CLI) handleFilter() {
+	sub := ""
+	if len(os.Args) > 2 {
+		sub = os.Args[2]
+	}
+	var err error
+	switch sub {
+	case "presets":
+		fs := flag.NewFlagSet("filter presets", flag.ExitOnError)
+		asJSON := fs.Bool("json", false, "Print the presets as JSON")
+		fs.Parse(os.Args[3:])
+		var all []profile.FilterPreset
+		switch all, err = c.profileStore.Presets(); {
+		case err != nil:
+		case *asJSON:
+			err = writeJSON(os.Stdout, all)
+		default:
+			printPresets(os.Stdout, all)
+		}
+	case "save":
+		err = c.savePreset(os.Stdout, os.Args[3:])
+	case "rm":
+		if len(os.Args) != 4 {
+			err = fmt.Errorf("rm needs a preset name")
+		} else if err = c.profileStore.DeletePreset(os.Args[3]); errors.Is(err, sql.ErrNoRows) {
+			err = fmt.Errorf("no filter preset %q", os.Args[3])
+		} else if err == nil {
+			fmt.Printf("Filter preset %s removed.\n", os.Args[3])
+		}
+	case "help", "-h", "-help", "--help":
+		fmt.Println(filterUsage)
+	default:
+		c.listJobs("filter", os.Args[2:], true)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
+// presetProfile is the stored profile id with the preset named name
+// applied, or as it is when name is empty.
+func (c *CLI) presetProfile(id, name string) (profile.Profile, error) {
+	p := c.batchProfile(id)
+	if name == "" {
+		return p, nil
+	}
+	f, err := c.profileStore.Preset(name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return p, fmt.Errorf("no filter preset %q (see 'sprayer filter presets')", name)
+	}
+	if err != nil {
+		return p, err
+	}
+	return f.Apply(p, time.Now()), nil
+}
+
+func printPresets(w io.Writer, all []profile.FilterPreset) {
+	if len(all) == 0 {
+		fmt.Fprintln(w, "No filter presets. Save one with: sprayer filter save <name> -keywords rust -remote")
+		return
+	}
+	for _, f := range all {
+		fmt.Fprintf(w, "%-24s %s\n", f.Name, f.Summary())
+	}
+}
+
+// savePreset saves the preset args describe: a name, then the filters
+// it sets.
+func (c *CLI) savePreset(w io.Writer, args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("save needs a preset name first\n%s", filterUsage)
+	}
+	f := profile.FilterPreset{Name: args[0]}
+	fs := flag.NewFlagSet("filter save", flag.ContinueOnError)
+	lists := map[string]*[]string{
+		"keywords":  &f.Keywords,
+		"exclude":   &f.ExcludeKeywords,
+		"locations": &f.Locations,
+		"tech":      &f.PreferredTech,
+	}
+	for name := range lists {
+		fs.String(name, "", "Comma-separated "+name)
+	}
+	fs.IntVar(&f.MinScore, "min-score", 0, "Lowest score listed")
+	fs.IntVar(&f.MaxScore, "max-score", 0, "Highest score listed")
+	fs.IntVar(&f.PostedWithinDays, "days", 0, "Only jobs posted in the last N days")
+	bools := map[string]**bool{
+		"remote":       &f.PreferRemote,
+		"has-email":    &f.MustHaveEmail,
+		"no-traps":     &f.ExcludeTraps,
+		"hide-applied": &f.HideApplied,
+	}
+	for name := range bools {
+		fs.Bool(name, false, "Set "+name)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	// Only the flags given are part of the preset.
+	fs.Visit(func(fl *flag.Flag) {
+		if dst, ok := lists[fl.Name]; ok {
+			*dst = splitList(fl.Value.String())
+		}
+		if dst, ok := bools[fl.Name]; ok {
+			on := fl.Value.String() == "true"
+			*dst = &on
+		}
+	})
+	if err := c.profileStore.SavePreset(f); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Saved filter preset %s: %s\n", strings.TrimSpace(f.Name), f.Summary())
+	return nil
+}
+
+// splitList reads a comma-separated flag, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
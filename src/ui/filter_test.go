@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/profile"
+)
+
+func TestSavePreset_LayersOnProfile(t *testing.T) {
+	c := newTestCLI(t)
+	ps, err := profile.NewStore(c.store.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.profileStore = ps
+	p := profile.NewDefaultProfile()
+	p.ContactEmail, p.MustHaveEmail = "jane@example.com", true
+	if err := ps.Save(p); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := c.savePreset(&out, []string{"remote rust", "-keywords", "rust, wasm", "-remote", "-has-email=false", "-days", "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Saved filter preset remote rust: keywords rust, wasm · last 1 day · remote · email or not"; !strings.Contains(out.String(), want) {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+
+	got, err := c.presetProfile("default", "Remote Rust")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Keywords, []string{"rust", "wasm"}) || !got.PreferRemote || got.MustHaveEmail {
+		t.Errorf("preset not applied: %+v", got)
+	}
+	if got.ContactEmail != "jane@example.com" || !reflect.DeepEqual(got.SeniorityLevels, p.SeniorityLevels) {
+		t.Errorf("profile fields the preset leaves alone changed: %+v", got)
+	}
+	if stored := c.batchProfile("default"); !stored.MustHaveEmail || stored.PreferRemote {
+		t.Errorf("applying a preset changed the stored profile: %+v", stored)
+	}
+
+	if own, _ := c.presetProfile("default", ""); !reflect.DeepEqual(own, c.batchProfile("default")) {
+		t.Error("no preset should leave the profile's own filters")
+	}
+	if _, err := c.presetProfile("default", "nope"); err == nil || !strings.Contains(err.Error(), `no filter preset "nope"`) {
+		t.Errorf("unknown preset: %v", err)
+	}
+	if err := c.savePreset(&out, []string{"-keywords", "go"}); err == nil {
+		t.Error("saved a preset without a name")
+	}
+}
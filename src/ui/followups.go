@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const followupsUsage = `Usage:
+  sprayer followups list [-all] [-json]   Applied jobs due a follow-up, longest overdue first (-all: upcoming too)
+  sprayer followups --mark-done <id>      Record that a job's follow-up was sent`
+
+func (c *CLI) handleFollowups() {
+	if len(os.Args) > 2 && os.Args[2] == "list" {
+		fs := flag.NewFlagSet("followups list", flag.ExitOnError)
+		all := fs.Bool("all", false, "Include follow-ups not yet due")
+		asJSON := fs.Bool("json", false, "Print the jobs due a follow-up as JSON")
+		fs.Parse(os.Args[3:])
+		list := c.listFollowups
+		if *asJSON {
+			list = c.followupsJSON
+		}
+		if err := list(os.Stdout, time.Now(), *all); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+		return
+	}
+
+	fs := flag.NewFlagSet("followups", flag.ExitOnError)
+	done := fs.String("mark-done", "", "Job whose follow-up was sent, by ID or #short ID")
+	fs.Parse(os.Args[2:])
+	if *done == "" {
+		fmt.Println(followupsUsage)
+		return
+	}
+	j, err := c.store.Resolve(*done)
+	if err == nil {
+		err = c.store.MarkFollowUpDone(j.ID)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	fmt.Printf("Follow-up done: %s %s @ %s\n", j.Ref(), j.Title, j.Company)
+}
+
+// followupsJSON is listFollowups as a JSON array of the jobs.
+func (c *CLI) followupsJSON(w io.Writer, now time.Time, all bool) error {
+	jobs, err := c.store.FollowUps(now, !all)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, jobs)
+}
+
+// listFollowups prints the follow-ups due at now, or with all every one
+// still to send.
+func (c *CLI) listFollowups(w io.Writer, now time.Time, all bool) error {
+	jobs, err := c.store.FollowUps(now, !all)
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		fmt.Fprintln(w, "No follow-ups due.")
+		return nil
+	}
+	due := 0
+	for _, j := range jobs {
+		when := "due " + j.FollowUpAt.Local().Format("2006-01-02")
+		if j.FollowUpDue(now) {
+			due++
+			if late := int(now.Sub(*j.FollowUpAt).Hours() / 24); late > 0 {
+				when += fmt.Sprintf(" (%dd overdue)", late)
+			}
+		}
+		fmt.Fprintf(w, "%s %s @ %s  applied %s  %s\n", j.Ref(), j.Title, j.Company,
+			j.AppliedDate.Local().Format("2006-01-02"), when)
+	}
+	fmt.Fprintf(w, "%d follow-up(s) due. Mark one sent with: sprayer followups --mark-done <id>\n", due)
+	return nil
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+func TestListFollowups(t *testing.T) {
+	c := newTestCLI(t)
+	if err := c.store.Save([]job.Job{
+		{ID: "remoteok-1", Company: "Acme", Title: "Go Engineer"},
+		{ID: "hn-2", Company: "Globex", Title: "SRE"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	applied := time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+	p := profile.Profile{FollowUpDays: 3}
+	if err := c.store.MarkApplied("remoteok-1", applied, p.FollowUpAfter()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.store.MarkApplied("hn-2", applied, profile.Profile{}.FollowUpAfter()); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	now := applied.AddDate(0, 0, 5)
+	if err := c.listFollowups(&out, now, false); err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "Go Engineer @ Acme  applied 2024-05-01  due 2024-05-04 (2d overdue)") ||
+		strings.Contains(got, "SRE") || !strings.Contains(got, "1 follow-up(s) due.") {
+		t.Errorf("due:\n%s", got)
+	}
+
+	out.Reset()
+	c.listFollowups(&out, now, true)
+	if !strings.Contains(out.String(), "SRE @ Globex  applied 2024-05-01  due 2024-05-08\n") {
+		t.Errorf("-all:\n%s", out.String())
+	}
+
+	if err := c.store.MarkFollowUpDone("remoteok-1"); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	c.listFollowups(&out, now, false)
+	if !strings.Contains(out.String(), "No follow-ups due.") {
+		t.Errorf("after done:\n%s", out.String())
+	}
+}
@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/inbox"
+)
+
+const inboxUsage = `Usage:
+  sprayer inbox list [-json]  (received messages, newest first)
+  sprayer inbox poll     (check the mailboxes once)
+  sprayer inbox watch    (keep checking until Ctrl-C)
+  sprayer inbox scratch [new | rm <id>]  (throwaway addresses; lists them without arguments)`
+
+func (c *CLI) handleInbox() {
+	if len(os.Args) > 2 && os.Args[2] == "scratch" {
+		c.handleScratch(os.Args[3:])
+		return
+	}
+	if len(os.Args) > 2 && os.Args[2] == "list" {
+		fs := flag.NewFlagSet("inbox list", flag.ExitOnError)
+		asJSON := fs.Bool("json", false, "Print the messages as JSON")
+		fs.Parse(os.Args[3:])
+		c.inboxList(*asJSON)
+		return
+	}
+	if len(os.Args) != 3 {
+		fmt.Println(inboxUsage)
+		return
+	}
+	p := c.inboxPoller()
+	switch os.Args[2] {
+	case "poll":
+		msgs, err := p.Poll(context.Background(), time.Now())
+		reportInbox(msgs, err)
+		if err == nil && len(msgs) == 0 {
+			fmt.Println("No new messages.")
+		}
+	case "watch":
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		fmt.Println("Watching for replies (Ctrl-C to stop)...")
+		p.Watch(ctx, reportInbox)
+	default:
+		fmt.Println(inboxUsage)
+	}
+}
+
+// reportInbox prints a poll's new messages, replies about a job first
+// in line.
+func reportInbox(msgs []inbox.Message, err error) {
+	for _, m := range msgs {
+		if m.JobID != "" {
+			fmt.Printf("New reply for %s (job %s): %s — %s\n", m.Company, m.JobID, m.From, m.Subject)
+		} else {
+			fmt.Printf("New message: %s — %s\n", m.From, m.Subject)
+		}
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
+func (c *CLI) inboxList(asJSON bool) {
+	msgs, err := c.inbox.All()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if asJSON {
+		writeJSON(os.Stdout, msgs)
+		return
+	}
+	if len(msgs) == 0 {
+		fmt.Println("No messages yet. Poll with: sprayer inbox poll")
+		return
+	}
+	for _, m := range msgs {
+		about := ""
+		if m.JobID != "" {
+			about = fmt.Sprintf("  [%s, job %s]", m.Company, m.JobID)
+		}
+		fmt.Printf("%s  %s  %s%s\n", m.Date.Local().Format("2006-01-02 15:04"), m.From, m.Subject, about)
+	}
+}
+
+// inboxPoller polls the configured mailboxes, tying messages to the
+// applications whose recipients sent them.
+func (c *CLI) inboxPoller() *inbox.Poller {
+	sources := []inbox.Source{inbox.ConfigureSource(inbox.DefaultMaildir(), inbox.DefaultInterval)}
+	for _, p := range c.scratch.Inboxes() {
+		sources = append(sources, inbox.ConfigureSource(p, inbox.DefaultInterval))
+	}
+	return &inbox.Poller{
+		Store:          c.inbox,
+		Sources:        sources,
+		Correspondents: c.correspondents,
+		OnReply:        c.noteInboxReply,
+		Power:          c.powerDecision,
+	}
+}
+
+// handleScratch lists, creates or deactivates scratch addresses.
+func (c *CLI) handleScratch(args []string) {
+	ctx := context.Background()
+	switch {
+	case len(args) == 0:
+		es, err := c.scratch.All()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		if len(es) == 0 {
+			fmt.Println("No scratch addresses. Create one with: sprayer inbox scratch new")
+		}
+		for _, e := range es {
+			state := "active"
+			if !e.Active {
+				state = "deactivated"
+			}
+			fmt.Printf("#%d  %s  (%s, %s, created %s)\n", e.ID, e.Address, e.Provider, state, e.CreatedAt.Local().Format("2006-01-02"))
+		}
+	case args[0] == "new" && len(args) == 1:
+		e, err := c.scratch.Create(ctx)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("Created %s (#%d). Replies to it show up in sprayer inbox.\n", e.Address, e.ID)
+	case args[0] == "rm" && len(args) == 2:
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			fmt.Printf("Invalid address ID %q\n", args[1])
+			return
+		}
+		e, err := c.scratch.ByID(id)
+		if err == nil {
+			err = c.scratch.Deactivate(ctx, e)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("Deactivated %s.\n", e.Address)
+	default:
+		fmt.Println(inboxUsage)
+	}
+}
+
+// correspondents lists the addresses applications went to, oldest
+// first so the latest application to an address wins: what was sent
+// when it was recorded, else the job's contact address.
+func (c *CLI) correspondents() ([]inbox.Correspondent, error) {
+	apps, err := c.appStore.All()
+	if err != nil {
+		return nil, err
+	}
+	var out []inbox.Correspondent
+	for _, a := range apps {
+		var addrs []string
+		if a.Sent != nil {
+			addrs = append([]string{a.Sent.To}, a.Sent.Cc...)
+		} else if j, err := c.store.ByID(a.JobID); err == nil && j.Email != "" {
+			addrs = []string{j.Email}
+		}
+		for _, addr := range addrs {
+			out = append(out, inbox.Correspondent{JobID: a.JobID, Company: a.Company, Address: addr})
+		}
+	}
+	return out, nil
+}
+
+// noteInboxReply adds a reply to its application's thread and marks the
+// application replied the first time the employer writes back.
+func (c *CLI) noteInboxReply(m inbox.Message) error {
+	a, err := c.appStore.ForJob(m.JobID)
+	if err != nil {
+		return fmt.Errorf("reply for job %s: %w", m.JobID, err)
+	}
+	msgID := m.MessageID
+	if msgID == "" {
+		msgID = m.Provider + ":" + m.ProviderID
+	}
+	if err := c.appStore.AppendMessage(&application.ThreadMessage{
+		ApplicationID: a.ID, Direction: application.Incoming, MessageID: msgID,
+		From: m.From, To: m.To, Subject: m.Subject, Date: m.Date, Body: m.Body,
+	}); err != nil {
+		return err
+	}
+	if a.Status == application.StatusApplied {
+		return c.appStore.SetStatus(a.ID, application.StatusReplied, m.Date)
+	}
+	return nil
+}
+
+// tuiInbox is the inbox as the TUI polls and lists it.
+type tuiInbox struct {
+	c   *CLI
+	p   *inbox.Poller
+	ctx context.Context
+}
+
+func (t tuiInbox) Poll() ([]inbox.Message, error)     { return t.p.Poll(t.ctx, time.Now()) }
+func (t tuiInbox) Messages() ([]inbox.Message, error) { return t.c.inbox.All() }
+func (t tuiInbox) Unread() (int, error)               { return t.c.inbox.Unread() }
+func (t tuiInbox) MarkAllRead() error                 { return t.c.inbox.MarkAllRead() }
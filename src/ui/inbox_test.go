@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/inbox"
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+	"sprayer/src/api/scratch"
+)
+
+func TestInboxPoll_MarksApplicationReplied(t *testing.T) {
+	c := newTestCLI(t)
+	var err error
+	if c.inbox, err = inbox.NewStore(c.store.DB); err != nil {
+		t.Fatal(err)
+	}
+	if c.scratch, err = scratch.NewManager(c.store.DB, &scratch.MailTM{BaseURL: "http://127.0.0.1:0"}); err != nil {
+		t.Fatal(err)
+	}
+	sent := job.Job{ID: "gh-1", Title: "Backend Engineer", Company: "Acme", Email: "jobs@acme.com"}
+	unsent := job.Job{ID: "gh-2", Title: "SRE", Company: "Globex", Email: "hr@globex.com"}
+	if err := c.store.Save([]job.Job{sent, unsent}); err != nil {
+		t.Fatal(err)
+	}
+	p := profile.NewDefaultProfile()
+	c.recordApplication(sent, p, application.MethodEmail, "Dear Acme,", &application.Sent{To: "jobs@acme.com", Subject: "Backend Engineer"})
+	c.recordApplication(unsent, p, application.MethodPortal, "", nil)
+
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "new"), 0o755)
+	os.WriteFile(filepath.Join(dir, "new", "1.host"), []byte("From: Jane <jane@acme.com>\r\nSubject: Re: Backend Engineer\r\n"+
+		"Date: Mon, 02 Mar 2026 10:00:00 +0000\r\n\r\nAre you free on Tuesday?\r\n"), 0o644)
+	os.WriteFile(filepath.Join(dir, "new", "2.host"), []byte("From: hr@globex.com\r\nSubject: Thanks\r\n\r\nReceived.\r\n"), 0o644)
+	t.Setenv("SPRAYER_INBOX_MAILDIR_DIR", dir)
+
+	msgs, err := c.inboxPoller().Poll(context.Background(), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("polled %d messages, want 2", len(msgs))
+	}
+	for _, m := range msgs {
+		want := map[string]string{"1.host": "gh-1", "2.host": "gh-2"}[m.ProviderID]
+		if m.JobID != want {
+			t.Errorf("message %s tied to job %q, want %q", m.ProviderID, m.JobID, want)
+		}
+	}
+
+	a, err := c.appStore.ForJob("gh-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Status != application.StatusReplied || a.RepliedAt == nil {
+		t.Errorf("application = %s replied %v, want replied", a.Status, a.RepliedAt)
+	}
+	thread, err := c.appStore.Thread(a.ID)
+	if err != nil || len(thread) != 1 || thread[0].Direction != application.Incoming || thread[0].Body != "Are you free on Tuesday?\n" {
+		t.Errorf("thread = %+v, %v", thread, err)
+	}
+}
+
+func TestLintAddresses_ComparesAgainstScratchAddress(t *testing.T) {
+	c := newTestCLI(t)
+	var err error
+	if c.scratch, err = scratch.NewManager(c.store.DB, &scratch.MailTM{BaseURL: "http://127.0.0.1:0"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.store.DB.Exec(`INSERT INTO scratch_emails (provider, address, created_at) VALUES ('mailtm', 'old@scratch.io', ?), ('mailtm', 'acme-42@scratch.io', ?)`, time.Now(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	c.lintAddresses(&out, profile.Profile{}, "me@real.dev", "Hi,\n\nBest,\nJo\nacme-42@scratch.io\n", false)
+	if !strings.Contains(out.String(), "acme-42@scratch.io") || strings.Contains(out.String(), "old@scratch.io") {
+		t.Errorf("should warn that From differs from the scratch address in use:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "signature") {
+		t.Errorf("signature matches the scratch address, should not warn:\n%s", out.String())
+	}
+}
@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+const jobsUsage = `Usage:
+  sprayer jobs dedup   Merge stored jobs that repeat one another (same URL, or same company, title and location where a URL is missing)
+  sprayer jobs enrich [--force]   Flag traps, read emails, normalize locations and date undated jobs
+                                  in the stored jobs not yet enriched, or with --force in all of them
+  sprayer jobs status <id>   Show a job's status history
+  sprayer jobs status <id> <status> [-note text]   Move a job to interested, applied, screening,
+                                                   interview, offer, rejected, ghosted or none`
+
+func (c *CLI) handleJobs() {
+	if len(os.Args) < 3 {
+		fmt.Println(jobsUsage)
+		return
+	}
+
+	var err error
+	switch os.Args[2] {
+	case "dedup":
+		err = c.dedupJobs(os.Stdout)
+	case "enrich":
+		err = c.enrichJobs(os.Stdout, os.Args[3:])
+	case "status":
+		err = c.jobStatus(os.Stdout, os.Args[3:])
+	default:
+		fmt.Println(jobsUsage)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
+// dedupJobs merges the stored duplicates, moving their applications,
+// tracking links and questions to the job kept.
+func (c *CLI) dedupJobs(w io.Writer) error {
+	merges, err := c.store.MergeDuplicates()
+	if err != nil {
+		return err
+	}
+	for _, m := range merges {
+		if c.appStore != nil {
+			if err := c.appStore.MoveJob(m.From, m.Into); err != nil {
+				return fmt.Errorf("moving %s to %s: %w", m.From, m.Into, err)
+			}
+		}
+		fmt.Fprintf(w, "  %s -> %s\n", m.From, m.Into)
+	}
+	if len(merges) == 0 {
+		fmt.Fprintln(w, "No duplicate jobs.")
+		return nil
+	}
+	fmt.Fprintf(w, "Merged %d duplicate job(s).\n", len(merges))
+	return nil
+}
+
+// scrapePipeline readies scraped jobs for storage: it enriches them,
+// then sanitizes and reads their full text before capping it. Trap rules
+// are read once, when it is built.
+func (c *CLI) scrapePipeline() job.Filter {
+	return job.Pipe(job.Enrich(c.trapMatcher()), job.SanitizeDescriptions(), job.ExtractDeadlines(), job.ExtractSeniority(), job.DetectLanguages(), job.CapDescriptions())
+}
+
+// enrichJobs backfills the enrichment a scrape now runs into the jobs
+// stored before it did.
+func (c *CLI) enrichJobs(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("jobs enrich", flag.ExitOnError)
+	force := fs.Bool("force", false, "Enrich jobs already enriched again")
+	fs.Parse(args)
+
+	n, err := c.store.EnrichStored(job.Enrich(c.trapMatcher()), *force)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		fmt.Fprintln(w, "All jobs are enriched.")
+		return nil
+	}
+	fmt.Fprintf(w, "Enriched %d job(s).\n", n)
+	return nil
+}
+
+// jobStatus moves a job to a new status, or with no status given prints
+// its history.
+func (c *CLI) jobStatus(w io.Writer, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(w, jobsUsage)
+		return nil
+	}
+	j, err := c.store.Resolve(args[0])
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		hist, err := c.store.StatusHistory(j.ID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s %s @ %s: %s\n", j.Ref(), j.Title, j.Company, statusName(j.Status))
+		printStatusHistory(w, hist)
+		return nil
+	}
+
+	st, err := job.ParseStatus(args[1])
+	if err != nil {
+		return err
+	}
+	fs := flag.NewFlagSet("jobs status", flag.ExitOnError)
+	note := fs.String("note", "", "Why the status changed, kept in the history")
+	fs.Parse(args[2:])
+
+	if err := c.store.SetStatus(j.ID, st, *note, time.Now()); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s %s @ %s: %s -> %s\n", j.Ref(), j.Title, j.Company, statusName(j.Status), statusName(st))
+	return nil
+}
+
+func printStatusHistory(w io.Writer, hist []job.StatusChange) {
+	for _, h := range hist {
+		line := fmt.Sprintf("  %s  %s -> %s", h.At.Local().Format("2006-01-02 15:04"), statusName(h.From), statusName(h.To))
+		if h.Note != "" {
+			line += "  " + h.Note
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+func statusName(s job.Status) string {
+	if s == job.StatusNone {
+		return "none"
+	}
+	return string(s)
+}
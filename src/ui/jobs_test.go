@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/job"
+)
+
+func TestDedupJobs(t *testing.T) {
+	c := newTestCLI(t)
+	if err := c.store.Save([]job.Job{
+		{ID: "remoteok-1", Company: "Acme", Title: "Go Engineer", URL: "https://acme.com/jobs/1"},
+		{ID: "gh-acme-1", Company: "Acme Inc.", Title: "Go Engineer", URL: "https://www.acme.com/jobs/1/"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.appStore.Add(&application.Application{JobID: "gh-acme-1", Company: "Acme"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := c.dedupJobs(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "gh-acme-1 -> remoteok-1") || !strings.Contains(out.String(), "Merged 1 duplicate job(s).") {
+		t.Errorf("output:\n%s", out.String())
+	}
+	if apps, _ := c.appStore.All(); len(apps) != 1 || apps[0].JobID != "remoteok-1" {
+		t.Errorf("application not moved to the kept job: %+v", apps)
+	}
+
+	out.Reset()
+	c.dedupJobs(&out)
+	if !strings.Contains(out.String(), "No duplicate jobs.") {
+		t.Errorf("second run:\n%s", out.String())
+	}
+}
+
+func TestJobStatus(t *testing.T) {
+	c := newTestCLI(t)
+	if err := c.store.Save([]job.Job{{ID: "remoteok-1", Company: "Acme", Title: "Go Engineer"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := c.jobStatus(&out, []string{"remoteok-1", "applied"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.jobStatus(&out, []string{"remoteok-1", "rejected", "-note", "role filled"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Go Engineer @ Acme: applied -> rejected") {
+		t.Errorf("output:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := c.jobStatus(&out, []string{"remoteok-1"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{": rejected", "none -> applied", "applied -> rejected  role filled"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("history lacks %q:\n%s", want, out.String())
+		}
+	}
+
+	if err := c.jobStatus(&out, []string{"remoteok-1", "hired"}); err == nil {
+		t.Error("unknown status accepted")
+	}
+}
+
+func TestEnrichJobs(t *testing.T) {
+	c := newTestCLI(t)
+	if err := c.store.Save([]job.Job{{ID: "remoteok-1", Company: "Acme", Title: "Go Engineer", Location: "Worldwide", Description: "Apply to jobs@acme.com"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := c.enrichJobs(&out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Enriched 1 job(s).") {
+		t.Errorf("output:\n%s", out.String())
+	}
+	if j, _ := c.store.ByID("remoteok-1"); j.Email != "jobs@acme.com" || j.Location != "Remote" {
+		t.Errorf("job not enriched: email %q location %q", j.Email, j.Location)
+	}
+
+	out.Reset()
+	c.enrichJobs(&out, nil)
+	if !strings.Contains(out.String(), "All jobs are enriched.") {
+		t.Errorf("second run:\n%s", out.String())
+	}
+	out.Reset()
+	c.enrichJobs(&out, []string{"--force"})
+	if !strings.Contains(out.String(), "Enriched 1 job(s).") {
+		t.Errorf("forced run:\n%s", out.String())
+	}
+}
@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/llm"
+)
+
+const llmUsage = `Usage:
+  sprayer llm usage [--since 7d]   (tokens and estimated cost per purpose)`
+
+func (c *CLI) handleLLM() {
+	if len(os.Args) < 3 || os.Args[2] != "usage" {
+		fmt.Println(llmUsage)
+		return
+	}
+	fs := flag.NewFlagSet("llm usage", flag.ExitOnError)
+	since := fs.String("since", "30d", "How far back to count, e.g. 7d or 12h")
+	fs.Parse(os.Args[3:])
+
+	window, err := job.ParseWindow(*since)
+	if err != nil || window <= 0 {
+		fmt.Printf("Invalid --since %q\n", *since)
+		return
+	}
+	from := time.Now().Add(-window)
+	if c.llmUsage == nil {
+		printLLMUsage(os.Stdout, nil, from)
+		return
+	}
+	totals, err := c.llmUsage.Totals(from)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	printLLMUsage(os.Stdout, totals, from)
+}
+
+// printLLMUsage prints the totals per purpose and overall. Costs with
+// estimated token counts are marked with a ~.
+func printLLMUsage(w io.Writer, totals []llm.Total, from time.Time) {
+	if len(totals) == 0 {
+		fmt.Fprintf(w, "No LLM calls since %s.\n", from.Local().Format("2006-01-02 15:04"))
+		return
+	}
+	var all llm.Total
+	fmt.Fprintf(w, "LLM usage since %s:\n", from.Local().Format("2006-01-02 15:04"))
+	for _, t := range totals {
+		fmt.Fprintf(w, "  %-13s %4d calls  %8d prompt  %7d completion tokens  avg %-6s %s\n",
+			t.Purpose, t.Calls, t.PromptTokens, t.CompletionTokens,
+			t.Latency.Round(100*time.Millisecond), cost(t))
+		all.Calls += t.Calls
+		all.PromptTokens += t.PromptTokens
+		all.CompletionTokens += t.CompletionTokens
+		all.Estimated += t.Estimated
+		all.Cost += t.Cost
+	}
+	fmt.Fprintf(w, "  %-13s %4d calls  %8d prompt  %7d completion tokens  %-10s %s\n",
+		"total", all.Calls, all.PromptTokens, all.CompletionTokens, "", cost(all))
+	if all.Estimated > 0 {
+		fmt.Fprintf(w, "~ %d of %d calls had their tokens estimated from the text's length.\n", all.Estimated, all.Calls)
+	}
+}
+
+func cost(t llm.Total) string {
+	s := fmt.Sprintf("$%.4f", t.Cost)
+	if t.Estimated > 0 {
+		s = "~" + s
+	}
+	return s
+}
+
+// monthToDateLLMCost is what LLM calls have cost since the month began.
+func (c *CLI) monthToDateLLMCost() (float64, error) {
+	if c.llmUsage == nil {
+		return 0, nil
+	}
+	now := time.Now()
+	return c.llmUsage.Cost(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()))
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/llm"
+)
+
+func TestPrintLLMUsage(t *testing.T) {
+	c := newTestCLI(t)
+	usage, err := llm.NewStore(c.store.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.llmUsage = usage
+
+	now := time.Now()
+	for _, u := range []llm.Usage{
+		{At: now.Add(-time.Hour), Purpose: llm.PurposeEmail, Provider: "hosted", Model: "kimi-k2", PromptTokens: 1200, CompletionTokens: 300, Latency: 2 * time.Second, Cost: 0.0015},
+		{At: now.Add(-2 * time.Hour), Purpose: llm.PurposeEmail, Provider: "hosted", Model: "kimi-k2", PromptTokens: 800, CompletionTokens: 100, Latency: 4 * time.Second, Cost: 0.0007},
+		{At: now.Add(-time.Hour), Purpose: llm.PurposeCVLatex, Provider: "ollama", Model: "llama3.1", PromptTokens: 3000, CompletionTokens: 900, Estimated: true},
+		{At: now.AddDate(0, 0, -10), Purpose: llm.PurposeCoverLetter, Provider: "hosted", Model: "kimi-k2", PromptTokens: 500, CompletionTokens: 500, Cost: 0.01},
+	} {
+		if err := usage.Record(u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	from := now.AddDate(0, 0, -7)
+	totals, err := usage.Totals(from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	printLLMUsage(&out, totals, from)
+	got := out.String()
+	for _, want := range []string{
+		"email            2 calls      2000 prompt      400 completion tokens  avg 3s     $0.0022",
+		"cv_latex         1 calls      3000 prompt      900 completion tokens",
+		"~$0.0000",
+		"total            3 calls      5000 prompt     1300 completion tokens",
+		"~ 1 of 3 calls had their tokens estimated",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("usage lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "cover_letter") {
+		t.Errorf("usage counts a call from before --since:\n%s", got)
+	}
+
+	out.Reset()
+	printLLMUsage(&out, nil, from)
+	if !strings.HasPrefix(out.String(), "No LLM calls since") {
+		t.Errorf("empty usage = %q", out.String())
+	}
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/apply"
+	"sprayer/src/api/job"
+	"sprayer/src/api/llm"
+	"sprayer/src/api/offline"
+	"sprayer/src/api/profile"
+	"sprayer/src/api/scraper"
+)
+
+func TestOffline_FailsFastWithFallbacks(t *testing.T) {
+	defer offline.Force(true)()
+	t.Setenv(llm.EnvLLMKey, "sk-test")
+	t.Setenv(llm.EnvLLMURL, "https://api.example.com/v1")
+
+	c := newTestCLI(t)
+	c.llmClient = llm.NewClient()
+	j := job.Job{Title: "Go Engineer", Company: "Acme", Email: "jobs@acme.com"}
+	p := profile.Profile{Name: "Jane Doe"}
+
+	subject, body, err := c.compose(io.Discard, j, p, "email_cold", "")
+	if err != nil {
+		t.Fatalf("compose offline should fall back to a template: %v", err)
+	}
+	if subject == "" || !strings.Contains(body, "Acme") {
+		t.Errorf("template fallback gave %q / %q", subject, body)
+	}
+
+	if _, err := scraper.All(profile.Profile{}, []string{"go"}, "")(); !errors.Is(err, offline.ErrOffline) {
+		t.Errorf("scraping offline: %v, want ErrOffline", err)
+	}
+	t.Setenv("SPRAYER_SMTP_HOST", "smtp.example.com")
+	t.Setenv("SPRAYER_SMTP_USER", "jane@example.com")
+	t.Setenv("SPRAYER_SMTP_PASS", "secret")
+	if err := apply.SendMessage(apply.Message{To: "jobs@acme.com"}); !errors.Is(err, offline.ErrOffline) {
+		t.Errorf("sending offline: %v, want ErrOffline", err)
+	}
+}
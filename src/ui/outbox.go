@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/apply"
+	"sprayer/src/api/offline"
+	"sprayer/src/api/outbox"
+)
+
+const outboxUsage = `Usage:
+  sprayer outbox list [-json]  (queued and failed messages)
+  sprayer outbox flush         (send what is due, retrying failures with backoff)
+  sprayer outbox retry <id>    (send one now, failed or not)
+  sprayer outbox rm <id>`
+
+func (c *CLI) handleOutbox() {
+	if len(os.Args) < 3 {
+		fmt.Println(outboxUsage)
+		return
+	}
+	sub, args := os.Args[2], os.Args[3:]
+	switch sub {
+	case "list":
+		fs := flag.NewFlagSet("outbox list", flag.ExitOnError)
+		asJSON := fs.Bool("json", false, "Print the messages as JSON")
+		fs.Parse(args)
+		if *asJSON {
+			msgs, err := c.outbox.Unsent()
+			if err == nil {
+				err = writeJSON(os.Stdout, msgs)
+			}
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+			}
+			return
+		}
+		c.outboxList()
+		return
+	case "flush":
+		sum, err := c.outbox.Flush(time.Now(), c.sendQueued)
+		if errors.Is(err, offline.ErrOffline) {
+			fmt.Printf("%v; %s so far. The rest stay queued.\n", err, sum)
+			return
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		fmt.Printf("Outbox flushed: %s\n", sum)
+		c.outboxList()
+		return
+	}
+
+	if len(args) != 1 {
+		fmt.Println(outboxUsage)
+		return
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid message ID %q\n", args[0])
+		return
+	}
+	switch sub {
+	case "retry":
+		if err = c.outbox.Requeue(id); err == nil {
+			if err = c.outbox.Send(id, time.Now(), c.sendQueued); err == nil {
+				fmt.Printf("Message %d sent.\n", id)
+			}
+		}
+	case "rm":
+		if err = c.outbox.Delete(id); err == nil {
+			fmt.Printf("Message %d deleted.\n", id)
+		}
+	default:
+		fmt.Println(outboxUsage)
+		return
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
+// outboxList prints the unsent messages with when each is next tried.
+func (c *CLI) outboxList() {
+	msgs, err := c.outbox.Unsent()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if len(msgs) == 0 {
+		fmt.Println("Outbox is empty.")
+		return
+	}
+	for _, m := range msgs {
+		when := "next try " + m.NextAttempt.Local().Format("2006-01-02 15:04")
+		switch m.Status {
+		case outbox.StatusFailed:
+			when = "gave up"
+		case outbox.StatusSending:
+			when = "interrupted while sending; it may be out, so check before retrying"
+		}
+		fmt.Printf("#%d  %-7s %s  %s  (%d attempt(s), %s)\n", m.ID, m.Status, m.To, m.Subject, m.Attempts, when)
+		if m.LastError != "" {
+			fmt.Printf("      error: %s\n", m.LastError)
+		}
+	}
+}
+
+// sendNow queues m in the outbox and sends it at once. A failed send
+// leaves it queued, and the error says so; a dry run removes it again,
+// since nothing was tried.
+func (c *CLI) sendNow(m *outbox.Message) error {
+	if err := c.outbox.Enqueue(m); err != nil {
+		return fmt.Errorf("queue message: %w", err)
+	}
+	err := c.outbox.Send(m.ID, time.Now(), c.sendQueued)
+	var dry *apply.DryRunError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &dry):
+		c.outbox.Delete(m.ID)
+		return err
+	}
+	return fmt.Errorf("%w; queued in the outbox as #%d (sprayer outbox flush retries it)", err, m.ID)
+}
+
+// sendQueued sends a message from the outbox with tracking and the job's
+// CV, and records the application with what was sent once it is out,
+// marking the job applied.
+func (c *CLI) sendQueued(m outbox.Message) error {
+	p := c.batchProfile(m.ProfileID)
+	body, pixel := c.instrument(m.JobID, p, m.Body)
+	cv := apply.ResolveCV(p, m.JobID)
+	j, jerr := c.store.ByID(m.JobID)
+	if jerr == nil {
+		cv = apply.ResolveJobCV(p, *j)
+	}
+	if err := apply.SendTracked(m.To, m.Subject, body, cv.Path, pixel, m.Cc...); err != nil {
+		return err
+	}
+	if jerr == nil {
+		c.recordApplication(*j, p, application.MethodEmail, m.Body, &application.Sent{
+			From: apply.SMTPFrom(), To: m.To, Cc: m.Cc, Subject: m.Subject, Body: body,
+			CVPath: cv.Path, CoverLetterPath: p.CoverPath,
+		})
+	}
+	return nil
+}
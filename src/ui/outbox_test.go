@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/offline"
+	"sprayer/src/api/outbox"
+	"sprayer/src/api/profile"
+)
+
+// fakeSMTP serves just enough SMTP for apply.SendTracked on 127.0.0.1,
+// refusing every message while down is set, and points the SMTP
+// settings at it.
+func fakeSMTP(t *testing.T, down *atomic.Bool) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, down.Load())
+		}
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	t.Setenv("SPRAYER_SMTP_HOST", host)
+	t.Setenv("SPRAYER_SMTP_PORT", port)
+	t.Setenv("SPRAYER_SMTP_USER", "jane@example.com")
+	t.Setenv("SPRAYER_SMTP_PASS", "secret")
+}
+
+func serveSMTP(conn net.Conn, down bool) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch cmd := strings.ToUpper(strings.Fields(line + " x")[0]); cmd {
+		case "EHLO", "HELO":
+			reply("250-localhost\r\n250 AUTH PLAIN")
+		case "AUTH":
+			reply("235 accepted")
+		case "MAIL":
+			if down {
+				reply("451 try again later")
+				continue
+			}
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+			}
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func TestOutbox_FailedSendIsQueuedAndFlushed(t *testing.T) {
+	defer offline.Force(false)()
+	var down atomic.Bool
+	down.Store(true)
+	fakeSMTP(t, &down)
+
+	c := newTestCLI(t)
+	ps, err := profile.NewStore(c.store.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.profileStore = ps
+	if c.outbox, err = outbox.NewStore(c.store.DB); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.store.Save([]job.Job{{ID: "j1", Title: "Go Dev", Company: "Globex", Email: "jobs@globex.com"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &outbox.Message{JobID: "j1", ProfileID: "default", To: "jobs@globex.com", Subject: "Go Dev", Body: "Dear Globex,"}
+	err = c.sendNow(m)
+	if err == nil || !strings.Contains(err.Error(), "451") || !strings.Contains(err.Error(), "queued in the outbox as #1") {
+		t.Fatalf("sendNow with the server down = %v", err)
+	}
+	if j, _ := c.store.ByID("j1"); j.Applied {
+		t.Error("job marked applied before the email went out")
+	}
+
+	down.Store(false)
+	sum, err := c.outbox.Flush(time.Now().Add(outbox.Backoff(1)), c.sendQueued)
+	if err != nil || sum != (outbox.Summary{Sent: 1}) {
+		t.Fatalf("Flush = %v, %v", sum, err)
+	}
+	j, _ := c.store.ByID("j1")
+	if !j.Applied || j.AppliedDate.IsZero() {
+		t.Errorf("flushed job applied = %v, date %v", j.Applied, j.AppliedDate)
+	}
+	if apps, _ := c.appStore.All(); len(apps) != 1 || apps[0].JobID != "j1" {
+		t.Errorf("applications = %+v", apps)
+	}
+	if unsent, _ := c.outbox.Unsent(); len(unsent) != 0 {
+		t.Errorf("outbox still holds %+v", unsent)
+	}
+}
@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"sprayer/src/api/power"
+)
+
+// powerState detects the current power and network state; a CLI built
+// without a detector sees an unconstrained machine.
+func (c *CLI) powerState() (power.State, power.Decision) {
+	d := c.power
+	if d == nil {
+		d = power.Fixed{}
+	}
+	s := d.Detect()
+	return s, power.Decide(s, power.ConfigFromEnv())
+}
+
+// powerDecision is what to hold back right now.
+func (c *CLI) powerDecision() power.Decision {
+	_, d := c.powerState()
+	return d
+}
+
+// confirmScrape asks before a scrape on a low battery or metered
+// connection. yes skips the question.
+func (c *CLI) confirmScrape(in io.Reader, yes bool) bool {
+	s, d := c.powerState()
+	if !d.ConfirmScrape() || yes {
+		return true
+	}
+	fmt.Printf("! %s (%s). A full scrape uses data and battery.\n", d.Reason(), s)
+	fmt.Print("Scrape anyway? [y/N] ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	fmt.Println("Not scraping. Pass --yes to skip this question.")
+	return false
+}
@@ -0,0 +1,26 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"sprayer/src/api/power"
+)
+
+func TestConfirmScrape(t *testing.T) {
+	t.Setenv(power.EnvMetered, "")
+	c := newTestCLI(t)
+	if !c.confirmScrape(strings.NewReader(""), false) {
+		t.Error("an unconstrained machine should scrape without asking")
+	}
+
+	c.power = power.Fixed{Metered: true, MeteredKnown: true}
+	for answer, want := range map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "\n": false, "": false} {
+		if got := c.confirmScrape(strings.NewReader(answer), false); got != want {
+			t.Errorf("answer %q: got %v, want %v", answer, got, want)
+		}
+	}
+	if !c.confirmScrape(strings.NewReader(""), true) {
+		t.Error("--yes should skip the question")
+	}
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/profile"
+	"sprayer/src/ui/tui/profileform"
+)
+
+// profileEditor adapts profileform.Model to a standalone tea program.
+type profileEditor struct {
+	form *profileform.Model
+}
+
+func (e profileEditor) Init() tea.Cmd { return e.form.Init() }
+
+func (e profileEditor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, cmd := e.form.Update(msg)
+	e.form = form
+	if form.Done() || form.Aborted() {
+		return e, tea.Quit
+	}
+	return e, cmd
+}
+
+func (e profileEditor) View() string {
+	if e.form.Done() || e.form.Aborted() {
+		return ""
+	}
+	return e.form.View()
+}
+
+func (c *CLI) handleProfileEdit(args []string) {
+	id := c.defaultProfile()
+	if len(args) > 0 {
+		id = args[0]
+	}
+
+	p := profile.NewDefaultProfile()
+	p.ID = id
+	if existing, err := c.profileStore.ByID(id); err == nil && existing != nil {
+		p = *existing
+	}
+
+	form := profileform.New(p)
+	if _, err := tea.NewProgram(profileEditor{form: form}, tea.WithAltScreen()).Run(); err != nil {
+		fmt.Printf("Editor failed: %v\n", err)
+		return
+	}
+	if !form.Done() {
+		fmt.Println("Profile edit cancelled.")
+		return
+	}
+
+	if err := c.profileStore.Save(form.Profile()); err != nil {
+		fmt.Printf("Error saving profile: %v\n", err)
+		return
+	}
+	fmt.Printf("Profile %s saved.\n", id)
+}
@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/apply"
+	"sprayer/src/api/job"
+	"sprayer/src/api/parse"
+	"sprayer/src/api/profile"
+)
+
+const questionsUsage = `Usage:
+  sprayer questions list <job-id> [-profile id]
+  sprayer questions detect <job-id> [-profile id]
+  sprayer questions add <job-id> [-profile id] <question>
+  sprayer questions answer <question> [-draft] [-m text]   (opens $EDITOR without -m)
+  sprayer questions search <text>
+  sprayer questions rm <question>`
+
+func (c *CLI) handleQuestions() {
+	if len(os.Args) < 4 {
+		fmt.Println(questionsUsage)
+		return
+	}
+	sub, args := os.Args[2], os.Args[3:]
+
+	switch sub {
+	case "search":
+		c.printSimilar(strings.Join(args, " "), 5)
+		return
+	case "answer":
+		c.answerQuestion(args)
+		return
+	case "rm":
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err == nil {
+			err = c.appStore.DeleteQuestion(id)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+		return
+	}
+
+	fs := flag.NewFlagSet("questions "+sub, flag.ExitOnError)
+	profileID := fs.String("profile", c.defaultProfile(), "Profile answering")
+	jobID := args[0]
+	fs.Parse(args[1:])
+
+	j, err := c.store.Resolve(jobID)
+	if err != nil {
+		fmt.Printf("Job not found: %v\n", err)
+		return
+	}
+	p := c.batchProfile(*profileID)
+
+	switch sub {
+	case "list":
+	case "detect":
+		if found := c.detectQuestions(os.Stdout, *j, p); found == 0 {
+			fmt.Println("No questions found in the posting.")
+		}
+	case "add":
+		q := &application.Question{JobID: j.ID, ProfileID: p.ID, Text: strings.Join(fs.Args(), " "), Source: application.SourceManual}
+		if q.Text == "" {
+			fmt.Println(questionsUsage)
+			return
+		}
+		if err := c.appStore.AddQuestion(q); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		c.printSimilar(q.Text, 3)
+	default:
+		fmt.Println(questionsUsage)
+		return
+	}
+	c.listQuestions(*j, p)
+}
+
+// prepareQuestions runs during apply: it records questions the posting
+// asks, points out unanswered ones on w, and returns the answered ones
+// for the email.
+func (c *CLI) prepareQuestions(w io.Writer, j job.Job, p profile.Profile) []application.Question {
+	c.detectQuestions(w, j, p)
+	qs, err := c.appStore.Questions(j.ID, p.ID)
+	if err != nil {
+		return nil
+	}
+	var answers []application.Question
+	for _, q := range qs {
+		if q.Answered() {
+			answers = append(answers, q)
+			continue
+		}
+		fmt.Fprintf(w, "? Unanswered question %d: %s\n  Answer with: sprayer questions answer %d\n", q.ID, q.Text, q.ID)
+	}
+	return answers
+}
+
+// detectQuestions stores the questions found in j's description and
+// returns how many there were, warning on w of any it could not store.
+func (c *CLI) detectQuestions(w io.Writer, j job.Job, p profile.Profile) int {
+	found := parse.ExtractQuestions(j.Description)
+	for _, text := range found {
+		q := &application.Question{JobID: j.ID, ProfileID: p.ID, Text: text, Source: application.SourceDetected}
+		if err := c.appStore.AddQuestion(q); err != nil {
+			fmt.Fprintf(w, "Warning: could not save question: %v\n", err)
+		}
+	}
+	return len(found)
+}
+
+func (c *CLI) listQuestions(j job.Job, p profile.Profile) {
+	qs, err := c.appStore.Questions(j.ID, p.ID)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if len(qs) == 0 {
+		fmt.Printf("No questions for %s @ %s.\n", j.Title, j.Company)
+		return
+	}
+	for _, q := range qs {
+		fmt.Printf("%d. [%s] %s\n", q.ID, q.Source, q.Text)
+		if q.Answered() {
+			fmt.Printf("   %s\n", strings.ReplaceAll(q.Answer, "\n", "\n   "))
+		} else {
+			fmt.Println("   (unanswered)")
+		}
+	}
+}
+
+// answerQuestion records an answer given with -m, or edited in $EDITOR
+// starting from the current answer, an LLM draft (-draft) or the closest
+// previous answer.
+func (c *CLI) answerQuestion(args []string) {
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		fmt.Printf("Invalid question ID %q\n", args[0])
+		return
+	}
+	fs := flag.NewFlagSet("questions answer", flag.ExitOnError)
+	draft := fs.Bool("draft", false, "Start from an LLM draft based on your CV")
+	message := fs.String("m", "", "Answer text (skips the editor)")
+	fs.Parse(args[1:])
+
+	q, err := c.appStore.QuestionByID(id)
+	if err != nil {
+		fmt.Printf("Question %d: %v\n", id, err)
+		return
+	}
+
+	answer := *message
+	if answer == "" {
+		start := q.Answer
+		similar, _ := c.appStore.SimilarAnswers(q.Text, 3)
+		similar = excludeQuestion(similar, q.ID)
+		if *draft {
+			j, err := c.store.ByID(q.JobID)
+			if err != nil {
+				fmt.Printf("Job not found: %v\n", err)
+				return
+			}
+			if start, err = apply.DraftAnswer(*j, c.batchProfile(q.ProfileID), c.llmClient, q.Text); err != nil {
+				fmt.Printf("Draft failed: %v\n", err)
+				return
+			}
+		} else if start == "" && len(similar) > 0 {
+			start = similar[0].Answer
+		}
+		if answer, err = editBody(answerTemplate(q.Text, start, similar)); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		answer = stripComments(answer)
+	}
+
+	if strings.TrimSpace(answer) == "" {
+		fmt.Println("Empty answer; nothing saved.")
+		return
+	}
+	if err := c.appStore.AnswerQuestion(id, answer); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	fmt.Printf("Answer to question %d saved.\n", id)
+}
+
+// answerTemplate is the editor buffer: the answer on top, then the
+// question and previous answers as # comments.
+func answerTemplate(question, start string, similar []application.AnswerMatch) string {
+	var b strings.Builder
+	b.WriteString(start)
+	b.WriteString("\n\n# Question: " + question + "\n# Lines starting with # are ignored.\n")
+	for _, m := range similar {
+		fmt.Fprintf(&b, "#\n# Previously asked (question %d): %s\n", m.ID, m.Text)
+		for _, line := range strings.Split(m.Answer, "\n") {
+			b.WriteString("#   " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+func stripComments(s string) string {
+	var keep []string
+	for _, line := range strings.Split(s, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			keep = append(keep, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(keep, "\n"))
+}
+
+func excludeQuestion(ms []application.AnswerMatch, id int64) []application.AnswerMatch {
+	var out []application.AnswerMatch
+	for _, m := range ms {
+		if m.ID != id {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// printSimilar shows previous answers to questions worded like text.
+func (c *CLI) printSimilar(text string, limit int) {
+	matches, err := c.appStore.SimilarAnswers(text, limit)
+	if err != nil {
+		fmt.Printf("Search failed: %v\n", err)
+		return
+	}
+	if len(matches) == 0 {
+		return
+	}
+	fmt.Println("Previous answers to similar questions:")
+	for _, m := range matches {
+		company := m.JobID
+		if j, err := c.store.ByID(m.JobID); err == nil {
+			company = j.Company
+		}
+		fmt.Printf("  %d. (%s, %.0f%% match) %s\n     %s\n", m.ID, company, m.Score*100, m.Text,
+			strings.ReplaceAll(m.Answer, "\n", "\n     "))
+	}
+}
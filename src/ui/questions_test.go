@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+func TestCompose_UsesDetectedAndAnsweredQuestions(t *testing.T) {
+	c := newTestCLI(t)
+	j := job.Job{
+		ID: "q-1", Title: "SRE", Company: "Acme",
+		Description: "Please answer the following:\n1. Describe a time you led an incident response.\n2. What is your on-call experience with Kubernetes?",
+	}
+	p := profile.NewDefaultProfile()
+	p.Name = "Ada"
+
+	if got := c.prepareQuestions(io.Discard, j, p); len(got) != 0 {
+		t.Fatalf("answers before answering: %+v", got)
+	}
+	qs, err := c.appStore.Questions(j.ID, p.ID)
+	if err != nil || len(qs) != 2 {
+		t.Fatalf("detected %+v, %v", qs, err)
+	}
+	if err := c.appStore.AnswerQuestion(qs[0].ID, "I ran our 2023 outage bridge."); err != nil {
+		t.Fatal(err)
+	}
+
+	_, body, err := c.compose(io.Discard, j, p, "email_cold", "email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, "Describe a time you led an incident response.\nI ran our 2023 outage bridge.") {
+		t.Errorf("answer not in email:\n%s", body)
+	}
+	if strings.Contains(body, "Kubernetes") {
+		t.Errorf("unanswered question in email:\n%s", body)
+	}
+	if again, _ := c.appStore.Questions(j.ID, p.ID); len(again) != 2 {
+		t.Errorf("re-detection duplicated questions: %+v", again)
+	}
+}
+
+func TestAnswerTemplate_StripsComments(t *testing.T) {
+	buf := answerTemplate("Why Go?", "Because it is simple.", nil)
+	if got := stripComments(buf); got != "Because it is simple." {
+		t.Errorf("stripComments = %q", got)
+	}
+}
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/apply"
+	"sprayer/src/api/offline"
+)
+
+// handleReply answers a recruiter email saved as an .eml file. The reply
+// is threaded onto the original and goes through the same drafts/SMTP
+// paths as applications.
+func (c *CLI) handleReply() {
+	fs := flag.NewFlagSet("reply", flag.ExitOnError)
+	appID := fs.Int64("app", 0, "Application the conversation belongs to")
+	text := fs.String("m", "", "Reply text (opens $EDITOR when empty)")
+	send := fs.Bool("send", false, "Send via SMTP instead of saving a draft")
+	fs.Parse(os.Args[2:])
+
+	if fs.NArg() != 1 {
+		fmt.Println("Usage: sprayer reply [-app ID] [-m text] [-send] <message.eml>")
+		return
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		fmt.Printf("Error opening message: %v\n", err)
+		return
+	}
+	orig, err := apply.ParseMessage(f)
+	f.Close()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+
+	c.noteReplySender(orig, *appID)
+
+	reply := apply.NewReply(orig, apply.SMTPFrom())
+	if *text != "" {
+		reply.Body = *text + reply.Body
+	} else if reply.Body, err = editBody(reply.Body); err != nil {
+		fmt.Printf("Error editing reply: %v\n", err)
+		return
+	}
+	if written, _, ok := strings.Cut(reply.Body, apply.Quote(orig)); ok && strings.TrimSpace(written) == "" {
+		fmt.Println("Empty reply, nothing sent.")
+		return
+	}
+
+	sent := false
+	if *send {
+		err := apply.SendMessage(reply)
+		switch {
+		case errors.Is(err, offline.ErrOffline):
+			fmt.Printf("%v; saving a draft instead.\n", err)
+		case err != nil:
+			fmt.Printf("Failed to send: %v\n", err)
+			return
+		default:
+			sent = true
+			fmt.Printf("Reply sent to %s\n", reply.To)
+		}
+	}
+	if !sent {
+		path, err := apply.DraftMessage(reply)
+		if err != nil {
+			fmt.Printf("Draft failed: %v\n", err)
+			return
+		}
+		fmt.Printf("Draft created: %s\n", path)
+	}
+
+	if *appID > 0 {
+		c.appendToThread(*appID, orig, reply)
+	}
+}
+
+// appendToThread records both sides of the exchange and marks the
+// application as replied the first time the employer writes back.
+func (c *CLI) appendToThread(appID int64, orig, reply apply.Message) {
+	in := &application.ThreadMessage{
+		ApplicationID: appID, Direction: application.Incoming, MessageID: orig.MessageID,
+		From: orig.From, To: orig.To, Subject: orig.Subject, Date: orig.Date, Body: orig.Body,
+	}
+	out := &application.ThreadMessage{
+		ApplicationID: appID, Direction: application.Outgoing, MessageID: reply.MessageID,
+		From: reply.From, To: reply.To, Subject: reply.Subject, Date: reply.Date, Body: reply.Body,
+	}
+	for _, m := range []*application.ThreadMessage{in, out} {
+		if err := c.appStore.AppendMessage(m); err != nil {
+			fmt.Printf("Warning: could not update thread: %v\n", err)
+			return
+		}
+	}
+	if a, err := c.appStore.ByID(appID); err == nil && a.Status == application.StatusApplied {
+		c.appStore.SetStatus(appID, application.StatusReplied, orig.Date)
+	}
+}
+
+// editBody opens $EDITOR on the pre-filled body, cursor area first.
+func editBody(body string) (string, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		return "", fmt.Errorf("set $EDITOR or pass -m")
+	}
+	tmp, err := os.CreateTemp("", "sprayer-reply-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(body); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	tmp.Close()
+
+	cmd := exec.Command(editor, tmp.Name())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(tmp.Name())
+	return string(data), err
+}
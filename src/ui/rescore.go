@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+// handleRescore recomputes stored scores with the current profile, so edits
+// to preferences or source weights apply to jobs already scraped. The
+// scores are kept for that profile only; other profiles keep theirs. A job
+// scraped again takes its new score until the next rescore.
+func (c *CLI) handleRescore() {
+	fs := flag.NewFlagSet("rescore", flag.ExitOnError)
+	profileID := fs.String("profile", c.defaultProfile(), "Profile to score against")
+	explain := fs.Bool("explain", false, "Print the score breakdown for each job")
+	fs.Parse(os.Args[2:])
+
+	p := profile.NewDefaultProfile()
+	if existing, err := c.profileStore.ByID(*profileID); err == nil && existing != nil {
+		p = *existing
+	} else if *profileID != "default" {
+		fmt.Printf("Profile not found: %s\n", *profileID)
+		return
+	}
+
+	jobs, err := c.store.ForProfile(p.ID, job.IncludeAll())
+	if err != nil {
+		fmt.Printf("Failed to load jobs: %v\n", err)
+		return
+	}
+
+	changed := 0
+	scores := make(map[string]int, len(jobs))
+	for i := range jobs {
+		b := p.Explain(&jobs[i])
+		if *explain {
+			fmt.Printf("%s @ %s (%s)\n  %s\n", jobs[i].Title, jobs[i].Company, jobs[i].ID,
+				strings.Join(b.Lines(), "\n  "))
+		}
+		if jobs[i].Score != b.Total {
+			changed++
+		}
+		scores[jobs[i].ID] = b.Total
+	}
+
+	if err := c.store.SetScores(p.ID, scores); err != nil {
+		fmt.Printf("Failed to save scores: %v\n", err)
+		return
+	}
+	fmt.Printf("Rescored %d job(s) for profile %s; %d changed.\n", len(jobs), p.Name, changed)
+}
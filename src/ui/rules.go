@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/rules"
+	"sprayer/src/ui/tui/rulelist"
+)
+
+const rulesUsage = `Usage:
+  sprayer rules list
+  sprayer rules add -name n -when EXPR -do ACTIONS
+  sprayer rules test [name] | -when EXPR -do ACTIONS   (dry run over stored jobs)
+  sprayer rules enable|disable|rm <name>
+  sprayer rules edit                                   (toggle rules in a list)
+
+EXPR combines keyword:, title:, company:, source:, location:, tag: and
+salary: (>=, >, <, <=) predicates with and, or, not and parentheses:
+  keyword:on-call or (company:"Acme Talent" and not location:remote)
+ACTIONS is a comma-separated list of tag:NAME, score:+N or score:-N,
+hide, and note:TEXT (last, as the note may hold commas).`
+
+// ingestRules runs the ingest rules over scraped jobs before they are
+// saved. A broken rule is reported and the jobs are saved as scraped, so
+// one bad rule does not lose a scrape.
+func (c *CLI) ingestRules(profileID string, jobs []job.Job) []job.Job {
+	if c.ruleStore == nil {
+		return jobs
+	}
+	out, err := c.ruleStore.Ingest(c.store, profileID, jobs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ingest rules: %v (saving jobs without them; see 'sprayer rules list')\n", err)
+		return jobs
+	}
+	return out
+}
+
+func (c *CLI) handleRules() {
+	if len(os.Args) < 3 {
+		fmt.Println(rulesUsage)
+		return
+	}
+	sub, args := os.Args[2], os.Args[3:]
+
+	var err error
+	switch sub {
+	case "list":
+		var all []rules.Rule
+		if all, err = c.ruleStore.All(); err == nil {
+			printRules(os.Stdout, all)
+		}
+	case "add":
+		err = c.addRule(args)
+	case "test":
+		err = c.testRules(os.Stdout, args)
+	case "enable", "disable":
+		if len(args) != 1 {
+			err = fmt.Errorf("%s needs a rule name", sub)
+		} else if err = c.ruleStore.SetEnabled(args[0], sub == "enable"); err == nil {
+			fmt.Printf("Rule %s %sd.\n", args[0], sub)
+		}
+	case "rm":
+		if len(args) != 1 {
+			err = fmt.Errorf("rm needs a rule name")
+		} else if err = c.ruleStore.Remove(args[0]); err == nil {
+			fmt.Printf("Rule %s removed.\n", args[0])
+		}
+	case "edit":
+		err = c.editRules()
+	default:
+		fmt.Println(rulesUsage)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
+func printRules(w io.Writer, all []rules.Rule) {
+	if len(all) == 0 {
+		fmt.Fprintln(w, "No rules. Add one with: sprayer rules add -name n -when EXPR -do ACTIONS")
+		return
+	}
+	for _, r := range all {
+		state := "on "
+		if !r.Enabled {
+			state = "off"
+		}
+		last := ""
+		if r.LastHit != nil {
+			last = ", last " + r.LastHit.Local().Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%3d  %s  %-20s %s\n       → %s  (%d hits%s)\n", r.Position, state, r.Name, r.When,
+			strings.ReplaceAll(rules.FormatActions(r.Actions), "\n", ", "), r.Hits, last)
+	}
+}
+
+func (c *CLI) addRule(args []string) error {
+	fs := flag.NewFlagSet("rules add", flag.ExitOnError)
+	name := fs.String("name", "", "Rule name (required)")
+	when := fs.String("when", "", "Condition, e.g. keyword:on-call or company:recruit")
+	do := fs.String("do", "", "Actions, e.g. tag:on-call, score:-10")
+	fs.Parse(args)
+
+	actions, err := rules.ParseActions(*do)
+	if err != nil {
+		return err
+	}
+	r := &rules.Rule{Name: *name, When: *when, Actions: actions}
+	if err := c.ruleStore.Add(r); err != nil {
+		return err
+	}
+	fmt.Printf("Added rule %s at position %d. Preview it with: sprayer rules test %s\n", r.Name, r.Position, r.Name)
+	return nil
+}
+
+// testRules shows what rules would do to the stored jobs without saving
+// anything: one named rule, an unsaved -when/-do pair, or every enabled
+// rule.
+func (c *CLI) testRules(w io.Writer, args []string) error {
+	fs := flag.NewFlagSet("rules test", flag.ExitOnError)
+	when := fs.String("when", "", "Condition to try instead of a stored rule")
+	do := fs.String("do", "tag:match", "Actions for -when")
+	fs.Parse(args)
+
+	var candidates []rules.Rule
+	switch {
+	case *when != "":
+		actions, err := rules.ParseActions(*do)
+		if err != nil {
+			return err
+		}
+		candidates = []rules.Rule{{Name: "(test)", When: *when, Actions: actions, Enabled: true}}
+	case fs.NArg() > 0:
+		r, err := c.ruleStore.ByName(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		r.Enabled = true
+		candidates = []rules.Rule{*r}
+	default:
+		all, err := c.ruleStore.All()
+		if err != nil {
+			return err
+		}
+		candidates = all
+	}
+	set, err := rules.Compile(candidates)
+	if err != nil {
+		return err
+	}
+	jobs, err := c.store.All()
+	if err != nil {
+		return err
+	}
+	dryRun(w, set, jobs)
+	return nil
+}
+
+// dryRun prints each job set would match and what it would do to it.
+func dryRun(w io.Writer, set *rules.Set, jobs []job.Job) {
+	matched := 0
+	for _, stored := range jobs {
+		j := rules.Reset(stored)
+		out := set.Apply(&j)
+		if len(out.Matched) == 0 {
+			continue
+		}
+		matched++
+		names := make([]string, len(out.Matched))
+		for i, r := range out.Matched {
+			names[i] = r.Name
+		}
+		fmt.Fprintf(w, "%s %s @ %s  [%s]\n", j.Ref(), j.Title, j.Company, strings.Join(names, ", "))
+		var effects []string
+		if len(j.Tags) > 0 {
+			effects = append(effects, "tags "+strings.Join(j.Tags, ", "))
+		}
+		if j.ScoreAdjust != 0 {
+			effects = append(effects, fmt.Sprintf("score %d → %d", rules.Reset(stored).Score, j.Score))
+		}
+		if out.Hide {
+			effects = append(effects, "hidden")
+		}
+		if j.Note != "" {
+			effects = append(effects, fmt.Sprintf("note %q", j.Note))
+		}
+		if len(effects) > 0 {
+			fmt.Fprintf(w, "    %s\n", strings.Join(effects, "; "))
+		}
+	}
+	fmt.Fprintf(w, "%d of %d stored jobs match.\n", matched, len(jobs))
+}
+
+func (c *CLI) editRules() error {
+	all, err := c.ruleStore.All()
+	if err != nil {
+		return err
+	}
+	_, err = tea.NewProgram(rulelist.New(all, c.ruleStore.SetEnabled), tea.WithAltScreen()).Run()
+	return err
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/rules"
+)
+
+func TestTestRules_DryRunLeavesJobsAlone(t *testing.T) {
+	c := newTestCLI(t)
+	rs, err := rules.NewStore(c.store.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.ruleStore = rs
+	actions, _ := rules.ParseActions("tag:on-call, score:-10, hide")
+	if err := rs.Add(&rules.Rule{Name: "on-call", When: "keyword:on-call", Actions: actions}); err != nil {
+		t.Fatal(err)
+	}
+	jobs := []job.Job{
+		{ID: "a", Title: "SRE", Company: "Acme", Description: "on-call weekly", Score: 60},
+		{ID: "b", Title: "Go Developer", Company: "Widgets", Description: "no pager"},
+	}
+	if err := c.store.Save(jobs); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := c.testRules(&out, []string{"on-call"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"SRE @ Acme  [on-call]", "tags on-call; score 60 → 50; hidden", "1 of 2 stored jobs match."} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := c.testRules(&out, []string{"-when", "company:widgets"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Go Developer @ Widgets  [(test)]") {
+		t.Errorf("ad hoc condition output:\n%s", out.String())
+	}
+
+	stored, _ := c.store.ByID("a")
+	if stored.Tags != nil || stored.Score != 60 {
+		t.Errorf("dry run changed the job: %+v", stored)
+	}
+	if r, _ := rs.ByName("on-call"); r.Hits != 0 {
+		t.Errorf("dry run counted %d hits", r.Hits)
+	}
+}
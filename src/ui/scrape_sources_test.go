@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/profile"
+	"sprayer/src/api/scraper"
+)
+
+func TestSelectedScraper(t *testing.T) {
+	c := newTestCLI(t)
+	ps, err := profile.NewStore(c.store.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.profileStore = ps
+	ps.Save(profile.Profile{ID: "quiet", Sources: []string{"hn", "remoteok", "linkedin"}})
+	kw := []string{"go"}
+
+	tests := []struct {
+		profile, flag string
+		fast          bool
+		count         int
+		all           bool
+		err           string
+	}{
+		{"default", "", false, scraper.SourceCount(false), true, ""},
+		{"default", "", true, scraper.SourceCount(true), true, ""},
+		{"quiet", "", false, 3, false, ""},
+		{"quiet", "", true, 2, false, ""},
+		{"quiet", "hn, jobicy", false, 2, false, ""},
+		{"default", "hn,monster", false, 0, false, `unknown source "monster"`},
+		{"default", "linkedin", true, 0, false, "-fast skips every selected source"},
+	}
+	for _, tt := range tests {
+		s, n, err := c.selectedScraper(context.Background(), tt.profile, tt.flag, tt.fast, kw)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s %q fast=%v: err %v, want %q", tt.profile, tt.flag, tt.fast, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || n != tt.count || (s == nil) != tt.all {
+			t.Errorf("%s %q fast=%v: %d sources, all=%v, %v; want %d, all=%v",
+				tt.profile, tt.flag, tt.fast, n, s == nil, err, tt.count, tt.all)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"sprayer/src/api/scraperun"
+)
+
+// startRun records a scrape beginning. It returns 0 when runs are not
+// recorded, which finishRun ignores; a scrape never fails for want of
+// its history.
+func (c *CLI) startRun(profileID string, sources int) int64 {
+	if c.runStore == nil {
+		return 0
+	}
+	id, err := c.runStore.Start(profileID, sources, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Recording the scrape: %v\n", err)
+	}
+	return id
+}
+
+func (c *CLI) finishRun(id int64, jobsFound int, errs []string) {
+	if id == 0 {
+		return
+	}
+	if err := c.runStore.Finish(id, jobsFound, errs, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Recording the scrape: %v\n", err)
+	}
+}
+
+// recordFetched records that run's sources returned fetched postings and
+// returns the summary line saying so, against the profile's last scrape.
+func (c *CLI) recordFetched(run int64, profileID string, fetched int) string {
+	if run == 0 {
+		return fetchSummary(fetched, 0)
+	}
+	if err := c.runStore.RecordFetched(run, fetched); err != nil {
+		fmt.Fprintf(os.Stderr, "Recording the scrape: %v\n", err)
+	}
+	prev, _, err := c.runStore.PreviousFetched(profileID, run)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Reading the last scrape: %v\n", err)
+	}
+	return fetchSummary(fetched, prev)
+}
+
+// fetchSummary reads "Fetched 84 postings, 93% fewer than the last scrape
+// (1204)." so a narrower search shows in what is downloaded; prev is 0
+// when there is no last scrape to compare with.
+func fetchSummary(fetched, prev int) string {
+	switch {
+	case prev == 0:
+		return fmt.Sprintf("Fetched %d postings.", fetched)
+	case fetched < prev:
+		return fmt.Sprintf("Fetched %d postings, %d%% fewer than the last scrape (%d).", fetched, (prev-fetched)*100/prev, prev)
+	}
+	return fmt.Sprintf("Fetched %d postings (%d the last scrape).", fetched, prev)
+}
+
+// handleScrapeHistory lists recent scrapes, newest first.
+func (c *CLI) handleScrapeHistory(args []string) {
+	fs := flag.NewFlagSet("scrape history", flag.ExitOnError)
+	n := fs.Int("n", 10, "How many runs to show")
+	asJSON := fs.Bool("json", false, "Print the runs as JSON")
+	fs.Parse(args)
+
+	var runs []scraperun.Run
+	if c.runStore != nil {
+		var err error
+		if runs, err = c.runStore.Recent(*n); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+	}
+	if *asJSON {
+		writeJSON(os.Stdout, runs)
+		return
+	}
+	printRuns(os.Stdout, runs, time.Now())
+}
+
+func printRuns(w io.Writer, runs []scraperun.Run, now time.Time) {
+	if len(runs) == 0 {
+		fmt.Fprintln(w, "No scrapes recorded.")
+		return
+	}
+	for _, r := range runs {
+		took := "unfinished"
+		if r.FinishedAt != nil {
+			took = r.Duration().Round(time.Second).String()
+		}
+		fetched := ""
+		if r.Fetched > 0 {
+			fetched = fmt.Sprintf("  %d fetched", r.Fetched)
+		}
+		fmt.Fprintf(w, "#%d  %s (%s)  %s  profile %s  %d sources%s  %d jobs  %d errors\n",
+			r.ID, r.StartedAt.Local().Format("2006-01-02 15:04"), ago(now.Sub(r.StartedAt)), took,
+			r.ProfileID, r.SourcesCount, fetched, r.JobsFound, len(r.Errors))
+		for _, e := range r.Errors {
+			fmt.Fprintf(w, "      %s\n", e)
+		}
+	}
+}
+
+// ago formats d coarsely, e.g. "2h ago".
+func ago(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
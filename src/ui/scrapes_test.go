@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/scraperun"
+)
+
+func TestPrintRuns(t *testing.T) {
+	c := newTestCLI(t)
+	runs, err := scraperun.NewStore(c.store.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.runStore = runs
+
+	now := time.Now()
+	first := c.startRun("default", 20)
+	c.finishRun(first, 0, []string{"error scraping LinkedIn: launch browser"})
+	second := c.startRun("default", 15)
+	c.finishRun(second, 212, nil)
+	if got := c.recordFetched(second, "default", 84); got != "Fetched 84 postings." {
+		t.Errorf("recordFetched = %q", got)
+	}
+
+	recent, _ := runs.Recent(10)
+	var out bytes.Buffer
+	printRuns(&out, recent, now.Add(2*time.Hour+time.Minute))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines:\n%s", len(lines), out.String())
+	}
+	for i, want := range []string{
+		"(2h ago)  0s  profile default  15 sources  84 fetched  212 jobs  0 errors",
+		"profile default  20 sources  0 jobs  1 errors",
+		"      error scraping LinkedIn: launch browser",
+	} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("line %d = %q, want it to contain %q", i, lines[i], want)
+		}
+	}
+
+	out.Reset()
+	printRuns(&out, nil, now)
+	if out.String() != "No scrapes recorded.\n" {
+		t.Errorf("empty history = %q", out.String())
+	}
+}
+
+func TestFetchSummary(t *testing.T) {
+	for _, tc := range []struct {
+		fetched, prev int
+		want          string
+	}{
+		{84, 0, "Fetched 84 postings."},
+		{84, 1204, "Fetched 84 postings, 93% fewer than the last scrape (1204)."},
+		{90, 84, "Fetched 90 postings (84 the last scrape)."},
+	} {
+		if got := fetchSummary(tc.fetched, tc.prev); got != tc.want {
+			t.Errorf("fetchSummary(%d, %d) = %q, want %q", tc.fetched, tc.prev, got, tc.want)
+		}
+	}
+}
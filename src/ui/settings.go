@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"sprayer/src/api/settings"
+)
+
+// handleSettings shows the settings in effect (--show, the default),
+// writes the stored ones to a file (--export) or stores those read from
+// one (--import). In the TUI , edits them.
+func (c *CLI) handleSettings() {
+	fs := flag.NewFlagSet("settings", flag.ExitOnError)
+	fs.Bool("show", false, "Print each setting, its value and where the value comes from (the default)")
+	exportTo := fs.String("export", "", "Write the stored settings as JSON to this file (- for stdout)")
+	importFrom := fs.String("import", "", "Store the settings in this JSON file (- for stdin) over the stored ones")
+	fs.Parse(os.Args[2:])
+
+	var err error
+	switch {
+	case *importFrom != "":
+		err = c.importSettings(*importFrom)
+		if err == nil {
+			fmt.Printf("Imported settings from %s.\n", *importFrom)
+		}
+	case *exportTo == "-":
+		err = c.exportSettings(os.Stdout)
+	case *exportTo != "":
+		var f *os.File
+		if f, err = os.Create(*exportTo); err == nil {
+			if err = c.exportSettings(f); err == nil {
+				err = f.Close()
+			} else {
+				f.Close()
+			}
+		}
+		if err == nil {
+			fmt.Printf("Exported settings to %s.\n", *exportTo)
+		}
+	default:
+		err = c.showSettings(os.Stdout)
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
+// showSettings prints every setting with the value it takes and its
+// source, and the environment variable that overrides it.
+func (c *CLI) showSettings(w io.Writer) error {
+	stored, err := c.settingsStore.Load()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Precedence: %s\n\n", settings.Precedence)
+	for _, v := range settings.Resolve(stored, c.settingsEnv) {
+		value := v.Value
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(w, "  %-19s %-24s %-12s %s\n", v.Key, value, v.Source, v.Env)
+	}
+	return nil
+}
+
+// exportSettings writes the stored settings, not the environment's.
+func (c *CLI) exportSettings(w io.Writer) error {
+	stored, err := c.settingsStore.Load()
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(stored)
+}
+
+// importSettings stores the settings in the file at path on top of the
+// stored ones. Keys it does not know, such as a password, are refused.
+func (c *CLI) importSettings(path string) error {
+	in := os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	stored, err := c.settingsStore.Load()
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(in)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&stored); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	return c.settingsStore.Save(stored)
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/settings"
+)
+
+func TestSettings_ImportShowExport(t *testing.T) {
+	// NewCLI puts stored settings in the environment; t.Setenv puts it
+	// back as it was once the test ends.
+	for _, f := range settings.Fields {
+		t.Setenv(f.Env, "")
+	}
+	c := newHomeCLI(t)
+
+	in := filepath.Join(t.TempDir(), "settings.json")
+	os.WriteFile(in, []byte(`{"default_profile": "rust", "smtp_port": "2525", "theme": "mono"}`), 0644)
+	if out := runCommand(t, c, "settings", "--import", in); !strings.Contains(out, "Imported") {
+		t.Fatalf("import printed %q", out)
+	}
+	os.WriteFile(in, []byte(`{"smtp_pass": "hunter2"}`), 0644)
+	if out := runCommand(t, c, "settings", "--import", in); !strings.Contains(out, `unknown field "smtp_pass"`) {
+		t.Errorf("a secret was not refused: %q", out)
+	}
+
+	// A new start applies them, except where the environment says otherwise.
+	t.Setenv(settings.EnvTheme, "dark")
+	c, err := NewCLI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.store.Close()
+	if got := c.defaultProfile(); got != "rust" {
+		t.Errorf("default profile %q, want the stored rust", got)
+	}
+
+	out := runCommand(t, c, "settings")
+	for _, want := range []string{
+		regexp.QuoteMeta("Precedence: " + settings.Precedence),
+		`default_profile +rust +stored +SPRAYER_DEFAULT_PROFILE`,
+		`theme +dark +environment +SPRAYER_THEME`,
+		`smtp_port +2525 +stored`,
+		`export_format +csv +default`,
+	} {
+		if !regexp.MustCompile(want).MatchString(out) {
+			t.Errorf("settings --show lacks %q:\n%s", want, out)
+		}
+	}
+
+	out = runCommand(t, c, "settings", "--export", "-")
+	if !strings.Contains(out, `"theme": "mono"`) || strings.Contains(out, "export_format") {
+		t.Errorf("export should hold what is stored, not the environment or defaults:\n%s", out)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 
 	"github.com/charmbracelet/huh"
+
+	"sprayer/src/api/brand"
 )
 
 func (c *CLI) handleSetup() {
@@ -17,6 +19,12 @@ func (c *CLI) handleSetup() {
 		llmKey   string = os.Getenv("SPRAYER_LLM_KEY")
 		llmURL   string = os.Getenv("SPRAYER_LLM_URL")
 		llmModel string = os.Getenv("SPRAYER_LLM_MODEL")
+
+		brandPrimary string = os.Getenv(brand.EnvPrimary)
+		brandAccent  string = os.Getenv(brand.EnvAccent)
+		brandFont    string = os.Getenv(brand.EnvFont)
+		brandName    string = os.Getenv(brand.EnvName)
+		brandTagline string = os.Getenv(brand.EnvTagline)
 	)
 
 	form := huh.NewForm(
@@ -66,6 +74,37 @@ func (c *CLI) handleSetup() {
 				Value(&llmModel).
 				Placeholder("gpt-4o"),
 		),
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Branding").
+				Description("Colors, font and name for generated documents. Blank keeps the defaults."),
+
+			huh.NewInput().
+				Title("Primary Color").
+				Value(&brandPrimary).
+				Placeholder(brand.DefaultPrimary).
+				Validate(brandColor),
+
+			huh.NewInput().
+				Title("Accent Color").
+				Value(&brandAccent).
+				Placeholder(brand.DefaultAccent).
+				Validate(brandColor),
+
+			huh.NewSelect[string]().
+				Title("Font").
+				Options(huh.NewOptions("", string(brand.FontSans), string(brand.FontSerif), string(brand.FontMono))...).
+				Value(&brandFont),
+
+			huh.NewInput().
+				Title("Name").
+				Value(&brandName),
+
+			huh.NewInput().
+				Title("Tagline").
+				Value(&brandTagline).
+				Placeholder("Backend engineer, Go and Postgres"),
+		),
 	)
 
 	err := form.Run()
@@ -82,7 +121,13 @@ SPRAYER_SMTP_FROM=%s
 SPRAYER_LLM_KEY=%s
 SPRAYER_LLM_URL=%s
 SPRAYER_LLM_MODEL=%s
-`, smtpHost, smtpPort, smtpUser, smtpPass, smtpFrom, llmKey, llmURL, llmModel)
+SPRAYER_BRAND_PRIMARY=%s
+SPRAYER_BRAND_ACCENT=%s
+SPRAYER_BRAND_FONT=%s
+SPRAYER_BRAND_NAME=%q
+SPRAYER_BRAND_TAGLINE=%q
+`, smtpHost, smtpPort, smtpUser, smtpPass, smtpFrom, llmKey, llmURL, llmModel,
+		brandPrimary, brandAccent, brandFont, brandName, brandTagline)
 
 	err = os.WriteFile(".env", []byte(content), 0600)
 	if err != nil {
@@ -92,3 +137,11 @@ SPRAYER_LLM_MODEL=%s
 
 	fmt.Println("Configuration saved to .env")
 }
+
+// brandColor validates an optional hex color field of the setup form.
+func brandColor(v string) error {
+	if v != "" && !brand.ValidColor(v) {
+		return fmt.Errorf("%q is not a hex color like %s", v, brand.DefaultPrimary)
+	}
+	return nil
+}
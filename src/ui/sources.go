@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"sprayer/src/api/scraper"
+	"sprayer/src/api/sourcestats"
+)
+
+const sourcesUsage = `Usage:
+  sprayer sources status [-zero-runs n] [-json]  (runs, jobs, errors, latency and last success per source)`
+
+func (c *CLI) handleSources() {
+	if len(os.Args) < 3 || os.Args[2] != "status" {
+		fmt.Println(sourcesUsage)
+		return
+	}
+	fs := flag.NewFlagSet("sources status", flag.ExitOnError)
+	zeroRuns := fs.Int("zero-runs", sourcestats.DefaultZeroRuns, "Flag a source that returned no jobs in this many runs in a row")
+	asJSON := fs.Bool("json", false, "Print the stats as JSON")
+	fs.Parse(os.Args[3:])
+
+	var stats []sourcestats.Stats
+	if c.statsStore != nil {
+		var err error
+		if stats, err = c.statsStore.All(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+	}
+	if *asJSON {
+		writeJSON(os.Stdout, stats)
+		return
+	}
+	printSourceStats(os.Stdout, stats, scraper.KnownSourceKeys(), *zeroRuns, time.Now())
+}
+
+// printSourceStats lists each source's record, flagging one that
+// returned no jobs in its last zeroRuns runs, then the known sources
+// never run.
+func printSourceStats(w io.Writer, stats []sourcestats.Stats, known []string, zeroRuns int, now time.Time) {
+	ran := make(map[string]bool)
+	for _, st := range stats {
+		ran[st.Source] = true
+		lastOK := "never succeeded"
+		if st.LastSuccess != nil {
+			lastOK = "last ok " + ago(now.Sub(*st.LastSuccess))
+		}
+		fmt.Fprintf(w, "%-16s %3d runs  %5d jobs (%d last)  %2d errors  avg %s  %s\n",
+			st.Source, st.Runs, st.Jobs, st.LastJobs, st.Errors, st.AvgLatency().Round(100*time.Millisecond), lastOK)
+		if st.Flagged(zeroRuns) {
+			fmt.Fprintf(w, "    ! no jobs in the last %d runs; it may be broken\n", st.ZeroStreak)
+		}
+		if st.Partial > 0 {
+			fmt.Fprintf(w, "    %d of the runs cut short by a cancelled scrape\n", st.Partial)
+		}
+		if st.LastError != "" && st.LastJobs == 0 {
+			fmt.Fprintf(w, "    last error: %s\n", st.LastError)
+		}
+	}
+	for _, key := range known {
+		if !ran[key] {
+			fmt.Fprintf(w, "%-16s never run\n", key)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/sourcestats"
+)
+
+func TestPrintSourceStats(t *testing.T) {
+	now := time.Now()
+	ok := now.Add(-3 * time.Hour)
+	stats := []sourcestats.Stats{
+		{Source: "hn", Runs: 4, Jobs: 120, LastJobs: 30, Latency: 8 * time.Second, LastSuccess: &ok, Partial: 1},
+		{Source: "jobicy", Runs: 5, Jobs: 10, Errors: 1, LastError: "Jobicy parse: unexpected end", ZeroStreak: 3},
+	}
+	var out bytes.Buffer
+	printSourceStats(&out, stats, []string{"hn", "jobicy", "remoteok"}, 3, now)
+	got := out.String()
+	for _, want := range []string{
+		"4 runs    120 jobs (30 last)   0 errors  avg 2s  last ok 3h ago",
+		"never succeeded",
+		"! no jobs in the last 3 runs",
+		"last error: Jobicy parse: unexpected end",
+		"remoteok         never run",
+		"1 of the runs cut short by a cancelled scrape",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "no jobs in the last") != 1 {
+		t.Errorf("only jobicy should be flagged:\n%s", got)
+	}
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/traps"
+)
+
+// flagTraps flags jobs with the loaded trap rules.
+func (c *CLI) flagTraps() job.Filter {
+	return job.FlagTrapsWith(c.trapMatcher())
+}
+
+// trapMatcher finds the loaded trap rules' matches in a text. A broken
+// rules directory is reported and the built-in rules apply instead, so
+// one bad shared file does not stop a scrape.
+func (c *CLI) trapMatcher() func(string) []job.TrapMatch {
+	set, err := traps.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Trap rules: %v (using built-in rules; run 'sprayer traps -lint' on the file)\n", err)
+		set, _ = traps.Compile(traps.Builtin())
+	}
+	return set.Matches
+}
+
+// handleTraps lists the effective trap rules, lints rule files, or shows
+// which rules fire on some text.
+func (c *CLI) handleTraps() {
+	fs := flag.NewFlagSet("traps", flag.ExitOnError)
+	lint := fs.String("lint", "", "Validate a rule file")
+	test := fs.String("test", "", "Show which rules fire on this text")
+	fs.Parse(os.Args[2:])
+
+	if *lint != "" {
+		data, err := os.ReadFile(*lint)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		rules, err := traps.Parse(data, *lint)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		problems := traps.Lint(rules)
+		for _, p := range problems {
+			fmt.Printf("%s: %s\n", *lint, p)
+		}
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+		fmt.Printf("%s: %d rules OK\n", *lint, len(rules))
+		return
+	}
+
+	set, err := traps.Load()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *test != "" {
+		hits := set.Find(*test)
+		if len(hits) == 0 {
+			fmt.Println("No rules fire.")
+			return
+		}
+		for _, h := range hits {
+			fmt.Printf("[%s] %s: %q (%s)\n", h.Rule.Severity, h.Rule.Name, h.Match, h.Rule.Source)
+		}
+		return
+	}
+
+	fmt.Printf("Rules from built-ins and %s:\n", traps.Dir())
+	for _, r := range set.Rules() {
+		lang := ""
+		if r.Language != "" {
+			lang = " [" + r.Language + "]"
+		}
+		fmt.Printf("  %-24s %-6s%s %s (%s)\n", r.Name, r.Severity, lang, r.Description, r.Source)
+	}
+}
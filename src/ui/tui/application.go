@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/application"
+	"sprayer/src/ui/tui/theme"
+)
+
+// WithApplications shows in Detail what was sent when the job was
+// applied to, as find reads it; (*application.Store).ForJob satisfies
+// it.
+func WithApplications(find func(jobID string) (*application.Application, error)) Option {
+	return func(m *Model) { m.findApplication = find }
+}
+
+// applicationMsg delivers the application to the job open in Detail, nil
+// when there is none.
+type applicationMsg struct {
+	jobID string
+	app   *application.Application
+}
+
+// loadApplication reads the application to the selected job.
+func (m Model) loadApplication() tea.Cmd {
+	if m.findApplication == nil || len(m.jobs) == 0 {
+		return nil
+	}
+	find, id := m.findApplication, m.jobs[m.selectedIndex].ID
+	return func() tea.Msg {
+		a, err := find(id)
+		if err != nil {
+			a = nil
+		}
+		return applicationMsg{jobID: id, app: a}
+	}
+}
+
+// applicationLines is Detail's Application section: when and how the job
+// was applied to and, for an email, exactly what was sent.
+func (m Model) applicationLines(label, text lipgloss.Style) []string {
+	a := m.app
+	if a == nil {
+		return nil
+	}
+	lines := []string{"", label.Render("Application")}
+	s := a.Sent
+	if s == nil {
+		return append(lines, text.Render("  "+string(a.Method)+" on "+a.AppliedAt.Local().Format("2006-01-02")))
+	}
+	lines = append(lines,
+		text.Render("  sent "+s.SentAt.Local().Format("2006-01-02 15:04")),
+		label.Render("  From     ")+text.Render(s.From),
+		label.Render("  To       ")+text.Render(strings.Join(append([]string{s.To}, s.Cc...), ", ")))
+	if s.CVPath != "" {
+		lines = append(lines, label.Render("  CV       ")+theme.SuccessStyle.Render(s.CVPath))
+	}
+	if s.CoverLetterPath != "" {
+		lines = append(lines, label.Render("  Cover    ")+text.Render(s.CoverLetterPath))
+	}
+	lines = append(lines, label.Render("  Subject  ")+text.Render(s.Subject), "")
+	for _, l := range strings.Split(strings.TrimRight(s.Body, "\n"), "\n") {
+		lines = append(lines, text.Render("  "+l))
+	}
+	return lines
+}
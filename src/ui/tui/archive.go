@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/job"
+	"sprayer/src/ui/tui/theme"
+)
+
+// WithArchiver archives and restores jobs with x and X through archive,
+// which records the change for the profile.
+func WithArchiver(archive func(jobID string, archived bool) error) Option {
+	return func(m *Model) { m.archive = archive }
+}
+
+// WithHideApplied starts with jobs already applied to hidden or shown as
+// hide says; h toggles it and save keeps the choice for next time.
+func WithHideApplied(hide bool, save func(bool) error) Option {
+	return func(m *Model) { m.hideApplied, m.saveHideApplied = hide, save }
+}
+
+// listSavedMsg reports saving an archive or hide-applied change.
+type listSavedMsg struct{ err error }
+
+// refilter derives the listed jobs from all: the archived ones in the
+// archived view, else the rest, less those applied to while hideApplied
+// is on, and to the jobs passing the filter preset applied, in the
+// list's sort order. The cursor stays on the job it was on
+// while that is listed.
+func (m Model) refilter() Model {
+	selected := m.selectedID()
+	switch {
+	case m.archivedView:
+		m.jobs = job.Select(m.all, func(j job.Job) bool { return j.Archived })
+	case m.hideApplied:
+		m.jobs = job.Pipe(job.ExcludeArchived(), job.ExcludeApplied())(m.all)
+	default:
+		m.jobs = job.ExcludeArchived()(m.all)
+	}
+	if m.preset != nil {
+		m.jobs = job.Select(m.jobs, func(j job.Job) bool { return m.presetIDs[j.ID] })
+	}
+	m.jobs = job.SortBy(m.sort.Less(time.Now(), job.ClosingWindow()))(m.jobs)
+	m.selectedIndex = max(min(m.selectedIndex, len(m.jobs)-1), 0)
+	if i := slices.IndexFunc(m.jobs, func(j job.Job) bool { return j.ID == selected }); i >= 0 {
+		m.selectedIndex = i
+	}
+	return m
+}
+
+// withJob returns jobs with j in place of the job with its ID.
+func withJob(jobs []job.Job, j job.Job) []job.Job {
+	i := slices.IndexFunc(jobs, func(k job.Job) bool { return k.ID == j.ID })
+	if i < 0 {
+		return jobs
+	}
+	jobs = slices.Clone(jobs)
+	jobs[i] = j
+	return jobs
+}
+
+// setArchived archives or restores the selected job. Like a status
+// change it shows at once; a failed save shows in the status bar.
+func (m Model) setArchived(archived bool) (Model, tea.Cmd) {
+	if len(m.jobs) == 0 || m.jobs[m.selectedIndex].Archived == archived {
+		return m, nil
+	}
+	j := m.jobs[m.selectedIndex]
+	j.Archived = archived
+	m.all = withJob(m.all, j)
+	m = m.refilter()
+	if m.archive == nil {
+		return m, nil
+	}
+	archive, id := m.archive, j.ID
+	return m, func() tea.Msg { return listSavedMsg{err: archive(id, archived)} }
+}
+
+// toggleHideApplied hides the jobs applied to, or shows them again.
+func (m Model) toggleHideApplied() (Model, tea.Cmd) {
+	m.hideApplied = !m.hideApplied
+	m = m.refilter()
+	if m.saveHideApplied == nil {
+		return m, nil
+	}
+	save, hide := m.saveHideApplied, m.hideApplied
+	return m, func() tea.Msg { return listSavedMsg{err: save(hide)} }
+}
+
+// toggleArchivedView switches between the archived jobs and the rest.
+func (m Model) toggleArchivedView() Model {
+	m.archivedView, m.selectedIndex, m.listOffset = !m.archivedView, 0, 0
+	return m.refilter()
+}
+
+// listNotice is the status bar's word on the list: that it shows the
+// archived jobs or a filter preset's, or why a change to it was not
+// saved.
+func (m Model) listNotice() string {
+	if m.listErr != nil {
+		return theme.ErrorStyle.Render("not saved: " + m.listErr.Error())
+	}
+	if m.archivedView {
+		return theme.StatusLabelStyle.Render("archived · X restore · esc back")
+	}
+	if name := m.presetName(); name != "" {
+		return theme.StatusLabelStyle.Render("preset: " + name)
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/job"
+)
+
+func TestModel_ArchiveAndHideApplied(t *testing.T) {
+	jobs := fixtureJobs()
+	jobs[0].Applied = true
+	jobs = append(jobs, job.Job{ID: "4", Title: "Junk Posting", Company: "Spam", Score: 10, Archived: true})
+
+	archived := map[string]bool{"4": true}
+	var hides []bool
+	m := drive(NewModel(WithJobSource(fixtureSource(jobs)),
+		WithArchiver(func(id string, a bool) error { archived[id] = a; return nil }),
+		WithHideApplied(false, func(hide bool) error { hides = append(hides, hide); return nil })))
+
+	if view := plain(m); !strings.Contains(view, "Jobs: 3/4") || strings.Contains(view, "Junk Posting") {
+		t.Fatalf("archived job listed or not counted:\n%s", view)
+	}
+
+	m = run(m, key("j"))
+	m = run(m, key("x"))
+	if !archived["2"] {
+		t.Error("x did not archive the selected job")
+	}
+	if view := plain(m); !strings.Contains(view, "Jobs: 2/4") || strings.Contains(view, "Platform Engineer") {
+		t.Errorf("archived job still listed:\n%s", view)
+	}
+	if m.(Model).selectedID() != "3" {
+		t.Errorf("cursor on %s after archiving, want the next job", m.(Model).selectedID())
+	}
+
+	m = run(m, key("h"))
+	if view := plain(m); !strings.Contains(view, "Jobs: 1/4") || strings.Contains(view, "Senior Go Engineer") {
+		t.Errorf("applied job not hidden:\n%s", view)
+	}
+	if len(hides) != 1 || !hides[0] {
+		t.Errorf("saved hide-applied %v, want [true]", hides)
+	}
+
+	m = run(m, key("v"))
+	view := plain(m)
+	if !strings.Contains(view, "Archived: 2/4") || !strings.Contains(view, "Junk Posting") || strings.Contains(view, "Backend Developer") {
+		t.Fatalf("archived view wrong:\n%s", view)
+	}
+	m = run(m, key("x")) // already archived: nothing to do
+	m = run(m, key("X"))
+	if archived["2"] || len(m.(Model).jobs) != 1 {
+		t.Errorf("X did not restore the selected job: %v", archived)
+	}
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if view := plain(m); m.(Model).archivedView || !strings.Contains(view, "Platform Engineer") {
+		t.Errorf("esc did not leave the archived view:\n%s", view)
+	}
+}
+
+func TestModel_HideApplied_StartsHiddenAndKeepsFollowUps(t *testing.T) {
+	due := time.Now().AddDate(0, 0, -1)
+	jobs := fixtureJobs()
+	jobs[0].Applied, jobs[0].FollowUpAt = true, &due
+	m := drive(NewModel(WithJobSource(fixtureSource(jobs)), WithHideApplied(true, nil)))
+	view := plain(m)
+	if !strings.Contains(view, "Jobs: 2/3") || strings.Contains(view, "Senior Go Engineer") {
+		t.Errorf("applied job shown:\n%s", view)
+	}
+	if !strings.Contains(view, "1 follow-up due") {
+		t.Errorf("hidden job's follow-up not counted:\n%s", view)
+	}
+}
+
+func TestModel_Archive_SaveFails(t *testing.T) {
+	m := drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())),
+		WithArchiver(func(string, bool) error { return errors.New("database is locked") })))
+	m = run(m, key("x"))
+	if view := plain(m); !strings.Contains(view, "not saved: database is locked") {
+		t.Errorf("failed archive not shown:\n%s", view)
+	}
+}
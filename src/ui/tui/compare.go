@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/profile"
+	"sprayer/src/ui/tui/theme"
+)
+
+// comparisonView is the state of the Compare view: the jobs either of
+// two profiles matches, with both profiles' scores, sorted by those of
+// the by'th.
+type comparisonView struct {
+	names    []string
+	jobs     []profile.Compared
+	by       int
+	selected int
+	busy     bool
+	err      error
+}
+
+// comparedMsg delivers the comparison of the profiles picked.
+type comparedMsg struct {
+	jobs []profile.Compared
+	err  error
+}
+
+// openComparison compares the two profiles picked in Profiles.
+func (m Model) openComparison() (Model, tea.Cmd) {
+	v := &m.profilesView
+	if len(v.picked) != 2 {
+		v.err = fmt.Errorf("pick two profiles with space to compare them")
+		return m, nil
+	}
+	c := comparisonView{busy: true}
+	for _, id := range v.picked {
+		name := id
+		for _, p := range v.profiles {
+			if p.ID == id {
+				name = p.Name
+			}
+		}
+		c.names = append(c.names, name)
+	}
+	m.viewState, m.comparison = Compare, c
+	comparer, ids := m.profileComparer, append([]string(nil), v.picked...)
+	return m, func() tea.Msg {
+		jobs, err := comparer.Compare(ids, 0)
+		return comparedMsg{jobs: jobs, err: err}
+	}
+}
+
+// updateCompare handles a key in the Compare view: tab sorts by the
+// other profile's score.
+func (m Model) updateCompare(msg tea.KeyMsg) (Model, tea.Cmd) {
+	v := &m.comparison
+	switch msg.String() {
+	case "esc":
+		m.viewState = Profiles
+	case "ctrl+c", "q":
+		return m.quit()
+	case "j", "down":
+		v.selected = max(min(v.selected+1, len(v.jobs)-1), 0)
+	case "k", "up":
+		v.selected = max(v.selected-1, 0)
+	case "tab":
+		v.by, v.jobs = 1-v.by, slices.Clone(v.jobs)
+		profile.SortCompared(v.jobs, v.by)
+		v.selected = 0
+	}
+	return m, nil
+}
+
+// compareRows is how many jobs the Compare view shows at once.
+func (m Model) compareRows() int {
+	return max(m.height-10, 1)
+}
+
+// renderCompare lists the jobs with each profile's score, ✓ where the
+// profile matches the job, the column sorted by marked ▼.
+func (m Model) renderCompare() string {
+	bg := lipgloss.NewStyle().Background(theme.Background)
+	label := bg.Foreground(theme.Subtle)
+	v := m.comparison
+
+	lines := []string{bg.Foreground(theme.Bright).Bold(true).Render("Comparing " + strings.Join(v.names, " and ")), ""}
+	const col = 14
+	header := ""
+	for i, name := range v.names {
+		if i == v.by {
+			name = "▼ " + name
+		}
+		header += fmt.Sprintf("%-*s", col, truncate(name, col-2))
+	}
+	lines = append(lines, label.Render(header+"Job"))
+
+	switch {
+	case v.busy:
+		lines = append(lines, label.Render("Comparing…"))
+	case v.err != nil:
+		lines = append(lines, theme.ErrorStyle.Render(v.err.Error()))
+	case len(v.jobs) == 0:
+		lines = append(lines, label.Render("Neither profile matches a job."))
+	}
+	rows := m.compareRows()
+	offset := max(v.selected-rows+1, 0)
+	for i := offset; i < min(offset+rows, len(v.jobs)); i++ {
+		c := v.jobs[i]
+		row := ""
+		for _, e := range c.Profiles {
+			mark := "  "
+			if e.Matches {
+				mark = "✓ "
+			}
+			row += fmt.Sprintf("%-*s", col, fmt.Sprintf("%s%3d", mark, e.Score))
+		}
+		style := theme.JobItemStyle
+		if i == v.selected {
+			style = theme.JobItemSelectedStyle
+		}
+		lines = append(lines, style.Render(row+truncate(c.Title+" · "+c.Company, max(m.width-len(v.names)*col-6, 10))))
+	}
+	lines = append(lines, "", label.Render("tab sort by the other profile · esc back"))
+	block := bg.Padding(1, 2).Width(m.width).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Left, lipgloss.Top, block,
+		lipgloss.WithWhitespaceBackground(theme.Background))
+}
@@ -0,0 +1,164 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/richtext"
+	"sprayer/src/ui/tui/theme"
+)
+
+// LazyJobSource is a JobSource that lists jobs without their descriptions
+// and reads each one's when it is opened, so a large store loads fast;
+// *job.Store satisfies it.
+type LazyJobSource interface {
+	JobSource
+	Summaries(profileID string, opts ...job.ActiveOption) ([]job.Job, error)
+	Description(id string) (string, error)
+}
+
+// FullJobSource is a JobSource that can read the whole of a description
+// cut to job.DescriptionLimit when saved; *job.Store satisfies it.
+type FullJobSource interface {
+	JobSource
+	FullDescription(id string) (string, error)
+}
+
+// descriptionMsg delivers the description of the job open in Detail;
+// full marks the uncut text of a truncated one.
+type descriptionMsg struct {
+	jobID string
+	text  string
+	full  bool
+	err   error
+}
+
+// loadJobs reads the profile's jobs, without descriptions when the source
+// can read them later.
+func loadJobs(src JobSource, profileID string) ([]job.Job, error) {
+	if lazy, ok := src.(LazyJobSource); ok {
+		return lazy.Summaries(profileID, job.IncludeArchived())
+	}
+	return src.ForProfile(profileID, job.IncludeArchived())
+}
+
+// loadDescription reads the selected job's description unless it came
+// with the job.
+func (m Model) loadDescription() tea.Cmd {
+	lazy, ok := m.source.(LazyJobSource)
+	if !ok || len(m.jobs) == 0 || m.jobs[m.selectedIndex].Description != "" {
+		return nil
+	}
+	id := m.jobs[m.selectedIndex].ID
+	return func() tea.Msg {
+		text, err := lazy.Description(id)
+		return descriptionMsg{jobID: id, text: text, err: err}
+	}
+}
+
+// loadFullDescription reads the whole of the selected job's description
+// when it was cut on saving and the source can read the rest.
+func (m Model) loadFullDescription() tea.Cmd {
+	full, ok := m.source.(FullJobSource)
+	if !ok || !m.canLoadFull() {
+		return nil
+	}
+	id := m.jobs[m.selectedIndex].ID
+	return func() tea.Msg {
+		text, err := full.FullDescription(id)
+		return descriptionMsg{jobID: id, text: text, full: true, err: err}
+	}
+}
+
+// canLoadFull reports whether the selected job's description was cut
+// and the rest is not loaded yet.
+func (m Model) canLoadFull() bool {
+	if len(m.jobs) == 0 || !m.jobs[m.selectedIndex].Truncated {
+		return false
+	}
+	_, ok := m.source.(FullJobSource)
+	return ok && !(m.desc.full && m.desc.jobID == m.jobs[m.selectedIndex].ID)
+}
+
+// description is the text of j as far as it is loaded.
+func (m Model) description(j job.Job) string {
+	if m.desc.jobID == j.ID && (j.Description == "" || m.desc.full) {
+		return m.desc.text
+	}
+	return j.Description
+}
+
+// descriptionLines is Detail's Description section, laid out from its
+// HTML or Markdown, or wrapped as is when raw is on, and cut to the rows
+// left.
+func (m Model) descriptionLines(rows int, label, text lipgloss.Style) []string {
+	desc := strings.TrimSpace(m.description(m.jobs[m.selectedIndex]))
+	rows -= 2 // a blank line and the heading
+	if desc == "" || rows < 1 {
+		return nil
+	}
+	width := max(m.width-4, 10)
+	heading := "Description"
+	var lines []string
+	if m.rawDesc {
+		heading += " (raw)"
+		lines = strings.Split(text.Width(width).Render(desc), "\n")
+	} else {
+		lines = renderBlocks(richtext.Parse(desc), width, label, text)
+	}
+	var hint []string
+	if m.canLoadFull() && rows > 1 {
+		hint = []string{label.Render("description truncated — press x to load full text")}
+		rows--
+	}
+	if len(lines) > rows {
+		lines = append(lines[:rows-1], label.Render("…"))
+	}
+	return append(append([]string{"", label.Render(heading)}, lines...), hint...)
+}
+
+// renderBlocks lays blocks out in width columns: paragraphs and list items
+// wrap, code and table rows are cut so they never widen the view.
+func renderBlocks(blocks []richtext.Block, width int, label, text lipgloss.Style) []string {
+	var lines []string
+	for _, b := range blocks {
+		var out string
+		switch b.Kind {
+		case richtext.Break:
+			out = ""
+		case richtext.Heading:
+			out = text.Foreground(theme.Bright).Bold(true).Width(width).Render(b.Text)
+		case richtext.Item:
+			indent := 2 * min(b.Level, 4)
+			bullet := text.Render(strings.Repeat(" ", indent) + "• ")
+			body := text.Width(max(width-indent-2, 10)).Render(b.Text)
+			out = lipgloss.JoinHorizontal(lipgloss.Top, bullet, body)
+		case richtext.Code:
+			out = label.Render(truncate(strings.ReplaceAll(b.Text, "\t", "    "), width))
+		case richtext.Table:
+			out = text.Render(truncate(b.Text, width))
+		default:
+			out = text.Width(width).Render(b.Text)
+		}
+		lines = append(lines, strings.Split(out, "\n")...)
+	}
+	return lines
+}
+
+// truncate cuts s to width columns, ending it with … when cut.
+func truncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if lipgloss.Width(b.String()+string(r)) > width-1 {
+			break
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + "…"
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/ui/tui/theme"
+)
+
+// WithDesktop lets Detail open the job's URL in the browser with open (o)
+// and copy its URL (y) or email (Y) to the clipboard with clip.
+func WithDesktop(open, clip func(string) error) Option {
+	return func(m *Model) { m.openURL, m.clip = open, clip }
+}
+
+// desktopMsg reports an action on the job open in Detail: done says what
+// happened, or err why it did not.
+type desktopMsg struct {
+	jobID string
+	done  string
+	err   error
+}
+
+// detailAction runs an action on the job open in Detail, o, y or Y.
+func (m Model) detailAction(key string) (Model, tea.Cmd) {
+	j := m.jobs[m.selectedIndex]
+	var do func(string) error
+	var arg, done, fail string
+	switch key {
+	case "o":
+		do, arg, done, fail = m.openURL, j.URL, "opened in browser", "could not open browser: "
+	case "y":
+		do, arg, done, fail = m.clip, j.URL, "copied URL", "could not copy: "
+	case "Y":
+		if j.Email == "" {
+			m.detailDone, m.detailErr = "", nil
+			return m, nil
+		}
+		do, arg, done, fail = m.clip, j.Email, "copied "+j.Email, "could not copy: "
+	}
+	if do == nil {
+		return m, nil
+	}
+	id := j.ID
+	return m, func() tea.Msg {
+		if err := do(arg); err != nil {
+			return desktopMsg{jobID: id, err: fmt.Errorf("%s%w", fail, err)}
+		}
+		return desktopMsg{jobID: id, done: done}
+	}
+}
+
+// detailNotice is the status bar's word on the last action in Detail.
+func (m Model) detailNotice() string {
+	switch {
+	case m.viewState != Detail:
+		return ""
+	case m.detailErr != nil:
+		return theme.ErrorStyle.Render(m.detailErr.Error())
+	case m.detailDone != "":
+		return theme.SuccessStyle.Render(m.detailDone)
+	}
+	return ""
+}
+
+// detailHints are the status bar's keys in Detail; copying the email is
+// off for a job without one, and x shows only for a cut description.
+func (m Model) detailHints() (keys, labels []string, off map[string]bool) {
+	keys = []string{"o", "y", "Y", "t", "r", "esc"}
+	labels = []string{"open", "copy URL", "copy email", "status", "raw", "back"}
+	if m.canLoadFull() {
+		keys = append(keys[:len(keys)-1], "x", "esc")
+		labels = append(labels[:len(labels)-1], "full text", "back")
+	}
+	if len(m.jobs) > 0 && m.jobs[m.selectedIndex].Email == "" {
+		off = map[string]bool{"Y": true}
+	}
+	return keys, labels, off
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestModel_DetailOpensAndCopies(t *testing.T) {
+	jobs := fixtureJobs()
+	jobs[0].URL, jobs[0].Email = "https://acme.example/jobs/1", "jobs@acme.com"
+	var opened, copied []string
+	open := func(url string) error { opened = append(opened, url); return nil }
+	clip := func(text string) error { copied = append(copied, text); return nil }
+	m := drive(NewModel(WithJobSource(fixtureSource(jobs)), WithDesktop(open, clip), WithOutbox(&fakeOutbox{})))
+
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if view := plain(m); !strings.Contains(view, "o open") || !strings.Contains(view, "Y copy email") {
+		t.Errorf("detail keys not in the status bar:\n%s", view)
+	}
+	m = run(m, key("o"))
+	if m.(Model).viewState != Detail || len(opened) != 1 || opened[0] != jobs[0].URL {
+		t.Fatalf("o opened %v in view %v", opened, m.(Model).viewState)
+	}
+	if view := plain(m); !strings.Contains(view, "opened in browser") {
+		t.Errorf("no confirmation:\n%s", view)
+	}
+	m = run(m, key("y"))
+	m = run(m, key("Y"))
+	if strings.Join(copied, " ") != "https://acme.example/jobs/1 jobs@acme.com" {
+		t.Errorf("copied %v", copied)
+	}
+	if view := plain(m); !strings.Contains(view, "copied jobs@acme.com") {
+		t.Errorf("no confirmation:\n%s", view)
+	}
+
+	// Globex has no email: Y does nothing.
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	m = run(m, key("j"))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = run(m, key("Y"))
+	if len(copied) != 2 {
+		t.Errorf("copied %v for a job without an email", copied)
+	}
+	if view := plain(m); strings.Contains(view, "copied") || !strings.Contains(view, "Email none") {
+		t.Errorf("stale notice or no email line:\n%s", view)
+	}
+
+	// Outside Detail, o is still the outbox.
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m = run(m, key("o")); m.(Model).viewState != Outbox {
+		t.Errorf("o from the list went to %v", m.(Model).viewState)
+	}
+}
+
+func TestModel_DetailOpenFails(t *testing.T) {
+	open := func(string) error { return errors.New("xdg-open not found") }
+	m := drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithDesktop(open, nil)))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = run(m, key("o"))
+	if view := plain(m); !strings.Contains(view, "could not open browser: xdg-open not found") {
+		t.Errorf("failure not shown:\n%s", view)
+	}
+}
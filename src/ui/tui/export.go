@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/export"
+	"sprayer/src/api/job"
+	"sprayer/src/ui/tui/theme"
+)
+
+// WithExportDir writes jobs exported with e into dir instead of the
+// exports directory under job.DataDir.
+func WithExportDir(dir string) Option {
+	return func(m *Model) { m.exportDir = dir }
+}
+
+// WithExportFormat is the format enter picks in Export; without it enter
+// picks csv.
+func WithExportFormat(f export.JobFormat) Option {
+	return func(m *Model) { m.exportFormat = f }
+}
+
+// exportKeys are the formats offered in Export, by the key choosing each.
+var exportKeys = []struct {
+	key    string
+	format export.JobFormat
+}{
+	{"c", export.JobsCSV},
+	{"m", export.JobsMarkdown},
+	{"j", export.JobsJSON},
+}
+
+// exportedMsg reports writing the export file.
+type exportedMsg struct {
+	path string
+	n    int
+	err  error
+}
+
+// updateExport handles a key while Export asks for a format; enter picks
+// the default one. Every other key leaves the prompt.
+func (m Model) updateExport(msg tea.KeyMsg) (Model, tea.Cmd) {
+	m.viewState = JobList
+	if msg.Type == tea.KeyEnter {
+		return m, m.exportJobs(m.defaultExportFormat())
+	}
+	for _, k := range exportKeys {
+		if msg.String() == k.key {
+			return m, m.exportJobs(k.format)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) defaultExportFormat() export.JobFormat {
+	if m.exportFormat == "" {
+		return export.JobsCSV
+	}
+	return m.exportFormat
+}
+
+// exportJobs writes the listed jobs in format f to a new file named for
+// the time, with the default columns.
+func (m Model) exportJobs(f export.JobFormat) tea.Cmd {
+	dir := m.exportDir
+	if dir == "" {
+		dir = filepath.Join(job.DataDir(), "exports")
+	}
+	jobs := m.jobs
+	path := filepath.Join(dir, "jobs-"+m.clock().Format("20060102-150405")+"."+f.Ext())
+	return func() tea.Msg {
+		return exportedMsg{path: path, n: len(jobs), err: writeExport(path, jobs, f)}
+	}
+}
+
+func writeExport(path string, jobs []job.Job, f export.JobFormat) error {
+	cols, err := export.ParseJobColumns("")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := export.WriteJobs(out, jobs, f, cols); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// exportNotice is the status bar's note on the last export.
+func (m Model) exportNotice() string {
+	if m.exported.err != nil {
+		return theme.ErrorStyle.Render("export failed: " + m.exported.err.Error())
+	}
+	if m.exported.path == "" {
+		return ""
+	}
+	return theme.SuccessStyle.Render(fmt.Sprintf("exported %s to %s", plural(m.exported.n, "job", "jobs"), filepath.Base(m.exported.path)))
+}
+
+// renderExport asks which format to export the listed jobs in.
+func (m Model) renderExport() string {
+	bg := lipgloss.NewStyle().Background(theme.Background)
+	label := bg.Foreground(theme.Subtle)
+	line := bg.Foreground(theme.Text).Render("Export " + plural(len(m.jobs), "job", "jobs") + " as ")
+	for i, k := range exportKeys {
+		if i > 0 {
+			line += theme.SepStyle.Render(" │ ")
+		}
+		line += theme.KbdStyle.Render(k.key) + label.Render(" "+string(k.format))
+	}
+	line += theme.SepStyle.Render(" │ ") + theme.KbdStyle.Render("enter") + label.Render(" "+string(m.defaultExportFormat()))
+	line += theme.SepStyle.Render(" │ ") + label.Render("esc cancel")
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Center, lipgloss.Center, line,
+		lipgloss.WithWhitespaceBackground(theme.Background))
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/export"
+)
+
+func TestModel_Export(t *testing.T) {
+	dir := t.TempDir()
+	var m tea.Model = drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithExportDir(dir)))
+
+	m = run(m, key("e"))
+	view := strings.Join(strings.Fields(ansiRe.ReplaceAllString(m.View(), "")), " ")
+	if !strings.Contains(view, "Export 3 jobs as c csv │ m markdown │ j json │ enter csv │ esc cancel") {
+		t.Fatalf("no format prompt:\n%s", view)
+	}
+	m = run(m, key("c"))
+	if m.(Model).viewState != JobList {
+		t.Errorf("export left view %v", m.(Model).viewState)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "jobs-*.csv"))
+	if len(files) != 1 {
+		t.Fatalf("exported files = %v", files)
+	}
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil || len(rows) != 4 || rows[0][0] != "Title" || rows[2][0] != fixtureJobs()[1].Title {
+		t.Errorf("csv = %q, %v", rows, err)
+	}
+	if view := ansiRe.ReplaceAllString(m.View(), ""); !strings.Contains(view, "exported 3 jobs to "+filepath.Base(files[0])) {
+		t.Errorf("export not reported:\n%s", view)
+	}
+
+	m = run(m, key("e"))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.(Model).viewState != JobList {
+		t.Errorf("esc left view %v", m.(Model).viewState)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 1 {
+		t.Errorf("cancelled export wrote %v", files)
+	}
+}
+
+func TestModel_ExportDefaultFormat(t *testing.T) {
+	dir := t.TempDir()
+	var m tea.Model = drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithExportDir(dir), WithExportFormat(export.JobsMarkdown)))
+
+	m = run(m, key("e"))
+	if view := strings.Join(strings.Fields(ansiRe.ReplaceAllString(m.View(), "")), " "); !strings.Contains(view, "enter markdown") {
+		t.Fatalf("default format not offered:\n%s", view)
+	}
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if files, _ := filepath.Glob(filepath.Join(dir, "jobs-*.md")); len(files) != 1 {
+		t.Errorf("enter exported %v, want one markdown file", files)
+	}
+}
@@ -0,0 +1,272 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/profile"
+	"sprayer/src/ui/tui/theme"
+)
+
+// FilterPresets keeps the named filter sets the Filter view picks from;
+// *profile.Store satisfies it.
+type FilterPresets interface {
+	Presets() ([]profile.FilterPreset, error)
+	SavePreset(profile.FilterPreset) error
+	DeletePreset(name string) error
+}
+
+// WithFilterPresets lets the Filter view, opened with f, narrow the list
+// to a preset from presets, or to keywords typed there, and save what it
+// applied as a preset. match returns the IDs of the profile's jobs that
+// pass its filters with the preset's on top; the profile is not changed.
+func WithFilterPresets(presets FilterPresets, match func(profile.FilterPreset) ([]string, error)) Option {
+	return func(m *Model) { m.presets, m.matchPreset = presets, match }
+}
+
+// filterView is the state of the Filter view. Row 0 is no preset, the
+// list as loaded; the presets follow.
+type filterView struct {
+	presets  []profile.FilterPreset
+	selected int
+	editing  string // "keywords" or "name" while one is typed, else ""
+	input    string
+	busy     bool
+	err      error
+}
+
+// presetsLoadedMsg delivers the saved presets; presetMatchedMsg the jobs
+// passing a preset being applied; presetSavedMsg the result of a save or
+// delete, after which the presets are loaded again.
+type (
+	presetsLoadedMsg struct {
+		presets []profile.FilterPreset
+		err     error
+	}
+	presetMatchedMsg struct {
+		preset profile.FilterPreset
+		ids    []string
+		err    error
+	}
+	presetSavedMsg struct {
+		saved *profile.FilterPreset // the preset saved, nil for a delete
+		err   error
+	}
+)
+
+// openFilter shows the Filter view and loads the presets.
+func (m Model) openFilter() (Model, tea.Cmd) {
+	if m.presets == nil {
+		return m, nil
+	}
+	m.viewState, m.filterView = Filter, filterView{busy: true}
+	return m, m.loadPresets()
+}
+
+func (m Model) loadPresets() tea.Cmd {
+	presets := m.presets
+	return func() tea.Msg {
+		all, err := presets.Presets()
+		return presetsLoadedMsg{presets: all, err: err}
+	}
+}
+
+// updateFilter handles a key in the Filter view.
+func (m Model) updateFilter(msg tea.KeyMsg) (Model, tea.Cmd) {
+	v := &m.filterView
+	if v.editing != "" {
+		return m.updateFilterInput(msg)
+	}
+	switch msg.String() {
+	case "esc":
+		m.viewState = JobList
+	case "ctrl+c", "q":
+		return m.quit()
+	case "j", "down":
+		v.selected = min(v.selected+1, len(v.presets))
+	case "k", "up":
+		v.selected = max(v.selected-1, 0)
+	case "/":
+		v.editing, v.input, v.err = "keywords", "", nil
+		if m.preset != nil {
+			v.input = strings.Join(m.preset.Keywords, ", ")
+		}
+	case "n":
+		if m.preset != nil {
+			v.editing, v.input, v.err = "name", m.preset.Name, nil
+		}
+	case "enter":
+		if v.busy {
+			return m, nil
+		}
+		if v.selected == 0 {
+			m.preset, m.presetIDs, m.viewState = nil, nil, JobList
+			return m.refilter(), nil
+		}
+		return m.applyPreset(v.presets[v.selected-1])
+	case "d":
+		if v.busy || v.selected == 0 {
+			return m, nil
+		}
+		presets, name := m.presets, v.presets[v.selected-1].Name
+		v.busy, v.err = true, nil
+		return m, func() tea.Msg { return presetSavedMsg{err: presets.DeletePreset(name)} }
+	}
+	return m, nil
+}
+
+// updateFilterInput handles a key while keywords or a preset's name are
+// typed: enter applies the keywords or saves under the name, esc gives
+// up.
+func (m Model) updateFilterInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	v := &m.filterView
+	switch msg.Type {
+	case tea.KeyEsc:
+		v.editing = ""
+	case tea.KeyBackspace:
+		if r := []rune(v.input); len(r) > 0 {
+			v.input = string(r[:len(r)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		v.input += string(msg.Runes)
+	case tea.KeyEnter:
+		editing := v.editing
+		v.editing = ""
+		var p profile.FilterPreset
+		if m.preset != nil {
+			p = *m.preset
+		}
+		if editing == "name" {
+			p.Name = strings.TrimSpace(v.input)
+			presets := m.presets
+			v.busy, v.err = true, nil
+			return m, func() tea.Msg { return presetSavedMsg{saved: &p, err: presets.SavePreset(p)} }
+		}
+		p.Name, p.Keywords = "", splitKeywords(v.input)
+		if p.Summary() == "no filters" {
+			m.preset, m.presetIDs, m.viewState = nil, nil, JobList
+			return m.refilter(), nil
+		}
+		return m.applyPreset(p)
+	}
+	return m, nil
+}
+
+// applyPreset finds the jobs passing p, to list only those.
+func (m Model) applyPreset(p profile.FilterPreset) (Model, tea.Cmd) {
+	if m.matchPreset == nil {
+		return m, nil
+	}
+	m.filterView.busy, m.filterView.err = true, nil
+	match := m.matchPreset
+	return m, func() tea.Msg {
+		ids, err := match(p)
+		return presetMatchedMsg{preset: p, ids: ids, err: err}
+	}
+}
+
+// presetSaved reloads the presets after a save or delete; a preset saved
+// under a name is the one applied.
+func (m Model) presetSaved(msg presetSavedMsg) (Model, tea.Cmd) {
+	m.filterView.busy, m.filterView.err = false, msg.err
+	if msg.err != nil {
+		return m, nil
+	}
+	if msg.saved != nil {
+		m.preset = msg.saved
+	}
+	return m, m.loadPresets()
+}
+
+// presetApplied lists the jobs a preset passed, back in the job list.
+func (m Model) presetApplied(msg presetMatchedMsg) Model {
+	m.filterView.busy, m.filterView.err = false, msg.err
+	if msg.err != nil {
+		return m
+	}
+	m.preset, m.presetIDs = &msg.preset, make(map[string]bool, len(msg.ids))
+	for _, id := range msg.ids {
+		m.presetIDs[id] = true
+	}
+	if m.viewState == Filter {
+		m.viewState = JobList
+	}
+	m.selectedIndex, m.listOffset = 0, 0
+	return m.refilter()
+}
+
+// splitKeywords reads typed keywords, separated by commas.
+func splitKeywords(s string) []string {
+	var out []string
+	for _, k := range strings.Split(s, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// presetName is how the status bar names the preset applied.
+func (m Model) presetName() string {
+	if m.preset == nil {
+		return ""
+	}
+	if m.preset.Name != "" {
+		return m.preset.Name
+	}
+	return m.preset.Summary()
+}
+
+// renderFilter lists the presets with the selected one highlighted and
+// the one applied marked, and the keywords or name being typed.
+func (m Model) renderFilter() string {
+	bg := lipgloss.NewStyle().Background(theme.Background)
+	label := bg.Foreground(theme.Subtle)
+	text := bg.Foreground(theme.Text)
+	v := m.filterView
+
+	lines := []string{bg.Foreground(theme.Bright).Bold(true).Render("Filter presets"), ""}
+	row := func(i int, name, summary string, active bool) {
+		style := theme.JobItemStyle
+		if i == v.selected {
+			style = theme.JobItemSelectedStyle
+		}
+		mark := "  "
+		if active {
+			mark = "● "
+		}
+		lines = append(lines, style.Render(mark+name)+label.Render("  "+summary))
+	}
+	row(0, "No preset", "the profile's jobs as loaded", m.preset == nil)
+	for i, p := range v.presets {
+		row(i+1, p.Name, p.Summary(), m.preset != nil && strings.EqualFold(m.preset.Name, p.Name))
+	}
+	if m.preset != nil && m.preset.Name == "" {
+		lines = append(lines, "", label.Render("Applied: ")+text.Render(m.preset.Summary()))
+	}
+
+	switch v.editing {
+	case "keywords":
+		lines = append(lines, "", label.Render("Keywords: ")+text.Render(v.input+"▏"))
+	case "name":
+		lines = append(lines, "", label.Render("Save as: ")+text.Render(v.input+"▏"))
+	}
+	switch {
+	case v.busy:
+		lines = append(lines, "", label.Render("Working…"))
+	case v.err != nil:
+		lines = append(lines, "", theme.ErrorStyle.Render(v.err.Error()))
+	}
+	hint := "enter apply · / keywords · n save as · d delete · esc back"
+	if v.editing != "" {
+		hint = "enter done · esc cancel"
+	} else if m.preset == nil {
+		hint = "enter apply · / keywords · d delete · esc back"
+	}
+	lines = append(lines, "", label.Render(hint))
+	block := bg.Padding(1, 2).Width(m.width).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Left, lipgloss.Top, block,
+		lipgloss.WithWhitespaceBackground(theme.Background))
+}
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/profile"
+)
+
+// fakePresets keeps presets in memory, by name.
+type fakePresets struct{ all []profile.FilterPreset }
+
+func (f *fakePresets) Presets() ([]profile.FilterPreset, error) { return slices.Clone(f.all), nil }
+
+func (f *fakePresets) SavePreset(p profile.FilterPreset) error {
+	if p.Name == "" {
+		return profile.ErrNoPresetName
+	}
+	f.all = append(f.all, p)
+	return nil
+}
+
+func (f *fakePresets) DeletePreset(name string) error {
+	f.all = slices.DeleteFunc(f.all, func(p profile.FilterPreset) bool { return p.Name == name })
+	return nil
+}
+
+// matchTitles passes the fixture jobs whose title has one of a preset's
+// keywords, and says which presets it was asked about.
+func matchTitles(asked *[]profile.FilterPreset) func(profile.FilterPreset) ([]string, error) {
+	return func(p profile.FilterPreset) ([]string, error) {
+		*asked = append(*asked, p)
+		var ids []string
+		for _, j := range fixtureJobs() {
+			if slices.ContainsFunc(p.Keywords, func(k string) bool { return strings.Contains(strings.ToLower(j.Title), k) }) {
+				ids = append(ids, j.ID)
+			}
+		}
+		return ids, nil
+	}
+}
+
+func TestModel_FilterPresets(t *testing.T) {
+	store := &fakePresets{all: []profile.FilterPreset{{Name: "go", Keywords: []string{"go"}}}}
+	var asked []profile.FilterPreset
+	m := drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithFilterPresets(store, matchTitles(&asked))))
+
+	m = run(m, key("f"))
+	view := plain(m)
+	if m.(Model).viewState != Filter || !strings.Contains(view, "No preset") || !strings.Contains(view, "go keywords go") {
+		t.Fatalf("presets not listed:\n%s", view)
+	}
+
+	m = run(m, key("j"))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.(Model).viewState != JobList || ids(m) != "1" {
+		t.Fatalf("preset go lists %q in %v, want Acme's Go job", ids(m), m.(Model).viewState)
+	}
+	if view := plain(m); !strings.Contains(view, "preset: go") || !strings.Contains(view, "Jobs: 1/3") {
+		t.Errorf("preset not shown:\n%s", view)
+	}
+
+	// Keywords typed replace the preset's and can be saved as a new one.
+	m = run(m, key("f"))
+	m = run(m, key("/"))
+	for _, k := range []tea.KeyMsg{{Type: tea.KeyBackspace}, {Type: tea.KeyBackspace}, key("backend, platform")} {
+		m = run(m, k)
+	}
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if ids(m) != "2 3" {
+		t.Fatalf("typed keywords list %q", ids(m))
+	}
+	m = run(m, key("f"))
+	m = run(m, key("n"))
+	m = run(m, key("infra"))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if len(store.all) != 2 || store.all[1].Name != "infra" || strings.Join(store.all[1].Keywords, ",") != "backend,platform" {
+		t.Fatalf("saved %+v", store.all)
+	}
+	if view := plain(m); !strings.Contains(view, "● infra") {
+		t.Errorf("saved preset not marked applied:\n%s", view)
+	}
+
+	// No preset returns to the list as loaded.
+	m = run(m, key("k"))
+	m = run(m, key("k"))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if ids(m) != "1 2 3" || strings.Contains(plain(m), "preset:") {
+		t.Errorf("clearing lists %q:\n%s", ids(m), plain(m))
+	}
+
+	m = run(m, key("f"))
+	m = run(m, key("j"))
+	m = run(m, key("d"))
+	if len(store.all) != 1 || store.all[0].Name != "infra" {
+		t.Errorf("d left %+v", store.all)
+	}
+}
+
+func TestModel_FilterPresetFails(t *testing.T) {
+	fail := func(profile.FilterPreset) ([]string, error) { return nil, errors.New("database is locked") }
+	store := &fakePresets{all: []profile.FilterPreset{{Name: "go", Keywords: []string{"go"}}}}
+	m := drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithFilterPresets(store, fail)))
+	m = run(m, key("f"))
+	m = run(m, key("j"))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if view := plain(m); m.(Model).viewState != Filter || !strings.Contains(view, "database is locked") {
+		t.Errorf("failure not shown in Filter:\n%s", view)
+	}
+	if ids(m) != "1 2 3" {
+		t.Errorf("a failed preset narrowed the list to %q", ids(m))
+	}
+}
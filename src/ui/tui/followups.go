@@ -0,0 +1,256 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/job"
+	"sprayer/src/ui/tui/theme"
+)
+
+// followUpPrompt is the LLM prompt a follow-up email is written from.
+const followUpPrompt = "email_followup"
+
+// Composer writes an email about j from the named LLM prompt, returning
+// its subject and body; apply.GenerateEmail with the active profile
+// satisfies it.
+type Composer func(j job.Job, prompt string) (subject, body string, err error)
+
+// WithComposer writes the email Compose opens on when a follow-up is
+// chosen in Reminders.
+func WithComposer(c Composer) Option {
+	return func(m *Model) { m.composer = c }
+}
+
+// StreamComposer is a Composer sending the body on chunks as it is
+// written; apply.StreamEmail satisfies it. Cancelling ctx abandons the
+// email. It does not close chunks.
+type StreamComposer func(ctx context.Context, j job.Job, prompt string, chunks chan<- string) (subject, body string, err error)
+
+// WithStreamComposer has Compose show the follow-up as it is written,
+// in place of the Composer's whole answer.
+func WithStreamComposer(c StreamComposer) Option {
+	return func(m *Model) { m.streamer = c }
+}
+
+// WithLLMStatus marks drafts written while status reports the LLM
+// unavailable as template drafts, to be edited before they go out; the
+// composer fills in a built-in template then.
+func WithLLMStatus(status func() error) Option {
+	return func(m *Model) { m.llmStatus = status }
+}
+
+// templateNote says why drafts are filled in from templates, or "" when
+// the LLM writes them.
+func templateNote(status func() error) string {
+	if status == nil {
+		return ""
+	}
+	if err := status(); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// renderTemplateNote warns that a draft came from a template.
+func renderTemplateNote(note string, label lipgloss.Style) []string {
+	return []string{theme.WarningStyle.Render("Template draft — edit before sending"), label.Render(note), ""}
+}
+
+// draft is the email open in Compose.
+type draft struct {
+	job           job.Job
+	subject, body string
+	err           error
+	writing       bool   // the composer has not answered yet
+	template      string // why the draft is a template, if it is
+
+	n      int                // which of the model's drafts this is
+	chunks chan string        // the body as it streams in
+	cancel context.CancelFunc // abandons a streamed draft
+}
+
+// draftMsg delivers the email the composer wrote for a job.
+type draftMsg struct {
+	n             int
+	jobID         string
+	subject, body string
+	template      string
+	err           error
+}
+
+// draftChunkMsg delivers the next piece of a streamed draft's body.
+type draftChunkMsg struct {
+	n    int
+	text string
+}
+
+// followUpsDue lists the loaded jobs due a follow-up, longest overdue
+// first. Applied jobs hidden from the list still count; archived ones
+// do not.
+func (m Model) followUpsDue() []job.Job {
+	return job.FollowUpsDue(job.ExcludeArchived()(m.all), m.clock())
+}
+
+// composeFollowUp opens Compose on the follow-up chosen in Reminders and
+// has the composer write it.
+func (m Model) composeFollowUp() (Model, tea.Cmd) {
+	due := m.followUpsDue()
+	if len(due) == 0 {
+		return m, nil
+	}
+	j := due[min(m.reminder, len(due)-1)]
+	m.viewState = Compose
+	m.drafts++
+	n := m.drafts
+	m.draft = draft{job: j, n: n, writing: m.composer != nil || m.streamer != nil}
+	if m.streamer != nil {
+		return m.streamFollowUp()
+	}
+	if m.composer == nil {
+		m.draft.err = fmt.Errorf("no email composer configured")
+		return m, nil
+	}
+	compose, status := m.composer, m.llmStatus
+	return m, func() tea.Msg {
+		subject, body, err := compose(j, followUpPrompt)
+		return draftMsg{n: n, jobID: j.ID, subject: subject, body: body, template: templateNote(status), err: err}
+	}
+}
+
+// streamFollowUp has the streamer write the draft, its body growing in
+// Compose chunk by chunk until the whole email arrives.
+func (m Model) streamFollowUp() (Model, tea.Cmd) {
+	ctx, cancel := context.WithCancel(m.baseContext())
+	chunks := make(chan string)
+	m.draft.chunks, m.draft.cancel = chunks, cancel
+	stream, status, j, n := m.streamer, m.llmStatus, m.draft.job, m.draft.n
+	return m, tea.Batch(
+		func() tea.Msg {
+			defer close(chunks)
+			subject, body, err := stream(ctx, j, followUpPrompt, chunks)
+			return draftMsg{n: n, jobID: j.ID, subject: subject, body: body, template: templateNote(status), err: err}
+		},
+		nextChunk(n, chunks),
+	)
+}
+
+// nextChunk waits for the next piece of draft n's body.
+func nextChunk(n int, chunks <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		text, ok := <-chunks
+		if !ok {
+			return nil
+		}
+		return draftChunkMsg{n: n, text: text}
+	}
+}
+
+// updateDraft takes in the composer's answers for the open draft.
+func (m Model) updateDraft(msg tea.Msg) (Model, tea.Cmd) {
+	d := &m.draft
+	switch msg := msg.(type) {
+	case draftChunkMsg:
+		if msg.n != d.n {
+			return m, nil
+		}
+		if d.writing {
+			d.body += msg.text
+		}
+		return m, nextChunk(d.n, d.chunks)
+	case draftMsg:
+		if msg.n != d.n || msg.jobID != d.job.ID {
+			return m, nil
+		}
+		d.subject, d.body, d.template, d.err = msg.subject, msg.body, msg.template, msg.err
+		d.writing = false
+		if d.cancel != nil {
+			d.cancel()
+		}
+	}
+	return m, nil
+}
+
+// closeDraft leaves Compose, abandoning a draft still being written.
+func (m Model) closeDraft() Model {
+	if m.draft.writing && m.draft.cancel != nil {
+		m.draft.cancel()
+	}
+	m.viewState = Reminders
+	return m
+}
+
+// renderReminders lists the jobs due a follow-up with the selected one
+// highlighted.
+func (m Model) renderReminders() string {
+	bg := lipgloss.NewStyle().Background(theme.Background)
+	label := bg.Foreground(theme.Subtle)
+	due := m.followUpsDue()
+
+	lines := []string{bg.Foreground(theme.Bright).Bold(true).Render("Follow-ups due"), ""}
+	if len(due) == 0 {
+		lines = append(lines, label.Render("No follow-ups due."))
+	}
+	now := m.clock()
+	for i, j := range due {
+		when := "due " + j.FollowUpAt.Local().Format("2006-01-02")
+		if late := int(now.Sub(*j.FollowUpAt).Hours() / 24); late > 0 {
+			when += fmt.Sprintf(" (%dd overdue)", late)
+		}
+		style := theme.JobItemStyle
+		if i == m.reminder {
+			style = theme.JobItemSelectedStyle
+		}
+		lines = append(lines, style.Render(j.Title+" @ "+j.Company)+label.Render("  "+when))
+	}
+	if len(due) > 0 {
+		lines = append(lines, "", label.Render("enter write follow-up · esc back"))
+	}
+	block := bg.Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Left, lipgloss.Top, block,
+		lipgloss.WithWhitespaceBackground(theme.Background))
+}
+
+// renderCompose shows the draft being written.
+func (m Model) renderCompose() string {
+	bg := lipgloss.NewStyle().Background(theme.Background)
+	label := bg.Foreground(theme.Subtle)
+	text := bg.Foreground(theme.Text)
+	d := m.draft
+
+	lines := []string{
+		label.Render("To       ") + text.Render(d.job.Email),
+		label.Render("Subject  ") + text.Render(d.subject),
+		"",
+	}
+	switch {
+	case d.writing && d.body != "":
+		for _, l := range strings.Split(d.body, "\n") {
+			lines = append(lines, text.Render(l))
+		}
+		lines[len(lines)-1] += label.Render("▌")
+	case d.writing:
+		lines = append(lines, label.Render("Writing the follow-up to "+d.job.Company+"…"))
+	case d.err != nil:
+		lines = append(lines, theme.ErrorStyle.Render("could not write the follow-up: "+d.err.Error()))
+	default:
+		if d.template != "" {
+			lines = append(lines, renderTemplateNote(d.template, label)...)
+		}
+		for _, l := range strings.Split(strings.TrimRight(d.body, "\n"), "\n") {
+			lines = append(lines, text.Render(l))
+		}
+	}
+	hint := "esc back"
+	if d.writing {
+		hint = "esc cancel"
+	}
+	lines = append(lines, "", label.Render(hint))
+	block := bg.Padding(1, 2).Width(m.width).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Left, lipgloss.Top, block,
+		lipgloss.WithWhitespaceBackground(theme.Background))
+}
@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/job"
+)
+
+func TestModel_Reminders(t *testing.T) {
+	now := time.Now()
+	due := func(days int) *time.Time { at := now.AddDate(0, 0, -days); return &at }
+	jobs := fixtureJobs()
+	jobs[1].Applied, jobs[1].FollowUpAt = true, due(1)
+	jobs[2].Applied, jobs[2].FollowUpAt = true, due(4)
+	jobs[2].Email = "jobs@initech.com"
+
+	var asked []string
+	composer := func(j job.Job, prompt string) (string, string, error) {
+		asked = append(asked, j.ID+" "+prompt)
+		return "Following up: " + j.Title, "Hi " + j.Company + ",\nany news?\n", nil
+	}
+	var m tea.Model = drive(NewModel(WithJobSource(fixtureSource(jobs)), WithComposer(composer)))
+
+	if view := ansiRe.ReplaceAllString(m.View(), ""); !strings.Contains(view, "2 follow-ups due") {
+		t.Errorf("status bar does not count follow-ups:\n%s", view)
+	}
+
+	m = run(m, key("u"))
+	view := ansiRe.ReplaceAllString(m.View(), "")
+	if m.(Model).viewState != Reminders || strings.Index(view, "Backend Developer") > strings.Index(view, "Platform Engineer") {
+		t.Fatalf("reminders not listed longest overdue first:\n%s", view)
+	}
+
+	m = run(m, key("j"))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.(Model).viewState != Compose || len(asked) != 1 || asked[0] != "2 email_followup" {
+		t.Fatalf("composer asked %v in view %v", asked, m.(Model).viewState)
+	}
+	view = ansiRe.ReplaceAllString(m.View(), "")
+	for _, want := range []string{"Following up: Platform Engineer", "Hi Globex,", "any news?"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("compose view lacks %q:\n%s", want, view)
+		}
+	}
+
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.(Model).viewState != Reminders {
+		t.Errorf("esc from compose went to %v", m.(Model).viewState)
+	}
+}
+
+func TestModel_ComposeTemplateNote(t *testing.T) {
+	jobs := fixtureJobs()
+	due := time.Now().AddDate(0, 0, -1)
+	jobs[0].Applied, jobs[0].FollowUpAt = true, &due
+	composer := func(j job.Job, prompt string) (string, string, error) {
+		return "Following up", "Hi " + j.Company, nil
+	}
+	llmErr := errors.New("LLM ollama (llama3.1): model not pulled")
+	status := func() error { return llmErr }
+	var m tea.Model = drive(NewModel(WithJobSource(fixtureSource(jobs)), WithComposer(composer), WithLLMStatus(status)))
+	m = run(m, key("u"))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	view := ansiRe.ReplaceAllString(m.View(), "")
+	if !strings.Contains(view, "Template draft") || !strings.Contains(view, "model not pulled") {
+		t.Errorf("compose does not mark the template draft:\n%s", view)
+	}
+
+	llmErr = nil
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if view := ansiRe.ReplaceAllString(m.View(), ""); strings.Contains(view, "Template draft") {
+		t.Errorf("LLM draft marked as a template:\n%s", view)
+	}
+}
+
+func TestModel_ComposeStreams(t *testing.T) {
+	jobs := fixtureJobs()
+	due := time.Now().AddDate(0, 0, -1)
+	jobs[0].Applied, jobs[0].FollowUpAt = true, &due
+
+	pieces := make(chan string)
+	cancelled := make(chan struct{})
+	streamer := func(ctx context.Context, j job.Job, prompt string, chunks chan<- string) (string, string, error) {
+		var body string
+		for p := range pieces {
+			chunks <- p
+			body += p
+		}
+		if j.ID == "1" && body == "" {
+			<-ctx.Done()
+			close(cancelled)
+			return "", "", ctx.Err()
+		}
+		return "Following up", strings.TrimSpace(body), nil
+	}
+	var m tea.Model = drive(NewModel(WithJobSource(fixtureSource(jobs)), WithStreamComposer(streamer)))
+	m = run(m, key("u"))
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	batch := cmd().(tea.BatchMsg)
+	final := make(chan tea.Msg)
+	go func() { final <- batch[0]() }()
+	listen := batch[1]
+
+	for _, p := range []string{"Hi Acme,\n", "any news?\n"} {
+		pieces <- p
+		m, listen = m.Update(listen())
+	}
+	view := ansiRe.ReplaceAllString(m.View(), "")
+	if !strings.Contains(view, "Hi Acme,") || !strings.Contains(view, "any news?") || !strings.Contains(view, "esc cancel") {
+		t.Errorf("compose does not show the draft as it streams:\n%s", view)
+	}
+	close(pieces)
+	if msg := listen(); msg != nil {
+		t.Errorf("listening after the stream ended got %#v", msg)
+	}
+	m, _ = m.Update(<-final)
+	if d := m.(Model).draft; d.writing || d.body != "Hi Acme,\nany news?" || d.subject != "Following up" {
+		t.Errorf("finished draft = %+v", d)
+	}
+
+	// Esc while writing abandons the request.
+	pieces = make(chan string)
+	close(pieces)
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	m, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	batch = cmd().(tea.BatchMsg)
+	go func() { final <- batch[0]() }()
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("esc did not cancel the streamed draft")
+	}
+	m, _ = m.Update(<-final)
+	if m.(Model).viewState != Reminders {
+		t.Errorf("esc from a streaming compose went to %v", m.(Model).viewState)
+	}
+}
+
+func TestModel_ComposeStreamEndsWithContext(t *testing.T) {
+	jobs := fixtureJobs()
+	due := time.Now().AddDate(0, 0, -1)
+	jobs[0].Applied, jobs[0].FollowUpAt = true, &due
+
+	streamer := func(ctx context.Context, j job.Job, prompt string, chunks chan<- string) (string, string, error) {
+		<-ctx.Done()
+		return "", "", ctx.Err()
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	var m tea.Model = drive(NewModel(WithContext(ctx), WithJobSource(fixtureSource(jobs)), WithStreamComposer(streamer)))
+	m = run(m, key("u"))
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	batch := cmd().(tea.BatchMsg)
+	final := make(chan tea.Msg)
+	go func() { final <- batch[0]() }()
+
+	// As the program quitting with the draft half written.
+	cancel()
+	select {
+	case msg := <-final:
+		if d, ok := msg.(draftMsg); !ok || !errors.Is(d.err, context.Canceled) {
+			t.Errorf("draft after cancelling = %#v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelling the program's context did not end the streamed draft")
+	}
+}
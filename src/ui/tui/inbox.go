@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/inbox"
+	"sprayer/src/api/power"
+	"sprayer/src/ui/tui/theme"
+)
+
+// InboxSource polls the mailboxes replies arrive in. Poll returns the
+// messages not seen before; Messages lists every one kept, and
+// MarkAllRead clears the unread count once they have been looked at.
+type InboxSource interface {
+	Poll() ([]inbox.Message, error)
+	Messages() ([]inbox.Message, error)
+	Unread() (int, error)
+	MarkAllRead() error
+}
+
+// WithInbox polls src while the program runs, announcing replies in the
+// status bar, and lists its messages in the Inbox view, opened with i.
+func WithInbox(src InboxSource) Option {
+	return func(m *Model) { m.inbox = src }
+}
+
+// inboxView is the state of the Inbox view.
+type inboxView struct {
+	msgs     []inbox.Message
+	selected int
+	loading  bool
+	err      error
+}
+
+// inboxPolledMsg delivers a poll's new messages and the unread count
+// after it; inboxTickMsg starts the next poll; inboxLoadedMsg delivers the
+// messages the Inbox view lists.
+type (
+	inboxPolledMsg struct {
+		msgs   []inbox.Message
+		unread int
+		err    error
+	}
+	inboxTickMsg   struct{}
+	inboxLoadedMsg struct {
+		msgs []inbox.Message
+		err  error
+	}
+)
+
+func (m Model) pollInbox() tea.Cmd {
+	src := m.inbox
+	return func() tea.Msg {
+		msgs, err := src.Poll()
+		unread, uerr := src.Unread()
+		if err == nil {
+			err = uerr
+		}
+		return inboxPolledMsg{msgs: msgs, unread: unread, err: err}
+	}
+}
+
+// pollInboxAllowed polls unless the power state holds background work
+// back, in which case it waits for the next, stretched, tick.
+func (m Model) pollInboxAllowed() tea.Cmd {
+	if m.power.PauseBackground() {
+		return inboxTick(m.power)
+	}
+	return m.pollInbox()
+}
+
+func inboxTick(d power.Decision) tea.Cmd {
+	return tea.Tick(d.Interval(inbox.Tick), func(time.Time) tea.Msg { return inboxTickMsg{} })
+}
+
+// inboxPolled takes in a poll's result and schedules the next one. The
+// latest new reply about a job is announced until the inbox is opened.
+func (m Model) inboxPolled(msg inboxPolledMsg) (Model, tea.Cmd) {
+	m.inboxErr = msg.err
+	m.unreadReplies = msg.unread
+	for _, in := range msg.msgs {
+		if in.JobID != "" {
+			m.replyNotice = "New reply for " + in.Company
+		}
+	}
+	if m.viewState == Inbox && len(msg.msgs) > 0 {
+		return m, tea.Batch(m.loadInbox(), inboxTick(m.power))
+	}
+	return m, inboxTick(m.power)
+}
+
+// openInbox shows the Inbox view, loads it and marks the replies read.
+func (m Model) openInbox() (Model, tea.Cmd) {
+	if m.inbox == nil {
+		return m, nil
+	}
+	m.viewState, m.inboxView = Inbox, inboxView{loading: true}
+	m.replyNotice, m.unreadReplies = "", 0
+	return m, m.loadInbox()
+}
+
+func (m Model) loadInbox() tea.Cmd {
+	src := m.inbox
+	return func() tea.Msg {
+		msgs, err := src.Messages()
+		if err == nil {
+			err = src.MarkAllRead()
+		}
+		return inboxLoadedMsg{msgs: msgs, err: err}
+	}
+}
+
+// updateInbox handles a key in the Inbox view.
+func (m Model) updateInbox(msg tea.KeyMsg) (Model, tea.Cmd) {
+	v := &m.inboxView
+	switch msg.String() {
+	case "esc":
+		m.viewState = JobList
+	case "ctrl+c", "q":
+		return m.quit()
+	case "j", "down":
+		v.selected = max(min(v.selected+1, len(v.msgs)-1), 0)
+	case "k", "up":
+		v.selected = max(v.selected-1, 0)
+	}
+	return m, nil
+}
+
+// renderInbox lists the received messages, newest first, with the job
+// each is about and the start of the selected one's body.
+func (m Model) renderInbox() string {
+	bg := lipgloss.NewStyle().Background(theme.Background)
+	label := bg.Foreground(theme.Subtle)
+	v := m.inboxView
+
+	lines := []string{bg.Foreground(theme.Bright).Bold(true).Render("Inbox"), ""}
+	if len(v.msgs) == 0 && !v.loading {
+		lines = append(lines, label.Render("No messages yet."))
+	}
+	for i, msg := range v.msgs {
+		style := theme.JobItemStyle
+		if i == v.selected {
+			style = theme.JobItemSelectedStyle
+		}
+		about := label.Render("no job")
+		if msg.JobID != "" {
+			about = theme.SuccessStyle.Render(fmt.Sprintf("%s (job %s)", msg.Company, msg.JobID))
+		}
+		lines = append(lines, style.Render(msg.Date.Local().Format("Jan 02 15:04")+"  "+msg.From+"  "+msg.Subject)+"  "+about)
+		if i == v.selected && msg.Body != "" {
+			lines = append(lines, label.Render("  "+firstLine(msg.Body)))
+		}
+	}
+	switch {
+	case v.loading:
+		lines = append(lines, "", label.Render("Loading…"))
+	case v.err != nil:
+		lines = append(lines, "", theme.ErrorStyle.Render(v.err.Error()))
+	}
+	lines = append(lines, "", label.Render("↑/↓ select · esc back"))
+	block := bg.Padding(1, 2).Width(m.width).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Left, lipgloss.Top, block,
+		lipgloss.WithWhitespaceBackground(theme.Background))
+}
+
+// inboxNotice is the status bar's word on the inbox: the latest reply,
+// else why polling failed.
+func (m Model) inboxNotice() string {
+	switch {
+	case m.replyNotice != "":
+		return theme.SuccessStyle.Render(m.replyNotice)
+	case m.inboxErr != nil:
+		return theme.ErrorStyle.Render("inbox: " + m.inboxErr.Error())
+	}
+	return ""
+}
+
+// firstLine is the first non-blank line of body.
+func firstLine(body string) string {
+	for _, l := range strings.Split(body, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			return l
+		}
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/inbox"
+	"sprayer/src/api/power"
+)
+
+// fakeInbox delivers incoming on the next poll and keeps what was
+// delivered.
+type fakeInbox struct {
+	incoming, kept []inbox.Message
+}
+
+func (f *fakeInbox) Poll() ([]inbox.Message, error) {
+	in := f.incoming
+	f.kept, f.incoming = append(in, f.kept...), nil
+	return in, nil
+}
+
+func (f *fakeInbox) Messages() ([]inbox.Message, error) { return f.kept, nil }
+
+func (f *fakeInbox) Unread() (int, error) {
+	n := 0
+	for _, m := range f.kept {
+		if !m.Read && m.JobID != "" {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeInbox) MarkAllRead() error {
+	for i := range f.kept {
+		f.kept[i].Read = true
+	}
+	return nil
+}
+
+func TestModel_Inbox(t *testing.T) {
+	date := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	f := &fakeInbox{incoming: []inbox.Message{
+		{ID: 1, From: "promo@shop.example", Subject: "Sale", Date: date},
+		{ID: 2, From: "jane@acme.com", Subject: "Re: Senior Go Engineer", Body: "\nCould you talk on Tuesday?\n", Date: date, JobID: "1", Company: "Acme"},
+	}}
+	m := drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithInbox(f)))
+
+	if view := plain(m); !strings.Contains(view, "New reply for Acme") {
+		t.Errorf("status bar does not announce the reply:\n%s", view)
+	}
+	if got := m.(Model).status().UnreadReplies; got != 1 {
+		t.Errorf("unread replies = %d, want 1", got)
+	}
+
+	m = run(m, key("i"))
+	m = run(m, key("j"))
+	view := plain(m)
+	for _, want := range []string{"Inbox", "promo@shop.example Sale no job",
+		"jane@acme.com Re: Senior Go Engineer Acme (job 1)", "Could you talk on Tuesday?"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("inbox lacks %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "New reply for") {
+		t.Errorf("notice still shown once the inbox is open:\n%s", view)
+	}
+	if n, _ := f.Unread(); n != 0 {
+		t.Errorf("opening the inbox left %d unread", n)
+	}
+
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.(Model).viewState != JobList {
+		t.Errorf("esc left the view in %v", m.(Model).viewState)
+	}
+}
+
+func TestModel_InboxPollWaitsWhilePowerConstrained(t *testing.T) {
+	f := &fakeInbox{incoming: []inbox.Message{{ID: 1, From: "jane@acme.com", JobID: "1", Company: "Acme"}}}
+	m := NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithInbox(f), WithPower(power.Decision{Metered: true}))
+
+	_, cmd := m.Update(inboxTickMsg{})
+	got := make(chan tea.Msg, 1)
+	go func() { got <- cmd() }()
+	select {
+	case msg := <-got:
+		t.Errorf("polled on a metered connection: %T", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if len(f.incoming) != 1 {
+		t.Error("polled on a metered connection")
+	}
+}
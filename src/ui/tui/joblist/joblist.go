@@ -1,6 +1,8 @@
 package joblist
 
 import (
+	"fmt"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"sprayer/src/api/job"
@@ -12,8 +14,44 @@ type Model struct {
 	SelectedIndex int
 	Width         int
 	Height        int
+	// Jobs closing within Window show a countdown badge; zero means
+	// job.ClosingWindow(). Now defaults to the current time.
+	Window time.Duration
+	Now    time.Time
+	// Marked jobs, by ID, are picked for bulk apply.
+	Marked map[string]bool
+	// Opens counts the tracked opens of each job's emails, by ID.
+	Opens map[string]int
+	// Offset is the first job shown, as ScrollOffset keeps it. Only the
+	// jobs that fit from there are rendered.
+	Offset int
 }
 
+// ScrollOffset returns the first of total jobs to show in rows lines so
+// that selected is in sight, moving from offset as little as it can and
+// leaving no blank rows below the last job.
+func ScrollOffset(offset, selected, rows, total int) int {
+	rows = max(rows, 1)
+	switch {
+	case selected < offset:
+		offset = selected
+	case selected >= offset+rows:
+		offset = selected - rows + 1
+	}
+	return max(min(offset, total-rows), 0)
+}
+
+// Position reads "37/412": the selected job's place in the list.
+func Position(selected, total int) string {
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", selected+1, total)
+}
+
+// Rows is how many jobs fit in the list.
+func (m Model) Rows() int { return m.contentHeight() }
+
 func (m Model) View() string {
 	if len(m.Jobs) == 0 {
 		return m.renderEmptyState()
@@ -87,9 +125,12 @@ func (m Model) emptySubLines() []string {
 
 func (m Model) renderJobList() string {
 	availH := m.contentHeight()
+	start := ScrollOffset(m.Offset, m.SelectedIndex, availH, len(m.Jobs))
+	end := min(start+max(availH, 1), len(m.Jobs))
 
-	var lines []string
-	for i, j := range m.Jobs {
+	lines := make([]string, 0, max(availH, end-start))
+	for i := start; i < end; i++ {
+		j := m.Jobs[i]
 		var line string
 		if i == m.SelectedIndex {
 			line = theme.JobItemSelectedStyle.Width(m.Width).Render(m.formatJobItem(j))
@@ -113,14 +154,55 @@ func (m Model) formatJobItem(j job.Job) string {
 	if j.HasTraps {
 		traps = trapStr
 	}
+	deadline := ""
+	if badge := job.DeadlineBadge(j, m.now(), m.window()); badge != "" {
+		deadline = theme.JobDeadlineStyle.Render(" " + badge)
+	}
+	status := ""
+	if badge := j.Status.Badge(); badge != "" {
+		status = " " + theme.JobStatusStyle.Render("["+badge+"]")
+	}
+	lang := ""
+	if j.Language != "" && j.Language != "en" {
+		lang = theme.JobSourceStyle.Render(" [" + j.Language + "]")
+	}
+	opens := ""
+	if n := m.Opens[j.ID]; n > 0 {
+		opens = theme.JobOpensStyle.Render(fmt.Sprintf(" 👁 %d", n))
+	}
+	ref := ""
+	if j.ShortID > 0 {
+		ref = theme.JobSourceStyle.Render(j.Ref()) + " "
+	}
+	if m.Marked[j.ID] {
+		ref = theme.JobMarkStyle.Render("+ ") + ref
+	}
 
-	availW := m.Width - lipgloss.Width(scoreStr) - lipgloss.Width(companyStr) -
-		lipgloss.Width(sourceStr) - lipgloss.Width(traps) - 4
+	availW := m.Width - lipgloss.Width(ref) - lipgloss.Width(scoreStr) - lipgloss.Width(status) - lipgloss.Width(companyStr) -
+		lipgloss.Width(sourceStr) - lipgloss.Width(traps) - lipgloss.Width(deadline) - lipgloss.Width(lang) - lipgloss.Width(opens) - 4
 	title := j.Title
-	if len(title) > availW && availW > 3 {
-		title = title[:availW-3] + "..."
+	if lipgloss.Width(title) > availW && availW > 3 {
+		runes := []rune(title)
+		for lipgloss.Width(string(runes))+3 > availW {
+			runes = runes[:len(runes)-1]
+		}
+		title = string(runes) + "..."
 	}
 	titleStr := theme.JobItemStyle.Render(title)
 
-	return scoreStr + " " + titleStr + " " + companyStr + " " + sourceStr + traps
+	return ref + scoreStr + status + " " + titleStr + " " + companyStr + " " + sourceStr + lang + traps + deadline + opens
+}
+
+func (m Model) now() time.Time {
+	if m.Now.IsZero() {
+		return time.Now()
+	}
+	return m.Now
+}
+
+func (m Model) window() time.Duration {
+	if m.Window == 0 {
+		return job.ClosingWindow()
+	}
+	return m.Window
 }
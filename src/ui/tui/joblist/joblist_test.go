@@ -0,0 +1,89 @@
+package joblist
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/job"
+)
+
+// syntheticJobs returns n jobs with descriptions as long as real ones.
+func syntheticJobs(n int) []job.Job {
+	desc := strings.Repeat("We are looking for a Go engineer to build our platform. ", 60)
+	jobs := make([]job.Job, n)
+	for i := range jobs {
+		jobs[i] = job.Job{
+			ID:          fmt.Sprintf("job-%d", i),
+			ShortID:     int64(i + 1),
+			Title:       fmt.Sprintf("Senior Go Engineer %d", i),
+			Company:     "Acme",
+			Source:      "hn",
+			Score:       i % 100,
+			Description: desc,
+		}
+	}
+	return jobs
+}
+
+func TestScrollOffset(t *testing.T) {
+	tests := []struct {
+		name                          string
+		offset, selected, rows, total int
+		want                          int
+	}{
+		{"top", 0, 0, 10, 100, 0},
+		{"last visible row", 0, 9, 10, 100, 0},
+		{"one past the bottom", 0, 10, 10, 100, 1},
+		{"page down", 0, 30, 10, 100, 21},
+		{"above the window", 5, 3, 10, 100, 3},
+		{"inside the window", 5, 14, 10, 100, 5},
+		{"end", 0, 99, 10, 100, 90},
+		{"list shrank", 90, 29, 10, 30, 20},
+		{"fewer jobs than rows", 3, 4, 10, 5, 0},
+		{"one row", 0, 4, 1, 100, 4},
+		{"no room", 0, 4, 0, 100, 4},
+		{"tall window", 0, 57, 40, 100, 18},
+		{"empty", 3, 0, 10, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScrollOffset(tt.offset, tt.selected, tt.rows, tt.total); got != tt.want {
+				t.Errorf("ScrollOffset(%d, %d, %d, %d) = %d, want %d", tt.offset, tt.selected, tt.rows, tt.total, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPosition(t *testing.T) {
+	if got := Position(36, 412); got != "37/412" {
+		t.Errorf("Position = %q", got)
+	}
+	if got := Position(0, 0); got != "" {
+		t.Errorf("Position of nothing = %q", got)
+	}
+}
+
+func TestView_RendersOnlyVisibleJobs(t *testing.T) {
+	m := Model{Jobs: syntheticJobs(100), Width: 80, Height: 12, Now: time.Now()}
+	m.SelectedIndex = 50
+	m.Offset = ScrollOffset(0, m.SelectedIndex, m.Rows(), len(m.Jobs))
+	view := m.View()
+	if lines := strings.Count(view, "\n") + 1; lines != m.Rows() {
+		t.Errorf("rendered %d lines, want %d", lines, m.Rows())
+	}
+	if !strings.Contains(view, "Engineer 50") || strings.Contains(view, "Engineer 40 ") || strings.Contains(view, "Engineer 51") {
+		t.Errorf("want jobs 41 to 50 ending with the selected one:\n%s", view)
+	}
+}
+
+func BenchmarkView_20kJobs(b *testing.B) {
+	m := Model{Jobs: syntheticJobs(20000), Width: 120, Height: 40, Now: time.Now()}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.SelectedIndex = (i * 37) % len(m.Jobs)
+		m.Offset = ScrollOffset(m.Offset, m.SelectedIndex, m.Rows(), len(m.Jobs))
+		_ = m.View()
+	}
+}
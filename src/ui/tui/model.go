@@ -1,9 +1,21 @@
 package tui
 
 import (
+	"context"
+	"strings"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 
+	"sprayer/src/api/application"
+	"sprayer/src/api/export"
 	"sprayer/src/api/job"
+	"sprayer/src/api/notify"
+	"sprayer/src/api/power"
+	"sprayer/src/api/profile"
+	"sprayer/src/api/settings"
+	"sprayer/src/ui/tui/joblist"
+	"sprayer/src/ui/tui/settingsform"
 )
 
 type ViewState int
@@ -17,25 +29,267 @@ const (
 	Scraping
 	Emails
 	Compose
-	CVEntry
-	CVInfo
-	CVSummary
-	CVExperience
-	CVSkills
-	CVReview
+	Detail
+	Reminders
+	Export
+	ApplyQueue
+	Outbox
+	Inbox
+	Settings
+	Compare
+	Sources
 )
 
+// JobSource supplies the jobs shown in the TUI; *job.Store satisfies it.
+type JobSource interface {
+	ForProfile(profileID string, opts ...job.ActiveOption) ([]job.Job, error)
+}
+
+// StatusStore records where the user stands with a job; *job.Store
+// satisfies it.
+type StatusStore interface {
+	SetStatus(jobID string, st job.Status, note string, at time.Time) error
+	StatusHistory(jobID string) ([]job.StatusChange, error)
+}
+
 type Model struct {
+	// all is every job loaded, archived ones too; jobs is the part the
+	// list shows, as refilter derives it.
+	all           []job.Job
 	jobs          []job.Job
 	selectedIndex int
+	listOffset    int // first job shown; see joblist.ScrollOffset
 	profileName   string
 	viewState     ViewState
 	width         int
 	height        int
+
+	source  JobSource
+	loading bool // jobs requested, not yet delivered
+	spin    int  // spinner frame while loading
+	loadErr error
+	power   power.Decision
+	offline bool
+
+	// v lists the archived jobs instead of the rest; x and X archive and
+	// restore through archive. h hides the jobs applied to, saving the
+	// choice with saveHideApplied. listErr is the last of those changes
+	// that failed to save. The list is in sort's order, which 1 to 4
+	// choose and saveSort keeps.
+	archivedView    bool
+	archive         func(jobID string, archived bool) error
+	hideApplied     bool
+	saveHideApplied func(bool) error
+	sort            job.Sort
+	saveSort        func(job.Sort) error
+	listErr         error
+
+	// f picks a filter preset from presets; while preset is applied the
+	// list keeps to presetIDs, the jobs matchPreset found passing it.
+	presets     FilterPresets
+	matchPreset func(profile.FilterPreset) ([]string, error)
+	filterView  filterView
+	preset      *profile.FilterPreset
+	presetIDs   map[string]bool
+
+	// Status changes made with t are saved to statuses; history is that
+	// of the job open in Detail.
+	statuses StatusStore
+	history  []job.StatusChange
+	desc     descriptionMsg // of the job open in Detail, when read lazily
+	rawDesc  bool           // Detail shows the description unrendered
+	saveErr  error // the last status change that failed to save
+
+	// Detail opens the job's URL with openURL and copies with clip;
+	// detailDone or detailErr tells how the last of those went.
+	openURL    func(string) error
+	clip       func(string) error
+	detailDone string
+	detailErr  error
+
+	// app is the application to the job open in Detail, as
+	// findApplication reads it.
+	findApplication func(jobID string) (*application.Application, error)
+	app             *application.Application
+
+	// reminder is the follow-up selected in Reminders; composer writes
+	// the draft Compose shows for it, or streamer as it is written.
+	// drafts counts the drafts started, telling a stale one's messages
+	// from the current one's.
+	reminder int
+	composer Composer
+	streamer StreamComposer
+	// llmStatus reports why drafts come from templates; see WithLLMStatus.
+	llmStatus func() error
+	draft    draft
+	drafts   int
+
+	// Jobs marked with space are applied to in turn by the queue A
+	// starts; applier sends what the composer writes, or in a dry run
+	// drafts it, with the CV tailorCV builds in cvTemplate when asked to.
+	marked      map[string]bool
+	queue       *applyQueue
+	applier     Applier
+	dryRun      bool
+	tailorCV    CVTailor
+	cvTemplates []string
+	cvTemplate  string
+
+	// outbox holds the emails that failed to send, listed in Outbox.
+	outbox     OutboxStore
+	outboxView outboxView
+
+	// sourceStats is how the scraper sources have fared, listed in
+	// Sources; esc there goes back to sourcesReturn.
+	sourceStats   SourceStats
+	sourcesView   sourcesView
+	sourcesReturn ViewState
+
+	// inbox is polled for replies while the program runs; replyNotice
+	// announces the latest until the Inbox view is opened.
+	inbox       InboxSource
+	inboxView   inboxView
+	replyNotice string
+	inboxErr    error
+
+	// opens counts the tracked opens of each job's emails, read by
+	// countOpens when the program starts.
+	opens      map[string]int
+	countOpens func() (map[string]int, error)
+
+	// llmSpend is what LLM calls have cost this month, as spend reads it
+	// when the program starts.
+	llmSpend float64
+	spend    func() (float64, error)
+
+	// Jobs exported with e go to exportDir, in exportFormat when enter
+	// picks none; exported is the last export.
+	exportDir    string
+	exportFormat export.JobFormat
+	exported     exportedMsg
+
+	// p exports the profile through exportProfile, stripped or with its
+	// CV as profileExportMode says; profileExported is the last export.
+	// With a profileComparer, p lists the profiles first, and two picked
+	// there are compared in Compare.
+	exportProfile     ProfileExporter
+	profileExportMode profile.ExportMode
+	profileExported   profileExportedMsg
+	profileComparer   ProfileComparer
+	profilesView      profilesView
+	comparison        comparisonView
+
+	// , edits the settings in a settingsForm over the view it was opened
+	// from, settingsReturn; settingsSaved is the last save.
+	settings       SettingsStore
+	settingsEnv    settings.Overrides
+	settingsForm   *settingsform.Model
+	settingsReturn ViewState
+	settingsSaved  settingsSavedMsg
+
+	// Probes that may block (a network dial, running nmcli) run from
+	// Init so the first frame does not wait on them.
+	probeOffline func() bool
+	detectPower  func() power.Decision
+
+	// s runs a scrape through startScrape, saving what it finds with
+	// saveScraped; scrape is the one running and lastScrape the one that
+	// ended last. Without startScrape, scrape progress arrives as
+	// messages from whoever runs the program; see ScrapeProgressMsg. So
+	// do unread replies.
+	startScrape   func() IncrementalScraper
+	saveScraped   func([]job.Job) ([]job.Job, error)
+	scrape        *scrapeRun
+	lastScrape    *scrapeRun
+	scraping      bool
+	scrapeDone    int
+	scrapeTotal   int
+	newJobs       int
+	unreadReplies int
+
+	// Scraped jobs scoring notifyMin or more go to notifier; after it
+	// fails once it is dropped and notifyErr says why.
+	notifier  notify.Notifier
+	notifyMin int
+	notifyErr error
+
+	// Terminal title and status file, kept in step by syncStatus.
+	titles     bool
+	statusFile string
+	title      titleState
+	now        func() time.Time
+
+	// ctx bounds the work commands do off the update loop; see
+	// WithContext.
+	ctx context.Context
 }
 
-func NewModel() Model {
-	return Model{
+// Option configures a Model. Dependencies are injected rather than opened
+// by the model so tests can drive it with fixtures.
+type Option func(*Model)
+
+// WithJobSource loads jobs from src when the program starts.
+func WithJobSource(src JobSource) Option {
+	return func(m *Model) { m.source = src }
+}
+
+// WithStatusStore saves status changes made with t to s and reads the
+// history the detail view shows.
+func WithStatusStore(s StatusStore) Option {
+	return func(m *Model) { m.statuses = s }
+}
+
+// WithOpenCounts shows how often each job's emails were opened, as
+// counted by count when the program starts.
+func WithOpenCounts(count func() (map[string]int, error)) Option {
+	return func(m *Model) { m.countOpens = count }
+}
+
+// WithLLMSpend shows what LLM calls have cost this month, as read by
+// spend when the program starts.
+func WithLLMSpend(spend func() (float64, error)) Option {
+	return func(m *Model) { m.spend = spend }
+}
+
+// WithContext bounds the drafts, notifications and other work the
+// program does off its update loop by ctx, so cancelling it once the
+// program quits abandons whatever is still running.
+func WithContext(ctx context.Context) Option {
+	return func(m *Model) { m.ctx = ctx }
+}
+
+// baseContext is the context set by WithContext, or the background one.
+func (m Model) baseContext() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+// WithPower marks the status bar when d holds work back.
+func WithPower(d power.Decision) Option {
+	return func(m *Model) { m.power = d }
+}
+
+// WithOffline shows the offline badge in the status bar.
+func WithOffline(offline bool) Option {
+	return func(m *Model) { m.offline = offline }
+}
+
+// WithOfflineProbe is WithOffline decided by probe once the program has
+// started.
+func WithOfflineProbe(probe func() bool) Option {
+	return func(m *Model) { m.probeOffline = probe }
+}
+
+// WithPowerDetect is WithPower decided by detect once the program has
+// started.
+func WithPowerDetect(detect func() power.Decision) Option {
+	return func(m *Model) { m.detectPower = detect }
+}
+
+func NewModel(opts ...Option) Model {
+	m := Model{
 		jobs:          []job.Job{},
 		selectedIndex: 0,
 		profileName:   "Default",
@@ -43,11 +297,85 @@ func NewModel() Model {
 		width:         80,
 		height:        24,
 	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+	m.loading = m.source != nil
+	return m
+}
+
+// jobsLoadedMsg delivers the result of reading the job source.
+type jobsLoadedMsg struct {
+	jobs []job.Job
+	err  error
+}
+
+// opensMsg delivers the open counts; a failure to read them shows none.
+type opensMsg map[string]int
+
+// llmSpendMsg delivers the month's LLM cost; a failure to read it shows
+// none.
+type llmSpendMsg float64
+
+// offlineMsg and powerMsg deliver the startup probes.
+type (
+	offlineMsg bool
+	powerMsg   power.Decision
+)
+
+// spinMsg advances the loading spinner.
+type spinMsg struct{}
+
+// spinInterval is the loading spinner's frame time.
+const spinInterval = 100 * time.Millisecond
+
+func spinTick() tea.Cmd {
+	return tea.Tick(spinInterval, func(time.Time) tea.Msg { return spinMsg{} })
 }
 
 func (m *Model) SelectedIndex() int     { return m.selectedIndex }
 func (m *Model) ViewState() ViewState   { return m.viewState }
 func (m *Model) Jobs() []job.Job        { return m.jobs }
-func (m *Model) SetJobs(jobs []job.Job) { m.jobs = jobs }
 
-func (m Model) Init() tea.Cmd { return nil }
\ No newline at end of file
+// SetJobs replaces the loaded jobs. The cursor stays on the job it was
+// on, or on the last job of a list that no longer has it.
+func (m *Model) SetJobs(jobs []job.Job) {
+	m.all = jobs
+	*m = m.refilter()
+	m.listOffset = joblist.ScrollOffset(m.listOffset, m.selectedIndex, m.listRows(), len(m.jobs))
+}
+
+// Init starts loading jobs and runs the startup probes. Nothing here
+// runs before the first frame, which shows a spinner until jobs arrive.
+func (m Model) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	if m.source != nil {
+		src, profileID := m.source, strings.ToLower(m.profileName)
+		cmds = append(cmds, func() tea.Msg {
+			jobs, err := loadJobs(src, profileID)
+			return jobsLoadedMsg{jobs: jobs, err: err}
+		}, spinTick())
+	}
+	if count := m.countOpens; count != nil {
+		cmds = append(cmds, func() tea.Msg {
+			opens, _ := count()
+			return opensMsg(opens)
+		})
+	}
+	if spend := m.spend; spend != nil {
+		cmds = append(cmds, func() tea.Msg {
+			cost, _ := spend()
+			return llmSpendMsg(cost)
+		})
+	}
+	if m.inbox != nil {
+		cmds = append(cmds, m.pollInboxAllowed())
+	}
+	if probe := m.probeOffline; probe != nil {
+		cmds = append(cmds, func() tea.Msg { return offlineMsg(probe()) })
+	}
+	if detect := m.detectPower; detect != nil {
+		cmds = append(cmds, func() tea.Msg { return powerMsg(detect()) })
+	}
+	return tea.Batch(cmds...)
+}
\ No newline at end of file
@@ -1,11 +1,15 @@
 package tui
 
 import (
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/charmbracelet/bubbletea"
 
 	"sprayer/src/api/job"
+	"sprayer/src/api/power"
 )
 
 func TestNewModel(t *testing.T) {
@@ -45,6 +49,53 @@ func TestModel_Init(t *testing.T) {
 	}
 }
 
+// slowSource stands in for a large database.
+type slowSource struct{ delay time.Duration }
+
+func (s slowSource) ForProfile(string, ...job.ActiveOption) ([]job.Job, error) {
+	time.Sleep(s.delay)
+	return nil, nil
+}
+
+func TestNewModel_DefersSlowWork(t *testing.T) {
+	opts := []Option{
+		WithJobSource(slowSource{time.Second}),
+		WithOfflineProbe(func() bool { time.Sleep(time.Second); return true }),
+		WithPowerDetect(func() power.Decision { time.Sleep(time.Second); return power.Decision{LowBattery: true} }),
+	}
+	res := testing.Benchmark(func(b *testing.B) {
+		for range b.N {
+			m := NewModel(opts...)
+			_ = m.View()
+		}
+	})
+	if per := time.Duration(res.NsPerOp()); per >= 10*time.Millisecond {
+		t.Errorf("NewModel and the first frame took %v, want single-digit milliseconds", per)
+	}
+}
+
+func TestModel_InitDeliversProbes(t *testing.T) {
+	m := NewModel(
+		WithOfflineProbe(func() bool { return true }),
+		WithPowerDetect(func() power.Decision { return power.Decision{Metered: true} }),
+	)
+	if m.offline || m.power.Constrained() {
+		t.Fatal("probes ran before Init")
+	}
+	batch, ok := m.Init()().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("Init should batch the two probes, got %T", m.Init()())
+	}
+	var tm tea.Model = m
+	for _, cmd := range batch {
+		tm, _ = tm.Update(cmd())
+	}
+	got := tm.(Model)
+	if !got.offline || !got.power.Metered {
+		t.Errorf("probe results not applied: offline=%v power=%+v", got.offline, got.power)
+	}
+}
+
 func TestModel_Update_Navigation(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -159,6 +210,61 @@ func TestModel_Update_Navigation(t *testing.T) {
 	}
 }
 
+func manyJobs(n int) []job.Job {
+	jobs := make([]job.Job, n)
+	for i := range jobs {
+		jobs[i] = job.Job{ID: strconv.Itoa(i), Title: "Job " + strconv.Itoa(i)}
+	}
+	return jobs
+}
+
+func TestModel_Update_Paging(t *testing.T) {
+	m := NewModel()
+	m.SetJobs(manyJobs(412))
+	m.viewState = JobList
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 24}) // 22 rows
+
+	steps := []struct {
+		key        tea.KeyType
+		index, top int
+	}{
+		{tea.KeyPgDown, 22, 1},
+		{tea.KeyPgDown, 44, 23},
+		{tea.KeyPgUp, 22, 22},
+		{tea.KeyEnd, 411, 390},
+		{tea.KeyHome, 0, 0},
+	}
+	for _, st := range steps {
+		model, _ = model.Update(tea.KeyMsg{Type: st.key})
+		got := model.(Model)
+		if got.selectedIndex != st.index || got.listOffset != st.top {
+			t.Fatalf("after %s: index %d, top %d; want %d, %d", st.key, got.selectedIndex, got.listOffset, st.index, st.top)
+		}
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnd})
+	view := ansiRe.ReplaceAllString(model.View(), "")
+	if !strings.Contains(view, "Job 411") || !strings.Contains(view, "412/412") {
+		t.Errorf("end of the list is not in view:\n%s", view)
+	}
+}
+
+func TestModel_SetJobs_ClampsCursor(t *testing.T) {
+	m := NewModel()
+	m.SetJobs(manyJobs(412))
+	m.selectedIndex = 300
+	m.listOffset = 290
+	m.SetJobs(manyJobs(30))
+	if m.selectedIndex != 29 || m.listOffset != 8 {
+		t.Errorf("index %d, top %d after the list shrank to 30; want 29, 8", m.selectedIndex, m.listOffset)
+	}
+	m.SetJobs(nil)
+	if m.selectedIndex != 0 || m.listOffset != 0 {
+		t.Errorf("index %d, top %d with no jobs; want 0, 0", m.selectedIndex, m.listOffset)
+	}
+}
+
 func TestModel_Update_ViewStates(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -207,6 +313,60 @@ func TestModel_Update_ViewStates(t *testing.T) {
 	}
 }
 
+// TestModel_Update_EveryViewState reaches each view from the job list by
+// the keys a user would press.
+func TestModel_Update_EveryViewState(t *testing.T) {
+	due := time.Now().AddDate(0, 0, -1)
+	newModel := func() tea.Model {
+		jobs := fixtureJobs()
+		jobs[1].Applied, jobs[1].FollowUpAt, jobs[1].Email = true, &due, "hiring@globex.com"
+		composer := func(j job.Job, prompt string) (string, string, error) {
+			return "Re: " + j.Title, "Hi " + j.Company + ",\n", nil
+		}
+		return drive(NewModel(WithJobSource(fixtureSource(jobs)), WithComposer(composer),
+			WithApplier(&fakeApplier{}), WithOutbox(&fakeOutbox{}), WithInbox(&fakeInbox{})))
+	}
+	enter, esc := tea.KeyMsg{Type: tea.KeyEnter}, tea.KeyMsg{Type: tea.KeyEsc}
+	paths := map[ViewState][]tea.Msg{
+		JobList:    nil,
+		Filter:     {key("f")},
+		Profiles:   {key("p")},
+		Help:       {key("?")},
+		Scraping:   {key("s")},
+		Emails:     {key("m")},
+		Detail:     {enter},
+		Reminders:  {key("u")},
+		Compose:    {key("u"), enter},
+		Export:     {key("e")},
+		ApplyQueue: {key(" "), key("A")},
+		Outbox:     {key("o")},
+		Inbox:      {key("i")},
+	}
+
+	if got := drive(NewModel()).(Model).viewState; got != EmptyState {
+		t.Errorf("with no jobs: view %v, want EmptyState", got)
+	}
+	for state := JobList; state <= Inbox; state++ {
+		path, ok := paths[state]
+		if !ok {
+			t.Errorf("no way to reach view %v", state)
+			continue
+		}
+		m := newModel()
+		for _, msg := range path {
+			m = run(m, msg)
+		}
+		if got := m.(Model).viewState; got != state {
+			t.Errorf("%v: landed in view %v, want %v", path, got, state)
+		}
+		if state == Detail || state == Reminders {
+			if got := run(m, esc).(Model).viewState; got != JobList {
+				t.Errorf("esc from %v went to %v, want JobList", state, got)
+			}
+		}
+	}
+}
+
 func TestModel_Update_Quit(t *testing.T) {
 	tests := []struct {
 		name string
@@ -318,6 +478,17 @@ func TestModel_View_JobList(t *testing.T) {
 	}
 }
 
+func TestModel_View_JobListShortIDs(t *testing.T) {
+	m := NewModel()
+	m.jobs = []job.Job{{ID: "gh-acme-1", ShortID: 1234, Title: "Software Engineer", Company: "TechCorp", Score: 85}}
+	m.width = 80
+	m.height = 24
+
+	if view := m.View(); !contains(view, "#1234") {
+		t.Error("expected View() to show the short ID '#1234'")
+	}
+}
+
 func TestModel_View_TopBar(t *testing.T) {
 	m := NewModel()
 	m.profileName = "TestProfile"
@@ -384,3 +555,32 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestModel_OpenCounts(t *testing.T) {
+	count := func() (map[string]int, error) { return map[string]int{"2": 3}, nil }
+	m := drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithOpenCounts(count)))
+	lines := strings.Split(ansiRe.ReplaceAllString(m.View(), ""), "\n")
+	var opened []string
+	for _, l := range lines {
+		if strings.Contains(l, "👁") {
+			opened = append(opened, l)
+		}
+	}
+	if len(opened) != 1 || !strings.Contains(opened[0], "Platform Engineer") || !strings.Contains(opened[0], "👁 3") {
+		t.Errorf("opened rows = %q", opened)
+	}
+}
+
+func TestModel_LLMSpend(t *testing.T) {
+	spend := func() (float64, error) { return 1.234, nil }
+	m := drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithLLMSpend(spend)))
+	if view := ansiRe.ReplaceAllString(m.View(), ""); !strings.Contains(view, "LLM $1.23 this month") {
+		t.Errorf("status bar lacks the month's LLM cost:\n%s", view)
+	}
+
+	none := func() (float64, error) { return 0, nil }
+	m = drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithLLMSpend(none)))
+	if view := ansiRe.ReplaceAllString(m.View(), ""); strings.Contains(view, "this month") {
+		t.Errorf("status bar shows a cost with nothing spent:\n%s", view)
+	}
+}
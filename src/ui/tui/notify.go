@@ -0,0 +1,43 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/notify"
+	"sprayer/src/ui/tui/theme"
+)
+
+// WithNotifier raises a desktop notification through n for each scraped
+// job scoring minScore or more. A minScore of zero notifies of none.
+func WithNotifier(n notify.Notifier, minScore int) Option {
+	return func(m *Model) { m.notifier, m.notifyMin = n, minScore }
+}
+
+// ScrapedJobMsg reports a job a running scrape found, to notify of when
+// it scores high enough; see WithNotifier.
+type ScrapedJobMsg struct{ Job job.Job }
+
+// notifiedMsg reports a notification handed to the desktop, or why not.
+type notifiedMsg struct{ err error }
+
+// notifyJob notifies of j off the update loop, so a slow or missing
+// notifier never holds up the scrape.
+func (m Model) notifyJob(j job.Job) tea.Cmd {
+	if m.notifier == nil || m.notifyMin <= 0 || j.Score < m.notifyMin {
+		return nil
+	}
+	n, ctx := m.notifier, m.baseContext()
+	return func() tea.Msg {
+		return notifiedMsg{err: n.Notify(ctx, notify.ForJob(j))}
+	}
+}
+
+// notifyNotice is the status bar's word on notifications: why they
+// stopped, if they did.
+func (m Model) notifyNotice() string {
+	if m.notifyErr == nil {
+		return ""
+	}
+	return theme.StatusLabelStyle.Render("notifications off: " + m.notifyErr.Error())
+}
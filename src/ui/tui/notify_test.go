@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/notify"
+)
+
+// fakeNotifier keeps what it is asked to show and fails with err.
+type fakeNotifier struct {
+	got []notify.Notification
+	err error
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, n notify.Notification) error {
+	f.got = append(f.got, n)
+	return f.err
+}
+
+func TestModel_NotifiesHighScoringJobs(t *testing.T) {
+	f := &fakeNotifier{}
+	m := drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithNotifier(f, 80)))
+
+	m = run(m, ScrapedJobMsg{Job: job.Job{Title: "Go Engineer", Company: "Acme", Score: 79}})
+	m = run(m, ScrapedJobMsg{Job: job.Job{Title: "Rust Engineer", Company: "Initech", Score: 91}})
+	if len(f.got) != 1 || f.got[0].Title != "New job (91): Rust Engineer" || f.got[0].Body != "Initech" {
+		t.Errorf("notified %+v, want only the 91", f.got)
+	}
+}
+
+func TestModel_NotifierFailureTurnsNotificationsOff(t *testing.T) {
+	f := &fakeNotifier{err: fmt.Errorf("%w: notify-send not found", notify.ErrUnavailable)}
+	m := drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithNotifier(f, 50)))
+
+	m = run(m, ScrapedJobMsg{Job: job.Job{Title: "Go Engineer", Score: 90}})
+	m = run(m, ScrapedJobMsg{Job: job.Job{Title: "Go Engineer", Score: 95}})
+	if len(f.got) != 1 {
+		t.Errorf("notified %d times, want 1 before giving up", len(f.got))
+	}
+	if view := plain(m); !strings.Contains(view, "notifications off") {
+		t.Errorf("status bar does not say notifications stopped:\n%s", view)
+	}
+}
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/outbox"
+	"sprayer/src/ui/tui/theme"
+)
+
+// OutboxStore holds the emails that have not gone out yet: Retry sends
+// one now and Delete drops it. The CLI's outbox store with its sender
+// satisfies it.
+type OutboxStore interface {
+	Unsent() ([]outbox.Message, error)
+	Retry(id int64) error
+	Delete(id int64) error
+}
+
+// WithOutbox lists the emails waiting in o in the Outbox view, opened
+// with o.
+func WithOutbox(o OutboxStore) Option {
+	return func(m *Model) { m.outbox = o }
+}
+
+// outboxView is the state of the Outbox view.
+type outboxView struct {
+	msgs     []outbox.Message
+	selected int
+	busy     bool  // a retry or delete is running
+	err      error // why the last load, retry or delete failed
+}
+
+// outboxLoadedMsg delivers the unsent messages; outboxDoneMsg the result
+// of a retry or delete, after which they are loaded again.
+type (
+	outboxLoadedMsg struct {
+		msgs []outbox.Message
+		err  error
+	}
+	outboxDoneMsg struct{ err error }
+)
+
+// openOutbox shows the Outbox view and loads it.
+func (m Model) openOutbox() (Model, tea.Cmd) {
+	if m.outbox == nil {
+		return m, nil
+	}
+	m.viewState, m.outboxView = Outbox, outboxView{busy: true}
+	return m, m.loadOutbox()
+}
+
+func (m Model) loadOutbox() tea.Cmd {
+	o := m.outbox
+	return func() tea.Msg {
+		msgs, err := o.Unsent()
+		return outboxLoadedMsg{msgs: msgs, err: err}
+	}
+}
+
+// updateOutbox handles a key in the Outbox view.
+func (m Model) updateOutbox(msg tea.KeyMsg) (Model, tea.Cmd) {
+	v := &m.outboxView
+	switch msg.String() {
+	case "esc":
+		m.viewState = JobList
+	case "ctrl+c", "q":
+		return m.quit()
+	case "j", "down":
+		v.selected = max(min(v.selected+1, len(v.msgs)-1), 0)
+	case "k", "up":
+		v.selected = max(v.selected-1, 0)
+	case "r", "d":
+		if v.busy || len(v.msgs) == 0 {
+			return m, nil
+		}
+		o, id, retry := m.outbox, v.msgs[v.selected].ID, msg.String() == "r"
+		v.busy, v.err = true, nil
+		return m, func() tea.Msg {
+			if retry {
+				return outboxDoneMsg{err: o.Retry(id)}
+			}
+			return outboxDoneMsg{err: o.Delete(id)}
+		}
+	}
+	return m, nil
+}
+
+// renderOutbox lists the unsent messages with the selected one
+// highlighted and its last error below it.
+func (m Model) renderOutbox() string {
+	bg := lipgloss.NewStyle().Background(theme.Background)
+	label := bg.Foreground(theme.Subtle)
+	v := m.outboxView
+
+	lines := []string{bg.Foreground(theme.Bright).Bold(true).Render("Outbox"), ""}
+	if len(v.msgs) == 0 && !v.busy {
+		lines = append(lines, label.Render("Nothing waiting to be sent."))
+	}
+	for i, msg := range v.msgs {
+		style := theme.JobItemStyle
+		if i == v.selected {
+			style = theme.JobItemSelectedStyle
+		}
+		state := theme.WarningStyle.Render(fmt.Sprintf("queued, %d attempt(s)", msg.Attempts))
+		switch msg.Status {
+		case outbox.StatusFailed:
+			state = theme.ErrorStyle.Render("failed")
+		case outbox.StatusSending:
+			state = theme.ErrorStyle.Render("interrupted while sending, may be out")
+		}
+		lines = append(lines, style.Render(msg.To+"  "+msg.Subject)+"  "+state)
+		if i == v.selected && msg.LastError != "" {
+			lines = append(lines, theme.ErrorStyle.Render("  "+msg.LastError))
+		}
+	}
+	switch {
+	case v.busy:
+		lines = append(lines, "", label.Render("Working…"))
+	case v.err != nil:
+		lines = append(lines, "", theme.ErrorStyle.Render(v.err.Error()))
+	}
+	lines = append(lines, "", label.Render("r retry now · d delete · esc back"))
+	block := bg.Padding(1, 2).Width(m.width).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Left, lipgloss.Top, block,
+		lipgloss.WithWhitespaceBackground(theme.Background))
+}
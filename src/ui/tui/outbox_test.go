@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/outbox"
+)
+
+// fakeOutbox holds messages in memory; Retry fails while down is set.
+type fakeOutbox struct {
+	msgs []outbox.Message
+	down error
+}
+
+func (o *fakeOutbox) Unsent() ([]outbox.Message, error) { return slices.Clone(o.msgs), nil }
+
+func (o *fakeOutbox) Retry(id int64) error {
+	if o.down != nil {
+		return o.down
+	}
+	return o.Delete(id)
+}
+
+func (o *fakeOutbox) Delete(id int64) error {
+	i := slices.IndexFunc(o.msgs, func(m outbox.Message) bool { return m.ID == id })
+	if i < 0 {
+		return errors.New("no such message")
+	}
+	o.msgs = slices.Delete(o.msgs, i, i+1)
+	return nil
+}
+
+func TestModel_Outbox(t *testing.T) {
+	o := &fakeOutbox{msgs: []outbox.Message{
+		{ID: 1, To: "jobs@acme.com", Subject: "Senior Go Engineer", Status: outbox.StatusPending, Attempts: 2, LastError: "451 try again later"},
+		{ID: 2, To: "hiring@globex.com", Subject: "Platform Engineer", Status: outbox.StatusFailed, Attempts: 5, LastError: "550 no such user"},
+	}, down: errors.New("connection refused")}
+	m := drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithOutbox(o)))
+
+	m = run(m, key("o"))
+	view := plain(m)
+	for _, want := range []string{"Outbox", "jobs@acme.com Senior Go Engineer queued, 2 attempt(s)", "451 try again later",
+		"hiring@globex.com Platform Engineer failed", "r retry now · d delete · esc back"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("outbox lacks %q:\n%s", want, view)
+		}
+	}
+
+	m = run(m, key("j"))
+	m = run(m, key("r"))
+	if view := plain(m); !strings.Contains(view, "connection refused") || !strings.Contains(view, "550 no such user") {
+		t.Errorf("failed retry not shown:\n%s", view)
+	}
+	o.down = nil
+	m = run(m, key("r"))
+	if len(o.msgs) != 1 || strings.Contains(plain(m), "hiring@globex.com") {
+		t.Errorf("retried message still listed: %+v\n%s", o.msgs, plain(m))
+	}
+	m = run(m, key("d"))
+	if view := plain(m); len(o.msgs) != 0 || !strings.Contains(view, "Nothing waiting to be sent.") {
+		t.Errorf("delete left %+v:\n%s", o.msgs, view)
+	}
+
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.(Model).viewState != JobList {
+		t.Errorf("esc left the view in %v", m.(Model).viewState)
+	}
+}
@@ -0,0 +1,233 @@
+package profileform
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/parse"
+	"sprayer/src/api/profile"
+	"sprayer/src/api/scraper"
+	"sprayer/src/ui/tui/sectionform"
+)
+
+// Model edits a profile.Profile through a three-section form, and
+// shift+tab on the first field of a section returns to the previous one.
+type Model struct {
+	*sectionform.Form
+
+	profile profile.Profile
+
+	// Text buffers for list and numeric fields; applied by Profile().
+	keywords  string
+	exclude   string
+	locations string
+	preferred string
+	avoid     string
+	ashbyOrgs string
+	boards    string
+	feeds     string
+	weights   string
+	minScore  string
+	notifyAt  string
+	authz     string
+	languages string
+}
+
+// New builds a form pre-filled from p.
+func New(p profile.Profile) *Model {
+	m := &Model{
+		profile:   p,
+		keywords:  strings.Join(p.Keywords, ", "),
+		exclude:   strings.Join(p.ExcludeKeywords, ", "),
+		locations: strings.Join(p.Locations, ", "),
+		preferred: strings.Join(p.PreferredTech, ", "),
+		avoid:     strings.Join(p.AvoidTech, ", "),
+		ashbyOrgs: strings.Join(p.AshbyOrgs, ", "),
+		boards:    strings.Join(p.GreenhouseBoards, ", "),
+		feeds:     strings.Join(p.Feeds, ", "),
+		weights:   profile.FormatSourceWeights(p.SourceWeights),
+		minScore:  strconv.Itoa(p.MinScore),
+		notifyAt:  strconv.Itoa(p.NotifyMinScore),
+		authz:     strings.Join(p.WorkAuthorizations, ", "),
+		languages: strings.Join(p.AcceptedLanguages, ", "),
+	}
+
+	groups := []*huh.Group{
+		huh.NewGroup(
+			huh.NewInput().Title("Profile name").Value(&m.profile.Name).
+				Validate(required("name")),
+			huh.NewInput().Title("Contact email").Value(&m.profile.ContactEmail).
+				Placeholder("me@example.com"),
+			huh.NewInput().Title("CV path").Value(&m.profile.CVPath).
+				Placeholder("cv.pdf"),
+			huh.NewInput().Title("Cover letter template").Value(&m.profile.CoverPath).
+				Placeholder("cover.txt"),
+		),
+		huh.NewGroup(
+			huh.NewInput().Title("Keywords").Description("Comma separated").
+				Value(&m.keywords),
+			huh.NewInput().Title("Exclude keywords").Value(&m.exclude),
+			huh.NewInput().Title("Locations").Value(&m.locations),
+			huh.NewConfirm().Title("Prefer remote?").Value(&m.profile.PreferRemote),
+			huh.NewMultiSelect[string]().Title("Job types").
+				Options(huh.NewOptions("full-time", "contract", "part-time", "internship")...).
+				Value(&m.profile.JobTypes),
+			huh.NewMultiSelect[string]().Title("Seniority").
+				Options(huh.NewOptions(parse.SeniorityLevels...)...).
+				Value(&m.profile.SeniorityLevels),
+			huh.NewConfirm().Title("Keep jobs of unknown seniority?").
+				Description("When no level is in the title or years in the description").
+				Value(&m.profile.IncludeUnknownSeniority),
+			huh.NewMultiSelect[string]().Title("Sources").Description("None selected runs all but linkedin, indeed and glassdoor").
+				Options(huh.NewOptions(scraper.KnownSourceKeys()...)...).
+				Value(&m.profile.Sources).Height(8),
+		),
+		huh.NewGroup(
+			huh.NewInput().Title("Minimum score").Value(&m.minScore).
+				Validate(scoreValidator),
+			huh.NewInput().Title("Notify from score").Description("Desktop notification for new jobs scoring this or more; 0 is off").
+				Value(&m.notifyAt).Validate(scoreValidator),
+			huh.NewConfirm().Title("Require contact email?").Value(&m.profile.MustHaveEmail),
+			huh.NewConfirm().Title("Exclude trap listings?").Value(&m.profile.ExcludeTraps),
+			huh.NewSelect[job.TrapSeverity]().Title("Exclude traps from severity").
+				Options(huh.NewOption("any", job.TrapSeverity("")), huh.NewOption("medium", job.TrapMedium),
+					huh.NewOption("high", job.TrapHigh)).
+				Value(&m.profile.TrapSeverity),
+			huh.NewInput().Title("Languages").Description("Posting languages to keep, e.g. en, de; empty keeps all").
+				Value(&m.languages),
+			huh.NewInput().Title("Preferred tech").Value(&m.preferred),
+			huh.NewInput().Title("Avoid tech").Value(&m.avoid),
+			huh.NewInput().Title("Ashby boards").Description("Org slugs; empty uses defaults").
+				Value(&m.ashbyOrgs),
+			huh.NewInput().Title("Greenhouse boards").Description("Board tokens or job URLs, scraped with the defaults").
+				Value(&m.boards).Validate(boardsValidator),
+			huh.NewInput().Title("Feeds").Description("RSS or Atom feed URLs; empty uses defaults").
+				Value(&m.feeds).Validate(feedsValidator),
+			huh.NewInput().Title("Source weights").Description("source:delta, e.g. greenhouse:+10").
+				Value(&m.weights).Validate(m.weightsValidator),
+			huh.NewInput().Title("Work authorizations").Description("Regions you may work in, e.g. US, EU, UK").
+				Value(&m.authz),
+			huh.NewSelect[string]().Title("Security clearance").
+				Options(append([]huh.Option[string]{huh.NewOption("none", "")}, huh.NewOptions(parse.Clearances...)...)...).
+				Value(&m.profile.Clearance),
+			huh.NewConfirm().Title("Hide jobs I lack authorization for?").
+				Description("Otherwise they are scored lower").Value(&m.profile.ExcludeUnmetRequirements),
+		),
+	}
+	m.Form = sectionform.New([]string{"Basics", "Search", "Filters"}, groups...)
+	return m
+}
+
+func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
+	return m, m.Form.Update(msg)
+}
+
+// Profile returns the edited profile with text buffers parsed back.
+func (m *Model) Profile() profile.Profile {
+	p := m.profile
+	p.Keywords = splitList(m.keywords)
+	p.ExcludeKeywords = splitList(m.exclude)
+	p.Locations = splitList(m.locations)
+	p.PreferredTech = splitList(m.preferred)
+	p.AvoidTech = splitList(m.avoid)
+	p.AshbyOrgs = splitList(m.ashbyOrgs)
+	p.GreenhouseBoards = nil
+	for _, b := range splitList(m.boards) {
+		if board, err := scraper.ParseGreenhouseBoard(b); err == nil && !slices.Contains(p.GreenhouseBoards, board) {
+			p.GreenhouseBoards = append(p.GreenhouseBoards, board)
+		}
+	}
+	p.Feeds = nil
+	for _, f := range splitList(m.feeds) {
+		if !slices.Contains(p.Feeds, f) {
+			p.Feeds = append(p.Feeds, f)
+		}
+	}
+	p.AcceptedLanguages = nil
+	for _, l := range splitList(m.languages) {
+		p.AcceptedLanguages = append(p.AcceptedLanguages, strings.ToLower(l))
+	}
+	p.WorkAuthorizations = nil
+	for _, r := range splitList(m.authz) {
+		p.WorkAuthorizations = append(p.WorkAuthorizations, parse.NormalizeRegion(r))
+	}
+	if w, err := profile.ParseSourceWeights(m.weights, m.feedNames()...); err == nil {
+		p.SourceWeights = w
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(m.minScore)); err == nil {
+		p.MinScore = n
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(m.notifyAt)); err == nil {
+		p.NotifyMinScore = n
+	}
+	return p
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func required(name string) func(string) error {
+	return func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("%s is required", name)
+		}
+		return nil
+	}
+}
+
+func scoreValidator(s string) error {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 || n > 100 {
+		return fmt.Errorf("score must be 0-100")
+	}
+	return nil
+}
+
+// boardsValidator accepts Greenhouse board tokens and job URLs.
+func boardsValidator(s string) error {
+	for _, b := range splitList(s) {
+		if _, err := scraper.ParseGreenhouseBoard(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// feedsValidator accepts http(s) feed URLs.
+func feedsValidator(s string) error {
+	for _, f := range splitList(s) {
+		if err := scraper.CheckFeedURL(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// weightsValidator accepts source weights for the known sources and the
+// feeds entered above.
+func (m *Model) weightsValidator(s string) error {
+	_, err := profile.ParseSourceWeights(s, m.feedNames()...)
+	return err
+}
+
+// feedNames are the source names of the feeds entered.
+func (m *Model) feedNames() []string {
+	var names []string
+	for _, f := range scraper.Feeds(profile.Profile{Feeds: splitList(m.feeds)}) {
+		names = append(names, f.Name)
+	}
+	return names
+}
@@ -0,0 +1,162 @@
+package profileform
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/profile"
+)
+
+var sectionFields = [][]string{
+	{"Profile name", "Contact email", "CV path", "Cover letter template"},
+	{"Keywords", "Exclude keywords", "Locations", "Prefer remote?", "Job types", "Seniority", "Keep jobs of unknown seniority?", "Sources"},
+	{"Minimum score", "Notify from score", "Require contact email?", "Exclude trap listings?", "Exclude traps from severity", "Languages", "Preferred tech", "Avoid tech", "Ashby boards", "Greenhouse boards", "Feeds", "Source weights",
+		"Work authorizations", "Security clearance", "Hide jobs I lack authorization for?"},
+}
+
+// run feeds msg to the form and follows the resulting commands, skipping
+// timers such as cursor blinks that would not fire in a test.
+func run(m *Model, msg tea.Msg) {
+	_, cmd := m.Update(msg)
+	drain(m, cmd, 0)
+}
+
+func drain(m *Model, cmd tea.Cmd, depth int) {
+	if cmd == nil || depth > 10 {
+		return
+	}
+	ch := make(chan tea.Msg, 1)
+	go func() { ch <- cmd() }()
+	var msg tea.Msg
+	select {
+	case msg = <-ch:
+	case <-time.After(20 * time.Millisecond):
+		return
+	}
+	switch msg := msg.(type) {
+	case nil:
+	case tea.BatchMsg:
+		for _, c := range msg {
+			drain(m, c, depth+1)
+		}
+	default:
+		_, next := m.Update(msg)
+		drain(m, next, depth+1)
+	}
+}
+
+// focusedTitle returns the first line drawn with huh's focus border.
+func focusedTitle(view string) string {
+	for _, line := range strings.Split(view, "\n") {
+		if i := strings.Index(line, "┃"); i >= 0 {
+			return strings.TrimSpace(line[i+len("┃"):])
+		}
+	}
+	return ""
+}
+
+func newSized(t *testing.T, w, h int) *Model {
+	t.Helper()
+	p := profile.NewDefaultProfile()
+	m := New(p)
+	m.SetSize(w, h)
+	drain(m, m.Init(), 0)
+	return m
+}
+
+func TestProfileForm_EveryFieldFocusableAtSmallSizes(t *testing.T) {
+	for _, size := range [][2]int{{80, 24}, {60, 16}, {40, 12}} {
+		m := newSized(t, size[0], size[1])
+		for s, fields := range sectionFields {
+			for _, title := range fields {
+				view := m.View()
+				if got := lipgloss.Height(view); got > size[1] {
+					t.Fatalf("%dx%d: view is %d rows tall", size[0], size[1], got)
+				}
+				if got := focusedTitle(view); !strings.HasPrefix(got, title) {
+					t.Fatalf("%dx%d: want %q focused and visible, got %q\n%s", size[0], size[1], title, got, view)
+				}
+				if m.Section() != s {
+					t.Fatalf("%dx%d: %q in section %d, indicator says %d", size[0], size[1], title, s, m.Section())
+				}
+				run(m, tea.KeyMsg{Type: tea.KeyEnter})
+			}
+		}
+		if !m.Done() {
+			t.Errorf("%dx%d: form not submitted after last field", size[0], size[1])
+		}
+	}
+}
+
+func TestProfileForm_ShiftTabCrossesSections(t *testing.T) {
+	m := newSized(t, 60, 16)
+	for range sectionFields[0] {
+		run(m, tea.KeyMsg{Type: tea.KeyTab})
+	}
+	if m.Section() != 1 || !strings.Contains(m.View(), "Section 2 of 3") {
+		t.Fatalf("expected section 2, got %d\n%s", m.Section()+1, m.View())
+	}
+
+	run(m, tea.KeyMsg{Type: tea.KeyShiftTab})
+	if m.Section() != 0 {
+		t.Fatalf("shift+tab should return to section 1, got %d", m.Section()+1)
+	}
+	if got := focusedTitle(m.View()); !strings.HasPrefix(got, "Cover letter template") {
+		t.Errorf("expected last field of section 1 focused, got %q", got)
+	}
+}
+
+func TestProfileForm_ProfileParsesLists(t *testing.T) {
+	m := New(profile.NewDefaultProfile())
+	m.keywords = " go,  rust ,,"
+	m.ashbyOrgs = "ramp"
+	m.boards = "acme, https://boards.greenhouse.io/Globex/jobs/4012, acme"
+	m.feeds = "https://example.com/jobs.rss, https://example.com/jobs.rss"
+	m.minScore = "40"
+	m.weights = "Greenhouse:+10, glassdoor:-15"
+	m.authz = "us, gb"
+
+	p := m.Profile()
+	if strings.Join(p.Keywords, "|") != "go|rust" {
+		t.Errorf("Keywords = %v", p.Keywords)
+	}
+	if p.SourceWeights["Greenhouse"] != 10 || p.SourceWeights["glassdoor"] != -15 {
+		t.Errorf("SourceWeights = %v", p.SourceWeights)
+	}
+	if strings.Join(p.WorkAuthorizations, "|") != "US|UK" {
+		t.Errorf("WorkAuthorizations = %v", p.WorkAuthorizations)
+	}
+	if strings.Join(p.GreenhouseBoards, "|") != "acme|globex" {
+		t.Errorf("GreenhouseBoards = %v", p.GreenhouseBoards)
+	}
+	if len(p.Feeds) != 1 {
+		t.Errorf("Feeds = %v", p.Feeds)
+	}
+	if len(p.AshbyOrgs) != 1 || p.MinScore != 40 {
+		t.Errorf("unexpected profile: %+v", p)
+	}
+}
+
+func TestProfileForm_RejectsUnknownSource(t *testing.T) {
+	m := New(profile.NewDefaultProfile())
+	if err := m.weightsValidator("greenhouse:+5, monster:-10"); err == nil || !strings.Contains(err.Error(), "monster") {
+		t.Errorf("expected unknown source error, got %v", err)
+	}
+	if err := m.weightsValidator(""); err != nil {
+		t.Errorf("empty weights should be valid: %v", err)
+	}
+	if err := m.weightsValidator("yc_work_at_startup:+5, golang-cafe:+5"); err != nil {
+		t.Errorf("built-in sources rejected: %v", err)
+	}
+	if err := m.weightsValidator("jobs.example.org:+5"); err == nil {
+		t.Errorf("weight for a feed not entered accepted")
+	}
+	m.feeds = "https://www.jobs.example.org/rss"
+	if err := m.weightsValidator("jobs.example.org:+5"); err != nil {
+		t.Errorf("weight for an entered feed rejected: %v", err)
+	}
+}
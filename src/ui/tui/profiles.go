@@ -0,0 +1,226 @@
+package tui
+
+import (
+	"path/filepath"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/profile"
+	"sprayer/src/ui/tui/theme"
+)
+
+// ProfileExporter writes the profile the TUI runs as, in mode, returning
+// the file written.
+type ProfileExporter func(mode profile.ExportMode) (string, error)
+
+// WithProfileExport lets p export the profile through export: as stored,
+// stripped of its personal fields to share it, or with its CV inlined.
+func WithProfileExport(export ProfileExporter) Option {
+	return func(m *Model) { m.exportProfile = export }
+}
+
+// ProfileComparer lists the stored profiles and compares some of them on
+// the jobs they see, as profile.CompareStored does.
+type ProfileComparer interface {
+	All() ([]profile.Profile, error)
+	Compare(ids []string, by int) ([]profile.Compared, error)
+}
+
+// WithProfileComparer makes p list the profiles of c, two of which space
+// picks and c compares side by side.
+func WithProfileComparer(c ProfileComparer) Option {
+	return func(m *Model) { m.profileComparer = c }
+}
+
+// profilesView is the state of the Profiles view: the profiles listed,
+// those picked to compare, in the order picked, and whether the export
+// prompt is open over the list.
+type profilesView struct {
+	profiles  []profile.Profile
+	selected  int
+	picked    []string
+	exporting bool
+	busy      bool
+	err       error
+}
+
+// profilesLoadedMsg delivers the stored profiles; profileExportedMsg
+// reports writing the profile export.
+type (
+	profilesLoadedMsg struct {
+		profiles []profile.Profile
+		err      error
+	}
+	profileExportedMsg struct {
+		path string
+		err  error
+	}
+)
+
+// openProfiles shows the Profiles view, loading the profiles to list.
+// Without a comparer it is only the export prompt.
+func (m Model) openProfiles() (Model, tea.Cmd) {
+	m.viewState = Profiles
+	if m.profileComparer == nil {
+		return m, nil
+	}
+	m.profilesView = profilesView{picked: m.profilesView.picked, busy: true}
+	comparer := m.profileComparer
+	return m, func() tea.Msg {
+		all, err := comparer.All()
+		return profilesLoadedMsg{profiles: all, err: err}
+	}
+}
+
+// exportPrompt tells whether Profiles shows the export prompt rather
+// than the list.
+func (m Model) exportPrompt() bool {
+	return m.profileComparer == nil || m.profilesView.exporting
+}
+
+// updateProfiles handles a key in the Profiles view: j and k move, space
+// picks a profile, up to two, c compares those picked and e opens the
+// export prompt.
+func (m Model) updateProfiles(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if m.exportPrompt() {
+		return m.updateProfileExport(msg)
+	}
+	v := &m.profilesView
+	switch msg.String() {
+	case "esc":
+		m.viewState = JobList
+	case "ctrl+c", "q":
+		return m.quit()
+	case "j", "down":
+		v.selected = max(min(v.selected+1, len(v.profiles)-1), 0)
+	case "k", "up":
+		v.selected = max(v.selected-1, 0)
+	case " ":
+		if len(v.profiles) == 0 {
+			return m, nil
+		}
+		id := v.profiles[v.selected].ID
+		if i := slices.Index(v.picked, id); i >= 0 {
+			v.picked = slices.Delete(v.picked, i, i+1)
+		} else {
+			// A third pick drops the first.
+			v.picked = append(v.picked[max(len(v.picked)-1, 0):], id)
+		}
+		v.err = nil
+	case "c":
+		return m.openComparison()
+	case "e":
+		if m.exportProfile != nil {
+			v.exporting = true
+		}
+	}
+	return m, nil
+}
+
+// updateProfileExport handles a key while the export prompt is open: s
+// and c toggle stripping the personal fields and inlining the CV, which
+// exclude each other, and enter exports.
+func (m Model) updateProfileExport(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "s":
+		m.profileExportMode = toggleMode(m.profileExportMode, profile.ExportStripped)
+	case "c":
+		m.profileExportMode = toggleMode(m.profileExportMode, profile.ExportWithCV)
+	case "enter":
+		m.viewState, m.profilesView.exporting = JobList, false
+		export, mode := m.exportProfile, m.profileExportMode
+		return m, func() tea.Msg {
+			path, err := export(mode)
+			return profileExportedMsg{path: path, err: err}
+		}
+	case "esc":
+		if m.profileComparer == nil {
+			m.viewState = JobList
+		}
+		m.profilesView.exporting = false
+	case "ctrl+c", "q":
+		return m.quit()
+	}
+	return m, nil
+}
+
+func toggleMode(current, mode profile.ExportMode) profile.ExportMode {
+	if current == mode {
+		return profile.ExportAll
+	}
+	return mode
+}
+
+// profileExportNotice is the status bar's note on the last profile
+// export.
+func (m Model) profileExportNotice() string {
+	if m.profileExported.err != nil {
+		return theme.ErrorStyle.Render("profile export failed: " + m.profileExported.err.Error())
+	}
+	if m.profileExported.path == "" {
+		return ""
+	}
+	return theme.SuccessStyle.Render("exported profile to " + filepath.Base(m.profileExported.path))
+}
+
+// renderProfiles lists the profiles, the one selected highlighted and
+// those picked to compare numbered, or shows the export prompt.
+func (m Model) renderProfiles() string {
+	if m.exportPrompt() {
+		return m.renderProfileExport()
+	}
+	bg := lipgloss.NewStyle().Background(theme.Background)
+	label := bg.Foreground(theme.Subtle)
+	v := m.profilesView
+
+	lines := []string{bg.Foreground(theme.Bright).Bold(true).Render("Profiles"), ""}
+	if len(v.profiles) == 0 && !v.busy && v.err == nil {
+		lines = append(lines, label.Render("No profiles stored."))
+	}
+	for i, p := range v.profiles {
+		style := theme.JobItemStyle
+		if i == v.selected {
+			style = theme.JobItemSelectedStyle
+		}
+		mark := "[ ] "
+		if n := slices.Index(v.picked, p.ID); n >= 0 {
+			mark = "[" + string(rune('1'+n)) + "] "
+		}
+		lines = append(lines, style.Render(mark+p.Name)+label.Render("  "+p.ID))
+	}
+	switch {
+	case v.busy:
+		lines = append(lines, "", label.Render("Loading…"))
+	case v.err != nil:
+		lines = append(lines, "", theme.ErrorStyle.Render(v.err.Error()))
+	}
+	hint := "space pick two · c compare · esc back"
+	if m.exportProfile != nil {
+		hint = "space pick two · c compare · e export " + m.profileName + " · esc back"
+	}
+	lines = append(lines, "", label.Render(hint))
+	block := bg.Padding(1, 2).Width(m.width).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Left, lipgloss.Top, block,
+		lipgloss.WithWhitespaceBackground(theme.Background))
+}
+
+// renderProfileExport offers exporting the profile with the toggles set.
+func (m Model) renderProfileExport() string {
+	bg := lipgloss.NewStyle().Background(theme.Background)
+	label := bg.Foreground(theme.Subtle)
+	check := func(on bool) string {
+		if on {
+			return "[x]"
+		}
+		return "[ ]"
+	}
+	line := bg.Foreground(theme.Text).Render("Export profile " + m.profileName + "  ")
+	line += theme.KbdStyle.Render("s") + label.Render(" "+check(m.profileExportMode == profile.ExportStripped)+" strip personal")
+	line += theme.SepStyle.Render(" │ ") + theme.KbdStyle.Render("c") + label.Render(" "+check(m.profileExportMode == profile.ExportWithCV)+" include CV")
+	line += theme.SepStyle.Render(" │ ") + theme.KbdStyle.Render("enter") + label.Render(" export")
+	line += theme.SepStyle.Render(" │ ") + label.Render("esc cancel")
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Center, lipgloss.Center, line,
+		lipgloss.WithWhitespaceBackground(theme.Background))
+}
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/profile"
+)
+
+func TestModel_ProfileExport(t *testing.T) {
+	var modes []profile.ExportMode
+	export := func(mode profile.ExportMode) (string, error) {
+		modes = append(modes, mode)
+		return "/exports/profile-default.json", nil
+	}
+	var m tea.Model = drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithProfileExport(export)))
+
+	m = run(run(m, key("p")), key("s"))
+	view := strings.Join(strings.Fields(ansiRe.ReplaceAllString(m.View(), "")), " ")
+	if !strings.Contains(view, "s [x] strip personal │ c [ ] include CV │ enter export") {
+		t.Fatalf("no export toggles:\n%s", view)
+	}
+	// The toggles exclude each other; c again turns it off.
+	for _, msg := range []tea.Msg{key("c"), key("c"), key("s"), key("c"), tea.KeyMsg{Type: tea.KeyEnter}} {
+		m = run(m, msg)
+	}
+	if m.(Model).viewState != JobList {
+		t.Errorf("export left view %v", m.(Model).viewState)
+	}
+	if len(modes) != 1 || modes[0] != profile.ExportWithCV {
+		t.Errorf("exported in modes %q", modes)
+	}
+	if view := ansiRe.ReplaceAllString(m.View(), ""); !strings.Contains(view, "exported profile to profile-default.json") {
+		t.Errorf("export not reported:\n%s", view)
+	}
+}
+
+type fakeComparer struct{ compared []string }
+
+func (f *fakeComparer) All() ([]profile.Profile, error) {
+	return []profile.Profile{{ID: "backend", Name: "Backend"}, {ID: "compilers", Name: "Compilers"}, {ID: "web", Name: "Web"}}, nil
+}
+
+func (f *fakeComparer) Compare(ids []string, by int) ([]profile.Compared, error) {
+	f.compared = ids
+	jobs := fixtureJobs()
+	return []profile.Compared{
+		{Job: jobs[0], Profiles: []profile.Evaluation{{ProfileID: ids[0], Score: 90, Matches: true}, {ProfileID: ids[1], Score: 10}}},
+		{Job: jobs[2], Profiles: []profile.Evaluation{{ProfileID: ids[0], Score: 40, Matches: true}, {ProfileID: ids[1], Score: 70, Matches: true}}},
+	}, nil
+}
+
+func TestModel_CompareProfiles(t *testing.T) {
+	comparer := &fakeComparer{}
+	export := func(profile.ExportMode) (string, error) { return "profile.json", nil }
+	var m tea.Model = drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithProfileComparer(comparer), WithProfileExport(export)))
+	flat := func() string { return strings.Join(strings.Fields(ansiRe.ReplaceAllString(m.View(), "")), " ") }
+
+	m = run(m, key("p"))
+	if view := flat(); !strings.Contains(view, "[ ] Backend backend [ ] Compilers compilers") {
+		t.Fatalf("profiles not listed:\n%s", view)
+	}
+	m = run(m, key("c"))
+	if view := flat(); !strings.Contains(view, "pick two profiles") {
+		t.Errorf("compared without two picked:\n%s", view)
+	}
+	// Picking a third drops the first.
+	for _, k := range []string{" ", "j", " ", "j", " "} {
+		m = run(m, key(k))
+	}
+	if view := flat(); !strings.Contains(view, "[ ] Backend backend [1] Compilers compilers [2] Web web") {
+		t.Fatalf("picks not shown:\n%s", view)
+	}
+
+	m = run(m, key("c"))
+	if m.(Model).viewState != Compare || strings.Join(comparer.compared, ",") != "compilers,web" {
+		t.Fatalf("view %v, compared %v", m.(Model).viewState, comparer.compared)
+	}
+	view := flat()
+	for _, want := range []string{"Comparing Compilers and Web", "▼ Compilers Web Job",
+		"✓ 90 10 Senior Go Engineer · Acme", "✓ 40 ✓ 70 Backend Developer · Initech"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("missing %q in\n%s", want, view)
+		}
+	}
+	m = run(m, tea.KeyMsg{Type: tea.KeyTab})
+	if view := flat(); !strings.Contains(view, "Compilers ▼ Web Job ✓ 40 ✓ 70 Backend Developer") {
+		t.Errorf("tab did not sort by the second profile:\n%s", view)
+	}
+
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	m = run(m, key("e"))
+	if view := flat(); !strings.Contains(view, "Export profile Default s [ ] strip personal") {
+		t.Errorf("e did not open the export prompt:\n%s", view)
+	}
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.(Model).viewState != Profiles || !strings.Contains(flat(), "e export Default") {
+		t.Errorf("esc left the export prompt for %v", m.(Model).viewState)
+	}
+}
@@ -0,0 +1,500 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/export"
+	"sprayer/src/api/job"
+	"sprayer/src/ui/tui/theme"
+)
+
+// applyPrompt is the LLM prompt bulk apply writes applications from.
+const applyPrompt = "email_cold"
+
+// Applier delivers the applications bulk apply writes: Send emails one
+// and records it, Draft only saves it to the outbox, and Preview writes
+// the exact message Send would send to an .eml file.
+type Applier interface {
+	Draft(j job.Job, subject, body string) (path string, err error)
+	Send(j job.Job, subject, body string) error
+	Preview(j job.Job, subject, body string) (path string, err error)
+}
+
+// WithApplier delivers bulk-apply applications through a.
+func WithApplier(a Applier) Option {
+	return func(m *Model) { m.applier = a }
+}
+
+// CVTailor builds the CV tailored to j in the named template, or with
+// regenerate rebuilds it, and returns the PDF's path.
+type CVTailor func(j job.Job, template string, regenerate bool) (pdf string, err error)
+
+// WithCVTailor lets the queue attach a CV built for each job, with c.
+func WithCVTailor(t CVTailor) Option {
+	return func(m *Model) { m.tailorCV = t }
+}
+
+// WithCVTemplates offers the named CV templates to pick from in the
+// queue, starting at current.
+func WithCVTemplates(names []string, current string) Option {
+	return func(m *Model) { m.cvTemplates, m.cvTemplate = names, current }
+}
+
+// WithDryRun has bulk apply save drafts instead of sending.
+func WithDryRun(on bool) Option {
+	return func(m *Model) { m.dryRun = on }
+}
+
+// queueResult is what became of a job in the bulk-apply queue.
+type queueResult string
+
+const (
+	queuePending queueResult = ""
+	queueSent    queueResult = "sent"
+	queueDrafted queueResult = "drafted"
+	queueSkipped queueResult = "skipped"
+	queueFailed  queueResult = "failed"
+)
+
+type queueItem struct {
+	job    job.Job
+	result queueResult
+	err    error
+}
+
+// applyQueue walks the jobs marked for bulk apply one at a time: the
+// application for the job at pos is written, shown, then sent or
+// skipped. It outlives leaving the ApplyQueue view.
+type applyQueue struct {
+	items         []queueItem
+	pos           int // len(items) once every job is dealt with
+	subject, body string
+	template      string // why the draft is a template, if it is
+	writing       bool   // the composer has not answered yet
+	sending       bool
+	tailoring     bool  // the tailored CV is being built
+	cvErr         error // why the last CV build failed
+
+	// The .eml previewed with p, shown in preview until closed.
+	previewing  bool
+	previewPath string
+	preview     viewport.Model
+	previewErr  error // why the last preview failed
+}
+
+func (q *applyQueue) finished() bool { return q.pos >= len(q.items) }
+
+// done counts the jobs dealt with.
+func (q *applyQueue) done() int {
+	n := 0
+	for _, it := range q.items {
+		if it.result != queuePending {
+			n++
+		}
+	}
+	return n
+}
+
+// queueDraftMsg delivers the application written for the item at pos.
+type queueDraftMsg struct {
+	pos           int
+	subject, body string
+	template      string
+	err           error
+}
+
+// queueSentMsg reports sending, or drafting, the item at pos.
+type queueSentMsg struct {
+	pos int
+	err error
+}
+
+// queuePreviewMsg delivers the message previewed for the item at pos.
+type queuePreviewMsg struct {
+	pos  int
+	path string
+	text string
+	err  error
+}
+
+// queueCVMsg delivers the CV tailored for the item at pos.
+type queueCVMsg struct {
+	pos int
+	pdf string
+	err error
+}
+
+// toggleMark marks or unmarks the selected job for bulk apply.
+func (m Model) toggleMark() Model {
+	if len(m.jobs) == 0 {
+		return m
+	}
+	marked := maps.Clone(m.marked)
+	if marked == nil {
+		marked = map[string]bool{}
+	}
+	id := m.jobs[m.selectedIndex].ID
+	if marked[id] {
+		delete(marked, id)
+	} else {
+		marked[id] = true
+	}
+	m.marked = marked
+	return m
+}
+
+// startQueue begins bulk apply on the marked jobs, in list order. With
+// none marked it goes back to a queue left mid-way.
+func (m Model) startQueue() (Model, tea.Cmd) {
+	if len(m.marked) == 0 {
+		if m.queue != nil {
+			m.viewState = ApplyQueue
+		}
+		return m, nil
+	}
+	q := &applyQueue{}
+	for _, j := range m.jobs {
+		if m.marked[j.ID] {
+			q.items = append(q.items, queueItem{job: j})
+		}
+	}
+	m.queue, m.marked, m.viewState = q, nil, ApplyQueue
+	return m.writeApplication()
+}
+
+// writeApplication has the composer write the application for the
+// current item. Jobs without an address fail at once and the queue moves
+// on.
+func (m Model) writeApplication() (Model, tea.Cmd) {
+	q := m.queueCopy()
+	for !q.finished() && q.items[q.pos].job.Email == "" {
+		q.items[q.pos].result, q.items[q.pos].err = queueFailed, errors.New("no email address")
+		q.pos++
+	}
+	m.queue = q
+	if q.finished() {
+		return m, nil
+	}
+	if m.composer == nil {
+		return m.settle(q.pos, queueFailed, errors.New("no email composer configured"))
+	}
+	q.writing, q.subject, q.body, q.template = true, "", "", ""
+	compose, status, pos, j := m.composer, m.llmStatus, q.pos, q.items[q.pos].job
+	return m, func() tea.Msg {
+		subject, body, err := compose(j, applyPrompt)
+		return queueDraftMsg{pos: pos, subject: subject, body: body, template: templateNote(status), err: err}
+	}
+}
+
+// settle records what became of the item at pos and moves to the next.
+func (m Model) settle(pos int, result queueResult, err error) (Model, tea.Cmd) {
+	q := m.queueCopy()
+	if pos != q.pos || q.finished() {
+		return m, nil
+	}
+	q.items[pos].result, q.items[pos].err = result, err
+	q.pos++
+	q.writing, q.sending, q.tailoring, q.cvErr = false, false, false, nil
+	q.previewing, q.previewErr = false, nil
+	m.queue = q
+	return m.writeApplication()
+}
+
+// queueCopy copies the queue so an earlier Model keeps its own.
+func (m Model) queueCopy() *applyQueue {
+	q := *m.queue
+	q.items = append([]queueItem(nil), q.items...)
+	return &q
+}
+
+// updateQueue handles a key in the ApplyQueue view.
+func (m Model) updateQueue(msg tea.KeyMsg) (Model, tea.Cmd) {
+	q := m.queue
+	if q.previewing {
+		return m.updatePreview(msg)
+	}
+	switch msg.String() {
+	case "esc":
+		m.viewState = JobList
+		if q.finished() {
+			m.queue = nil
+		}
+	case "ctrl+c":
+		return m.quit()
+	case "enter", "y":
+		if q.finished() || q.writing || q.sending || q.tailoring {
+			return m, nil
+		}
+		return m.deliver()
+	case "n":
+		if !q.finished() && !q.sending {
+			return m.settle(q.pos, queueSkipped, nil)
+		}
+	case "c", "C":
+		if !q.finished() && !q.sending && !q.tailoring {
+			return m.tailor(msg.String() == "C")
+		}
+	case "p":
+		if !q.finished() && !q.writing && !q.sending && !q.tailoring {
+			return m.previewApplication()
+		}
+	case "t":
+		if !q.finished() && !q.sending && !q.tailoring && len(m.cvTemplates) > 1 {
+			i := slices.Index(m.cvTemplates, m.cvTemplate)
+			m.cvTemplate = m.cvTemplates[(i+1)%len(m.cvTemplates)]
+			return m.tailor(true)
+		}
+	}
+	return m, nil
+}
+
+// previewApplication has the applier write the message on show to an
+// .eml file, then shows it.
+func (m Model) previewApplication() (Model, tea.Cmd) {
+	if m.applier == nil {
+		return m, nil
+	}
+	a, pos, j, subject, body := m.applier, m.queue.pos, m.queue.items[m.queue.pos].job, m.queue.subject, m.queue.body
+	return m, func() tea.Msg {
+		path, err := a.Preview(j, subject, body)
+		if err != nil {
+			return queuePreviewMsg{pos: pos, err: err}
+		}
+		raw, err := os.ReadFile(path)
+		return queuePreviewMsg{pos: pos, path: path, text: strings.ReplaceAll(string(raw), "\r\n", "\n"), err: err}
+	}
+}
+
+// updatePreview scrolls the previewed message; enter sends it and esc
+// or p closes it.
+func (m Model) updatePreview(msg tea.KeyMsg) (Model, tea.Cmd) {
+	q := m.queueCopy()
+	m.queue = q
+	switch msg.String() {
+	case "ctrl+c":
+		return m.quit()
+	case "esc", "p":
+		q.previewing = false
+	case "enter", "y":
+		q.previewing = false
+		return m.deliver()
+	default:
+		q.preview, _ = q.preview.Update(msg)
+	}
+	return m, nil
+}
+
+// tailor builds the CV for the current item in the background; C, and
+// picking another template with t, rebuild one made before.
+func (m Model) tailor(regenerate bool) (Model, tea.Cmd) {
+	q := m.queueCopy()
+	m.queue = q
+	if m.tailorCV == nil {
+		q.cvErr = errors.New("CV tailoring is not configured")
+		return m, nil
+	}
+	q.tailoring, q.cvErr = true, nil
+	build, pos, j, tmpl := m.tailorCV, q.pos, q.items[q.pos].job, m.cvTemplate
+	return m, func() tea.Msg {
+		pdf, err := build(j, tmpl, regenerate)
+		return queueCVMsg{pos: pos, pdf: pdf, err: err}
+	}
+}
+
+// deliver sends the application on show, or in a dry run drafts it.
+func (m Model) deliver() (Model, tea.Cmd) {
+	q := m.queueCopy()
+	if m.applier == nil {
+		m.queue = q
+		return m.settle(q.pos, queueFailed, errors.New("sending is not configured"))
+	}
+	q.sending = true
+	m.queue = q
+	a, dry, pos, j, subject, body := m.applier, m.dryRun, q.pos, q.items[q.pos].job, q.subject, q.body
+	return m, func() tea.Msg {
+		if dry {
+			_, err := a.Draft(j, subject, body)
+			return queueSentMsg{pos: pos, err: err}
+		}
+		return queueSentMsg{pos: pos, err: a.Send(j, subject, body)}
+	}
+}
+
+// queueReceive handles the composer's and applier's answers.
+func (m Model) queueReceive(msg tea.Msg) (Model, tea.Cmd) {
+	if m.queue == nil {
+		return m, nil
+	}
+	switch msg := msg.(type) {
+	case queueDraftMsg:
+		if msg.pos != m.queue.pos {
+			return m, nil
+		}
+		if msg.err != nil {
+			return m.settle(msg.pos, queueFailed, msg.err)
+		}
+		q := m.queueCopy()
+		q.subject, q.body, q.template, q.writing = msg.subject, msg.body, msg.template, false
+		m.queue = q
+	case queuePreviewMsg:
+		if msg.pos != m.queue.pos {
+			return m, nil
+		}
+		q := m.queueCopy()
+		m.queue = q
+		if q.previewErr = msg.err; msg.err != nil {
+			return m, nil
+		}
+		q.previewing, q.previewPath = true, msg.path
+		q.preview = viewport.New(max(m.width-4, 20), max(m.height-10, 3))
+		q.preview.SetContent(msg.text)
+	case queueCVMsg:
+		if msg.pos != m.queue.pos {
+			return m, nil
+		}
+		q := m.queueCopy()
+		q.tailoring, q.cvErr = false, msg.err
+		if msg.err == nil {
+			q.items[msg.pos].job.CVPath = msg.pdf
+		}
+		m.queue = q
+	case queueSentMsg:
+		switch {
+		case msg.err != nil:
+			return m.settle(msg.pos, queueFailed, msg.err)
+		case m.dryRun:
+			return m.settle(msg.pos, queueDrafted, nil)
+		default:
+			return m.settle(msg.pos, queueSent, nil)
+		}
+	}
+	return m, nil
+}
+
+// cvErrLines shows why the tailored CV failed: for a LaTeX error, the
+// end of its log.
+func cvErrLines(err error, label lipgloss.Style) []string {
+	var ce *export.CompileError
+	if !errors.As(err, &ce) {
+		return []string{label.Render("CV       ") + theme.ErrorStyle.Render("tailored CV failed: "+err.Error())}
+	}
+	lines := []string{label.Render("CV       ") + theme.ErrorStyle.Render(fmt.Sprintf("tailored CV failed: %s: %v", ce.Engine, ce.Err))}
+	log := strings.Split(ce.Log, "\n")
+	for _, l := range log[max(len(log)-cvLogLines, 0):] {
+		lines = append(lines, label.Render("  "+l))
+	}
+	return lines
+}
+
+// cvLogLines is how much of a failed LaTeX log the queue shows.
+const cvLogLines = 8
+
+// queueNotice is the status bar's note on a queue left mid-way.
+func (m Model) queueNotice() string {
+	if m.queue == nil || m.viewState == ApplyQueue || m.queue.finished() {
+		return ""
+	}
+	return theme.WarningStyle.Render(fmt.Sprintf("bulk apply %d/%d · A resumes", m.queue.done(), len(m.queue.items)))
+}
+
+// renderQueue shows the application on show in the bulk-apply queue, or
+// once every job is dealt with, what became of each.
+func (m Model) renderQueue() string {
+	bg := lipgloss.NewStyle().Background(theme.Background)
+	label := bg.Foreground(theme.Subtle)
+	text := bg.Foreground(theme.Text)
+	q := m.queue
+
+	head := bg.Foreground(theme.Bright).Bold(true).Render("Bulk apply") +
+		theme.ProgressStyle.Render(fmt.Sprintf("  %d/%d done", q.done(), len(q.items)))
+	if m.dryRun {
+		head += theme.WarningStyle.Render("  dry run: drafts only")
+	}
+	lines := []string{head, ""}
+
+	if q.finished() {
+		counts := map[queueResult]int{}
+		for _, it := range q.items {
+			counts[it.result]++
+			mark := theme.SuccessStyle.Render("✓ " + string(it.result))
+			switch it.result {
+			case queueSkipped:
+				mark = label.Render("- " + string(it.result))
+			case queueFailed:
+				mark = theme.ErrorStyle.Render("✗ " + string(it.result))
+			}
+			line := mark + text.Render("  "+it.job.Title+" @ "+it.job.Company)
+			if it.err != nil {
+				line += theme.ErrorStyle.Render("  " + it.err.Error())
+			}
+			lines = append(lines, line)
+		}
+		var sum []string
+		for _, r := range []queueResult{queueSent, queueDrafted, queueSkipped, queueFailed} {
+			if counts[r] > 0 {
+				sum = append(sum, fmt.Sprintf("%d %s", counts[r], r))
+			}
+		}
+		lines = append(lines, "", label.Render(strings.Join(sum, ", ")+" · esc back to the list"))
+	} else {
+		j := q.items[q.pos].job
+		lines = append(lines,
+			bg.Foreground(theme.Bright).Render(j.Title)+theme.JobCompanyStyle.Render(" @ "+j.Company),
+			label.Render("To       ")+text.Render(j.Email),
+			label.Render("Subject  ")+text.Render(q.subject))
+		switch {
+		case q.tailoring:
+			lines = append(lines, label.Render("CV       ")+text.Render("Building tailored CV…"))
+		case q.cvErr != nil:
+			lines = append(lines, cvErrLines(q.cvErr, label)...)
+		case j.CVPath != "":
+			lines = append(lines, label.Render("CV       ")+theme.SuccessStyle.Render("tailored: "+j.CVPath))
+		default:
+			lines = append(lines, label.Render("CV       ")+text.Render("profile CV"))
+		}
+		if len(m.cvTemplates) > 1 {
+			lines = append(lines, label.Render("Template ")+text.Render(m.cvTemplate)+label.Render("  t next"))
+		}
+		lines = append(lines, "")
+		switch {
+		case q.previewing:
+			lines = append(lines, label.Render("Preview  ")+text.Render(q.previewPath), q.preview.View())
+		case q.writing:
+			lines = append(lines, label.Render("Writing the application…"))
+		default:
+			if q.template != "" {
+				lines = append(lines, renderTemplateNote(q.template, label)...)
+			}
+			for _, l := range strings.Split(strings.TrimRight(q.body, "\n"), "\n") {
+				lines = append(lines, text.Render(l))
+			}
+		}
+		verb, doing := "send", "Sending…"
+		if m.dryRun {
+			verb, doing = "save draft", "Saving the draft…"
+		}
+		if q.previewErr != nil {
+			lines = append(lines, "", theme.ErrorStyle.Render("preview failed: "+q.previewErr.Error()))
+		}
+		switch {
+		case q.sending:
+			lines = append(lines, "", label.Render(doing))
+		case q.previewing:
+			lines = append(lines, "", label.Render("↑/↓ scroll · enter "+verb+" · esc close the preview"))
+		default:
+			lines = append(lines, "", label.Render("enter "+verb+" · n skip · p preview .eml · c tailor CV (C rebuild) · esc back to the list (the queue is kept)"))
+		}
+	}
+	block := bg.Padding(1, 2).Width(m.width).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Left, lipgloss.Top, block,
+		lipgloss.WithWhitespaceBackground(theme.Background))
+}
@@ -0,0 +1,246 @@
+package tui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/export"
+	"sprayer/src/api/job"
+)
+
+// fakeApplier records what bulk apply delivers.
+type fakeApplier struct {
+	sent, drafted []string
+	cvs           []string // the CV each sent job had linked
+	dir           string   // where Preview writes
+	fail          error
+}
+
+func (a *fakeApplier) Draft(j job.Job, subject, body string) (string, error) {
+	a.drafted = append(a.drafted, j.ID+": "+subject)
+	return "/outbox/" + j.ID + ".eml", nil
+}
+
+func (a *fakeApplier) Send(j job.Job, subject, body string) error {
+	if a.fail != nil {
+		return a.fail
+	}
+	a.sent = append(a.sent, j.ID+": "+subject)
+	a.cvs = append(a.cvs, j.CVPath)
+	return nil
+}
+
+// Preview writes what Send would send, the subject and body, to a file.
+func (a *fakeApplier) Preview(j job.Job, subject, body string) (string, error) {
+	if a.fail != nil {
+		return "", a.fail
+	}
+	path := filepath.Join(a.dir, j.ID+".eml")
+	return path, os.WriteFile(path, []byte("To: "+j.Email+"\r\nSubject: "+subject+"\r\n\r\n"+body), 0644)
+}
+
+func queueModel(a Applier, opts ...Option) tea.Model {
+	jobs := fixtureJobs()
+	jobs[0].Email, jobs[1].Email = "jobs@acme.com", "hiring@globex.com"
+	composer := func(j job.Job, prompt string) (string, string, error) {
+		if prompt != "email_cold" {
+			return "", "", errors.New("wrong prompt " + prompt)
+		}
+		return "Application: " + j.Title, "Dear " + j.Company + ",\n", nil
+	}
+	opts = append(opts, WithJobSource(fixtureSource(jobs)), WithComposer(composer), WithApplier(a))
+	return drive(NewModel(opts...))
+}
+
+func plain(m tea.Model) string {
+	return strings.Join(strings.Fields(ansiRe.ReplaceAllString(m.View(), "")), " ")
+}
+
+func TestModel_BulkApply(t *testing.T) {
+	a := &fakeApplier{}
+	m := queueModel(a)
+
+	for _, k := range []string{" ", "j", " ", "j", " "} {
+		m = run(m, key(k))
+	}
+	if view := plain(m); !strings.Contains(view, "+ [92]") || !strings.Contains(view, "+ [64]") {
+		t.Fatalf("marks not shown:\n%s", view)
+	}
+
+	m = run(m, key("A"))
+	if view := plain(m); !strings.Contains(view, "Bulk apply 0/3 done") || !strings.Contains(view, "Subject Application: Senior Go Engineer") {
+		t.Fatalf("first application not shown:\n%s", view)
+	}
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if len(a.sent) != 1 || a.sent[0] != "1: Application: Senior Go Engineer" {
+		t.Errorf("sent = %v", a.sent)
+	}
+
+	// Leaving mid-way keeps the queue.
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if view := plain(m); m.(Model).viewState != JobList || !strings.Contains(view, "bulk apply 1/3 · A resumes") {
+		t.Fatalf("queue not kept on leaving:\n%s", view)
+	}
+	m = run(m, key("A"))
+	if view := plain(m); !strings.Contains(view, "1/3 done") || !strings.Contains(view, "Application: Platform Engineer") {
+		t.Fatalf("queue not resumed:\n%s", view)
+	}
+
+	// Skipping the second reaches the third, which has no address.
+	m = run(m, key("n"))
+	view := plain(m)
+	for _, want := range []string{"3/3 done", "✓ sent Senior Go Engineer @ Acme", "- skipped Platform Engineer",
+		"✗ failed Backend Developer @ Initech no email address", "1 sent, 1 skipped, 1 failed"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("results lack %q:\n%s", want, view)
+		}
+	}
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.(Model).queue != nil {
+		t.Error("finished queue kept")
+	}
+}
+
+func TestModel_BulkApply_DryRunAndFailures(t *testing.T) {
+	a := &fakeApplier{}
+	m := queueModel(a, WithDryRun(true))
+	m = run(m, key(" "))
+	m = run(m, key("A"))
+	if !strings.Contains(plain(m), "dry run: drafts only") {
+		t.Errorf("dry run not shown:\n%s", plain(m))
+	}
+	m = run(m, key("y"))
+	if len(a.sent) != 0 || len(a.drafted) != 1 || !strings.Contains(plain(m), "✓ drafted Senior Go Engineer") {
+		t.Errorf("dry run sent %v, drafted %v:\n%s", a.sent, a.drafted, plain(m))
+	}
+
+	a = &fakeApplier{fail: errors.New("535 authentication failed")}
+	m = queueModel(a)
+	m = run(m, key(" "))
+	m = run(m, key("A"))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.Contains(plain(m), "✗ failed Senior Go Engineer @ Acme 535 authentication failed") {
+		t.Errorf("send failure not recorded:\n%s", plain(m))
+	}
+}
+
+func TestModel_BulkApply_Preview(t *testing.T) {
+	a := &fakeApplier{dir: t.TempDir()}
+	m := queueModel(a)
+	m = run(m, key(" "))
+	m = run(m, key("A"))
+	m = run(m, key("p"))
+	view := plain(m)
+	for _, want := range []string{"Preview " + filepath.Join(a.dir, "1.eml"), "To: jobs@acme.com",
+		"Subject: Application: Senior Go Engineer", "Dear Acme,", "enter send · esc close the preview"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("preview lacks %q:\n%s", want, view)
+		}
+	}
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if view := plain(m); strings.Contains(view, "Preview") || m.(Model).viewState != ApplyQueue {
+		t.Errorf("preview not closed:\n%s", view)
+	}
+	m = run(m, key("p"))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if len(a.sent) != 1 || !strings.Contains(plain(m), "1/1 done") {
+		t.Errorf("enter in the preview did not send: %v\n%s", a.sent, plain(m))
+	}
+
+	a = &fakeApplier{dir: t.TempDir(), fail: errors.New("no SMTP")}
+	m = queueModel(a)
+	m = run(m, key(" "))
+	m = run(m, key("A"))
+	m = run(m, key("p"))
+	if view := plain(m); !strings.Contains(view, "preview failed: no SMTP") {
+		t.Errorf("preview failure not shown:\n%s", view)
+	}
+}
+
+func TestModel_BulkApply_TailorCV(t *testing.T) {
+	a := &fakeApplier{}
+	var builds []bool
+	tailor := func(j job.Job, template string, regenerate bool) (string, error) {
+		builds = append(builds, regenerate)
+		if j.ID != "1" {
+			return "", errors.New("pdflatex is not installed")
+		}
+		return "/outputs/cv/" + j.ID + "/cv.pdf", nil
+	}
+	m := queueModel(a, WithCVTailor(tailor))
+	m = run(m, key(" "))
+	m = run(m, key("j"))
+	m = run(m, key(" "))
+	m = run(m, key("A"))
+	if !strings.Contains(plain(m), "CV profile CV") {
+		t.Fatalf("CV line missing:\n%s", plain(m))
+	}
+
+	building, _ := m.Update(key("c"))
+	if !strings.Contains(plain(building), "Building tailored CV…") {
+		t.Errorf("build not shown:\n%s", plain(building))
+	}
+	if _, cmd := building.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Error("sent while the CV was building")
+	}
+	m = run(m, key("c"))
+	m = run(m, key("C"))
+	if !strings.Contains(plain(m), "tailored: /outputs/cv/1/cv.pdf") || len(builds) != 2 || builds[0] || !builds[1] {
+		t.Fatalf("builds %v:\n%s", builds, plain(m))
+	}
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if len(a.cvs) != 1 || a.cvs[0] != "/outputs/cv/1/cv.pdf" {
+		t.Errorf("sent with CVs %v", a.cvs)
+	}
+
+	m = run(m, key("c"))
+	if !strings.Contains(plain(m), "tailored CV failed: pdflatex is not installed") {
+		t.Errorf("build failure not shown:\n%s", plain(m))
+	}
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if len(a.cvs) != 2 || a.cvs[1] != "" {
+		t.Errorf("sent with CVs %v", a.cvs)
+	}
+}
+
+func TestModel_BulkApply_TailorCVLog(t *testing.T) {
+	tailor := func(j job.Job, template string, regenerate bool) (string, error) {
+		return "", &export.CompileError{Engine: "pdflatex", Err: errors.New("exit status 1"),
+			Log: "(./cv.tex\n! Undefined control sequence.\nl.12 \\hfil"}
+	}
+	m := queueModel(&fakeApplier{}, WithCVTailor(tailor))
+	m = run(m, key(" "))
+	m = run(m, key("A"))
+	m = run(m, key("c"))
+	view := plain(m)
+	for _, want := range []string{"tailored CV failed: pdflatex: exit status 1", "! Undefined control sequence. l.12"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view lacks %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestModel_BulkApply_PickCVTemplate(t *testing.T) {
+	var built []string
+	tailor := func(j job.Job, template string, regenerate bool) (string, error) {
+		built = append(built, template)
+		return "/outputs/cv/" + j.ID + "/cv.pdf", nil
+	}
+	m := queueModel(&fakeApplier{}, WithCVTailor(tailor), WithCVTemplates([]string{"classic", "compact", "mine"}, "compact"))
+	m = run(m, key(" "))
+	m = run(m, key("A"))
+	if !strings.Contains(plain(m), "Template compact t next") {
+		t.Fatalf("template not shown:\n%s", plain(m))
+	}
+	m = run(m, key("c"))
+	m = run(m, key("t"))
+	m = run(m, key("t"))
+	if strings.Join(built, ",") != "compact,mine,classic" || !strings.Contains(plain(m), "Template classic") {
+		t.Errorf("built %v:\n%s", built, plain(m))
+	}
+}
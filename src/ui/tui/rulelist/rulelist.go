@@ -0,0 +1,102 @@
+// Package rulelist is a list of ingest rules where each can be switched
+// on and off.
+package rulelist
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/rules"
+	"sprayer/src/ui/tui/theme"
+)
+
+// Model lists rules in evaluation order. Space or enter toggles the
+// selected rule through Toggle, which persists it; q or esc leaves.
+type Model struct {
+	Rules         []rules.Rule
+	SelectedIndex int
+	Width         int
+	Height        int
+	// Toggle stores a rule's new enabled state. The list changes only
+	// once it succeeds.
+	Toggle func(name string, enabled bool) error
+
+	err error
+}
+
+// New lists rs, saving toggles through toggle.
+func New(rs []rules.Rule, toggle func(name string, enabled bool) error) Model {
+	return Model{Rules: rs, Toggle: toggle, Width: 80, Height: 24}
+}
+
+func (m Model) Init() tea.Cmd { return nil }
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "j", "down":
+			m.SelectedIndex = min(m.SelectedIndex+1, max(len(m.Rules)-1, 0))
+		case "k", "up":
+			m.SelectedIndex = max(m.SelectedIndex-1, 0)
+		case " ", "enter", "x":
+			m.toggle()
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		}
+	case tea.WindowSizeMsg:
+		m.Width, m.Height = msg.Width, msg.Height
+	}
+	return m, nil
+}
+
+func (m *Model) toggle() {
+	if m.SelectedIndex >= len(m.Rules) {
+		return
+	}
+	r := &m.Rules[m.SelectedIndex]
+	m.err = nil
+	if m.Toggle != nil {
+		if m.err = m.Toggle(r.Name, !r.Enabled); m.err != nil {
+			return
+		}
+	}
+	r.Enabled = !r.Enabled
+}
+
+func (m Model) View() string {
+	header := theme.ModalTitleStyle.Render("Ingest rules")
+	var lines []string
+	if len(m.Rules) == 0 {
+		lines = append(lines, theme.EmptySubStyle.Render(`No rules yet. Add one with: sprayer rules add -name NAME -when EXPR -do ACTIONS`))
+	}
+	for i, r := range m.Rules {
+		style := theme.JobItemStyle
+		if i == m.SelectedIndex {
+			style = theme.JobItemSelectedStyle
+		}
+		lines = append(lines, style.Width(m.Width).Render(m.formatRule(r)))
+	}
+
+	footer := theme.ModalHintStyle.Render("space toggle · j/k move · q quit")
+	if m.err != nil {
+		footer = theme.ErrorStyle.Render("Error: " + m.err.Error())
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, header, "", strings.Join(lines, "\n"), "", footer)
+}
+
+func (m Model) formatRule(r rules.Rule) string {
+	box := "[ ]"
+	if r.Enabled {
+		box = "[x]"
+	}
+	actions := strings.ReplaceAll(rules.FormatActions(r.Actions), "\n", ", ")
+	line := fmt.Sprintf("%s %s  %s → %s  (%d hits)", box, r.Name, r.When, actions, r.Hits)
+	if runes := []rune(line); m.Width > 3 && len(runes) > m.Width {
+		line = string(runes[:m.Width-3]) + "..."
+	}
+	return line
+}
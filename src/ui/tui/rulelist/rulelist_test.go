@@ -0,0 +1,78 @@
+package rulelist
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/rules"
+)
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func press(m Model, keys ...string) Model {
+	for _, k := range keys {
+		next, _ := m.Update(key(k))
+		m = next.(Model)
+	}
+	return m
+}
+
+func TestToggle(t *testing.T) {
+	saved := map[string]bool{}
+	m := New([]rules.Rule{
+		{Name: "on-call", When: "keyword:on-call", Enabled: true},
+		{Name: "agencies", When: "company:recruit", Enabled: true},
+	}, func(name string, enabled bool) error {
+		saved[name] = enabled
+		return nil
+	})
+
+	m = press(m, "down", " ")
+	if m.Rules[1].Enabled || saved["agencies"] {
+		t.Fatalf("agencies still enabled: rules %+v, saved %v", m.Rules, saved)
+	}
+	if !strings.Contains(m.View(), "[ ] agencies") || !strings.Contains(m.View(), "[x] on-call") {
+		t.Errorf("view:\n%s", m.View())
+	}
+	m = press(m, "enter")
+	if !m.Rules[1].Enabled || !saved["agencies"] {
+		t.Errorf("second toggle did not re-enable")
+	}
+	m = press(m, "down", "down")
+	if m.SelectedIndex != 1 {
+		t.Errorf("selection ran past the end: %d", m.SelectedIndex)
+	}
+}
+
+func TestToggle_FailureKeepsState(t *testing.T) {
+	m := New([]rules.Rule{{Name: "on-call", When: "keyword:on-call", Enabled: true}},
+		func(string, bool) error { return errors.New("database is locked") })
+	m = press(m, " ")
+	if !m.Rules[0].Enabled {
+		t.Error("rule shown disabled though saving failed")
+	}
+	if !strings.Contains(m.View(), "database is locked") {
+		t.Errorf("error not shown:\n%s", m.View())
+	}
+}
+
+func TestQuit(t *testing.T) {
+	_, cmd := New(nil, nil).Update(key("q"))
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Error("q should quit")
+	}
+	if !strings.Contains(New(nil, nil).View(), "No rules yet") {
+		t.Error("empty list should say how to add a rule")
+	}
+}
@@ -0,0 +1,331 @@
+package tui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/scraper"
+	"sprayer/src/ui/tui/theme"
+)
+
+// IncrementalScraper streams what a scrape finds;
+// *scraper.IncrementalScraper satisfies it. Its channels close when the
+// scrape ends, stopped or not.
+type IncrementalScraper interface {
+	Start()
+	Stop()
+	Progress() <-chan scraper.ScraperProgress
+	Results() <-chan job.Job
+	Errors() <-chan error
+}
+
+// WithScraper scrapes when s is pressed: start sets up a scrape, and the
+// jobs it streams are stored by save, a batch at a time (see saveBatch),
+// which returns the jobs as stored.
+func WithScraper(start func() IncrementalScraper, save func([]job.Job) ([]job.Job, error)) Option {
+	return func(m *Model) { m.startScrape, m.saveScraped = start, save }
+}
+
+// A scrape's jobs are saved, and then listed, in batches: once saveBatch
+// have gathered, once the first of them has waited saveEvery, and when
+// the scrape ends. Each batch is a transaction of its own, so a crash
+// mid-scrape loses at most the one being gathered.
+const saveBatch = 50
+
+var saveEvery = 2 * time.Second
+
+// scrapeRun is a scrape in progress. Its channels are read by one
+// command at a time, each reading the next event; a closed channel is
+// set to nil so the select skips it.
+type scrapeRun struct {
+	s        IncrementalScraper
+	save     func([]job.Job) ([]job.Job, error)
+	progress <-chan scraper.ScraperProgress
+	results  <-chan job.Job
+	errs     <-chan error
+
+	// pending is the batch being gathered, first added at pendingSince.
+	// A job streamed again before it is saved replaces its earlier copy.
+	// mu guards both: next gathers them while a stop may flush them.
+	mu           sync.Mutex
+	pending      []job.Job
+	pendingSince time.Time
+
+	// What the Scraping view shows.
+	source   string
+	status   string
+	found    int
+	errors   []string
+	saveErr  error
+	stopping bool
+}
+
+// scraperProgressMsg, scraperJobMsg and scraperErrMsg each carry one
+// event of run; scraperCompleteMsg says its channels have all closed.
+type (
+	scraperProgressMsg struct {
+		run *scrapeRun
+		p   scraper.ScraperProgress
+	}
+	scraperJobMsg struct {
+		run     *scrapeRun
+		jobs    []job.Job // as saved
+		err     error
+		stopped bool // flushed by stopping, outside next
+	}
+	scraperErrMsg struct {
+		run *scrapeRun
+		err error
+	}
+	scraperCompleteMsg struct{ run *scrapeRun }
+)
+
+// next waits for the run's next event. Found jobs are saved before they
+// are reported, a batch at a time, off the update loop.
+func (r *scrapeRun) next() tea.Msg {
+	for r.progress != nil || r.results != nil || r.errs != nil {
+		var due <-chan time.Time
+		if n, since := r.gathered(); n > 0 {
+			due = time.After(time.Until(since.Add(saveEvery)))
+		}
+		select {
+		case p, ok := <-r.progress:
+			if !ok {
+				r.progress = nil
+				continue
+			}
+			return scraperProgressMsg{r, p}
+		case j, ok := <-r.results:
+			if !ok {
+				r.results = nil
+				if n, _ := r.gathered(); n > 0 {
+					return r.flush()
+				}
+				continue
+			}
+			if r.add(j) >= saveBatch {
+				return r.flush()
+			}
+		case err, ok := <-r.errs:
+			if !ok {
+				r.errs = nil
+				continue
+			}
+			return scraperErrMsg{r, err}
+		case <-due:
+			return r.flush()
+		}
+	}
+	return scraperCompleteMsg{r}
+}
+
+// add gathers j into the pending batch and returns the batch's size.
+func (r *scrapeRun) add(j job.Job) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pending) == 0 {
+		r.pendingSince = time.Now()
+	}
+	for i, k := range r.pending {
+		if k.ID == j.ID {
+			r.pending[i] = j
+			return len(r.pending)
+		}
+	}
+	r.pending = append(r.pending, j)
+	return len(r.pending)
+}
+
+// gathered is the pending batch's size and when it was started.
+func (r *scrapeRun) gathered() (int, time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.pending), r.pendingSince
+}
+
+// flush saves the pending batch and reports it.
+func (r *scrapeRun) flush() tea.Msg {
+	r.mu.Lock()
+	jobs := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+	if r.save != nil {
+		saved, err := r.save(jobs)
+		if err != nil {
+			return scraperJobMsg{run: r, jobs: jobs, err: err}
+		}
+		jobs = saved
+	}
+	return scraperJobMsg{run: r, jobs: jobs}
+}
+
+// startScraping opens the Scraping view, starting a scrape unless one is
+// running already.
+func (m Model) startScraping() (Model, tea.Cmd) {
+	m.viewState = Scraping
+	if m.startScrape == nil || m.scrape != nil {
+		return m, nil
+	}
+	s := m.startScrape()
+	r := &scrapeRun{s: s, save: m.saveScraped, progress: s.Progress(), results: s.Results(), errs: s.Errors()}
+	s.Start()
+	m.scrape = r
+	m.scraping, m.scrapeDone, m.scrapeTotal, m.newJobs = true, 0, 0, 0
+	return m, r.next
+}
+
+// stopScraping asks the running scrape to stop and saves the batch it
+// was gathering. Its channels are still drained until they close, so the
+// scraper's goroutine can finish.
+func (m Model) stopScraping() (Model, tea.Cmd) {
+	r := m.scrape
+	if r == nil || r.stopping {
+		return m, nil
+	}
+	r.stopping = true
+	r.s.Stop()
+	return m, func() tea.Msg {
+		if n, _ := r.gathered(); n == 0 {
+			return nil
+		}
+		msg := r.flush().(scraperJobMsg)
+		msg.stopped = true
+		return msg
+	}
+}
+
+// quit stops a running scrape, saving what it gathered, and then quits.
+func (m Model) quit() (Model, tea.Cmd) {
+	m, flush := m.stopScraping()
+	if flush == nil {
+		return m, tea.Quit
+	}
+	return m, func() tea.Msg {
+		flush()
+		return tea.Quit()
+	}
+}
+
+// scrapeReceive handles an event of the running scrape; those of a
+// scrape that has since ended are dropped.
+func (m Model) scrapeReceive(msg tea.Msg) (Model, tea.Cmd) {
+	r := m.scrape
+	switch msg := msg.(type) {
+	case scraperProgressMsg:
+		if msg.run != r {
+			return m, nil
+		}
+		if msg.p.Source != "" {
+			r.source = msg.p.Source
+		}
+		r.status = msg.p.Status
+		if msg.p.TotalSources > 0 {
+			m.scrapeTotal = msg.p.TotalSources
+		}
+		if msg.p.Status == "Complete" {
+			m.scrapeDone = msg.p.CurrentSource
+		}
+		return m, r.next
+	case scraperJobMsg:
+		// A stop's flush may land after the scrape has ended.
+		if msg.run != r && !(msg.stopped && msg.run == m.lastScrape) {
+			return m, nil
+		}
+		r := msg.run
+		if msg.err != nil {
+			r.saveErr = msg.err
+		}
+		var cmds []tea.Cmd
+		for _, j := range msg.jobs {
+			m = m.addScraped(j)
+			r.found++
+			m.newJobs++
+			cmds = append(cmds, m.notifyJob(j))
+		}
+		if !msg.stopped {
+			cmds = append(cmds, r.next)
+		}
+		return m, tea.Batch(cmds...)
+	case scraperErrMsg:
+		if msg.run != r {
+			return m, nil
+		}
+		r.errors = append(r.errors, msg.err.Error())
+		return m, r.next
+	case scraperCompleteMsg:
+		if msg.run != r {
+			return m, nil
+		}
+		m.scrape, m.lastScrape, m.scraping = nil, r, false
+		if r.stopping {
+			r.status = "Stopped"
+		} else {
+			r.status = "Finished"
+		}
+	}
+	return m, nil
+}
+
+// addScraped adds j by score among the loaded jobs, replacing an earlier
+// copy, and keeps the cursor on the job it was on.
+func (m Model) addScraped(j job.Job) Model {
+	jobs := make([]job.Job, 0, len(m.all)+1)
+	for _, k := range m.all {
+		if k.ID != j.ID {
+			jobs = append(jobs, k)
+		}
+	}
+	at := len(jobs)
+	for i, k := range jobs {
+		if j.Score > k.Score {
+			at = i
+			break
+		}
+	}
+	m.all = append(jobs[:at], append([]job.Job{j}, jobs[at:]...)...)
+	return m.refilter()
+}
+
+// renderScraping shows the running scrape, or how the last one ended.
+func (m Model) renderScraping() string {
+	bg := lipgloss.NewStyle().Background(theme.Background)
+	label := bg.Foreground(theme.Subtle)
+	text := bg.Foreground(theme.Text)
+
+	r := m.scrape
+	if r == nil {
+		r = m.lastScrape
+	}
+	lines := []string{bg.Foreground(theme.Bright).Bold(true).Render("Scraping"), ""}
+	if r == nil {
+		lines = append(lines, label.Render("Starting…"))
+	} else {
+		progress := r.status
+		if m.scrapeTotal > 0 {
+			progress = fmt.Sprintf("%d/%d sources · %s", m.scrapeDone, m.scrapeTotal, r.status)
+		}
+		if r.source != "" && m.scrape != nil {
+			progress += " " + r.source
+		}
+		lines = append(lines, text.Render(progress), text.Render(plural(r.found, "job", "jobs")+" found"))
+		if r.saveErr != nil {
+			lines = append(lines, theme.ErrorStyle.Render("not saved: "+r.saveErr.Error()))
+		}
+		for _, e := range r.errors {
+			lines = append(lines, theme.WarningStyle.Render(e))
+		}
+	}
+	hint := "esc back"
+	if m.scrape != nil && !m.scrape.stopping {
+		hint = "esc stop"
+	}
+	lines = append(lines, "", label.Render(hint))
+	block := bg.Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Left, lipgloss.Top, block,
+		lipgloss.WithWhitespaceBackground(theme.Background))
+}
@@ -0,0 +1,295 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/scraper"
+)
+
+// stubScraper streams jobs from one source, then the errors, and closes
+// its channels; Stop ends it early. With hold set it runs until stopped.
+type stubScraper struct {
+	jobs     []job.Job
+	hold     bool
+	errs     []error
+	progress chan scraper.ScraperProgress
+	results  chan job.Job
+	errors   chan error
+	stop     chan struct{}
+	stopped  bool
+}
+
+func newStubScraper(jobs []job.Job, errs ...error) *stubScraper {
+	return &stubScraper{jobs: jobs, errs: errs, progress: make(chan scraper.ScraperProgress),
+		results: make(chan job.Job), errors: make(chan error), stop: make(chan struct{})}
+}
+
+func (s *stubScraper) Start() {
+	go func() {
+		defer close(s.progress)
+		defer close(s.results)
+		defer close(s.errors)
+		s.progress <- scraper.ScraperProgress{Source: "Stub", TotalSources: 1, CurrentSource: 1, Status: "Scraping"}
+		s.progress <- scraper.ScraperProgress{Source: "Stub", TotalSources: 1, CurrentSource: 1, Status: "Complete", JobsFound: len(s.jobs)}
+		for _, j := range s.jobs {
+			select {
+			case s.results <- j:
+			case <-s.stop:
+				return
+			}
+		}
+		for _, err := range s.errs {
+			s.errors <- err
+		}
+		if s.hold {
+			<-s.stop
+		}
+	}()
+}
+
+func (s *stubScraper) Stop() {
+	if !s.stopped {
+		s.stopped = true
+		close(s.stop)
+	}
+}
+
+func (s *stubScraper) Progress() <-chan scraper.ScraperProgress { return s.progress }
+func (s *stubScraper) Results() <-chan job.Job                  { return s.results }
+func (s *stubScraper) Errors() <-chan error                     { return s.errors }
+
+// pump feeds m the messages of cmd and of the commands they return,
+// until none are left.
+func pump(m tea.Model, cmd tea.Cmd) tea.Model {
+	for queue := []tea.Cmd{cmd}; len(queue) > 0; queue = queue[1:] {
+		if queue[0] == nil {
+			continue
+		}
+		msg := queue[0]()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			queue = append(queue, batch...)
+			continue
+		}
+		var next tea.Cmd
+		m, next = m.Update(msg)
+		queue = append(queue, next)
+	}
+	return m
+}
+
+func TestModel_Scrape_StreamsJobsIntoList(t *testing.T) {
+	found := []job.Job{
+		{ID: "9", Title: "Staff Go Engineer", Company: "Hooli", Score: 95},
+		{ID: "3", Title: "Backend Developer", Company: "Initech", Score: 70}, // rescored
+		{ID: "8", Title: "Go Developer", Company: "Pied Piper", Score: 50},
+	}
+	stub := newStubScraper(found, errors.New("error scraping LinkedIn: blocked"))
+	var saved []string
+	save := func(jobs []job.Job) ([]job.Job, error) {
+		for _, j := range jobs {
+			saved = append(saved, j.ID)
+		}
+		return jobs, nil
+	}
+	m := drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())),
+		WithScraper(func() IncrementalScraper { return stub }, save)))
+	m = run(m, key("j")) // on Globex
+
+	m, cmd := m.Update(key("s"))
+	if m.(Model).viewState != Scraping || !m.(Model).scraping {
+		t.Fatalf("s did not start a scrape: view %v", m.(Model).viewState)
+	}
+	m = pump(m, cmd)
+
+	model := m.(Model)
+	if model.scraping || model.scrape != nil {
+		t.Error("scrape still running after its channels closed")
+	}
+	if strings.Join(saved, ",") != "9,3,8" {
+		t.Errorf("saved %v, want 9,3,8", saved)
+	}
+	var ids []string
+	for _, j := range model.jobs {
+		ids = append(ids, j.ID)
+	}
+	if strings.Join(ids, ",") != "9,1,2,3,8" {
+		t.Errorf("listed %v, want 9,1,2,3,8", ids)
+	}
+	if model.selectedID() != "2" {
+		t.Errorf("cursor moved to %s, want it on 2", model.selectedID())
+	}
+	view := plain(m)
+	for _, want := range []string{"1/1 sources · Finished", "3 jobs found", "error scraping LinkedIn: blocked", "esc back"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("scraping view lacks %q:\n%s", want, view)
+		}
+	}
+	if m = run(m, tea.KeyMsg{Type: tea.KeyEsc}); m.(Model).viewState != JobList {
+		t.Errorf("esc went to %v", m.(Model).viewState)
+	}
+}
+
+func TestModel_Scrape_EscStops(t *testing.T) {
+	defer func(d time.Duration) { saveEvery = d }(saveEvery)
+	saveEvery = 10 * time.Millisecond
+	stub := newStubScraper([]job.Job{{ID: "9", Score: 95}})
+	stub.hold = true
+	m := drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())),
+		WithScraper(func() IncrementalScraper { return stub }, nil)))
+
+	m, cmd := m.Update(key("s"))
+	for range 3 { // two progress events and the first job
+		var next tea.Cmd
+		m, next = m.Update(cmd())
+		cmd = next
+	}
+	if view := plain(m); !strings.Contains(view, "esc stop") {
+		t.Errorf("no way to stop shown:\n%s", view)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if !stub.stopped || m.(Model).viewState != JobList {
+		t.Fatalf("esc left the scrape running (stopped %v) in view %v", stub.stopped, m.(Model).viewState)
+	}
+	m = pump(m, cmd)
+	if m.(Model).scraping || len(m.(Model).jobs) != 4 {
+		t.Errorf("after stopping: scraping %v with %d jobs, want 4", m.(Model).scraping, len(m.(Model).jobs))
+	}
+
+	// A new scrape can start once the stopped one has wound down.
+	next := newStubScraper(nil)
+	model := m.(Model)
+	model.startScrape = func() IncrementalScraper { return next }
+	m, cmd = model.Update(key("s"))
+	if m.(Model).scrape == nil || m.(Model).scrape.s != next {
+		t.Error("s did not start a second scrape")
+	}
+	pump(m, cmd)
+}
+
+func TestModel_Scrape_SaveFails(t *testing.T) {
+	stub := newStubScraper([]job.Job{{ID: "9", Title: "Staff Go Engineer", Score: 95}})
+	save := func([]job.Job) ([]job.Job, error) { return nil, errors.New("disk full") }
+	m := drive(NewModel(WithScraper(func() IncrementalScraper { return stub }, save)))
+	m, cmd := m.Update(key("s"))
+	m = pump(m, cmd)
+	if view := plain(m); !strings.Contains(view, "not saved: disk full") {
+		t.Errorf("save failure not shown:\n%s", view)
+	}
+	if len(m.(Model).jobs) != 1 {
+		t.Errorf("the unsaved job is not listed")
+	}
+}
+
+func TestModel_Scrape_SavesInBatches(t *testing.T) {
+	var found []job.Job
+	for i := range 120 {
+		found = append(found, job.Job{ID: fmt.Sprintf("s%03d", i), Score: i % 100})
+	}
+	found = append(found[:60], append([]job.Job{{ID: "s055", Score: 99}}, found[60:]...)...) // streamed again
+	stub := newStubScraper(found)
+	var batches []int
+	save := func(jobs []job.Job) ([]job.Job, error) {
+		if len(batches) == 2 {
+			return nil, errors.New("disk full")
+		}
+		batches = append(batches, len(jobs))
+		return jobs, nil
+	}
+	m := drive(NewModel(WithScraper(func() IncrementalScraper { return stub }, save)))
+	m, cmd := m.Update(key("s"))
+	m = pump(m, cmd)
+
+	// The third batch failing leaves the two before it saved.
+	if fmt.Sprint(batches) != "[50 50]" {
+		t.Errorf("saved batches %v, want [50 50]", batches)
+	}
+	if view := plain(m); !strings.Contains(view, "not saved: disk full") {
+		t.Errorf("failed batch not shown:\n%s", view)
+	}
+	if n := len(m.(Model).all); n != 120 {
+		t.Errorf("listed %d jobs, want 120", n)
+	}
+}
+
+func TestModel_Scrape_SavesAfterAWhile(t *testing.T) {
+	defer func(d time.Duration) { saveEvery = d }(saveEvery)
+	saveEvery = 10 * time.Millisecond
+	stub := newStubScraper([]job.Job{{ID: "9", Score: 95}})
+	stub.hold = true
+	saved := 0
+	save := func(jobs []job.Job) ([]job.Job, error) { saved += len(jobs); return jobs, nil }
+	m := drive(NewModel(WithScraper(func() IncrementalScraper { return stub }, save)))
+
+	m, cmd := m.Update(key("s"))
+	for range 3 { // the two progress events and the batch of one
+		var next tea.Cmd
+		m, next = m.Update(cmd())
+		cmd = next
+	}
+	if saved != 1 || len(m.(Model).all) != 1 {
+		t.Errorf("while the scrape runs: saved %d, listed %d", saved, len(m.(Model).all))
+	}
+	stub.Stop()
+	pump(m, cmd)
+}
+
+func TestModel_Scrape_StopAndQuitSaveTheGatheredBatch(t *testing.T) {
+	defer func(d time.Duration) { saveEvery = d }(saveEvery)
+	saveEvery = time.Hour
+	for _, stop := range []tea.KeyMsg{{Type: tea.KeyEsc}, key("q")} {
+		t.Run(stop.String(), func(t *testing.T) {
+			stub := newStubScraper([]job.Job{{ID: "1", Score: 90}, {ID: "2", Score: 80}, {ID: "3", Score: 70}})
+			stub.hold = true
+			var mu sync.Mutex
+			var saved []job.Job
+			save := func(jobs []job.Job) ([]job.Job, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				saved = append(saved, jobs...)
+				return jobs, nil
+			}
+			m := drive(NewModel(WithScraper(func() IncrementalScraper { return stub }, save)))
+			m, cmd := m.Update(key("s"))
+			for range 2 { // the two progress events
+				m, cmd = m.Update(cmd())
+			}
+			done := make(chan struct{})
+			go func() { // gathers the jobs, then waits on the held scraper
+				defer close(done)
+				cmd()
+			}()
+			r := m.(Model).scrape
+			for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+				if n, _ := r.gathered(); n == 3 {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatal("jobs never gathered")
+				}
+			}
+
+			_, cmd = m.Update(stop)
+			if cmd == nil {
+				t.Fatal("stopping returned no command")
+			}
+			msg := cmd()
+			<-done
+			mu.Lock()
+			defer mu.Unlock()
+			if len(saved) != 3 {
+				t.Errorf("saved %d of the 3 gathered jobs", len(saved))
+			}
+			if _, quit := msg.(tea.QuitMsg); quit != (stop.String() == "q") {
+				t.Errorf("%s ended with %T", stop, msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Package sectionform frames a huh form whose groups are sections: it
+// fits the form under a header naming the section on screen, and keeps
+// track of which section that is.
+package sectionform
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/ui/tui/theme"
+)
+
+// headerHeight is the section indicator line plus a blank spacer.
+const headerHeight = 2
+
+// footerHeight is what huh renders under a group: a blank line and the
+// help/error row.
+const footerHeight = 2
+
+// minFormHeight keeps at least one field visible on absurdly short terminals.
+const minFormHeight = 3
+
+// Form is a huh form with a group per section, titled by titles. Groups
+// scroll internally so every field stays reachable at small heights.
+// Hidden groups and layouts other than huh's default are not supported.
+type Form struct {
+	form    *huh.Form
+	groups  []*huh.Group
+	titles  []string
+	section int
+}
+
+// New builds a form of groups, one per title, sized for 80x24 until told
+// otherwise.
+func New(titles []string, groups ...*huh.Group) *Form {
+	f := &Form{form: huh.NewForm(groups...), groups: groups, titles: titles}
+	f.SetSize(80, 24)
+	return f
+}
+
+// SetSize fits the form into width x height, leaving room for the section
+// indicator and huh's footer. Groups taller than that scroll.
+func (f *Form) SetSize(width, height int) {
+	f.form.WithWidth(width).WithHeight(max(height-headerHeight-footerHeight, minFormHeight))
+}
+
+func (f *Form) Init() tea.Cmd { return f.form.Init() }
+
+// Update passes msg to the form, following any section it moves to.
+func (f *Form) Update(msg tea.Msg) tea.Cmd {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		// Sizing is ours to decide; huh would otherwise use the full height.
+		f.SetSize(size.Width, size.Height)
+		return nil
+	}
+	m, cmd := f.form.Update(msg)
+	if form, ok := m.(*huh.Form); ok {
+		f.form = form
+	}
+	f.followSection()
+	return cmd
+}
+
+// followSection finds the section huh is on. huh keeps that private, but
+// draws only its group, with the focused field set apart from blurred
+// ones, so it is the group drawn the way the form is. Sections that draw
+// exactly alike can't be told apart that way; the current one wins.
+func (f *Form) followSection() {
+	if f.form.State != huh.StateNormal {
+		return
+	}
+	view := f.form.View()
+	if f.groups[f.section].View() == view {
+		return
+	}
+	for i, g := range f.groups {
+		if g.View() == view {
+			f.section = i
+			return
+		}
+	}
+}
+
+func (f *Form) View() string {
+	indicator := lipgloss.NewStyle().Foreground(theme.Subtle).Render(
+		fmt.Sprintf("Section %d of %d", f.section+1, len(f.titles)))
+	title := lipgloss.NewStyle().Foreground(theme.Cyan).Bold(true).Render(f.titles[f.section])
+	return title + "  " + indicator + "\n\n" + f.form.View()
+}
+
+// Section returns the zero-based index of the visible section.
+func (f *Form) Section() int { return f.section }
+
+// Done reports whether the form was submitted.
+func (f *Form) Done() bool { return f.form.State == huh.StateCompleted }
+
+// Aborted reports whether the user cancelled the form.
+func (f *Form) Aborted() bool { return f.form.State == huh.StateAborted }
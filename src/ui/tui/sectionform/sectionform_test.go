@@ -0,0 +1,105 @@
+package sectionform
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+)
+
+// update feeds msg to f and follows the resulting commands, skipping
+// timers such as cursor blinks that would not fire in a test.
+func update(f *Form, msg tea.Msg) {
+	drain(f, f.Update(msg), 0)
+}
+
+func drain(f *Form, cmd tea.Cmd, depth int) {
+	if cmd == nil || depth > 10 {
+		return
+	}
+	ch := make(chan tea.Msg, 1)
+	go func() { ch <- cmd() }()
+	var msg tea.Msg
+	select {
+	case msg = <-ch:
+	case <-time.After(20 * time.Millisecond):
+		return
+	}
+	switch msg := msg.(type) {
+	case nil:
+	case tea.BatchMsg:
+		for _, c := range msg {
+			drain(f, c, depth+1)
+		}
+	default:
+		drain(f, f.Update(msg), depth+1)
+	}
+}
+
+// twins is a form whose sections draw exactly the same.
+func twins() *Form {
+	var a, b string
+	f := New([]string{"One", "Two"},
+		huh.NewGroup(huh.NewInput().Title("Same").Value(&a)),
+		huh.NewGroup(huh.NewInput().Title("Same").Value(&b)))
+	f.Init()
+	return f
+}
+
+func TestForm_TracksSectionsThatLookAlike(t *testing.T) {
+	f := twins()
+	update(f, tea.KeyMsg{Type: tea.KeyTab})
+	if f.Section() != 1 || !strings.Contains(f.View(), "Section 2 of 2") {
+		t.Fatalf("tab past the only field should reach section 2, got %d\n%s", f.Section()+1, f.View())
+	}
+	update(f, tea.KeyMsg{Type: tea.KeyShiftTab})
+	update(f, tea.KeyMsg{Type: tea.KeyShiftTab})
+	if f.Section() != 0 {
+		t.Errorf("shift+tab past the first section should stay on it, got %d", f.Section()+1)
+	}
+}
+
+func TestForm_StaysWhileSectionHasErrors(t *testing.T) {
+	var v string
+	f := New([]string{"One", "Two"},
+		huh.NewGroup(huh.NewInput().Title("Required").Value(&v).Validate(func(s string) error {
+			if s == "" {
+				return errors.New("required")
+			}
+			return nil
+		})),
+		huh.NewGroup(huh.NewInput().Title("Next")))
+	f.Init()
+	update(f, tea.KeyMsg{Type: tea.KeyEnter})
+	if f.Section() != 0 {
+		t.Fatalf("an invalid field should keep section 1, got %d", f.Section()+1)
+	}
+	update(f, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	update(f, tea.KeyMsg{Type: tea.KeyEnter})
+	if f.Section() != 1 {
+		t.Errorf("once valid, enter should reach section 2, got %d", f.Section()+1)
+	}
+}
+
+func TestForm_FieldMovesKeepSection(t *testing.T) {
+	var a, b, c string
+	f := New([]string{"One", "Two"},
+		huh.NewGroup(huh.NewInput().Title("A").Value(&a), huh.NewInput().Title("B").Value(&b)),
+		huh.NewGroup(huh.NewInput().Title("C").Value(&c)))
+	f.Init()
+	update(f, tea.KeyMsg{Type: tea.KeyTab})
+	if f.Section() != 0 {
+		t.Fatalf("tab to the second field should stay in section 1, got %d", f.Section()+1)
+	}
+	update(f, tea.KeyMsg{Type: tea.KeyTab})
+	if f.Section() != 1 {
+		t.Fatalf("tab past the last field should reach section 2, got %d", f.Section()+1)
+	}
+	update(f, tea.KeyMsg{Type: tea.KeyShiftTab})
+	if f.Section() != 0 {
+		t.Errorf("shift+tab should return to section 1, got %d", f.Section()+1)
+	}
+}
@@ -0,0 +1,105 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/settings"
+	"sprayer/src/ui/tui/settingsform"
+	"sprayer/src/ui/tui/theme"
+)
+
+// SettingsStore keeps the app's settings; *settings.Store satisfies it.
+type SettingsStore interface {
+	Load() (settings.Settings, error)
+	Save(settings.Settings) error
+}
+
+// WithSettings lets , open a form editing the settings in store. env is
+// what the environment overrides, which the form points out.
+func WithSettings(store SettingsStore, env settings.Overrides) Option {
+	return func(m *Model) { m.settings, m.settingsEnv = store, env }
+}
+
+// settingsLoadedMsg delivers the stored settings to edit; settingsSavedMsg
+// the result of saving them.
+type (
+	settingsLoadedMsg struct {
+		settings settings.Settings
+		err      error
+	}
+	settingsSavedMsg struct {
+		saved bool
+		err   error
+	}
+)
+
+// openSettings loads the settings, for the form to start from.
+func (m Model) openSettings() (Model, tea.Cmd) {
+	store := m.settings
+	return m, func() tea.Msg {
+		s, err := store.Load()
+		return settingsLoadedMsg{settings: s, err: err}
+	}
+}
+
+// settingsLoaded shows the form, unless loading failed.
+func (m Model) settingsLoaded(msg settingsLoadedMsg) (Model, tea.Cmd) {
+	if msg.err != nil {
+		m.settingsSaved = settingsSavedMsg{err: msg.err}
+		return m, nil
+	}
+	m.settingsForm = settingsform.New(msg.settings, m.settingsEnv)
+	m.settingsForm.SetSize(m.width-4, m.height-2)
+	m.viewState, m.settingsReturn = Settings, m.viewState
+	return m, m.settingsForm.Init()
+}
+
+// updateSettings passes msg to the form; esc leaves it unsaved, ctrl+o
+// opens the Sources view over it and a submitted form is saved.
+func (m Model) updateSettings(msg tea.Msg) (Model, tea.Cmd) {
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Type == tea.KeyEsc:
+			m.viewState, m.settingsForm = m.settingsReturn, nil
+			return m, nil
+		case key.String() == "ctrl+o" && m.sourceStats != nil:
+			return m.openSources()
+		}
+	}
+	form, cmd := m.settingsForm.Update(msg)
+	switch {
+	case form.Done():
+		s, store := form.Settings(), m.settings
+		m.viewState, m.settingsForm = m.settingsReturn, nil
+		return m, func() tea.Msg { return settingsSavedMsg{saved: true, err: store.Save(s)} }
+	case form.Aborted():
+		m.viewState, m.settingsForm = m.settingsReturn, nil
+		return m, nil
+	}
+	return m, cmd
+}
+
+// settingsNotice is the status bar's note on the last save. Settings are
+// read when the program starts, so they apply from the next one.
+func (m Model) settingsNotice() string {
+	switch {
+	case m.settingsSaved.err != nil:
+		return theme.ErrorStyle.Render("settings: " + m.settingsSaved.err.Error())
+	case m.settingsSaved.saved:
+		return theme.SuccessStyle.Render("settings saved; they apply from the next start")
+	}
+	return ""
+}
+
+// renderSettings draws the settings form, and the key to the Sources
+// view when there is one.
+func (m Model) renderSettings() string {
+	view := m.settingsForm.View()
+	if m.sourceStats != nil {
+		view += "\n" + lipgloss.NewStyle().Foreground(theme.Subtle).Render("ctrl+o source health")
+	}
+	block := lipgloss.NewStyle().Padding(0, 2).Render(view)
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Left, lipgloss.Top, block,
+		lipgloss.WithWhitespaceBackground(theme.Background))
+}
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/settings"
+)
+
+// memSettings keeps the settings in memory.
+type memSettings struct {
+	s     settings.Settings
+	saves int
+}
+
+func (s *memSettings) Load() (settings.Settings, error) { return s.s, nil }
+
+func (s *memSettings) Save(st settings.Settings) error {
+	if err := st.Validate(); err != nil {
+		return err
+	}
+	s.s, s.saves = st, s.saves+1
+	return nil
+}
+
+// feed passes msg to m and follows its commands, giving up on those that
+// wait, such as the form's cursor blink.
+func feed(m tea.Model, msg tea.Msg) tea.Model {
+	m, cmd := m.Update(msg)
+	return follow(m, cmd, 0)
+}
+
+func follow(m tea.Model, cmd tea.Cmd, depth int) tea.Model {
+	if cmd == nil || depth > 10 {
+		return m
+	}
+	ch := make(chan tea.Msg, 1)
+	go func() { ch <- cmd() }()
+	select {
+	case msg := <-ch:
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, c := range batch {
+				m = follow(m, c, depth+1)
+			}
+			return m
+		}
+		if msg == nil {
+			return m
+		}
+		m, cmd = m.Update(msg)
+		return follow(m, cmd, depth+1)
+	case <-time.After(20 * time.Millisecond):
+		return m
+	}
+}
+
+func TestModel_Settings(t *testing.T) {
+	store := &memSettings{s: settings.Settings{DefaultProfile: "rust", SMTPPort: "465"}}
+	var m tea.Model = drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())),
+		WithSettings(store, settings.Overrides{"export_format": "json"})))
+
+	m = feed(m, key(","))
+	if m.(Model).viewState != Settings {
+		t.Fatalf(", opened %v", m.(Model).viewState)
+	}
+	view := plain(m)
+	if !strings.Contains(view, "General") || !strings.Contains(view, "rust") {
+		t.Fatalf("settings not shown:\n%s", view)
+	}
+	m = feed(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if view := plain(m); !strings.Contains(view, "SPRAYER_EXPORT_FORMAT overrides this: json") {
+		t.Errorf("override not pointed out:\n%s", view)
+	}
+
+	// esc leaves without saving.
+	m = feed(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.(Model).viewState != JobList || store.saves != 0 {
+		t.Fatalf("esc left %v after %d saves", m.(Model).viewState, store.saves)
+	}
+
+	m = feed(m, key(","))
+	for i := 0; m.(Model).viewState == Settings; i++ {
+		if i > len(settings.Fields) {
+			t.Fatal("form never submitted")
+		}
+		m = feed(m, tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	if store.saves != 1 || store.s.DefaultProfile != "rust" || store.s.SMTPPort != "465" {
+		t.Errorf("saved %+v after %d saves", store.s, store.saves)
+	}
+	if view := plain(m); !strings.Contains(view, "settings saved") {
+		t.Errorf("save not reported:\n%s", view)
+	}
+}
@@ -0,0 +1,114 @@
+package settingsform
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+
+	"sprayer/src/api/export"
+	"sprayer/src/api/llm"
+	"sprayer/src/api/settings"
+	"sprayer/src/ui/tui/sectionform"
+)
+
+// sections groups the settings, by key, into the form's pages.
+var sections = []struct {
+	title string
+	keys  []string
+}{
+	{"General", []string{"default_profile", "export_format", "export_dir", "scrape_parallelism", "theme"}},
+	{"LLM", []string{"llm_provider", "llm_model", "llm_base_url"}},
+	{"Mail", []string{"smtp_host", "smtp_port", "smtp_user", "smtp_from"}},
+}
+
+// choices are the values offered for the settings picked from a list;
+// the rest are typed.
+var choices = map[string][]string{
+	"export_format": formats(),
+	"llm_provider":  {llm.ProviderHosted, llm.ProviderOpenAI, llm.ProviderOllama},
+	"theme":         settings.Themes,
+}
+
+func formats() []string {
+	var out []string
+	for _, f := range export.JobFormats {
+		out = append(out, string(f))
+	}
+	return out
+}
+
+// Model edits settings.Settings through a form, a section per group of
+// settings. Each field says when the environment overrides it, since
+// a stored value then has no effect until the variable is unset.
+type Model struct {
+	*sectionform.Form
+
+	// Text of each setting, by key; applied by Settings().
+	values map[string]*string
+}
+
+// New builds a form pre-filled from s; env is what the environment
+// overrides, as settings.Apply returned it.
+func New(s settings.Settings, env settings.Overrides) *Model {
+	m := &Model{values: make(map[string]*string)}
+	byKey := make(map[string]settings.Field)
+	for _, f := range settings.Fields {
+		byKey[f.Key] = f
+		v := f.Get(s)
+		m.values[f.Key] = &v
+	}
+
+	var titles []string
+	var groups []*huh.Group
+	for _, sec := range sections {
+		var fields []huh.Field
+		for _, key := range sec.keys {
+			f := byKey[key]
+			desc := "Empty uses the default"
+			if f.Default != "" {
+				desc = "Empty uses " + f.Default
+			}
+			if v, ok := env[key]; ok {
+				desc = fmt.Sprintf("%s overrides this: %s", f.Env, v)
+			}
+			if opts, ok := choices[key]; ok {
+				options := []huh.Option[string]{huh.NewOption("default ("+f.Default+")", "")}
+				fields = append(fields, huh.NewSelect[string]().Title(f.Title).Description(desc).
+					Options(append(options, huh.NewOptions(opts...)...)...).Value(m.values[key]))
+				continue
+			}
+			fields = append(fields, huh.NewInput().Title(f.Title).Description(desc).
+				Value(m.values[key]).Validate(validator(f)))
+		}
+		titles = append(titles, sec.title)
+		groups = append(groups, huh.NewGroup(fields...))
+	}
+	m.Form = sectionform.New(titles, groups...)
+	return m
+}
+
+// validator refuses a value settings.Validate would.
+func validator(f settings.Field) func(string) error {
+	return func(v string) error {
+		var s settings.Settings
+		if err := f.Set(&s, v); err != nil {
+			return err
+		}
+		return s.Validate()
+	}
+}
+
+func (m *Model) Update(msg tea.Msg) (*Model, tea.Cmd) {
+	return m, m.Form.Update(msg)
+}
+
+// Settings returns the edited settings. The fields validated what was
+// typed, so nothing is lost parsing it back.
+func (m *Model) Settings() settings.Settings {
+	var s settings.Settings
+	for _, f := range settings.Fields {
+		f.Set(&s, *m.values[f.Key])
+	}
+	return s
+}
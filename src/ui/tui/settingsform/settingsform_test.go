@@ -0,0 +1,95 @@
+package settingsform
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/settings"
+)
+
+// run feeds msg to the form and follows the resulting commands, skipping
+// timers such as cursor blinks that would not fire in a test.
+func run(m *Model, msg tea.Msg) {
+	_, cmd := m.Update(msg)
+	drain(m, cmd, 0)
+}
+
+func drain(m *Model, cmd tea.Cmd, depth int) {
+	if cmd == nil || depth > 10 {
+		return
+	}
+	ch := make(chan tea.Msg, 1)
+	go func() { ch <- cmd() }()
+	var msg tea.Msg
+	select {
+	case msg = <-ch:
+	case <-time.After(20 * time.Millisecond):
+		return
+	}
+	switch msg := msg.(type) {
+	case nil:
+	case tea.BatchMsg:
+		for _, c := range msg {
+			drain(m, c, depth+1)
+		}
+	default:
+		_, next := m.Update(msg)
+		drain(m, next, depth+1)
+	}
+}
+
+func TestSettingsForm_EveryFieldOnce(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, sec := range sections {
+		for _, key := range sec.keys {
+			if seen[key] {
+				t.Errorf("%s in two sections", key)
+			}
+			seen[key] = true
+		}
+	}
+	for _, f := range settings.Fields {
+		if !seen[f.Key] {
+			t.Errorf("%s is not in the form", f.Key)
+		}
+	}
+}
+
+func TestSettingsForm_SubmitKeepsValues(t *testing.T) {
+	s := settings.Settings{DefaultProfile: "rust", ExportFormat: "json", ScrapeParallelism: 2, SMTPPort: "465"}
+	m := New(s, settings.Overrides{"llm_model": "llama3"})
+	m.SetSize(80, 24)
+	drain(m, m.Init(), 0)
+
+	if view := m.View(); !strings.Contains(view, "General") || !strings.Contains(view, "Section 1 of 3") {
+		t.Fatalf("first section not shown:\n%s", m.View())
+	}
+	for range 5 {
+		run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	if view := m.View(); !strings.Contains(view, "SPRAYER_LLM_MODEL overrides this: llama3") {
+		t.Errorf("override not shown:\n%s", view)
+	}
+	for !m.Done() {
+		run(m, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.Aborted() {
+			t.Fatal("form aborted")
+		}
+	}
+	if got := m.Settings(); got != s {
+		t.Errorf("submitted %+v, want %+v", got, s)
+	}
+}
+
+func TestSettingsForm_RefusesInvalid(t *testing.T) {
+	for key, bad := range map[string]string{"smtp_port": "mail", "scrape_parallelism": "-2"} {
+		for _, f := range settings.Fields {
+			if f.Key == key && validator(f)(bad) == nil {
+				t.Errorf("%s accepted %q", key, bad)
+			}
+		}
+	}
+}
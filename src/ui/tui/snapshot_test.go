@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/job"
+)
+
+// Snapshot tests render views at a fixed size and compare them to golden
+// files under testdata/snapshots. Run with UPDATE_SNAPSHOTS=1 to regenerate.
+
+var ansiRe = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+
+type fixtureSource []job.Job
+
+func (f fixtureSource) ForProfile(_ string, opts ...job.ActiveOption) ([]job.Job, error) {
+	return job.Active(opts...)(f), nil
+}
+
+func fixtureJobs() []job.Job {
+	posted := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	return []job.Job{
+		{ID: "1", Title: "Senior Go Engineer", Company: "Acme", Score: 92, Source: "ashby", PostedDate: posted},
+		{ID: "2", Title: "Platform Engineer (Kubernetes, Terraform, AWS) — Remote EU", Company: "Globex", Score: 81, Source: "greenhouse", PostedDate: posted},
+		{ID: "3", Title: "Backend Developer", Company: "Initech", Score: 64, Source: "hn", HasTraps: true, PostedDate: posted},
+	}
+}
+
+// drive starts the model like a tea.Program would and feeds it msgs.
+// Init's commands are run once each; what Update returns is not.
+func drive(m tea.Model, msgs ...tea.Msg) tea.Model {
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	if cmd := m.Init(); cmd != nil {
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for _, c := range batch {
+				m, _ = m.Update(c())
+			}
+		} else {
+			m, _ = m.Update(msg)
+		}
+	}
+	for _, msg := range msgs {
+		m, _ = m.Update(msg)
+	}
+	return m
+}
+
+func key(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func assertSnapshot(t *testing.T, name, view string) {
+	t.Helper()
+	got := ansiRe.ReplaceAllString(view, "")
+	path := filepath.Join("testdata", "snapshots", name+".golden")
+	if os.Getenv("UPDATE_SNAPSHOTS") != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing snapshot %s (run with UPDATE_SNAPSHOTS=1): %v", path, err)
+	}
+	if got != string(want) {
+		t.Errorf("snapshot %s changed:\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+func TestSnapshots(t *testing.T) {
+	loaded := WithJobSource(fixtureSource(fixtureJobs()))
+	tests := []struct {
+		name string
+		opts []Option
+		msgs []tea.Msg
+	}{
+		{name: "empty_state"},
+		{name: "job_list", opts: []Option{loaded}},
+		{name: "job_list_selected", opts: []Option{loaded}, msgs: []tea.Msg{key("j"), key("j")}},
+		{name: "filter", opts: []Option{loaded}, msgs: []tea.Msg{key("f")}},
+		{name: "profiles", opts: []Option{loaded}, msgs: []tea.Msg{key("p")}},
+		{name: "help", opts: []Option{loaded}, msgs: []tea.Msg{key("?")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := drive(NewModel(tt.opts...), tt.msgs...)
+			assertSnapshot(t, tt.name, m.View())
+		})
+	}
+}
+
+func TestSnapshots_Loading(t *testing.T) {
+	var m tea.Model = NewModel(WithJobSource(fixtureSource(fixtureJobs())))
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	assertSnapshot(t, "loading", m.View())
+
+	m, cmd := m.Update(spinMsg{})
+	if cmd == nil || !strings.Contains(m.View(), spinFrames[1]) {
+		t.Error("spinner did not advance while loading")
+	}
+	m, _ = m.Update(jobsLoadedMsg{jobs: fixtureJobs()})
+	if _, cmd := m.Update(spinMsg{}); cmd != nil {
+		t.Error("spinner kept ticking after jobs arrived")
+	}
+}
+
+func TestSnapshots_Stable(t *testing.T) {
+	m := drive(NewModel(WithJobSource(fixtureSource(fixtureJobs()))))
+	if a, b := m.View(), m.View(); a != b {
+		t.Error("View() is not deterministic")
+	}
+	if lines := strings.Count(ansiRe.ReplaceAllString(m.View(), ""), "\n") + 1; lines != 24 {
+		t.Errorf("job list renders %d rows at height 24", lines)
+	}
+}
+
+func TestJobList_LanguageTag(t *testing.T) {
+	jobs := fixtureJobs()
+	jobs[1].Language, jobs[2].Language = "de", "en"
+	view := plain(drive(NewModel(WithJobSource(fixtureSource(jobs)))))
+	if !strings.Contains(view, "(greenhouse) [de]") || strings.Contains(view, "[en]") {
+		t.Errorf("only the German job should be tagged:\n%s", view)
+	}
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"cmp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/job"
+)
+
+// WithSort starts the job list in order s; 1 to 4 sort by score, date,
+// title and company, and save keeps the choice for next time.
+func WithSort(s job.Sort, save func(job.Sort) error) Option {
+	return func(m *Model) { m.sort, m.saveSort = s, save }
+}
+
+// sortBy sorts the list by key, or reverses it when it is sorted by key
+// already. The cursor stays on the job it was on.
+func (m Model) sortBy(key job.SortKey) (Model, tea.Cmd) {
+	if cmp.Or(m.sort.Key, job.SortScore) == key {
+		m.sort.Reverse = !m.sort.Reverse
+	} else {
+		m.sort = job.Sort{Key: key}
+	}
+	m = m.refilter()
+	if m.saveSort == nil {
+		return m, nil
+	}
+	save, s := m.saveSort, m.sort
+	return m, func() tea.Msg { return listSavedMsg{err: save(s)} }
+}
+
+// sortLabel is the header's word on the list's order, as "date ↓".
+func (m Model) sortLabel() string {
+	arrow := " ↑"
+	if m.sort.Descending() {
+		arrow = " ↓"
+	}
+	return strings.TrimPrefix(string(cmp.Or(m.sort.Key, job.SortScore)), "posted_") + arrow
+}
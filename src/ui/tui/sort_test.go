@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/job"
+)
+
+func ids(m tea.Model) string {
+	var out []string
+	for _, j := range m.(Model).jobs {
+		out = append(out, j.ID)
+	}
+	return strings.Join(out, " ")
+}
+
+func TestModel_SortColumns(t *testing.T) {
+	jobs := fixtureJobs()
+	jobs[1].PostedDate = jobs[1].PostedDate.AddDate(0, 0, 1)
+	var saved []job.Sort
+	m := drive(NewModel(WithJobSource(fixtureSource(jobs)),
+		WithSort(job.Sort{Key: job.SortDate}, func(s job.Sort) error { saved = append(saved, s); return nil })))
+
+	// Globex is newest; Acme and Initech tie and keep their order.
+	if got := ids(m); got != "2 1 3" {
+		t.Fatalf("started in order %s, want by date", got)
+	}
+	if view := plain(m); !strings.Contains(view, "Jobs: 3 · date ↓") {
+		t.Errorf("sort not in the header:\n%s", view)
+	}
+
+	m = run(m, key("j")) // on Acme
+	for _, tc := range []struct{ key, want, label string }{
+		{"4", "1 2 3", "company ↑"},
+		{"4", "3 2 1", "company ↓"},
+		{"3", "3 2 1", "title ↑"},
+		{"1", "1 2 3", "score ↓"},
+		{"1", "3 2 1", "score ↑"},
+	} {
+		m = run(m, key(tc.key))
+		if got := ids(m); got != tc.want {
+			t.Errorf("%s sorted %s, want %s", tc.key, got, tc.want)
+		}
+		if view := plain(m); !strings.Contains(view, tc.label) {
+			t.Errorf("%s: header lacks %q:\n%s", tc.key, tc.label, view)
+		}
+		if m.(Model).selectedID() != "1" {
+			t.Errorf("%s moved the cursor to %s", tc.key, m.(Model).selectedID())
+		}
+	}
+	if len(saved) != 5 || saved[4] != (job.Sort{Key: job.SortScore, Reverse: true}) {
+		t.Errorf("saved %v", saved)
+	}
+}
@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/sourcestats"
+	"sprayer/src/ui/tui/theme"
+)
+
+// SourceStats reports how each scraper source has fared across scrapes;
+// *sourcestats.Store satisfies it.
+type SourceStats interface {
+	All() ([]sourcestats.Stats, error)
+}
+
+// WithSourceStats lists the stats in s in the Sources view, opened with
+// ctrl+o from the settings.
+func WithSourceStats(s SourceStats) Option {
+	return func(m *Model) { m.sourceStats = s }
+}
+
+// sourcesView is the state of the Sources view.
+type sourcesView struct {
+	stats   []sourcestats.Stats
+	loading bool
+	err     error
+}
+
+// sourcesLoadedMsg delivers the source stats.
+type sourcesLoadedMsg struct {
+	stats []sourcestats.Stats
+	err   error
+}
+
+// openSources shows the Sources view and loads it.
+func (m Model) openSources() (Model, tea.Cmd) {
+	if m.sourceStats == nil {
+		return m, nil
+	}
+	m.sourcesReturn, m.viewState, m.sourcesView = m.viewState, Sources, sourcesView{loading: true}
+	s := m.sourceStats
+	return m, func() tea.Msg {
+		stats, err := s.All()
+		return sourcesLoadedMsg{stats: stats, err: err}
+	}
+}
+
+// updateSources handles a key in the Sources view: esc goes back to
+// where it was opened from.
+func (m Model) updateSources(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.viewState = m.sourcesReturn
+	case "ctrl+c", "q":
+		return m.quit()
+	}
+	return m, nil
+}
+
+// renderSources lists each source's runs, jobs, errors, latency and last
+// success, flagging those that returned nothing in their last
+// sourcestats.DefaultZeroRuns runs.
+func (m Model) renderSources() string {
+	bg := lipgloss.NewStyle().Background(theme.Background)
+	label := bg.Foreground(theme.Subtle)
+	v := m.sourcesView
+
+	lines := []string{bg.Foreground(theme.Bright).Bold(true).Render("Sources"), ""}
+	switch {
+	case v.loading:
+		lines = append(lines, label.Render("Loading…"))
+	case v.err != nil:
+		lines = append(lines, theme.ErrorStyle.Render(v.err.Error()))
+	case len(v.stats) == 0:
+		lines = append(lines, label.Render("No scrapes recorded yet."))
+	}
+	now := time.Now()
+	for _, st := range v.stats {
+		lastOK := "never succeeded"
+		if st.LastSuccess != nil {
+			lastOK = "ok " + sinceLabel(now.Sub(*st.LastSuccess))
+		}
+		line := fmt.Sprintf("%-16s %3d runs  %5d jobs (%d last)  %2d errors  avg %-6s %s",
+			st.Source, st.Runs, st.Jobs, st.LastJobs, st.Errors, st.AvgLatency().Round(100*time.Millisecond), lastOK)
+		switch {
+		case st.Flagged(sourcestats.DefaultZeroRuns):
+			line = theme.ErrorStyle.Render(line + fmt.Sprintf("  ⚠ no jobs in %d runs", st.ZeroStreak))
+		default:
+			line = theme.JobItemStyle.Render(line)
+		}
+		lines = append(lines, line)
+		if st.LastError != "" && st.LastJobs == 0 {
+			lines = append(lines, label.Render("  last error: "+st.LastError))
+		}
+	}
+	lines = append(lines, "", label.Render("esc back"))
+	block := bg.Padding(1, 2).Width(m.width).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Left, lipgloss.Top, block,
+		lipgloss.WithWhitespaceBackground(theme.Background))
+}
+
+// sinceLabel formats d coarsely, e.g. "2h ago".
+func sinceLabel(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+}
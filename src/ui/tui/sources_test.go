@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/sourcestats"
+)
+
+type fakeSourceStats []sourcestats.Stats
+
+func (s fakeSourceStats) All() ([]sourcestats.Stats, error) { return s, nil }
+
+func TestModel_SourcesFromSettings(t *testing.T) {
+	ok := time.Now().Add(-2 * time.Hour)
+	stats := fakeSourceStats{
+		{Source: "hn", Runs: 4, Jobs: 120, LastJobs: 30, Latency: 8 * time.Second, LastSuccess: &ok},
+		{Source: "jobicy", Runs: 5, Jobs: 10, ZeroStreak: 3, LastError: "Jobicy parse: unexpected end"},
+	}
+	var m tea.Model = drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())),
+		WithSettings(&memSettings{}, nil), WithSourceStats(stats)))
+
+	m = feed(m, key(","))
+	if view := plain(m); !strings.Contains(view, "ctrl+o source health") {
+		t.Fatalf("settings do not point to the sources:\n%s", view)
+	}
+	m = feed(m, tea.KeyMsg{Type: tea.KeyCtrlO})
+	if m.(Model).viewState != Sources {
+		t.Fatalf("ctrl+o opened %v", m.(Model).viewState)
+	}
+	view := plain(m)
+	for _, want := range []string{"Sources", "hn", "120 jobs (30 last)", "ok 2h ago", "⚠ no jobs in 3 runs", "last error: Jobicy parse"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("sources view lacks %q:\n%s", want, view)
+		}
+	}
+
+	m = feed(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.(Model).viewState != Settings {
+		t.Errorf("esc went to %v, want back to the settings", m.(Model).viewState)
+	}
+}
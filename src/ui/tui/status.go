@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/job"
+	"sprayer/src/ui/tui/theme"
+)
+
+// statusSavedMsg reports saving a status change made with t.
+type statusSavedMsg struct {
+	jobID string
+	err   error
+}
+
+// historyMsg delivers the status history of the job open in Detail.
+type historyMsg struct {
+	jobID   string
+	history []job.StatusChange
+	err     error
+}
+
+// cycleStatus moves the selected job on to its next status and saves it.
+// The list shows the change at once; a failed save shows in the status bar.
+func (m Model) cycleStatus() (Model, tea.Cmd) {
+	if len(m.jobs) == 0 {
+		return m, nil
+	}
+	jobs := slices.Clone(m.jobs)
+	j := &jobs[m.selectedIndex]
+	j.Status = j.Status.Next()
+	m.jobs, m.all = jobs, withJob(m.all, *j)
+	if m.statuses == nil {
+		return m, nil
+	}
+	store, id, st := m.statuses, j.ID, j.Status
+	return m, func() tea.Msg {
+		return statusSavedMsg{jobID: id, err: store.SetStatus(id, st, "", time.Now())}
+	}
+}
+
+// loadHistory reads the status history of the selected job.
+func (m Model) loadHistory() tea.Cmd {
+	if m.statuses == nil || len(m.jobs) == 0 {
+		return nil
+	}
+	store, id := m.statuses, m.jobs[m.selectedIndex].ID
+	return func() tea.Msg {
+		hist, err := store.StatusHistory(id)
+		return historyMsg{jobID: id, history: hist, err: err}
+	}
+}
+
+func (m Model) selectedID() string {
+	if len(m.jobs) == 0 {
+		return ""
+	}
+	return m.jobs[m.selectedIndex].ID
+}
+
+// renderDetail shows the selected job with its status history and the
+// application sent, if any.
+func (m Model) renderDetail() string {
+	j := m.jobs[m.selectedIndex]
+	bg := lipgloss.NewStyle().Background(theme.Background)
+	label := bg.Foreground(theme.Subtle)
+	text := bg.Foreground(theme.Text)
+
+	status := "none"
+	if j.Status != job.StatusNone {
+		status = string(j.Status)
+	}
+	lines := []string{
+		bg.Foreground(theme.Bright).Bold(true).Render(j.Title),
+		theme.JobCompanyStyle.Render("@ "+j.Company) + theme.JobSourceStyle.Render(" ("+j.Source+")"),
+		"",
+		label.Render("Status  ") + theme.JobStatusStyle.Render(status),
+		label.Render("URL     ") + text.Render(j.URL),
+		label.Render("Email   ") + text.Render(cmp.Or(j.Email, "none")),
+		"",
+	}
+	if j.HasTraps {
+		lines = append(lines, label.Render("Traps"))
+		if len(j.Traps) == 0 {
+			lines = append(lines, label.Render("  flagged, rules not recorded"))
+		}
+		for _, t := range j.Traps {
+			severity, name := string(t.Severity), t.Name
+			if name == "" {
+				severity, name = "?", "rule not recorded"
+			}
+			lines = append(lines, theme.JobTrapsStyle.Render("  ["+severity+"] ")+text.Render(name)+
+				label.Render("  "+strconv.Quote(t.Match)))
+		}
+		lines = append(lines, "")
+	}
+	lines = append(lines, label.Render("History"))
+	if len(m.history) == 0 {
+		lines = append(lines, label.Render("  no status changes yet"))
+	}
+	name := func(s job.Status) string {
+		if s == job.StatusNone {
+			return "none"
+		}
+		return string(s)
+	}
+	for _, h := range m.history {
+		line := text.Render("  "+h.At.Local().Format("2006-01-02 15:04")+"  "+name(h.From)+" → ") + theme.JobStatusStyle.Render(name(h.To))
+		if h.Note != "" {
+			line += label.Render("  " + h.Note)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, m.applicationLines(label, text)...)
+	used := lipgloss.Height(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	lines = append(lines, m.descriptionLines(m.height-4-used, label, text)...)
+	block := bg.Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Left, lipgloss.Top, block,
+		lipgloss.WithWhitespaceBackground(theme.Background))
+}
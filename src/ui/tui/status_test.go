@@ -0,0 +1,279 @@
+package tui
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/job"
+)
+
+// memStatuses is a StatusStore in memory.
+type memStatuses struct {
+	history map[string][]job.StatusChange
+	fail    error
+}
+
+func (s *memStatuses) SetStatus(id string, st job.Status, note string, at time.Time) error {
+	if s.fail != nil {
+		return s.fail
+	}
+	h := s.history[id]
+	from := job.StatusNone
+	if len(h) > 0 {
+		from = h[len(h)-1].To
+	}
+	s.history[id] = append(h, job.StatusChange{From: from, To: st, At: at, Note: note})
+	return nil
+}
+
+func (s *memStatuses) StatusHistory(id string) ([]job.StatusChange, error) {
+	return s.history[id], nil
+}
+
+// run feeds msg to m and then whatever its command returns, once.
+func run(m tea.Model, msg tea.Msg) tea.Model {
+	m, cmd := m.Update(msg)
+	for cmd != nil {
+		next := cmd()
+		if batch, ok := next.(tea.BatchMsg); ok {
+			for _, c := range batch {
+				if c != nil {
+					if msg := c(); msg != nil {
+						m, _ = m.Update(msg)
+					}
+				}
+			}
+			return m
+		}
+		m, cmd = m.Update(next)
+	}
+	return m
+}
+
+func TestModel_CycleStatus(t *testing.T) {
+	store := &memStatuses{history: map[string][]job.StatusChange{}}
+	var m tea.Model = drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithStatusStore(store)))
+
+	m = run(m, key("t"))
+	m = run(m, key("t"))
+	got := m.(Model).jobs[0].Status
+	if got != job.StatusApplied {
+		t.Fatalf("status after two presses = %q, want applied", got)
+	}
+	if h := store.history["1"]; len(h) != 2 || h[1].To != job.StatusApplied {
+		t.Errorf("saved history = %+v", h)
+	}
+	if view := ansiRe.ReplaceAllString(m.View(), ""); !strings.Contains(view, "[92] [APP] Senior Go Engineer") {
+		t.Errorf("list has no status badge:\n%s", view)
+	}
+
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.(Model).viewState != Detail {
+		t.Fatalf("enter opened view %v", m.(Model).viewState)
+	}
+	view := ansiRe.ReplaceAllString(m.View(), "")
+	for _, want := range []string{"Senior Go Engineer", "Status  applied", "none → interested", "interested → applied"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("detail view lacks %q:\n%s", want, view)
+		}
+	}
+
+	m = run(m, key("t"))
+	if view := ansiRe.ReplaceAllString(m.View(), ""); !strings.Contains(view, "applied → screening") {
+		t.Errorf("history not refreshed after a change in the detail view:\n%s", view)
+	}
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.(Model).viewState != JobList {
+		t.Errorf("esc left view %v", m.(Model).viewState)
+	}
+}
+
+func TestModel_CycleStatus_SaveFails(t *testing.T) {
+	store := &memStatuses{fail: errors.New("database is locked")}
+	var m tea.Model = drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithStatusStore(store)))
+	m = run(m, key("t"))
+	if view := ansiRe.ReplaceAllString(m.View(), ""); !strings.Contains(view, "status not saved: database is locked") {
+		t.Errorf("failed save not shown:\n%s", view)
+	}
+}
+
+func TestModel_DetailShowsApplication(t *testing.T) {
+	sent := time.Date(2024, 5, 6, 9, 30, 0, 0, time.Local)
+	find := func(id string) (*application.Application, error) {
+		if id != "1" {
+			return nil, errors.New("no application")
+		}
+		return &application.Application{JobID: "1", Method: application.MethodEmail, AppliedAt: sent, Sent: &application.Sent{
+			From: "jane+acme@example.com", To: "jobs@acme.com", Cc: []string{"cto@acme.com"}, Subject: "Senior Go Engineer",
+			Body: "Dear Acme,\n\nI build Go services.", CVPath: "/cv/acme.pdf", SentAt: sent,
+		}}, nil
+	}
+	var m tea.Model = drive(NewModel(WithJobSource(fixtureSource(fixtureJobs())), WithApplications(find)))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	view := plain(m)
+	for _, want := range []string{"Application sent 2024-05-06 09:30", "From jane+acme@example.com",
+		"To jobs@acme.com, cto@acme.com", "CV /cv/acme.pdf", "Subject Senior Go Engineer", "Dear Acme, I build Go services."} {
+		if !strings.Contains(view, want) {
+			t.Errorf("detail lacks %q:\n%s", want, view)
+		}
+	}
+
+	m = run(m, tea.KeyMsg{Type: tea.KeyEsc})
+	m = run(m, key("j"))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if view := plain(m); strings.Contains(view, "Application") {
+		t.Errorf("job never applied to shows an application:\n%s", view)
+	}
+}
+
+func TestModel_DetailListsTraps(t *testing.T) {
+	jobs := fixtureJobs()
+	jobs[0].HasTraps, jobs[0].Traps = true, []job.TrapMatch{
+		{Name: "unpaid-trial", Severity: job.TrapHigh, Match: "unpaid trial project"},
+		{Name: "fast-paced", Severity: job.TrapLow, Match: "fast-paced"},
+	}
+	var m tea.Model = drive(NewModel(WithJobSource(fixtureSource(jobs))))
+	m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+	view := plain(m)
+	for _, want := range []string{"Traps", `[high] unpaid-trial "unpaid trial project"`, `[low] fast-paced "fast-paced"`} {
+		if !strings.Contains(view, want) {
+			t.Errorf("detail lacks %q:\n%s", want, view)
+		}
+	}
+}
+
+// lazySource lists fixture jobs without descriptions, as the store's
+// summary query does, and counts the descriptions read.
+type lazySource struct {
+	fixtureSource
+	reads int
+}
+
+func (l *lazySource) Summaries(profileID string, opts ...job.ActiveOption) ([]job.Job, error) {
+	jobs, _ := l.ForProfile(profileID, opts...)
+	out := make([]job.Job, len(jobs))
+	for i, j := range jobs {
+		j.Description = ""
+		out[i] = j
+	}
+	return out, nil
+}
+
+func (l *lazySource) Description(id string) (string, error) {
+	l.reads++
+	for _, j := range l.fixtureSource {
+		if j.ID == id {
+			return j.Description, nil
+		}
+	}
+	return "", sql.ErrNoRows
+}
+
+func TestModel_DetailLoadsDescriptionLazily(t *testing.T) {
+	jobs := fixtureJobs()
+	for i := range jobs {
+		jobs[i].Description = "Posting-" + jobs[i].ID + " asks for Go."
+	}
+	src := &lazySource{fixtureSource: fixtureSource(jobs)}
+	m := drive(NewModel(WithJobSource(src)))
+	if src.reads != 0 {
+		t.Fatalf("read %d descriptions before any job was opened", src.reads)
+	}
+	if got := m.(Model).jobs[0].Description; got != "" {
+		t.Fatalf("listed job carries its description %q", got)
+	}
+	m = run(m, key("enter"))
+	if src.reads != 1 {
+		t.Errorf("read %d descriptions opening one job, want 1", src.reads)
+	}
+	want := "Posting-" + m.(Model).selectedID()
+	if view := plain(m); !strings.Contains(view, "Description") || !strings.Contains(view, want) {
+		t.Errorf("detail does not show the description:\n%s", view)
+	}
+}
+
+// fullSource is a lazySource whose descriptions were cut on saving and
+// reads the whole text on demand.
+type fullSource struct {
+	lazySource
+	full map[string]string
+}
+
+func (f *fullSource) FullDescription(id string) (string, error) {
+	return f.full[id], nil
+}
+
+func TestModel_DetailLoadsFullDescription(t *testing.T) {
+	jobs := fixtureJobs()
+	full := map[string]string{}
+	for i := range jobs {
+		jobs[i].Description = "Cut posting " + jobs[i].ID
+		jobs[i].Truncated = true
+		full[jobs[i].ID] = "Cut posting " + jobs[i].ID + " and the tail about on-call."
+	}
+	src := &fullSource{lazySource: lazySource{fixtureSource: fixtureSource(jobs)}, full: full}
+	m := run(drive(NewModel(WithJobSource(src))), key("enter"))
+	if view := plain(m); !strings.Contains(view, "description truncated — press x to load full text") ||
+		strings.Contains(view, "on-call") {
+		t.Fatalf("cut description not flagged:\n%s", view)
+	}
+	m = run(m, key("x"))
+	view := plain(m)
+	if !strings.Contains(view, "the tail about on-call") || strings.Contains(view, "press x") {
+		t.Errorf("x did not load the full description:\n%s", view)
+	}
+	if len(m.(Model).jobs) != len(jobs) {
+		t.Errorf("x in Detail archived a job")
+	}
+}
+
+func TestModel_DetailRendersDescription(t *testing.T) {
+	long := strings.Repeat("x", 200)
+	for _, tc := range []struct {
+		name, desc string
+		want       []string
+	}{
+		{"html", "<h2>Role</h2><p>Ship &amp; run <b>Go</b>.</p><ul><li>Postgres</li><li>Kafka</li></ul><pre>" + long + "</pre>",
+			[]string{"Role", "Ship & run Go.", "• Postgres", "• Kafka"}},
+		{"markdown", "## Role\n\nShip **Go**, see [docs](https://x.example).\n\n- Postgres\n- Kafka\n\n| Team | Size |\n|---|---|\n| Core | " + long + " |",
+			[]string{"Role", "Ship Go, see docs.", "• Postgres", "• Kafka", "Team │ Size"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			jobs := fixtureJobs()
+			jobs[0].Description = tc.desc
+			m := drive(NewModel(WithJobSource(fixtureSource(jobs))))
+			m = run(m, tea.WindowSizeMsg{Width: 80, Height: 40})
+			m = run(m, tea.KeyMsg{Type: tea.KeyEnter})
+			view := plain(m)
+			for _, w := range tc.want {
+				if !strings.Contains(view, w) {
+					t.Errorf("missing %q:\n%s", w, view)
+				}
+			}
+			if strings.Contains(view, "<") || strings.Contains(view, "**") {
+				t.Errorf("markup left in:\n%s", view)
+			}
+			for _, line := range strings.Split(m.View(), "\n") {
+				if w := lipgloss.Width(line); w > 80 {
+					t.Errorf("line %d wide: %q", w, line)
+				}
+			}
+
+			m = run(m, key("r"))
+			if view := plain(m); !strings.Contains(view, "Description (raw)") || !strings.Contains(view, "Role") ||
+				!strings.Contains(view, strings.Fields(tc.desc)[0]) {
+				t.Errorf("raw description not shown:\n%s", view)
+			}
+			if m = run(m, key("r")); strings.Contains(plain(m), "(raw)") {
+				t.Errorf("r did not toggle back")
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@ package theme
 
 import (
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"sprayer/src/api/brand"
 )
 
 var (
@@ -18,9 +21,9 @@ var (
 	Dim         = lipgloss.Color("#3a3a3a")
 	Yellow      = lipgloss.Color("#f0c060")
 	Green       = lipgloss.Color("#50e3a4")
-	Cyan        = lipgloss.Color("#4cc9f0")
+	Cyan        = lipgloss.Color(brand.DefaultAccent)
 	Purple      = lipgloss.Color("#a78bfa")
-	Accent      = lipgloss.Color("#7b61ff")
+	Accent      = lipgloss.Color(brand.DefaultPrimary) // also the default document brand
 )
 
 var (
@@ -85,6 +88,23 @@ var (
 			Foreground(Yellow).
 			Bold(true)
 
+	JobDeadlineStyle = lipgloss.NewStyle().
+				Background(Background).
+				Foreground(Yellow)
+
+	JobStatusStyle = lipgloss.NewStyle().
+			Background(Background).
+			Foreground(Purple)
+
+	JobMarkStyle = lipgloss.NewStyle().
+			Background(Background).
+			Foreground(Green).
+			Bold(true)
+
+	JobOpensStyle = lipgloss.NewStyle().
+			Background(Background).
+			Foreground(Cyan)
+
 	JobCompanyStyle = lipgloss.NewStyle().
 			Background(Background).
 			Foreground(Subtle)
@@ -136,4 +156,12 @@ var (
 	ModalHintStyle = lipgloss.NewStyle().
 			Background(Surface2).
 			Foreground(Muted)
-)
\ No newline at end of file
+)
+
+// Apply switches to the named theme before the TUI starts: "mono" drops
+// colour altogether, anything else keeps the dark palette above.
+func Apply(name string) {
+	if name == "mono" {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
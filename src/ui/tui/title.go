@@ -0,0 +1,170 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Status is the part of the model's state the terminal title and the
+// status file report. Summary reads nothing else, so adding a fact to the
+// title means adding it here.
+type Status struct {
+	Loading       bool
+	Scraping      bool
+	ScrapeDone    int // sources finished
+	ScrapeTotal   int
+	NewJobs       int // found by the last scrape
+	UnreadReplies int
+	FollowUpsDue  int
+	Jobs          int
+	Offline       bool
+}
+
+// Summary renders s on one line, the most pressing fact first:
+// "sprayer: scraping 4/10", "sprayer: 3 replies unread · 212 new jobs".
+// With nothing to report it counts the jobs listed.
+func Summary(s Status) string {
+	var facts []string
+	switch {
+	case s.Loading:
+		facts = append(facts, "loading")
+	case s.Scraping && s.ScrapeTotal > 0:
+		facts = append(facts, fmt.Sprintf("scraping %d/%d", s.ScrapeDone, s.ScrapeTotal))
+	case s.Scraping:
+		facts = append(facts, "scraping")
+	}
+	if s.UnreadReplies > 0 {
+		facts = append(facts, plural(s.UnreadReplies, "reply", "replies")+" unread")
+	}
+	if s.FollowUpsDue > 0 {
+		facts = append(facts, plural(s.FollowUpsDue, "follow-up", "follow-ups")+" due")
+	}
+	if s.NewJobs > 0 {
+		facts = append(facts, plural(s.NewJobs, "new job", "new jobs"))
+	}
+	if len(facts) == 0 {
+		facts = append(facts, plural(s.Jobs, "job", "jobs"))
+	}
+	if s.Offline {
+		facts = append(facts, "offline")
+	}
+	return "sprayer: " + strings.Join(facts, " · ")
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
+
+// ScrapeProgressMsg reports a running scrape: Done of Total sources
+// finished.
+type ScrapeProgressMsg struct{ Done, Total int }
+
+// ScrapeDoneMsg ends a scrape that found New jobs not seen before.
+type ScrapeDoneMsg struct{ New int }
+
+// RepliesMsg reports how many recruiter replies are unread.
+type RepliesMsg struct{ Unread int }
+
+// titleInterval is the least time between two title changes, so a fast
+// scrape does not flood the terminal with escape sequences.
+const titleInterval = time.Second
+
+// titleTickMsg fires when a title held back by titleInterval may go out.
+type titleTickMsg struct{}
+
+// titleState tracks what was last shown and written.
+type titleState struct {
+	shown   string
+	at      time.Time
+	pending bool // a titleTickMsg is on its way
+	written string
+}
+
+// WithTerminalTitle keeps the terminal title set to the Summary of the
+// model's state. Callers pass false in plain mode and when stdout is not
+// a terminal.
+func WithTerminalTitle(enabled bool) Option {
+	return func(m *Model) { m.titles = enabled }
+}
+
+// WithStatusFile writes the Summary to path whenever it changes, for a
+// tmux status-right such as #(cat ~/.sprayer/status).
+func WithStatusFile(path string) Option {
+	return func(m *Model) { m.statusFile = path }
+}
+
+func (m Model) status() Status {
+	return Status{
+		Loading:       m.loading,
+		Scraping:      m.scraping,
+		ScrapeDone:    m.scrapeDone,
+		ScrapeTotal:   m.scrapeTotal,
+		NewJobs:       m.newJobs,
+		UnreadReplies: m.unreadReplies,
+		FollowUpsDue:  len(m.followUpsDue()),
+		Jobs:          len(m.jobs),
+		Offline:       m.offline,
+	}
+}
+
+// syncStatus brings the title and status file up to date with the model,
+// holding a title change back until titleInterval has passed since the
+// last one.
+func (m *Model) syncStatus() tea.Cmd {
+	if !m.titles && m.statusFile == "" {
+		return nil
+	}
+	summary := Summary(m.status())
+	var cmds []tea.Cmd
+	if m.statusFile != "" && summary != m.title.written {
+		m.title.written = summary
+		path := m.statusFile
+		cmds = append(cmds, func() tea.Msg {
+			// The status line is a convenience; a failed write shows
+			// as a stale line rather than an error in the TUI.
+			writeStatusFile(path, summary)
+			return nil
+		})
+	}
+	if m.titles && summary != m.title.shown {
+		now := m.clock()
+		if wait := titleInterval - now.Sub(m.title.at); wait > 0 {
+			if !m.title.pending {
+				m.title.pending = true
+				cmds = append(cmds, tea.Tick(wait, func(time.Time) tea.Msg { return titleTickMsg{} }))
+			}
+		} else {
+			m.title.shown, m.title.at = summary, now
+			cmds = append(cmds, tea.SetWindowTitle(summary))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// writeStatusFile replaces path's contents in one rename, so tmux never
+// reads a half-written line.
+func writeStatusFile(path, line string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(line+"\n"), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (m Model) clock() time.Time {
+	if m.now != nil {
+		return m.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/job"
+)
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		s    Status
+		want string
+	}{
+		{Status{Loading: true, Jobs: 5}, "sprayer: loading"},
+		{Status{Scraping: true, ScrapeDone: 4, ScrapeTotal: 10, Jobs: 50}, "sprayer: scraping 4/10"},
+		{Status{Scraping: true}, "sprayer: scraping"},
+		{Status{UnreadReplies: 3, Jobs: 40}, "sprayer: 3 replies unread"},
+		{Status{UnreadReplies: 1}, "sprayer: 1 reply unread"},
+		{Status{NewJobs: 212, Jobs: 400}, "sprayer: 212 new jobs"},
+		{Status{UnreadReplies: 1, FollowUpsDue: 3, Jobs: 40}, "sprayer: 1 reply unread · 3 follow-ups due"},
+		{Status{Scraping: true, ScrapeDone: 2, ScrapeTotal: 3, UnreadReplies: 2, NewJobs: 1},
+			"sprayer: scraping 2/3 · 2 replies unread · 1 new job"},
+		{Status{Jobs: 40}, "sprayer: 40 jobs"},
+		{Status{Jobs: 1}, "sprayer: 1 job"},
+		{Status{}, "sprayer: 0 jobs"},
+		{Status{Jobs: 7, Offline: true}, "sprayer: 7 jobs · offline"},
+	}
+	for _, tt := range tests {
+		if got := Summary(tt.s); got != tt.want {
+			t.Errorf("Summary(%+v) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+// titles runs cmd and returns the window titles and titleTickMsgs it
+// produces, without waiting for ticks.
+func titles(cmd tea.Cmd) (set []string, ticks int) {
+	if cmd == nil {
+		return nil, 0
+	}
+	ch := make(chan tea.Msg, 1)
+	go func() { ch <- cmd() }()
+	var msg tea.Msg
+	select {
+	case msg = <-ch:
+	case <-time.After(50 * time.Millisecond):
+		return nil, 1 // a tea.Tick waiting out titleInterval
+	}
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, c := range batch {
+			s, n := titles(c)
+			set, ticks = append(set, s...), ticks+n
+		}
+		return set, ticks
+	}
+	// tea.SetWindowTitle's message type is unexported; read its value.
+	if v := reflect.ValueOf(msg); v.Kind() == reflect.String && v.Type().Name() == "setWindowTitleMsg" {
+		set = append(set, v.String())
+	}
+	return set, ticks
+}
+
+func TestTitle_RateLimited(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	m := NewModel(WithTerminalTitle(true))
+	m.now = func() time.Time { return now }
+
+	step := func(msg tea.Msg) ([]string, int) {
+		next, cmd := m.Update(msg)
+		m = next.(Model)
+		return titles(cmd)
+	}
+
+	if set, _ := step(ScrapeProgressMsg{Done: 1, Total: 10}); !reflect.DeepEqual(set, []string{"sprayer: scraping 1/10"}) {
+		t.Fatalf("first title = %v", set)
+	}
+	now = now.Add(300 * time.Millisecond)
+	if set, ticks := step(ScrapeProgressMsg{Done: 2, Total: 10}); len(set) != 0 || ticks != 1 {
+		t.Fatalf("within a second: titles %v, ticks %d; want it held back", set, ticks)
+	}
+	now = now.Add(300 * time.Millisecond)
+	if set, ticks := step(ScrapeProgressMsg{Done: 3, Total: 10}); len(set) != 0 || ticks != 0 {
+		t.Fatalf("second change in the window: titles %v, ticks %d; want one pending tick", set, ticks)
+	}
+	now = now.Add(400 * time.Millisecond)
+	if set, _ := step(titleTickMsg{}); !reflect.DeepEqual(set, []string{"sprayer: scraping 3/10"}) {
+		t.Fatalf("after the tick = %v, want the latest state", set)
+	}
+	now = now.Add(2 * time.Second)
+	if set, _ := step(tea.WindowSizeMsg{Width: 100, Height: 30}); len(set) != 0 {
+		t.Errorf("unchanged summary retitled: %v", set)
+	}
+	if set, _ := step(ScrapeDoneMsg{New: 212}); !reflect.DeepEqual(set, []string{"sprayer: 212 new jobs"}) {
+		t.Errorf("after the scrape = %v", set)
+	}
+}
+
+func TestTitle_DisabledByDefault(t *testing.T) {
+	m := NewModel()
+	_, cmd := m.Update(ScrapeProgressMsg{Done: 1, Total: 2})
+	if set, ticks := titles(cmd); len(set) != 0 || ticks != 0 {
+		t.Errorf("plain model set titles %v", set)
+	}
+}
+
+func TestStatusFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tmux", "status")
+	m := NewModel(WithStatusFile(path))
+	next, cmd := m.Update(jobsLoadedMsg{jobs: []job.Job{{ID: "a"}, {ID: "b"}}})
+	if cmd == nil {
+		t.Fatal("no write scheduled")
+	}
+	cmd()
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "sprayer: 2 jobs\n" {
+		t.Fatalf("status file = %q, %v", data, err)
+	}
+	if _, cmd := next.Update(tea.WindowSizeMsg{Width: 90, Height: 20}); cmd != nil {
+		t.Error("unchanged summary rewrote the file")
+	}
+}
@@ -1,40 +1,319 @@
 package tui
 
 import (
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
+
+	"sprayer/src/api/job"
+	"sprayer/src/api/power"
+	"sprayer/src/ui/tui/joblist"
 )
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	m.listOffset = joblist.ScrollOffset(m.listOffset, m.selectedIndex, m.listRows(), len(m.jobs))
+	return m, tea.Batch(cmd, m.syncStatus())
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.viewState == Export {
+			return m.updateExport(msg)
+		}
+		if m.viewState == Profiles && (m.exportProfile != nil || m.profileComparer != nil) {
+			return m.updateProfiles(msg)
+		}
+		if m.viewState == Compare {
+			return m.updateCompare(msg)
+		}
+		if m.viewState == ApplyQueue && m.queue != nil {
+			return m.updateQueue(msg)
+		}
+		if m.viewState == Filter && m.presets != nil {
+			return m.updateFilter(msg)
+		}
+		if m.viewState == Outbox {
+			return m.updateOutbox(msg)
+		}
+		if m.viewState == Inbox {
+			return m.updateInbox(msg)
+		}
+		if m.viewState == Sources {
+			return m.updateSources(msg)
+		}
+		if m.viewState == Settings && m.settingsForm != nil {
+			return m.updateSettings(msg)
+		}
 		switch msg.String() {
 		case "j", "↓":
-			if len(m.jobs) > 0 {
+			if m.viewState == Reminders {
+				m.reminder = max(min(m.reminder+1, len(m.followUpsDue())-1), 0)
+			} else if len(m.jobs) > 0 {
 				m.selectedIndex = min(m.selectedIndex+1, len(m.jobs)-1)
 				m.viewState = JobList
 			}
 		case "k", "↑":
-			if len(m.jobs) > 0 {
+			if m.viewState == Reminders {
+				m.reminder = max(m.reminder-1, 0)
+			} else if len(m.jobs) > 0 {
 				m.selectedIndex = max(m.selectedIndex-1, 0)
 				m.viewState = JobList
 			}
+		case "pgdown", "pgup", "home", "end":
+			if m.viewState == JobList && len(m.jobs) > 0 {
+				m.selectedIndex = m.pageTarget(msg.String())
+			}
 		case "s":
-			m.viewState = Scraping
+			return m.startScraping()
 		case "f":
+			if m.presets != nil {
+				return m.openFilter()
+			}
 			m.viewState = Filter
 		case "p":
-			m.viewState = Profiles
+			return m.openProfiles()
 		case "m":
 			m.viewState = Emails
+		case "u":
+			m.viewState, m.reminder = Reminders, 0
+		case "o":
+			if m.viewState == Detail {
+				return m.detailAction("o")
+			}
+			return m.openOutbox()
+		case "y", "Y":
+			if m.viewState == Detail {
+				return m.detailAction(msg.String())
+			}
+		case "r":
+			if m.viewState == Detail {
+				m.rawDesc = !m.rawDesc
+			}
+		case "i":
+			return m.openInbox()
+		case ",":
+			if m.settings != nil {
+				return m.openSettings()
+			}
+		case " ":
+			if m.viewState == JobList {
+				m = m.toggleMark()
+			}
+		case "A":
+			if m.viewState == JobList {
+				return m.startQueue()
+			}
+		case "e":
+			if m.viewState == JobList {
+				m.viewState = Export
+			}
+		case "x", "X":
+			if m.viewState == Detail && msg.String() == "x" {
+				return m, m.loadFullDescription()
+			}
+			if m.viewState == JobList && m.archivedView == (msg.String() == "X") {
+				return m.setArchived(msg.String() == "x")
+			}
+		case "h":
+			if m.viewState == JobList || m.viewState == EmptyState {
+				return m.toggleHideApplied()
+			}
+		case "v":
+			if m.viewState == JobList || m.viewState == EmptyState {
+				m = m.toggleArchivedView()
+			}
+		case "1", "2", "3", "4":
+			if m.viewState == JobList {
+				return m.sortBy(job.SortKeys[msg.String()[0]-'1'])
+			}
+		case "t":
+			if m.viewState == JobList || m.viewState == Detail {
+				return m.cycleStatus()
+			}
+		case "enter":
+			if m.viewState == Reminders {
+				return m.composeFollowUp()
+			}
+			if m.viewState == JobList && len(m.jobs) > 0 {
+				m.viewState, m.history, m.app, m.desc = Detail, nil, nil, descriptionMsg{}
+				m.detailDone, m.detailErr = "", nil
+				return m, tea.Batch(m.loadHistory(), m.loadApplication(), m.loadDescription())
+			}
+		case "esc":
+			switch m.viewState {
+			case Detail, Reminders:
+				m.viewState = JobList
+			case JobList, EmptyState:
+				if m.archivedView {
+					m = m.toggleArchivedView()
+				}
+			case Compose:
+				m = m.closeDraft()
+			case Scraping:
+				var flush tea.Cmd
+				m, flush = m.stopScraping()
+				if len(m.jobs) > 0 {
+					m.viewState = JobList
+				} else {
+					m.viewState = EmptyState
+				}
+				return m, flush
+			}
 		case "a":
 		case "?":
 			m.viewState = Help
 		case "ctrl+c", "q":
-			return m, tea.Quit
+			return m.quit()
 		}
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.settingsForm != nil {
+			m.settingsForm.SetSize(m.width-4, m.height-2)
+		}
+	case spinMsg:
+		if m.loading {
+			m.spin++
+			return m, spinTick()
+		}
+	case offlineMsg:
+		m.offline = bool(msg)
+	case powerMsg:
+		m.power = power.Decision(msg)
+	case ScrapeProgressMsg:
+		m.scraping, m.scrapeDone, m.scrapeTotal = true, msg.Done, msg.Total
+	case ScrapeDoneMsg:
+		m.scraping, m.newJobs = false, msg.New
+	case scraperProgressMsg, scraperJobMsg, scraperErrMsg, scraperCompleteMsg:
+		return m.scrapeReceive(msg)
+	case ScrapedJobMsg:
+		return m, m.notifyJob(msg.Job)
+	case notifiedMsg:
+		if msg.err != nil {
+			m.notifier, m.notifyErr = nil, msg.err
+		}
+	case RepliesMsg:
+		m.unreadReplies = msg.Unread
+	case desktopMsg:
+		if msg.jobID == m.selectedID() {
+			m.detailDone, m.detailErr = msg.done, msg.err
+		}
+	case listSavedMsg:
+		m.listErr = msg.err
+	case presetsLoadedMsg:
+		v := &m.filterView
+		v.presets, v.busy, v.err = msg.presets, false, msg.err
+		v.selected = 0
+		for i, p := range msg.presets {
+			if m.preset != nil && strings.EqualFold(p.Name, m.preset.Name) {
+				v.selected = i + 1
+			}
+		}
+	case presetSavedMsg:
+		return m.presetSaved(msg)
+	case presetMatchedMsg:
+		return m.presetApplied(msg), nil
+	case statusSavedMsg:
+		m.saveErr = msg.err
+		if msg.err == nil && m.viewState == Detail && msg.jobID == m.selectedID() {
+			return m, m.loadHistory()
+		}
+	case historyMsg:
+		if msg.err == nil && msg.jobID == m.selectedID() {
+			m.history = msg.history
+		}
+	case descriptionMsg:
+		if msg.err == nil && msg.jobID == m.selectedID() {
+			m.desc = msg
+		}
+	case applicationMsg:
+		if msg.jobID == m.selectedID() {
+			m.app = msg.app
+		}
+	case queueDraftMsg, queueSentMsg, queueCVMsg, queuePreviewMsg:
+		return m.queueReceive(msg)
+	case outboxLoadedMsg:
+		m.outboxView.busy = false
+		if msg.err != nil {
+			m.outboxView.err = msg.err
+		} else {
+			m.outboxView.msgs = msg.msgs
+			m.outboxView.selected = min(m.outboxView.selected, max(len(msg.msgs)-1, 0))
+		}
+	case sourcesLoadedMsg:
+		m.sourcesView = sourcesView{stats: msg.stats, err: msg.err}
+	case outboxDoneMsg:
+		m.outboxView.err = msg.err
+		return m, m.loadOutbox()
+	case inboxPolledMsg:
+		return m.inboxPolled(msg)
+	case inboxTickMsg:
+		return m, m.pollInboxAllowed()
+	case inboxLoadedMsg:
+		m.inboxView.loading = false
+		if msg.err != nil {
+			m.inboxView.err = msg.err
+		} else {
+			m.inboxView.msgs = msg.msgs
+			m.inboxView.selected = min(m.inboxView.selected, max(len(msg.msgs)-1, 0))
+		}
+	case exportedMsg:
+		m.exported = msg
+	case profileExportedMsg:
+		m.profileExported = msg
+	case profilesLoadedMsg:
+		v := &m.profilesView
+		v.profiles, v.err, v.busy = msg.profiles, msg.err, false
+		v.selected = min(v.selected, max(len(msg.profiles)-1, 0))
+	case comparedMsg:
+		m.comparison.jobs, m.comparison.err, m.comparison.busy = msg.jobs, msg.err, false
+	case settingsLoadedMsg:
+		return m.settingsLoaded(msg)
+	case settingsSavedMsg:
+		m.settingsSaved = msg
+	case opensMsg:
+		m.opens = msg
+	case llmSpendMsg:
+		m.llmSpend = float64(msg)
+	case draftMsg, draftChunkMsg:
+		return m.updateDraft(msg)
+	case titleTickMsg:
+		m.title.pending = false
+	case jobsLoadedMsg:
+		m.loading = false
+		m.loadErr = msg.err
+		if msg.err == nil {
+			m.all, m.selectedIndex = msg.jobs, 0
+			m = m.refilter()
+			if len(m.jobs) > 0 {
+				m.viewState = JobList
+			}
+		}
+	default:
+		// The form's own messages, such as a cursor blink.
+		if m.viewState == Settings && m.settingsForm != nil {
+			return m.updateSettings(msg)
+		}
 	}
 	return m, nil
 }
+
+// listRows is how many jobs the job list shows at once.
+func (m Model) listRows() int { return joblist.Model{Height: m.height}.Rows() }
+
+// pageTarget is where key moves the selection in the job list: a page of
+// rows at a time, or to either end.
+func (m Model) pageTarget(key string) int {
+	last := len(m.jobs) - 1
+	switch key {
+	case "pgdown":
+		return min(m.selectedIndex+m.listRows(), last)
+	case "pgup":
+		return max(m.selectedIndex-m.listRows(), 0)
+	case "home":
+		return 0
+	}
+	return last
+}
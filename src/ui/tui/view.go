@@ -26,7 +26,17 @@ func (m Model) renderTopBar() string {
 
 	left := on(theme.Subtle).Render("Profile: ") + on(theme.Cyan).Render(m.profileName)
 	title := on(theme.Bright).Bold(true).Render("Sprayer")
-	right := on(theme.Subtle).Render("Jobs: ") + on(theme.Yellow).Render(strconv.Itoa(len(m.jobs)))
+	count := strconv.Itoa(len(m.jobs))
+	if m.loading {
+		count = "…"
+	} else if len(m.all) > len(m.jobs) {
+		count += "/" + strconv.Itoa(len(m.all))
+	}
+	label := "Jobs: "
+	if m.archivedView {
+		label = "Archived: "
+	}
+	right := on(theme.Subtle).Render(label) + on(theme.Yellow).Render(count) + on(theme.Subtle).Render(" · "+m.sortLabel())
 
 	titleW := lipgloss.Width(title)
 	sideW := (m.width - titleW) / 2
@@ -41,6 +51,9 @@ func (m Model) renderTopBar() string {
 // ── Content ───────────────────────────────────────────────────────────────────
 
 func (m Model) renderContent() string {
+	if m.loading {
+		return m.renderLoading()
+	}
 	// Root level router for content. For now, we only have Job List (including Empty state).
 	// Later, this handles help, filters, etc.
 	switch m.viewState {
@@ -50,35 +63,151 @@ func (m Model) renderContent() string {
 			SelectedIndex: m.selectedIndex,
 			Width:         m.width,
 			Height:        m.height,
+			Marked:        m.marked,
+			Opens:         m.opens,
+			Offset:        m.listOffset,
 		}
 		return jm.View()
-	default:
-		// Fallback for screens not yet implemented or managed at root.
-		return lipgloss.NewStyle().
-			Background(theme.Background).
-			Width(m.width).
-			Height(m.height - 2).
-			Align(lipgloss.Center, lipgloss.Center).
-			Render("Screen [" + strconv.Itoa(int(m.viewState)) + "]")
+	case Detail:
+		if len(m.jobs) > 0 {
+			return m.renderDetail()
+		}
+	case Reminders:
+		return m.renderReminders()
+	case Export:
+		return m.renderExport()
+	case Profiles:
+		if m.exportProfile != nil || m.profileComparer != nil {
+			return m.renderProfiles()
+		}
+	case Compare:
+		return m.renderCompare()
+	case Filter:
+		if m.presets != nil {
+			return m.renderFilter()
+		}
+	case Outbox:
+		return m.renderOutbox()
+	case Sources:
+		return m.renderSources()
+	case Inbox:
+		return m.renderInbox()
+	case Settings:
+		if m.settingsForm != nil {
+			return m.renderSettings()
+		}
+	case ApplyQueue:
+		if m.queue != nil {
+			return m.renderQueue()
+		}
+	case Compose:
+		if m.draft.job.ID != "" {
+			return m.renderCompose()
+		}
+	case Scraping:
+		if m.startScrape != nil {
+			return m.renderScraping()
+		}
 	}
+	// Fallback for screens not yet implemented or managed at root.
+	return lipgloss.NewStyle().
+		Background(theme.Background).
+		Width(m.width).
+		Height(m.height - 2).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render("Screen [" + strconv.Itoa(int(m.viewState)) + "]")
+}
+
+// spinFrames are the loading spinner's frames.
+var spinFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// renderLoading fills the content area while jobs load: a spinner and the
+// profile whose jobs are coming.
+func (m Model) renderLoading() string {
+	bg := lipgloss.NewStyle().Background(theme.Background)
+	line := theme.ProgressStyle.Render(spinFrames[m.spin%len(spinFrames)]) +
+		bg.Foreground(theme.Subtle).Render(" Loading jobs for ") +
+		bg.Foreground(theme.Cyan).Render(m.profileName) +
+		bg.Foreground(theme.Subtle).Render("…")
+	return lipgloss.Place(m.width, m.height-2, lipgloss.Center, lipgloss.Center, line,
+		lipgloss.WithWhitespaceBackground(theme.Background))
 }
 
 // ── Status bar — single row ───────────────────────────────────────────────────
 
 func (m Model) renderStatusBar() string {
-	keys := []string{"s", "f", "p", "m", "↑↓", "a", "?", "ctrl+c"}
-	labels := []string{"scrape", "filter", "profiles", "emails", "navigate", "apply", "help", "quit"}
+	keys := []string{"s", "f", "p", "m", "↑↓", "?", "q"}
+	labels := []string{"scrape", "filter", "profiles", "emails", "navigate", "help", "quit"}
+	var off map[string]bool
+	if m.viewState == Detail {
+		keys, labels, off = m.detailHints()
+	}
 
 	// Footer kbd: same theme.Surface background as the bar — no tint.
 	footerKbd := lipgloss.NewStyle().Background(theme.Surface).Foreground(theme.Cyan)
+	offKbd := lipgloss.NewStyle().Background(theme.Surface).Foreground(theme.Subtle).Strikethrough(true)
 	sp := lipgloss.NewStyle().Background(theme.Surface).Foreground(theme.Subtle).Render(" ")
 
 	line := ""
+	if m.viewState == JobList && len(m.jobs) > 0 {
+		line = theme.StatusLabelStyle.Render(joblist.Position(m.selectedIndex, len(m.jobs))) + theme.SepStyle.Render(" │ ")
+	}
+	if m.offline {
+		line += theme.WarningStyle.Render("offline") + theme.SepStyle.Render(" │ ")
+	}
+	if g := m.power.Glyph(); g != "" {
+		line += theme.WarningStyle.Render(g+" "+m.power.Reason()) + theme.SepStyle.Render(" │ ")
+	}
+	if n := len(m.followUpsDue()); n > 0 {
+		line += theme.WarningStyle.Render(plural(n, "follow-up", "follow-ups")+" due") + theme.SepStyle.Render(" │ ")
+	}
+	if m.loadErr != nil {
+		line += theme.ErrorStyle.Render("load failed: "+m.loadErr.Error()) + theme.SepStyle.Render(" │ ")
+	}
+	if n := m.queueNotice(); n != "" {
+		line += n + theme.SepStyle.Render(" │ ")
+	}
+	if n := m.exportNotice(); n != "" {
+		line += n + theme.SepStyle.Render(" │ ")
+	}
+	if n := m.profileExportNotice(); n != "" {
+		line += n + theme.SepStyle.Render(" │ ")
+	}
+	if n := m.settingsNotice(); n != "" {
+		line += n + theme.SepStyle.Render(" │ ")
+	}
+	if n := m.inboxNotice(); n != "" {
+		line += n + theme.SepStyle.Render(" │ ")
+	}
+	if n := m.detailNotice(); n != "" {
+		line += n + theme.SepStyle.Render(" │ ")
+	}
+	if n := m.listNotice(); n != "" {
+		line += n + theme.SepStyle.Render(" │ ")
+	}
+	if n := m.notifyNotice(); n != "" {
+		line += n + theme.SepStyle.Render(" │ ")
+	}
+	if m.llmSpend > 0 {
+		line += theme.StatusLabelStyle.Render("LLM $"+strconv.FormatFloat(m.llmSpend, 'f', 2, 64)+" this month") + theme.SepStyle.Render(" │ ")
+	}
+	if m.saveErr != nil {
+		line += theme.ErrorStyle.Render("status not saved: "+m.saveErr.Error()) + theme.SepStyle.Render(" │ ")
+	}
+	// Hints that would wrap the bar onto a second row are dropped.
+	avail := m.width - 4
 	for i, key := range keys {
+		item := footerKbd.Render(key) + sp + theme.StatusLabelStyle.Render(labels[i])
+		if off[key] {
+			item = offKbd.Render(key) + sp + offKbd.Render(labels[i])
+		}
 		if i > 0 {
-			line += theme.SepStyle.Render(" │ ")
+			item = theme.SepStyle.Render(" │ ") + item
+		}
+		if lipgloss.Width(line+item) > avail {
+			break
 		}
-		line += footerKbd.Render(key) + sp + theme.StatusLabelStyle.Render(labels[i])
+		line += item
 	}
 
 	return lipgloss.NewStyle().Background(theme.Surface).Width(m.width).PaddingLeft(2).PaddingRight(2).Render(line)
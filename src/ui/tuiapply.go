@@ -0,0 +1,236 @@
+package ui
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/apply"
+	"sprayer/src/api/desktop"
+	"sprayer/src/api/export"
+	"sprayer/src/api/job"
+	"sprayer/src/api/notify"
+	"sprayer/src/api/outbox"
+	"sprayer/src/api/profile"
+	"sprayer/src/api/scraper"
+	"sprayer/src/api/settings"
+	"sprayer/src/ui/tui"
+)
+
+// TUIOptions connects the TUI to the CLI's stores: the jobs listed,
+// their statuses, what was sent to each and how often it was opened, the
+// follow-ups and bulk applications written and sent as profileID, with
+// CVs tailored from its CV, the outbox of those that failed to send and
+// the inbox replies arrive in.
+// With dryRun bulk apply only saves drafts. An empty profileID is the
+// default profile; exports and the settings form follow the settings.
+// Cancelling ctx abandons the scrapes, LLM requests and queries the TUI
+// has running; cancel it once the program quits.
+func (c *CLI) TUIOptions(ctx context.Context, profileID string, dryRun bool) []tui.Option {
+	p := c.batchProfile(cmp.Or(profileID, c.defaultProfile()))
+	listSort, _ := job.ParseSort(p.ListSort) // a sort it cannot read lists by score
+	// Unset, or a format it cannot read, exports csv.
+	exportFormat, _ := export.ParseJobFormat(os.Getenv(settings.EnvExportFormat))
+	// Trap rules are read now, before the TUI takes the terminal.
+	enrich := job.Enrich(c.trapMatcher())
+	scrapeOpts := []scraper.IncrementalOption{scraper.WithSeen(c.store, false), scraper.WithEnrich(enrich)}
+	var sourceStats tui.SourceStats
+	if c.statsStore != nil {
+		scrapeOpts = append(scrapeOpts, scraper.WithStats(c.statsStore))
+		sourceStats = c.statsStore
+	}
+	compose := func(j job.Job, prompt string) (string, string, error) {
+		j = c.withDescription(j)
+		return apply.GenerateEmailContext(ctx, j, p, c.llmClient, prompt, c.answered(j, p)...)
+	}
+	return []tui.Option{
+		tui.WithContext(ctx),
+		tui.WithJobSource(c.store),
+		tui.WithStatusStore(c.store),
+		tui.WithOpenCounts(c.appStore.OpenCounts),
+		tui.WithLLMSpend(c.monthToDateLLMCost),
+		tui.WithApplications(c.appStore.ForJob),
+		tui.WithComposer(compose),
+		tui.WithLLMStatus(func() error { return c.llmClient.Check(ctx) }),
+		tui.WithStreamComposer(func(ctx context.Context, j job.Job, prompt string, chunks chan<- string) (string, string, error) {
+			j = c.withDescription(j)
+			return apply.StreamEmail(ctx, j, p, c.llmClient, prompt, chunks, c.answered(j, p)...)
+		}),
+		tui.WithApplier(tuiApplier{c: c, p: p}),
+		tui.WithCVTailor(func(j job.Job, template string, regenerate bool) (string, error) {
+			p := p
+			p.CVTemplate = template
+			j = c.withDescription(j)
+			_, err := c.tailorCV(ctx, &j, p, regenerate)
+			return j.CVPath, err
+		}),
+		tui.WithCVTemplates(apply.CVTemplates(), cmp.Or(p.CVTemplate, apply.DefaultCVTemplate)),
+		tui.WithDryRun(dryRun),
+		tui.WithOutbox(tuiOutbox{c}),
+		tui.WithSourceStats(sourceStats),
+		tui.WithProfileComparer(tuiComparer{c: c, ctx: ctx}),
+		tui.WithInbox(tuiInbox{c: c, p: c.inboxPoller(), ctx: ctx}),
+		tui.WithNotifier(notify.Desktop(), p.NotifyMinScore),
+		tui.WithScraper(func() tui.IncrementalScraper {
+			return scraper.NewIncrementalScraper(ctx, p, scrapeOpts...)
+		}, c.saveScraped(p.ID)),
+		tui.WithDesktop(desktop.OpenURL, func(text string) error { return desktop.Copy(os.Stdout, text) }),
+		tui.WithArchiver(func(jobID string, archived bool) error {
+			return c.store.SetArchivedContext(ctx, jobID, p.ID, archived)
+		}),
+		tui.WithHideApplied(p.HideApplied, func(hide bool) error {
+			stored := c.batchProfile(p.ID)
+			stored.HideApplied = hide
+			return c.profileStore.Save(stored)
+		}),
+		tui.WithFilterPresets(c.profileStore, func(f profile.FilterPreset) ([]string, error) {
+			layered := f.Apply(c.batchProfile(p.ID), time.Now())
+			jobs, err := layered.StoredMatches(c.store, job.IncludeArchived())
+			ids := make([]string, len(jobs))
+			for i, j := range jobs {
+				ids[i] = j.ID
+			}
+			return ids, err
+		}),
+		tui.WithSort(listSort, func(s job.Sort) error {
+			stored := c.batchProfile(p.ID)
+			stored.ListSort = s.String()
+			return c.profileStore.Save(stored)
+		}),
+		tui.WithSettings(c.settingsStore, c.settingsEnv),
+		tui.WithExportDir(os.Getenv(settings.EnvExportDir)),
+		tui.WithExportFormat(exportFormat),
+		tui.WithProfileExport(func(mode profile.ExportMode) (string, error) {
+			dir := cmp.Or(os.Getenv(settings.EnvExportDir), filepath.Join(job.DataDir(), "exports"))
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return "", err
+			}
+			path := filepath.Join(dir, "profile-"+p.ID+"-"+time.Now().Format("20060102-150405")+".json")
+			return path, profile.NewProfileImporter().ExportProfile(c.batchProfile(p.ID), path, profile.FormatJSON, mode)
+		}),
+	}
+}
+
+// saveScraped stores the jobs a TUI scrape streams as handleScrape stores
+// a scrape's, without printing: the TUI shows what failed. Trap rules are
+// read once, before the TUI takes the terminal.
+func (c *CLI) saveScraped(profileID string) func([]job.Job) ([]job.Job, error) {
+	pipeline := c.scrapePipeline()
+	return func(jobs []job.Job) ([]job.Job, error) {
+		jobs, _, err := c.store.MatchStored(pipeline(jobs))
+		if err != nil {
+			return nil, err
+		}
+		if c.ruleStore != nil {
+			if ruled, err := c.ruleStore.Ingest(c.store, profileID, jobs); err == nil {
+				jobs = ruled
+			}
+		}
+		if err := c.store.Save(jobs); err != nil {
+			return jobs, err
+		}
+		return jobs, c.store.MarkSaved(jobs)
+	}
+}
+
+// withDescription fills in the whole description of a job listed without
+// one or with one cut on saving, so the LLM and the application's posting
+// snapshot see all of it.
+func (c *CLI) withDescription(j job.Job) job.Job {
+	if j.Description == "" || j.Truncated {
+		if full, err := c.store.FullDescription(j.ID); err == nil {
+			j.Description = full
+		}
+	}
+	return j
+}
+
+// answered returns the job's answered questions, without detecting new
+// ones or nagging about the rest as prepareQuestions does.
+func (c *CLI) answered(j job.Job, p profile.Profile) []application.Question {
+	qs, _ := c.appStore.Questions(j.ID, p.ID)
+	var answers []application.Question
+	for _, q := range qs {
+		if q.Answered() {
+			answers = append(answers, q)
+		}
+	}
+	return answers
+}
+
+// Close releases the database.
+func (c *CLI) Close() error { return c.store.Close() }
+
+// tuiApplier sends bulk applications the way apply --send does, without
+// printing: the TUI shows what happened to each.
+type tuiApplier struct {
+	c *CLI
+	p profile.Profile
+}
+
+func (a tuiApplier) Draft(j job.Job, subject, body string) (string, error) {
+	return apply.Draft(a.c.withDescription(j), a.p, subject, body)
+}
+
+func (a tuiApplier) Send(j job.Job, subject, body string) error {
+	if j.Email == "" {
+		return errors.New("no email address")
+	}
+	if _, err := apply.Draft(j, a.p, subject, body); err != nil {
+		return err
+	}
+	cv := apply.ResolveJobCV(a.p, j)
+	if cv.Blocking() {
+		return errors.New(cv.Label(time.Now()))
+	}
+	return a.c.sendNow(&outbox.Message{JobID: j.ID, ProfileID: a.p.ID, To: j.Email, Cc: j.Cc, Subject: subject, Body: body})
+}
+
+func (a tuiApplier) Preview(j job.Job, subject, body string) (string, error) {
+	if j.Email == "" {
+		return "", errors.New("no email address")
+	}
+	return apply.PreviewTracked(j.Email, subject, body, apply.ResolveJobCV(a.p, j).Path, "", j.Cc...)
+}
+
+// tuiComparer lists and compares the stored profiles for the TUI's
+// Profiles view.
+type tuiComparer struct {
+	c   *CLI
+	ctx context.Context
+}
+
+func (t tuiComparer) All() ([]profile.Profile, error) { return t.c.profileStore.AllContext(t.ctx) }
+
+func (t tuiComparer) Compare(ids []string, by int) ([]profile.Compared, error) {
+	profiles := make([]profile.Profile, len(ids))
+	for i, id := range ids {
+		p, err := t.c.profileStore.ByIDContext(t.ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: %w", id, err)
+		}
+		profiles[i] = *p
+	}
+	return profile.CompareStored(t.ctx, t.c.store, profiles, by)
+}
+
+// tuiOutbox is the outbox as the TUI's Outbox view works it.
+type tuiOutbox struct {
+	c *CLI
+}
+
+func (o tuiOutbox) Unsent() ([]outbox.Message, error) { return o.c.outbox.Unsent() }
+
+func (o tuiOutbox) Retry(id int64) error {
+	if err := o.c.outbox.Requeue(id); err != nil {
+		return err
+	}
+	return o.c.outbox.Send(id, time.Now(), o.c.sendQueued)
+}
+
+func (o tuiOutbox) Delete(id int64) error { return o.c.outbox.Delete(id) }
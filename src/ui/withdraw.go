@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/apply"
+	"sprayer/src/api/offline"
+)
+
+// withdraw pulls out of every application sel covers: it lists them, asks
+// once, then drafts (or with send, sends) a withdrawal email to each and
+// marks it withdrawn. An application with no known address is still
+// marked, so it drops out of follow-ups.
+func (c *CLI) withdraw(in io.Reader, sel application.Selection, reason string, send, yes bool) error {
+	apps, err := c.appStore.All()
+	if err != nil {
+		return fmt.Errorf("load applications: %w", err)
+	}
+	picked := sel.Apply(apps)
+	if len(picked) == 0 {
+		fmt.Println("No applications to withdraw.")
+		return nil
+	}
+
+	to := make([]string, len(picked))
+	for i, a := range picked {
+		to[i] = c.withdrawalAddress(a)
+		dest := to[i]
+		if dest == "" {
+			dest = "no address, status only"
+		}
+		fmt.Printf("  %4d  %-10s %s @ %s → %s\n", a.ID, a.Status, a.Title, a.Company, dest)
+	}
+	if !yes {
+		fmt.Printf("Withdraw %d application(s)? [y/N] ", len(picked))
+		answer, _ := bufio.NewReader(in).ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+		default:
+			fmt.Println("Nothing withdrawn. Pass --yes to skip this question.")
+			return nil
+		}
+	}
+
+	now := time.Now()
+	for i, a := range picked {
+		if to[i] != "" {
+			if err := c.sendWithdrawal(a, to[i], reason, send, now); err != nil {
+				fmt.Printf("  %d: %v; left as %s\n", a.ID, err, a.Status)
+				continue
+			}
+		}
+		if err := c.appStore.Withdraw(a.ID, reason, now); err != nil {
+			fmt.Printf("  %d: %v\n", a.ID, err)
+			continue
+		}
+		fmt.Printf("  %d: withdrawn\n", a.ID)
+	}
+	return nil
+}
+
+// withdrawalAddress is who last wrote about the application, or failing
+// that the address the posting gave.
+func (c *CLI) withdrawalAddress(a application.Application) string {
+	if msgs, err := c.appStore.Thread(a.ID); err == nil {
+		for i := len(msgs) - 1; i >= 0; i-- {
+			if msgs[i].Direction == application.Incoming && msgs[i].From != "" {
+				return msgs[i].From
+			}
+		}
+	}
+	if j, err := c.store.ByID(a.JobID); err == nil {
+		return j.Email
+	}
+	return ""
+}
+
+// sendWithdrawal writes the withdrawal email for a to the drafts folder,
+// or sends it when send is set and the machine is online.
+func (c *CLI) sendWithdrawal(a application.Application, to, reason string, send bool, now time.Time) error {
+	p := c.batchProfile(a.ProfileID)
+	subject, body, err := apply.RenderWithdrawal(a, p, reason)
+	if err != nil {
+		return err
+	}
+	from := apply.SMTPFrom()
+	if from == "" {
+		from = p.ContactEmail
+	}
+	m := apply.Message{
+		MessageID: apply.NewMessageID(from),
+		From:      from, To: to, Subject: subject, Date: now, Body: body,
+	}
+
+	if send {
+		err := apply.SendMessage(m)
+		switch {
+		case errors.Is(err, offline.ErrOffline):
+			fmt.Printf("  %d: %v; saving a draft instead.\n", a.ID, err)
+		case err != nil:
+			return fmt.Errorf("send: %w", err)
+		default:
+			return c.appStore.AppendMessage(outgoing(a.ID, m))
+		}
+	}
+	if _, err := apply.DraftMessage(m); err != nil {
+		return err
+	}
+	return c.appStore.AppendMessage(outgoing(a.ID, m))
+}
+
+func outgoing(appID int64, m apply.Message) *application.ThreadMessage {
+	return &application.ThreadMessage{
+		ApplicationID: appID, Direction: application.Outgoing, MessageID: m.MessageID,
+		From: m.From, To: m.To, Subject: m.Subject, Date: m.Date, Body: m.Body,
+	}
+}
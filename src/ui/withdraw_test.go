@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"sprayer/src/api/application"
+	"sprayer/src/api/job"
+	"sprayer/src/api/profile"
+)
+
+func TestWithdraw_DraftsAndMarks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SPRAYER_SMTP_FROM", "jane@example.com")
+	c := newTestCLI(t)
+	ps, err := profile.NewStore(c.store.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.profileStore = ps
+
+	if err := c.store.Save([]job.Job{
+		{ID: "j1", Title: "Go Dev", Company: "Globex", Email: "jobs@globex.com"},
+		{ID: "j2", Title: "SRE", Company: "Acme", Email: "jobs@acme.com"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	applied := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	globex := &application.Application{JobID: "j1", Company: "Globex", Title: "Go Dev", Status: application.StatusInterview, AppliedAt: applied}
+	acme := &application.Application{JobID: "j2", Company: "Acme", Title: "SRE", Status: application.StatusOffer, AppliedAt: applied}
+	for _, a := range []*application.Application{globex, acme} {
+		if err := c.appStore.Add(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sel := application.Selection{Except: []string{"Acme"}}
+	if err := c.withdraw(strings.NewReader("n\n"), sel, "", false, false); err != nil {
+		t.Fatal(err)
+	}
+	if a, _ := c.appStore.ByID(globex.ID); a.Status != application.StatusInterview {
+		t.Fatalf("declined confirmation still withdrew: %s", a.Status)
+	}
+
+	if err := c.withdraw(nil, sel, "I have accepted another offer", false, true); err != nil {
+		t.Fatal(err)
+	}
+	if a, _ := c.appStore.ByID(globex.ID); a.Status != application.StatusWithdrawn || a.WithdrawReason != "I have accepted another offer" {
+		t.Errorf("globex = %s (%q), want withdrawn", a.Status, a.WithdrawReason)
+	}
+	if a, _ := c.appStore.ByID(acme.ID); a.Status != application.StatusOffer {
+		t.Errorf("excepted company changed to %s", a.Status)
+	}
+
+	drafts, _ := os.ReadDir(filepath.Join(home, "Maildir", "drafts", "new"))
+	if len(drafts) != 1 {
+		t.Fatalf("%d drafts, want 1", len(drafts))
+	}
+	data, _ := os.ReadFile(filepath.Join(home, "Maildir", "drafts", "new", drafts[0].Name()))
+	if !strings.Contains(string(data), "To: jobs@globex.com") || !strings.Contains(string(data), "applied for on 2024-03-04") {
+		t.Errorf("draft:\n%s", data)
+	}
+	if msgs, _ := c.appStore.Thread(globex.ID); len(msgs) != 1 || msgs[0].Direction != application.Outgoing {
+		t.Errorf("thread = %+v, want the withdrawal", msgs)
+	}
+}